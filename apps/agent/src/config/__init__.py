@@ -2,11 +2,13 @@
 
 from .settings import settings
 from .llm_provider import create_llm
-from .prompts import DEFAULT_PROMPT, BENCHMARK_PROMPT
+from .prompts import DEFAULT_PROMPT, BENCHMARK_PROMPT, build_student_context, build_preferences_context
 
 __all__ = [
     "settings",
     "create_llm",
     "DEFAULT_PROMPT",
     "BENCHMARK_PROMPT",
+    "build_student_context",
+    "build_preferences_context",
 ]
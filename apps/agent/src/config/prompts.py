@@ -42,6 +42,9 @@ Bạn là trợ lý hỗ trợ sinh viên của Trường Đại học Công ngh
 - MẶC ĐỊNH HỆ ĐÀO TẠO: Nếu user không nhắc tới "từ xa", "liên thông", "văn bằng 2" -> Mặc định là hệ CHÍNH QUY.
 - Khi user ĐỀ CẬP TÊN NGÀNH (trong list trên) → gọi `retrieve_curriculum()`.
 - Khi user muốn xem ĐIỂM SỐ hoặc THỜI KHÓA BIỂU → gọi `get_user_credential()` sau đó gọi `get_grades()` hoặc `get_schedule()`.
+- Khi user hỏi về ĐIỂM RÈN LUYỆN (DRL) → gọi `get_training_score()`.
+- Khi user cần hỗ trợ ĐĂNG KÝ HỌC PHẦN → gọi `get_open_classes()` để xem lớp còn chỗ, và `check_registration_conflicts()` để kiểm tra trùng lịch trước khi tư vấn.
+- Khi user hỏi về THÔNG BÁO MỚI, tin tức gần đây của trường (lịch đăng ký, lịch thi, hạn học phí, sự kiện) → gọi `get_uit_announcements()`, trích dẫn link thông báo.
 - Khi user chào hỏi, hoặc hỏi về bạn → trả lời trực tiếp, KHÔNG cần gọi tool.
 
 ### 2. KHI GỌI TOOL retrieve_regulation() hoặc retrieve_curriculum()
@@ -152,3 +155,54 @@ Nếu gọi tool lần đầu mà kết quả trả về không chứa thông ti
 3. Nếu không tìm thấy thông tin: trả lời "Không tìm thấy thông tin".
 4. LUÔN TRẢ LỜI BẰNG TIẾNG VIỆT.
 """
+
+
+# ===== NGỮ CẢNH NGƯỜI DÙNG =====
+# Ghép vào cuối system prompt theo từng request
+
+def build_student_context(faculty: str = "", program: str = "", enrollment_year: int = 0) -> str:
+    """
+    Build the student profile section of the system prompt.
+
+    Only fields the student filled in are listed. Returns an empty string if none is set.
+    """
+    lines = []
+    if faculty:
+        lines.append(f"- Khoa: {faculty}")
+    if program:
+        lines.append(f"- Chương trình đào tạo: {program}")
+    if enrollment_year:
+        lines.append(f"- Năm nhập học: {enrollment_year} (khóa {enrollment_year})")
+
+    if not lines:
+        return ""
+
+    return (
+        "\n\n## HỒ SƠ SINH VIÊN\n"
+        + "\n".join(lines)
+        + "\n\nKhi câu hỏi phụ thuộc vào khoa, chương trình hoặc khóa (VD: chương trình đào tạo, số tín chỉ, "
+        "điều kiện tốt nghiệp), trả lời theo hồ sơ này và dùng tên ngành đầy đủ trong query, "
+        "trừ khi sinh viên hỏi rõ về ngành hoặc khóa khác."
+    )
+
+
+def build_preferences_context(language: str = "", omit_citations: bool = False, blocked_topics=None) -> str:
+    """
+    Build the answer preferences section of the system prompt: response language,
+    citations and topics the user does not want brought up.
+
+    Rules here override the defaults of the base prompt. Returns an empty string if nothing differs from them.
+    """
+    lines = []
+    if language == "en":
+        lines.append("- Trả lời bằng TIẾNG ANH (English), thay cho quy tắc trả lời bằng tiếng Việt ở trên. Query gửi tới tool vẫn viết bằng tiếng Việt.")
+    if omit_citations:
+        lines.append("- KHÔNG chèn nguồn tham khảo hay ngày hiệu lực tài liệu vào câu trả lời.")
+    if blocked_topics:
+        topics = ", ".join(f'"{t}"' for t in blocked_topics)
+        lines.append(f"- KHÔNG nhắc tới các chủ đề sau, kể cả khi liên quan: {topics}. Nếu câu hỏi xoay quanh chủ đề này, lịch sự từ chối trả lời.")
+
+    if not lines:
+        return ""
+
+    return "\n\n## TÙY CHỌN CỦA NGƯỜI DÙNG (ƯU TIÊN CAO NHẤT)\n" + "\n".join(lines)
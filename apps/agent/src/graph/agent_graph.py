@@ -120,7 +120,7 @@ def _create_tool_node_with_logging_and_timeout(tools, timeout=120):
     return tool_node_with_timeout
 
 
-def create_agent_graph(llm, tools, checkpointer=None, tool_timeout=120):
+def create_agent_graph(llm, tools, checkpointer=None, tool_timeout=120, llm_factory=None):
     """
     Create LangGraph agent with ReAct-style workflow.
 
@@ -133,6 +133,7 @@ def create_agent_graph(llm, tools, checkpointer=None, tool_timeout=120):
         tools: List of tools (MCP tools + native tools)
         checkpointer: State persistence layer (PostgresSaver or RedisSaver)
         tool_timeout: Timeout for tool execution in seconds (default: 30)
+        llm_factory: Creates the LLM for a model picked per request; None always uses llm
 
     Returns:
         Compiled graph ready for invocation
@@ -140,8 +141,18 @@ def create_agent_graph(llm, tools, checkpointer=None, tool_timeout=120):
     # Bind tools to LLM
     llm_with_tools = llm.bind_tools(tools)
 
+    # LLMs for models picked per request, created on first use
+    llms_by_model = {}
+
+    def llm_for_model(model):
+        if not model or llm_factory is None:
+            return llm_with_tools
+        if model not in llms_by_model:
+            llms_by_model[model] = llm_factory(model).bind_tools(tools)
+        return llms_by_model[model]
+
     # Create partial function with LLM
-    agent_with_llm = partial(agent_node, llm_with_tools=llm_with_tools)
+    agent_with_llm = partial(agent_node, llm_for_model=llm_for_model)
 
     # Define graph
     workflow = StateGraph(AgentState)
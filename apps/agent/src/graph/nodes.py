@@ -6,7 +6,7 @@ from typing import Literal
 from langchain_core.messages import AIMessage, SystemMessage, HumanMessage
 
 from .state import AgentState
-from ..config import BENCHMARK_PROMPT
+from ..config import BENCHMARK_PROMPT, build_student_context, build_preferences_context
 from ..query_refinement.refiner import QueryRefiner
 from ..utils.logger import logger
 
@@ -25,7 +25,7 @@ def get_query_refiner():
     return _query_refiner
 
 
-def agent_node(state: AgentState, llm_with_tools):
+def agent_node(state: AgentState, llm_for_model):
     """
     Agent reasoning node - LLM decides whether to use tools or respond.
 
@@ -36,7 +36,7 @@ def agent_node(state: AgentState, llm_with_tools):
 
     Args:
         state: Current agent state
-        llm_with_tools: LLM instance bound with tools
+        llm_for_model: Returns the LLM bound with tools for a model name, the default LLM for an empty one
 
     Returns:
         Updated state with LLM response
@@ -63,11 +63,23 @@ def agent_node(state: AgentState, llm_with_tools):
 
     if not has_system_prompt:
         # Inject user_id into system prompt
-        system_prompt_with_user_id = SYSTEM_PROMPT + f"\n\n## THÔNG TIN NGƯỜI DÙNG HIỆN TẠI\nUser ID: {user_id}\n\nKhi gọi tool `get_user_credential`, LUÔN LUÔN sử dụng user_id này."
+        system_prompt_with_user_id = SYSTEM_PROMPT + f"\n\n## THÔNG TIN NGƯỜI DÙNG HIỆN TẠI\nUser ID: {user_id}\n\nKhi gọi tool `get_user_credential`, `get_training_score`, `get_open_classes` hoặc `check_registration_conflicts`, LUÔN LUÔN sử dụng user_id này."
+        # Inject student profile (faculty, program, cohort) for personalized answers
+        system_prompt_with_user_id += build_student_context(
+            faculty=state.get("faculty", ""),
+            program=state.get("program", ""),
+            enrollment_year=state.get("enrollment_year", 0),
+        )
+        # Inject answer preferences last so they override the base prompt
+        system_prompt_with_user_id += build_preferences_context(
+            language=state.get("language", ""),
+            omit_citations=state.get("omit_citations", False),
+            blocked_topics=state.get("blocked_topics", []),
+        )
         messages = [SystemMessage(content=system_prompt_with_user_id)] + messages
 
-    # Step 3: Invoke LLM with tools
-    response = llm_with_tools.invoke(messages)
+    # Step 3: Invoke LLM with tools (the model the user picked, if any)
+    response = llm_for_model(state.get("model", "")).invoke(messages)
 
     # Log final answer if no tool calls
     if not hasattr(response, "tool_calls") or not response.tool_calls:
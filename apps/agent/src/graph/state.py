@@ -14,6 +14,11 @@ class AgentState(TypedDict):
     Fields:
         messages: Chat history with automatic message deduplication/merging
         user_id: User ID for credential lookup (from Redis)
+        faculty, program, enrollment_year: Student profile used to personalize answers, empty if not filled in
+        language: Response language ("vi" | "en"), resolved from the session override or user settings
+        model: Model picked by the user, empty for the agent's default
+        omit_citations: User turned citations off
+        blocked_topics: Topics the user never wants brought up
     """
     # Chat messages with automatic state updates
     # add_messages reducer handles appending new messages
@@ -21,3 +26,12 @@ class AgentState(TypedDict):
 
     # User context
     user_id: str
+    faculty: str
+    program: str
+    enrollment_year: int
+
+    # Answer preferences
+    language: str
+    model: str
+    omit_citations: bool
+    blocked_topics: list[str]
@@ -17,6 +17,7 @@ from src.config.llm_provider import create_llm
 from src.config.settings import settings
 from src.tools.mcp_loader import load_mcp_tools
 from src.tools.credential_tool import get_user_credential
+from src.tools.uit_data_tool import get_training_score, get_open_classes, check_registration_conflicts, get_uit_announcements
 from src.graph.agent_graph import create_agent_graph
 from src.graph.checkpointer import create_checkpointer
 from src.grpc.pb import agent_pb2, agent_pb2_grpc
@@ -47,10 +48,23 @@ class AgentServicer(agent_pb2_grpc.AgentServicer):
         Returns:
             ChatResponse with agent's reply
         """
+        # Forwarded by the API gateway to correlate agent logs with its own
+        request_id = dict(context.invocation_metadata()).get("x-request-id", "-")
+
         logger.info(f"\n{'='*70}")
         logger.info(f"[AGENT SERVER] Received request:")
+        logger.info(f"  - Request ID: {request_id}")
         logger.info(f"  - User ID: {request.user_id}")
         logger.info(f"  - Thread ID: {request.thread_id}")
+        logger.info(f"  - Language: {request.language or 'vi'}")
+        if request.faculty or request.program or request.enrollment_year:
+            logger.info(f"  - Student: {request.faculty or '-'} | {request.program or '-'} | {request.enrollment_year or '-'}")
+        if request.model:
+            logger.info(f"  - Model: {request.model}")
+        if request.omit_citations:
+            logger.info("  - Citations: off")
+        if request.blocked_topics:
+            logger.info(f"  - Blocked topics: {len(request.blocked_topics)}")
         logger.info(f"  - Message: {request.message[:100]}...")
         logger.info(f"{'='*70}\n")
 
@@ -59,41 +73,49 @@ class AgentServicer(agent_pb2_grpc.AgentServicer):
             loop = asyncio.new_event_loop()
             asyncio.set_event_loop(loop)
             response = loop.run_until_complete(
-                self._ainvoke_agent(request.message, request.user_id, request.thread_id)
+                self._ainvoke_agent(request)
             )
             loop.close()
 
             return response
 
         except Exception as e:
-            logger.exception(f"[AGENT SERVER] Error during chat invocation")
+            logger.exception(f"[AGENT SERVER] Error during chat invocation (request {request_id})")
             context.set_code(grpc.StatusCode.INTERNAL)
             context.set_details(f"Agent error: {str(e)}")
             return agent_pb2.ChatResponse(content=f"Xin lỗi, đã xảy ra lỗi: {str(e)}")
 
-    async def _ainvoke_agent(self, message: str, user_id: str, thread_id: str):
+    async def _ainvoke_agent(self, request):
         """
         Invoke agent graph asynchronously.
 
         Args:
-            message: User's message
-            user_id: User ID (for credential lookup)
-            thread_id: Thread ID (for state persistence)
+            request: ChatRequest with the user's message, user_id (for credential lookup),
+                thread_id (for state persistence), student profile (for personalization)
+                and answer preferences (language, model, citations, blocked topics)
 
         Returns:
             ChatResponse protobuf message
         """
         # Build config with thread_id for checkpointer
         config = {
-            "configurable": {"thread_id": thread_id},
+            "configurable": {"thread_id": request.thread_id},
             "recursion_limit": 50  # Increased from default 25 to handle complex tool chains
         }
 
         # Invoke graph (will automatically load state from checkpointer if exists)
         result = await self.graph.ainvoke(
             {
-                "messages": [("user", message)],
-                "user_id": user_id
+                "messages": [("user", request.message)],
+                "user_id": request.user_id,
+                # Student profile is per request: an updated profile applies to the next message
+                "faculty": request.faculty,
+                "program": request.program,
+                "enrollment_year": request.enrollment_year,
+                "language": request.language or "vi",
+                "model": request.model,
+                "omit_citations": request.omit_citations,
+                "blocked_topics": list(request.blocked_topics),
             },
             config=config
         )
@@ -148,7 +170,7 @@ async def _initialize_agent():
 
     # Step 3: Add native tools
     logger.info("[3/5] Adding native tools...")
-    native_tools = [get_user_credential]
+    native_tools = [get_user_credential, get_training_score, get_open_classes, check_registration_conflicts, get_uit_announcements]
     all_tools = mcp_tools + native_tools
     logger.info(f"✅ Total tools: {len(all_tools)}")
     logger.info(f"   - MCP tools: {len(mcp_tools)}")
@@ -170,7 +192,9 @@ async def _initialize_agent():
         llm=llm,
         tools=all_tools,
         checkpointer=checkpointer,
-        tool_timeout=120  # 2 minutes for MCP tools (handles cold start)
+        tool_timeout=120,  # 2 minutes for MCP tools (handles cold start)
+        # Models users pick in their settings, same provider as the default
+        llm_factory=lambda model: create_llm(provider=settings.llm.PROVIDER, model=model),
     )
     logger.info("✅ Agent graph created\n")
 
@@ -24,7 +24,7 @@ _sym_db = _symbol_database.Default()
 
 
 
-DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x0b\x61gent.proto\x12\x05\x61gent\"@\n\x08ToolCall\x12\x11\n\ttool_name\x18\x01 \x01(\t\x12\x11\n\targs_json\x18\x02 \x01(\t\x12\x0e\n\x06output\x18\x03 \x01(\t\"D\n\x06Source\x12\r\n\x05title\x18\x01 \x01(\t\x12\x0f\n\x07\x63ontent\x18\x02 \x01(\t\x12\r\n\x05score\x18\x03 \x01(\x02\x12\x0b\n\x03url\x18\x04 \x01(\t\"B\n\x0b\x43hatRequest\x12\x0f\n\x07message\x18\x01 \x01(\t\x12\x0f\n\x07user_id\x18\x02 \x01(\t\x12\x11\n\tthread_id\x18\x03 \x01(\t\"\xa6\x01\n\x0c\x43hatResponse\x12\x0f\n\x07\x63ontent\x18\x01 \x01(\t\x12#\n\ntool_calls\x18\x02 \x03(\x0b\x32\x0f.agent.ToolCall\x12\x17\n\x0freasoning_steps\x18\x03 \x03(\t\x12\x1e\n\x07sources\x18\x04 \x03(\x0b\x32\r.agent.Source\x12\x13\n\x0btokens_used\x18\x05 \x01(\x05\x12\x12\n\nlatency_ms\x18\x06 \x01(\x05\x32\x38\n\x05\x41gent\x12/\n\x04\x43hat\x12\x12.agent.ChatRequest\x1a\x13.agent.ChatResponseB@Z>github.com/giakiet05/uit-ai-assistant/backend/internal/grpc/pbb\x06proto3')
+DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x0b\x61gent.proto\x12\x05\x61gent\"@\n\x08ToolCall\x12\x11\n\ttool_name\x18\x01 \x01(\t\x12\x11\n\targs_json\x18\x02 \x01(\t\x12\x0e\n\x06output\x18\x03 \x01(\t\"D\n\x06Source\x12\r\n\x05title\x18\x01 \x01(\t\x12\x0f\n\x07\x63ontent\x18\x02 \x01(\t\x12\r\n\x05score\x18\x03 \x01(\x02\x12\x0b\n\x03url\x18\x04 \x01(\t\"\xe2\x01\n\x0b\x43hatRequest\x12\x0f\n\x07message\x18\x01 \x01(\t\x12\x0f\n\x07user_id\x18\x02 \x01(\t\x12\x11\n\tthread_id\x18\x03 \x01(\t\x12\x10\n\x08language\x18\x04 \x01(\t\x12\x12\n\nstudent_id\x18\x05 \x01(\t\x12\x0f\n\x07\x66\x61\x63ulty\x18\x06 \x01(\t\x12\x0f\n\x07program\x18\x07 \x01(\t\x12\x17\n\x0f\x65nrollment_year\x18\x08 \x01(\x05\x12\r\n\x05model\x18\t \x01(\t\x12\x16\n\x0eomit_citations\x18\n \x01(\x08\x12\x16\n\x0e\x62locked_topics\x18\x0b \x03(\t\"\xa6\x01\n\x0c\x43hatResponse\x12\x0f\n\x07\x63ontent\x18\x01 \x01(\t\x12#\n\ntool_calls\x18\x02 \x03(\x0b\x32\x0f.agent.ToolCall\x12\x17\n\x0freasoning_steps\x18\x03 \x03(\t\x12\x1e\n\x07sources\x18\x04 \x03(\x0b\x32\r.agent.Source\x12\x13\n\x0btokens_used\x18\x05 \x01(\x05\x12\x12\n\nlatency_ms\x18\x06 \x01(\x05\x32\x38\n\x05\x41gent\x12/\n\x04\x43hat\x12\x12.agent.ChatRequest\x1a\x13.agent.ChatResponseB@Z>github.com/giakiet05/uit-ai-assistant/backend/internal/grpc/pbb\x06proto3')
 
 _globals = globals()
 _builder.BuildMessageAndEnumDescriptors(DESCRIPTOR, _globals)
@@ -36,10 +36,10 @@ if not _descriptor._USE_C_DESCRIPTORS:
   _globals['_TOOLCALL']._serialized_end=86
   _globals['_SOURCE']._serialized_start=88
   _globals['_SOURCE']._serialized_end=156
-  _globals['_CHATREQUEST']._serialized_start=158
-  _globals['_CHATREQUEST']._serialized_end=224
-  _globals['_CHATRESPONSE']._serialized_start=227
-  _globals['_CHATRESPONSE']._serialized_end=393
-  _globals['_AGENT']._serialized_start=395
-  _globals['_AGENT']._serialized_end=451
+  _globals['_CHATREQUEST']._serialized_start=159
+  _globals['_CHATREQUEST']._serialized_end=385
+  _globals['_CHATRESPONSE']._serialized_start=388
+  _globals['_CHATRESPONSE']._serialized_end=554
+  _globals['_AGENT']._serialized_start=556
+  _globals['_AGENT']._serialized_end=612
 # @@protoc_insertion_point(module_scope)
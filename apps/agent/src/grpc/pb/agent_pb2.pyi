@@ -29,14 +29,30 @@ class Source(_message.Message):
     def __init__(self, title: _Optional[str] = ..., content: _Optional[str] = ..., score: _Optional[float] = ..., url: _Optional[str] = ...) -> None: ...
 
 class ChatRequest(_message.Message):
-    __slots__ = ("message", "user_id", "thread_id")
+    __slots__ = ("message", "user_id", "thread_id", "language", "student_id", "faculty", "program", "enrollment_year", "model", "omit_citations", "blocked_topics")
     MESSAGE_FIELD_NUMBER: _ClassVar[int]
     USER_ID_FIELD_NUMBER: _ClassVar[int]
     THREAD_ID_FIELD_NUMBER: _ClassVar[int]
+    LANGUAGE_FIELD_NUMBER: _ClassVar[int]
+    STUDENT_ID_FIELD_NUMBER: _ClassVar[int]
+    FACULTY_FIELD_NUMBER: _ClassVar[int]
+    PROGRAM_FIELD_NUMBER: _ClassVar[int]
+    ENROLLMENT_YEAR_FIELD_NUMBER: _ClassVar[int]
+    MODEL_FIELD_NUMBER: _ClassVar[int]
+    OMIT_CITATIONS_FIELD_NUMBER: _ClassVar[int]
+    BLOCKED_TOPICS_FIELD_NUMBER: _ClassVar[int]
     message: str
     user_id: str
     thread_id: str
-    def __init__(self, message: _Optional[str] = ..., user_id: _Optional[str] = ..., thread_id: _Optional[str] = ...) -> None: ...
+    language: str
+    student_id: str
+    faculty: str
+    program: str
+    enrollment_year: int
+    model: str
+    omit_citations: bool
+    blocked_topics: _containers.RepeatedScalarFieldContainer[str]
+    def __init__(self, message: _Optional[str] = ..., user_id: _Optional[str] = ..., thread_id: _Optional[str] = ..., language: _Optional[str] = ..., student_id: _Optional[str] = ..., faculty: _Optional[str] = ..., program: _Optional[str] = ..., enrollment_year: _Optional[int] = ..., model: _Optional[str] = ..., omit_citations: bool = ..., blocked_topics: _Optional[_Iterable[str]] = ...) -> None: ...
 
 class ChatResponse(_message.Message):
     __slots__ = ("content", "tool_calls", "reasoning_steps", "sources", "tokens_used", "latency_ms")
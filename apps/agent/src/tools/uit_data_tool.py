@@ -0,0 +1,202 @@
+"""
+Native LangChain tools for reading UIT portal data cached by the API gateway.
+"""
+
+import json
+
+import redis
+from langchain_core.tools import tool
+from src.config.settings import settings
+from src.utils.logger import logger
+
+
+# Redis client (shared across tool calls)
+redis_client = redis.from_url(
+    settings.redis.URL,
+    decode_responses=True
+)
+
+
+@tool
+def get_training_score(user_id: str) -> str:
+    """Get the user's training score (điểm rèn luyện) per term from the DRL portal.
+
+    IMPORTANT: You do NOT need to provide user_id parameter - it will be
+    automatically injected from the current user context.
+
+    The API gateway fetches the score from drl.uit.edu.vn with the cookie the
+    user synced via the browser extension, and caches it in Redis. This tool
+    reads that cache.
+
+    Args:
+        user_id: (Auto-injected - DO NOT SPECIFY) The user's ID
+
+    Returns:
+        JSON string with "terms" (semester, academic_year, score, rank),
+        "average" and "fetched_at". A term whose score is null is not graded yet.
+
+    Raises:
+        ValueError: If the training score has not been fetched yet
+    """
+    # Key written by the API gateway (config.RedisUITTrainingScoreKey)
+    key = f"uit_drl:{user_id}"
+
+    try:
+        data = redis_client.get(key)
+
+        if not data:
+            raise ValueError(
+                f"No training score found for user {user_id}. "
+                f"Ask the user to open the training score (DRL) page in the app "
+                f"after syncing the DRL cookie via the browser extension."
+            )
+        logger.debug(f"Retrieved training score for user {user_id}")
+        return json.dumps(json.loads(data), ensure_ascii=False)
+
+    except redis.RedisError as e:
+        raise ValueError(f"Redis error: {str(e)}")
+
+
+def _load_cached(key: str, what: str, user_id: str) -> dict:
+    """Read a JSON document cached by the API gateway, raising ValueError if there is none."""
+    try:
+        data = redis_client.get(key)
+    except redis.RedisError as e:
+        raise ValueError(f"Redis error: {str(e)}")
+
+    if not data:
+        raise ValueError(
+            f"No {what} found for user {user_id}. "
+            f"Ask the user to open the {what} page in the app first."
+        )
+    return json.loads(data)
+
+
+def _period_span(period: str):
+    """Parse a period span such as "1-4" or "6" into (first, last), or None."""
+    first, _, last = (period or "").partition("-")
+    try:
+        start, end = int(first), int(last or first)
+    except ValueError:
+        return None
+    return (start, end) if start <= end else None
+
+
+def _slots_overlap(a: dict, b: dict) -> bool:
+    """Mirror of uit.SlotsOverlap in the API gateway."""
+    if not a.get("day_of_week") or a.get("day_of_week") != b.get("day_of_week"):
+        return False
+    span_a, span_b = _period_span(a.get("period")), _period_span(b.get("period"))
+    return bool(span_a and span_b and span_a[0] <= span_b[1] and span_b[0] <= span_a[1])
+
+
+@tool
+def get_open_classes(user_id: str, subject: str = "") -> str:
+    """List the classes open for course registration (đăng ký học phần) with their remaining slots.
+
+    IMPORTANT: You do NOT need to provide user_id parameter - it will be
+    automatically injected from the current user context.
+
+    Args:
+        user_id: (Auto-injected - DO NOT SPECIFY) The user's ID
+        subject: Optional class code prefix or part of the subject name, e.g. "IT003"
+
+    Returns:
+        JSON list of classes with class_code, subject, day_of_week, period, room,
+        capacity, registered and remaining.
+    """
+    # Key written by the API gateway (config.RedisUITOpenClassesKey)
+    classes = _load_cached(f"uit_open_classes:{user_id}", "open class list", user_id)["classes"]
+
+    needle = subject.strip().lower()
+    if needle:
+        classes = [
+            c for c in classes
+            if c["class_code"].lower().startswith(needle) or needle in c.get("subject", "").lower()
+        ]
+    logger.debug(f"Retrieved {len(classes)} open classes for user {user_id}")
+    return json.dumps(classes, ensure_ascii=False)
+
+
+@tool
+def check_registration_conflicts(user_id: str, class_codes: list[str]) -> str:
+    """Check planned classes against the user's current timetable and each other.
+
+    IMPORTANT: You do NOT need to provide user_id parameter - it will be
+    automatically injected from the current user context.
+
+    Args:
+        user_id: (Auto-injected - DO NOT SPECIFY) The user's ID
+        class_codes: Class codes the user plans to register, e.g. ["IT003.Q12", "MA006.Q11"]
+
+    Returns:
+        JSON with "conflicts" (class_code, conflicts_with, in_timetable), "full"
+        (classes without slots) and "unknown" (codes not open for registration).
+    """
+    classes = _load_cached(f"uit_open_classes:{user_id}", "open class list", user_id)["classes"]
+    timetable = _load_cached(f"uit_schedule:{user_id}", "schedule", user_id)["classes"]
+
+    open_classes = {c["class_code"].upper(): c for c in classes}
+    result = {"conflicts": [], "full": [], "unknown": []}
+    planned = []
+
+    for code in class_codes:
+        cls = open_classes.get(code.strip().upper())
+        if cls is None:
+            result["unknown"].append(code)
+            continue
+        if cls.get("remaining", 0) == 0:
+            result["full"].append(cls["class_code"])
+
+        for current in timetable:
+            if _slots_overlap(cls, current):
+                result["conflicts"].append({
+                    "class_code": cls["class_code"],
+                    "conflicts_with": current["subject_code"],
+                    "in_timetable": True,
+                })
+        for other in planned:
+            if _slots_overlap(cls, other):
+                result["conflicts"].append({
+                    "class_code": cls["class_code"],
+                    "conflicts_with": other["class_code"],
+                    "in_timetable": False,
+                })
+        planned.append(cls)
+
+    return json.dumps(result, ensure_ascii=False)
+
+
+@tool
+def get_uit_announcements(keyword: str = "", limit: int = 10) -> str:
+    """Get the latest official UIT announcements (thông báo) crawled from the school's websites.
+
+    Use this for recent news and notices (registration windows, exam schedules,
+    tuition deadlines, events) that may be newer than the knowledge base.
+
+    Args:
+        keyword: Optional word or phrase to filter titles and summaries, e.g. "học phí"
+        limit: Maximum number of announcements to return (default 10)
+
+    Returns:
+        JSON list of announcements with source, url, title, summary,
+        published_at and cohorts (khóa targeted, empty = everyone), newest first.
+    """
+    # Key written by the API gateway (config.RedisUITAnnouncementsKey)
+    try:
+        data = redis_client.get("uit_announcements")
+    except redis.RedisError as e:
+        raise ValueError(f"Redis error: {str(e)}")
+
+    if not data:
+        raise ValueError("No UIT announcements have been crawled yet.")
+
+    announcements = json.loads(data).get("announcements") or []
+    needle = keyword.strip().lower()
+    if needle:
+        announcements = [
+            a for a in announcements
+            if needle in a.get("title", "").lower() or needle in a.get("summary", "").lower()
+        ]
+    logger.debug(f"Retrieved {len(announcements)} UIT announcements")
+    return json.dumps(announcements[:max(limit, 1)], ensure_ascii=False)
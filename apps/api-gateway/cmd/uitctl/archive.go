@@ -0,0 +1,180 @@
+package main
+
+import (
+	"archive/tar"
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+	"go.mongodb.org/mongo-driver/bson"
+	"golang.org/x/crypto/scrypt"
+)
+
+// Backup archives are gzipped tar files holding, in this order:
+//
+//	manifest.json            what the archive holds, see manifest
+//	collections/<name>.bson  the documents of a collection as concatenated BSON, the format mongodump writes
+//	cookies.enc              the synced UIT portal cookies, encrypted with a passphrase (only with -cookies)
+const (
+	archiveFormatVersion = 1
+	manifestEntry        = "manifest.json"
+	collectionEntryDir   = "collections/"
+	cookiesEntry         = "cookies.enc"
+)
+
+// passphraseEnv holds the passphrase for the cookies, so it is not left in the shell history
+const passphraseEnv = "UITCTL_BACKUP_PASSPHRASE"
+
+type manifest struct {
+	FormatVersion int                  `json:"format_version"`
+	CreatedAt     time.Time            `json:"created_at"`
+	Database      string               `json:"database"`
+	Collections   []manifestCollection `json:"collections"`
+	Cookies       int                  `json:"cookies"` // Number of cookies in cookies.enc, 0 if the archive has none
+}
+
+type manifestCollection struct {
+	Name      string `json:"name"`
+	Documents int64  `json:"documents"`
+}
+
+// backupCookie is a synced cookie in cookies.enc
+type backupCookie struct {
+	UserID    string    `json:"user_id"`
+	Source    string    `json:"source"`
+	Cookie    string    `json:"cookie"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// selectCollections applies the comma-separated -collections and -exclude flags to the available collections.
+// System collections and the migration lock are never backed up or restored.
+func selectCollections(available []string, only, exclude string) ([]string, error) {
+	included := splitList(only)
+	for _, name := range included {
+		if !slices.Contains(available, name) {
+			return nil, fmt.Errorf("collection %q does not exist", name)
+		}
+	}
+	excluded := splitList(exclude)
+
+	var selected []string
+	for _, name := range available {
+		if strings.HasPrefix(name, "system.") || name == config.MigrationLockColName {
+			continue
+		}
+		if len(included) > 0 && !slices.Contains(included, name) || slices.Contains(excluded, name) {
+			continue
+		}
+		selected = append(selected, name)
+	}
+	slices.Sort(selected)
+	return selected, nil
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// writeEntry adds a file of known size to the archive
+func writeEntry(tw *tar.Writer, name string, size int64, r io.Reader) error {
+	header := &tar.Header{
+		Name:    name,
+		Mode:    0o600,
+		Size:    size,
+		ModTime: time.Now(),
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err := io.Copy(tw, r)
+	return err
+}
+
+// readDocument reads the next BSON document of a collection entry, or returns io.EOF after the last one
+func readDocument(r *bufio.Reader) (bson.Raw, error) {
+	var prefix [4]byte
+	if _, err := io.ReadFull(r, prefix[:]); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, errors.New("truncated document")
+		}
+		return nil, err
+	}
+
+	length := int(binary.LittleEndian.Uint32(prefix[:]))
+	if length < 5 {
+		return nil, fmt.Errorf("invalid document length %d", length)
+	}
+	doc := make([]byte, length)
+	copy(doc, prefix[:])
+	if _, err := io.ReadFull(r, doc[4:]); err != nil {
+		return nil, errors.New("truncated document")
+	}
+	return doc, nil
+}
+
+// sealCookies encrypts the cookies with AES-GCM under a key derived from the passphrase.
+// The output is the salt, then the nonce, then the ciphertext.
+func sealCookies(passphrase string, plaintext []byte) ([]byte, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	aead, err := cookieCipher(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	sealed := append(salt, nonce...)
+	return aead.Seal(sealed, nonce, plaintext, nil), nil
+}
+
+func openCookies(passphrase string, sealed []byte) ([]byte, error) {
+	if len(sealed) < 16 {
+		return nil, errors.New("cookies entry is truncated")
+	}
+	salt, rest := sealed[:16], sealed[16:]
+	aead, err := cookieCipher(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) < aead.NonceSize() {
+		return nil, errors.New("cookies entry is truncated")
+	}
+
+	plaintext, err := aead.Open(nil, rest[:aead.NonceSize()], rest[aead.NonceSize():], nil)
+	if err != nil {
+		return nil, errors.New("cannot decrypt the cookies, is the passphrase right?")
+	}
+	return plaintext, nil
+}
+
+func cookieCipher(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, 1<<15, 8, 1, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,384 @@
+package main
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/bootstrap"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// restoreBatchSize is how many documents are written to MongoDB at once while restoring
+const restoreBatchSize = 500
+
+func backup(fs *flag.FlagSet) func(ctx context.Context, tk *bootstrap.Toolkit) error {
+	out := fs.String("out", "", "archive to create, e.g. backup.tar.gz (required, must not exist)")
+	only := fs.String("collections", "", "comma-separated collections to back up (default all)")
+	exclude := fs.String("exclude", "", "comma-separated collections to leave out")
+	cookies := fs.Bool("cookies", false, "also back up the synced UIT portal cookies, encrypted with $"+passphraseEnv)
+
+	return func(ctx context.Context, tk *bootstrap.Toolkit) error {
+		if *out == "" {
+			return errors.New("-out is required")
+		}
+		passphrase := os.Getenv(passphraseEnv)
+		if *cookies && passphrase == "" {
+			return fmt.Errorf("$%s must be set to back up cookies", passphraseEnv)
+		}
+
+		available, err := tk.DB.ListCollectionNames(ctx, bson.M{"type": "collection"})
+		if err != nil {
+			return err
+		}
+		names, err := selectCollections(available, *only, *exclude)
+		if err != nil {
+			return err
+		}
+
+		// Entries need their size up front, so collections are dumped to temporary files first
+		tmpDir, err := os.MkdirTemp("", "uitctl-backup-")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(tmpDir)
+
+		m := manifest{
+			FormatVersion: archiveFormatVersion,
+			CreatedAt:     time.Now(),
+			Database:      tk.DB.Name(),
+		}
+		for _, name := range names {
+			count, err := dumpCollection(ctx, tk.DB.Collection(name), filepath.Join(tmpDir, name))
+			if err != nil {
+				return fmt.Errorf("back up %s: %w", name, err)
+			}
+			m.Collections = append(m.Collections, manifestCollection{Name: name, Documents: count})
+			fmt.Printf("Backed up %d documents of %s\n", count, name)
+		}
+
+		var sealedCookies []byte
+		if *cookies {
+			stored, err := collectCookies(ctx, tk)
+			if err != nil {
+				return fmt.Errorf("back up cookies: %w", err)
+			}
+			data, err := json.Marshal(stored)
+			if err != nil {
+				return err
+			}
+			if sealedCookies, err = sealCookies(passphrase, data); err != nil {
+				return err
+			}
+			m.Cookies = len(stored)
+			fmt.Printf("Backed up %d cookies\n", len(stored))
+		}
+
+		if err := writeArchive(*out, tmpDir, &m, sealedCookies); err != nil {
+			os.Remove(*out)
+			return err
+		}
+		fmt.Printf("Wrote %s\n", *out)
+		return nil
+	}
+}
+
+// dumpCollection writes every document of the collection to path and returns how many there were
+func dumpCollection(ctx context.Context, col *mongo.Collection, path string) (int64, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	w := bufio.NewWriter(f)
+
+	cursor, err := col.Find(ctx, bson.M{})
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var count int64
+	for cursor.Next(ctx) {
+		if _, err := w.Write(cursor.Current); err != nil {
+			return count, err
+		}
+		count++
+	}
+	if err := cursor.Err(); err != nil {
+		return count, err
+	}
+	return count, w.Flush()
+}
+
+func collectCookies(ctx context.Context, tk *bootstrap.Toolkit) ([]backupCookie, error) {
+	stored := []backupCookie{}
+	for _, source := range config.CookieSources {
+		userIDs, err := tk.CookieStore.UserIDs(ctx, source)
+		if err != nil {
+			return nil, err
+		}
+		for _, userID := range userIDs {
+			cookie, ok, err := tk.CookieStore.Get(ctx, userID, source)
+			if err != nil {
+				return nil, err
+			}
+			ttl, hasTTL, err := tk.CookieStore.TTL(ctx, userID, source)
+			if err != nil {
+				return nil, err
+			}
+			// Expired between listing and reading
+			if !ok || !hasTTL {
+				continue
+			}
+			stored = append(stored, backupCookie{
+				UserID:    userID,
+				Source:    source,
+				Cookie:    cookie,
+				ExpiresAt: time.Now().Add(ttl),
+			})
+		}
+	}
+	return stored, nil
+}
+
+func writeArchive(path, tmpDir string, m *manifest, sealedCookies []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	manifestData, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeEntry(tw, manifestEntry, int64(len(manifestData)), bytes.NewReader(manifestData)); err != nil {
+		return err
+	}
+
+	for _, col := range m.Collections {
+		if err := writeFileEntry(tw, collectionEntryDir+col.Name+".bson", filepath.Join(tmpDir, col.Name)); err != nil {
+			return err
+		}
+	}
+
+	if sealedCookies != nil {
+		if err := writeEntry(tw, cookiesEntry, int64(len(sealedCookies)), bytes.NewReader(sealedCookies)); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+func writeFileEntry(tw *tar.Writer, name, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	return writeEntry(tw, name, info.Size(), f)
+}
+
+func restore(fs *flag.FlagSet) func(ctx context.Context, tk *bootstrap.Toolkit) error {
+	in := fs.String("in", "", "archive to restore (required)")
+	only := fs.String("collections", "", "comma-separated collections to restore (default all in the archive)")
+	exclude := fs.String("exclude", "", "comma-separated collections to leave as they are")
+	cookies := fs.Bool("cookies", false, "also restore the archived cookies that have not expired, decrypted with $"+passphraseEnv)
+	drop := fs.Bool("drop", false, "drop each restored collection first, removing documents that are not in the archive")
+	yes := fs.Bool("yes", false, "confirm, documents with the same _id are replaced by the archived ones. Stop the API first")
+
+	return func(ctx context.Context, tk *bootstrap.Toolkit) error {
+		if *in == "" {
+			return errors.New("-in is required")
+		}
+		if !*yes {
+			return errors.New("restoring overwrites data, pass -yes to confirm")
+		}
+		passphrase := os.Getenv(passphraseEnv)
+		if *cookies && passphrase == "" {
+			return fmt.Errorf("$%s must be set to restore cookies", passphraseEnv)
+		}
+
+		f, err := os.Open(*in)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return err
+		}
+		tr := tar.NewReader(gz)
+
+		m, err := readManifest(tr)
+		if err != nil {
+			return err
+		}
+		archived := make([]string, len(m.Collections))
+		for i, col := range m.Collections {
+			archived[i] = col.Name
+		}
+		names, err := selectCollections(archived, *only, *exclude)
+		if err != nil {
+			return err
+		}
+		if *cookies && m.Cookies == 0 {
+			return errors.New("the archive has no cookies")
+		}
+		fmt.Printf("Restoring a backup of %s from %s\n", m.Database, m.CreatedAt.Local().Format(time.DateTime))
+
+		for {
+			header, err := tr.Next()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return err
+			}
+
+			switch {
+			case strings.HasPrefix(header.Name, collectionEntryDir):
+				name := strings.TrimSuffix(strings.TrimPrefix(header.Name, collectionEntryDir), ".bson")
+				if !slices.Contains(names, name) {
+					continue
+				}
+				count, err := restoreCollection(ctx, tk.DB.Collection(name), tr, *drop)
+				if err != nil {
+					return fmt.Errorf("restore %s: %w", name, err)
+				}
+				fmt.Printf("Restored %d documents of %s\n", count, name)
+
+			case header.Name == cookiesEntry && *cookies:
+				count, err := restoreCookies(ctx, tk, tr, passphrase)
+				if err != nil {
+					return fmt.Errorf("restore cookies: %w", err)
+				}
+				fmt.Printf("Restored %d cookies\n", count)
+			}
+		}
+
+		// Dropped collections lost their indexes
+		if err := config.EnsureIndexes(ctx, tk.DB); err != nil {
+			return err
+		}
+		return nil
+	}
+}
+
+func readManifest(tr *tar.Reader) (*manifest, error) {
+	header, err := tr.Next()
+	if err != nil || header.Name != manifestEntry {
+		return nil, errors.New("not a uitctl backup archive")
+	}
+
+	var m manifest
+	if err := json.NewDecoder(tr).Decode(&m); err != nil {
+		return nil, fmt.Errorf("read manifest: %w", err)
+	}
+	if m.FormatVersion != archiveFormatVersion {
+		return nil, fmt.Errorf("archive format %d is not supported, expected %d", m.FormatVersion, archiveFormatVersion)
+	}
+	return &m, nil
+}
+
+// restoreCollection upserts the archived documents by _id and returns how many there were
+func restoreCollection(ctx context.Context, col *mongo.Collection, r io.Reader, drop bool) (int64, error) {
+	if drop {
+		if err := col.Drop(ctx); err != nil {
+			return 0, err
+		}
+	}
+
+	br := bufio.NewReader(r)
+	var count int64
+	batch := make([]mongo.WriteModel, 0, restoreBatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		_, err := col.BulkWrite(ctx, batch, options.BulkWrite().SetOrdered(false))
+		batch = batch[:0]
+		return err
+	}
+
+	for {
+		doc, err := readDocument(br)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return count, err
+		}
+
+		batch = append(batch, mongo.NewReplaceOneModel().
+			SetFilter(bson.D{{Key: "_id", Value: doc.Lookup("_id")}}).
+			SetReplacement(doc).
+			SetUpsert(true))
+		count++
+		if len(batch) == restoreBatchSize {
+			if err := flush(); err != nil {
+				return count, err
+			}
+		}
+	}
+	return count, flush()
+}
+
+func restoreCookies(ctx context.Context, tk *bootstrap.Toolkit, r io.Reader, passphrase string) (int, error) {
+	sealed, err := io.ReadAll(r)
+	if err != nil {
+		return 0, err
+	}
+	data, err := openCookies(passphrase, sealed)
+	if err != nil {
+		return 0, err
+	}
+	var stored []backupCookie
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return 0, err
+	}
+
+	restored := 0
+	for _, c := range stored {
+		ttl := time.Until(c.ExpiresAt)
+		if ttl <= 0 {
+			continue
+		}
+		if err := tk.CookieStore.Save(ctx, c.UserID, c.Source, c.Cookie, ttl); err != nil {
+			return restored, err
+		}
+		restored++
+	}
+	return restored, nil
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/bootstrap"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/bus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// demoChat is the conversation every demo user starts with
+var demoChat = struct {
+	title, question, answer string
+}{
+	title:    "Đăng ký học phần",
+	question: "Khi nào mở cổng đăng ký học phần học kỳ tới?",
+	answer:   "Lịch đăng ký học phần được Phòng Đào tạo công bố trên trang daa.uit.edu.vn khoảng hai tuần trước khi mở cổng. Bạn có thể bật thông báo để được nhắc khi có lịch.",
+}
+
+func seed(fs *flag.FlagSet) func(ctx context.Context, tk *bootstrap.Toolkit) error {
+	count := fs.Int("users", 5, "number of demo users, named demo01, demo02, ...")
+	password := fs.String("password", "demo123", "password of the demo users")
+
+	return func(ctx context.Context, tk *bootstrap.Toolkit) error {
+		if *count < 1 || *count > 99 {
+			return errors.New("-users must be between 1 and 99")
+		}
+
+		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(*password), bcrypt.DefaultCost)
+		if err != nil {
+			return err
+		}
+
+		created := 0
+		for i := 1; i <= *count; i++ {
+			username := fmt.Sprintf("demo%02d", i)
+
+			// Seeding again only adds the users that are missing
+			if _, err := tk.Repos.UserRepo.GetByUsername(ctx, username); err == nil {
+				continue
+			} else if !errors.Is(err, mongo.ErrNoDocuments) {
+				return err
+			}
+
+			if err := seedUser(ctx, tk, username, string(hashedPassword)); err != nil {
+				return fmt.Errorf("%s: %w", username, err)
+			}
+			created++
+		}
+
+		fmt.Printf("Created %d demo users, %d already existed\n", created, *count-created)
+		return nil
+	}
+}
+
+// seedUser creates a verified demo user with a chat session and a welcome notification
+func seedUser(ctx context.Context, tk *bootstrap.Toolkit, username, hashedPassword string) error {
+	now := time.Now()
+	user, err := tk.Repos.UserRepo.Create(ctx, &model.User{
+		Username:   username,
+		Email:      username + "@example.com",
+		Password:   hashedPassword,
+		Provider:   model.ProviderLocal,
+		Role:       model.UserRole,
+		Settings:   model.NewDefaultSettings(),
+		IsVerified: true,
+		IsActive:   true,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	})
+	if err != nil {
+		return err
+	}
+	tk.EventBus.Publish(bus.UserUpdatedEvent{UserID: user.ID.Hex(), Username: username})
+
+	session, err := tk.Repos.ChatSessionRepo.Create(ctx, &model.ChatSession{UserID: user.ID, Title: demoChat.title})
+	if err != nil {
+		return err
+	}
+	messages := []*model.ChatMessage{
+		{SessionID: session.ID, Role: model.RoleUser, Content: demoChat.question, CreatedAt: now},
+		{SessionID: session.ID, Role: model.RoleAssistant, Content: demoChat.answer, CreatedAt: now.Add(time.Second)},
+	}
+	for _, message := range messages {
+		if _, err := tk.Repos.ChatMessageRepo.Create(ctx, message); err != nil {
+			return err
+		}
+	}
+
+	_, err = tk.Repos.NotificationRepo.Create(ctx, &model.Notification{
+		RecipientID: user.ID,
+		Type:        model.NotificationTypeSystem,
+		Message:     "Chào mừng bạn đến với UIT AI Assistant!",
+		CreatedAt:   now,
+	})
+	return err
+}
+
+func reindex(fs *flag.FlagSet) func(ctx context.Context, tk *bootstrap.Toolkit) error {
+	drop := fs.Bool("drop", false, "drop every index except _id first and rebuild them all. "+
+		"Unique constraints are not enforced until they are rebuilt, so stop the API first")
+
+	return func(ctx context.Context, tk *bootstrap.Toolkit) error {
+		if *drop {
+			names, err := tk.DB.ListCollectionNames(ctx, bson.M{"type": "collection"})
+			if err != nil {
+				return err
+			}
+			for _, name := range names {
+				if strings.HasPrefix(name, "system.") {
+					continue
+				}
+				if _, err := tk.DB.Collection(name).Indexes().DropAll(ctx); err != nil {
+					return fmt.Errorf("drop indexes of %s: %w", name, err)
+				}
+				fmt.Printf("Dropped the indexes of %s\n", name)
+			}
+		}
+
+		if err := config.EnsureIndexes(ctx, tk.DB); err != nil {
+			return err
+		}
+
+		fmt.Println("Indexes are up to date")
+		return nil
+	}
+}
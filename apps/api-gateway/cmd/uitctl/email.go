@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/bootstrap"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+)
+
+func sendTestEmail(fs *flag.FlagSet) func(ctx context.Context, tk *bootstrap.Toolkit) error {
+	to := fs.String("to", "", "recipient address (required)")
+
+	return func(ctx context.Context, tk *bootstrap.Toolkit) error {
+		if *to == "" {
+			return errors.New("-to is required")
+		}
+
+		// Ping first: a disabled provider only logs emails, which would otherwise look like a success
+		if err := tk.EmailSender.Ping(ctx); err != nil {
+			return fmt.Errorf("%s provider is not reachable: %w", config.Cfg.Email.Provider, err)
+		}
+
+		// Sent directly rather than queued, so a failure is reported here instead of being retried
+		err := tk.EmailSender.SendNotificationEmail(*to, "UIT AI Assistant test email",
+			"This is a test email sent by uitctl to check the email configuration.", config.Cfg.FrontendURL, "")
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Test email sent to %s through %s\n", *to, config.Cfg.Email.Provider)
+		return nil
+	}
+}
@@ -0,0 +1,101 @@
+// Command uitctl runs administrative tasks against the API's database and services, so operators do not
+// have to edit MongoDB or Redis by hand. It reads the same configuration (.env and environment) as the API.
+//
+// Usage:
+//
+//	uitctl <command> [flags]
+//
+// Run uitctl without arguments to list the commands, or uitctl <command> -h for a command's flags.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/bootstrap"
+)
+
+// closeTimeout bounds how long the Mongo, Redis and agent clients get to close
+const closeTimeout = 10 * time.Second
+
+// command is a uitctl subcommand
+type command struct {
+	name    string
+	summary string
+	// setup declares the command's flags and returns the action to run once they are parsed
+	setup func(fs *flag.FlagSet) func(ctx context.Context, tk *bootstrap.Toolkit) error
+}
+
+var commands = []command{
+	{"create-admin", "Create an admin account, or promote the account with that email", createAdmin},
+	{"seed", "Insert demo users with a chat and a notification each", seed},
+	{"reindex", "Create missing MongoDB indexes, or rebuild them all with -drop", reindex},
+	{"migrate", "Apply pending schema migrations, or list them with -status", migrate},
+	{"backup", "Write the collections, and optionally the synced cookies, to a compressed archive", backup},
+	{"restore", "Load the collections and cookies of a backup archive", restore},
+	{"purge-soft-deleted", "Permanently erase soft-deleted users and their data", purgeSoftDeleted},
+	{"send-test-email", "Send an email through the configured provider and report the result", sendTestEmail},
+	{"invalidate-user-tokens", "Sign a user out of every device", invalidateUserTokens},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	cmd, ok := findCommand(os.Args[1])
+	if !ok {
+		fmt.Fprintf(os.Stderr, "uitctl: unknown command %q\n\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+
+	fs := flag.NewFlagSet("uitctl "+cmd.name, flag.ExitOnError)
+	run := cmd.setup(fs)
+	fs.Parse(os.Args[2:])
+
+	tk, err := bootstrap.InitToolkit()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "uitctl: failed to initialize:", err)
+		os.Exit(1)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err = run(ctx, tk)
+	stop()
+
+	closeCtx, cancel := context.WithTimeout(context.Background(), closeTimeout)
+	defer cancel()
+	if closeErr := tk.Close(closeCtx); closeErr != nil {
+		fmt.Fprintln(os.Stderr, "uitctl: failed to close clients:", closeErr)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "uitctl %s: %v\n", cmd.name, err)
+		os.Exit(1)
+	}
+}
+
+func findCommand(name string) (command, bool) {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd, true
+		}
+	}
+	return command{}, false
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: uitctl <command> [flags]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-24s %s\n", cmd.name, cmd.summary)
+	}
+}
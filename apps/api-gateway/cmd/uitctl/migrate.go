@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/bootstrap"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/migrations"
+)
+
+func migrate(fs *flag.FlagSet) func(ctx context.Context, tk *bootstrap.Toolkit) error {
+	status := fs.Bool("status", false, "list the migrations and when each was applied, without applying any")
+
+	return func(ctx context.Context, tk *bootstrap.Toolkit) error {
+		if *status {
+			statuses, err := migrations.List(ctx, tk.DB)
+			if err != nil {
+				return err
+			}
+			for _, s := range statuses {
+				applied := "pending"
+				if s.Applied != nil {
+					applied = "applied " + s.Applied.AppliedAt.Local().Format(time.DateTime)
+				}
+				fmt.Printf("%4d  %-32s %s\n", s.Version, s.Name, applied)
+			}
+			return nil
+		}
+
+		lease := time.Duration(config.Cfg.Migrations.TimeoutSeconds) * time.Second
+		ran, err := migrations.Run(ctx, tk.DB, lease)
+		for _, m := range ran {
+			fmt.Printf("Applied %d %s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(ran) == 0 {
+			fmt.Println("No pending migrations")
+		}
+		return nil
+	}
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/auth"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/bootstrap"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/bus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// passwordEnv lets scripts pass passwords without leaving them in the shell history
+const passwordEnv = "UITCTL_PASSWORD"
+
+func createAdmin(fs *flag.FlagSet) func(ctx context.Context, tk *bootstrap.Toolkit) error {
+	email := fs.String("email", "", "email of the admin (required)")
+	username := fs.String("username", "", "username of a new account, 3 to 20 characters")
+	password := fs.String("password", "", "password of a new account, at least 6 characters (default $"+passwordEnv+")")
+
+	return func(ctx context.Context, tk *bootstrap.Toolkit) error {
+		addr := strings.ToLower(strings.TrimSpace(*email))
+		if addr == "" {
+			return errors.New("-email is required")
+		}
+
+		user, err := tk.Repos.UserRepo.GetByEmail(ctx, addr)
+		switch {
+		case err == nil:
+			return promoteToAdmin(ctx, tk, user)
+		case !errors.Is(err, mongo.ErrNoDocuments):
+			return err
+		}
+
+		pass := *password
+		if pass == "" {
+			pass = os.Getenv(passwordEnv)
+		}
+		if len(*username) < 3 || len(*username) > 20 {
+			return errors.New("-username must be 3 to 20 characters for a new account")
+		}
+		if len(pass) < 6 {
+			return fmt.Errorf("-password (or $%s) must be at least 6 characters for a new account", passwordEnv)
+		}
+		if _, err := tk.Repos.UserRepo.GetByUsername(ctx, *username); err == nil {
+			return fmt.Errorf("username %q is taken", *username)
+		} else if !errors.Is(err, mongo.ErrNoDocuments) {
+			return err
+		}
+
+		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(pass), bcrypt.DefaultCost)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		created, err := tk.Repos.UserRepo.Create(ctx, &model.User{
+			Username:   *username,
+			Email:      addr,
+			Password:   string(hashedPassword),
+			Provider:   model.ProviderLocal,
+			Role:       model.AdminRole,
+			Settings:   model.NewDefaultSettings(),
+			IsVerified: true,
+			IsActive:   true,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		})
+		if err != nil {
+			return err
+		}
+
+		// The username is taken now
+		tk.EventBus.Publish(bus.UserUpdatedEvent{UserID: created.ID.Hex(), Username: created.Username})
+
+		fmt.Printf("Created admin %s (%s), id %s\n", created.Username, created.Email, created.ID.Hex())
+		return nil
+	}
+}
+
+// promoteToAdmin gives an existing account the admin role and signs it out so the role is picked up
+func promoteToAdmin(ctx context.Context, tk *bootstrap.Toolkit, user *model.User) error {
+	if user.Role == model.AdminRole {
+		fmt.Printf("%s (%s) is already an admin\n", user.Username, user.Email)
+		return nil
+	}
+
+	userID := user.ID.Hex()
+	user.Role = model.AdminRole
+	user.UpdatedAt = time.Now()
+	if _, err := tk.Repos.UserRepo.Update(ctx, user); err != nil {
+		return err
+	}
+
+	if err := auth.TokenSvc.InvalidateAllUserTokens(ctx, userID); err != nil {
+		fmt.Fprintln(os.Stderr, "Warning: failed to sign the user out, the new role applies from their next login:", err)
+	}
+	tk.EventBus.Publish(bus.UserUpdatedEvent{UserID: userID, Username: user.Username})
+	tk.EventBus.Publish(bus.SessionTerminatedEvent{UserID: userID, Reason: bus.SessionTerminatedRoleChanged})
+
+	fmt.Printf("Promoted %s (%s) to admin\n", user.Username, user.Email)
+	return nil
+}
+
+func invalidateUserTokens(fs *flag.FlagSet) func(ctx context.Context, tk *bootstrap.Toolkit) error {
+	ident := fs.String("user", "", "id, username or email of the user (required)")
+
+	return func(ctx context.Context, tk *bootstrap.Toolkit) error {
+		user, err := findUser(ctx, tk, *ident)
+		if err != nil {
+			return err
+		}
+
+		userID := user.ID.Hex()
+		if err := auth.TokenSvc.InvalidateAllUserTokens(ctx, userID); err != nil {
+			return err
+		}
+		tk.EventBus.Publish(bus.SessionTerminatedEvent{UserID: userID, Reason: bus.SessionTerminatedForceLogout})
+
+		fmt.Printf("Signed %s (%s) out of every device\n", user.Username, user.Email)
+		return nil
+	}
+}
+
+func purgeSoftDeleted(fs *flag.FlagSet) func(ctx context.Context, tk *bootstrap.Toolkit) error {
+	days := fs.Int("older-than-days", 0, "only erase users deleted at least this many days ago, 0 erases every soft-deleted user")
+	yes := fs.Bool("yes", false, "confirm, the users cannot be restored afterwards")
+
+	return func(ctx context.Context, tk *bootstrap.Toolkit) error {
+		if !*yes {
+			return errors.New("erasing users cannot be undone, pass -yes to confirm")
+		}
+
+		purged, err := tk.Services.UserPurgeService.PurgeDeletedBefore(ctx, time.Now().AddDate(0, 0, -*days))
+		fmt.Printf("Erased %d users\n", purged)
+		return err
+	}
+}
+
+// findUser looks a user up by id, email or username
+func findUser(ctx context.Context, tk *bootstrap.Toolkit, ident string) (*model.User, error) {
+	ident = strings.TrimSpace(ident)
+	if ident == "" {
+		return nil, errors.New("-user is required")
+	}
+
+	var user *model.User
+	var err error
+	switch {
+	case primitive.IsValidObjectID(ident):
+		user, err = tk.Repos.UserRepo.GetByID(ctx, ident)
+	case strings.Contains(ident, "@"):
+		user, err = tk.Repos.UserRepo.GetByEmail(ctx, strings.ToLower(ident))
+	default:
+		user, err = tk.Repos.UserRepo.GetByUsername(ctx, ident)
+	}
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, fmt.Errorf("no user matches %q", ident)
+	}
+	return user, err
+}
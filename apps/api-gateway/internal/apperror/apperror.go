@@ -1,6 +1,7 @@
 package apperror
 
 import (
+	"context"
 	"errors"
 	"net/http"
 )
@@ -17,6 +18,9 @@ func (e AppError) Error() string {
 
 // Code extracts the error Code from an error, returning the AppError Code if it's an AppError, otherwise returns INTERNAL_ERROR
 func Code(err error) string {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrRequestTimeout.Code
+	}
 	if isAppError(err) {
 		return err.(AppError).Code
 	}
@@ -31,6 +35,9 @@ func NewError(originalErr error, code, message string) *AppError {
 
 // Message extracts the error Message from an error, returning the AppError Message if it's an AppError, otherwise returns a generic internal error Message
 func Message(err error) string {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrRequestTimeout.Message
+	}
 	if isAppError(err) {
 		return err.(AppError).Message
 	}
@@ -59,20 +66,46 @@ func StatusFromError(err error) int {
 	switch {
 	// 400 Bad Request
 	case isErrorType(err, ErrBadRequest, ErrInvalidID, ErrInvalidOTP, ErrOTPExpired,
-		ErrInvalidGender, ErrInvalidDateFormat, ErrAgeTooYoung, ErrInvalidBirthDate, ErrInvalidProvince, ErrTooManyInterests, ErrInvalidInterest):
+		ErrInvalidGender, ErrInvalidDateFormat, ErrAgeTooYoung, ErrInvalidBirthDate, ErrInvalidProvince, ErrTooManyInterests, ErrInvalidInterest,
+		ErrInvalidNotificationType, ErrInvalidDeliverAt, ErrInvalidCursor, ErrInvalidDateRange, ErrMessageNotReportable, ErrInvalidMonth,
+		ErrUserNotDeleted, ErrInvalidEmailTemplate, ErrCannotDemoteSelf, ErrInvalidStudentID, ErrInvalidEnrollmentYear,
+		ErrAvatarRequired, ErrInvalidAvatarType, ErrInvalidAvatarDimensions,
+		ErrInvalidModel, ErrInvalidTimezone, ErrTooManyBlockedTopics, ErrInvalidCookieSource, ErrInvalidExtensionVersion,
+		ErrInvalidIdempotencyKey):
 		return http.StatusBadRequest
 	// 401 Unauthorized
-	case isErrorType(err, ErrInvalidCredentials, ErrInvalidToken, ErrInvalidClaims, ErrInvalidIssuer, ErrInvalidAudience, ErrTokenInvalidated):
+	case isErrorType(err, ErrInvalidCredentials, ErrInvalidToken, ErrInvalidClaims, ErrInvalidIssuer, ErrInvalidAudience, ErrTokenInvalidated, ErrExtensionSessionExpired):
 		return http.StatusUnauthorized
 	// 403 Forbidden
-	case isErrorType(err, ErrForbidden, ErrUserInactive, ErrEmailNotVerified):
+	case isErrorType(err, ErrForbidden, ErrUserInactive, ErrEmailNotVerified, ErrCannotModifyAdmin, ErrDownloadLinkInvalid,
+		ErrProfilePrivate, ErrUnsubscribeLinkInvalid, ErrCalendarLinkInvalid):
 		return http.StatusForbidden
 	// 404 Not Found
-	case isErrorType(err, ErrUserNotFound):
+	case isErrorType(err, ErrUserNotFound, ErrAnnouncementNotFound, ErrScheduledNotificationNotFound,
+		ErrNotificationNotFound, ErrChatSessionNotFound, ErrChatMessageNotFound, ErrReportNotFound,
+		ErrEmailCampaignNotFound, ErrDataExportNotFound, ErrBlockedTopicNotFound, ErrFailedEmailNotFound):
 		return http.StatusNotFound
 	// 409 Conflict
-	case isErrorType(err, ErrUsernameExists, ErrEmailExists, ErrEmailAlreadyVerified, ErrLoginMethodMismatch):
+	case isErrorType(err, ErrUsernameExists, ErrEmailExists, ErrEmailAlreadyVerified, ErrLoginMethodMismatch,
+		ErrAnnouncementNotEditable, ErrAlreadyReported, ErrEmailCampaignAlreadySent, ErrLastAdmin,
+		ErrDataExportInProgress, ErrBlockedTopicExists, ErrPortalCookieMissing, ErrPortalSessionExpired,
+		ErrIdempotencyKeyInUse):
 		return http.StatusConflict
+	// 413 Content Too Large
+	case isErrorType(err, ErrRequestTooLarge, ErrAvatarTooLarge):
+		return http.StatusRequestEntityTooLarge
+	// 422 Unprocessable Entity
+	case isErrorType(err, ErrIdempotencyKeyReused):
+		return http.StatusUnprocessableEntity
+	// 429 Too Many Requests
+	case isErrorType(err, ErrQuotaExceeded, ErrUsageLimitReached, ErrDataExportTooSoon, ErrRateLimited):
+		return http.StatusTooManyRequests
+	// 502 Bad Gateway
+	case isErrorType(err, ErrPortalUnavailable):
+		return http.StatusBadGateway
+	// 504 Gateway Timeout, the route's timeout ran out before a downstream call finished
+	case isErrorType(err, ErrRequestTimeout, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
 	// 500 Internal Server Error
 	case isErrorType(err, ErrInternal, ErrNoFieldsToUpdate):
 		return http.StatusInternalServerError
@@ -99,22 +132,98 @@ var (
 
 	// Generic
 	ErrInternal          = AppError{Code: "INTERNAL_ERROR", Message: "Lỗi hệ thống"}
+	ErrRequestTooLarge   = AppError{Code: "REQUEST_TOO_LARGE", Message: "Dữ liệu gửi lên vượt quá dung lượng cho phép"}
+	ErrRequestTimeout    = AppError{Code: "REQUEST_TIMEOUT", Message: "Yêu cầu xử lý quá lâu, vui lòng thử lại sau"}
+	ErrRateLimited       = AppError{Code: "RATE_LIMITED", Message: "Bạn thao tác quá nhanh, vui lòng thử lại sau"}
 	ErrNoFieldsToUpdate  = AppError{Code: "NO_FIELDS_TO_UPDATE", Message: "Không có trường nào để cập nhật"}
 	ErrInvalidID         = AppError{Code: "INVALID_ID", Message: "Định dạng ID không hợp lệ"}
+	ErrInvalidCursor     = AppError{Code: "INVALID_CURSOR", Message: "Con trỏ phân trang không hợp lệ"}
 	ErrPaginationInvalid = AppError{Code: "PAGINATION_INVALID", Message: "Số trang hoặc kích thước trang không hợp lệ. Kích thước trang phải nhỏ hơn 500."}
 
+	// Idempotency-Key header
+	ErrInvalidIdempotencyKey = AppError{Code: "INVALID_IDEMPOTENCY_KEY", Message: "Idempotency-Key không hợp lệ"}
+	ErrIdempotencyKeyInUse   = AppError{Code: "IDEMPOTENCY_KEY_IN_USE", Message: "Yêu cầu với Idempotency-Key này đang được xử lý"}
+	ErrIdempotencyKeyReused  = AppError{Code: "IDEMPOTENCY_KEY_REUSED", Message: "Idempotency-Key đã được dùng cho một yêu cầu khác"}
+
 	// User-related
-	ErrUserNotFound   = AppError{Code: "USER_NOT_FOUND", Message: "Không tìm thấy người dùng"}
-	ErrUsernameExists = AppError{Code: "USERNAME_EXISTS", Message: "Tên người dùng đã tồn tại"}
-	ErrEmailExists    = AppError{Code: "EMAIL_EXISTS", Message: "Email đã được sử dụng"}
-	ErrUserInactive   = AppError{Code: "USER_INACTIVE", Message: "Tài khoản người dùng đã bị vô hiệu hóa"}
+	ErrUserNotFound           = AppError{Code: "USER_NOT_FOUND", Message: "Không tìm thấy người dùng"}
+	ErrUsernameExists         = AppError{Code: "USERNAME_EXISTS", Message: "Tên người dùng đã tồn tại"}
+	ErrEmailExists            = AppError{Code: "EMAIL_EXISTS", Message: "Email đã được sử dụng"}
+	ErrUserInactive           = AppError{Code: "USER_INACTIVE", Message: "Tài khoản người dùng đã bị vô hiệu hóa"}
+	ErrProfilePrivate         = AppError{Code: "PROFILE_PRIVATE", Message: "Hồ sơ này không được công khai"}
+	ErrUnsubscribeLinkInvalid = AppError{Code: "UNSUBSCRIBE_LINK_INVALID", Message: "Liên kết hủy đăng ký không hợp lệ"}
+	ErrInvalidStudentID       = AppError{Code: "INVALID_STUDENT_ID", Message: "MSSV phải gồm đúng 8 chữ số"}
+	ErrInvalidEnrollmentYear  = AppError{Code: "INVALID_ENROLLMENT_YEAR", Message: "Năm nhập học không hợp lệ"}
+
+	// Admin user management
+	ErrCannotModifyAdmin = AppError{Code: "CANNOT_MODIFY_ADMIN", Message: "Không thể thực hiện thao tác này với tài khoản quản trị viên"}
+	ErrUserNotDeleted    = AppError{Code: "USER_NOT_DELETED", Message: "Người dùng chưa bị xóa"}
+	ErrCannotDemoteSelf  = AppError{Code: "CANNOT_DEMOTE_SELF", Message: "Không thể tự hạ quyền quản trị viên của chính mình"}
+	ErrLastAdmin         = AppError{Code: "LAST_ADMIN", Message: "Không thể hạ quyền quản trị viên cuối cùng"}
 
 	// Profile validation
 	ErrInvalidGender     = AppError{Code: "INVALID_GENDER", Message: "Giá trị giới tính không hợp lệ"}
 	ErrInvalidDateFormat = AppError{Code: "INVALID_DATE_FORMAT", Message: "Định dạng ngày không hợp lệ, sử dụng YYYY-MM-DD"}
+	ErrInvalidDateRange  = AppError{Code: "INVALID_DATE_RANGE", Message: "Khoảng thời gian không hợp lệ, tối đa 180 ngày"}
+	ErrInvalidMonth      = AppError{Code: "INVALID_MONTH", Message: "Tháng không hợp lệ, sử dụng YYYY-MM"}
 	ErrAgeTooYoung       = AppError{Code: "AGE_TOO_YOUNG", Message: "Phải từ 13 tuổi trở lên"}
 	ErrInvalidBirthDate  = AppError{Code: "INVALID_BIRTH_DATE", Message: "Ngày sinh không hợp lệ"}
 	ErrInvalidProvince   = AppError{Code: "INVALID_PROVINCE", Message: "Tỉnh/thành phố không hợp lệ"}
 	ErrTooManyInterests  = AppError{Code: "TOO_MANY_INTERESTS", Message: "Tối đa 10 sở thích"}
 	ErrInvalidInterest   = AppError{Code: "INVALID_INTEREST", Message: "Sở thích không hợp lệ"}
+
+	// Avatar validation
+	ErrAvatarRequired          = AppError{Code: "AVATAR_REQUIRED", Message: "Vui lòng chọn một ảnh đại diện"}
+	ErrAvatarTooLarge          = AppError{Code: "AVATAR_TOO_LARGE", Message: "Ảnh đại diện vượt quá dung lượng cho phép"}
+	ErrInvalidAvatarType       = AppError{Code: "INVALID_AVATAR_TYPE", Message: "Ảnh đại diện phải có định dạng JPEG, PNG hoặc GIF"}
+	ErrInvalidAvatarDimensions = AppError{Code: "INVALID_AVATAR_DIMENSIONS", Message: "Kích thước ảnh đại diện quá nhỏ hoặc quá lớn"}
+	ErrInvalidModel            = AppError{Code: "INVALID_MODEL", Message: "Model không được hỗ trợ"}
+	ErrInvalidTimezone         = AppError{Code: "INVALID_TIMEZONE", Message: "Múi giờ không hợp lệ"}
+	ErrTooManyBlockedTopics    = AppError{Code: "TOO_MANY_BLOCKED_TOPICS", Message: "Tối đa 20 chủ đề bị chặn"}
+	ErrBlockedTopicExists      = AppError{Code: "BLOCKED_TOPIC_EXISTS", Message: "Chủ đề này đã bị chặn"}
+	ErrBlockedTopicNotFound    = AppError{Code: "BLOCKED_TOPIC_NOT_FOUND", Message: "Không tìm thấy chủ đề bị chặn"}
+
+	// Portal cookie-related
+	ErrInvalidCookieSource  = AppError{Code: "INVALID_SOURCE", Message: "Nguồn không hợp lệ, phải là daa, courses hoặc drl"}
+	ErrPortalCookieMissing  = AppError{Code: "PORTAL_COOKIE_MISSING", Message: "Bạn chưa đồng bộ phiên đăng nhập cổng UIT, hãy đồng bộ bằng tiện ích mở rộng"}
+	ErrPortalSessionExpired = AppError{Code: "PORTAL_SESSION_EXPIRED", Message: "Phiên đăng nhập cổng UIT đã hết hạn, hãy đồng bộ lại bằng tiện ích mở rộng"}
+	ErrPortalUnavailable    = AppError{Code: "PORTAL_UNAVAILABLE", Message: "Không thể kết nối tới cổng UIT, vui lòng thử lại sau"}
+	ErrCalendarLinkInvalid  = AppError{Code: "CALENDAR_LINK_INVALID", Message: "Liên kết lịch không hợp lệ"}
+
+	// Extension-related
+	ErrInvalidExtensionVersion = AppError{Code: "INVALID_EXTENSION_VERSION", Message: "Phiên bản tiện ích mở rộng không hợp lệ"}
+	ErrExtensionSessionExpired = AppError{Code: "EXTENSION_SESSION_EXPIRED", Message: "Phiên đăng nhập của tiện ích mở rộng đã hết hạn, vui lòng đăng nhập lại"}
+
+	// Notification-related
+	ErrNotificationNotFound          = AppError{Code: "NOTIFICATION_NOT_FOUND", Message: "Không tìm thấy thông báo"}
+	ErrInvalidNotificationType       = AppError{Code: "INVALID_NOTIFICATION_TYPE", Message: "Loại thông báo không hợp lệ"}
+	ErrInvalidDeliverAt              = AppError{Code: "INVALID_DELIVER_AT", Message: "Thời gian gửi phải ở trong tương lai"}
+	ErrScheduledNotificationNotFound = AppError{Code: "SCHEDULED_NOTIFICATION_NOT_FOUND", Message: "Không tìm thấy thông báo đã lên lịch hoặc thông báo đã được xử lý"}
+
+	// Chat-related
+	ErrChatSessionNotFound = AppError{Code: "CHAT_SESSION_NOT_FOUND", Message: "Không tìm thấy phiên trò chuyện"}
+	ErrChatMessageNotFound = AppError{Code: "CHAT_MESSAGE_NOT_FOUND", Message: "Không tìm thấy tin nhắn"}
+	ErrQuotaExceeded       = AppError{Code: "QUOTA_EXCEEDED", Message: "Bạn đã dùng hết hạn mức trò chuyện hôm nay, vui lòng thử lại vào ngày mai"}
+	ErrUsageLimitReached   = AppError{Code: "USAGE_LIMIT_REACHED", Message: "Bạn đã đạt giới hạn sử dụng hôm nay do chính bạn đặt. Hãy thay đổi giới hạn trong cài đặt để tiếp tục"}
+
+	// Report-related
+	ErrReportNotFound       = AppError{Code: "REPORT_NOT_FOUND", Message: "Không tìm thấy báo cáo"}
+	ErrAlreadyReported      = AppError{Code: "ALREADY_REPORTED", Message: "Bạn đã báo cáo câu trả lời này"}
+	ErrMessageNotReportable = AppError{Code: "MESSAGE_NOT_REPORTABLE", Message: "Chỉ có thể báo cáo câu trả lời của trợ lý"}
+
+	// Email campaign-related
+	ErrEmailCampaignNotFound    = AppError{Code: "EMAIL_CAMPAIGN_NOT_FOUND", Message: "Không tìm thấy chiến dịch email"}
+	ErrEmailCampaignAlreadySent = AppError{Code: "EMAIL_CAMPAIGN_ALREADY_SENT", Message: "Chiến dịch email đã được gửi"}
+	ErrInvalidEmailTemplate     = AppError{Code: "INVALID_EMAIL_TEMPLATE", Message: "Mẫu email không hợp lệ"}
+	ErrFailedEmailNotFound      = AppError{Code: "FAILED_EMAIL_NOT_FOUND", Message: "Không tìm thấy email gửi thất bại"}
+
+	// Data export-related
+	ErrDataExportNotFound   = AppError{Code: "DATA_EXPORT_NOT_FOUND", Message: "Không tìm thấy yêu cầu xuất dữ liệu"}
+	ErrDataExportInProgress = AppError{Code: "DATA_EXPORT_IN_PROGRESS", Message: "Yêu cầu xuất dữ liệu trước đó đang được xử lý"}
+	ErrDataExportTooSoon    = AppError{Code: "DATA_EXPORT_TOO_SOON", Message: "Bạn vừa yêu cầu xuất dữ liệu, vui lòng thử lại sau"}
+	ErrDownloadLinkInvalid  = AppError{Code: "DOWNLOAD_LINK_INVALID", Message: "Liên kết tải xuống không hợp lệ hoặc đã hết hạn"}
+
+	// Announcement-related
+	ErrAnnouncementNotFound    = AppError{Code: "ANNOUNCEMENT_NOT_FOUND", Message: "Không tìm thấy thông báo chung"}
+	ErrAnnouncementNotEditable = AppError{Code: "ANNOUNCEMENT_NOT_EDITABLE", Message: "Thông báo chung đã được gửi, không thể chỉnh sửa"}
 )
@@ -18,6 +18,11 @@ type AuthUser struct {
 	ID       string
 	Role     string
 	Settings interface{} // Will hold *model.UserSettings, using interface{} to avoid circular import
+	Student  interface{} // Will hold model.StudentProfile, loaded with the settings
+
+	// Set for extension tokens only
+	Scope        string    // ScopeExtension
+	SessionStart time.Time // When the extension session was first exchanged from a full login
 }
 
 // SetupTokenClaims holds the claims for the short-lived token used for completing Google user setup.
@@ -29,6 +34,15 @@ type SetupTokenClaims struct {
 	jwt.RegisteredClaims
 }
 
+// LinkTokenClaims holds the claims for the short-lived token used for linking a Google account
+// to an existing local account with the same email.
+type LinkTokenClaims struct {
+	UserID   string `json:"user_id"`
+	GoogleID string `json:"google_id"`
+	Email    string `json:"email"`
+	jwt.RegisteredClaims
+}
+
 // VerificationTokenClaims holds the claims for email verification after OTP is verified.
 // This token allows the user to complete registration within 15 minutes.
 type VerificationTokenClaims struct {
@@ -37,6 +51,21 @@ type VerificationTokenClaims struct {
 	jwt.RegisteredClaims
 }
 
+// ExtensionTokenClaims holds the claims for the scoped token the extension uses instead of the user's
+// access and refresh tokens. It only grants the cookie-sync and heartbeat endpoints.
+type ExtensionTokenClaims struct {
+	UserID       string `json:"user_id"`
+	Scope        string `json:"scope"`
+	SessionStart int64  `json:"session_start"` // Unix time of the full login the session was exchanged from, kept on renewal
+	jwt.RegisteredClaims
+}
+
+// ScopeExtension is the scope of extension tokens
+const ScopeExtension = "extension"
+
+// LinkTokenTTL is how long a user has to confirm linking a Google account with their password
+const LinkTokenTTL = 15 * time.Minute
+
 // Global token service instance
 var TokenSvc *TokenService
 
@@ -129,6 +158,42 @@ func ParseSetupToken(tokenStr string) (*SetupTokenClaims, error) {
 	return &claims, nil
 }
 
+// ====== Link Token (for Google OAuth) ======
+
+// CreateLinkToken creates a short-lived token to link a Google account to an existing local user.
+func CreateLinkToken(userID string, userInfo *GoogleUserInfo) (string, error) {
+	claims := LinkTokenClaims{
+		UserID:   userID,
+		GoogleID: userInfo.ID,
+		Email:    userInfo.Email,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(), // Lets the password check be spent only once
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(LinkTokenTTL)),
+			Issuer:    config.Cfg.JWTIssuer,
+		},
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(config.Cfg.JWTSecret + "-link"))
+}
+
+// ParseLinkToken validates the link token and returns the claims.
+func ParseLinkToken(tokenStr string) (*LinkTokenClaims, error) {
+	var claims LinkTokenClaims
+	token, err := jwt.ParseWithClaims(tokenStr, &claims, func(t *jwt.Token) (interface{}, error) {
+		return []byte(config.Cfg.JWTSecret + "-link"), nil
+	})
+
+	if err != nil {
+		return nil, apperror.ErrInvalidToken
+	}
+
+	if !token.Valid || claims.ID == "" {
+		return nil, apperror.ErrInvalidToken
+	}
+
+	return &claims, nil
+}
+
 // ====== Verification Token (for Email Verification) ======
 
 // CreateVerificationToken creates a short-lived token after email OTP is verified.
@@ -163,6 +228,67 @@ func ParseVerificationToken(tokenStr string) (*VerificationTokenClaims, error) {
 	return &claims, nil
 }
 
+// ====== Extension Token (for the Chrome extension) ======
+
+// CreateExtensionToken creates a scoped token for the extension, valid for ttl. sessionStart is carried over
+// from the token being renewed, so renewals cannot outlive the session's maximum age.
+func CreateExtensionToken(userID string, sessionStart time.Time, ttl time.Duration) (string, time.Time, error) {
+	expiresAt := time.Now().Add(ttl)
+	claims := ExtensionTokenClaims{
+		UserID:       userID,
+		Scope:        ScopeExtension,
+		SessionStart: sessionStart.Unix(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(), // Lets the token be revoked through the blacklist
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Issuer:    config.Cfg.JWTIssuer,
+		},
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	// A separate secret keeps extension tokens from ever passing as access tokens
+	signed, err := token.SignedString([]byte(config.Cfg.JWTSecret + "-extension"))
+	return signed, expiresAt, err
+}
+
+// ParseExtensionToken validates the extension token and returns the user it was issued to.
+func ParseExtensionToken(tokenStr string) (AuthUser, error) {
+	var claims ExtensionTokenClaims
+	token, err := jwt.ParseWithClaims(tokenStr, &claims, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+		}
+		return []byte(config.Cfg.JWTSecret + "-extension"), nil
+	})
+
+	if err != nil {
+		return AuthUser{}, apperror.ErrInvalidToken
+	}
+
+	if !token.Valid || claims.Scope != ScopeExtension || claims.UserID == "" {
+		return AuthUser{}, apperror.ErrInvalidToken
+	}
+
+	if claims.Issuer != config.Cfg.JWTIssuer {
+		return AuthUser{}, apperror.ErrInvalidIssuer
+	}
+
+	if TokenSvc != nil {
+		ctx := context.Background()
+
+		// Invalidating all of the user's tokens (deletion, bans) revokes extension tokens too
+		if claims.IssuedAt == nil || !TokenSvc.IsUserValid(ctx, claims.UserID, claims.IssuedAt.Time) {
+			return AuthUser{}, apperror.ErrTokenInvalidated
+		}
+
+		if claims.ID != "" && TokenSvc.IsTokenBlacklisted(ctx, claims.ID) {
+			return AuthUser{}, apperror.ErrTokenInvalidated
+		}
+	}
+
+	return AuthUser{ID: claims.UserID, Scope: ScopeExtension, SessionStart: time.Unix(claims.SessionStart, 0)}, nil
+}
+
 // ====== PARSE ======
 
 func ParseAccessToken(tokenStr string) (AuthUser, error) {
@@ -200,8 +326,8 @@ func ParseAccessToken(tokenStr string) (AuthUser, error) {
 	if TokenSvc != nil {
 		ctx := context.Background()
 
-		// Check if user is deleted/invalidated
-		if !TokenSvc.IsUserValid(ctx, userID) {
+		// Check if the user's tokens were invalidated after this one was issued
+		if !tokenIssuedAfterInvalidation(ctx, userID, claims) {
 			return AuthUser{}, apperror.ErrTokenInvalidated
 		}
 
@@ -249,8 +375,8 @@ func ParseRefreshToken(tokenStr string) (string, error) {
 	if TokenSvc != nil {
 		ctx := context.Background()
 
-		// Check if user is deleted/invalidated
-		if !TokenSvc.IsUserValid(ctx, userID) {
+		// Check if the user's tokens were invalidated after this one was issued
+		if !tokenIssuedAfterInvalidation(ctx, userID, claims) {
 			return "", apperror.ErrTokenInvalidated
 		}
 
@@ -265,6 +391,16 @@ func ParseRefreshToken(tokenStr string) (string, error) {
 
 // ====== HELPERS ======
 
+// tokenIssuedAfterInvalidation reports whether a token is still valid given the last time all of the user's
+// tokens were invalidated. Tokens without an issue time are rejected.
+func tokenIssuedAfterInvalidation(ctx context.Context, userID string, claims jwt.MapClaims) bool {
+	issuedAt, err := claims.GetIssuedAt()
+	if err != nil || issuedAt == nil {
+		return false
+	}
+	return TokenSvc.IsUserValid(ctx, userID, issuedAt.Time)
+}
+
 func IsOwner(c *gin.Context, ownerID string) bool {
 	authUser, exists := c.Get("authUser")
 	if !exists {
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -21,18 +22,26 @@ func NewTokenService(redisClient *redis.Client) *TokenService {
 	}
 }
 
-// InvalidateAllUserTokens marks a user as deleted in Redis
-// Used for: Delete user account
+// userInvalidationTTL keeps a user's invalidation time for longer than any token issued before it can live
+const userInvalidationTTL = 90 * 24 * time.Hour
+
+// InvalidateAllUserTokens rejects every token issued to the user until now. Tokens issued afterwards, e.g. on
+// the user's next login, are accepted.
+// Used for: Delete user account, ban, force logout, role change
 func (s *TokenService) InvalidateAllUserTokens(ctx context.Context, userID string) error {
 	key := fmt.Sprintf(config.RedisInvalidatedUserKey, userID)
-	return s.redisClient.Set(ctx, key, time.Now().Unix(), 90*24*time.Hour).Err()
+	return s.redisClient.Set(ctx, key, time.Now().Unix(), userInvalidationTTL).Err()
 }
 
-// IsUserValid checks if a user is still valid (not invalidated)
-func (s *TokenService) IsUserValid(ctx context.Context, userID string) bool {
+// IsUserValid checks that a token issued to the user at issuedAt was not invalidated. Token times have
+// second precision, so a token issued in the same second as the invalidation is rejected too.
+func (s *TokenService) IsUserValid(ctx context.Context, userID string, issuedAt time.Time) bool {
 	key := fmt.Sprintf(config.RedisInvalidatedUserKey, userID)
-	exists, err := s.redisClient.Exists(ctx, key).Result()
-	return exists == 0 && err == nil
+	invalidatedAt, err := s.redisClient.Get(ctx, key).Int64()
+	if errors.Is(err, redis.Nil) {
+		return true
+	}
+	return err == nil && issuedAt.Unix() > invalidatedAt
 }
 
 // InvalidateToken blacklists a specific token by its JTI
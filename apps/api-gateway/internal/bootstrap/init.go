@@ -1,21 +1,30 @@
 package bootstrap
 
 import (
-	"log"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
 
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/auth"
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/controller"
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/middleware"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/migrations"
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/bus"
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/email"
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/gemini"
 	platformgrpc "github.com/giakiet05/uit-ai-assistant/backend/internal/platform/grpc"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/logger"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/requestid"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/uit"
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/ws"
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/repo"
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/route"
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/service"
 	"github.com/gin-gonic/gin"
+	"github.com/nats-io/nats.go"
 	"github.com/redis/go-redis/v9"
 	"go.mongodb.org/mongo-driver/mongo"
 )
@@ -26,6 +35,20 @@ type Repos struct {
 	repo.EmailVerificationRepo
 	repo.ChatSessionRepo
 	repo.ChatMessageRepo
+	repo.AnnouncementRepo
+	repo.ScheduledNotificationRepo
+	repo.ChatAnalyticsRepo
+	repo.AuditLogRepo
+	repo.MessageReportRepo
+	repo.EmailCampaignRepo
+	repo.EmailDeliveryRepo
+	repo.ModerationDecisionRepo
+	repo.UserUsageRepo
+	repo.DataExportRepo
+	repo.UITAnnouncementRepo
+	repo.OutboxRepo
+	repo.Transactor
+	repo.EmailQueue
 }
 
 type Services struct {
@@ -34,6 +57,31 @@ type Services struct {
 	service.NotificationService
 	service.AdminUserService
 	service.ChatService
+	service.DigestService
+	service.AnnouncementService
+	service.PresenceService
+	service.AdminStatsService
+	service.AnalyticsService
+	service.AuditService
+	service.AdminChatService
+	service.ReportService
+	service.MaintenanceService
+	service.UserPurgeService
+	service.EmailCampaignService
+	service.SystemHealthService
+	service.ModerationService
+	service.UsageService
+	service.QuotaService
+	service.DashboardService
+	service.DataExportService
+	service.EmailPreferenceService
+	service.CookieService
+	service.UITService
+	service.ExtensionService
+	service.UITAnnouncementService
+	service.CacheInvalidationService
+	service.OutboxService
+	service.EmailQueueService
 }
 
 type Controllers struct {
@@ -44,36 +92,136 @@ type Controllers struct {
 	controller.AdminUserController
 	controller.ChatController
 	controller.CookieController
+	controller.AnnouncementController
+	controller.PresenceController
+	controller.AdminStatsController
+	controller.AnalyticsController
+	controller.AdminChatController
+	controller.ReportController
+	controller.MaintenanceController
+	controller.EmailCampaignController
+	controller.SystemHealthController
+	controller.ModerationController
+	controller.UsageController
+	controller.QuotaController
+	controller.DataExportController
+	controller.EmailPreferenceController
+	controller.UITController
+	controller.ExtensionController
+	controller.UITAnnouncementController
+	controller.EmailQueueController
 }
 
-func initRepos(client *mongo.Client, db *mongo.Database) *Repos {
+func initRepos(client *mongo.Client, db *mongo.Database, redisClient *redis.Client) *Repos {
+	userRepo := repo.NewUserRepo(db)
+	if ttl := config.Cfg.UserCache.TTLSeconds; ttl > 0 {
+		userRepo = repo.NewCachedUserRepo(userRepo, redisClient, time.Duration(ttl)*time.Second)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	transactor, err := repo.NewTransactor(ctx, client, config.Cfg.Mongo.AllowStandalone)
+	if err != nil {
+		logger.Fatal("MongoDB transactions unavailable, run it as a replica set or set MONGO_ALLOW_STANDALONE=true", "error", err)
+	}
+
 	return &Repos{
-		UserRepo:              repo.NewUserRepo(db),
-		NotificationRepo:      repo.NewNotificationRepo(db),
-		EmailVerificationRepo: repo.NewEmailVerificationRepo(db),
-		ChatSessionRepo:       repo.NewChatSessionRepo(db),
-		ChatMessageRepo:       repo.NewChatMessageRepo(db),
+		UserRepo:                  userRepo,
+		NotificationRepo:          repo.NewNotificationRepo(db),
+		EmailVerificationRepo:     repo.NewEmailVerificationRepo(db),
+		ChatSessionRepo:           repo.NewChatSessionRepo(db),
+		ChatMessageRepo:           repo.NewChatMessageRepo(db),
+		AnnouncementRepo:          repo.NewAnnouncementRepo(db),
+		ScheduledNotificationRepo: repo.NewScheduledNotificationRepo(db),
+		ChatAnalyticsRepo:         repo.NewChatAnalyticsRepo(db),
+		AuditLogRepo:              repo.NewAuditLogRepo(db),
+		MessageReportRepo:         repo.NewMessageReportRepo(db),
+		EmailCampaignRepo:         repo.NewEmailCampaignRepo(db),
+		EmailDeliveryRepo:         repo.NewEmailDeliveryRepo(db),
+		ModerationDecisionRepo:    repo.NewModerationDecisionRepo(db),
+		UserUsageRepo:             repo.NewUserUsageRepo(db),
+		DataExportRepo:            repo.NewDataExportRepo(db),
+		UITAnnouncementRepo:       repo.NewUITAnnouncementRepo(db),
+		OutboxRepo:                repo.NewOutboxRepo(db),
+		Transactor:                transactor,
+		EmailQueue:                repo.NewRedisEmailQueue(redisClient),
 	}
 }
 
-func initServices(repos *Repos, redisClient *redis.Client, emailSender email.Sender, eventBus bus.EventBus, geminiClient *gemini.GeminiClient, agentClient *platformgrpc.AgentClient) *Services {
+func initServices(repos *Repos, mongoClient *mongo.Client, redisClient *redis.Client, emailSender email.Sender, eventBus bus.EventBus, geminiClient *gemini.GeminiClient, agentClient *platformgrpc.AgentClient) *Services {
+	emailQueueService := service.NewEmailQueueService(repos.EmailQueue, emailSender, &config.Cfg.EmailQueue)
+	notificationService := service.NewNotificationService(repos.NotificationRepo, repos.ScheduledNotificationRepo, repos.UserRepo, eventBus, redisClient, emailQueueService, &config.Cfg.Scheduler, &config.Cfg.Retention)
+	auditService := service.NewAuditService(repos.AuditLogRepo)
+	cookieStore := repo.NewRedisCookieStore(redisClient)
+	cookieService := service.NewCookieService(cookieStore, notificationService, &config.Cfg.Cookie)
+	userPurgeService := service.NewUserPurgeService(repos.UserRepo, repos.ChatSessionRepo, repos.ChatMessageRepo, repos.MessageReportRepo, repos.NotificationRepo, repos.ScheduledNotificationRepo, repos.EmailVerificationRepo, repos.UserUsageRepo, repos.DataExportRepo, repos.EmailDeliveryRepo, cookieStore, redisClient, &config.Cfg.Retention)
+	quotaService := service.NewQuotaService(repos.UserRepo, redisClient, auditService, notificationService, &config.Cfg.Quota)
+	dashboardService := service.NewDashboardService(redisClient, eventBus, &config.Cfg.Dashboard)
+	moderationService := service.NewModerationService(repos.ModerationDecisionRepo, geminiClient, &config.Cfg.Gemini)
+	outboxService := service.NewOutboxService(repos.OutboxRepo, eventBus, &config.Cfg.Outbox)
+
 	return &Services{
-		AuthService:         service.NewAuthService(repos.UserRepo, repos.EmailVerificationRepo, emailSender, redisClient),
-		UserService:         service.NewUserService(repos.UserRepo, eventBus, redisClient),
-		NotificationService: service.NewNotificationService(repos.NotificationRepo, repos.UserRepo, eventBus, redisClient),
-		ChatService:         service.NewChatService(repos.ChatSessionRepo, repos.ChatMessageRepo, agentClient),
+		AuthService:              service.NewAuthService(repos.UserRepo, repos.EmailVerificationRepo, emailQueueService, redisClient, eventBus, repos.Transactor),
+		UserService:              service.NewUserService(repos.UserRepo, eventBus, redisClient, cookieService),
+		NotificationService:      notificationService,
+		AdminUserService:         service.NewAdminUserService(repos.UserRepo, eventBus, repos.Transactor, outboxService, userPurgeService, auditService, redisClient, &config.Cfg.UserCache),
+		ChatService:              service.NewChatService(repos.ChatSessionRepo, repos.ChatMessageRepo, agentClient, eventBus, quotaService, dashboardService, moderationService),
+		DigestService:            service.NewDigestService(repos.NotificationRepo, repos.UserRepo, emailQueueService, &config.Cfg.Digest),
+		AnnouncementService:      service.NewAnnouncementService(repos.AnnouncementRepo, repos.UserRepo, notificationService, eventBus),
+		PresenceService:          service.NewPresenceService(repos.UserRepo, redisClient, eventBus),
+		AdminStatsService:        service.NewAdminStatsService(repos.UserRepo, repos.ChatSessionRepo, repos.ChatMessageRepo),
+		AnalyticsService:         service.NewAnalyticsService(repos.ChatAnalyticsRepo),
+		AuditService:             auditService,
+		AdminChatService:         service.NewAdminChatService(repos.ChatSessionRepo, repos.ChatMessageRepo, auditService),
+		ReportService:            service.NewReportService(repos.MessageReportRepo, repos.ChatSessionRepo, repos.ChatMessageRepo),
+		MaintenanceService:       service.NewMaintenanceService(redisClient),
+		UserPurgeService:         userPurgeService,
+		EmailCampaignService:     service.NewEmailCampaignService(repos.EmailCampaignRepo, repos.EmailDeliveryRepo, repos.UserRepo, emailQueueService, &config.Cfg.EmailCampaign),
+		SystemHealthService:      service.NewSystemHealthService(mongoClient, redisClient, agentClient, emailSender, geminiClient),
+		ModerationService:        moderationService,
+		UsageService:             service.NewUsageService(repos.UserUsageRepo, repos.ChatAnalyticsRepo, repos.UserRepo, &config.Cfg.Usage),
+		QuotaService:             quotaService,
+		DashboardService:         dashboardService,
+		DataExportService:        service.NewDataExportService(repos.DataExportRepo, repos.UserRepo, repos.ChatSessionRepo, repos.ChatMessageRepo, repos.NotificationRepo, notificationService, &config.Cfg.DataExport),
+		EmailPreferenceService:   service.NewEmailPreferenceService(repos.UserRepo),
+		CookieService:            cookieService,
+		UITService:               service.NewUITService(cookieService, notificationService, repos.UserRepo, uit.NewDAAClient(&config.Cfg.UIT), uit.NewDRLClient(&config.Cfg.UIT), uit.NewCoursesClient(&config.Cfg.UIT), redisClient, &config.Cfg.UIT),
+		ExtensionService:         service.NewExtensionService(cookieService, redisClient, &config.Cfg.Extension),
+		UITAnnouncementService:   service.NewUITAnnouncementService(repos.UITAnnouncementRepo, repos.UserRepo, notificationService, eventBus, uit.NewAnnouncementClient(&config.Cfg.UIT), redisClient, &config.Cfg.UIT),
+		CacheInvalidationService: service.NewCacheInvalidationService(eventBus, redisClient),
+		OutboxService:            outboxService,
+		EmailQueueService:        emailQueueService,
 	}
 }
 
 func initControllers(services *Services, wsHub *ws.Hub, redisClient *redis.Client) *Controllers {
 	return &Controllers{
-		AuthController:         *controller.NewAuthController(services.AuthService),
-		UserController:         *controller.NewUserController(services.UserService),
-		NotificationController: *controller.NewNotificationController(services.NotificationService),
-		WebSocketController:    *controller.NewWebSocketController(wsHub),
-		AdminUserController:    *controller.NewAdminUserController(services.AdminUserService),
-		ChatController:         *controller.NewChatController(services.ChatService),
-		CookieController:       *controller.NewCookieController(redisClient),
+		AuthController:            *controller.NewAuthController(services.AuthService),
+		UserController:            *controller.NewUserController(services.UserService),
+		NotificationController:    *controller.NewNotificationController(services.NotificationService),
+		WebSocketController:       *controller.NewWebSocketController(wsHub),
+		AdminUserController:       *controller.NewAdminUserController(services.AdminUserService),
+		ChatController:            *controller.NewChatController(services.ChatService),
+		CookieController:          *controller.NewCookieController(services.CookieService),
+		AnnouncementController:    *controller.NewAnnouncementController(services.AnnouncementService),
+		PresenceController:        *controller.NewPresenceController(services.PresenceService),
+		AdminStatsController:      *controller.NewAdminStatsController(services.AdminStatsService),
+		AnalyticsController:       *controller.NewAnalyticsController(services.AnalyticsService),
+		AdminChatController:       *controller.NewAdminChatController(services.AdminChatService),
+		ReportController:          *controller.NewReportController(services.ReportService),
+		MaintenanceController:     *controller.NewMaintenanceController(services.MaintenanceService),
+		EmailCampaignController:   *controller.NewEmailCampaignController(services.EmailCampaignService),
+		SystemHealthController:    *controller.NewSystemHealthController(services.SystemHealthService),
+		ModerationController:      *controller.NewModerationController(services.ModerationService),
+		UsageController:           *controller.NewUsageController(services.UsageService),
+		QuotaController:           *controller.NewQuotaController(services.QuotaService),
+		DataExportController:      *controller.NewDataExportController(services.DataExportService),
+		EmailPreferenceController: *controller.NewEmailPreferenceController(services.EmailPreferenceService),
+		UITController:             *controller.NewUITController(services.UITService),
+		ExtensionController:       *controller.NewExtensionController(services.ExtensionService),
+		UITAnnouncementController: *controller.NewUITAnnouncementController(services.UITAnnouncementService),
+		EmailQueueController:      *controller.NewEmailQueueController(services.EmailQueueService),
 	}
 }
 
@@ -81,46 +229,175 @@ func initRoutes(controllers *Controllers, r *gin.Engine) {
 	r.GET("/ping", func(c *gin.Context) {
 		c.JSON(200, gin.H{"message": "pong"})
 	})
+	r.GET("/healthz", controllers.SystemHealthController.Liveness)
+	r.GET("/readyz", controllers.SystemHealthController.Readiness)
+
+	shared := sharedRoutes(controllers)
+
+	// v1 is the version the web app and the browser extension use. Its paginated lists keep their original shape.
+	registerAPIVersion(r.Group("/api/v1", middleware.LegacyListShape()), "v1", shared, nil)
+
+	// v2 serves the shared routes too. When an endpoint changes incompatibly, register the v2 controller of its
+	// feature here and attach middleware.Deprecated to the v1 route, so clients still on v1 keep working until
+	// the sunset date.
+	registerAPIVersion(r.Group("/api/v2"), "v2", shared, map[string]routeRegistrar{})
+}
+
+// routeRegistrar registers the routes of one feature on the group of an API version
+type routeRegistrar func(api *gin.RouterGroup)
+
+// namedRoutes is a feature's routes, named so a version can replace them with its own
+type namedRoutes struct {
+	feature  string
+	register routeRegistrar
+}
 
-	api := r.Group("/api/v1")
+// sharedRoutes lists the routes every API version serves unless it overrides their feature
+func sharedRoutes(controllers *Controllers) []namedRoutes {
+	return []namedRoutes{
+		{"auth", func(api *gin.RouterGroup) {
+			route.RegisterAuthRoutes(api, &controllers.AuthController, &controllers.UserController)
+		}},
+		{"user", func(api *gin.RouterGroup) {
+			route.RegisterUserRoutes(api, &controllers.UserController, &controllers.PresenceController)
+		}},
+		{"notification", func(api *gin.RouterGroup) { route.RegisterNotificationRoutes(api, &controllers.NotificationController) }},
+		{"websocket", func(api *gin.RouterGroup) { route.RegisterWebSocketRoutes(api, &controllers.WebSocketController) }},
+		{"admin_user", func(api *gin.RouterGroup) { route.RegisterAdminUserRoutes(api, &controllers.AdminUserController) }},
+		{"chat", func(api *gin.RouterGroup) { route.RegisterChatRoutes(api, &controllers.ChatController) }},
+		{"cookie", func(api *gin.RouterGroup) { route.RegisterCookieRoutes(api, &controllers.CookieController) }},
+		{"announcement", func(api *gin.RouterGroup) { route.RegisterAnnouncementRoutes(api, &controllers.AnnouncementController) }},
+		{"admin_stats", func(api *gin.RouterGroup) { route.RegisterAdminStatsRoutes(api, &controllers.AdminStatsController) }},
+		{"analytics", func(api *gin.RouterGroup) { route.RegisterAnalyticsRoutes(api, &controllers.AnalyticsController) }},
+		{"admin_chat", func(api *gin.RouterGroup) { route.RegisterAdminChatRoutes(api, &controllers.AdminChatController) }},
+		{"report", func(api *gin.RouterGroup) { route.RegisterReportRoutes(api, &controllers.ReportController) }},
+		{"maintenance", func(api *gin.RouterGroup) { route.RegisterMaintenanceRoutes(api, &controllers.MaintenanceController) }},
+		{"email_campaign", func(api *gin.RouterGroup) {
+			route.RegisterEmailCampaignRoutes(api, &controllers.EmailCampaignController)
+		}},
+		{"email_queue", func(api *gin.RouterGroup) { route.RegisterEmailQueueRoutes(api, &controllers.EmailQueueController) }},
+		{"system_health", func(api *gin.RouterGroup) { route.RegisterSystemHealthRoutes(api, &controllers.SystemHealthController) }},
+		{"moderation", func(api *gin.RouterGroup) { route.RegisterModerationRoutes(api, &controllers.ModerationController) }},
+		{"usage", func(api *gin.RouterGroup) { route.RegisterUsageRoutes(api, &controllers.UsageController) }},
+		{"quota", func(api *gin.RouterGroup) { route.RegisterQuotaRoutes(api, &controllers.QuotaController) }},
+		{"data_export", func(api *gin.RouterGroup) { route.RegisterDataExportRoutes(api, &controllers.DataExportController) }},
+		{"email_preference", func(api *gin.RouterGroup) {
+			route.RegisterEmailPreferenceRoutes(api, &controllers.EmailPreferenceController)
+		}},
+		{"uit", func(api *gin.RouterGroup) { route.RegisterUITRoutes(api, &controllers.UITController) }},
+		{"uit_announcement", func(api *gin.RouterGroup) {
+			route.RegisterUITAnnouncementRoutes(api, &controllers.UITAnnouncementController)
+		}},
+		{"extension", func(api *gin.RouterGroup) { route.RegisterExtensionRoutes(api, &controllers.ExtensionController) }},
+	}
+}
+
+// registerAPIVersion registers the shared routes on api, using the version's own registrar for the
+// features it overrides
+func registerAPIVersion(api *gin.RouterGroup, version string, shared []namedRoutes, overrides map[string]routeRegistrar) {
 	api.GET("/", func(c *gin.Context) {
-		c.JSON(200, gin.H{"message": "Welcome to LKForum API!"})
+		c.JSON(200, gin.H{"message": "Welcome to LKForum API!", "version": version})
 	})
 
-	route.RegisterAuthRoutes(api, &controllers.AuthController, &controllers.UserController)
-	route.RegisterUserRoutes(api, &controllers.UserController)
-	route.RegisterNotificationRoutes(api, &controllers.NotificationController)
-	route.RegisterWebSocketRoutes(api, &controllers.WebSocketController)
-	route.RegisterAdminUserRoutes(api, &controllers.AdminUserController)
-	route.RegisterChatRoutes(api, &controllers.ChatController)
-	route.RegisterCookieRoutes(api, &controllers.CookieController)
+	for _, routes := range shared {
+		group := api.Group("", middleware.Timeout(routeTimeout(routes.feature)))
+		if override, ok := overrides[routes.feature]; ok {
+			override(group)
+			continue
+		}
+		routes.register(group)
+	}
+}
+
+// routeTimeout is how long the handlers of a feature may run, 0 for no limit
+func routeTimeout(feature string) time.Duration {
+	cfg := config.Cfg.RouteTimeout
+	switch feature {
+	case "websocket":
+		// Connections stay open, their heartbeats detect dead peers
+		return 0
+	case "auth", "user":
+		return time.Duration(cfg.ShortSeconds) * time.Second
+	case "chat", "admin_user", "data_export":
+		// Agent calls, CSV exports and archive downloads
+		return time.Duration(cfg.LongSeconds) * time.Second
+	default:
+		return time.Duration(cfg.DefaultSeconds) * time.Second
+	}
+}
+
+// App holds the initialized router and the components that must be stopped on shutdown
+type App struct {
+	Router      *gin.Engine
+	wsHub       *ws.Hub
+	workers     *service.Workers
+	agentClient *platformgrpc.AgentClient
+	natsConn    *nats.Conn // Set only with the NATS event bus
+	mongoClient *mongo.Client
+	redisClient *redis.Client
+}
+
+// StopWebSockets tells connected WebSocket clients to reconnect to another instance and drains them. Upgraded
+// connections are not tracked by the HTTP server, so they must be stopped separately.
+func (a *App) StopWebSockets(ctx context.Context) error {
+	return a.wsHub.Stop(ctx)
+}
+
+// StopWorkers stops the background jobs of the services and waits for the work in progress to finish,
+// so none of it runs against closed clients
+func (a *App) StopWorkers(ctx context.Context) error {
+	return a.workers.Stop(ctx)
 }
 
-func Init() (*gin.Engine, error) {
+// Close releases the clients of outside services, once no request can use them anymore: the agent first since
+// nothing else depends on it, then NATS after flushing the events published meanwhile, then Mongo, then Redis
+// which the event bus and token checks rely on until the end
+func (a *App) Close(ctx context.Context) error {
+	var errs []error
+	if err := a.agentClient.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("agent gRPC client: %w", err))
+	}
+	if err := closeNATS(a.natsConn); err != nil {
+		errs = append(errs, fmt.Errorf("nats connection: %w", err))
+	}
+	if err := a.mongoClient.Disconnect(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("mongo client: %w", err))
+	}
+	if err := a.redisClient.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("redis client: %w", err))
+	}
+	return errors.Join(errs...)
+}
+
+func Init() (*App, error) {
 	config.LoadConfig()
 	auth.InitGoogleOAuthConfig()
 
 	redisClient := config.NewRedisClient()
 
 	if err := InitializeTokenService(redisClient); err != nil {
-		log.Printf("Warning: Token invalidation service not available: %v\n", err)
+		slog.Warn("Token invalidation service not available", "error", err)
 	}
 
 	client := config.NewMongoClient()
 	db := client.Database(config.Cfg.DBName)
-	router := gin.Default()
+	if config.Cfg.Migrations.RunOnStartup {
+		runMigrations(db)
+	}
+
+	gin.SetMode(config.Cfg.GinMode)
+	router := gin.New()
+	// Only the configured proxies may set the client IP through X-Forwarded-For, rate limits count by it
+	if err := router.SetTrustedProxies(config.Cfg.TrustedProxies); err != nil {
+		logger.Fatal("Invalid TRUSTED_PROXIES", "error", err)
+	}
+	router.Use(middleware.RequestID(), middleware.RequestLogger(&config.Cfg.Log), middleware.Recovery())
 
 	router.Use(func(c *gin.Context) {
 		origin := c.Request.Header.Get("Origin")
 
-		// Allow FrontendURL and ExtensionOrigin
-		allowedOrigins := []string{config.Cfg.FrontendURL}
-		if config.Cfg.ExtensionOrigin != "" {
-			allowedOrigins = append(allowedOrigins, config.Cfg.ExtensionOrigin)
-		}
-
-		// Check if origin is allowed
-		for _, allowedOrigin := range allowedOrigins {
+		// Check if origin is allowed, FrontendURL and ExtensionOrigin always are
+		for _, allowedOrigin := range config.Cfg.CORS.AllowedOrigins {
 			if origin == allowedOrigin {
 				c.Writer.Header().Set("Access-Control-Allow-Origin", origin)
 				break
@@ -128,7 +405,9 @@ func Init() (*gin.Engine, error) {
 		}
 
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, "+requestid.Header+", "+middleware.IdempotencyKeyHeader)
+		// Clients read the deprecation headers to warn before an endpoint they use is removed
+		c.Writer.Header().Set("Access-Control-Expose-Headers", requestid.Header+", Deprecation, Sunset, Link, Retry-After, "+middleware.IdempotentReplayedHeader)
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -136,36 +415,129 @@ func Init() (*gin.Engine, error) {
 		}
 		c.Next()
 	})
+	router.Use(middleware.Metrics())
+	router.Use(middleware.BodyLimit(int64(config.Cfg.BodyLimit.JSONKB)<<10, int64(config.Cfg.BodyLimit.MultipartMB)<<20))
 
-	eventBus := bus.NewEventBus()
-	wsHub := ws.NewHub(eventBus)
-	emailSender := email.NewSMTPSender()
+	natsConn := newNATSConn()
+	eventBus := newEventBus(redisClient, natsConn)
+	emailSender := email.NewSender()
 
 	// Initialize Gemini client for content moderation
 	geminiClient, err := gemini.NewGeminiClient(&config.Cfg.Gemini)
 	if err != nil {
-		log.Printf("Warning: Gemini client initialization failed: %v. Content moderation will be disabled.", err)
+		slog.Warn("Gemini client initialization failed, content moderation will be disabled", "error", err)
 	}
 
 	// Initialize Agent gRPC client
 	agentClient, err := platformgrpc.NewAgentClient(config.Cfg.AgentGRPCAddr)
 	if err != nil {
-		log.Fatalf("Failed to connect to Agent gRPC server: %v", err)
+		logger.Fatal("Failed to connect to Agent gRPC server", "error", err)
 	}
-	log.Printf("Connected to Agent gRPC server at %s", config.Cfg.AgentGRPCAddr)
+	slog.Info("Connected to Agent gRPC server", "addr", config.Cfg.AgentGRPCAddr)
 
-	repos := initRepos(client, db)
-	services := initServices(repos, redisClient, emailSender, eventBus, geminiClient, agentClient)
+	repos := initRepos(client, db, redisClient)
+	services := initServices(repos, client, redisClient, emailSender, eventBus, geminiClient, agentClient)
+	wsHub := ws.NewHub(eventBus, &wsIncomingHandler{
+		notifications: services.NotificationService,
+		chat:          services.ChatService,
+		dashboard:     services.DashboardService,
+	}, services.PresenceService, &config.Cfg.WebSocket)
 	controllers := initControllers(services, wsHub, redisClient)
 
 	// Inject userRepo into middleware for settings caching
 	middleware.SetUserRepo(repos.UserRepo)
+	middleware.SetIdempotencyStore(redisClient, &config.Cfg.Idempotency)
+	middleware.SetRateLimitStore(redisClient, &config.Cfg.RateLimit)
+
+	// Must be registered before the routes it guards
+	router.Use(middleware.Maintenance(services.MaintenanceService))
 
 	initRoutes(controllers, router)
 
 	// Start background services
 	go wsHub.Start()
-	services.NotificationService.Start()
+	workers := service.NewWorkers()
+	services.NotificationService.Start(workers)
+	services.DigestService.Start(workers)
+	services.UserPurgeService.Start(workers)
+	services.EmailCampaignService.Start(workers)
+	services.UsageService.Start(workers)
+	services.DashboardService.Start(workers)
+	services.DataExportService.Start(workers)
+	services.UITService.Start(workers)
+	services.UITAnnouncementService.Start(workers)
+	services.CookieService.Start(workers)
+	services.CacheInvalidationService.Start(workers)
+	services.OutboxService.Start(workers)
+	services.EmailQueueService.Start(workers)
+
+	return &App{Router: router, wsHub: wsHub, workers: workers, agentClient: agentClient, natsConn: natsConn, mongoClient: client, redisClient: redisClient}, nil
+}
 
-	return router, nil
+// newNATSConn connects to NATS when it backs the event bus, otherwise returns nil
+func newNATSConn() *nats.Conn {
+	if config.Cfg.EventBus.Backend != "nats" {
+		return nil
+	}
+	return config.NewNATSConn()
+}
+
+// closeNATS flushes pending publishes and closes the NATS connection, if there is one
+func closeNATS(conn *nats.Conn) error {
+	if conn == nil {
+		return nil
+	}
+	return conn.Drain()
+}
+
+// newEventBus creates the event bus selected by config, defaulting to the in-memory bus.
+// natsConn is only used, and only set, with the NATS backend.
+func newEventBus(redisClient *redis.Client, natsConn *nats.Conn) bus.EventBus {
+	switch config.Cfg.EventBus.Backend {
+	case "redis":
+		slog.Info("Using Redis event bus")
+		return bus.NewRedisEventBus(redisClient, config.Cfg.EventBus.ChannelPrefix)
+	case "redis_streams":
+		cfg := &config.Cfg.EventBus
+		slog.Info("Using Redis Streams event bus", "group", cfg.ConsumerGroup, "consumer", cfg.ConsumerName)
+		return bus.NewRedisStreamsEventBus(redisClient, bus.StreamsConfig{
+			Prefix:    cfg.ChannelPrefix,
+			Group:     cfg.ConsumerGroup,
+			Consumer:  cfg.ConsumerName,
+			MaxLen:    int64(cfg.StreamMaxLen),
+			ClaimIdle: time.Duration(cfg.ClaimIdleSeconds) * time.Second,
+		})
+	case "nats":
+		cfg := &config.Cfg.EventBus
+		slog.Info("Using NATS event bus", "stream", cfg.NATSStream, "group", cfg.ConsumerGroup)
+		eventBus, err := bus.NewNATSEventBus(natsConn, bus.NATSConfig{
+			Stream:        cfg.NATSStream,
+			SubjectPrefix: cfg.NATSSubjectPrefix,
+			Group:         cfg.ConsumerGroup,
+			MaxLen:        int64(cfg.StreamMaxLen),
+			AckWait:       time.Duration(cfg.ClaimIdleSeconds) * time.Second,
+		})
+		if err != nil {
+			logger.Fatal("Failed to create NATS event bus", "error", err)
+		}
+		return eventBus
+	case "memory", "":
+		return bus.NewEventBus()
+	default:
+		slog.Warn("Unknown EVENT_BUS_BACKEND, using in-memory event bus", "backend", config.Cfg.EventBus.Backend)
+		return bus.NewEventBus()
+	}
+}
+
+// runMigrations applies pending schema migrations before anything reads the data they change
+func runMigrations(db *mongo.Database) {
+	timeout := time.Duration(config.Cfg.Migrations.TimeoutSeconds) * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	ran, err := migrations.Run(ctx, db, timeout)
+	if err != nil {
+		logger.Fatal("Schema migration failed", "error", err)
+	}
+	slog.Info("Schema migrations up to date", "applied", len(ran))
 }
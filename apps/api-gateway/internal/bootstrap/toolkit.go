@@ -0,0 +1,88 @@
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/bus"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/email"
+	platformgrpc "github.com/giakiet05/uit-ai-assistant/backend/internal/platform/grpc"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/repo"
+	"github.com/nats-io/nats.go"
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Toolkit gives command-line tools the repos and services the API runs on, configured the same way,
+// without serving HTTP or starting background jobs.
+type Toolkit struct {
+	Repos       *Repos
+	Services    *Services
+	EmailSender email.Sender
+	EventBus    bus.EventBus // Reaches running API instances only when EVENT_BUS_BACKEND is shared
+	DB          *mongo.Database
+	CookieStore repo.CookieStore
+
+	agentClient *platformgrpc.AgentClient
+	natsConn    *nats.Conn
+	mongoClient *mongo.Client
+	redisClient *redis.Client
+}
+
+// InitToolkit connects to Mongo and Redis and builds the repos and services. Content moderation is disabled,
+// and the agent client is created but only connects if a service calls the agent.
+func InitToolkit() (*Toolkit, error) {
+	config.LoadConfig()
+
+	redisClient := config.NewRedisClient()
+	if err := InitializeTokenService(redisClient); err != nil {
+		return nil, err
+	}
+
+	client := config.NewMongoClient()
+	db := client.Database(config.Cfg.DBName)
+
+	agentClient, err := platformgrpc.NewAgentClient(config.Cfg.AgentGRPCAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	natsConn := newNATSConn()
+	eventBus := newEventBus(redisClient, natsConn)
+	emailSender := email.NewSender()
+	repos := initRepos(client, db, redisClient)
+	services := initServices(repos, client, redisClient, emailSender, eventBus, nil, agentClient)
+
+	return &Toolkit{
+		Repos:       repos,
+		Services:    services,
+		EmailSender: emailSender,
+		EventBus:    eventBus,
+		DB:          db,
+		CookieStore: repo.NewRedisCookieStore(redisClient),
+		agentClient: agentClient,
+		natsConn:    natsConn,
+		mongoClient: client,
+		redisClient: redisClient,
+	}, nil
+}
+
+// Close releases the clients of outside services
+func (t *Toolkit) Close(ctx context.Context) error {
+	var errs []error
+	if err := t.agentClient.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("agent gRPC client: %w", err))
+	}
+	if err := closeNATS(t.natsConn); err != nil {
+		errs = append(errs, fmt.Errorf("nats connection: %w", err))
+	}
+	if err := t.mongoClient.Disconnect(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("mongo client: %w", err))
+	}
+	if err := t.redisClient.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("redis client: %w", err))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,41 @@
+package bootstrap
+
+import (
+	"context"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/service"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/util"
+)
+
+// wsIncomingHandler adapts the services to the actions WebSocket clients can request
+type wsIncomingHandler struct {
+	notifications service.NotificationService
+	chat          service.ChatService
+	dashboard     service.DashboardService
+}
+
+func (h *wsIncomingHandler) MarkNotificationRead(userID, notificationID string) error {
+	return h.notifications.MarkAsRead(context.Background(), userID, notificationID)
+}
+
+func (h *wsIncomingHandler) MarkAllNotificationsRead(userID string) (int64, error) {
+	return h.notifications.MarkAllAsRead(context.Background(), userID)
+}
+
+func (h *wsIncomingHandler) NotificationsSince(userID string, since time.Time, limit int) ([]dto.NotificationResponse, bool, error) {
+	return h.notifications.GetNotificationsSince(context.Background(), userID, since, limit)
+}
+
+func (h *wsIncomingHandler) CanAccessSession(userID, sessionID string) bool {
+	ctx, cancel := util.NewDefaultDBContext()
+	defer cancel()
+
+	_, err := h.chat.GetSessionByID(ctx, userID, sessionID)
+	return err == nil
+}
+
+func (h *wsIncomingHandler) DashboardSnapshot() (*dto.DashboardMetricsPayload, error) {
+	return h.dashboard.Snapshot()
+}
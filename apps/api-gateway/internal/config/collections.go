@@ -7,9 +7,39 @@ const (
 	EmailVerificationColName = "email_verifications"
 
 	// Chat collections
-	ChatSessionColName = "chat_sessions"
-	ChatMessageColName = "chat_messages"
+	ChatSessionColName   = "chat_sessions"
+	ChatMessageColName   = "chat_messages"
+	MessageReportColName = "message_reports"
 
-	// Notification collection
-	NotificationColName = "notifications"
+	// Notification collections
+	NotificationColName          = "notifications"
+	ScheduledNotificationColName = "scheduled_notifications"
+
+	// Announcement collections
+	AnnouncementColName    = "announcements"
+	UITAnnouncementColName = "uit_announcements" // Crawled from official UIT pages
+
+	// Email campaign collections
+	EmailCampaignColName = "email_campaigns"
+	EmailDeliveryColName = "email_deliveries"
+
+	// Usage collection
+	UserUsageColName = "user_usage"
+
+	// Moderation collection
+	ModerationDecisionColName = "moderation_decisions"
+
+	// Data export collection; archives are stored in the GridFS bucket
+	DataExportColName       = "data_exports"
+	DataExportArchiveBucket = "data_export_archives"
+
+	// Audit collection
+	AuditLogColName = "audit_logs"
+
+	// Domain events waiting to be published on the event bus
+	OutboxColName = "outbox_events"
+
+	// Applied schema migrations, and the lock that keeps two instances from running them at once
+	MigrationColName     = "migrations"
+	MigrationLockColName = "migration_lock"
 )
@@ -1,162 +1,442 @@
 package config
 
 import (
-	"log"
+	"fmt"
+	"log/slog"
 	"os"
-	"strconv"
+	"reflect"
+	"slices"
+	"strings"
 
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/logger"
 	"github.com/joho/godotenv"
 )
 
-// AppConfig holds the application's configuration
+// AppConfig holds the application's configuration. Each setting is read from the environment variable in its
+// env tag (the first one set if several are listed). Unset settings take the default of the running profile:
+// the staging or prod tag when present, otherwise the default tag. Settings marked required for the profile
+// must be set explicitly, oneof limits a setting to the listed values (matched case-insensitively, stored as
+// listed), and min is the smallest value an integer setting accepts.
 type AppConfig struct {
-	Port                 string
-	MongoURI             string
-	DBName               string
-	JWTSecret            string
-	JWTIssuer            string
-	JWTAudience          string
-	TokenTTL             int
-	RefreshTokenTTL      int
-	FrontendURL          string
-	ExtensionOrigin      string
-	OTPExpirationMinutes int
-	AgentGRPCAddr        string
+	Profile              string   `env:"APP_ENV" default:"dev" oneof:"dev staging prod"`
+	GinMode              string   `env:"GIN_MODE" default:"debug" staging:"release" prod:"release" oneof:"debug release test"`
+	Port                 string   `env:"PORT" default:"8080"`
+	MetricsPort          string   `env:"METRICS_PORT" default:"9090"`                    // Internal port serving /metrics, not to be published; empty = metrics not served
+	TrustedProxies       []string `env:"TRUSTED_PROXIES"`                                // IPs or CIDRs of the reverse proxies whose X-Forwarded-For is believed for the client IP, empty = none
+	ShutdownTimeout      int      `env:"SHUTDOWN_TIMEOUT_SECONDS" default:"630" min:"0"` // Seconds in-flight requests get to finish on SIGTERM; agent calls run up to 10 minutes
+	MongoURI             string   `env:"MONGO_URI" default:"mongodb://localhost:27017" required:"prod"`
+	DBName               string   `env:"DB_NAME" default:"uit-ai-assistant"`
+	JWTSecret            string   `env:"JWT_SECRET" default:"your-secret-key" required:"staging,prod"`
+	JWTIssuer            string   `env:"JWT_ISSUER" default:"uit-ai-assistant"`
+	JWTAudience          string   `env:"JWT_AUDIENCE" default:"uit-ai-assistant-users"`
+	TokenTTL             int      `env:"TOKEN_TTL_MINUTES" default:"60" min:"1"`
+	RefreshTokenTTL      int      `env:"REFRESH_TOKEN_TTL_HOURS" default:"72" min:"1"`
+	FrontendURL          string   `env:"FRONTEND_URL" default:"http://localhost:5173" required:"prod"`
+	APIBaseURL           string   `env:"API_BASE_URL" required:"prod"` // Public base URL of the API, used for links in emails; default http://localhost:PORT/api/v1
+	ExtensionOrigin      string   `env:"EXTENSION_ORIGIN"`             // Chrome extension origin
+	OTPExpirationMinutes int      `env:"OTP_EXPIRATION_MINUTES" default:"15" min:"1"`
+	AgentGRPCAddr        string   `env:"AGENT_GRPC_ADDR" default:"localhost:50051" required:"prod"`
+	AgentModels          []string `env:"AGENT_MODELS" default:"gpt-5-nano,gpt-5-mini"` // Models users may pick as their default, the first one is the agent's default
+	Log                  LogConfig
+	CORS                 CORSConfig
+	BodyLimit            BodyLimitConfig
+	RouteTimeout         RouteTimeoutConfig
+	Idempotency          IdempotencyConfig
+	RateLimit            RateLimitConfig
+	Email                EmailConfig
 	SMTP                 SMTPConfig
+	EmailQueue           EmailQueueConfig
+	Mongo                MongoConfig
 	Redis                RedisConfig
+	UserCache            UserCacheConfig
+	EventBus             EventBusConfig
+	Outbox               OutboxConfig
+	Migrations           MigrationsConfig
+	WebSocket            WebSocketConfig
 	Google               GoogleConfig
 	Cloudinary           CloudinaryConfig
 	Gemini               GeminiConfig
+	Citation             CitationConfig
+	Digest               DigestConfig
+	Scheduler            SchedulerConfig
+	EmailCampaign        EmailCampaignConfig
+	Retention            RetentionConfig
+	Usage                UsageConfig
+	Quota                QuotaConfig
+	Dashboard            DashboardConfig
+	DataExport           DataExportConfig
+	Avatar               AvatarConfig
+	UIT                  UITConfig
+	Cookie               CookieConfig
+	Extension            ExtensionConfig
+}
+
+// LogConfig holds the structured logger settings
+type LogConfig struct {
+	Level  string `env:"LOG_LEVEL" default:"debug" staging:"info" prod:"info" oneof:"debug info warn warning error"`
+	Format string `env:"LOG_FORMAT" default:"text" staging:"json" prod:"json" oneof:"text json"` // "text" (human readable) | "json" (for log collectors)
+
+	// Add JSON request and response bodies to the access log, with passwords, tokens, OTPs and cookies redacted
+	Bodies       bool `env:"LOG_BODIES" default:"true" staging:"false" prod:"false"`
+	BodyMaxBytes int  `env:"LOG_BODY_MAX_BYTES" default:"4096"` // Larger bodies are left out
+}
+
+// CORSConfig holds the origins browsers may call the API from
+type CORSConfig struct {
+	// Allowed besides FrontendURL and ExtensionOrigin, which are always allowed. In dev the Vite server is
+	// also allowed when it is opened by IP instead of localhost.
+	AllowedOrigins []string `env:"CORS_ALLOWED_ORIGINS" default:"http://127.0.0.1:5173" staging:"" prod:""`
+}
+
+// BodyLimitConfig caps the size of request bodies. Routes accepting uploads may lower the cap for themselves.
+type BodyLimitConfig struct {
+	JSONKB      int `env:"BODY_LIMIT_JSON_KB" default:"1024"`    // JSON and any other non-multipart body
+	MultipartMB int `env:"BODY_LIMIT_MULTIPART_MB" default:"10"` // multipart/form-data uploads
+}
+
+// RouteTimeoutConfig holds how long handlers may run, by kind of route. When it runs out, calls made on
+// the request's context are canceled and the client gets 504.
+type RouteTimeoutConfig struct {
+	ShortSeconds   int `env:"ROUTE_TIMEOUT_SHORT_SECONDS" default:"10" min:"1"`   // Auth and user profile routes
+	DefaultSeconds int `env:"ROUTE_TIMEOUT_DEFAULT_SECONDS" default:"30" min:"1"` // Routes without a kind of their own
+	LongSeconds    int `env:"ROUTE_TIMEOUT_LONG_SECONDS" default:"600" min:"1"`   // Chat, whose agent calls run up to 10 minutes, and exports
+}
+
+// IdempotencyConfig holds how long responses to requests with an Idempotency-Key are kept for replay
+type IdempotencyConfig struct {
+	WindowSeconds int `env:"IDEMPOTENCY_WINDOW_SECONDS" default:"86400" min:"1"` // How long a successful response is replayed for its key
+	LockSeconds   int `env:"IDEMPOTENCY_LOCK_SECONDS" default:"600" min:"1"`     // How long a key stays claimed by a request still running
+	MaxResponseKB int `env:"IDEMPOTENCY_MAX_RESPONSE_KB" default:"256"`          // Larger responses are not kept, their key is released instead
+}
+
+// RateLimitConfig holds the token buckets of rate-limited routes: a caller may send Burst requests at once,
+// then PerMinute requests a minute
+type RateLimitConfig struct {
+	Enabled               bool `env:"RATE_LIMIT_ENABLED" default:"true"`
+	AuthPerMinute         int  `env:"RATE_LIMIT_AUTH_PER_MINUTE" default:"10"` // Each sign-up step sending or checking email codes, and each Google sign-in step, per route and IP
+	AuthBurst             int  `env:"RATE_LIMIT_AUTH_BURST" default:"10"`
+	LoginPerMinute        int  `env:"RATE_LIMIT_LOGIN_PER_MINUTE" default:"30"` // Password sign-ins per IP, high enough for students behind one campus NAT
+	LoginBurst            int  `env:"RATE_LIMIT_LOGIN_BURST" default:"30"`
+	LoginAccountPerMinute int  `env:"RATE_LIMIT_LOGIN_ACCOUNT_PER_MINUTE" default:"5"` // Password sign-ins per account from any IP, against password guessing
+	LoginAccountBurst     int  `env:"RATE_LIMIT_LOGIN_ACCOUNT_BURST" default:"5"`
+	LookupPerMinute       int  `env:"RATE_LIMIT_LOOKUP_PER_MINUTE" default:"60"` // Public availability checks such as usernames, per IP
+	LookupBurst           int  `env:"RATE_LIMIT_LOOKUP_BURST" default:"20"`
+}
+
+// EmailConfig selects the email provider. Emails are only logged when the selected provider is not configured.
+type EmailConfig struct {
+	Provider   string `env:"EMAIL_PROVIDER" default:"smtp" oneof:"smtp sendgrid ses"`
+	From       string `env:"EMAIL_FROM"`                                                    // Sender address for SendGrid and SES, must be verified with the provider
+	SenderName string `env:"EMAIL_SENDER_NAME,SMTP_SENDER_NAME" default:"UIT AI Assistant"` // Display name of the sender, also shown in email templates
+	SendGrid   SendGridConfig
+	SES        SESConfig
+}
+
+// SendGridConfig holds the SendGrid API credentials
+type SendGridConfig struct {
+	APIKey string `env:"SENDGRID_API_KEY"`
+}
+
+// SESConfig holds the AWS SES credentials
+type SESConfig struct {
+	Region          string `env:"AWS_REGION" default:"ap-southeast-1"`
+	AccessKeyID     string `env:"AWS_ACCESS_KEY_ID"`
+	SecretAccessKey string `env:"AWS_SECRET_ACCESS_KEY"`
 }
 
 // SMTPConfig holds the email server configuration
 type SMTPConfig struct {
-	Host       string
-	Port       int
-	User       string
-	Pass       string
-	SenderName string
+	Host string `env:"SMTP_HOST" default:"smtp.example.com"`
+	Port int    `env:"SMTP_PORT" default:"587"`
+	User string `env:"SMTP_USER"`
+	Pass string `env:"SMTP_PASS"`
+}
+
+// EmailQueueConfig holds the settings for sending queued emails
+type EmailQueueConfig struct {
+	WorkerIntervalSeconds int `env:"EMAIL_QUEUE_WORKER_INTERVAL_SECONDS" default:"5" min:"0"` // How often due emails are sent, 0 disables the worker
+	BatchSize             int `env:"EMAIL_QUEUE_BATCH_SIZE" default:"50" min:"1"`             // Emails sent per pass at most
+	MaxAttempts           int `env:"EMAIL_QUEUE_MAX_ATTEMPTS" default:"6" min:"1"`            // Failed sends are retried until this many attempts, then moved to the dead-letter list
+	BaseBackoffSeconds    int `env:"EMAIL_QUEUE_BASE_BACKOFF_SECONDS" default:"30"`           // Wait before the first retry, doubled after each failed attempt
+	MaxBackoffMinutes     int `env:"EMAIL_QUEUE_MAX_BACKOFF_MINUTES" default:"60"`            // Longest wait between two attempts
+	LeaseSeconds          int `env:"EMAIL_QUEUE_LEASE_SECONDS" default:"120" min:"1"`         // An email claimed this long ago but neither sent nor rescheduled is tried again
+	DeadLetterMax         int `env:"EMAIL_QUEUE_DEAD_LETTER_MAX" default:"1000"`              // Failed emails kept for inspection, older ones are dropped
+}
+
+// MongoConfig tunes the MongoDB client. Options set in MONGO_URI take precedence over these settings.
+type MongoConfig struct {
+	MaxPoolSize                   int    `env:"MONGO_MAX_POOL_SIZE" default:"100"`                   // Connections per server the client may open
+	MinPoolSize                   int    `env:"MONGO_MIN_POOL_SIZE" default:"0" prod:"10"`           // Connections per server kept open while idle
+	ConnectTimeoutSeconds         int    `env:"MONGO_CONNECT_TIMEOUT_SECONDS" default:"30"`          // Opening a connection
+	SocketTimeoutSeconds          int    `env:"MONGO_SOCKET_TIMEOUT_SECONDS" default:"0"`            // Reading or writing on a connection, 0 leaves it to the operation's context
+	ServerSelectionTimeoutSeconds int    `env:"MONGO_SERVER_SELECTION_TIMEOUT_SECONDS" default:"30"` // Finding a server for an operation, e.g. during an election
+	ReadPreference                string `env:"MONGO_READ_PREFERENCE" default:"primary" oneof:"primary primaryPreferred secondary secondaryPreferred nearest"`
+	// Startup fails on a standalone server, whose writes meant to be atomic (e.g. an outbox event and its domain
+	// write) could be left half applied. Set only for throwaway local databases.
+	AllowStandalone bool `env:"MONGO_ALLOW_STANDALONE" default:"false"`
 }
 
 // RedisConfig holds the Redis server configuration
 type RedisConfig struct {
-	Addr     string
-	Password string
-	DB       int
+	Addr     string `env:"REDIS_ADDR" default:"localhost:6379" required:"prod"`
+	Password string `env:"REDIS_PASSWORD"`
+	DB       int    `env:"REDIS_DB" default:"0"`
+}
+
+// UserCacheConfig holds the settings for caching user documents in Redis
+type UserCacheConfig struct {
+	TTLSeconds           int `env:"USER_CACHE_TTL_SECONDS" default:"300"`            // How long a user is served from cache, 0 disables the cache
+	AdminCountTTLSeconds int `env:"ADMIN_USER_COUNT_CACHE_TTL_SECONDS" default:"60"` // How long admin user list totals are reused, 0 disables caching them
+}
+
+// EventBusConfig selects the event bus implementation
+type EventBusConfig struct {
+	Backend       string `env:"EVENT_BUS_BACKEND" default:"memory" oneof:"memory redis redis_streams nats"` // "memory" (single instance) | "redis" (shared across replicas) | "redis_streams" or "nats" (durable)
+	ChannelPrefix string `env:"EVENT_BUS_CHANNEL_PREFIX" default:"uit-ai-assistant:events:"`                // Redis pub/sub channel prefix, also the stream name prefix
+
+	// NATS only. Events are kept in a JetStream stream, one subject per topic.
+	NATSURL           string `env:"NATS_URL" default:"nats://localhost:4222"`
+	NATSStream        string `env:"EVENT_BUS_NATS_STREAM" default:"UIT_EVENTS"`
+	NATSSubjectPrefix string `env:"EVENT_BUS_NATS_SUBJECT_PREFIX" default:"uit-ai-assistant.events"` // Events are published on prefix.topic
+
+	// Redis Streams and NATS. Processes sharing a group split its events, so each process that needs every event
+	// (e.g. every WebSocket hub) needs its own group, named after a stable service or replica name that stays
+	// the same across restarts. The Redis consumer name defaults to the hostname; entries a stopped consumer left
+	// pending are claimed by the next one after ClaimIdleSeconds.
+	ConsumerGroup    string `env:"EVENT_BUS_CONSUMER_GROUP"` // Required with the redis_streams and nats backends
+	ConsumerName     string `env:"EVENT_BUS_CONSUMER_NAME"`
+	StreamMaxLen     int    `env:"EVENT_BUS_STREAM_MAX_LEN" default:"10000"`          // Number of events kept per topic, approximate with Redis
+	ClaimIdleSeconds int    `env:"EVENT_BUS_CLAIM_IDLE_SECONDS" default:"60" min:"1"` // Events left unacknowledged this long, by a stopped consumer or a listener that was full, are delivered again
+}
+
+// OutboxConfig holds the settings for relaying domain events from the outbox collection to the event bus
+type OutboxConfig struct {
+	RelayIntervalSeconds int `env:"OUTBOX_RELAY_INTERVAL_SECONDS" default:"1" min:"0"` // How often pending events are published, 0 disables the relay
+	BatchSize            int `env:"OUTBOX_BATCH_SIZE" default:"100" min:"1"`           // Events published per relay pass at most
+	LeaseSeconds         int `env:"OUTBOX_LEASE_SECONDS" default:"30" min:"1"`         // An event claimed this long ago but not marked delivered is published again
+	RetentionHours       int `env:"OUTBOX_RETENTION_HOURS" default:"24"`               // How long delivered events are kept
+}
+
+// MigrationsConfig holds the settings for applying schema migrations
+type MigrationsConfig struct {
+	RunOnStartup   bool `env:"MIGRATIONS_RUN_ON_STARTUP" default:"true"`         // Apply pending migrations before serving; otherwise run them with uitctl migrate
+	TimeoutSeconds int  `env:"MIGRATIONS_TIMEOUT_SECONDS" default:"600" min:"1"` // How long applying may take, also how long a crashed instance's lock blocks others
+}
+
+// WebSocketConfig holds the WebSocket heartbeat settings
+type WebSocketConfig struct {
+	PingIntervalSeconds  int    `env:"WS_PING_INTERVAL_SECONDS" default:"54"` // How often the server pings each client, must be less than PongTimeoutSeconds
+	PongTimeoutSeconds   int    `env:"WS_PONG_TIMEOUT_SECONDS" default:"60"`  // A client that sends nothing (including pongs) for this long is disconnected
+	WriteTimeoutSeconds  int    `env:"WS_WRITE_TIMEOUT_SECONDS" default:"10"`
+	SweepIntervalSeconds int    `env:"WS_SWEEP_INTERVAL_SECONDS" default:"30"` // How often the hub evicts clients that missed heartbeats
+	AuthTimeoutSeconds   int    `env:"WS_AUTH_TIMEOUT_SECONDS" default:"10"`   // How long a new connection has to send its auth frame
+	SendQueueSize        int    `env:"WS_SEND_QUEUE_SIZE" default:"256"`       // Outbound messages buffered per client before the overflow policy applies
+	OverflowPolicy       string `env:"WS_OVERFLOW_POLICY" default:"drop_oldest" oneof:"drop_oldest disconnect"`
 }
 
 // GoogleConfig holds the Google OAuth2 configuration
 type GoogleConfig struct {
-	ClientID     string
-	ClientSecret string
-	RedirectURL  string
+	ClientID     string `env:"GOOGLE_CLIENT_ID"`
+	ClientSecret string `env:"GOOGLE_CLIENT_SECRET"`
+	RedirectURL  string `env:"GOOGLE_REDIRECT_URL"`
 }
 
 // CloudinaryConfig holds the Cloudinary configuration
 type CloudinaryConfig struct {
-	CloudName    string
-	APIKey       string
-	APISecret    string
-	UploadFolder string
-	UploadPreset string
+	CloudName    string `env:"CLOUDINARY_CLOUD_NAME"`
+	APIKey       string `env:"CLOUDINARY_API_KEY"`
+	APISecret    string `env:"CLOUDINARY_API_SECRET"`
+	UploadFolder string `env:"CLOUDINARY_FOLDER" default:"uit-ai-assistant"`
+	UploadPreset string `env:"CLOUDINARY_UPLOAD_PRESET" default:"uit-ai-assistant_preset"`
 }
 
 // GeminiConfig holds the Gemini AI configuration
 type GeminiConfig struct {
-	APIKey              string
-	Model               string
-	Enabled             bool
-	ConfidenceThreshold float64
-	Timeout             int
-	MaxRetries          int
+	APIKey              string  `env:"GEMINI_API_KEY"`
+	Model               string  `env:"GEMINI_MODEL" default:"gemini-2.0-flash-lite"`
+	Enabled             bool    `env:"GEMINI_ENABLED" default:"true"`
+	ConfidenceThreshold float64 `env:"GEMINI_CONFIDENCE_THRESHOLD" default:"0.7"`
+	Timeout             int     `env:"GEMINI_TIMEOUT" default:"15"`
+	MaxRetries          int     `env:"GEMINI_MAX_RETRIES" default:"3"`
+}
+
+// CitationConfig holds the settings for post-processing agent source citations
+type CitationConfig struct {
+	SnippetMaxLength int  `env:"CITATION_SNIPPET_MAX_LENGTH" default:"300"`
+	LinkCheckEnabled bool `env:"CITATION_LINK_CHECK_ENABLED" default:"true"`
+	LinkCheckTimeout int  `env:"CITATION_LINK_CHECK_TIMEOUT" default:"3"` // Seconds
+}
+
+// DigestConfig holds the settings for the unread notification email digest job
+type DigestConfig struct {
+	Enabled         bool `env:"DIGEST_ENABLED" default:"true"`
+	MinAgeHours     int  `env:"DIGEST_MIN_AGE_HOURS" default:"24"`    // Only notifications unread for at least this long are included
+	IntervalMinutes int  `env:"DIGEST_INTERVAL_MINUTES" default:"60"` // How often the job checks for due digests
+	BatchSize       int  `env:"DIGEST_BATCH_SIZE" default:"50"`       // Number of recipients loaded and emailed per batch
+	SendHour        int  `env:"DIGEST_SEND_HOUR" default:"8"`         // Hour of the day digests are sent, in each user's timezone
+}
+
+// SchedulerConfig holds the settings for the scheduled notification dispatcher
+type SchedulerConfig struct {
+	IntervalSeconds int `env:"SCHEDULER_INTERVAL_SECONDS" default:"30" min:"1"` // How often the dispatcher checks for due notifications
+	BatchSize       int `env:"SCHEDULER_BATCH_SIZE" default:"100" min:"1"`      // Maximum notifications delivered per tick
+}
+
+// EmailCampaignConfig holds the settings for the campaign email sender
+type EmailCampaignConfig struct {
+	IntervalSeconds int `env:"EMAIL_CAMPAIGN_INTERVAL_SECONDS" default:"10"` // How often the sender hands queued deliveries to the email queue
+	BatchSize       int `env:"EMAIL_CAMPAIGN_BATCH_SIZE" default:"50"`       // Maximum deliveries handed over per tick, keeps the email queue from flooding SMTP
+}
+
+// RetentionConfig holds the data retention policy for notifications and deleted users
+type RetentionConfig struct {
+	ReadNotificationDays   int `env:"RETENTION_READ_NOTIFICATION_DAYS" default:"90"`    // Read notifications are deleted this many days after being read
+	UnreadNotificationDays int `env:"RETENTION_UNREAD_NOTIFICATION_DAYS" default:"365"` // Unread notifications are deleted this many days after creation, 0 = keep forever
+	CleanupIntervalHours   int `env:"RETENTION_CLEANUP_INTERVAL_HOURS" default:"24"`    // How often the cleanup job runs
+	DeletedUserDays        int `env:"RETENTION_DELETED_USER_DAYS" default:"30"`         // Soft-deleted users are purged with all their data this many days after deletion, 0 = keep forever
+}
+
+// UsageConfig holds the settings for the per-user usage rollup
+type UsageConfig struct {
+	RollupIntervalMinutes int     `env:"USAGE_ROLLUP_INTERVAL_MINUTES" default:"60"` // How often usage of the current and previous month is recomputed
+	CostPer1KTokens       float64 `env:"USAGE_COST_PER_1K_TOKENS" default:"0.0003"`  // Estimated LLM price in USD per 1000 tokens
+}
+
+// QuotaConfig holds the default daily chat limits per user, 0 = unlimited.
+// Admins can override them for specific users.
+type QuotaConfig struct {
+	DailyMessages int `env:"QUOTA_DAILY_MESSAGES" default:"0"`
+	DailyTokens   int `env:"QUOTA_DAILY_TOKENS" default:"0"`
+}
+
+// DashboardConfig holds the settings for the live admin dashboard pushed over WebSocket
+type DashboardConfig struct {
+	PushIntervalSeconds int `env:"DASHBOARD_PUSH_INTERVAL_SECONDS" default:"5" min:"0"` // How often metrics are pushed to subscribed admins
+	WindowMinutes       int `env:"DASHBOARD_WINDOW_MINUTES" default:"5"`                // Chat latency and error rate are computed over this many recent minutes
+}
+
+// DataExportConfig holds the settings for self-service data exports
+type DataExportConfig struct {
+	WorkerIntervalSeconds int    `env:"DATA_EXPORT_WORKER_INTERVAL_SECONDS" default:"30" min:"0"` // How often the worker picks up pending exports and deletes expired archives
+	RetentionHours        int    `env:"DATA_EXPORT_RETENTION_HOURS" default:"72"`                 // Archives and their download links expire this many hours after they are ready
+	CooldownHours         int    `env:"DATA_EXPORT_COOLDOWN_HOURS" default:"24"`                  // Minimum time between two export requests of a user
+	StaleAfterMinutes     int    `env:"DATA_EXPORT_STALE_AFTER_MINUTES" default:"30"`             // An export still processing after this long is assumed abandoned and retried
+	DownloadBaseURL       string `env:"DATA_EXPORT_DOWNLOAD_BASE_URL"`                            // Public base URL of the API, download links are DownloadBaseURL + /data-exports/...; default APIBaseURL
+}
+
+// AvatarConfig holds the limits for uploaded avatars
+type AvatarConfig struct {
+	MaxSizeMB    int `env:"AVATAR_MAX_SIZE_MB" default:"5"`      // Largest accepted upload
+	MinDimension int `env:"AVATAR_MIN_DIMENSION" default:"128"`  // Smallest accepted width and height in pixels
+	MaxDimension int `env:"AVATAR_MAX_DIMENSION" default:"4096"` // Largest accepted width and height in pixels
+	OutputSize   int `env:"AVATAR_OUTPUT_SIZE" default:"512"`    // Avatars are cropped to a square of this many pixels before they are stored
+}
+
+// CookieConfig holds the settings for the UIT portal cookies synced by the extension
+type CookieConfig struct {
+	TTLMinutes           map[string]int // How long a synced cookie is kept, by source (COOKIE_TTL_MINUTES_DAA, ...); portals expire sessions differently
+	RefreshBeforeMinutes int            `env:"COOKIE_REFRESH_BEFORE_MINUTES" default:"120"`      // A cookie expiring within this long is flagged for re-sync
+	ExpiryCheckSeconds   int            `env:"COOKIE_EXPIRY_CHECK_SECONDS" default:"60" min:"1"` // How often expired cookies are looked for, to notify their users
+}
+
+// ExtensionConfig holds the Chrome extension versions the backend supports, as semantic versions (1.2.3)
+type ExtensionConfig struct {
+	MinVersion          string `env:"EXTENSION_MIN_VERSION" default:"1.0.0"`            // Older extensions are told they must update
+	LatestVersion       string `env:"EXTENSION_LATEST_VERSION" default:"1.0.0"`         // Older extensions are told an update is available
+	ReauthBeforeVersion string `env:"EXTENSION_REAUTH_BEFORE_VERSION"`                  // Extensions older than this must sync every cookie again, empty = never
+	HeartbeatTTLHours   int    `env:"EXTENSION_HEARTBEAT_TTL_HOURS" default:"720"`      // How long the last heartbeat of a user is kept
+	TokenTTLMinutes     int    `env:"EXTENSION_TOKEN_TTL_MINUTES" default:"60" min:"1"` // How long a scoped extension token is valid before it must be renewed
+	SessionMaxAgeHours  int    `env:"EXTENSION_SESSION_MAX_AGE_HOURS" default:"720"`    // Renewals stop this long after the full login, the user must sign in to the extension again
+}
+
+// UITConfig holds the settings for fetching data from UIT portals with a user's synced cookie
+type UITConfig struct {
+	DAABaseURL           string `env:"UIT_DAA_BASE_URL" default:"https://daa.uit.edu.vn"`
+	TimeoutSeconds       int    `env:"UIT_TIMEOUT_SECONDS" default:"15" min:"1"` // Timeout of one portal request
+	ScheduleCacheMinutes int    `env:"UIT_SCHEDULE_CACHE_MINUTES" default:"360"` // How long a parsed timetable is served from cache
+	DRLBaseURL           string `env:"UIT_DRL_BASE_URL" default:"https://drl.uit.edu.vn"`
+	DRLCacheMinutes      int    `env:"UIT_DRL_CACHE_MINUTES" default:"1440"`    // How long a parsed training score is served from cache
+	TuitionCacheMinutes  int    `env:"UIT_TUITION_CACHE_MINUTES" default:"360"` // How long a parsed tuition status is served from cache
+
+	// Course registration
+	CoursesBaseURL          string `env:"UIT_COURSES_BASE_URL" default:"https://courses.uit.edu.vn"`
+	OpenClassesPath         string `env:"UIT_OPEN_CLASSES_PATH" default:"/dkhp/danh-sach-lop"` // Page listing the classes open for registration
+	OpenClassesCacheMinutes int    `env:"UIT_OPEN_CLASSES_CACHE_MINUTES" default:"5"`          // Kept short, remaining slots change fast during registration week
+
+	// Exam reminders
+	ReminderEnabled           bool `env:"UIT_REMINDER_ENABLED" default:"true"`
+	ReminderIntervalMinutes   int  `env:"UIT_REMINDER_INTERVAL_MINUTES" default:"360" min:"1"` // How often the job scans synced students' exams
+	ReminderDaysBefore        int  `env:"UIT_REMINDER_DAYS_BEFORE" default:"1"`                // How many days before an exam the reminder is delivered
+	ReminderHour              int  `env:"UIT_REMINDER_HOUR" default:"20"`                      // Hour of the day reminders are delivered, in each user's timezone
+	TuitionReminderDaysBefore int  `env:"UIT_TUITION_REMINDER_DAYS_BEFORE" default:"3"`        // How many days before a payment deadline the tuition reminder is delivered
+
+	// Grade change detection
+	GradeCheckIntervalMinutes int `env:"UIT_GRADE_CHECK_INTERVAL_MINUTES" default:"60" min:"0"` // How often synced students' grades are compared to their last snapshot, 0 disables it
+
+	// Announcement crawling
+	AnnouncementPages             []string `env:"UIT_ANNOUNCEMENT_PAGES" default:"https://daa.uit.edu.vn/thong-bao-chung,https://www.uit.edu.vn/tin-tuc"` // Public listing pages of official notices
+	AnnouncementPollMinutes       int      `env:"UIT_ANNOUNCEMENT_POLL_MINUTES" default:"30" min:"0"`                                                     // How often the pages are crawled, 0 disables it
+	AnnouncementNotifyMaxAgeDays  int      `env:"UIT_ANNOUNCEMENT_NOTIFY_MAX_AGE_DAYS" default:"7"`                                                       // Older notices are stored without notifying, e.g. when a listing reshuffles
+	AnnouncementAgentContextLimit int      `env:"UIT_ANNOUNCEMENT_AGENT_CONTEXT_LIMIT" default:"30"`                                                      // How many of the latest notices are cached for the agent
 }
 
 // Cfg is a global variable holding the application's configuration
 var Cfg AppConfig
 
-// LoadConfig loads environment variables from .env file and populates the Cfg struct
+// LoadConfig loads environment variables from .env file and populates the Cfg struct.
+// An invalid configuration stops the process, listing every problem found.
 func LoadConfig() {
-	if err := godotenv.Load(); err != nil {
-		log.Println(".env file not found. Using environment variables.")
-	}
+	envErr := godotenv.Load()
+
+	cfg, err := loadConfig(os.LookupEnv)
 
-	//Port
-	Cfg.Port = getEnv("PORT", "8080")
-
-	// Database & App
-	Cfg.MongoURI = getEnv("MONGO_URI", "mongodb://localhost:27017")
-	Cfg.DBName = getEnv("DB_NAME", "uit-ai-assistant")
-	Cfg.FrontendURL = getEnv("FRONTEND_URL", "http://localhost:5173")
-	Cfg.ExtensionOrigin = getEnv("EXTENSION_ORIGIN", "") // Chrome extension origin
-
-	// JWT
-	Cfg.JWTSecret = getEnv("JWT_SECRET", "your-secret-key")
-	Cfg.JWTIssuer = getEnv("JWT_ISSUER", "uit-ai-assistant")
-	Cfg.JWTAudience = getEnv("JWT_AUDIENCE", "uit-ai-assistant-users")
-	Cfg.TokenTTL = getEnvInt("TOKEN_TTL_MINUTES", 60)
-	Cfg.RefreshTokenTTL = getEnvInt("REFRESH_TOKEN_TTL_HOURS", 72)
-
-	// Features
-	Cfg.OTPExpirationMinutes = getEnvInt("OTP_EXPIRATION_MINUTES", 15)
-
-	// Agent
-	Cfg.AgentGRPCAddr = getEnv("AGENT_GRPC_ADDR", "localhost:50051")
-
-	// Services
-	Cfg.SMTP.Host = getEnv("SMTP_HOST", "smtp.example.com")
-	Cfg.SMTP.Port = getEnvInt("SMTP_PORT", 587)
-	Cfg.SMTP.User = getEnv("SMTP_USER", "")
-	Cfg.SMTP.Pass = getEnv("SMTP_PASS", "")
-	Cfg.SMTP.SenderName = getEnv("SMTP_SENDER_NAME", "UIT AI Assistant")
-
-	Cfg.Redis.Addr = getEnv("REDIS_ADDR", "localhost:6379")
-	Cfg.Redis.Password = getEnv("REDIS_PASSWORD", "")
-	Cfg.Redis.DB = getEnvInt("REDIS_DB", 0)
-
-	Cfg.Google.ClientID = getEnv("GOOGLE_CLIENT_ID", "")
-	Cfg.Google.ClientSecret = getEnv("GOOGLE_CLIENT_SECRET", "")
-	Cfg.Google.RedirectURL = getEnv("GOOGLE_REDIRECT_URL", "")
-
-	Cfg.Cloudinary.CloudName = getEnv("CLOUDINARY_CLOUD_NAME", "")
-	Cfg.Cloudinary.APIKey = getEnv("CLOUDINARY_API_KEY", "")
-	Cfg.Cloudinary.APISecret = getEnv("CLOUDINARY_API_SECRET", "")
-	Cfg.Cloudinary.UploadFolder = getEnv("CLOUDINARY_FOLDER", "uit-ai-assistant")
-	Cfg.Cloudinary.UploadPreset = getEnv("CLOUDINARY_UPLOAD_PRESET", "uit-ai-assistant_preset")
-
-	Cfg.Gemini.APIKey = getEnv("GEMINI_API_KEY", "")
-	Cfg.Gemini.Model = getEnv("GEMINI_MODEL", "gemini-2.0-flash-lite")
-	Cfg.Gemini.Enabled = getEnv("GEMINI_ENABLED", "true") == "true"
-	Cfg.Gemini.ConfidenceThreshold = getEnvFloat("GEMINI_CONFIDENCE_THRESHOLD", 0.7)
-	Cfg.Gemini.Timeout = getEnvInt("GEMINI_TIMEOUT", 15)
-	Cfg.Gemini.MaxRetries = getEnvInt("GEMINI_MAX_RETRIES", 3)
-
-	log.Println("Configuration loaded successfully")
-}
-
-// Helper function to get environment variable with a default value
-func getEnv(key, defaultValue string) string {
-	if value, exists := os.LookupEnv(key); exists {
-		return value
+	// Logging, configured first so the rest of startup logs in the chosen format
+	logger.Init(cfg.Log.Level, cfg.Log.Format)
+	if envErr != nil {
+		slog.Info(".env file not found, using environment variables")
 	}
-	return defaultValue
+	if err != nil {
+		logger.Fatal("Invalid configuration", "profile", cfg.Profile, "error", err)
+	}
+
+	Cfg = cfg
+	slog.Info("Configuration loaded successfully", "profile", Cfg.Profile)
 }
 
-// Helper function to get integer environment variable with a default value
-func getEnvInt(key string, defaultValue int) int {
-	if valueStr, exists := os.LookupEnv(key); exists {
-		if value, err := strconv.Atoi(valueStr); err == nil {
-			return value
-		}
+// loadConfig reads the configuration of the profile in APP_ENV
+func loadConfig(lookup func(key string) (string, bool)) (AppConfig, error) {
+	var cfg AppConfig
+	l := &loader{profile: ProfileDev, lookup: lookup}
+	if profile, ok := lookup("APP_ENV"); ok && profile != "" {
+		l.profile = strings.ToLower(profile)
 	}
-	return defaultValue
-}
+	l.fill(reflect.ValueOf(&cfg).Elem())
+	cfg.Profile = l.profile
 
-// Helper function to get float environment variable with a default value
-func getEnvFloat(key string, defaultValue float64) float64 {
-	if valueStr, exists := os.LookupEnv(key); exists {
-		if value, err := strconv.ParseFloat(valueStr, 64); err == nil {
-			return value
+	// Defaults derived from other settings
+	if cfg.APIBaseURL == "" {
+		cfg.APIBaseURL = "http://localhost:" + cfg.Port + "/api/v1"
+	}
+	if cfg.DataExport.DownloadBaseURL == "" {
+		cfg.DataExport.DownloadBaseURL = cfg.APIBaseURL
+	}
+	// A group named after the hostname would be new after every container restart and miss what was published meanwhile
+	if (cfg.EventBus.Backend == "redis_streams" || cfg.EventBus.Backend == "nats") && cfg.EventBus.ConsumerGroup == "" {
+		l.errs = append(l.errs, fmt.Errorf("EVENT_BUS_CONSUMER_GROUP is required with EVENT_BUS_BACKEND=%s", cfg.EventBus.Backend))
+	}
+	if cfg.EventBus.ConsumerName == "" {
+		cfg.EventBus.ConsumerName, _ = os.Hostname()
+	}
+	for _, origin := range []string{cfg.FrontendURL, cfg.ExtensionOrigin} {
+		if origin != "" && !slices.Contains(cfg.CORS.AllowedOrigins, origin) {
+			cfg.CORS.AllowedOrigins = append(cfg.CORS.AllowedOrigins, origin)
 		}
 	}
-	return defaultValue
+
+	cfg.Cookie.TTLMinutes = make(map[string]int, len(CookieSources))
+	for _, source := range CookieSources {
+		cfg.Cookie.TTLMinutes[source] = l.int("COOKIE_TTL_MINUTES_"+strings.ToUpper(source), 1440) // e.g. COOKIE_TTL_MINUTES_DAA
+	}
+
+	return cfg, l.err()
 }
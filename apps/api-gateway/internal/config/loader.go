@@ -0,0 +1,161 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Profiles select the defaults and required settings of a deployment
+const (
+	ProfileDev     = "dev"
+	ProfileStaging = "staging"
+	ProfileProd    = "prod"
+)
+
+// loader fills a config struct from the environment following the tags documented on AppConfig,
+// collecting every problem so a misconfigured deployment reports them all at once
+type loader struct {
+	profile string
+	lookup  func(key string) (string, bool)
+	errs    []error
+}
+
+// fill sets the tagged fields of the struct v points into, descending into nested structs
+func (l *loader) fill(v reflect.Value) {
+	t := v.Type()
+	for i := range t.NumField() {
+		field, value := t.Field(i), v.Field(i)
+
+		keys, ok := field.Tag.Lookup("env")
+		if !ok {
+			if field.Type.Kind() == reflect.Struct {
+				l.fill(value)
+			}
+			continue
+		}
+
+		raw, key, set := l.env(strings.Split(keys, ","))
+		if required, ok := field.Tag.Lookup("required"); ok && slices.Contains(strings.Split(required, ","), l.profile) && !set {
+			l.errs = append(l.errs, fmt.Errorf("%s is required in the %s profile", key, l.profile))
+			continue
+		}
+		if !set {
+			raw = l.defaultValue(field.Tag)
+		}
+
+		if oneOf, ok := field.Tag.Lookup("oneof"); ok {
+			allowed := strings.Fields(oneOf)
+			i := slices.IndexFunc(allowed, func(a string) bool { return strings.EqualFold(a, raw) })
+			if i < 0 {
+				l.errs = append(l.errs, fmt.Errorf("%s must be one of %s, got %q", key, strings.Join(allowed, ", "), raw))
+				continue
+			}
+			// Stored as listed, the code compares against that spelling
+			raw = allowed[i]
+		}
+
+		if err := setValue(value, raw); err != nil {
+			l.errs = append(l.errs, fmt.Errorf("%s: %w", key, err))
+			continue
+		}
+
+		if minValue, ok := field.Tag.Lookup("min"); ok && value.Kind() == reflect.Int {
+			if n, err := strconv.Atoi(minValue); err == nil && value.Int() < int64(n) {
+				l.errs = append(l.errs, fmt.Errorf("%s must be at least %d, got %d", key, n, value.Int()))
+			}
+		}
+	}
+}
+
+// env returns the value of the first of keys that is set and not empty. key is the one to name in errors.
+func (l *loader) env(keys []string) (value, key string, set bool) {
+	for _, k := range keys {
+		if value, ok := l.lookup(k); ok && value != "" {
+			return value, k, true
+		}
+	}
+	return "", keys[0], false
+}
+
+// defaultValue returns the default of the running profile; dev uses the plain default
+func (l *loader) defaultValue(tag reflect.StructTag) string {
+	if l.profile != ProfileDev {
+		if value, ok := tag.Lookup(l.profile); ok {
+			return value
+		}
+	}
+	return tag.Get("default")
+}
+
+// int reads an integer setting that has no struct field of its own
+func (l *loader) int(key string, defaultValue int) int {
+	raw, ok := l.lookup(key)
+	if !ok || raw == "" {
+		return defaultValue
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		l.errs = append(l.errs, fmt.Errorf("%s: %q is not an integer", key, raw))
+	}
+	return value
+}
+
+func (l *loader) err() error {
+	return errors.Join(l.errs...)
+}
+
+func setValue(v reflect.Value, raw string) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Int:
+		if raw == "" {
+			v.SetInt(0)
+			return nil
+		}
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("%q is not an integer", raw)
+		}
+		v.SetInt(int64(n))
+	case reflect.Float64:
+		if raw == "" {
+			v.SetFloat(0)
+			return nil
+		}
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", raw)
+		}
+		v.SetFloat(f)
+	case reflect.Bool:
+		if raw == "" {
+			v.SetBool(false)
+			return nil
+		}
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("%q is not true or false", raw)
+		}
+		v.SetBool(b)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported type %s", v.Type())
+		}
+		// Comma-separated, empty items are ignored
+		var items []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		v.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/metrics"
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+// Datastore metrics, exposed on /metrics
+var (
+	mongoCommandDuration = metrics.NewHistogramVec(
+		"mongo_command_duration_seconds",
+		"Time MongoDB took to run a command, by command name.",
+		metrics.DefaultBuckets,
+		"command",
+	)
+	mongoCommandErrors = metrics.NewCounterVec(
+		"mongo_command_errors_total",
+		"MongoDB commands that failed, by command name.",
+		"command",
+	)
+	redisCommandDuration = metrics.NewHistogramVec(
+		"redis_command_duration_seconds",
+		"Time Redis took to run a command, by command name (pipelines as \"pipeline\").",
+		metrics.DefaultBuckets,
+		"command",
+	)
+	redisCommandErrors = metrics.NewCounterVec(
+		"redis_command_errors_total",
+		"Redis commands that failed, by command name. Cache misses are not errors.",
+		"command",
+	)
+)
+
+// newMongoMonitor times every command the driver sends
+func newMongoMonitor() *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Succeeded: func(_ context.Context, e *event.CommandSucceededEvent) {
+			mongoCommandDuration.WithLabel(e.CommandName).Observe(e.Duration.Seconds())
+		},
+		Failed: func(_ context.Context, e *event.CommandFailedEvent) {
+			mongoCommandDuration.WithLabel(e.CommandName).Observe(e.Duration.Seconds())
+			mongoCommandErrors.WithLabel(e.CommandName).Inc()
+		},
+	}
+}
+
+// redisMetricsHook times every command and pipeline run by the client
+type redisMetricsHook struct{}
+
+func (redisMetricsHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+func (redisMetricsHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmd)
+		observeRedis(cmd.Name(), start, err)
+		return err
+	}
+}
+
+func (redisMetricsHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmds)
+		observeRedis("pipeline", start, err)
+		return err
+	}
+}
+
+func observeRedis(command string, start time.Time, err error) {
+	redisCommandDuration.WithLabel(command).Observe(time.Since(start).Seconds())
+	if err != nil && !errors.Is(err, redis.Nil) {
+		redisCommandErrors.WithLabel(command).Inc()
+	}
+}
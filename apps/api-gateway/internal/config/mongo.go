@@ -2,12 +2,16 @@ package config
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"time"
 
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/logger"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
 var (
@@ -19,27 +23,33 @@ var (
 func NewMongoClient() *mongo.Client {
 	uri := Cfg.MongoURI
 	if uri == "" {
-		log.Fatal("MONGO_URI not configured")
+		logger.Fatal("MONGO_URI not configured")
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	clientOptions, err := mongoClientOptions(&Cfg.Mongo)
 	if err != nil {
-		log.Fatalf("Could not connect to MongoDB: %v", err)
+		logger.Fatal("Invalid MongoDB client settings", "error", err)
+	}
+
+	// Applied last so options in the URI override the settings
+	client, err := mongo.Connect(ctx, clientOptions.ApplyURI(uri).SetMonitor(newMongoMonitor()))
+	if err != nil {
+		logger.Fatal("Could not connect to MongoDB", "error", err)
 	}
 
 	// Test connection
 	if err := client.Ping(ctx, nil); err != nil {
-		log.Fatalf("MongoDB ping failed: %v", err)
+		logger.Fatal("MongoDB ping failed", "error", err)
 	}
 
-	log.Println("Connected to MongoDB successfully!")
+	slog.Info("Connected to MongoDB")
 
 	dbName := Cfg.DBName
 	if dbName == "" {
-		log.Fatal("DB_NAME not configured")
+		logger.Fatal("DB_NAME not configured")
 	}
 
 	Client = client
@@ -47,13 +57,72 @@ func NewMongoClient() *mongo.Client {
 
 	// Ensure required collections exist (create if missing)
 	if err := ensureCollections(ctx, db); err != nil {
-		log.Fatalf("Collection initialization failed: %v", err)
+		logger.Fatal("Collection initialization failed", "error", err)
 	}
 
-	log.Printf("Using database: %s\n", dbName)
+	if err := EnsureIndexes(ctx, db); err != nil {
+		logger.Fatal("Index initialization failed", "error", err)
+	}
+
+	slog.Info("Using database", "name", dbName)
 	return client
 }
 
+// mongoClientOptions builds the client pool, timeout and read preference options from cfg
+func mongoClientOptions(cfg *MongoConfig) (*options.ClientOptions, error) {
+	if cfg.MaxPoolSize < 0 || cfg.MinPoolSize < 0 || cfg.SocketTimeoutSeconds < 0 {
+		return nil, errors.New("pool sizes and timeouts must not be negative")
+	}
+	if cfg.MaxPoolSize > 0 && cfg.MinPoolSize > cfg.MaxPoolSize {
+		return nil, fmt.Errorf("MONGO_MIN_POOL_SIZE %d exceeds MONGO_MAX_POOL_SIZE %d", cfg.MinPoolSize, cfg.MaxPoolSize)
+	}
+	mode, err := readpref.ModeFromString(cfg.ReadPreference)
+	if err != nil {
+		return nil, err
+	}
+	readPreference, err := readpref.New(mode)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := options.Client().
+		SetMaxPoolSize(uint64(cfg.MaxPoolSize)).
+		SetMinPoolSize(uint64(cfg.MinPoolSize)).
+		SetConnectTimeout(time.Duration(cfg.ConnectTimeoutSeconds) * time.Second).
+		SetServerSelectionTimeout(time.Duration(cfg.ServerSelectionTimeoutSeconds) * time.Second).
+		SetReadPreference(readPreference)
+	if cfg.SocketTimeoutSeconds > 0 {
+		opts.SetSocketTimeout(time.Duration(cfg.SocketTimeoutSeconds) * time.Second)
+	}
+	return opts, nil
+}
+
+// EnsureIndexes creates the indexes the collections rely on. Existing indexes are left as they are,
+// so it is safe to run on every start.
+func EnsureIndexes(ctx context.Context, db *mongo.Database) error {
+	steps := []struct {
+		name   string
+		ensure func(context.Context, *mongo.Database) error
+	}{
+		{"notification", ensureNotificationIndexes},
+		{"user", ensureUserIndexes},
+		{"uit announcement", ensureUITAnnouncementIndexes},
+		{"chat", ensureChatIndexes},
+		{"outbox", ensureOutboxIndexes},
+		{"email delivery", ensureEmailDeliveryIndexes},
+		{"data export", ensureDataExportIndexes},
+		{"scheduled notification", ensureScheduledNotificationIndexes},
+		{"email verification", ensureEmailVerificationIndexes},
+	}
+
+	for _, step := range steps {
+		if err := step.ensure(ctx, db); err != nil {
+			return fmt.Errorf("%s indexes: %w", step.name, err)
+		}
+	}
+	return nil
+}
+
 func ensureCollections(ctx context.Context, db *mongo.Database) error {
 	collections, err := db.ListCollectionNames(ctx, struct{}{})
 	if err != nil {
@@ -66,6 +135,9 @@ func ensureCollections(ctx context.Context, db *mongo.Database) error {
 		EmailVerificationColName,
 		ChatSessionColName,
 		ChatMessageColName,
+		// Written inside transactions, which cannot always create collections
+		AuditLogColName,
+		OutboxColName,
 	}
 
 	existing := make(map[string]bool, len(collections))
@@ -76,14 +148,249 @@ func ensureCollections(ctx context.Context, db *mongo.Database) error {
 	// Create missing collections
 	for _, name := range required {
 		if !existing[name] {
-			log.Printf("Creating missing collection: %s", name)
+			slog.Info("Creating missing collection", "collection", name)
 			if err := db.CreateCollection(ctx, name); err != nil {
 				return fmt.Errorf("failed to create collection %q: %w", name, err)
 			}
-			log.Printf("✓ Collection created: %s", name)
+			slog.Info("Collection created", "collection", name)
 		}
 	}
 
-	log.Println("All required collections ready")
+	slog.Info("All required collections ready")
+	return nil
+}
+
+// Names of the unique user indexes, a duplicate key error names the index it violated
+const (
+	UserEmailUniqueIndex    = "email_unique"
+	UserUsernameUniqueIndex = "username_unique"
+)
+
+// ensureUserIndexes creates the indexes backing the admin user list filters and sort fields, and the unique
+// indexes on email and username. deleted_at is part of the unique keys: live users all have it missing,
+// so they must not share an email or username, while soft-deleted users keep theirs without blocking reuse.
+func ensureUserIndexes(ctx context.Context, db *mongo.Database) error {
+	indexes := db.Collection(UserColName).Indexes()
+
+	_, err := indexes.CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "last_login", Value: -1}}},
+		{Keys: bson.D{{Key: "provider", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "is_verified", Value: 1}, {Key: "created_at", Value: -1}}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create user indexes: %w", err)
+	}
+
+	for _, unique := range []struct{ name, field string }{
+		{UserEmailUniqueIndex, "email"},
+		{UserUsernameUniqueIndex, "username"},
+	} {
+		_, err := indexes.CreateOne(ctx, mongo.IndexModel{
+			Keys:    bson.D{{Key: unique.field, Value: 1}, {Key: "deleted_at", Value: 1}},
+			Options: options.Index().SetName(unique.name).SetUnique(true),
+		})
+		if mongo.IsDuplicateKeyError(err) {
+			// Existing duplicates must be resolved by hand, the app still runs on its own checks meanwhile
+			slog.Error("Unique user index not created, live users share a value", "index", unique.name, "error", err)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to create %s index: %w", unique.name, err)
+		}
+
+		// The unique index covers lookups by the field, the plain index it replaces is dropped
+		if err := dropIndexIfExists(ctx, indexes, unique.field+"_1"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dropIndexIfExists drops an index that is no longer needed, doing nothing if it was already dropped
+func dropIndexIfExists(ctx context.Context, indexes mongo.IndexView, name string) error {
+	_, err := indexes.DropOne(ctx, name)
+	var cmdErr mongo.CommandError
+	if err != nil && !(errors.As(err, &cmdErr) && cmdErr.Code == 27) { // IndexNotFound
+		return fmt.Errorf("failed to drop index %s: %w", name, err)
+	}
+	return nil
+}
+
+// ensureChatIndexes creates the indexes backing a user's session list (most recent first) and
+// a session's message history (oldest first)
+func ensureChatIndexes(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection(ChatSessionColName).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "updated_at", Value: -1}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create chat session indexes: %w", err)
+	}
+
+	_, err = db.Collection(ChatMessageColName).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "session_id", Value: 1}, {Key: "created_at", Value: 1}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create chat message indexes: %w", err)
+	}
+	return nil
+}
+
+// ensureUITAnnouncementIndexes creates the unique indexes that dedupe crawled announcements by URL and by hash
+func ensureUITAnnouncementIndexes(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection(UITAnnouncementColName).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "url", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "created_at", Value: -1}}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create uit announcement indexes: %w", err)
+	}
+	return nil
+}
+
+// ensureNotificationIndexes creates the indexes backing cursor pagination and unread lookups, and the TTL index that
+// expires read notifications. If the retention period changed since the TTL index was created,
+// the index is updated in place.
+func ensureNotificationIndexes(ctx context.Context, db *mongo.Database) error {
+	const ttlIndexName = "read_at_ttl"
+	ttlSeconds := int32(Cfg.Retention.ReadNotificationDays * 24 * 60 * 60)
+
+	_, err := db.Collection(NotificationColName).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "recipient_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create recipient index: %w", err)
+	}
+
+	_, err = db.Collection(NotificationColName).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "recipient_id", Value: 1}, {Key: "is_read", Value: 1}, {Key: "created_at", Value: -1}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create unread index: %w", err)
+	}
+
+	updated, err := ensureTTLIndex(ctx, db, NotificationColName, ttlIndexName, "read_at", ttlSeconds)
+	if err != nil {
+		return err
+	}
+	if updated {
+		slog.Info("TTL index updated: read notifications expire", "after_days", Cfg.Retention.ReadNotificationDays)
+	} else {
+		slog.Info("TTL index ready: read notifications expire", "after_days", Cfg.Retention.ReadNotificationDays)
+	}
+	return nil
+}
+
+// ensureTTLIndex creates a TTL index on field, or changes its expiry in place if it already exists with another one.
+// Reports whether an existing index was updated.
+func ensureTTLIndex(ctx context.Context, db *mongo.Database, colName, name, field string, ttlSeconds int32) (bool, error) {
+	_, err := db.Collection(colName).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: field, Value: 1}},
+		Options: options.Index().
+			SetName(name).
+			SetExpireAfterSeconds(ttlSeconds),
+	})
+	if err == nil {
+		return false, nil
+	}
+
+	// IndexOptionsConflict: same index with a different expireAfterSeconds
+	var cmdErr mongo.CommandError
+	if !errors.As(err, &cmdErr) || cmdErr.Code != 85 {
+		return false, fmt.Errorf("failed to create TTL index: %w", err)
+	}
+
+	err = db.RunCommand(ctx, bson.D{
+		{Key: "collMod", Value: colName},
+		{Key: "index", Value: bson.D{
+			{Key: "name", Value: name},
+			{Key: "expireAfterSeconds", Value: ttlSeconds},
+		}},
+	}).Err()
+	if err != nil {
+		return false, fmt.Errorf("failed to update TTL index: %w", err)
+	}
+	return true, nil
+}
+
+// ensureOutboxIndexes creates the index the relay scans pending events with, and the TTL index that
+// expires delivered events. Pending events have no delivered_at, so the TTL index leaves them alone.
+func ensureOutboxIndexes(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection(OutboxColName).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "delivered_at", Value: 1}, {Key: "created_at", Value: 1}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create pending index: %w", err)
+	}
+
+	ttlSeconds := int32(Cfg.Outbox.RetentionHours * 60 * 60)
+	_, err = ensureTTLIndex(ctx, db, OutboxColName, "delivered_at_ttl", "delivered_at", ttlSeconds)
+	return err
+}
+
+// ensureEmailDeliveryIndexes creates the index the campaign progress counts use, and the unique index that
+// keeps a campaign at one delivery per user, so a batch queued again after an interrupted attempt adds no duplicates
+func ensureEmailDeliveryIndexes(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection(EmailDeliveryColName).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "campaign_id", Value: 1}, {Key: "status", Value: 1}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create campaign status index: %w", err)
+	}
+
+	_, err = db.Collection(EmailDeliveryColName).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "campaign_id", Value: 1}, {Key: "user_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		// Campaigns already sent twice keep their duplicates, queueing falls back to its progress marker
+		slog.Error("Unique email delivery index not created, a campaign has duplicate deliveries", "error", err)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create campaign recipient index: %w", err)
+	}
+	return nil
+}
+
+// ensureDataExportIndexes creates the indexes the worker claims pending exports with and a user's latest export is
+// looked up with
+func ensureDataExportIndexes(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection(DataExportColName).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: 1}}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create data export indexes: %w", err)
+	}
+	return nil
+}
+
+// ensureScheduledNotificationIndexes creates the index the worker claims due notifications with
+func ensureScheduledNotificationIndexes(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection(ScheduledNotificationColName).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "status", Value: 1}, {Key: "deliver_at", Value: 1}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create due index: %w", err)
+	}
+	return nil
+}
+
+// ensureEmailVerificationIndexes creates the index verifications are looked up by and the TTL index that expires
+// consumed verifications. They are kept a day, well past the verification token, so a retried registration
+// completion still finds the account it created.
+func ensureEmailVerificationIndexes(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection(EmailVerificationColName).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "email", Value: 1}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create email verification index: %w", err)
+	}
+
+	if _, err := ensureTTLIndex(ctx, db, EmailVerificationColName, "completed_at_ttl", "completed_at", 24*60*60); err != nil {
+		return err
+	}
 	return nil
 }
@@ -0,0 +1,36 @@
+package config
+
+import (
+	"log/slog"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/logger"
+	"github.com/nats-io/nats.go"
+)
+
+// NewNATSConn connects to the NATS server of the event bus. Like Redis, an unavailable server does not stop startup:
+// the connection keeps retrying in the background and events are delivered locally meanwhile.
+func NewNATSConn() *nats.Conn {
+	conn, err := nats.Connect(Cfg.EventBus.NATSURL,
+		nats.Name("api-gateway"),
+		nats.RetryOnFailedConnect(true),
+		nats.MaxReconnects(-1),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			if err != nil {
+				slog.Warn("Disconnected from NATS, reconnecting", "error", err)
+			}
+		}),
+		nats.ReconnectHandler(func(conn *nats.Conn) {
+			slog.Info("Reconnected to NATS", "url", conn.ConnectedUrl())
+		}),
+	)
+	if err != nil {
+		logger.Fatal("Invalid NATS settings", "url", Cfg.EventBus.NATSURL, "error", err)
+	}
+
+	if conn.IsConnected() {
+		slog.Info("Connected to NATS")
+	} else {
+		slog.Warn("Could not connect to NATS yet, retrying in the background", "url", Cfg.EventBus.NATSURL)
+	}
+	return conn
+}
@@ -2,7 +2,7 @@ package config
 
 import (
 	"context"
-	"log"
+	"log/slog"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -10,10 +10,40 @@ import (
 
 const (
 	// Redis key patterns
-	RedisInvalidatedUserKey  = "invalidated:user:%s"  // For delete user - invalidate all tokens
-	RedisBlacklistedTokenKey = "blacklisted:token:%s" // For logout - invalidate specific token by JTI
+	RedisInvalidatedUserKey    = "invalidated:user:%s"       // For delete user - invalidate all tokens
+	RedisBlacklistedTokenKey   = "blacklisted:token:%s"      // For logout - invalidate specific token by JTI
+	RedisPresenceKey           = "presence:user:%s"          // Hash of WebSocket connection count and last seen time
+	RedisUserCacheKey          = "user_cache:%s"             // BSON encoded user document, by user ID
+	RedisUsernameExistsKey     = "username_exists:%s"        // Cached availability check of a username, "true" if taken
+	RedisAdminUserCountsKey    = "admin_user_counts"         // Hash of admin user list totals by filter signature, dropped whenever a user changes
+	RedisEmailQueueKey         = "email_queue"               // Sorted set of queued email job IDs, scored by next attempt time (Unix ms)
+	RedisEmailJobsKey          = "email_jobs"                // Hash of queued email jobs as JSON, by job ID
+	RedisEmailDeadLetterKey    = "email_dead_letter"         // List of email jobs that failed every attempt, as JSON, newest first
+	RedisMaintenanceKey        = "maintenance"               // Hash of maintenance mode state, shared by all API instances
+	RedisCookieKey             = "%s_cookie:%s"              // UIT portal cookie synced by the extension, by source and user ID
+	RedisCookieExpiryKey       = "cookie_expiry"             // Sorted set of synced cookies ("source:userID") scored by expiry time, to detect expired ones
+	RedisChatQuotaKey          = "chat_quota:%s:%s"          // Hash of messages and tokens used by a user on a day (YYYY-MM-DD)
+	RedisOnlineUsersKey        = "presence:online"           // Set of user IDs with at least one WebSocket connection
+	RedisDashboardInFlightKey  = "dashboard:chats_in_flight" // Sorted set of chat requests waiting on the agent, scored by start time
+	RedisDashboardChatKey      = "dashboard:chats:%d"        // Hash of chat requests, errors and agent latency in one minute (Unix minutes)
+	RedisDashboardPublisherKey = "dashboard:publisher"       // Held by the instance pushing dashboard metrics this interval
+	RedisAccountLinkUsedKey    = "account_link:used:%s"      // Set once a link token (by JTI) has been spent on a password check
+	RedisUITScheduleKey        = "uit_schedule:%s"           // Parsed DAA timetable of a user, as JSON
+	RedisUITExamsKey           = "uit_exams:%s"              // Parsed DAA exam schedule of a user, as JSON
+	RedisUITTuitionKey         = "uit_tuition:%s"            // Parsed DAA tuition status of a user, as JSON
+	RedisUITOpenClassesKey     = "uit_open_classes:%s"       // Classes open for registration as seen by a user, as JSON; also read by the agent
+	RedisUITReminderKey        = "uit_reminder:%s:%s"        // Marks a reminder as scheduled, by user and event, until the event is over
+	RedisUITGradeSnapshotKey   = "uit_grades:%s"             // Hash of a user's last seen course grades, by semester and course code
+	RedisUITTrainingScoreKey   = "uit_drl:%s"                // Parsed DRL training score of a user, as JSON; also read by the agent
+	RedisUITAnnouncementsKey   = "uit_announcements"         // Latest crawled UIT announcements, as JSON; read by the agent
+	RedisExtensionHeartbeatKey = "extension:heartbeat:%s"    // Hash of the version, capabilities and time of a user's last extension heartbeat
+	RedisIdempotencyKey        = "idempotency:%s:%s:%s"      // Response to a request with an Idempotency-Key, as JSON, by caller, route and key
+	RedisRateLimitKey          = "rate_limit:%s:%s"          // Hash of the tokens left in a rate limit bucket and when it was refilled, by policy and caller
 )
 
+// CookieSources are the UIT portals the extension can sync cookies for
+var CookieSources = []string{"daa", "courses", "drl"}
+
 // NewRedisClient creates and returns a new Redis client using the global AppConfig.
 func NewRedisClient() *redis.Client {
 	// Create the client with configuration from the global Cfg variable.
@@ -22,6 +52,7 @@ func NewRedisClient() *redis.Client {
 		Password: Cfg.Redis.Password,
 		DB:       Cfg.Redis.DB,
 	})
+	client.AddHook(redisMetricsHook{})
 
 	// Create a context with a timeout to test the connection.
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -32,9 +63,9 @@ func NewRedisClient() *redis.Client {
 		// Log a warning instead of a fatal error.
 		// This allows the application to continue running even if Redis is unavailable.
 		// Features that depend on Redis (like token invalidation) will be gracefully disabled.
-		log.Printf("WARNING: Could not connect to Redis at %s. Features depending on Redis may be disabled. Error: %v", Cfg.Redis.Addr, err)
+		slog.Warn("Could not connect to Redis, features depending on it may be disabled", "addr", Cfg.Redis.Addr, "error", err)
 	} else {
-		log.Println("Successfully connected to Redis.")
+		slog.Info("Connected to Redis")
 	}
 
 	return client
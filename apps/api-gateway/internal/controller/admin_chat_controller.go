@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/apperror"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/auth"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type AdminChatController struct {
+	adminChatService service.AdminChatService
+}
+
+func NewAdminChatController(adminChatService service.AdminChatService) *AdminChatController {
+	return &AdminChatController{
+		adminChatService: adminChatService,
+	}
+}
+
+// GetUserSessions lists a user's chat sessions
+// GET /api/v1/admin/users/:user_id/chat/sessions
+func (c *AdminChatController) GetUserSessions(ctx *gin.Context) {
+	authUser, exists := ctx.Get("authUser")
+	if !exists {
+		dto.SendError(ctx, http.StatusUnauthorized, apperror.ErrForbidden.Message, apperror.ErrForbidden.Code)
+		return
+	}
+
+	var query dto.GetSessionsQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		dto.SendError(ctx, http.StatusBadRequest, "Invalid query parameters", apperror.ErrBadRequest.Code)
+		return
+	}
+
+	sessions, err := c.adminChatService.GetUserSessions(ctx.Request.Context(), authUser.(auth.AuthUser).ID, ctx.Param("user_id"), &query)
+	if err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
+	}
+
+	dto.SendPage(ctx, http.StatusOK, "Sessions retrieved successfully", sessions)
+}
+
+// GetUserSessionMessages returns a user's chat session with its messages
+// GET /api/v1/admin/users/:user_id/chat/sessions/:session_id/messages
+func (c *AdminChatController) GetUserSessionMessages(ctx *gin.Context) {
+	authUser, exists := ctx.Get("authUser")
+	if !exists {
+		dto.SendError(ctx, http.StatusUnauthorized, apperror.ErrForbidden.Message, apperror.ErrForbidden.Code)
+		return
+	}
+
+	var query dto.GetMessagesQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		dto.SendError(ctx, http.StatusBadRequest, "Invalid query parameters", apperror.ErrBadRequest.Code)
+		return
+	}
+
+	limit := query.Limit
+	if limit == 0 {
+		limit = 50 // Default 50 messages
+	}
+
+	session, err := c.adminChatService.GetUserSessionMessages(ctx.Request.Context(), authUser.(auth.AuthUser).ID, ctx.Param("user_id"), ctx.Param("session_id"), limit)
+	if err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
+	}
+
+	dto.SendSuccess(ctx, http.StatusOK, "Messages retrieved successfully", session)
+}
+
+// SearchMessages searches all chat transcripts for an abuse investigation
+// POST /api/v1/admin/chat/search
+func (c *AdminChatController) SearchMessages(ctx *gin.Context) {
+	authUser, exists := ctx.Get("authUser")
+	if !exists {
+		dto.SendError(ctx, http.StatusUnauthorized, apperror.ErrForbidden.Message, apperror.ErrForbidden.Code)
+		return
+	}
+
+	var req dto.SearchChatMessagesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		dto.SendError(ctx, http.StatusBadRequest, apperror.Message(apperror.ErrBadRequest), apperror.ErrBadRequest.Code)
+		return
+	}
+
+	results, err := c.adminChatService.SearchMessages(ctx.Request.Context(), authUser.(auth.AuthUser).ID, &req)
+	if err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
+	}
+
+	dto.SendSuccess(ctx, http.StatusOK, "Chat messages searched successfully", results)
+}
@@ -0,0 +1,37 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/apperror"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type AdminStatsController struct {
+	statsService service.AdminStatsService
+}
+
+func NewAdminStatsController(statsService service.AdminStatsService) *AdminStatsController {
+	return &AdminStatsController{
+		statsService: statsService,
+	}
+}
+
+// GetStats returns user growth and chat activity statistics
+func (c *AdminStatsController) GetStats(ctx *gin.Context) {
+	var query dto.AdminStatsQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		dto.SendError(ctx, http.StatusBadRequest, "Invalid query parameters", apperror.ErrBadRequest.Code)
+		return
+	}
+
+	stats, err := c.statsService.GetStats(ctx.Request.Context(), query.Days)
+	if err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
+	}
+
+	dto.SendSuccess(ctx, http.StatusOK, "Statistics retrieved successfully", stats)
+}
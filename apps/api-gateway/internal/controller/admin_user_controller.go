@@ -1,10 +1,14 @@
 package controller
 
 import (
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/apperror"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/auth"
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/logger"
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/service"
 	"github.com/gin-gonic/gin"
 )
@@ -27,13 +31,40 @@ func (c *AdminUserController) GetUsers(ctx *gin.Context) {
 		return
 	}
 
-	users, err := c.adminService.GetUsersAdmin(&query)
+	users, err := c.adminService.GetUsersAdmin(ctx.Request.Context(), &query)
 	if err != nil {
 		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
 		return
 	}
 
-	dto.SendSuccess(ctx, http.StatusOK, "Users retrieved successfully", users)
+	dto.SendPage(ctx, http.StatusOK, "Users retrieved successfully", users)
+}
+
+// ExportUsers streams users matching the admin list filters as a CSV download
+// GET /api/v1/admin/users/export
+func (c *AdminUserController) ExportUsers(ctx *gin.Context) {
+	var query dto.GetUsersAdminQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		dto.SendError(ctx, http.StatusBadRequest, "Invalid query parameters", apperror.ErrBadRequest.Code)
+		return
+	}
+
+	filename := fmt.Sprintf("users-%s.csv", time.Now().Format("20060102-150405"))
+	ctx.Header("Content-Type", "text/csv; charset=utf-8")
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	ctx.Status(http.StatusOK)
+
+	// Headers are already sent once rows are streaming, so a failure can only be logged
+	// and the download ends truncated
+	if err := c.adminService.ExportUsersCSV(ctx.Request.Context(), &query, ctx.Writer); err != nil {
+		if !ctx.Writer.Written() {
+			ctx.Header("Content-Type", "")
+			ctx.Header("Content-Disposition", "")
+			dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+			return
+		}
+		logger.FromContext(ctx.Request.Context()).Error("User export failed", "error", err)
+	}
 }
 
 // BanUser bans a user
@@ -50,7 +81,7 @@ func (c *AdminUserController) BanUser(ctx *gin.Context) {
 		return
 	}
 
-	err := c.adminService.BanUser(userID, &req)
+	err := c.adminService.BanUser(ctx.Request.Context(), userID, &req)
 	if err != nil {
 		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
 		return
@@ -72,7 +103,7 @@ func (c *AdminUserController) UnbanUser(ctx *gin.Context) {
 		return
 	}
 
-	err := c.adminService.UnbanUser(userID)
+	err := c.adminService.UnbanUser(ctx.Request.Context(), userID)
 	if err != nil {
 		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
 		return
@@ -89,7 +120,7 @@ func (c *AdminUserController) DeleteUser(ctx *gin.Context) {
 		return
 	}
 
-	err := c.adminService.SoftDeleteUser(userID)
+	err := c.adminService.SoftDeleteUser(ctx.Request.Context(), userID)
 	if err != nil {
 		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
 		return
@@ -106,7 +137,7 @@ func (c *AdminUserController) RestoreUser(ctx *gin.Context) {
 		return
 	}
 
-	err := c.adminService.RestoreUser(userID)
+	err := c.adminService.RestoreUser(ctx.Request.Context(), userID)
 	if err != nil {
 		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
 		return
@@ -114,3 +145,146 @@ func (c *AdminUserController) RestoreUser(ctx *gin.Context) {
 
 	dto.SendSuccess(ctx, http.StatusOK, "User restored successfully", gin.H{"user_id": userID})
 }
+
+// BulkUserAction applies ban/unban/delete/restore to many users at once
+// POST /api/v1/admin/users/bulk
+func (c *AdminUserController) BulkUserAction(ctx *gin.Context) {
+	var req dto.BulkUserActionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		dto.SendError(ctx, http.StatusBadRequest, apperror.Message(apperror.ErrBadRequest), apperror.ErrBadRequest.Code)
+		return
+	}
+
+	result, err := c.adminService.BulkUserAction(ctx.Request.Context(), &req)
+	if err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
+	}
+
+	dto.SendSuccess(ctx, http.StatusOK, "Bulk action completed", result)
+}
+
+// EraseUser permanently erases a user and all associated data
+// POST /api/v1/admin/users/:user_id/erase
+func (c *AdminUserController) EraseUser(ctx *gin.Context) {
+	authUser, exists := ctx.Get("authUser")
+	if !exists {
+		dto.SendError(ctx, http.StatusUnauthorized, apperror.ErrForbidden.Message, apperror.ErrForbidden.Code)
+		return
+	}
+
+	var req dto.EraseUserRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		dto.SendError(ctx, http.StatusBadRequest, apperror.Message(apperror.ErrBadRequest), apperror.ErrBadRequest.Code)
+		return
+	}
+
+	report, err := c.adminService.EraseUser(ctx.Request.Context(), authUser.(auth.AuthUser).ID, ctx.Param("user_id"), &req)
+	if err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
+	}
+
+	dto.SendSuccess(ctx, http.StatusOK, "User erased successfully", report)
+}
+
+// UpdateUserRole promotes a user to admin or demotes an admin to user
+// PATCH /api/v1/admin/users/:user_id/role
+func (c *AdminUserController) UpdateUserRole(ctx *gin.Context) {
+	authUser, exists := ctx.Get("authUser")
+	if !exists {
+		dto.SendError(ctx, http.StatusUnauthorized, apperror.ErrForbidden.Message, apperror.ErrForbidden.Code)
+		return
+	}
+
+	var req dto.UpdateUserRoleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		dto.SendError(ctx, http.StatusBadRequest, apperror.Message(apperror.ErrBadRequest), apperror.ErrBadRequest.Code)
+		return
+	}
+
+	user, err := c.adminService.UpdateUserRole(ctx.Request.Context(), authUser.(auth.AuthUser).ID, ctx.Param("user_id"), &req)
+	if err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
+	}
+
+	dto.SendSuccess(ctx, http.StatusOK, "User role updated successfully", user)
+}
+
+// ForceLogout invalidates all of a user's tokens and closes their WebSocket connections
+// POST /api/v1/admin/users/:user_id/force-logout
+func (c *AdminUserController) ForceLogout(ctx *gin.Context) {
+	authUser, exists := ctx.Get("authUser")
+	if !exists {
+		dto.SendError(ctx, http.StatusUnauthorized, apperror.ErrForbidden.Message, apperror.ErrForbidden.Code)
+		return
+	}
+
+	if err := c.adminService.ForceLogout(ctx.Request.Context(), authUser.(auth.AuthUser).ID, ctx.Param("user_id")); err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
+	}
+
+	dto.SendSuccess(ctx, http.StatusOK, "User logged out successfully", nil)
+}
+
+// UpdateUser edits a user's username, email verified flag or settings for support cases
+// PATCH /api/v1/admin/users/:user_id
+func (c *AdminUserController) UpdateUser(ctx *gin.Context) {
+	authUser, exists := ctx.Get("authUser")
+	if !exists {
+		dto.SendError(ctx, http.StatusUnauthorized, apperror.ErrForbidden.Message, apperror.ErrForbidden.Code)
+		return
+	}
+
+	var req dto.AdminUpdateUserRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		dto.SendError(ctx, http.StatusBadRequest, apperror.Message(apperror.ErrBadRequest), apperror.ErrBadRequest.Code)
+		return
+	}
+
+	user, err := c.adminService.UpdateUser(ctx.Request.Context(), authUser.(auth.AuthUser).ID, ctx.Param("user_id"), &req)
+	if err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
+	}
+
+	dto.SendSuccess(ctx, http.StatusOK, "User updated successfully", user)
+}
+
+// GetUserNotes lists the internal support notes on a user
+// GET /api/v1/admin/users/:user_id/notes
+func (c *AdminUserController) GetUserNotes(ctx *gin.Context) {
+	notes, err := c.adminService.GetUserNotes(ctx.Request.Context(), ctx.Param("user_id"))
+	if err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
+	}
+
+	dto.SendSuccess(ctx, http.StatusOK, "User notes retrieved successfully", notes)
+}
+
+// AddUserNote appends an internal support note to a user
+// POST /api/v1/admin/users/:user_id/notes
+func (c *AdminUserController) AddUserNote(ctx *gin.Context) {
+	authUser, exists := ctx.Get("authUser")
+	if !exists {
+		dto.SendError(ctx, http.StatusUnauthorized, apperror.ErrForbidden.Message, apperror.ErrForbidden.Code)
+		return
+	}
+
+	var req dto.AddUserNoteRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		dto.SendError(ctx, http.StatusBadRequest, apperror.Message(apperror.ErrBadRequest), apperror.ErrBadRequest.Code)
+		return
+	}
+
+	note, err := c.adminService.AddUserNote(ctx.Request.Context(), authUser.(auth.AuthUser).ID, ctx.Param("user_id"), &req)
+	if err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
+	}
+
+	dto.SendSuccess(ctx, http.StatusCreated, "User note added successfully", note)
+}
@@ -0,0 +1,109 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/apperror"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/auth"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type AnnouncementController struct {
+	announcementService service.AnnouncementService
+}
+
+func NewAnnouncementController(announcementService service.AnnouncementService) *AnnouncementController {
+	return &AnnouncementController{
+		announcementService: announcementService,
+	}
+}
+
+// CreateAnnouncement creates a draft announcement
+func (c *AnnouncementController) CreateAnnouncement(ctx *gin.Context) {
+	authUser, exists := ctx.Get("authUser")
+	if !exists {
+		dto.SendError(ctx, http.StatusUnauthorized, apperror.ErrForbidden.Message, apperror.ErrForbidden.Code)
+		return
+	}
+
+	var req dto.CreateAnnouncementRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		dto.SendError(ctx, http.StatusBadRequest, apperror.Message(apperror.ErrBadRequest), apperror.ErrBadRequest.Code)
+		return
+	}
+
+	announcement, err := c.announcementService.CreateAnnouncement(ctx.Request.Context(), authUser.(auth.AuthUser).ID, &req)
+	if err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
+	}
+
+	dto.SendSuccess(ctx, http.StatusCreated, "Announcement created successfully", announcement)
+}
+
+// GetAnnouncements lists announcements, newest first
+func (c *AnnouncementController) GetAnnouncements(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
+
+	announcements, err := c.announcementService.GetAnnouncements(ctx.Request.Context(), page, pageSize)
+	if err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
+	}
+
+	dto.SendSuccess(ctx, http.StatusOK, "Announcements retrieved successfully", announcements)
+}
+
+// GetAnnouncement gets a single announcement
+func (c *AnnouncementController) GetAnnouncement(ctx *gin.Context) {
+	announcement, err := c.announcementService.GetAnnouncement(ctx.Request.Context(), ctx.Param("id"))
+	if err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
+	}
+
+	dto.SendSuccess(ctx, http.StatusOK, "Announcement retrieved successfully", announcement)
+}
+
+// UpdateAnnouncement updates a draft announcement
+func (c *AnnouncementController) UpdateAnnouncement(ctx *gin.Context) {
+	var req dto.UpdateAnnouncementRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		dto.SendError(ctx, http.StatusBadRequest, apperror.Message(apperror.ErrBadRequest), apperror.ErrBadRequest.Code)
+		return
+	}
+
+	announcement, err := c.announcementService.UpdateAnnouncement(ctx.Request.Context(), ctx.Param("id"), &req)
+	if err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
+	}
+
+	dto.SendSuccess(ctx, http.StatusOK, "Announcement updated successfully", announcement)
+}
+
+// DeleteAnnouncement deletes an announcement
+func (c *AnnouncementController) DeleteAnnouncement(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if err := c.announcementService.DeleteAnnouncement(ctx.Request.Context(), id); err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
+	}
+
+	dto.SendSuccess(ctx, http.StatusOK, "Announcement deleted successfully", gin.H{"id": id})
+}
+
+// SendAnnouncement starts delivering an announcement to its segment
+func (c *AnnouncementController) SendAnnouncement(ctx *gin.Context) {
+	announcement, err := c.announcementService.SendAnnouncement(ctx.Request.Context(), ctx.Param("id"))
+	if err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
+	}
+
+	dto.SendSuccess(ctx, http.StatusAccepted, "Announcement is being sent", announcement)
+}
@@ -2,7 +2,6 @@ package controller
 
 import (
 	"fmt"
-	"log"
 	"net/http"
 	"net/url"
 
@@ -10,6 +9,7 @@ import (
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/auth"
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/logger"
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/service"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -55,7 +55,7 @@ func (c *AuthController) SendEmailVerification(ctx *gin.Context) {
 		return
 	}
 
-	err := c.authService.SendEmailVerification(req.Email)
+	err := c.authService.SendEmailVerification(ctx.Request.Context(), req.Email)
 	if err != nil {
 		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
 		return
@@ -71,7 +71,7 @@ func (c *AuthController) Login(ctx *gin.Context) {
 		return
 	}
 
-	user, accessToken, refreshToken, err := c.authService.Login(req.Identifier, req.Password)
+	user, accessToken, refreshToken, err := c.authService.Login(ctx.Request.Context(), req.Identifier, req.Password, ctx.ClientIP())
 	if err != nil {
 		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
 		return
@@ -95,7 +95,7 @@ func (c *AuthController) VerifyEmailCode(ctx *gin.Context) {
 		return
 	}
 
-	verificationToken, err := c.authService.VerifyEmailCode(req.Email, req.OTP)
+	verificationToken, err := c.authService.VerifyEmailCode(ctx.Request.Context(), req.Email, req.OTP)
 	if err != nil {
 		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
 		return
@@ -112,7 +112,7 @@ func (c *AuthController) CompleteRegistration(ctx *gin.Context) {
 		return
 	}
 
-	user, accessToken, refreshToken, err := c.authService.CompleteRegistration(req.VerificationToken, req.Username, req.Password)
+	user, accessToken, refreshToken, err := c.authService.CompleteRegistration(ctx.Request.Context(), req.VerificationToken, req.Username, req.Password, ctx.ClientIP())
 	if err != nil {
 		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
 		return
@@ -136,7 +136,7 @@ func (c *AuthController) ResendOTP(ctx *gin.Context) {
 		return
 	}
 
-	err := c.authService.ResendOTP(req.Email)
+	err := c.authService.ResendOTP(ctx.Request.Context(), req.Email)
 	if err != nil {
 		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
 		return
@@ -152,7 +152,7 @@ func (c *AuthController) RefreshToken(ctx *gin.Context) {
 		return
 	}
 
-	accessToken, refreshToken, err := c.authService.RefreshToken(req.RefreshToken)
+	accessToken, refreshToken, err := c.authService.RefreshToken(ctx.Request.Context(), req.RefreshToken, ctx.ClientIP())
 	if err != nil {
 		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
 		return
@@ -175,7 +175,7 @@ func (c *AuthController) Logout(ctx *gin.Context) {
 		return
 	}
 
-	err := c.authService.Logout(req.AccessToken, req.RefreshToken)
+	err := c.authService.Logout(ctx.Request.Context(), req.AccessToken, req.RefreshToken)
 	if err != nil {
 		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
 		return
@@ -196,27 +196,28 @@ func (c *AuthController) GoogleLogin(ctx *gin.Context) {
 }
 
 func (c *AuthController) GoogleCallback(ctx *gin.Context) {
+	reqLog := logger.FromContext(ctx.Request.Context())
 	code := ctx.Query("code")
 	if code == "" {
 		// Redirect to FE with error
 		redirectURL := fmt.Sprintf("%s/#/auth/error?message=missing_auth_code", config.Cfg.FrontendURL)
-		log.Printf("GoogleCallback: Missing code, redirecting to: %s", redirectURL)
+		reqLog.Warn("GoogleCallback: missing code", "redirect", redirectURL)
 		redirectWithHash(ctx, redirectURL)
 		return
 	}
 
-	log.Printf("GoogleCallback: Processing code: %s", code[:10]+"...")
+	reqLog.Debug("GoogleCallback: processing code")
 
-	result, err := c.authService.ProcessGoogleCallback(code)
+	result, err := c.authService.ProcessGoogleCallback(ctx.Request.Context(), code, ctx.ClientIP())
 	if err != nil {
 		// Redirect to FE with error
 		redirectURL := fmt.Sprintf("%s/#/auth/error?message=%s", config.Cfg.FrontendURL, url.QueryEscape(apperror.Message(err)))
-		log.Printf("GoogleCallback: Error processing callback: %v, redirecting to: %s", err, redirectURL)
+		reqLog.Error("GoogleCallback: error processing callback", "error", err, "redirect", redirectURL)
 		redirectWithHash(ctx, redirectURL)
 		return
 	}
 
-	log.Printf("GoogleCallback: Result status: %s", result.Status)
+	reqLog.Debug("GoogleCallback: result", "status", result.Status)
 
 	switch result.Status {
 	case service.StatusLoginSuccess:
@@ -225,7 +226,7 @@ func (c *AuthController) GoogleCallback(ctx *gin.Context) {
 			config.Cfg.FrontendURL,
 			url.QueryEscape(result.AccessToken),
 			url.QueryEscape(result.RefreshToken))
-		log.Printf("GoogleCallback: Login success, redirecting to: %s", redirectURL)
+		reqLog.Info("GoogleCallback: login success")
 		redirectWithHash(ctx, redirectURL)
 
 	case service.StatusSetupRequired:
@@ -233,13 +234,21 @@ func (c *AuthController) GoogleCallback(ctx *gin.Context) {
 		redirectURL := fmt.Sprintf("%s/#/auth/google-setup?setup_token=%s",
 			config.Cfg.FrontendURL,
 			url.QueryEscape(result.SetupToken))
-		log.Printf("GoogleCallback: Setup required, redirecting to: %s", redirectURL)
+		reqLog.Info("GoogleCallback: setup required")
+		redirectWithHash(ctx, redirectURL)
+
+	case service.StatusLinkRequired:
+		// Email belongs to a local account, FE asks for its password before linking
+		redirectURL := fmt.Sprintf("%s/#/auth/link-account?link_token=%s",
+			config.Cfg.FrontendURL,
+			url.QueryEscape(result.LinkToken))
+		reqLog.Info("GoogleCallback: link required")
 		redirectWithHash(ctx, redirectURL)
 
 	default:
 		// Redirect to FE with error
 		redirectURL := fmt.Sprintf("%s/#/auth/error?message=unknown_error", config.Cfg.FrontendURL)
-		log.Printf("GoogleCallback: Unknown status, redirecting to: %s", redirectURL)
+		reqLog.Warn("GoogleCallback: unknown status", "status", result.Status, "redirect", redirectURL)
 		redirectWithHash(ctx, redirectURL)
 	}
 }
@@ -251,7 +260,7 @@ func (c *AuthController) CompleteGoogleSetup(ctx *gin.Context) {
 		return
 	}
 
-	user, accessToken, refreshToken, err := c.authService.CompleteGoogleSetup(req.SetupToken, req.Username)
+	user, accessToken, refreshToken, err := c.authService.CompleteGoogleSetup(ctx.Request.Context(), req.SetupToken, req.Username, ctx.ClientIP())
 	if err != nil {
 		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
 		return
@@ -268,19 +277,43 @@ func (c *AuthController) CompleteGoogleSetup(ctx *gin.Context) {
 	dto.SendSuccess(ctx, http.StatusOK, "Setup complete. You are now logged in.", data)
 }
 
+// CompleteAccountLink links Google to an existing local account after the user confirms its password.
+func (c *AuthController) CompleteAccountLink(ctx *gin.Context) {
+	var req dto.CompleteAccountLinkRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		dto.SendError(ctx, http.StatusBadRequest, apperror.Message(apperror.ErrBadRequest), apperror.ErrBadRequest.Code)
+		return
+	}
+
+	user, accessToken, refreshToken, err := c.authService.CompleteAccountLink(ctx.Request.Context(), req.LinkToken, req.Password, ctx.ClientIP())
+	if err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
+	}
+
+	setAuthCookies(ctx, accessToken, refreshToken)
+
+	data := dto.AuthResponse{
+		User:         dto.FromUser(user),
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}
+	dto.SendSuccess(ctx, http.StatusOK, "Google account linked. You are now logged in.", data)
+}
+
 // ====== Cookie Helpers ======
 
 // setAuthCookies sets HTTP-only cookies for access and refresh tokens.
 func setAuthCookies(ctx *gin.Context, accessToken, refreshToken string) {
 	// Access token cookie (TTL từ config)
 	ctx.SetCookie(
-		"access_token",                  // name
-		accessToken,                     // value
-		config.Cfg.TokenTTL*60,         // maxAge (phút -> giây)
-		"/",                             // path
-		"",                              // domain (empty = same origin)
-		false,                           // secure (true nếu HTTPS production)
-		true,                            // httpOnly (QUAN TRỌNG!)
+		"access_token",         // name
+		accessToken,            // value
+		config.Cfg.TokenTTL*60, // maxAge (phút -> giây)
+		"/",                    // path
+		"",                     // domain (empty = same origin)
+		false,                  // secure (true nếu HTTPS production)
+		true,                   // httpOnly (QUAN TRỌNG!)
 	)
 
 	// Refresh token cookie (TTL từ config)
@@ -6,8 +6,9 @@ import (
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/apperror"
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/auth"
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/middleware"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/service"
-	"github.com/giakiet05/uit-ai-assistant/backend/internal/util"
 	"github.com/gin-gonic/gin"
 )
 
@@ -47,11 +48,24 @@ func (c *ChatController) Chat(ctx *gin.Context) {
 		return
 	}
 
-	// Call service
-	dbCtx, cancel := util.NewDefaultDBContext()
+	// Call service, the request ID and logger come along for the service's logs and the agent call,
+	// which stops at the route's timeout
+	dbCtx, cancel := middleware.RequestContext(ctx)
 	defer cancel()
 
-	assistantMsg, err := c.chatService.Chat(dbCtx, userID, req.SessionID, req.Message)
+	// User's default language comes from settings cached by the auth middleware
+	var settings *model.UserSettings
+	if userSettings, ok := authUser.(auth.AuthUser).Settings.(model.UserSettings); ok {
+		settings = &userSettings
+	}
+
+	// Student profile lets the agent personalize answers, e.g. curriculum of the user's program
+	var student *model.StudentProfile
+	if profile, ok := authUser.(auth.AuthUser).Student.(model.StudentProfile); ok {
+		student = &profile
+	}
+
+	assistantMsg, err := c.chatService.Chat(dbCtx, userID, req.SessionID, req.Message, req.Language, settings, student)
 	if err != nil {
 		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
 		return
@@ -89,31 +103,30 @@ func (c *ChatController) GetSessions(ctx *gin.Context) {
 		return
 	}
 
-	// Build options
-	opts := query.ToFindOptions()
+	page, err := query.ToPage()
+	if err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
+	}
 
 	// Call service
-	dbCtx, cancel := util.NewDefaultDBContext()
+	dbCtx, cancel := middleware.RequestContext(ctx)
 	defer cancel()
 
-	sessions, err := c.chatService.GetSessionsByUserID(dbCtx, userID, opts)
+	sessions, next, err := c.chatService.GetSessionsByUserID(dbCtx, userID, page)
 	if err != nil {
 		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
 		return
 	}
 
-	// Convert to response
-	response := make([]dto.SessionResponse, len(sessions))
-	for i, session := range sessions {
-		response[i] = dto.SessionResponse{
-			ID:        session.ID.Hex(),
-			Title:     session.Title,
-			CreatedAt: session.CreatedAt,
-			UpdatedAt: session.UpdatedAt,
-		}
+	// Convert to response (includes last message preview and message count)
+	response := dto.PaginatedSessionsResponse{
+		Sessions:   dto.FromChatSessionSummaries(sessions),
+		NextCursor: dto.NextCursor(next),
+		HasMore:    next != nil,
 	}
 
-	dto.SendSuccess(ctx, http.StatusOK, "Sessions retrieved successfully", response)
+	dto.SendPage(ctx, http.StatusOK, "Sessions retrieved successfully", response)
 }
 
 // GetSession retrieves a single session by ID
@@ -133,7 +146,7 @@ func (c *ChatController) GetSession(ctx *gin.Context) {
 	}
 
 	// Call service
-	dbCtx, cancel := util.NewDefaultDBContext()
+	dbCtx, cancel := middleware.RequestContext(ctx)
 	defer cancel()
 
 	session, err := c.chatService.GetSessionByID(dbCtx, userID, sessionID)
@@ -145,6 +158,7 @@ func (c *ChatController) GetSession(ctx *gin.Context) {
 	response := dto.SessionResponse{
 		ID:        session.ID.Hex(),
 		Title:     session.Title,
+		Language:  session.Language,
 		CreatedAt: session.CreatedAt,
 		UpdatedAt: session.UpdatedAt,
 	}
@@ -175,26 +189,30 @@ func (c *ChatController) GetMessages(ctx *gin.Context) {
 		return
 	}
 
-	// Default limit
-	limit := query.Limit
-	if limit == 0 {
-		limit = 50 // Default 50 messages
+	page, err := query.ToPage()
+	if err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
 	}
 
 	// Call service
-	dbCtx, cancel := util.NewDefaultDBContext()
+	dbCtx, cancel := middleware.RequestContext(ctx)
 	defer cancel()
 
-	messages, err := c.chatService.GetMessagesBySessionID(dbCtx, userID, sessionID, limit)
+	messages, next, err := c.chatService.GetMessagesBySessionID(dbCtx, userID, sessionID, page)
 	if err != nil {
 		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
 		return
 	}
 
 	// Convert to response
-	response := make([]dto.ChatMessageResponse, len(messages))
+	response := dto.PaginatedMessagesResponse{
+		Messages:   make([]dto.ChatMessageResponse, len(messages)),
+		NextCursor: dto.NextCursor(next),
+		HasMore:    next != nil,
+	}
 	for i, msg := range messages {
-		response[i] = dto.ChatMessageResponse{
+		response.Messages[i] = dto.ChatMessageResponse{
 			ID:        msg.ID.Hex(),
 			Role:      string(msg.Role),
 			Content:   msg.Content,
@@ -203,7 +221,7 @@ func (c *ChatController) GetMessages(ctx *gin.Context) {
 		}
 	}
 
-	dto.SendSuccess(ctx, http.StatusOK, "Messages retrieved successfully", response)
+	dto.SendPage(ctx, http.StatusOK, "Messages retrieved successfully", response)
 }
 
 // DeleteSession soft deletes a session
@@ -223,7 +241,7 @@ func (c *ChatController) DeleteSession(ctx *gin.Context) {
 	}
 
 	// Call service
-	dbCtx, cancel := util.NewDefaultDBContext()
+	dbCtx, cancel := middleware.RequestContext(ctx)
 	defer cancel()
 
 	err := c.chatService.DeleteSession(dbCtx, userID, sessionID)
@@ -265,7 +283,7 @@ func (c *ChatController) UpdateSessionTitle(ctx *gin.Context) {
 	}
 
 	// Call service
-	dbCtx, cancel := util.NewDefaultDBContext()
+	dbCtx, cancel := middleware.RequestContext(ctx)
 	defer cancel()
 
 	session, err := c.chatService.UpdateSessionTitle(dbCtx, userID, sessionID, req.Title)
@@ -277,9 +295,46 @@ func (c *ChatController) UpdateSessionTitle(ctx *gin.Context) {
 	response := dto.SessionResponse{
 		ID:        session.ID.Hex(),
 		Title:     session.Title,
+		Language:  session.Language,
 		CreatedAt: session.CreatedAt,
 		UpdatedAt: session.UpdatedAt,
 	}
 
 	dto.SendSuccess(ctx, http.StatusOK, "Session title updated successfully", response)
 }
+
+// UpdateSessionLanguage sets or clears the session language override
+// PATCH /api/chat/sessions/:id/language
+func (c *ChatController) UpdateSessionLanguage(ctx *gin.Context) {
+	authUser, exists := ctx.Get("authUser")
+	if !exists {
+		dto.SendError(ctx, http.StatusUnauthorized, apperror.ErrForbidden.Message, apperror.ErrForbidden.Code)
+		return
+	}
+	userID := authUser.(auth.AuthUser).ID
+
+	sessionID := ctx.Param("id")
+	if sessionID == "" {
+		dto.SendError(ctx, http.StatusBadRequest, "Session ID is required", apperror.ErrBadRequest.Code)
+		return
+	}
+
+	// Bind request
+	var req dto.UpdateSessionLanguageRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		dto.SendError(ctx, http.StatusBadRequest, "Invalid request body", apperror.ErrBadRequest.Code)
+		return
+	}
+
+	// Call service
+	dbCtx, cancel := middleware.RequestContext(ctx)
+	defer cancel()
+
+	session, err := c.chatService.UpdateSessionLanguage(dbCtx, userID, sessionID, req.Language)
+	if err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
+	}
+
+	dto.SendSuccess(ctx, http.StatusOK, "Session language updated successfully", dto.FromChatSession(session))
+}
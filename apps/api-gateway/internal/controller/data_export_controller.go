@@ -0,0 +1,74 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/apperror"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/auth"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type DataExportController struct {
+	dataExportService service.DataExportService
+}
+
+func NewDataExportController(dataExportService service.DataExportService) *DataExportController {
+	return &DataExportController{
+		dataExportService: dataExportService,
+	}
+}
+
+// RequestExport starts building an archive of all the current user's data
+// POST /api/v1/users/me/export
+func (c *DataExportController) RequestExport(ctx *gin.Context) {
+	authUser, exists := ctx.Get("authUser")
+	if !exists {
+		dto.SendError(ctx, http.StatusUnauthorized, apperror.ErrForbidden.Message, apperror.ErrForbidden.Code)
+		return
+	}
+
+	export, err := c.dataExportService.RequestExport(ctx.Request.Context(), authUser.(auth.AuthUser).ID)
+	if err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
+	}
+
+	dto.SendSuccess(ctx, http.StatusAccepted, "Data export requested successfully", export)
+}
+
+// GetExport returns the status of one of the current user's exports, with a download link once ready
+// GET /api/v1/users/me/exports/:export_id
+func (c *DataExportController) GetExport(ctx *gin.Context) {
+	authUser, exists := ctx.Get("authUser")
+	if !exists {
+		dto.SendError(ctx, http.StatusUnauthorized, apperror.ErrForbidden.Message, apperror.ErrForbidden.Code)
+		return
+	}
+
+	export, err := c.dataExportService.GetExport(ctx.Request.Context(), authUser.(auth.AuthUser).ID, ctx.Param("export_id"))
+	if err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
+	}
+
+	dto.SendSuccess(ctx, http.StatusOK, "Data export retrieved successfully", export)
+}
+
+// Download streams an export archive. The signed link is the only credential,
+// so it also works when opened from the notification email.
+// GET /api/v1/data-exports/:export_id/download?expires=...&signature=...
+func (c *DataExportController) Download(ctx *gin.Context) {
+	reader, size, filename, err := c.dataExportService.OpenDownload(ctx.Param("export_id"), ctx.Query("expires"), ctx.Query("signature"))
+	if err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
+	}
+	defer reader.Close()
+
+	ctx.DataFromReader(http.StatusOK, size, "application/zip", reader, map[string]string{
+		"Content-Disposition": fmt.Sprintf(`attachment; filename="%s"`, filename),
+	})
+}
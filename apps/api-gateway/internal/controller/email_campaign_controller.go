@@ -0,0 +1,103 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/apperror"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/auth"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type EmailCampaignController struct {
+	emailCampaignService service.EmailCampaignService
+}
+
+func NewEmailCampaignController(emailCampaignService service.EmailCampaignService) *EmailCampaignController {
+	return &EmailCampaignController{
+		emailCampaignService: emailCampaignService,
+	}
+}
+
+// CreateCampaign creates a draft email campaign
+// POST /api/v1/admin/email-campaigns
+func (c *EmailCampaignController) CreateCampaign(ctx *gin.Context) {
+	authUser, exists := ctx.Get("authUser")
+	if !exists {
+		dto.SendError(ctx, http.StatusUnauthorized, apperror.ErrForbidden.Message, apperror.ErrForbidden.Code)
+		return
+	}
+
+	var req dto.CreateEmailCampaignRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		dto.SendError(ctx, http.StatusBadRequest, apperror.Message(apperror.ErrBadRequest), apperror.ErrBadRequest.Code)
+		return
+	}
+
+	campaign, err := c.emailCampaignService.CreateCampaign(ctx.Request.Context(), authUser.(auth.AuthUser).ID, &req)
+	if err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
+	}
+
+	dto.SendSuccess(ctx, http.StatusCreated, "Email campaign created successfully", campaign)
+}
+
+// GetCampaigns lists email campaigns, newest first
+// GET /api/v1/admin/email-campaigns
+func (c *EmailCampaignController) GetCampaigns(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
+
+	campaigns, err := c.emailCampaignService.GetCampaigns(ctx.Request.Context(), page, pageSize)
+	if err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
+	}
+
+	dto.SendSuccess(ctx, http.StatusOK, "Email campaigns retrieved successfully", campaigns)
+}
+
+// GetCampaign returns a campaign with its delivery progress
+// GET /api/v1/admin/email-campaigns/:id
+func (c *EmailCampaignController) GetCampaign(ctx *gin.Context) {
+	campaign, err := c.emailCampaignService.GetCampaign(ctx.Request.Context(), ctx.Param("id"))
+	if err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
+	}
+
+	dto.SendSuccess(ctx, http.StatusOK, "Email campaign retrieved successfully", campaign)
+}
+
+// SendCampaign queues a draft campaign for delivery
+// POST /api/v1/admin/email-campaigns/:id/send
+func (c *EmailCampaignController) SendCampaign(ctx *gin.Context) {
+	campaign, err := c.emailCampaignService.SendCampaign(ctx.Request.Context(), ctx.Param("id"))
+	if err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
+	}
+
+	dto.SendSuccess(ctx, http.StatusAccepted, "Email campaign is being sent", campaign)
+}
+
+// GetDeliveries lists the per-recipient delivery results of a campaign
+// GET /api/v1/admin/email-campaigns/:id/deliveries
+func (c *EmailCampaignController) GetDeliveries(ctx *gin.Context) {
+	var query dto.GetEmailDeliveriesQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		dto.SendError(ctx, http.StatusBadRequest, "Invalid query parameters", apperror.ErrBadRequest.Code)
+		return
+	}
+
+	deliveries, err := c.emailCampaignService.GetDeliveries(ctx.Request.Context(), ctx.Param("id"), &query)
+	if err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
+	}
+
+	dto.SendSuccess(ctx, http.StatusOK, "Email deliveries retrieved successfully", deliveries)
+}
@@ -0,0 +1,39 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/apperror"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type EmailPreferenceController struct {
+	emailPreferenceService service.EmailPreferenceService
+}
+
+func NewEmailPreferenceController(emailPreferenceService service.EmailPreferenceService) *EmailPreferenceController {
+	return &EmailPreferenceController{
+		emailPreferenceService: emailPreferenceService,
+	}
+}
+
+// Unsubscribe turns off the email category of a signed unsubscribe link. The signature is the only credential.
+// GET /api/v1/email/unsubscribe?user=...&category=...&type=...&signature=...
+func (c *EmailPreferenceController) Unsubscribe(ctx *gin.Context) {
+	category := model.EmailCategory(ctx.Query("category"))
+	notifType := model.NotificationType(ctx.Query("type"))
+
+	err := c.emailPreferenceService.Unsubscribe(ctx.Request.Context(), ctx.Query("user"), category, notifType, ctx.Query("signature"))
+	if err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
+	}
+
+	dto.SendSuccess(ctx, http.StatusOK, "Unsubscribed successfully", gin.H{
+		"category": category,
+		"type":     notifType,
+	})
+}
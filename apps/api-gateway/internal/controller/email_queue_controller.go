@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/apperror"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type EmailQueueController struct {
+	emailQueueService service.EmailQueueService
+}
+
+func NewEmailQueueController(emailQueueService service.EmailQueueService) *EmailQueueController {
+	return &EmailQueueController{
+		emailQueueService: emailQueueService,
+	}
+}
+
+// GetFailedEmails lists emails given up on after too many failed sends, most recently failed first
+// GET /api/v1/admin/emails/failed
+func (c *EmailQueueController) GetFailedEmails(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
+
+	emails, err := c.emailQueueService.GetFailedEmails(page, pageSize)
+	if err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
+	}
+
+	dto.SendSuccess(ctx, http.StatusOK, "Failed emails retrieved successfully", emails)
+}
+
+// RequeueFailedEmail queues a failed email again with a fresh set of attempts
+// POST /api/v1/admin/emails/failed/:id/requeue
+func (c *EmailQueueController) RequeueFailedEmail(ctx *gin.Context) {
+	if err := c.emailQueueService.RequeueFailedEmail(ctx.Param("id")); err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
+	}
+
+	dto.SendSuccess(ctx, http.StatusAccepted, "Email queued for sending", nil)
+}
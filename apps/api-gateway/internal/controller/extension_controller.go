@@ -0,0 +1,62 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/apperror"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/auth"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type ExtensionController struct {
+	extensionService service.ExtensionService
+}
+
+func NewExtensionController(extensionService service.ExtensionService) *ExtensionController {
+	return &ExtensionController{extensionService: extensionService}
+}
+
+// IssueToken exchanges the user's access token, or a still valid extension token, for a scoped extension token
+// POST /api/v1/extension/token
+func (c *ExtensionController) IssueToken(ctx *gin.Context) {
+	authUser, exists := ctx.Get("authUser")
+	if !exists {
+		dto.SendError(ctx, http.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
+		return
+	}
+
+	resp, err := c.extensionService.IssueToken(authUser.(auth.AuthUser))
+	if err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
+	}
+
+	dto.SendSuccess(ctx, http.StatusOK, "Extension token issued", resp)
+}
+
+// Heartbeat reports the extension's version and capabilities and returns the supported versions
+// POST /api/v1/extension/heartbeat
+func (c *ExtensionController) Heartbeat(ctx *gin.Context) {
+	authUser, exists := ctx.Get("authUser")
+	if !exists {
+		dto.SendError(ctx, http.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
+		return
+	}
+	user := authUser.(auth.AuthUser)
+
+	var req dto.ExtensionHeartbeatRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		dto.SendError(ctx, http.StatusBadRequest, apperror.Message(apperror.ErrBadRequest), apperror.ErrBadRequest.Code)
+		return
+	}
+
+	resp, err := c.extensionService.Heartbeat(user.ID, &req)
+	if err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
+	}
+
+	dto.SendSuccess(ctx, http.StatusOK, "Heartbeat received", resp)
+}
@@ -0,0 +1,38 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/apperror"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type ModerationController struct {
+	moderationService service.ModerationService
+}
+
+func NewModerationController(moderationService service.ModerationService) *ModerationController {
+	return &ModerationController{
+		moderationService: moderationService,
+	}
+}
+
+// GetDecisions lists Gemini moderation decisions, newest first
+// GET /api/v1/admin/moderation/decisions
+func (c *ModerationController) GetDecisions(ctx *gin.Context) {
+	var query dto.GetModerationDecisionsQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		dto.SendError(ctx, http.StatusBadRequest, "Invalid query parameters", apperror.ErrBadRequest.Code)
+		return
+	}
+
+	decisions, err := c.moderationService.GetDecisions(ctx.Request.Context(), &query)
+	if err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
+	}
+
+	dto.SendSuccess(ctx, http.StatusOK, "Moderation decisions retrieved successfully", decisions)
+}
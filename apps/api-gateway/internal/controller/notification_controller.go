@@ -7,6 +7,7 @@ import (
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/apperror"
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/auth"
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/service"
 	"github.com/gin-gonic/gin"
 )
@@ -26,16 +27,16 @@ func (c *NotificationController) GetNotifications(ctx *gin.Context) {
 		return
 	}
 
-	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
-	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("pageSize", "15"))
+	cursor := ctx.Query("cursor")
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "15"))
 
-	notifications, err := c.service.GetNotifications(authUser.(auth.AuthUser).ID, page, pageSize)
+	notifications, err := c.service.GetNotifications(ctx.Request.Context(), authUser.(auth.AuthUser).ID, cursor, limit)
 	if err != nil {
 		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
 		return
 	}
 
-	dto.SendSuccess(ctx, http.StatusOK, "Notifications retrieved successfully", notifications)
+	dto.SendPage(ctx, http.StatusOK, "Notifications retrieved successfully", notifications)
 }
 
 func (c *NotificationController) MarkAllAsRead(ctx *gin.Context) {
@@ -45,7 +46,7 @@ func (c *NotificationController) MarkAllAsRead(ctx *gin.Context) {
 		return
 	}
 
-	modifiedCount, err := c.service.MarkAllAsRead(authUser.(auth.AuthUser).ID)
+	modifiedCount, err := c.service.MarkAllAsRead(ctx.Request.Context(), authUser.(auth.AuthUser).ID)
 	if err != nil {
 		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
 		return
@@ -53,3 +54,68 @@ func (c *NotificationController) MarkAllAsRead(ctx *gin.Context) {
 
 	dto.SendSuccess(ctx, http.StatusOK, "All notifications marked as read", gin.H{"marked_count": modifiedCount})
 }
+
+func (c *NotificationController) GetUnreadCount(ctx *gin.Context) {
+	authUser, exists := ctx.Get("authUser")
+	if !exists {
+		dto.SendError(ctx, http.StatusUnauthorized, apperror.ErrForbidden.Message, apperror.ErrForbidden.Code)
+		return
+	}
+
+	count, err := c.service.GetUnreadCount(ctx.Request.Context(), authUser.(auth.AuthUser).ID)
+	if err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
+	}
+
+	dto.SendSuccess(ctx, http.StatusOK, "Unread count retrieved successfully", gin.H{"unread_count": count})
+}
+
+// ScheduleNotification queues a notification for future delivery (admin only)
+func (c *NotificationController) ScheduleNotification(ctx *gin.Context) {
+	authUser, exists := ctx.Get("authUser")
+	if !exists {
+		dto.SendError(ctx, http.StatusUnauthorized, apperror.ErrForbidden.Message, apperror.ErrForbidden.Code)
+		return
+	}
+
+	var req dto.ScheduleNotificationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		dto.SendError(ctx, http.StatusBadRequest, apperror.Message(apperror.ErrBadRequest), apperror.ErrBadRequest.Code)
+		return
+	}
+
+	scheduled, err := c.service.ScheduleNotification(ctx.Request.Context(), req.RecipientIDs, req.Type, req.Message, req.Link, req.Data.ToModel(), req.DeliverAt, req.LocalTime, authUser.(auth.AuthUser).ID)
+	if err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
+	}
+
+	dto.SendSuccess(ctx, http.StatusCreated, "Notification scheduled successfully", scheduled)
+}
+
+// GetScheduledNotifications lists scheduled notifications, optionally filtered by status (admin only)
+func (c *NotificationController) GetScheduledNotifications(ctx *gin.Context) {
+	status := model.ScheduledNotificationStatus(ctx.Query("status"))
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "20"))
+
+	scheduled, err := c.service.GetScheduledNotifications(ctx.Request.Context(), status, page, pageSize)
+	if err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
+	}
+
+	dto.SendPage(ctx, http.StatusOK, "Scheduled notifications retrieved successfully", scheduled)
+}
+
+// CancelScheduledNotification cancels a pending scheduled notification (admin only)
+func (c *NotificationController) CancelScheduledNotification(ctx *gin.Context) {
+	cancelled, err := c.service.CancelScheduledNotification(ctx.Request.Context(), ctx.Param("id"))
+	if err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
+	}
+
+	dto.SendSuccess(ctx, http.StatusOK, "Scheduled notification cancelled", cancelled)
+}
@@ -0,0 +1,38 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/apperror"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/auth"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type PresenceController struct {
+	presenceService service.PresenceService
+}
+
+func NewPresenceController(presenceService service.PresenceService) *PresenceController {
+	return &PresenceController{presenceService: presenceService}
+}
+
+// GetPresence returns whether a user is online and when they were last seen
+func (c *PresenceController) GetPresence(ctx *gin.Context) {
+	authUser, exists := ctx.Get("authUser")
+	if !exists {
+		dto.SendError(ctx, http.StatusUnauthorized, apperror.ErrForbidden.Message, apperror.ErrForbidden.Code)
+		return
+	}
+	requester := authUser.(auth.AuthUser)
+
+	presence, err := c.presenceService.GetPresence(ctx.Request.Context(), requester.ID, ctx.Param("id"), requester.Role == string(model.AdminRole))
+	if err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
+	}
+
+	dto.SendSuccess(ctx, http.StatusOK, "Presence retrieved successfully", presence)
+}
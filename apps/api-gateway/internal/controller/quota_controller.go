@@ -0,0 +1,75 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/apperror"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/auth"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type QuotaController struct {
+	quotaService service.QuotaService
+}
+
+func NewQuotaController(quotaService service.QuotaService) *QuotaController {
+	return &QuotaController{
+		quotaService: quotaService,
+	}
+}
+
+// GetUserQuota returns a user's daily chat limits and today's usage
+// GET /api/v1/admin/users/:user_id/quota
+func (c *QuotaController) GetUserQuota(ctx *gin.Context) {
+	quota, err := c.quotaService.GetUserQuota(ctx.Request.Context(), ctx.Param("user_id"))
+	if err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
+	}
+
+	dto.SendSuccess(ctx, http.StatusOK, "User quota retrieved successfully", quota)
+}
+
+// SetUserQuota overrides a user's default daily chat limits
+// PUT /api/v1/admin/users/:user_id/quota
+func (c *QuotaController) SetUserQuota(ctx *gin.Context) {
+	authUser, exists := ctx.Get("authUser")
+	if !exists {
+		dto.SendError(ctx, http.StatusUnauthorized, apperror.ErrForbidden.Message, apperror.ErrForbidden.Code)
+		return
+	}
+
+	var req dto.SetUserQuotaRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		dto.SendError(ctx, http.StatusBadRequest, apperror.Message(apperror.ErrBadRequest), apperror.ErrBadRequest.Code)
+		return
+	}
+
+	quota, err := c.quotaService.SetUserQuota(ctx.Request.Context(), authUser.(auth.AuthUser).ID, ctx.Param("user_id"), &req)
+	if err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
+	}
+
+	dto.SendSuccess(ctx, http.StatusOK, "User quota updated successfully", quota)
+}
+
+// ClearUserQuota removes a user's override so the default limits apply again
+// DELETE /api/v1/admin/users/:user_id/quota
+func (c *QuotaController) ClearUserQuota(ctx *gin.Context) {
+	authUser, exists := ctx.Get("authUser")
+	if !exists {
+		dto.SendError(ctx, http.StatusUnauthorized, apperror.ErrForbidden.Message, apperror.ErrForbidden.Code)
+		return
+	}
+
+	quota, err := c.quotaService.ClearUserQuota(ctx.Request.Context(), authUser.(auth.AuthUser).ID, ctx.Param("user_id"))
+	if err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
+	}
+
+	dto.SendSuccess(ctx, http.StatusOK, "User quota cleared successfully", quota)
+}
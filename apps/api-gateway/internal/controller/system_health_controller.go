@@ -0,0 +1,43 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type SystemHealthController struct {
+	systemHealthService service.SystemHealthService
+}
+
+func NewSystemHealthController(systemHealthService service.SystemHealthService) *SystemHealthController {
+	return &SystemHealthController{
+		systemHealthService: systemHealthService,
+	}
+}
+
+// Liveness tells the orchestrator the process is up and serving, without touching any dependency
+// GET /healthz
+func (c *SystemHealthController) Liveness(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"status": dto.SystemStatusOK})
+}
+
+// Readiness reports whether Mongo, Redis and the agent are reachable, with 503 while any is down so the instance
+// is taken out of rotation
+// GET /readyz
+func (c *SystemHealthController) Readiness(ctx *gin.Context) {
+	readiness := c.systemHealthService.GetReadiness()
+	status := http.StatusOK
+	if readiness.Status != dto.SystemStatusOK {
+		status = http.StatusServiceUnavailable
+	}
+	ctx.JSON(status, readiness)
+}
+
+// GetHealth reports the live status and latency of every dependency
+// GET /api/v1/admin/system/health
+func (c *SystemHealthController) GetHealth(ctx *gin.Context) {
+	dto.SendSuccess(ctx, http.StatusOK, "System health retrieved successfully", c.systemHealthService.GetHealth())
+}
@@ -0,0 +1,34 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/apperror"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type UITAnnouncementController struct {
+	uitAnnouncementService service.UITAnnouncementService
+}
+
+func NewUITAnnouncementController(uitAnnouncementService service.UITAnnouncementService) *UITAnnouncementController {
+	return &UITAnnouncementController{uitAnnouncementService: uitAnnouncementService}
+}
+
+// GetAnnouncements lists the notices crawled from official UIT pages, newest first
+// GET /api/v1/uit/announcements
+func (c *UITAnnouncementController) GetAnnouncements(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "20"))
+
+	announcements, err := c.uitAnnouncementService.GetAnnouncements(ctx.Request.Context(), page, pageSize)
+	if err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
+	}
+
+	dto.SendSuccess(ctx, http.StatusOK, "UIT announcements retrieved", announcements)
+}
@@ -0,0 +1,169 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/apperror"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/auth"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type UITController struct {
+	uitService service.UITService
+}
+
+func NewUITController(uitService service.UITService) *UITController {
+	return &UITController{uitService: uitService}
+}
+
+// GetSchedule returns the current user's weekly timetable from DAA
+// GET /api/v1/uit/schedule?refresh=true
+func (c *UITController) GetSchedule(ctx *gin.Context) {
+	authUser, exists := ctx.Get("authUser")
+	if !exists {
+		dto.SendError(ctx, http.StatusUnauthorized, apperror.ErrForbidden.Message, apperror.ErrForbidden.Code)
+		return
+	}
+
+	schedule, err := c.uitService.GetSchedule(ctx.Request.Context(), authUser.(auth.AuthUser).ID, ctx.Query("refresh") == "true")
+	if err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
+	}
+
+	dto.SendSuccess(ctx, http.StatusOK, "Schedule retrieved successfully", schedule)
+}
+
+// GetTrainingScore returns the current user's training score per term from DRL
+// GET /api/v1/uit/drl?refresh=true
+func (c *UITController) GetTrainingScore(ctx *gin.Context) {
+	authUser, exists := ctx.Get("authUser")
+	if !exists {
+		dto.SendError(ctx, http.StatusUnauthorized, apperror.ErrForbidden.Message, apperror.ErrForbidden.Code)
+		return
+	}
+
+	score, err := c.uitService.GetTrainingScore(ctx.Request.Context(), authUser.(auth.AuthUser).ID, ctx.Query("refresh") == "true")
+	if err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
+	}
+
+	dto.SendSuccess(ctx, http.StatusOK, "Training score retrieved successfully", score)
+}
+
+// GetTuition returns the current user's tuition fee status from DAA
+// GET /api/v1/uit/tuition?refresh=true
+func (c *UITController) GetTuition(ctx *gin.Context) {
+	authUser, exists := ctx.Get("authUser")
+	if !exists {
+		dto.SendError(ctx, http.StatusUnauthorized, apperror.ErrForbidden.Message, apperror.ErrForbidden.Code)
+		return
+	}
+
+	tuition, err := c.uitService.GetTuition(ctx.Request.Context(), authUser.(auth.AuthUser).ID, ctx.Query("refresh") == "true")
+	if err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
+	}
+
+	dto.SendSuccess(ctx, http.StatusOK, "Tuition retrieved successfully", tuition)
+}
+
+// GetOpenClasses lists the classes open for course registration, as seen by the current user
+// GET /api/v1/uit/registration/classes?subject=IT003&available=true&refresh=true
+func (c *UITController) GetOpenClasses(ctx *gin.Context) {
+	authUser, exists := ctx.Get("authUser")
+	if !exists {
+		dto.SendError(ctx, http.StatusUnauthorized, apperror.ErrForbidden.Message, apperror.ErrForbidden.Code)
+		return
+	}
+
+	var query dto.OpenClassesQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		dto.SendError(ctx, http.StatusBadRequest, apperror.Message(apperror.ErrBadRequest), apperror.ErrBadRequest.Code)
+		return
+	}
+
+	classes, err := c.uitService.GetOpenClasses(ctx.Request.Context(), authUser.(auth.AuthUser).ID, &query)
+	if err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
+	}
+
+	dto.SendSuccess(ctx, http.StatusOK, "Open classes retrieved successfully", classes)
+}
+
+// CheckConflicts checks planned classes against the current user's timetable and each other
+// POST /api/v1/uit/registration/conflicts
+func (c *UITController) CheckConflicts(ctx *gin.Context) {
+	authUser, exists := ctx.Get("authUser")
+	if !exists {
+		dto.SendError(ctx, http.StatusUnauthorized, apperror.ErrForbidden.Message, apperror.ErrForbidden.Code)
+		return
+	}
+
+	var req dto.CheckConflictsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		dto.SendError(ctx, http.StatusBadRequest, apperror.Message(apperror.ErrBadRequest), apperror.ErrBadRequest.Code)
+		return
+	}
+
+	result, err := c.uitService.CheckConflicts(ctx.Request.Context(), authUser.(auth.AuthUser).ID, &req)
+	if err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
+	}
+
+	dto.SendSuccess(ctx, http.StatusOK, "Conflicts checked successfully", result)
+}
+
+// GetCalendarFeed returns the current user's signed iCalendar feed URL
+// GET /api/v1/uit/calendar
+func (c *UITController) GetCalendarFeed(ctx *gin.Context) {
+	authUser, exists := ctx.Get("authUser")
+	if !exists {
+		dto.SendError(ctx, http.StatusUnauthorized, apperror.ErrForbidden.Message, apperror.ErrForbidden.Code)
+		return
+	}
+
+	feed, err := c.uitService.GetCalendarFeed(ctx.Request.Context(), authUser.(auth.AuthUser).ID)
+	if err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
+	}
+
+	dto.SendSuccess(ctx, http.StatusOK, "Calendar feed retrieved successfully", feed)
+}
+
+// RegenerateCalendarFeed revokes the current user's iCalendar feed URL and returns a new one
+// POST /api/v1/uit/calendar/regenerate
+func (c *UITController) RegenerateCalendarFeed(ctx *gin.Context) {
+	authUser, exists := ctx.Get("authUser")
+	if !exists {
+		dto.SendError(ctx, http.StatusUnauthorized, apperror.ErrForbidden.Message, apperror.ErrForbidden.Code)
+		return
+	}
+
+	feed, err := c.uitService.RegenerateCalendarFeed(ctx.Request.Context(), authUser.(auth.AuthUser).ID)
+	if err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
+	}
+
+	dto.SendSuccess(ctx, http.StatusOK, "Calendar feed regenerated successfully", feed)
+}
+
+// GetCalendar serves a user's timetable and exams as an iCalendar feed
+// GET /api/v1/uit/calendar/:user_id/schedule.ics?signature=...
+func (c *UITController) GetCalendar(ctx *gin.Context) {
+	calendar, err := c.uitService.GetCalendar(ctx.Request.Context(), ctx.Param("user_id"), ctx.Query("signature"))
+	if err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
+	}
+
+	ctx.Data(http.StatusOK, "text/calendar; charset=utf-8", calendar)
+}
@@ -0,0 +1,38 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/apperror"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type UsageController struct {
+	usageService service.UsageService
+}
+
+func NewUsageController(usageService service.UsageService) *UsageController {
+	return &UsageController{
+		usageService: usageService,
+	}
+}
+
+// GetUsage lists the heaviest users of a month with token usage, estimated cost and totals
+// GET /api/v1/admin/usage
+func (c *UsageController) GetUsage(ctx *gin.Context) {
+	var query dto.GetUsageQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		dto.SendError(ctx, http.StatusBadRequest, "Invalid query parameters", apperror.ErrBadRequest.Code)
+		return
+	}
+
+	usage, err := c.usageService.GetUsage(ctx.Request.Context(), &query)
+	if err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
+	}
+
+	dto.SendSuccess(ctx, http.StatusOK, "Usage retrieved successfully", usage)
+}
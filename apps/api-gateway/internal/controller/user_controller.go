@@ -1,12 +1,12 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/apperror"
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/auth"
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
-	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/cloudinary"
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/service"
 	"github.com/gin-gonic/gin"
 )
@@ -29,12 +29,18 @@ func (c *UserController) GetUsers(ctx *gin.Context) {
 		return
 	}
 
-	response, err := c.service.GetUsers(&query)
+	// Get requester ID (may be empty for unauthenticated requests)
+	requesterID := ""
+	if authUser, exists := ctx.Get("authUser"); exists {
+		requesterID = authUser.(auth.AuthUser).ID
+	}
+
+	response, err := c.service.GetUsers(ctx.Request.Context(), &query, requesterID)
 	if err != nil {
 		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
 		return
 	}
-	dto.SendSuccess(ctx, http.StatusOK, "Users retrieved successfully", response)
+	dto.SendPage(ctx, http.StatusOK, "Users retrieved successfully", response)
 }
 
 // GetUserByUsername retrieves a user's public profile by their username.
@@ -42,19 +48,17 @@ func (c *UserController) GetUserByUsername(ctx *gin.Context) {
 	username := ctx.Param("username")
 
 	// Get requester ID (may be empty for unauthenticated requests)
-	requesterID, _ := ctx.Get("user_id")
 	requesterIDStr := ""
-	if id, ok := requesterID.(string); ok {
-		requesterIDStr = id
+	if authUser, exists := ctx.Get("authUser"); exists {
+		requesterIDStr = authUser.(auth.AuthUser).ID
 	}
 
-	user, err := c.service.GetUserByUsername(username, requesterIDStr)
+	user, err := c.service.GetUserByUsername(ctx.Request.Context(), username, requesterIDStr)
 	if err != nil {
 		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
 		return
 	}
 
-	user.Email = "" // Hide email for public profile
 	dto.SendSuccess(ctx, http.StatusOK, "User profile retrieved successfully", user)
 }
 
@@ -66,7 +70,7 @@ func (c *UserController) GetMyProfile(ctx *gin.Context) {
 		return
 	}
 
-	user, err := c.service.GetUserByID(authUser.(auth.AuthUser).ID)
+	user, err := c.service.GetUserByID(ctx.Request.Context(), authUser.(auth.AuthUser).ID)
 	if err != nil {
 		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
 		return
@@ -75,6 +79,23 @@ func (c *UserController) GetMyProfile(ctx *gin.Context) {
 	dto.SendSuccess(ctx, http.StatusOK, "Profile retrieved successfully", user)
 }
 
+// DeactivateAccount temporarily deactivates the current user's account. Logging in again reactivates it.
+// POST /api/v1/users/me/deactivate
+func (c *UserController) DeactivateAccount(ctx *gin.Context) {
+	authUser, exists := ctx.Get("authUser")
+	if !exists {
+		dto.SendError(ctx, http.StatusUnauthorized, apperror.ErrForbidden.Message, apperror.ErrForbidden.Code)
+		return
+	}
+
+	if err := c.service.DeactivateAccount(ctx.Request.Context(), authUser.(auth.AuthUser).ID); err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
+	}
+
+	dto.SendSuccess(ctx, http.StatusOK, "Account deactivated successfully", nil)
+}
+
 // UpdateUser allows a user to update their own information (username).
 func (c *UserController) UpdateUser(ctx *gin.Context) {
 	authUser, exists := ctx.Get("authUser")
@@ -89,7 +110,7 @@ func (c *UserController) UpdateUser(ctx *gin.Context) {
 		return
 	}
 
-	updatedUser, err := c.service.UpdateUser(authUser.(auth.AuthUser).ID, &req)
+	updatedUser, err := c.service.UpdateUser(ctx.Request.Context(), authUser.(auth.AuthUser).ID, &req)
 	if err != nil {
 		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
 		return
@@ -106,21 +127,21 @@ func (c *UserController) UploadAvatar(ctx *gin.Context) {
 		return
 	}
 
-	form, err := ctx.MultipartForm()
+	// The route's body limit stops oversized uploads early
+	file, err := ctx.FormFile("avatar")
 	if err != nil {
-		dto.SendError(ctx, http.StatusBadRequest, "Invalid form data", "INVALID_FORM")
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			dto.SendError(ctx, apperror.StatusFromError(apperror.ErrAvatarTooLarge), apperror.Message(apperror.ErrAvatarTooLarge), apperror.ErrAvatarTooLarge.Code)
+			return
+		}
+		dto.SendError(ctx, apperror.StatusFromError(apperror.ErrAvatarRequired), apperror.Message(apperror.ErrAvatarRequired), apperror.ErrAvatarRequired.Code)
 		return
 	}
 
-	images, err := cloudinary.UploadImages(form.File["avatar"])
+	updatedUser, err := c.service.UploadAvatar(ctx.Request.Context(), authUser.(auth.AuthUser).ID, file)
 	if err != nil {
-		dto.SendError(ctx, http.StatusInternalServerError, "Failed to upload image", "UPLOAD_FAILED")
-		return
-	}
-
-	updatedUser, err := c.service.UpdateAvatar(authUser.(auth.AuthUser).ID, images[0].URL, images[0].PublicID)
-	if err != nil {
-		dto.SendError(ctx, http.StatusInternalServerError, "Failed to update avatar", "DB_UPDATE_FAILED")
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
 		return
 	}
 
@@ -135,7 +156,7 @@ func (c *UserController) DeleteAvatar(ctx *gin.Context) {
 		return
 	}
 
-	updatedUser, err := c.service.DeleteAvatar(authUser.(auth.AuthUser).ID)
+	updatedUser, err := c.service.DeleteAvatar(ctx.Request.Context(), authUser.(auth.AuthUser).ID)
 	if err != nil {
 		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
 		return
@@ -157,7 +178,7 @@ func (c *UserController) ChangePassword(ctx *gin.Context) {
 		return
 	}
 
-	err := c.service.ChangePassword(authUser.(auth.AuthUser).ID, req.OldPassword, req.NewPassword)
+	err := c.service.ChangePassword(ctx.Request.Context(), authUser.(auth.AuthUser).ID, req.OldPassword, req.NewPassword)
 	if err != nil {
 		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
 		return
@@ -174,7 +195,7 @@ func (c *UserController) GetSettings(ctx *gin.Context) {
 		return
 	}
 
-	settings, err := c.service.GetSettings(authUser.(auth.AuthUser).ID)
+	settings, err := c.service.GetSettings(ctx.Request.Context(), authUser.(auth.AuthUser).ID)
 	if err != nil {
 		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
 		return
@@ -197,7 +218,7 @@ func (c *UserController) UpdateSettings(ctx *gin.Context) {
 		return
 	}
 
-	settings, err := c.service.UpdateSettings(authUser.(auth.AuthUser).ID, &req)
+	settings, err := c.service.UpdateSettings(ctx.Request.Context(), authUser.(auth.AuthUser).ID, &req)
 	if err != nil {
 		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
 		return
@@ -206,6 +227,80 @@ func (c *UserController) UpdateSettings(ctx *gin.Context) {
 	dto.SendSuccess(ctx, http.StatusOK, "Settings updated successfully", settings)
 }
 
+// GetProfileCompleteness scores how complete the current user's profile is, with hints for missing items
+func (c *UserController) GetProfileCompleteness(ctx *gin.Context) {
+	authUser, exists := ctx.Get("authUser")
+	if !exists {
+		dto.SendError(ctx, http.StatusUnauthorized, apperror.ErrForbidden.Message, apperror.ErrForbidden.Code)
+		return
+	}
+
+	completeness, err := c.service.GetProfileCompleteness(ctx.Request.Context(), authUser.(auth.AuthUser).ID)
+	if err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
+	}
+
+	dto.SendSuccess(ctx, http.StatusOK, "Profile completeness retrieved successfully", completeness)
+}
+
+// GetBlockedTopics lists the topics the assistant must not bring up for the current user
+func (c *UserController) GetBlockedTopics(ctx *gin.Context) {
+	authUser, exists := ctx.Get("authUser")
+	if !exists {
+		dto.SendError(ctx, http.StatusUnauthorized, apperror.ErrForbidden.Message, apperror.ErrForbidden.Code)
+		return
+	}
+
+	topics, err := c.service.GetBlockedTopics(ctx.Request.Context(), authUser.(auth.AuthUser).ID)
+	if err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
+	}
+
+	dto.SendSuccess(ctx, http.StatusOK, "Blocked topics retrieved successfully", topics)
+}
+
+// AddBlockedTopic adds a topic the assistant must not bring up for the current user
+func (c *UserController) AddBlockedTopic(ctx *gin.Context) {
+	authUser, exists := ctx.Get("authUser")
+	if !exists {
+		dto.SendError(ctx, http.StatusUnauthorized, apperror.ErrForbidden.Message, apperror.ErrForbidden.Code)
+		return
+	}
+
+	var req dto.AddBlockedTopicRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		dto.SendError(ctx, http.StatusBadRequest, apperror.Message(apperror.ErrBadRequest), apperror.ErrBadRequest.Code)
+		return
+	}
+
+	topics, err := c.service.AddBlockedTopic(ctx.Request.Context(), authUser.(auth.AuthUser).ID, req.Topic)
+	if err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
+	}
+
+	dto.SendSuccess(ctx, http.StatusCreated, "Blocked topic added successfully", topics)
+}
+
+// RemoveBlockedTopic removes a topic from the current user's blocked topics
+func (c *UserController) RemoveBlockedTopic(ctx *gin.Context) {
+	authUser, exists := ctx.Get("authUser")
+	if !exists {
+		dto.SendError(ctx, http.StatusUnauthorized, apperror.ErrForbidden.Message, apperror.ErrForbidden.Code)
+		return
+	}
+
+	topics, err := c.service.RemoveBlockedTopic(ctx.Request.Context(), authUser.(auth.AuthUser).ID, ctx.Param("topic"))
+	if err != nil {
+		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		return
+	}
+
+	dto.SendSuccess(ctx, http.StatusOK, "Blocked topic removed successfully", topics)
+}
+
 // CheckUsername checks if a username is available for registration.
 // This is a public endpoint for real-time username availability checking.
 func (c *UserController) CheckUsername(ctx *gin.Context) {
@@ -218,7 +313,7 @@ func (c *UserController) CheckUsername(ctx *gin.Context) {
 		return
 	}
 
-	available, err := c.service.CheckUsernameAvailability(req.Username)
+	available, err := c.service.CheckUsernameAvailability(ctx.Request.Context(), req.Username)
 	if err != nil {
 		dto.SendError(ctx, http.StatusInternalServerError, apperror.Message(apperror.ErrInternal), apperror.ErrInternal.Code)
 		return
@@ -231,7 +326,7 @@ func (c *UserController) CheckUsername(ctx *gin.Context) {
 
 func (c *UserController) DeleteUser(ctx *gin.Context) {
 	userID := ctx.Param("id")
-	err := c.service.DeleteUser(userID)
+	err := c.service.DeleteUser(ctx.Request.Context(), userID)
 	if err != nil {
 		dto.SendError(ctx, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
 		return
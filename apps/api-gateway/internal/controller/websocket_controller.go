@@ -1,15 +1,27 @@
 package controller
 
 import (
-	"log"
+	"encoding/json"
+	"errors"
 	"net/http"
+	"time"
 
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/apperror"
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/auth"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/logger"
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/ws"
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
 )
 
+// authFrameMaxSize bounds the first message, which carries the access token
+const authFrameMaxSize = 4096
+
+var errAuthFrameMissing = errors.New("first message must be an auth frame")
+
 var upgrader = websocket.Upgrader{
 	ReadBufferSize:  1024,
 	WriteBufferSize: 1024,
@@ -29,27 +41,111 @@ func NewWebSocketController(hub *ws.Hub) *WebSocketController {
 }
 
 // HandleConnections handles the WebSocket connection requests.
+// Connections authenticated by the deprecated query-string token are registered immediately;
+// all others must send an auth frame as their first message.
 func (c *WebSocketController) HandleConnections(ctx *gin.Context) {
-	authUser, exists := ctx.Get("authUser")
-	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
-		return
-	}
-	userID := authUser.(auth.AuthUser).ID
-
 	conn, err := upgrader.Upgrade(ctx.Writer, ctx.Request, nil)
 	if err != nil {
-		log.Printf("Failed to upgrade connection for user %s: %v", userID, err)
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Failed to upgrade WebSocket"})
+		logger.FromContext(ctx.Request.Context()).Warn("Failed to upgrade WebSocket connection", "error", err)
 		return
 	}
 
+	var user auth.AuthUser
+	var lastAckAt *time.Time
+	if authUser, exists := ctx.Get("authUser"); exists {
+		user = authUser.(auth.AuthUser)
+		if raw := ctx.Query("last_ack_at"); raw != "" {
+			if t, err := time.Parse(time.RFC3339Nano, raw); err == nil {
+				lastAckAt = &t
+			}
+		}
+	} else {
+		user, lastAckAt, err = authenticateFirstMessage(conn)
+		if err != nil {
+			rejectConnection(conn, err)
+			return
+		}
+	}
+
 	// Create a new client instance.
-	client := ws.NewClient(c.wsHub, conn, userID)
+	client := ws.NewClient(c.wsHub, conn, user.ID, user.Role == string(model.AdminRole))
+	client.LastAckAt = lastAckAt
 
 	// Register the client with the hub.
-	c.wsHub.RegisterClient(client)
+	if !c.wsHub.RegisterClient(client) {
+		rejectShutdown(conn)
+		return
+	}
 
 	// Start the client's processing goroutines.
 	client.Serve()
 }
+
+// authenticateFirstMessage waits for the auth frame and validates its token.
+// Also returns the client's last acknowledged notification time, if it sent one.
+func authenticateFirstMessage(conn *websocket.Conn) (auth.AuthUser, *time.Time, error) {
+	timeout := time.Duration(config.Cfg.WebSocket.AuthTimeoutSeconds) * time.Second
+
+	conn.SetReadLimit(authFrameMaxSize)
+	if err := conn.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+		return auth.AuthUser{}, nil, err
+	}
+
+	var msg dto.IncomingWebSocketMessage
+	if err := conn.ReadJSON(&msg); err != nil {
+		return auth.AuthUser{}, nil, errAuthFrameMissing
+	}
+	if msg.Type != dto.Auth {
+		return auth.AuthUser{}, nil, errAuthFrameMissing
+	}
+
+	var payload dto.AuthPayload
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil || payload.Token == "" {
+		return auth.AuthUser{}, nil, errAuthFrameMissing
+	}
+
+	user, err := auth.ParseAccessToken(payload.Token)
+	if err != nil {
+		return auth.AuthUser{}, nil, apperror.ErrInvalidToken
+	}
+
+	ack, _ := json.Marshal(dto.WebSocketMessage{
+		Type:    dto.ACKMessage,
+		Payload: dto.AckPayload{RequestID: msg.RequestID, Type: dto.Auth},
+	})
+	conn.SetWriteDeadline(time.Now().Add(time.Duration(config.Cfg.WebSocket.WriteTimeoutSeconds) * time.Second))
+	if err := conn.WriteMessage(websocket.TextMessage, ack); err != nil {
+		return auth.AuthUser{}, nil, err
+	}
+
+	return user, payload.LastAckAt, nil
+}
+
+// rejectShutdown closes a connection that arrived while the hub was stopping
+func rejectShutdown(conn *websocket.Conn) {
+	defer conn.Close()
+
+	deadline := time.Now().Add(time.Duration(config.Cfg.WebSocket.WriteTimeoutSeconds) * time.Second)
+	_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseServiceRestart, "server restarting, please reconnect"), deadline)
+}
+
+// rejectConnection sends an error frame and a policy-violation close frame, then closes the connection
+func rejectConnection(conn *websocket.Conn, err error) {
+	defer conn.Close()
+
+	code := dto.WSErrAuthRequired
+	message := "Cần gửi tin nhắn xác thực trước khi sử dụng kết nối"
+	if errors.Is(err, apperror.ErrInvalidToken) {
+		code = apperror.ErrInvalidToken.Code
+		message = apperror.ErrInvalidToken.Message
+	}
+
+	deadline := time.Now().Add(time.Duration(config.Cfg.WebSocket.WriteTimeoutSeconds) * time.Second)
+	frame, _ := json.Marshal(dto.WebSocketMessage{
+		Type:    dto.ErrorMessage,
+		Payload: dto.ErrorPayload{ErrorCode: &code, ErrorMsg: message},
+	})
+	conn.SetWriteDeadline(deadline)
+	_ = conn.WriteMessage(websocket.TextMessage, frame)
+	_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.ClosePolicyViolation, code), deadline)
+}
@@ -0,0 +1,37 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+)
+
+// AdminStatsQuery is the query for the admin statistics dashboard
+type AdminStatsQuery struct {
+	Days int `form:"days"` // Length of the per-day chat series, default 30, max 90
+}
+
+// AdminStatsResponse is the admin statistics dashboard data
+type AdminStatsResponse struct {
+	Users       UserStats `json:"users"`
+	Chat        ChatStats `json:"chat"`
+	GeneratedAt time.Time `json:"generated_at"`
+}
+
+// UserStats summarizes user growth and activity
+type UserStats struct {
+	Total         int64   `json:"total"`
+	NewLast7Days  int64   `json:"new_last_7_days"`
+	NewLast30Days int64   `json:"new_last_30_days"`
+	DAU           int64   `json:"dau"` // Logged in or refreshed a token in the last 24 hours
+	WAU           int64   `json:"wau"` // Same, over the last 7 days
+	Verified      int64   `json:"verified"`
+	VerifiedRatio float64 `json:"verified_ratio"` // Verified / Total, 0 when there are no users
+	Banned        int64   `json:"banned"`
+}
+
+// ChatStats holds per-day chat activity, one entry per day including days without activity
+type ChatStats struct {
+	SessionsPerDay []model.DailyCount `json:"sessions_per_day"`
+	MessagesPerDay []model.DailyCount `json:"messages_per_day"` // User messages only
+}
@@ -8,10 +8,90 @@ type BanUserRequest struct {
 	BanUntil *time.Time `json:"ban_until,omitempty"` // null = permanent ban
 }
 
-// GetUsersAdminQuery is the query for admin to get all users
+// GetUsersAdminQuery is the query for admin to get all users.
+// CreatedFrom and CreatedTo are inclusive (YYYY-MM-DD).
 type GetUsersAdminQuery struct {
-	Username string `form:"username"`
-	Status   string `form:"status"` // all, active, banned, deleted
-	Page     int    `form:"page"`
-	PageSize int    `form:"page_size"`
+	Username    string `form:"username"` // Prefix match
+	Email       string `form:"email"`    // Prefix match
+	Status      string `form:"status"`   // all, active, banned, deleted
+	Provider    string `form:"provider" binding:"omitempty,oneof=local google"`
+	Verified    *bool  `form:"verified"`
+	CreatedFrom string `form:"created_from"`
+	CreatedTo   string `form:"created_to"`
+	ActiveDays  int    `form:"active_days" binding:"omitempty,min=1,max=365"`                          // Only users who logged in within the last N days
+	SortBy      string `form:"sort_by" binding:"omitempty,oneof=created_at last_login username email"` // Default created_at
+	SortOrder   string `form:"sort_order" binding:"omitempty,oneof=asc desc"`                          // Default desc
+	Cursor      string `form:"cursor"`                                                                 // next_cursor of the previous page, takes precedence over Page
+	Page        int    `form:"page"`
+	PageSize    int    `form:"page_size"`
+}
+
+// AdminUpdateUserRequest lets an admin fix a user's account for support cases; only provided fields change
+type AdminUpdateUserRequest struct {
+	Username      string `json:"username" binding:"omitempty,min=3,max=30"`
+	IsVerified    *bool  `json:"is_verified"`
+	ResetSettings bool   `json:"reset_settings"` // Restore default settings and notification preferences
+}
+
+// UpdateUserRoleRequest promotes a user to admin or demotes an admin to user
+type UpdateUserRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=user admin"`
+}
+
+// AddUserNoteRequest appends an internal support note to a user account
+type AddUserNoteRequest struct {
+	Content string `json:"content" binding:"required,max=2000"`
+}
+
+// Bulk user actions
+const (
+	BulkActionBan     = "ban"
+	BulkActionUnban   = "unban"
+	BulkActionDelete  = "delete"
+	BulkActionRestore = "restore"
+)
+
+// BulkUserActionRequest applies one admin action to many users at once
+type BulkUserActionRequest struct {
+	Action   string     `json:"action" binding:"required,oneof=ban unban delete restore"`
+	UserIDs  []string   `json:"user_ids" binding:"required,min=1,max=500,dive,required"`
+	Reason   string     `json:"reason" binding:"max=500"` // Required for ban
+	BanUntil *time.Time `json:"ban_until,omitempty"`      // Ban only, null = permanent ban
+}
+
+// BulkUserActionResult is the outcome of a bulk action for one user
+type BulkUserActionResult struct {
+	UserID    string `json:"user_id"`
+	Success   bool   `json:"success"`
+	ErrorCode string `json:"error_code,omitempty"`
+	Message   string `json:"message,omitempty"`
+}
+
+// BulkUserActionResponse reports the outcome of a bulk action per user
+type BulkUserActionResponse struct {
+	Action    string                 `json:"action"`
+	Succeeded int                    `json:"succeeded"`
+	Failed    int                    `json:"failed"`
+	Results   []BulkUserActionResult `json:"results"`
+}
+
+// UserPurgeReport lists what was permanently erased for a user
+type UserPurgeReport struct {
+	UserID                        string `json:"user_id"`
+	SessionsDeleted               int64  `json:"sessions_deleted"`
+	MessagesDeleted               int64  `json:"messages_deleted"`
+	ReportsDeleted                int64  `json:"reports_deleted"`
+	NotificationsDeleted          int64  `json:"notifications_deleted"`
+	ScheduledNotificationsDeleted int64  `json:"scheduled_notifications_deleted"`
+	EmailVerificationsDeleted     bool   `json:"email_verifications_deleted"`
+	UsageRecordsDeleted           int64  `json:"usage_records_deleted"`
+	DataExportsDeleted            int64  `json:"data_exports_deleted"`
+	EmailDeliveriesDeleted        int64  `json:"email_deliveries_deleted"`
+	AvatarDeleted                 bool   `json:"avatar_deleted"`
+	RedisKeysDeleted              int64  `json:"redis_keys_deleted"` // Synced portal cookies, cached timetable and presence
+}
+
+// EraseUserRequest confirms the permanent erasure of a user
+type EraseUserRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"` // Recorded in the audit log
 }
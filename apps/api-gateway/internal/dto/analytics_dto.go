@@ -0,0 +1,19 @@
+package dto
+
+import "github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+
+// ChatAnalyticsQuery is the date range for chat analytics, both ends inclusive (YYYY-MM-DD).
+// Defaults to the last 30 days.
+type ChatAnalyticsQuery struct {
+	From string `form:"from"`
+	To   string `form:"to"`
+}
+
+// ChatAnalyticsResponse is the chat analytics for a date range
+type ChatAnalyticsResponse struct {
+	From      string                 `json:"from"`
+	To        string                 `json:"to"`
+	Daily     []model.ChatDailyStats `json:"daily"` // One entry per day, including days without activity
+	ToolUsage []model.NamedCount     `json:"tool_usage"`
+	TopTopics []model.NamedCount     `json:"top_topics"` // Most frequent keywords in user questions
+}
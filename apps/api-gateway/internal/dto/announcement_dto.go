@@ -0,0 +1,110 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+)
+
+// AnnouncementSegmentRequest selects the users an announcement is delivered to. Empty fields match everyone.
+type AnnouncementSegmentRequest struct {
+	Roles                  []model.Role `json:"roles" binding:"omitempty,dive,oneof=user admin"`
+	Languages              []string     `json:"languages" binding:"omitempty,dive,oneof=vi en"`
+	VerifiedOnly           bool         `json:"verified_only"`
+	FeatureSubscribersOnly bool         `json:"feature_subscribers_only"`
+}
+
+// CreateAnnouncementRequest is the request to create a draft announcement
+type CreateAnnouncementRequest struct {
+	Title       string                     `json:"title" binding:"required,max=200"`
+	Message     string                     `json:"message" binding:"required,max=2000"`
+	Link        string                     `json:"link" binding:"omitempty,max=500"`
+	Segment     AnnouncementSegmentRequest `json:"segment"`
+	BroadcastWS bool                       `json:"broadcast_ws"`
+}
+
+// UpdateAnnouncementRequest is the request to update a draft announcement
+type UpdateAnnouncementRequest struct {
+	Title       *string                     `json:"title" binding:"omitempty,max=200"`
+	Message     *string                     `json:"message" binding:"omitempty,max=2000"`
+	Link        *string                     `json:"link" binding:"omitempty,max=500"`
+	Segment     *AnnouncementSegmentRequest `json:"segment"`
+	BroadcastWS *bool                       `json:"broadcast_ws"`
+}
+
+// AnnouncementResponse is the admin view of an announcement
+type AnnouncementResponse struct {
+	ID             string                    `json:"id"`
+	Title          string                    `json:"title"`
+	Message        string                    `json:"message"`
+	Link           string                    `json:"link,omitempty"`
+	Segment        model.AnnouncementSegment `json:"segment"`
+	BroadcastWS    bool                      `json:"broadcast_ws"`
+	Status         model.AnnouncementStatus  `json:"status"`
+	RecipientCount int64                     `json:"recipient_count"`
+	SentAt         *time.Time                `json:"sent_at,omitempty"`
+	CreatedBy      string                    `json:"created_by"`
+	CreatedAt      time.Time                 `json:"created_at"`
+	UpdatedAt      time.Time                 `json:"updated_at"`
+}
+
+// PaginatedAnnouncementsResponse is a paginated list of announcements
+type PaginatedAnnouncementsResponse struct {
+	Announcements []AnnouncementResponse `json:"announcements"`
+	Pagination    Pagination             `json:"pagination"`
+}
+
+// AnnouncementBanner is the WebSocket payload for a live announcement banner
+type AnnouncementBanner struct {
+	ID      string `json:"id"`
+	Title   string `json:"title"`
+	Message string `json:"message"`
+	Link    string `json:"link,omitempty"`
+}
+
+// ToModel converts the segment request to the stored segment
+func (r AnnouncementSegmentRequest) ToModel() model.AnnouncementSegment {
+	return model.AnnouncementSegment{
+		Roles:                  r.Roles,
+		Languages:              r.Languages,
+		VerifiedOnly:           r.VerifiedOnly,
+		FeatureSubscribersOnly: r.FeatureSubscribersOnly,
+	}
+}
+
+// FromAnnouncement converts a model.Announcement to an AnnouncementResponse DTO
+func FromAnnouncement(a *model.Announcement) AnnouncementResponse {
+	return AnnouncementResponse{
+		ID:             a.ID.Hex(),
+		Title:          a.Title,
+		Message:        a.Message,
+		Link:           a.Link,
+		Segment:        a.Segment,
+		BroadcastWS:    a.BroadcastWS,
+		Status:         a.Status,
+		RecipientCount: a.RecipientCount,
+		SentAt:         a.SentAt,
+		CreatedBy:      a.CreatedBy.Hex(),
+		CreatedAt:      a.CreatedAt,
+		UpdatedAt:      a.UpdatedAt,
+	}
+}
+
+// FromAnnouncements converts a slice of model.Announcement to AnnouncementResponse DTOs
+func FromAnnouncements(announcements []*model.Announcement) []AnnouncementResponse {
+	responses := make([]AnnouncementResponse, len(announcements))
+	for i, a := range announcements {
+		responses[i] = FromAnnouncement(a)
+	}
+	return responses
+}
+
+// NewAnnouncementBanner builds the WebSocket banner payload for an announcement
+func NewAnnouncementBanner(a *model.Announcement) *AnnouncementBanner {
+	return &AnnouncementBanner{
+		ID:      a.ID.Hex(),
+		Title:   a.Title,
+		Message: a.Message,
+		Link:    a.Link,
+	}
+}
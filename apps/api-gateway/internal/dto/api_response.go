@@ -1,19 +1,52 @@
 package dto
 
-import "github.com/gin-gonic/gin"
+import (
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/requestid"
+	"github.com/gin-gonic/gin"
+)
 
 type ApiResponse struct {
-	Success   bool        `json:"success"`
-	Message   string      `json:"message"`
-	Data      interface{} `json:"data,omitempty"` // omitempty: nếu data là nil thì không hiển thị
-	ErrorCode string      `json:"error_code,omitempty"`
+	Success    bool        `json:"success"`
+	Message    string      `json:"message"`
+	Data       interface{} `json:"data,omitempty"`       // omitempty: nếu data là nil thì không hiển thị
+	Pagination *PageInfo   `json:"pagination,omitempty"` // Chỉ có ở danh sách phân trang gửi bằng SendPage
+	ErrorCode  string      `json:"error_code,omitempty"`
+	RequestID  string      `json:"request_id,omitempty"` // Để đối chiếu với log khi người dùng báo lỗi
 }
 
+// Paged is a paginated list response that SendPage splits into its items and pagination metadata
+type Paged interface {
+	PageItems() interface{}
+	PageInfo() PageInfo
+}
+
+// LegacyListKey marks requests on an API version whose clients expect a paginated list's whole DTO as data
+const LegacyListKey = "legacyListShape"
+
 func SendSuccess(c *gin.Context, statusCode int, message string, data interface{}) {
 	c.JSON(statusCode, ApiResponse{
-		Success: true,
-		Message: message,
-		Data:    data,
+		Success:   true,
+		Message:   message,
+		Data:      data,
+		RequestID: requestid.FromContext(c.Request.Context()),
+	})
+}
+
+// SendPage sends a paginated list with its items as data and the pagination metadata next to them.
+// Requests marked with LegacyListKey get the list DTO as data instead, pagination fields included.
+func SendPage(c *gin.Context, statusCode int, message string, page Paged) {
+	if c.GetBool(LegacyListKey) {
+		SendSuccess(c, statusCode, message, page)
+		return
+	}
+
+	info := page.PageInfo()
+	c.JSON(statusCode, ApiResponse{
+		Success:    true,
+		Message:    message,
+		Data:       page.PageItems(),
+		Pagination: &info,
+		RequestID:  requestid.FromContext(c.Request.Context()),
 	})
 }
 
@@ -22,5 +55,6 @@ func SendError(c *gin.Context, statusCode int, message string, errorCode string)
 		Success:   false,
 		Message:   message,
 		ErrorCode: errorCode,
+		RequestID: requestid.FromContext(c.Request.Context()),
 	})
 }
@@ -29,6 +29,11 @@ type CompleteGoogleSetupRequest struct {
 	SetupToken string `json:"setup_token" binding:"required"`
 	Username   string `json:"username" binding:"required,min=3,max=20"`
 }
+
+type CompleteAccountLinkRequest struct {
+	LinkToken string `json:"link_token" binding:"required"`
+	Password  string `json:"password" binding:"required"`
+}
 type RefreshRequest struct {
 	RefreshToken string `json:"refresh_token" binding:"required"`
 }
@@ -1,6 +1,7 @@
 package dto
 
 import (
+	"strings"
 	"time"
 
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
@@ -12,7 +13,8 @@ import (
 // ChatRequest for sending a chat message (with optional session ID)
 type ChatRequest struct {
 	Message   string  `json:"message" binding:"required,min=1,max=5000"`
-	SessionID *string `json:"session_id" binding:"omitempty"` // If nil, creates new session
+	SessionID *string `json:"session_id" binding:"omitempty"`           // If nil, creates new session
+	Language  *string `json:"language" binding:"omitempty,oneof=vi en"` // Optional per-session language override
 }
 
 // CreateChatSessionRequest for creating a new chat session
@@ -30,37 +32,39 @@ type UpdateSessionTitleRequest struct {
 	Title string `json:"title" binding:"required,min=1,max=100"`
 }
 
-// GetSessionsQuery for querying chat sessions with pagination
-type GetSessionsQuery struct {
-	Page     int `form:"page" binding:"omitempty,min=1"`
-	PageSize int `form:"page_size" binding:"omitempty,min=1,max=100"`
+// UpdateSessionLanguageRequest for setting or clearing the session language override
+type UpdateSessionLanguageRequest struct {
+	Language string `json:"language" binding:"omitempty,oneof=vi en"` // Empty = follow user settings
 }
 
-// ToFindOptions converts query to repo.FindOptions
-func (q *GetSessionsQuery) ToFindOptions() *repo.FindOptions {
-	page := q.Page
-	if page < 1 {
-		page = 1
-	}
+// GetSessionsQuery for querying chat sessions with cursor pagination
+type GetSessionsQuery struct {
+	Cursor   string `form:"cursor"` // next_cursor of the previous page, empty for the first page
+	PageSize int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
 
+// ToPage converts the query to a repo.Page, most recently updated first
+func (q *GetSessionsQuery) ToPage() (repo.Page, error) {
 	pageSize := q.PageSize
 	if pageSize < 1 {
 		pageSize = 20
 	}
-	if pageSize > 100 {
-		pageSize = 100
-	}
-
-	return &repo.FindOptions{
-		Skip:  int64((page - 1) * pageSize),
-		Limit: int64(pageSize),
-		Sort:  map[string]int{"updated_at": -1}, // Most recent first
-	}
+	return cursorPage("updated_at", -1, q.Cursor, pageSize)
 }
 
-// GetMessagesQuery for querying messages with pagination
+// GetMessagesQuery for querying messages with cursor pagination, pages go back in time
 type GetMessagesQuery struct {
-	Limit int `form:"limit" binding:"omitempty,min=1,max=100"` // Last N messages
+	Cursor string `form:"cursor"`                                  // next_cursor of the previous page, empty for the latest messages
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"` // Messages per page, default 50
+}
+
+// ToPage converts the query to a repo.Page, newest first
+func (q *GetMessagesQuery) ToPage() (repo.Page, error) {
+	limit := q.Limit
+	if limit < 1 {
+		limit = 50
+	}
+	return cursorPage("created_at", -1, q.Cursor, limit)
 }
 
 // --- Response DTOs ---
@@ -75,10 +79,25 @@ type ChatResponse struct {
 type ChatSessionResponse struct {
 	ID        string    `json:"id"`
 	Title     string    `json:"title"`
+	Language  string    `json:"language,omitempty"` // Session language override, empty = follow user settings
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// LastMessagePreview is a short snippet of the latest message in a session
+type LastMessagePreview struct {
+	Role      string    `json:"role"` // "user" | "assistant"
+	Snippet   string    `json:"snippet"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ChatSessionSummaryResponse is a session list item with preview data for the sidebar
+type ChatSessionSummaryResponse struct {
+	ChatSessionResponse
+	LastMessage  *LastMessagePreview `json:"last_message,omitempty"`
+	MessageCount int64               `json:"message_count"`
+}
+
 // SessionResponse is an alias for ChatSessionResponse (for backward compatibility)
 type SessionResponse = ChatSessionResponse
 
@@ -91,6 +110,40 @@ type ChatMessageResponse struct {
 	CreatedAt time.Time      `json:"created_at"`
 }
 
+// PaginatedSessionsResponse is a cursor-paginated list of sessions, most recently updated first.
+// Pass NextCursor as the cursor query parameter to load the next page; it is empty on the last page.
+type PaginatedSessionsResponse struct {
+	Sessions   []ChatSessionSummaryResponse `json:"sessions"`
+	NextCursor string                       `json:"next_cursor,omitempty"`
+	HasMore    bool                         `json:"has_more"`
+}
+
+// PageItems and PageInfo implement Paged
+func (r PaginatedSessionsResponse) PageItems() interface{} {
+	return r.Sessions
+}
+
+func (r PaginatedSessionsResponse) PageInfo() PageInfo {
+	return cursorPageInfo(r.NextCursor, r.HasMore)
+}
+
+// PaginatedMessagesResponse is a page of a session's messages in chronological order.
+// NextCursor loads the older messages before this page; it is empty once the first message is reached.
+type PaginatedMessagesResponse struct {
+	Messages   []ChatMessageResponse `json:"messages"`
+	NextCursor string                `json:"next_cursor,omitempty"`
+	HasMore    bool                  `json:"has_more"`
+}
+
+// PageItems and PageInfo implement Paged
+func (r PaginatedMessagesResponse) PageItems() interface{} {
+	return r.Messages
+}
+
+func (r PaginatedMessagesResponse) PageInfo() PageInfo {
+	return cursorPageInfo(r.NextCursor, r.HasMore)
+}
+
 // SourceInfo represents a RAG source citation
 type SourceInfo struct {
 	Title   string `json:"title"`
@@ -98,15 +151,40 @@ type SourceInfo struct {
 	Snippet string `json:"snippet,omitempty"` // Truncated content
 }
 
-// PaginatedSessionsResponse for paginated session list
-type PaginatedSessionsResponse struct {
-	Sessions   []ChatSessionResponse `json:"sessions"`
-	Pagination Pagination            `json:"pagination"`
+// AdminChatSessionResponse is a user's chat session with its messages, as seen by support staff
+type AdminChatSessionResponse struct {
+	UserID   string                `json:"user_id"`
+	Session  ChatSessionResponse   `json:"session"`
+	Messages []ChatMessageResponse `json:"messages"`
 }
 
-// PaginatedMessagesResponse for paginated message list
-type PaginatedMessagesResponse struct {
-	Messages []ChatMessageResponse `json:"messages"`
+// SearchChatMessagesRequest searches all chat transcripts for an abuse investigation.
+// From and To are inclusive (YYYY-MM-DD). The reason is recorded in the audit log.
+type SearchChatMessagesRequest struct {
+	Query    string            `json:"query" binding:"required,min=2,max=200"` // Case-insensitive substring
+	Reason   string            `json:"reason" binding:"required,max=500"`
+	UserID   string            `json:"user_id"`
+	Role     model.MessageRole `json:"role" binding:"omitempty,oneof=user assistant"`
+	From     string            `json:"from"`
+	To       string            `json:"to"`
+	Context  *int              `json:"context" binding:"omitempty,min=0,max=5"` // Messages shown before and after each match, default 2
+	Page     int               `json:"page" binding:"omitempty,min=1"`
+	PageSize int               `json:"page_size" binding:"omitempty,min=1,max=50"`
+}
+
+// ChatSearchMatch is a message matching a transcript search, with the messages around it
+type ChatSearchMatch struct {
+	UserID  string                `json:"user_id,omitempty"`
+	Session *ChatSessionResponse  `json:"session,omitempty"`
+	Message ChatMessageResponse   `json:"message"`
+	Before  []ChatMessageResponse `json:"before"`
+	After   []ChatMessageResponse `json:"after"`
+}
+
+// ChatSearchResponse is a page of transcript search matches, newest first
+type ChatSearchResponse struct {
+	Matches    []ChatSearchMatch `json:"matches"`
+	Pagination Pagination        `json:"pagination"`
 }
 
 // --- Converter Functions ---
@@ -120,6 +198,7 @@ func FromChatSession(s *model.ChatSession) *ChatSessionResponse {
 	return &ChatSessionResponse{
 		ID:        s.ID.Hex(),
 		Title:     s.Title,
+		Language:  s.Language,
 		CreatedAt: s.CreatedAt,
 		UpdatedAt: s.UpdatedAt,
 	}
@@ -137,6 +216,40 @@ func FromChatSessions(sessions []*model.ChatSession) []ChatSessionResponse {
 	return responses
 }
 
+// FromChatSessionSummary converts model.ChatSessionSummary to ChatSessionSummaryResponse
+func FromChatSessionSummary(s *model.ChatSessionSummary) *ChatSessionSummaryResponse {
+	if s == nil {
+		return nil
+	}
+
+	resp := &ChatSessionSummaryResponse{
+		ChatSessionResponse: *FromChatSession(&s.ChatSession),
+		MessageCount:        s.MessageCount,
+	}
+
+	if s.LastMessage != nil {
+		resp.LastMessage = &LastMessagePreview{
+			Role:      string(s.LastMessage.Role),
+			Snippet:   GenerateMessageSnippet(s.LastMessage.Content),
+			CreatedAt: s.LastMessage.CreatedAt,
+		}
+	}
+
+	return resp
+}
+
+// FromChatSessionSummaries converts multiple session summaries to response DTOs
+func FromChatSessionSummaries(summaries []*model.ChatSessionSummary) []ChatSessionSummaryResponse {
+	responses := make([]ChatSessionSummaryResponse, len(summaries))
+	for i, s := range summaries {
+		resp := FromChatSessionSummary(s)
+		if resp != nil {
+			responses[i] = *resp
+		}
+	}
+	return responses
+}
+
 // FromChatMessage converts model.ChatMessage to ChatMessageResponse
 func FromChatMessage(m *model.ChatMessage) *ChatMessageResponse {
 	if m == nil {
@@ -188,3 +301,19 @@ func GenerateSessionTitle(firstMessage string) string {
 
 	return title
 }
+
+// GenerateMessageSnippet builds a single-line preview of a message for the session list
+func GenerateMessageSnippet(content string) string {
+	maxLen := 100
+
+	// Collapse newlines and repeated whitespace
+	snippet := strings.Join(strings.Fields(content), " ")
+
+	// Truncate on rune boundary so Vietnamese characters are not split
+	runes := []rune(snippet)
+	if len(runes) > maxLen {
+		snippet = string(runes[:maxLen]) + "..."
+	}
+
+	return snippet
+}
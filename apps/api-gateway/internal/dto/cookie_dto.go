@@ -0,0 +1,47 @@
+package dto
+
+import "time"
+
+// SyncCookieRequest is a UIT portal cookie sent by the extension
+type SyncCookieRequest struct {
+	Source string `json:"source" binding:"required"` // "daa", "courses", "drl"
+	Cookie string `json:"cookie" binding:"required"`
+}
+
+// SyncCookieResponse tells when the synced cookie will be dropped
+type SyncCookieResponse struct {
+	Source    string    `json:"source"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// CookieStatusResponse tells whether the cookie of one portal is synced
+type CookieStatusResponse struct {
+	Synced             bool       `json:"synced"`
+	ExpiresIn          int        `json:"expires_in,omitempty"` // Seconds, only while synced
+	ExpiresAt          *time.Time `json:"expires_at,omitempty"` // Only while synced
+	RefreshRecommended bool       `json:"refresh_recommended"`  // The cookie expires soon, the extension should sync it again
+	Error              string     `json:"error,omitempty"`
+}
+
+// ExtensionTokenResponse is a scoped token the extension uses for cookie sync and heartbeats only
+type ExtensionTokenResponse struct {
+	Token     string    `json:"token"`
+	Scope     string    `json:"scope"`
+	ExpiresAt time.Time `json:"expires_at"` // Renew before this with the token itself
+}
+
+// ExtensionHeartbeatRequest is sent by the extension on startup and periodically
+type ExtensionHeartbeatRequest struct {
+	Version      string   `json:"version" binding:"required"`
+	Capabilities []string `json:"capabilities"` // Cookie sources the extension can sync, e.g. "daa", "drl"
+}
+
+// ExtensionHeartbeatResponse tells the extension whether it is still supported and what to sync again
+type ExtensionHeartbeatResponse struct {
+	MinSupportedVersion string   `json:"min_supported_version"`
+	LatestVersion       string   `json:"latest_version"`
+	UpdateRequired      bool     `json:"update_required"`    // The version is no longer supported, the extension must update
+	UpdateRecommended   bool     `json:"update_recommended"` // A newer version is available
+	ReauthRequired      bool     `json:"reauth_required"`    // Every cookie must be synced again
+	ResyncSources       []string `json:"resync_sources"`     // Sources whose cookie is missing or expires soon, among the reported capabilities
+}
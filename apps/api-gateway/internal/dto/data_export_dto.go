@@ -0,0 +1,41 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+)
+
+// DataExportResponse is the status of a data export request
+type DataExportResponse struct {
+	ID          string                 `json:"id"`
+	Status      model.DataExportStatus `json:"status"`
+	SizeBytes   int64                  `json:"size_bytes,omitempty"`
+	Error       string                 `json:"error,omitempty"`
+	CreatedAt   time.Time              `json:"created_at"`
+	CompletedAt *time.Time             `json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time             `json:"expires_at,omitempty"`
+	DownloadURL string                 `json:"download_url,omitempty"` // Signed link, only while the export is ready
+}
+
+func FromDataExport(e *model.DataExport) *DataExportResponse {
+	if e == nil {
+		return nil
+	}
+
+	return &DataExportResponse{
+		ID:          e.ID.Hex(),
+		Status:      e.Status,
+		SizeBytes:   e.SizeBytes,
+		Error:       e.Error,
+		CreatedAt:   e.CreatedAt,
+		CompletedAt: e.CompletedAt,
+		ExpiresAt:   e.ExpiresAt,
+	}
+}
+
+// ExportedChatSession is one chat session with its full transcript inside the export archive
+type ExportedChatSession struct {
+	ChatSessionResponse
+	Messages []ChatMessageResponse `json:"messages"`
+}
@@ -0,0 +1,139 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+)
+
+// CreateEmailCampaignRequest is the request to create a draft email campaign.
+// Body is an HTML template; {{.Username}} and {{.Email}} are replaced per recipient.
+type CreateEmailCampaignRequest struct {
+	Subject  string              `json:"subject" binding:"required,max=200"`
+	Body     string              `json:"body" binding:"required,max=20000"`
+	Audience model.EmailAudience `json:"audience" binding:"required,oneof=all active role"`
+	Role     model.Role          `json:"role" binding:"omitempty,oneof=user admin"` // Required for the role audience
+}
+
+// GetEmailDeliveriesQuery filters a campaign's per-recipient delivery statuses
+type GetEmailDeliveriesQuery struct {
+	Status   model.EmailDeliveryStatus `form:"status" binding:"omitempty,oneof=pending processing queued sent failed"`
+	Page     int                       `form:"page" binding:"omitempty,min=1"`
+	PageSize int                       `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+// EmailCampaignResponse is the admin view of an email campaign
+type EmailCampaignResponse struct {
+	ID             string                    `json:"id"`
+	Subject        string                    `json:"subject"`
+	Body           string                    `json:"body"`
+	Audience       model.EmailAudience       `json:"audience"`
+	Role           model.Role                `json:"role,omitempty"`
+	Status         model.EmailCampaignStatus `json:"status"`
+	RecipientCount int64                     `json:"recipient_count"`
+	SentCount      int64                     `json:"sent_count"`
+	FailedCount    int64                     `json:"failed_count"`
+	QueuedAt       *time.Time                `json:"queued_at,omitempty"`
+	CompletedAt    *time.Time                `json:"completed_at,omitempty"`
+	CreatedBy      string                    `json:"created_by"`
+	CreatedAt      time.Time                 `json:"created_at"`
+	UpdatedAt      time.Time                 `json:"updated_at"`
+}
+
+// PaginatedEmailCampaignsResponse is a paginated list of email campaigns
+type PaginatedEmailCampaignsResponse struct {
+	Campaigns  []EmailCampaignResponse `json:"campaigns"`
+	Pagination Pagination              `json:"pagination"`
+}
+
+// EmailDeliveryResponse is the delivery status of a campaign email for one recipient
+type EmailDeliveryResponse struct {
+	UserID   string                    `json:"user_id"`
+	Email    string                    `json:"email"`
+	Username string                    `json:"username"`
+	Status   model.EmailDeliveryStatus `json:"status"`
+	Error    string                    `json:"error,omitempty"`
+	SentAt   *time.Time                `json:"sent_at,omitempty"`
+}
+
+// PaginatedEmailDeliveriesResponse is a paginated list of campaign deliveries
+type PaginatedEmailDeliveriesResponse struct {
+	Deliveries []EmailDeliveryResponse `json:"deliveries"`
+	Pagination Pagination              `json:"pagination"`
+}
+
+// FromEmailCampaign converts a model.EmailCampaign to an EmailCampaignResponse DTO
+func FromEmailCampaign(c *model.EmailCampaign) EmailCampaignResponse {
+	return EmailCampaignResponse{
+		ID:             c.ID.Hex(),
+		Subject:        c.Subject,
+		Body:           c.Body,
+		Audience:       c.Audience,
+		Role:           c.Role,
+		Status:         c.Status,
+		RecipientCount: c.RecipientCount,
+		SentCount:      c.SentCount,
+		FailedCount:    c.FailedCount,
+		QueuedAt:       c.QueuedAt,
+		CompletedAt:    c.CompletedAt,
+		CreatedBy:      c.CreatedBy.Hex(),
+		CreatedAt:      c.CreatedAt,
+		UpdatedAt:      c.UpdatedAt,
+	}
+}
+
+// FromEmailCampaigns converts multiple campaigns to response DTOs
+func FromEmailCampaigns(campaigns []*model.EmailCampaign) []EmailCampaignResponse {
+	responses := make([]EmailCampaignResponse, len(campaigns))
+	for i, c := range campaigns {
+		responses[i] = FromEmailCampaign(c)
+	}
+	return responses
+}
+
+// FromEmailDeliveries converts deliveries to response DTOs
+func FromEmailDeliveries(deliveries []*model.EmailDelivery) []EmailDeliveryResponse {
+	responses := make([]EmailDeliveryResponse, len(deliveries))
+	for i, d := range deliveries {
+		responses[i] = EmailDeliveryResponse{
+			UserID:   d.UserID.Hex(),
+			Email:    d.Email,
+			Username: d.Username,
+			Status:   d.Status,
+			Error:    d.Error,
+			SentAt:   d.SentAt,
+		}
+	}
+	return responses
+}
+
+// FailedEmailResponse is an email given up on after too many failed sends. OTPs are never returned.
+type FailedEmailResponse struct {
+	ID        string             `json:"id"`
+	Kind      model.EmailJobKind `json:"kind"`
+	To        string             `json:"to"`
+	Subject   string             `json:"subject,omitempty"`
+	Attempts  int                `json:"attempts"`
+	LastError string             `json:"last_error"`
+	CreatedAt time.Time          `json:"created_at"`
+	FailedAt  *time.Time         `json:"failed_at,omitempty"`
+}
+
+// PaginatedFailedEmailsResponse is a paginated list of failed emails, most recently failed first
+type PaginatedFailedEmailsResponse struct {
+	Emails     []FailedEmailResponse `json:"emails"`
+	Pagination Pagination            `json:"pagination"`
+}
+
+func FromEmailJob(job *model.EmailJob) FailedEmailResponse {
+	return FailedEmailResponse{
+		ID:        job.ID,
+		Kind:      job.Kind,
+		To:        job.To,
+		Subject:   job.Subject,
+		Attempts:  job.Attempts,
+		LastError: job.LastError,
+		CreatedAt: job.CreatedAt,
+		FailedAt:  job.FailedAt,
+	}
+}
@@ -0,0 +1,76 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+)
+
+// GetModerationDecisionsQuery filters the moderation decision log. From and To are inclusive (YYYY-MM-DD).
+type GetModerationDecisionsQuery struct {
+	Action        model.ModerationAction `form:"action" binding:"omitempty,oneof=approved rejected failed"`
+	Category      string                 `form:"category"`
+	IsViolation   *bool                  `form:"is_violation"`
+	MinConfidence *float64               `form:"min_confidence" binding:"omitempty,min=0,max=1"`
+	MaxConfidence *float64               `form:"max_confidence" binding:"omitempty,min=0,max=1"`
+	From          string                 `form:"from"`
+	To            string                 `form:"to"`
+	Page          int                    `form:"page" binding:"omitempty,min=1"`
+	PageSize      int                    `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+// ModerationDecisionResponse is a moderation decision as shown to admins
+type ModerationDecisionResponse struct {
+	ID          string                 `json:"id"`
+	ContentHash string                 `json:"content_hash"`
+	ImageCount  int                    `json:"image_count"`
+	VideoCount  int                    `json:"video_count"`
+	IsViolation bool                   `json:"is_violation"`
+	Confidence  float64                `json:"confidence"`
+	Categories  []string               `json:"categories"`
+	Reason      string                 `json:"reason,omitempty"`
+	Action      model.ModerationAction `json:"action"`
+	Threshold   float64                `json:"threshold"`
+	Model       string                 `json:"model"`
+	Error       string                 `json:"error,omitempty"`
+	LatencyMs   int64                  `json:"latency_ms"`
+	CreatedAt   time.Time              `json:"created_at"`
+}
+
+// PaginatedModerationDecisionsResponse is a page of the moderation decision log
+type PaginatedModerationDecisionsResponse struct {
+	Decisions  []ModerationDecisionResponse `json:"decisions"`
+	Pagination Pagination                   `json:"pagination"`
+}
+
+func FromModerationDecision(d *model.ModerationDecision) ModerationDecisionResponse {
+	categories := d.Categories
+	if categories == nil {
+		categories = []string{}
+	}
+
+	return ModerationDecisionResponse{
+		ID:          d.ID.Hex(),
+		ContentHash: d.ContentHash,
+		ImageCount:  d.ImageCount,
+		VideoCount:  d.VideoCount,
+		IsViolation: d.IsViolation,
+		Confidence:  d.Confidence,
+		Categories:  categories,
+		Reason:      d.Reason,
+		Action:      d.Action,
+		Threshold:   d.Threshold,
+		Model:       d.Model,
+		Error:       d.Error,
+		LatencyMs:   d.LatencyMs,
+		CreatedAt:   d.CreatedAt,
+	}
+}
+
+func FromModerationDecisions(decisions []*model.ModerationDecision) []ModerationDecisionResponse {
+	responses := make([]ModerationDecisionResponse, 0, len(decisions))
+	for _, d := range decisions {
+		responses = append(responses, FromModerationDecision(d))
+	}
+	return responses
+}
@@ -12,16 +12,59 @@ type NotificationResponse struct {
 	Type      model.NotificationType `json:"type"`
 	Message   string                 `json:"message"`
 	Link      string                 `json:"link"`
+	Data      *NotificationDataDTO   `json:"data,omitempty"`
 	IsRead    bool                   `json:"is_read"`
 	CreatedAt time.Time              `json:"created_at"`
 	// We can add actor information here later if needed
 	// Actor     *ShortUserResponse `json:"actor,omitempty"`
 }
 
-// PaginatedNotificationsResponse defines the structure for a paginated list of notifications.
+// PaginatedNotificationsResponse defines the structure for a cursor-paginated list of notifications.
+// Pass NextCursor as the cursor query parameter to load the next page; it is empty on the last page.
 type PaginatedNotificationsResponse struct {
 	Notifications []NotificationResponse `json:"notifications"`
-	Pagination    Pagination             `json:"pagination"`
+	NextCursor    string                 `json:"next_cursor,omitempty"`
+	HasMore       bool                   `json:"has_more"`
+}
+
+// PageItems and PageInfo implement Paged
+func (r PaginatedNotificationsResponse) PageItems() interface{} {
+	return r.Notifications
+}
+
+func (r PaginatedNotificationsResponse) PageInfo() PageInfo {
+	return cursorPageInfo(r.NextCursor, r.HasMore)
+}
+
+// NotificationDataDTO is the structured deep link of a notification, used in requests and responses
+type NotificationDataDTO struct {
+	EntityType model.NotificationEntityType `json:"entity_type" binding:"required,oneof=chat_session settings announcement notification"`
+	EntityID   string                       `json:"entity_id,omitempty" binding:"omitempty,max=100"`
+	Action     model.NotificationAction     `json:"action" binding:"required,oneof=open view"`
+}
+
+// ToModel converts the DTO to a model.NotificationData, returning nil for a nil DTO.
+func (d *NotificationDataDTO) ToModel() *model.NotificationData {
+	if d == nil {
+		return nil
+	}
+	return &model.NotificationData{
+		EntityType: d.EntityType,
+		EntityID:   d.EntityID,
+		Action:     d.Action,
+	}
+}
+
+// FromNotificationData converts a model.NotificationData to its DTO, returning nil when there is no deep link.
+func FromNotificationData(d *model.NotificationData) *NotificationDataDTO {
+	if d == nil {
+		return nil
+	}
+	return &NotificationDataDTO{
+		EntityType: d.EntityType,
+		EntityID:   d.EntityID,
+		Action:     d.Action,
+	}
 }
 
 // FromNotification converts a model.Notification to a NotificationResponse DTO.
@@ -31,6 +74,7 @@ func FromNotification(n *model.Notification) NotificationResponse {
 		Type:      n.Type,
 		Message:   n.Message,
 		Link:      n.Link,
+		Data:      FromNotificationData(n.Data),
 		IsRead:    n.IsRead,
 		CreatedAt: n.CreatedAt,
 	}
@@ -44,3 +88,75 @@ func FromNotifications(notifications []*model.Notification) []NotificationRespon
 	}
 	return responses
 }
+
+// ScheduleNotificationRequest is the request to queue a notification for future delivery
+type ScheduleNotificationRequest struct {
+	RecipientIDs []string               `json:"recipient_ids" binding:"required,min=1,max=1000,dive,mongodb"`
+	Type         model.NotificationType `json:"type" binding:"required,oneof=system deadline_reminder announcement"`
+	Message      string                 `json:"message" binding:"required,max=2000"`
+	Link         string                 `json:"link" binding:"omitempty,max=500"`
+	Data         *NotificationDataDTO   `json:"data" binding:"omitempty"`
+	DeliverAt    time.Time              `json:"deliver_at" binding:"required_without=LocalTime"`
+	// LocalTime is a wall-clock time ("2006-01-02T15:04") resolved in each recipient's timezone, used instead of DeliverAt
+	LocalTime string `json:"local_time" binding:"omitempty,datetime=2006-01-02T15:04"`
+}
+
+// ScheduledNotificationResponse defines the structure for a scheduled notification returned to admins.
+type ScheduledNotificationResponse struct {
+	ID          string                            `json:"id"`
+	RecipientID string                            `json:"recipient_id"`
+	Type        model.NotificationType            `json:"type"`
+	Message     string                            `json:"message"`
+	Link        string                            `json:"link,omitempty"`
+	Data        *NotificationDataDTO              `json:"data,omitempty"`
+	DeliverAt   time.Time                         `json:"deliver_at"`
+	LocalTime   string                            `json:"local_time,omitempty"`
+	Timezone    string                            `json:"timezone,omitempty"`
+	Status      model.ScheduledNotificationStatus `json:"status"`
+	DeliveredAt *time.Time                        `json:"delivered_at,omitempty"`
+	Error       string                            `json:"error,omitempty"`
+	CreatedAt   time.Time                         `json:"created_at"`
+}
+
+// PaginatedScheduledNotificationsResponse defines the structure for a paginated list of scheduled notifications.
+type PaginatedScheduledNotificationsResponse struct {
+	ScheduledNotifications []ScheduledNotificationResponse `json:"scheduled_notifications"`
+	Pagination             Pagination                      `json:"pagination"`
+}
+
+// PageItems and PageInfo implement Paged
+func (r PaginatedScheduledNotificationsResponse) PageItems() interface{} {
+	return r.ScheduledNotifications
+}
+
+func (r PaginatedScheduledNotificationsResponse) PageInfo() PageInfo {
+	return r.Pagination.PageInfo()
+}
+
+// FromScheduledNotification converts a model.ScheduledNotification to a ScheduledNotificationResponse DTO.
+func FromScheduledNotification(n *model.ScheduledNotification) ScheduledNotificationResponse {
+	return ScheduledNotificationResponse{
+		ID:          n.ID.Hex(),
+		RecipientID: n.RecipientID.Hex(),
+		Type:        n.Type,
+		Message:     n.Message,
+		Link:        n.Link,
+		Data:        FromNotificationData(n.Data),
+		DeliverAt:   n.DeliverAt,
+		LocalTime:   n.LocalTime,
+		Timezone:    n.Timezone,
+		Status:      n.Status,
+		DeliveredAt: n.DeliveredAt,
+		Error:       n.Error,
+		CreatedAt:   n.CreatedAt,
+	}
+}
+
+// FromScheduledNotifications converts a slice of model.ScheduledNotification to ScheduledNotificationResponse DTOs.
+func FromScheduledNotifications(notifications []*model.ScheduledNotification) []ScheduledNotificationResponse {
+	responses := make([]ScheduledNotificationResponse, len(notifications))
+	for i, n := range notifications {
+		responses[i] = FromScheduledNotification(n)
+	}
+	return responses
+}
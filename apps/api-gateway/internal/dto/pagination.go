@@ -1,7 +1,60 @@
 package dto
 
+import (
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/apperror"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/repo"
+)
+
 type Pagination struct {
 	Page     int   `json:"page"`
 	PageSize int   `json:"page_size"`
 	Total    int64 `json:"total"`
 }
+
+// PageInfo is the pagination metadata of a list sent with SendPage. Offset-paginated lists fill page,
+// page_size and total, cursor-paginated lists fill next_cursor; has_more is always set.
+type PageInfo struct {
+	Page       int    `json:"page,omitempty"`
+	PageSize   int    `json:"page_size,omitempty"`
+	Total      *int64 `json:"total,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+}
+
+// PageInfo returns the metadata of an offset-paginated page
+func (p Pagination) PageInfo() PageInfo {
+	total := p.Total
+	return PageInfo{
+		Page:     p.Page,
+		PageSize: p.PageSize,
+		Total:    &total,
+		HasMore:  int64(p.Page)*int64(p.PageSize) < p.Total,
+	}
+}
+
+// cursorPageInfo returns the metadata of a cursor-paginated page
+func cursorPageInfo(nextCursor string, hasMore bool) PageInfo {
+	return PageInfo{NextCursor: nextCursor, HasMore: hasMore}
+}
+
+// cursorPage builds a page of limit documents sorted by field in order, starting after the encoded
+// cursor of a previous response's next_cursor (empty for the first page)
+func cursorPage(field string, order int, cursor string, limit int) (repo.Page, error) {
+	page := repo.Page{Field: field, Order: order, Limit: limit}
+	if cursor != "" {
+		after, err := repo.DecodeCursor(cursor)
+		if err != nil {
+			return repo.Page{}, apperror.ErrInvalidCursor
+		}
+		page.After = after
+	}
+	return page, nil
+}
+
+// NextCursor encodes the cursor of the next page, empty on the last page
+func NextCursor(next *repo.Cursor) string {
+	if next == nil {
+		return ""
+	}
+	return next.Encode()
+}
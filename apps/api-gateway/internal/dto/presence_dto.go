@@ -0,0 +1,10 @@
+package dto
+
+import "time"
+
+// PresenceResponse is a user's online state
+type PresenceResponse struct {
+	UserID   string     `json:"user_id"`
+	Online   bool       `json:"online"`
+	LastSeen *time.Time `json:"last_seen,omitempty"` // Last connect or disconnect, nil if never connected
+}
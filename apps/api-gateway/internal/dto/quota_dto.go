@@ -0,0 +1,22 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+)
+
+// SetUserQuotaRequest overrides a user's default daily chat limits, 0 = unlimited
+type SetUserQuotaRequest struct {
+	DailyMessages *int `json:"daily_messages" binding:"required,min=0"`
+	DailyTokens   *int `json:"daily_tokens" binding:"required,min=0"`
+}
+
+// UserQuotaResponse is a user's daily chat limits and what they used today
+type UserQuotaResponse struct {
+	UserID    string           `json:"user_id"`
+	Override  *model.UserQuota `json:"override,omitempty"` // nil = default limits apply
+	Limits    model.UserQuota  `json:"limits"`             // Limits in effect, 0 = unlimited
+	UsedToday model.UserQuota  `json:"used_today"`
+	ResetsAt  time.Time        `json:"resets_at"` // Start of the next day in the stats timezone
+}
@@ -0,0 +1,79 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+)
+
+// ReportMessageRequest is the request to report a bad assistant answer
+type ReportMessageRequest struct {
+	Reason  model.ReportReason `json:"reason" binding:"required,oneof=wrong_info harmful outdated_policy"`
+	Comment string             `json:"comment" binding:"omitempty,max=1000"`
+}
+
+// GetReportsQuery filters the admin moderation queue
+type GetReportsQuery struct {
+	Status   model.ReportStatus `form:"status" binding:"omitempty,oneof=open resolved dismissed"`
+	Reason   model.ReportReason `form:"reason" binding:"omitempty,oneof=wrong_info harmful outdated_policy"`
+	Page     int                `form:"page" binding:"omitempty,min=1"`
+	PageSize int                `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+// ReviewReportRequest records the moderation decision on a report
+type ReviewReportRequest struct {
+	Status         model.ReportStatus `json:"status" binding:"required,oneof=resolved dismissed"`
+	ResolutionNote string             `json:"resolution_note" binding:"omitempty,max=1000"`
+}
+
+// MessageReportResponse is a report as returned to its reporter and to admins
+type MessageReportResponse struct {
+	ID             string             `json:"id"`
+	MessageID      string             `json:"message_id"`
+	SessionID      string             `json:"session_id"`
+	ReporterID     string             `json:"reporter_id"`
+	Reason         model.ReportReason `json:"reason"`
+	Comment        string             `json:"comment,omitempty"`
+	MessageContent string             `json:"message_content"`
+	Status         model.ReportStatus `json:"status"`
+	ResolutionNote string             `json:"resolution_note,omitempty"`
+	ReviewedBy     string             `json:"reviewed_by,omitempty"`
+	ReviewedAt     *time.Time         `json:"reviewed_at,omitempty"`
+	CreatedAt      time.Time          `json:"created_at"`
+}
+
+// PaginatedReportsResponse is a page of the moderation queue
+type PaginatedReportsResponse struct {
+	Reports    []MessageReportResponse `json:"reports"`
+	Pagination Pagination              `json:"pagination"`
+}
+
+// FromMessageReport converts a model.MessageReport to a MessageReportResponse DTO
+func FromMessageReport(r *model.MessageReport) MessageReportResponse {
+	resp := MessageReportResponse{
+		ID:             r.ID.Hex(),
+		MessageID:      r.MessageID.Hex(),
+		SessionID:      r.SessionID.Hex(),
+		ReporterID:     r.ReporterID.Hex(),
+		Reason:         r.Reason,
+		Comment:        r.Comment,
+		MessageContent: r.MessageContent,
+		Status:         r.Status,
+		ResolutionNote: r.ResolutionNote,
+		ReviewedAt:     r.ReviewedAt,
+		CreatedAt:      r.CreatedAt,
+	}
+	if r.ReviewedBy != nil {
+		resp.ReviewedBy = r.ReviewedBy.Hex()
+	}
+	return resp
+}
+
+// FromMessageReports converts multiple reports to response DTOs
+func FromMessageReports(reports []*model.MessageReport) []MessageReportResponse {
+	responses := make([]MessageReportResponse, len(reports))
+	for i, r := range reports {
+		responses[i] = FromMessageReport(r)
+	}
+	return responses
+}
@@ -0,0 +1,31 @@
+package dto
+
+import "time"
+
+// Dependency health statuses
+const (
+	DependencyStatusUp       = "up"
+	DependencyStatusDown     = "down"
+	DependencyStatusDisabled = "disabled" // Not configured, the feature falls back to a no-op
+)
+
+// System health statuses
+const (
+	SystemStatusOK       = "ok"
+	SystemStatusDegraded = "degraded" // At least one dependency is down
+)
+
+// DependencyHealth is the probe result of one external dependency
+type DependencyHealth struct {
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// SystemHealthResponse is the live status of every dependency of the gateway
+type SystemHealthResponse struct {
+	Status       string             `json:"status"`
+	Dependencies []DependencyHealth `json:"dependencies"`
+	CheckedAt    time.Time          `json:"checked_at"`
+}
@@ -0,0 +1,104 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+)
+
+// UITScheduleResponse is the student's weekly timetable fetched from DAA
+type UITScheduleResponse struct {
+	Semester  string                   `json:"semester,omitempty"`
+	Classes   []model.UITScheduleClass `json:"classes"`
+	FetchedAt time.Time                `json:"fetched_at"`
+	Cached    bool                     `json:"cached"` // Served from cache instead of fetched for this request
+}
+
+func FromUITSchedule(s *model.UITSchedule, cached bool) *UITScheduleResponse {
+	return &UITScheduleResponse{
+		Semester:  s.Semester,
+		Classes:   s.Classes,
+		FetchedAt: s.FetchedAt,
+		Cached:    cached,
+	}
+}
+
+// UITTrainingScoreResponse is the student's training score (điểm rèn luyện) per term fetched from DRL
+type UITTrainingScoreResponse struct {
+	Terms     []model.UITTrainingScoreTerm `json:"terms"`
+	Average   float64                      `json:"average"`
+	FetchedAt time.Time                    `json:"fetched_at"`
+	Cached    bool                         `json:"cached"` // Served from cache instead of fetched for this request
+}
+
+func FromUITTrainingScore(s *model.UITTrainingScore, cached bool) *UITTrainingScoreResponse {
+	return &UITTrainingScoreResponse{
+		Terms:     s.Terms,
+		Average:   s.Average,
+		FetchedAt: s.FetchedAt,
+		Cached:    cached,
+	}
+}
+
+// UITCalendarFeedResponse is the user's iCalendar feed link, to subscribe to from Google Calendar or another calendar app
+type UITCalendarFeedResponse struct {
+	URL string `json:"url"`
+}
+
+// UITTuitionResponse is the student's tuition fee status fetched from DAA
+type UITTuitionResponse struct {
+	Terms       []model.UITTuitionTerm `json:"terms"`
+	Outstanding int64                  `json:"outstanding"` // VND
+	FetchedAt   time.Time              `json:"fetched_at"`
+	Cached      bool                   `json:"cached"` // Served from cache instead of fetched for this request
+}
+
+func FromUITTuition(t *model.UITTuition, cached bool) *UITTuitionResponse {
+	return &UITTuitionResponse{
+		Terms:       t.Terms,
+		Outstanding: t.Outstanding,
+		FetchedAt:   t.FetchedAt,
+		Cached:      cached,
+	}
+}
+
+// OpenClassesQuery filters the classes open for registration
+type OpenClassesQuery struct {
+	Subject   string `form:"subject"`   // Class code prefix or part of the subject name, e.g. "IT003"
+	Available bool   `form:"available"` // Only classes with slots left
+	Refresh   bool   `form:"refresh"`   // Bypass the cache
+}
+
+// UITOpenClassesResponse lists the classes open for course registration
+type UITOpenClassesResponse struct {
+	Classes   []model.UITOpenClass `json:"classes"`
+	FetchedAt time.Time            `json:"fetched_at"`
+	Cached    bool                 `json:"cached"` // Served from cache instead of fetched for this request
+}
+
+// CheckConflictsRequest lists the classes a student plans to register
+type CheckConflictsRequest struct {
+	ClassCodes []string `json:"class_codes" binding:"required,min=1,max=20"`
+}
+
+// UITConflictCheckResponse tells which planned classes cannot be registered together with the timetable
+type UITConflictCheckResponse struct {
+	Conflicts []UITClassConflict `json:"conflicts"`
+	Full      []string           `json:"full"`    // Planned classes with no slot left
+	Unknown   []string           `json:"unknown"` // Planned class codes not open for registration
+}
+
+// UITClassConflict is a planned class sharing a slot with another class
+type UITClassConflict struct {
+	ClassCode     string `json:"class_code"`
+	ConflictsWith string `json:"conflicts_with"`
+	InTimetable   bool   `json:"in_timetable"` // The other class is in the current timetable, not among the planned ones
+	DayOfWeek     string `json:"day_of_week"`
+	Period        string `json:"period"` // Period of the planned class
+}
+
+// PaginatedUITAnnouncementsResponse is a page of notices crawled from official UIT pages, newest first
+type PaginatedUITAnnouncementsResponse struct {
+	Announcements []*model.UITAnnouncement `json:"announcements"`
+	Pagination    Pagination               `json:"pagination"`
+}
@@ -0,0 +1,28 @@
+package dto
+
+import "github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+
+// GetUsageQuery selects the month of the usage report (YYYY-MM), defaults to the current month
+type GetUsageQuery struct {
+	Month    string `form:"month"`
+	Page     int    `form:"page" binding:"omitempty,min=1"`
+	PageSize int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+// UserUsageResponse is one user's usage in the report
+type UserUsageResponse struct {
+	UserID        string  `json:"user_id"`
+	Username      string  `json:"username,omitempty"` // Empty if the user no longer exists
+	Email         string  `json:"email,omitempty"`
+	Requests      int64   `json:"requests"`
+	TokensUsed    int64   `json:"tokens_used"`
+	EstimatedCost float64 `json:"estimated_cost"`
+}
+
+// UsageReportResponse lists the heaviest users of a month and the totals of all users
+type UsageReportResponse struct {
+	Month      string              `json:"month"`
+	Totals     model.UsageTotals   `json:"totals"`
+	Users      []UserUsageResponse `json:"users"`
+	Pagination Pagination          `json:"pagination"`
+}
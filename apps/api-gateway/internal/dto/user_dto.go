@@ -18,6 +18,12 @@ type GetUsersQuery struct {
 // UpdateUserRequest defines the fields a user can update
 type UpdateUserRequest struct {
 	Username string `json:"username" binding:"omitempty,min=3,max=30"`
+
+	// Student profile, only provided fields change; an empty string or 0 clears the field
+	StudentID      *string `json:"student_id"`
+	Faculty        *string `json:"faculty" binding:"omitempty,max=100"`
+	Program        *string `json:"program" binding:"omitempty,max=100"`
+	EnrollmentYear *int    `json:"enrollment_year"`
 }
 
 // UpdateSettingsRequest allows updating user settings
@@ -25,6 +31,40 @@ type UpdateSettingsRequest struct {
 	Language          *string `json:"language" binding:"omitempty,oneof=vi en"`
 	Theme             *string `json:"theme" binding:"omitempty,oneof=light dark"`
 	NotifyNewFeatures *bool   `json:"notify_new_features"`
+	DigestFrequency   *string `json:"digest_frequency" binding:"omitempty,oneof=off daily weekly"`
+	ProductEmails     *bool   `json:"product_emails"`                      // Campaign emails; transactional emails are always sent
+	Timezone          *string `json:"timezone" binding:"omitempty,max=64"` // IANA name, e.g. "Asia/Ho_Chi_Minh"
+	ProfileVisibility *string `json:"profile_visibility" binding:"omitempty,oneof=everyone users nobody"`
+
+	// Accessibility
+	FontScale     *float64 `json:"font_scale" binding:"omitempty,min=0.75,max=2"`
+	ReducedMotion *bool    `json:"reduced_motion"`
+	HighContrast  *bool    `json:"high_contrast"`
+
+	// Keyed by notification type, only provided fields are changed
+	NotificationPreferences map[model.NotificationType]UpdateNotificationPreferenceRequest `json:"notification_preferences"`
+
+	// Chat behavior, an empty default_model resets to the agent default
+	DefaultModel     *string `json:"default_model" binding:"omitempty,max=100"`
+	ResponseLanguage *string `json:"response_language" binding:"omitempty,oneof=auto vi en"`
+	StreamingEnabled *bool   `json:"streaming_enabled"`
+	CitationsEnabled *bool   `json:"citations_enabled"`
+
+	// Self-imposed daily limits, 0 removes a limit
+	UsageLimitMessages *int  `json:"usage_limit_messages" binding:"omitempty,min=0,max=10000"`
+	UsageLimitTokens   *int  `json:"usage_limit_tokens" binding:"omitempty,min=0,max=100000000"`
+	UsageLimitEnforced *bool `json:"usage_limit_enforced"` // Refuse chat once a limit is reached instead of only notifying
+}
+
+// UpdateNotificationPreferenceRequest updates delivery channels of a notification type
+type UpdateNotificationPreferenceRequest struct {
+	InApp *bool `json:"in_app"`
+	Email *bool `json:"email"`
+}
+
+// AddBlockedTopicRequest adds a topic the assistant must not bring up
+type AddBlockedTopicRequest struct {
+	Topic string `json:"topic" binding:"required,min=2,max=50"`
 }
 
 // ChangePasswordRequest for changing user password
@@ -40,6 +80,46 @@ type UserSettingsResponse struct {
 	Language          string `json:"language"`
 	Theme             string `json:"theme"`
 	NotifyNewFeatures bool   `json:"notify_new_features"`
+	DigestFrequency   string `json:"digest_frequency"`
+	ProductEmails     bool   `json:"product_emails"`
+	Timezone          string `json:"timezone"`
+	ProfileVisibility string `json:"profile_visibility"`
+
+	FontScale     float64 `json:"font_scale"`
+	ReducedMotion bool    `json:"reduced_motion"`
+	HighContrast  bool    `json:"high_contrast"`
+
+	NotificationPreferences map[model.NotificationType]model.NotificationPreference `json:"notification_preferences"`
+
+	DefaultModel     string `json:"default_model"` // Empty = agent default
+	ResponseLanguage string `json:"response_language"`
+	StreamingEnabled bool   `json:"streaming_enabled"`
+	CitationsEnabled bool   `json:"citations_enabled"`
+
+	BlockedTopics []string `json:"blocked_topics"` // Managed through /users/me/blocked-topics
+
+	UsageLimitMessages int  `json:"usage_limit_messages"` // 0 = no limit
+	UsageLimitTokens   int  `json:"usage_limit_tokens"`   // 0 = no limit
+	UsageLimitEnforced bool `json:"usage_limit_enforced"`
+}
+
+// BlockedTopicsResponse lists the topics the assistant must not bring up
+type BlockedTopicsResponse struct {
+	Topics []string `json:"topics"`
+}
+
+// ProfileCompletenessResponse scores how complete the user's profile is, for the "complete your profile" card
+type ProfileCompletenessResponse struct {
+	Score int                       `json:"score"` // Sum of the weights of completed items, 0-100
+	Items []ProfileCompletenessItem `json:"items"`
+}
+
+// ProfileCompletenessItem is one step of completing the profile
+type ProfileCompletenessItem struct {
+	Key    string `json:"key"` // "avatar" | "student_id" | "faculty" | "program" | "enrollment_year" | "uit_cookies"
+	Done   bool   `json:"done"`
+	Weight int    `json:"weight"`
+	Hint   string `json:"hint,omitempty"` // What to do next, only while not done
 }
 
 // UserResponse is the main user object returned in API responses
@@ -52,17 +132,58 @@ type UserResponse struct {
 	IsVerified bool                 `json:"is_verified"`
 	IsActive   bool                 `json:"is_active"`
 	Avatar     *model.Image         `json:"avatar,omitempty"`
+	Student    model.StudentProfile `json:"student"`
 	Settings   UserSettingsResponse `json:"settings"`
 	CreatedAt  time.Time            `json:"created_at"`
+
+	LinkedProviders []model.AuthProvider `json:"linked_providers,omitempty"` // Sign-in methods added after registration
+}
+
+// PublicUserResponse is the profile shown to other users and anonymous visitors
+type PublicUserResponse struct {
+	Username  string       `json:"username"`
+	Avatar    *model.Image `json:"avatar,omitempty"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+// PaginatedPublicUsersResponse for the public user list
+type PaginatedPublicUsersResponse struct {
+	Users      []*PublicUserResponse `json:"users"`
+	Pagination Pagination            `json:"pagination"`
+}
+
+// PageItems and PageInfo implement Paged
+func (r PaginatedPublicUsersResponse) PageItems() interface{} {
+	return r.Users
+}
+
+func (r PaginatedPublicUsersResponse) PageInfo() PageInfo {
+	return r.Pagination.PageInfo()
 }
 
 // PaginatedUsersResponse for paginated user lists
 type PaginatedUsersResponse struct {
 	Users      []*UserResponse `json:"users"`
 	Pagination Pagination      `json:"pagination"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+	HasMore    bool            `json:"has_more"`
+}
+
+// PageItems and PageInfo implement Paged
+func (r PaginatedUsersResponse) PageItems() interface{} {
+	return r.Users
+}
+
+// PageInfo carries the cursor as well when the list was paged by cursor
+func (r PaginatedUsersResponse) PageInfo() PageInfo {
+	info := r.Pagination.PageInfo()
+	info.NextCursor = r.NextCursor
+	info.HasMore = info.HasMore || r.HasMore
+	return info
 }
 
-// FromUser converts model.User to UserResponse
+// FromUser converts model.User to UserResponse, the view of the user's own account and of admins. It holds
+// the email, student profile and settings, public routes use FromPublicUser.
 func FromUser(u *model.User) *UserResponse {
 	if u == nil {
 		return nil
@@ -77,21 +198,107 @@ func FromUser(u *model.User) *UserResponse {
 		IsVerified: u.IsVerified,
 		IsActive:   u.IsActive,
 		Avatar:     u.Avatar,
-		Settings: UserSettingsResponse{
-			Language:          u.Settings.Language,
-			Theme:             u.Settings.Theme,
-			NotifyNewFeatures: u.Settings.NotifyNewFeatures,
-		},
+		Student:    u.Student,
+		Settings:   *FromUserSettings(&u.Settings),
+		CreatedAt:  u.CreatedAt,
+
+		LinkedProviders: u.LinkedProviders,
+	}
+}
+
+// FromPublicUser converts model.User to the public profile
+func FromPublicUser(u *model.User) *PublicUserResponse {
+	if u == nil {
+		return nil
+	}
+
+	return &PublicUserResponse{
+		Username:  u.Username,
+		Avatar:    u.Avatar,
 		CreatedAt: u.CreatedAt,
 	}
 }
 
+// FromPublicUsers converts multiple users to public profiles
+func FromPublicUsers(users []*model.User) []*PublicUserResponse {
+	responses := make([]*PublicUserResponse, len(users))
+	for i, u := range users {
+		responses[i] = FromPublicUser(u)
+	}
+	return responses
+}
+
+// FromUserSettings converts model.UserSettings to UserSettingsResponse,
+// filling in defaults for notification types the user has not configured
+func FromUserSettings(s *model.UserSettings) *UserSettingsResponse {
+	prefs := make(map[model.NotificationType]model.NotificationPreference)
+	for t := range model.DefaultNotificationPreferences() {
+		prefs[t] = s.NotificationPreference(t)
+	}
+
+	digestFrequency := s.DigestFrequency
+	if digestFrequency == "" {
+		digestFrequency = model.DigestOff
+	}
+
+	fontScale := s.FontScale
+	if fontScale == 0 {
+		fontScale = model.DefaultFontScale
+	}
+
+	profileVisibility := s.ProfileVisibility
+	if profileVisibility == "" {
+		profileVisibility = model.ProfileVisibilityEveryone
+	}
+
+	responseLanguage := s.ResponseLanguage
+	if responseLanguage == "" {
+		responseLanguage = model.ResponseLanguageAuto
+	}
+
+	return &UserSettingsResponse{
+		Language:                s.Language,
+		Theme:                   s.Theme,
+		FontScale:               fontScale,
+		ReducedMotion:           s.ReducedMotion,
+		HighContrast:            s.HighContrast,
+		NotifyNewFeatures:       s.NotifyNewFeatures,
+		DigestFrequency:         digestFrequency,
+		ProductEmails:           s.IsProductEmailsEnabled(),
+		Timezone:                s.Location().String(),
+		ProfileVisibility:       profileVisibility,
+		NotificationPreferences: prefs,
+		DefaultModel:            s.DefaultModel,
+		ResponseLanguage:        responseLanguage,
+		StreamingEnabled:        s.IsStreamingEnabled(),
+		CitationsEnabled:        s.IsCitationsEnabled(),
+		BlockedTopics:           blockedTopics(s.BlockedTopics),
+		UsageLimitMessages:      s.UsageLimitMessages,
+		UsageLimitTokens:        s.UsageLimitTokens,
+		UsageLimitEnforced:      s.UsageLimitEnforced,
+	}
+}
+
+// blockedTopics returns the topics as a non-nil list so it encodes as []
+func blockedTopics(topics []string) []string {
+	if topics == nil {
+		return []string{}
+	}
+	return topics
+}
+
+// FromBlockedTopics converts a user's blocked topics to a response DTO
+func FromBlockedTopics(s *model.UserSettings) *BlockedTopicsResponse {
+	return &BlockedTopicsResponse{Topics: blockedTopics(s.BlockedTopics)}
+}
+
 // FromUsers converts multiple users to response DTOs
 func FromUsers(users []*model.User) []*UserResponse {
 	responses := make([]*UserResponse, len(users))
 	for i, u := range users {
 		userResponse := FromUser(u)
 		userResponse.Email = "" // Hide email in list views
+		userResponse.Student.StudentID = ""
 		responses[i] = userResponse
 	}
 	return responses
@@ -1,28 +1,152 @@
 package dto
 
+import (
+	"encoding/json"
+	"time"
+)
+
 type WebSocketMessageType string
 
 const (
 	NewNotification WebSocketMessageType = "new_notification"
+	UnreadCount     WebSocketMessageType = "unread_count"
+	Announcement    WebSocketMessageType = "announcement"
 	ACKMessage      WebSocketMessageType = "ack_message"
 	NewMessage      WebSocketMessageType = "new_message"
 	SendMessage     WebSocketMessageType = "send_message"
 	TypingIndicator WebSocketMessageType = "typing"
 	InChatIndicator WebSocketMessageType = "in_chat"
 	ErrorMessage    WebSocketMessageType = "error"
+
+	// Client -> server
+	Auth                 WebSocketMessageType = "auth" // Must be the first message when no token is in the URL
+	Ping                 WebSocketMessageType = "ping"
+	MarkRead             WebSocketMessageType = "mark_read"
+	SubscribeSession     WebSocketMessageType = "subscribe_session"
+	UnsubscribeSession   WebSocketMessageType = "unsubscribe_session"
+	AckNotifications     WebSocketMessageType = "ack_notifications"   // Confirms notifications up to a timestamp were received
+	SubscribeDashboard   WebSocketMessageType = "subscribe_dashboard" // Admin clients only
+	UnsubscribeDashboard WebSocketMessageType = "unsubscribe_dashboard"
+
+	// Server -> client
+	Pong               WebSocketMessageType = "pong"
+	PresenceChanged    WebSocketMessageType = "presence_changed"    // Admin clients only
+	Reconnect          WebSocketMessageType = "reconnect"           // Sent before the server closes the connection on shutdown
+	NotificationReplay WebSocketMessageType = "notification_replay" // Notifications missed while disconnected
+	SessionTerminated  WebSocketMessageType = "session_terminated"  // Sent before the server closes a banned or deleted user's connection
+	ChatSessionUpdate  WebSocketMessageType = "chat_session_update" // Sent to clients subscribed to the session
+	DashboardMetrics   WebSocketMessageType = "dashboard_metrics"   // Sent to admin clients subscribed to the dashboard
 )
 
+// Error codes sent in error frames for malformed client messages
+const (
+	WSErrInvalidMessage = "INVALID_MESSAGE"
+	WSErrUnknownType    = "UNKNOWN_MESSAGE_TYPE"
+	WSErrInvalidPayload = "INVALID_PAYLOAD"
+	WSErrNotSubscribed  = "NOT_SUBSCRIBED"
+	WSErrAuthRequired   = "AUTH_REQUIRED"
+	WSErrReplayFailed   = "REPLAY_FAILED"
+)
+
+// IncomingWebSocketMessage is a message sent by a client.
+// RequestID is optional and echoed back in the ack or error frame for the message.
+type IncomingWebSocketMessage struct {
+	Type      WebSocketMessageType `json:"type"`
+	RequestID string               `json:"request_id,omitempty"`
+	Payload   json.RawMessage      `json:"payload,omitempty"`
+}
+
 type WebSocketMessage struct {
 	Type    WebSocketMessageType `json:"type"`
 	Payload interface{}          `json:"payload"`
 }
 type ErrorPayload struct {
+	RequestID     string  `json:"request_id,omitempty"`
 	TempMessageID *string `json:"temp_message_id,omitempty"`
 	ErrorCode     *string `json:"error_code,omitempty"`
 	ErrorMsg      string  `json:"error_msg"`
 }
 
+type UnreadCountPayload struct {
+	UnreadCount int64 `json:"unread_count"`
+}
+
 type ChatPresenceKey struct {
 	UserID    string
 	ChannelID string
 }
+
+// AckPayload confirms a client message was handled
+type AckPayload struct {
+	RequestID string               `json:"request_id,omitempty"`
+	Type      WebSocketMessageType `json:"type"`
+	Data      interface{}          `json:"data,omitempty"`
+}
+
+// AuthPayload carries the access token in the first message of a connection.
+// LastAckAt is the created_at of the last notification the client acknowledged;
+// notifications created after it are replayed once the connection is registered.
+type AuthPayload struct {
+	Token     string     `json:"token"`
+	LastAckAt *time.Time `json:"last_ack_at,omitempty"`
+}
+
+// AckNotificationsPayload acknowledges every notification created up to LastAckAt.
+// During a replay, the next batch is sent once the current one is acknowledged.
+type AckNotificationsPayload struct {
+	LastAckAt time.Time `json:"last_ack_at"`
+}
+
+// NotificationReplayPayload is one batch of missed notifications, oldest first
+type NotificationReplayPayload struct {
+	Notifications []NotificationResponse `json:"notifications"`
+	HasMore       bool                   `json:"has_more"`
+}
+
+// MarkReadPayload marks one notification, or all of them when All is set, as read
+type MarkReadPayload struct {
+	NotificationID string `json:"notification_id,omitempty"`
+	All            bool   `json:"all,omitempty"`
+}
+
+// SessionPayload references a chat session for subscribe/unsubscribe messages
+type SessionPayload struct {
+	SessionID string `json:"session_id"`
+}
+
+// TypingPayload is sent by a client while composing in a session and relayed to the session's other subscribers
+type TypingPayload struct {
+	SessionID string `json:"session_id"`
+	IsTyping  bool   `json:"is_typing"`
+	UserID    string `json:"user_id,omitempty"` // Set by the server when relaying
+}
+
+// ReconnectPayload tells the client to reconnect after a delay, spreading reconnects across clients
+type ReconnectPayload struct {
+	Reason       string `json:"reason"`
+	RetryAfterMs int    `json:"retry_after_ms"`
+}
+
+// ChatSessionUpdatePayload describes a change to a subscribed chat session
+type ChatSessionUpdatePayload struct {
+	SessionID string      `json:"session_id"`
+	Event     string      `json:"event"`
+	Data      interface{} `json:"data,omitempty"`
+}
+
+// SessionTerminatedPayload explains why the server is closing the connection. The client must not reconnect.
+type SessionTerminatedPayload struct {
+	Reason string `json:"reason"`
+}
+
+// DashboardMetricsPayload is a snapshot of the live admin dashboard, across all API instances
+type DashboardMetricsPayload struct {
+	OnlineUsers       int64     `json:"online_users"`
+	ChatsInProgress   int64     `json:"chats_in_progress"`    // Chat requests waiting on the agent
+	ChatRequests      int64     `json:"chat_requests"`        // Chat requests finished in the window
+	ChatErrors        int64     `json:"chat_errors"`          // Of which the agent call failed
+	ErrorRate         float64   `json:"error_rate"`           // ChatErrors / ChatRequests, 0 without requests
+	AvgAgentLatencyMs int64     `json:"avg_agent_latency_ms"` // Over successful agent calls in the window
+	WindowMinutes     int       `json:"window_minutes"`
+	GeneratedAt       time.Time `json:"generated_at"`
+}
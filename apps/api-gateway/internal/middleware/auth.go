@@ -1,13 +1,16 @@
 package middleware
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/auth"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/logger"
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/repo"
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/util"
 	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 // userRepo is injected at startup to load user settings in middleware
@@ -21,21 +24,7 @@ func SetUserRepo(repo repo.UserRepo) {
 // RequireAuth parse access token và nhét AuthUser vào context
 func RequireAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		var token string
-
-		// Ưu tiên lấy từ Authorization header (cho Web App)
-		authHeader := c.GetHeader("Authorization")
-		if authHeader != "" {
-			parts := strings.SplitN(authHeader, " ", 2)
-			if len(parts) == 2 && parts[0] == "Bearer" {
-				token = parts[1]
-			}
-		}
-
-		// Fallback: Lấy từ cookie (cho Extension)
-		if token == "" {
-			token, _ = c.Cookie("access_token")
-		}
+		token := tokenFromRequest(c)
 
 		// Không có token ở cả 2 nơi → Unauthorized
 		if token == "" {
@@ -56,31 +45,79 @@ func RequireAuth() gin.HandlerFunc {
 		}
 
 		// Load user settings from DB once per request
-		if userRepo != nil {
-			ctx, cancel := util.NewDefaultDBContext()
-			defer cancel()
-
-			dbUser, err := userRepo.GetByID(ctx, user.ID)
-			if err == nil {
-				user.Settings = dbUser.Settings
-			}
+		if !loadActiveUser(c, &user) {
+			return
 		}
 
 		// Nhét user vào context with settings cached
-		c.Set("authUser", user)
+		setAuthUser(c, user)
 		c.Next()
 	}
 }
 
+// RequireExtensionAuth chấp nhận token phạm vi hẹp của extension, hoặc access token đầy đủ cho client cũ.
+// Chỉ dùng cho các endpoint extension được phép gọi (đồng bộ cookie, heartbeat).
+func RequireExtensionAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := tokenFromRequest(c)
+		if token == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing Authorization header or cookie"})
+			c.Abort()
+			return
+		}
+
+		if user, err := auth.ParseExtensionToken(token); err == nil {
+			setAuthUser(c, user)
+			c.Next()
+			return
+		}
+
+		// Không phải token extension → xử lý như access token
+		RequireAuth()(c)
+	}
+}
+
+// OptionalAuth nhét AuthUser vào context nếu request có access token hợp lệ,
+// request không có token hoặc token không hợp lệ vẫn đi tiếp như khách
+func OptionalAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token := tokenFromRequest(c); token != "" {
+			if user, err := auth.ParseAccessToken(token); err == nil {
+				setAuthUser(c, user)
+			}
+		}
+		c.Next()
+	}
+}
+
+// tokenFromRequest lấy access token từ Authorization header (cho Web App), fallback sang cookie (cho Extension)
+func tokenFromRequest(c *gin.Context) string {
+	authHeader := c.GetHeader("Authorization")
+	if authHeader != "" {
+		parts := strings.SplitN(authHeader, " ", 2)
+		if len(parts) == 2 && parts[0] == "Bearer" {
+			return parts[1]
+		}
+	}
+
+	token, _ := c.Cookie("access_token")
+	return token
+}
+
+// RequireAuthSocket authenticates WebSocket upgrades that carry the token in the query string.
+// Deprecated flow: the token leaks into proxy and access logs. Requests without a token pass through
+// unauthenticated and must send an auth frame as their first WebSocket message instead.
 func RequireAuthSocket() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		token := c.Query("token")
 		if token == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing token in query parameter"})
-			c.Abort()
+			c.Next()
 			return
 		}
 
+		logger.FromContext(c.Request.Context()).Warn("Deprecated: WebSocket token passed in query string, use the auth frame instead", "ip", c.ClientIP())
+		c.Header("Deprecation", "true")
+
 		user, err := auth.ParseAccessToken(token)
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{
@@ -91,21 +128,38 @@ func RequireAuthSocket() gin.HandlerFunc {
 			return
 		}
 
-		if userRepo != nil {
-			ctx, cancel := util.NewDefaultDBContext()
-			defer cancel()
-
-			dbUser, err := userRepo.GetByID(ctx, user.ID)
-			if err == nil && true {
-				user.Settings = dbUser.Settings
-			}
+		if !loadActiveUser(c, &user) {
+			return
 		}
 
-		c.Set("authUser", user)
+		setAuthUser(c, user)
 		c.Next()
 	}
 }
 
+// loadActiveUser fills the user's settings from the DB and aborts with 403 if the account is banned or
+// deleted. If the DB cannot be reached the request goes on without settings.
+func loadActiveUser(c *gin.Context, user *auth.AuthUser) bool {
+	if userRepo == nil {
+		return true
+	}
+
+	ctx, cancel := util.NewDBContextFrom(c.Request.Context())
+	defer cancel()
+
+	dbUser, err := userRepo.GetByID(ctx, user.ID)
+	if errors.Is(err, mongo.ErrNoDocuments) || (err == nil && (dbUser.IsBanned() || dbUser.DeletedAt != nil)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Account is banned or deleted"})
+		c.Abort()
+		return false
+	}
+	if err == nil {
+		user.Settings = dbUser.Settings
+		user.Student = dbUser.Student
+	}
+	return true
+}
+
 // RequireAdmin check role admin
 func RequireAdmin() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/apperror"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
+	"github.com/gin-gonic/gin"
+)
+
+// BodyLimit rejects request bodies over the limit with 413: multipart uploads over multipartMax bytes and
+// any other body over otherMax bytes, 0 meaning that kind of body is not limited here. Attached to a route
+// group or route after the global limit, it can only lower the limit.
+//
+// Bodies other than uploads are read into memory up front, so an oversized JSON body is rejected before the
+// handler binds it. Uploads are streamed; one without a Content-Length that turns out too large fails in the
+// handler with an *http.MaxBytesError.
+func BodyLimit(otherMax, multipartMax int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Body == nil || c.Request.Body == http.NoBody {
+			c.Next()
+			return
+		}
+
+		mediaType, _, _ := mime.ParseMediaType(c.ContentType())
+		multipart := mediaType == "multipart/form-data"
+		limit := otherMax
+		if multipart {
+			limit = multipartMax
+		}
+		if limit <= 0 {
+			c.Next()
+			return
+		}
+
+		if c.Request.ContentLength > limit {
+			abortTooLarge(c)
+			return
+		}
+
+		if multipart {
+			c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, limit+1))
+		var maxBytesErr *http.MaxBytesError
+		switch {
+		case errors.As(err, &maxBytesErr), int64(len(body)) > limit:
+			abortTooLarge(c)
+			return
+		case err != nil:
+			c.Abort()
+			dto.SendError(c, http.StatusBadRequest, apperror.ErrBadRequest.Message, apperror.ErrBadRequest.Code)
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		c.Next()
+	}
+}
+
+func abortTooLarge(c *gin.Context) {
+	// The client may still be sending; closing the connection saves reading the rest
+	c.Header("Connection", "close")
+	c.Abort()
+	dto.SendError(c, apperror.StatusFromError(apperror.ErrRequestTooLarge), apperror.ErrRequestTooLarge.Message, apperror.ErrRequestTooLarge.Code)
+}
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Deprecated marks the routes it is attached to as slated for removal. Responses carry the Deprecation
+// header (RFC 9745) with the date the routes were deprecated, the Sunset header (RFC 8594) with the date
+// they stop working and, when successor is set, a Link to the route that replaces them.
+// Usage of deprecated routes can be followed in http_requests_total before removing them.
+func Deprecated(since, sunset time.Time, successor string) gin.HandlerFunc {
+	deprecation := fmt.Sprintf("@%d", since.Unix())
+	sunsetDate := sunset.UTC().Format(http.TimeFormat)
+	link := ""
+	if successor != "" {
+		link = fmt.Sprintf(`<%s>; rel="successor-version"`, successor)
+	}
+
+	return func(c *gin.Context) {
+		c.Header("Deprecation", deprecation)
+		c.Header("Sunset", sunsetDate)
+		if link != "" {
+			c.Header("Link", link)
+		}
+		c.Next()
+	}
+}
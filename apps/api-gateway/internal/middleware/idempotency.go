@@ -0,0 +1,211 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/apperror"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/auth"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// IdempotencyKeyHeader carries the client's key for a request it may send more than once
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set to "true" on responses replayed from an earlier request
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+)
+
+// replayedHeaders are the response headers kept with a response. Never Set-Cookie: stored responses must
+// not carry credentials, and routes issuing them do not use Idempotent.
+var replayedHeaders = []string{"Content-Type", "Location"}
+
+// idempotencyRedis and idempotencyCfg are injected at startup; without them requests are not deduplicated
+var (
+	idempotencyRedis *redis.Client
+	idempotencyCfg   *config.IdempotencyConfig
+)
+
+// SetIdempotencyStore injects the Redis client responses are kept in
+func SetIdempotencyStore(client *redis.Client, cfg *config.IdempotencyConfig) {
+	idempotencyRedis = client
+	idempotencyCfg = cfg
+}
+
+// idempotentResponse is what is kept in Redis for a key: only the request fingerprint while the first
+// request runs, then its response
+type idempotentResponse struct {
+	Fingerprint string              `json:"fingerprint"`
+	Done        bool                `json:"done"`
+	Status      int                 `json:"status,omitempty"`
+	Header      map[string][]string `json:"header,omitempty"`
+	Body        []byte              `json:"body,omitempty"`
+}
+
+// Idempotent replays the response to an earlier request with the same Idempotency-Key, caller and route
+// instead of running the handler again, so a client retrying on a flaky network does not repeat the action.
+// Requests without the header run as usual. Attach it after the route's auth middleware so the key is
+// scoped to the user; anonymous requests are scoped to the client IP. Responses are stored as they are, so
+// never attach it to routes that return tokens or set auth cookies.
+//
+// Only 2xx responses are kept, for IdempotencyConfig.WindowSeconds. Any other response releases the key so
+// the request can be retried. A request reusing a key with a different body gets 422, and one arriving while
+// the first is still running gets 409. If Redis is unavailable, requests run without deduplication.
+func Idempotent() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" || idempotencyRedis == nil {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			abortWithAppError(c, apperror.ErrInvalidIdempotencyKey)
+			return
+		}
+
+		fingerprint, err := requestFingerprint(c)
+		if err != nil {
+			abortWithAppError(c, apperror.ErrBadRequest)
+			return
+		}
+
+		cfg := idempotencyCfg
+		redisKey := fmt.Sprintf(config.RedisIdempotencyKey, requestCaller(c), c.Request.Method+" "+c.FullPath(), key)
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 2*time.Second)
+		defer cancel()
+
+		pending, _ := json.Marshal(idempotentResponse{Fingerprint: fingerprint})
+		claimed, err := idempotencyRedis.SetNX(ctx, redisKey, pending, time.Duration(cfg.LockSeconds)*time.Second).Result()
+		if err != nil {
+			logger.FromContext(c.Request.Context()).Warn("Idempotency store unavailable, running request without it", "error", err)
+			c.Next()
+			return
+		}
+		if !claimed {
+			replayIdempotent(c, ctx, redisKey, fingerprint)
+			return
+		}
+
+		recorder := &bodyRecorder{ResponseWriter: c.Writer, max: cfg.MaxResponseKB << 10}
+		c.Writer = recorder
+		c.Next()
+		c.Writer = recorder.ResponseWriter
+
+		// The request context may have ended with the request, the key must still be stored or released
+		storeCtx, storeCancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), 2*time.Second)
+		defer storeCancel()
+
+		status := c.Writer.Status()
+		if status < 200 || status >= 300 || recorder.truncated {
+			if err := idempotencyRedis.Del(storeCtx, redisKey).Err(); err != nil {
+				logger.FromContext(c.Request.Context()).Warn("Failed to release idempotency key", "error", err)
+			}
+			return
+		}
+
+		response := idempotentResponse{
+			Fingerprint: fingerprint,
+			Done:        true,
+			Status:      status,
+			Header:      make(map[string][]string),
+			Body:        recorder.body.Bytes(),
+		}
+		for _, name := range replayedHeaders {
+			if values := c.Writer.Header().Values(name); len(values) > 0 {
+				response.Header[name] = values
+			}
+		}
+		data, err := json.Marshal(response)
+		if err == nil {
+			err = idempotencyRedis.Set(storeCtx, redisKey, data, time.Duration(cfg.WindowSeconds)*time.Second).Err()
+		}
+		if err != nil {
+			logger.FromContext(c.Request.Context()).Warn("Failed to store idempotent response", "error", err)
+		}
+	}
+}
+
+// replayIdempotent answers a request whose key is already claimed, with the stored response or an error
+func replayIdempotent(c *gin.Context, ctx context.Context, redisKey, fingerprint string) {
+	data, err := idempotencyRedis.Get(ctx, redisKey).Bytes()
+	if errors.Is(err, redis.Nil) {
+		// Released since the claim failed, the earlier request did not succeed
+		abortWithAppError(c, apperror.ErrIdempotencyKeyInUse)
+		return
+	}
+	var stored idempotentResponse
+	if err == nil {
+		err = json.Unmarshal(data, &stored)
+	}
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Warn("Failed to read idempotent response", "error", err)
+		abortWithAppError(c, apperror.ErrInternal)
+		return
+	}
+
+	switch {
+	case stored.Fingerprint != fingerprint:
+		abortWithAppError(c, apperror.ErrIdempotencyKeyReused)
+	case !stored.Done:
+		abortWithAppError(c, apperror.ErrIdempotencyKeyInUse)
+	default:
+		for name, values := range stored.Header {
+			for _, value := range values {
+				c.Writer.Header().Add(name, value)
+			}
+		}
+		c.Header(IdempotentReplayedHeader, "true")
+		c.Status(stored.Status)
+		_, _ = c.Writer.Write(stored.Body)
+		c.Abort()
+	}
+}
+
+// requestCaller identifies the caller by the authenticated user, or by the client IP for anonymous requests
+func requestCaller(c *gin.Context) string {
+	if val, ok := c.Get("authUser"); ok {
+		if user, ok := val.(auth.AuthUser); ok {
+			return "user:" + user.ID
+		}
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// requestFingerprint hashes the request body so a key reused for a different request is detected. Uploads
+// are streamed to the handler and not hashed.
+func requestFingerprint(c *gin.Context) (string, error) {
+	if c.Request.Body == nil || c.Request.Body == http.NoBody {
+		return "", nil
+	}
+	if mediaType, _, _ := mime.ParseMediaType(c.ContentType()); mediaType == "multipart/form-data" {
+		return "", nil
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return "", err
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func abortWithAppError(c *gin.Context, err apperror.AppError) {
+	c.Abort()
+	dto.SendError(c, apperror.StatusFromError(err), err.Message, err.Code)
+}
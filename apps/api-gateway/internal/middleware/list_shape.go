@@ -0,0 +1,15 @@
+package middleware
+
+import (
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
+	"github.com/gin-gonic/gin"
+)
+
+// LegacyListShape keeps paginated lists in the shape v1 clients parse: the list DTO as data with its
+// pagination fields inside, instead of the items as data and the pagination in the envelope
+func LegacyListShape() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(dto.LegacyListKey, true)
+		c.Next()
+	}
+}
@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/auth"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// ErrCodeMaintenance is the error code returned while maintenance mode is enabled
+const ErrCodeMaintenance = "MAINTENANCE_MODE"
+
+// Default maintenance messages by language, used when the admin did not set one
+var maintenanceMessages = map[string]string{
+	model.LanguageVI: "Hệ thống đang bảo trì, vui lòng quay lại sau",
+	model.LanguageEN: "The system is under maintenance, please come back later",
+}
+
+// maintenanceAllowedPrefixes stay reachable during maintenance: health checks, admin routes,
+// and sign-in so admins can get a token
+var maintenanceAllowedPrefixes = []string{
+	"/ping",
+	"/healthz",
+	"/readyz",
+}
+
+// maintenanceAllowedAPIPrefixes are allowed under every API version, e.g. /api/v1/admin/ and /api/v2/admin/
+var maintenanceAllowedAPIPrefixes = []string{
+	"/admin/",
+	"/auth/local/login",
+	"/auth/google/",
+	"/auth/refresh",
+	"/auth/logout",
+}
+
+// Maintenance rejects non-admin traffic with 503 while maintenance mode is enabled
+func Maintenance(maintenanceService service.MaintenanceService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		status := maintenanceService.Current()
+		if !status.Enabled || maintenanceAllowed(c) {
+			c.Next()
+			return
+		}
+
+		message := status.Message
+		if message == "" {
+			message = maintenanceMessages[requestLanguage(c)]
+		}
+
+		dto.SendError(c, http.StatusServiceUnavailable, message, ErrCodeMaintenance)
+		c.Abort()
+	}
+}
+
+func maintenanceAllowed(c *gin.Context) bool {
+	path := c.Request.URL.Path
+	for _, prefix := range maintenanceAllowedPrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	if route, ok := apiRoute(path); ok {
+		for _, prefix := range maintenanceAllowedAPIPrefixes {
+			if strings.HasPrefix(route, prefix) {
+				return true
+			}
+		}
+	}
+
+	// Admins keep using the whole app, e.g. to verify a fix before reopening
+	if token := tokenFromRequest(c); token != "" {
+		if user, err := auth.ParseAccessToken(token); err == nil && user.Role == string(model.AdminRole) {
+			return true
+		}
+	}
+	return false
+}
+
+// apiRoute strips the /api/<version> prefix from path, reporting whether path is an API route
+func apiRoute(path string) (string, bool) {
+	rest, ok := strings.CutPrefix(path, "/api/")
+	if !ok {
+		return "", false
+	}
+	version, route, ok := strings.Cut(rest, "/")
+	if !ok || !strings.HasPrefix(version, "v") {
+		return "", false
+	}
+	return "/" + route, true
+}
+
+// requestLanguage picks the response language from the Accept-Language header, defaulting to Vietnamese
+func requestLanguage(c *gin.Context) string {
+	if strings.HasPrefix(strings.ToLower(c.GetHeader("Accept-Language")), model.LanguageEN) {
+		return model.LanguageEN
+	}
+	return model.LanguageVI
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+// HTTP metrics, exposed on /metrics
+var (
+	httpRequests = metrics.NewCounterVec(
+		"http_requests_total",
+		"HTTP requests handled, by method, route and status code.",
+		"method", "route", "status",
+	)
+	httpRequestDuration = metrics.NewHistogramVec(
+		"http_request_duration_seconds",
+		"Time to handle an HTTP request, by method and route.",
+		metrics.DefaultBuckets,
+		"method", "route",
+	)
+)
+
+// unmatchedRoute labels requests no route matched, so scanners cannot blow up the label set with random paths
+const unmatchedRoute = "unmatched"
+
+// Metrics records the count and latency of every request by its route pattern (e.g. /api/v1/chat/:id), not its path
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		method := c.Request.Method
+
+		httpRequests.WithLabel(method, route, strconv.Itoa(c.Writer.Status())).Inc()
+		httpRequestDuration.WithLabel(method, route).Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,175 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/apperror"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/auth"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+// takeToken refills a token bucket for the time since its last request and takes a token if one is left.
+// Time comes from the Redis server so every API instance refills the bucket alike. Returns 1 and 0 when
+// the token was taken, otherwise 0 and the milliseconds until one is available.
+// ARGV: tokens refilled per millisecond, bucket size.
+var takeToken = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+
+local bucket = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(bucket[1]) or burst
+local ts = tonumber(bucket[2]) or now
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate)
+
+local allowed, wait = 0, 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	wait = math.ceil((1 - tokens) / rate)
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst / rate))
+return {allowed, wait}
+`)
+
+// RateLimitKey selects what a rate limit counts requests by
+type RateLimitKey int
+
+const (
+	// RateLimitByIP gives every client IP its own bucket
+	RateLimitByIP RateLimitKey = iota
+	// RateLimitByUser gives every authenticated user their own bucket, and anonymous requests one per IP.
+	// Attach it after the route's auth middleware.
+	RateLimitByUser
+	// RateLimitByLoginIdentifier gives every account signed in to its own bucket, whatever IP the requests
+	// come from. The account is the identifier field of the JSON body; requests without one count per IP.
+	RateLimitByLoginIdentifier
+	// RateLimitByVerificationToken gives every email completing registration its own bucket. The email is read
+	// from the verification_token field of the JSON body; requests without a valid token count per IP.
+	RateLimitByVerificationToken
+	// RateLimitByLinkToken gives every account a Google sign-in is linked to its own bucket. The account is read
+	// from the link_token field of the JSON body; requests without a valid token count per IP.
+	RateLimitByLinkToken
+)
+
+// rateLimitRedis and rateLimitCfg are injected at startup; without them requests are not limited
+var (
+	rateLimitRedis *redis.Client
+	rateLimitCfg   *config.RateLimitConfig
+)
+
+// SetRateLimitStore injects the Redis client the rate limit buckets are kept in
+func SetRateLimitStore(client *redis.Client, cfg *config.RateLimitConfig) {
+	rateLimitRedis = client
+	rateLimitCfg = cfg
+}
+
+// RateLimit limits requests with a token bucket shared by all API instances: each caller may send burst
+// requests at once, then perMinute requests a minute. Routes using the same policy name share buckets, so
+// give each route its own unless it should count against another's limit.
+// Requests over the limit get 429 with Retry-After. If Redis is unavailable, requests are not limited.
+func RateLimit(policy string, key RateLimitKey, perMinute, burst int) gin.HandlerFunc {
+	rate := float64(perMinute) / float64(time.Minute.Milliseconds())
+
+	return func(c *gin.Context) {
+		if rateLimitRedis == nil || !rateLimitCfg.Enabled || perMinute <= 0 || burst <= 0 {
+			c.Next()
+			return
+		}
+
+		caller := "ip:" + c.ClientIP()
+		switch key {
+		case RateLimitByUser:
+			caller = requestCaller(c)
+		case RateLimitByLoginIdentifier, RateLimitByVerificationToken, RateLimitByLinkToken:
+			if account := requestAccount(c, key); account != "" {
+				caller = "account:" + account
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 2*time.Second)
+		defer cancel()
+
+		result, err := takeToken.Run(ctx, rateLimitRedis, []string{fmt.Sprintf(config.RedisRateLimitKey, policy, caller)},
+			strconv.FormatFloat(rate, 'g', -1, 64), burst).Int64Slice()
+		if err != nil || len(result) != 2 {
+			logger.FromContext(c.Request.Context()).Warn("Rate limit store unavailable, request not limited", "policy", policy, "error", err)
+			c.Next()
+			return
+		}
+
+		if result[0] == 0 {
+			retryAfter := int(math.Ceil(float64(result[1]) / 1000))
+			c.Header("Retry-After", strconv.Itoa(max(retryAfter, 1)))
+			abortWithAppError(c, apperror.ErrRateLimited)
+			return
+		}
+		c.Next()
+	}
+}
+
+// requestAccount returns a hash of the account a sign-in request is for, so Redis keys hold no email addresses.
+// An account given by email hashes the same whichever route it came from. Empty if the body names no account.
+func requestAccount(c *gin.Context, key RateLimitKey) string {
+	var account string
+	switch key {
+	case RateLimitByLoginIdentifier:
+		account = bodyField(c, "identifier")
+	case RateLimitByVerificationToken:
+		if claims, err := auth.ParseVerificationToken(bodyField(c, "verification_token")); err == nil {
+			account = claims.Email
+		}
+	case RateLimitByLinkToken:
+		if claims, err := auth.ParseLinkToken(bodyField(c, "link_token")); err == nil {
+			account = claims.Email
+		}
+	}
+
+	account = strings.ToLower(strings.TrimSpace(account))
+	if account == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(account))
+	return hex.EncodeToString(sum[:])
+}
+
+// bodyField returns a string field of the JSON body and restores the body for the handler.
+// Empty if the body has no such field.
+func bodyField(c *gin.Context, name string) string {
+	if c.Request.Body == nil || c.Request.Body == http.NoBody {
+		return ""
+	}
+	body, err := io.ReadAll(c.Request.Body)
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return ""
+	}
+
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(body, &fields) != nil {
+		return ""
+	}
+	var value string
+	if json.Unmarshal(fields[name], &value) != nil {
+		return ""
+	}
+	return value
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"io"
+	"runtime/debug"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/apperror"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// Recovery turns a panicking handler into the standard error envelope with INTERNAL_ERROR, instead of
+// Gin's bare 500, and logs the panic and its stack with the request's fields.
+// Register it after RequestLogger so the failed request still gets its log line.
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(io.Discard, func(c *gin.Context, recovered any) {
+		logger.FromContext(c.Request.Context()).Error("panic recovered", "panic", recovered, "stack", string(debug.Stack()))
+
+		// A handler that panicked mid-response has already sent its status, only the connection can be cut
+		if c.Writer.Written() {
+			c.Abort()
+			return
+		}
+		err := apperror.ErrInternal
+		dto.SendError(c, apperror.StatusFromError(err), apperror.Message(err), apperror.Code(err))
+		c.Abort()
+	})
+}
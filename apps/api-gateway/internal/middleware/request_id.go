@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/logger"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/requestid"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// maxRequestIDLength bounds caller-supplied IDs so they cannot bloat every log line of the request
+const maxRequestIDLength = 128
+
+// RequestID takes the request ID from the caller (e.g. a proxy) or generates one, then stores it in the
+// Gin context ("requestID"), the request context and the response header. Register it before RequestLogger.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(requestid.Header)
+		if id == "" || len(id) > maxRequestIDLength {
+			id = uuid.NewString()
+		}
+
+		c.Set("requestID", id)
+		c.Header(requestid.Header, id)
+		c.Request = c.Request.WithContext(requestid.NewContext(c.Request.Context(), id))
+		c.Next()
+	}
+}
+
+// RequestScope copies the request ID and logger of c onto ctx, for work that runs on its own context
+// (e.g. a DB timeout context) but must still be correlated with the request
+func RequestScope(c *gin.Context, ctx context.Context) context.Context {
+	reqCtx := c.Request.Context()
+	return logger.WithContext(requestid.NewContext(ctx, requestid.FromContext(reqCtx)), logger.FromContext(reqCtx))
+}
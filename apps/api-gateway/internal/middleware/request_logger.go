@@ -0,0 +1,140 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"mime"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/auth"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/logger"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// RequestLogger attaches a logger with the request ID, method and route to the request context,
+// then logs one line per request with its status, latency and sizes. With cfg.Bodies, JSON request and
+// response bodies up to cfg.BodyMaxBytes are added with passwords, tokens, OTPs and cookies redacted.
+func RequestLogger(cfg *config.LogConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		l := slog.Default().With("request_id", requestid.FromContext(c.Request.Context()), "method", c.Request.Method, "route", route)
+		c.Request = c.Request.WithContext(logger.WithContext(c.Request.Context(), l))
+
+		var requestBody []byte
+		var response *bodyRecorder
+		if cfg.Bodies && c.GetHeader("Upgrade") == "" {
+			requestBody = peekBody(c, cfg.BodyMaxBytes)
+			response = &bodyRecorder{ResponseWriter: c.Writer, max: cfg.BodyMaxBytes}
+			c.Writer = response
+		}
+
+		c.Next()
+
+		// Re-read the logger, the auth middleware adds the user ID to it
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		if status >= 500 {
+			level = slog.LevelError
+		}
+		attrs := []any{
+			"status", status,
+			"path", c.Request.URL.Path,
+			"latency_ms", time.Since(start).Milliseconds(),
+			"request_bytes", c.Request.ContentLength,
+			"response_bytes", c.Writer.Size(),
+			"ip", c.ClientIP(),
+		}
+		if query := c.Request.URL.Query(); len(query) > 0 {
+			attrs = append(attrs, "query", logger.RedactQuery(query))
+		}
+		if requestBody != nil {
+			attrs = append(attrs, "request_body", loggableBody(requestBody, c.ContentType()))
+		}
+		if response != nil && !response.truncated {
+			attrs = append(attrs, "response_body", loggableBody(response.body.Bytes(), c.Writer.Header().Get("Content-Type")))
+		}
+		logger.FromContext(c.Request.Context()).Log(c.Request.Context(), level, "request", attrs...)
+	}
+}
+
+// peekBody returns the request body if it is at most max bytes, leaving it readable for the handler.
+// It returns nil for bodies that are empty or larger.
+func peekBody(c *gin.Context, max int) []byte {
+	if c.Request.Body == nil || c.Request.ContentLength == 0 {
+		return nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, int64(max)+1))
+	c.Request.Body = readCloser{io.MultiReader(bytes.NewReader(body), c.Request.Body), c.Request.Body}
+	if err != nil || len(body) > max || len(body) == 0 {
+		return nil
+	}
+	return body
+}
+
+// loggableBody is the redacted body, or a placeholder for bodies that are not JSON. Redaction needs the
+// whole document, so anything unparseable is left out rather than risk logging a credential.
+func loggableBody(body []byte, contentType string) string {
+	if len(body) == 0 {
+		return ""
+	}
+	if mediaType, _, _ := mime.ParseMediaType(contentType); mediaType != "application/json" {
+		return "[not logged: " + mediaType + "]"
+	}
+	redacted, ok := logger.RedactJSON(body)
+	if !ok {
+		return "[not logged: invalid JSON]"
+	}
+	return redacted
+}
+
+// readCloser reads the peeked bytes and the rest of the body, and closes the original body
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// bodyRecorder keeps a copy of the first max bytes written to the response
+type bodyRecorder struct {
+	gin.ResponseWriter
+	body      bytes.Buffer
+	max       int
+	truncated bool
+}
+
+func (w *bodyRecorder) Write(data []byte) (int, error) {
+	w.record(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *bodyRecorder) WriteString(s string) (int, error) {
+	w.record([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *bodyRecorder) record(data []byte) {
+	if w.truncated {
+		return
+	}
+	if w.body.Len()+len(data) > w.max {
+		w.truncated = true
+		w.body.Reset()
+		return
+	}
+	w.body.Write(data)
+}
+
+// setAuthUser stores the authenticated user for handlers and adds its ID to the request logger
+func setAuthUser(c *gin.Context, user auth.AuthUser) {
+	c.Set("authUser", user)
+	ctx := c.Request.Context()
+	c.Request = c.Request.WithContext(logger.WithContext(ctx, logger.FromContext(ctx).With("user_id", user.ID)))
+}
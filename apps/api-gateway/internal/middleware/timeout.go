@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/apperror"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/util"
+	"github.com/gin-gonic/gin"
+)
+
+// Timeout gives the handlers timeout to run: the request context is canceled when it runs out, so calls
+// made on it (or on RequestContext) stop, and a handler that has not responded by then answers 504.
+// A timeout of 0 sets no limit.
+//
+// The handler keeps running on the request's goroutine until it returns; work on contexts that are not
+// derived from the request is not interrupted. Handlers therefore pass ctx.Request.Context() to services,
+// which derive their database contexts from it with util.NewDBContextFrom.
+func Timeout(timeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if timeout <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && !c.Writer.Written() {
+			dto.SendError(c, apperror.StatusFromError(apperror.ErrRequestTimeout), apperror.ErrRequestTimeout.Message, apperror.ErrRequestTimeout.Code)
+		}
+	}
+}
+
+// RequestContext returns a context for downstream calls made on behalf of the request. It carries the
+// request ID and logger and ends with the route's timeout, but unlike the request context it is not
+// canceled when the client disconnects, so work such as saving a chat answer is not cut short.
+func RequestContext(c *gin.Context) (context.Context, context.CancelFunc) {
+	ctx := RequestScope(c, context.Background())
+	if deadline, ok := c.Request.Context().Deadline(); ok {
+		return context.WithDeadline(ctx, deadline)
+	}
+	return context.WithTimeout(ctx, util.DefaultDBTimeout)
+}
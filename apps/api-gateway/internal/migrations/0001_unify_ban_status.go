@@ -0,0 +1,38 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// unifyBanStatus folds the legacy is_banned flag into is_active, which is what banning sets now, and
+// drops it. Users banned through the flag get an empty ban reason so they still count as banned rather
+// than self-deactivated.
+func unifyBanStatus(ctx context.Context, db *mongo.Database) error {
+	users := db.Collection(config.UserColName)
+
+	_, err := users.UpdateMany(ctx,
+		bson.M{"is_banned": true, "ban_reason": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"ban_reason": ""}},
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = users.UpdateMany(ctx,
+		bson.M{"is_banned": true},
+		bson.M{"$set": bson.M{"is_active": false}},
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = users.UpdateMany(ctx,
+		bson.M{"is_banned": bson.M{"$exists": true}},
+		bson.M{"$unset": bson.M{"is_banned": ""}},
+	)
+	return err
+}
@@ -0,0 +1,181 @@
+// Package migrations applies versioned changes to the MongoDB data, such as renaming fields or reshaping
+// documents, that indexes alone cannot express. Applied versions are recorded in the migrations collection,
+// so each migration runs once per database. They run at startup unless disabled, or with uitctl migrate.
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Migration is one versioned change to the data. Up must be safe to run again: a migration interrupted
+// before it is recorded is applied again from the start.
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(ctx context.Context, db *mongo.Database) error
+}
+
+// all lists the migrations in the order they are applied. Append new ones with the next version;
+// never renumber or remove one that may have been applied somewhere.
+var all = []Migration{
+	{1, "unify_ban_status", unifyBanStatus},
+}
+
+// Record is the migrations collection document of an applied migration
+type Record struct {
+	Version    int       `bson:"_id"`
+	Name       string    `bson:"name"`
+	AppliedAt  time.Time `bson:"applied_at"`
+	DurationMs int64     `bson:"duration_ms"`
+}
+
+// Status is a migration and, once applied, its record
+type Status struct {
+	Migration
+	Applied *Record
+}
+
+// lockID is the _id of the only document in the lock collection
+const lockID = "lock"
+
+// lockRetryInterval is how often an instance waiting for the lock checks it again
+const lockRetryInterval = 2 * time.Second
+
+// Run applies the pending migrations in order and returns the ones it applied. Only one instance applies
+// migrations at a time; the others wait for it and then find nothing left to do. The lock expires after
+// lease, so an instance that crashes while holding it only blocks the others until then.
+func Run(ctx context.Context, db *mongo.Database, lease time.Duration) ([]Migration, error) {
+	if err := validate(); err != nil {
+		return nil, err
+	}
+
+	owner := uuid.New().String()
+	if err := acquireLock(ctx, db, owner, lease); err != nil {
+		return nil, fmt.Errorf("acquire migration lock: %w", err)
+	}
+	defer releaseLock(db, owner)
+
+	applied, err := appliedRecords(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	var ran []Migration
+	for _, m := range all {
+		if _, ok := applied[m.Version]; ok {
+			continue
+		}
+
+		slog.Info("Migration: applying", "version", m.Version, "name", m.Name)
+		start := time.Now()
+		if err := m.Up(ctx, db); err != nil {
+			return ran, fmt.Errorf("migration %d %s: %w", m.Version, m.Name, err)
+		}
+
+		record := Record{
+			Version:    m.Version,
+			Name:       m.Name,
+			AppliedAt:  time.Now(),
+			DurationMs: time.Since(start).Milliseconds(),
+		}
+		if _, err := db.Collection(config.MigrationColName).InsertOne(ctx, record); err != nil {
+			return ran, fmt.Errorf("record migration %d %s: %w", m.Version, m.Name, err)
+		}
+		slog.Info("Migration: applied", "version", m.Version, "name", m.Name, "duration_ms", record.DurationMs)
+		ran = append(ran, m)
+	}
+	return ran, nil
+}
+
+// List returns every migration in order with its record if it was applied
+func List(ctx context.Context, db *mongo.Database) ([]Status, error) {
+	applied, err := appliedRecords(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, len(all))
+	for i, m := range all {
+		statuses[i].Migration = m
+		if record, ok := applied[m.Version]; ok {
+			statuses[i].Applied = &record
+		}
+	}
+	return statuses, nil
+}
+
+// validate catches a migration added out of order before anything is applied
+func validate() error {
+	for i, m := range all {
+		if m.Version != i+1 {
+			return fmt.Errorf("migration %s has version %d, expected %d", m.Name, m.Version, i+1)
+		}
+	}
+	return nil
+}
+
+func appliedRecords(ctx context.Context, db *mongo.Database) (map[int]Record, error) {
+	cursor, err := db.Collection(config.MigrationColName).Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	var records []Record
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int]Record, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return applied, nil
+}
+
+// acquireLock takes the lock once it is free or expired. While another instance holds it, the upsert
+// matches nothing and fails on the existing _id, so the caller waits and tries again.
+func acquireLock(ctx context.Context, db *mongo.Database, owner string, lease time.Duration) error {
+	col := db.Collection(config.MigrationLockColName)
+	for {
+		now := time.Now()
+		_, err := col.UpdateOne(ctx,
+			bson.M{"_id": lockID, "locked_until": bson.M{"$lt": now}},
+			bson.M{"$set": bson.M{"owner": owner, "locked_until": now.Add(lease)}},
+			options.Update().SetUpsert(true),
+		)
+		if err == nil {
+			return nil
+		}
+		if !mongo.IsDuplicateKeyError(err) {
+			return err
+		}
+
+		slog.Info("Migration: another instance is applying migrations, waiting")
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(lockRetryInterval):
+		}
+	}
+}
+
+// releaseLock frees the lock if this instance still holds it. It runs on its own context so the lock is
+// released even when migrating stopped because the caller's context ended.
+func releaseLock(db *mongo.Database, owner string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := db.Collection(config.MigrationLockColName).DeleteOne(ctx, bson.M{"_id": lockID, "owner": owner})
+	if err != nil && !errors.Is(err, context.Canceled) {
+		slog.Error("Migration: failed to release lock, it is released when it expires", "error", err)
+	}
+}
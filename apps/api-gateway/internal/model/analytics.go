@@ -0,0 +1,16 @@
+package model
+
+// ChatDailyStats aggregates chat activity for one day
+type ChatDailyStats struct {
+	Date              string  `bson:"_id" json:"date"` // YYYY-MM-DD in the stats timezone
+	UserMessages      int64   `bson:"user_messages" json:"user_messages"`
+	AssistantMessages int64   `bson:"assistant_messages" json:"assistant_messages"`
+	AvgLatencyMs      float64 `bson:"avg_latency_ms" json:"avg_latency_ms"`
+	TokensUsed        int64   `bson:"tokens_used" json:"tokens_used"`
+}
+
+// NamedCount is a value and how often it occurred, e.g. a tool name and its call count
+type NamedCount struct {
+	Name  string `bson:"_id" json:"name"`
+	Count int64  `bson:"count" json:"count"`
+}
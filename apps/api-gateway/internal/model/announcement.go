@@ -0,0 +1,74 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Announcement is an admin-authored product update delivered to a segment of users as notifications
+type Announcement struct {
+	ID          primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	Title       string              `bson:"title" json:"title"`
+	Message     string              `bson:"message" json:"message"`
+	Link        string              `bson:"link,omitempty" json:"link,omitempty"`
+	Segment     AnnouncementSegment `bson:"segment" json:"segment"`
+	BroadcastWS bool                `bson:"broadcast_ws" json:"broadcast_ws"` // Also push a live banner to connected clients
+
+	// Delivery
+	Status         AnnouncementStatus `bson:"status" json:"status"`
+	RecipientCount int64              `bson:"recipient_count" json:"recipient_count"`
+	SentAt         *time.Time         `bson:"sent_at,omitempty" json:"sent_at,omitempty"`
+
+	CreatedBy primitive.ObjectID `bson:"created_by" json:"created_by"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// AnnouncementSegment filters the users an announcement is delivered to. Empty fields match everyone.
+type AnnouncementSegment struct {
+	Roles                  []Role   `bson:"roles,omitempty" json:"roles,omitempty"`
+	Languages              []string `bson:"languages,omitempty" json:"languages,omitempty"`
+	VerifiedOnly           bool     `bson:"verified_only" json:"verified_only"`
+	FeatureSubscribersOnly bool     `bson:"feature_subscribers_only" json:"feature_subscribers_only"` // Only users with notify_new_features enabled
+}
+
+// AnnouncementStatus tracks the delivery state of an announcement
+type AnnouncementStatus string
+
+const (
+	AnnouncementStatusDraft   AnnouncementStatus = "draft"
+	AnnouncementStatusSending AnnouncementStatus = "sending"
+	AnnouncementStatusSent    AnnouncementStatus = "sent"
+	AnnouncementStatusFailed  AnnouncementStatus = "failed"
+)
+
+// IsEditable checks if the announcement can still be changed or sent
+func (a *Announcement) IsEditable() bool {
+	return a.Status == AnnouncementStatusDraft || a.Status == AnnouncementStatusFailed
+}
+
+// CloneAnnouncement creates a deep copy of an announcement
+func CloneAnnouncement(a *Announcement) *Announcement {
+	if a == nil {
+		return nil
+	}
+
+	clone := *a
+
+	// Deep copy segment slices
+	if a.Segment.Roles != nil {
+		clone.Segment.Roles = append([]Role(nil), a.Segment.Roles...)
+	}
+	if a.Segment.Languages != nil {
+		clone.Segment.Languages = append([]string(nil), a.Segment.Languages...)
+	}
+
+	// Deep copy SentAt
+	if a.SentAt != nil {
+		t := *a.SentAt
+		clone.SentAt = &t
+	}
+
+	return &clone
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AuditLog records a privileged action performed by an admin
+type AuditLog struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ActorID    primitive.ObjectID `bson:"actor_id" json:"actor_id"` // Admin who performed the action
+	Action     AuditAction        `bson:"action" json:"action"`
+	TargetType string             `bson:"target_type" json:"target_type"` // "user" | "chat_session" | "chat_messages"
+	TargetID   string             `bson:"target_id" json:"target_id"`
+	Metadata   map[string]string  `bson:"metadata,omitempty" json:"metadata,omitempty"`
+	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// AuditAction identifies the kind of audited action
+type AuditAction string
+
+const (
+	AuditActionViewUserChatSessions AuditAction = "view_user_chat_sessions"
+	AuditActionViewChatMessages     AuditAction = "view_chat_messages"
+	AuditActionEraseUser            AuditAction = "erase_user"
+	AuditActionChangeUserRole       AuditAction = "change_user_role"
+	AuditActionForceLogout          AuditAction = "force_logout"
+	AuditActionUpdateUser           AuditAction = "update_user"
+	AuditActionSearchChatMessages   AuditAction = "search_chat_messages"
+	AuditActionSetUserQuota         AuditAction = "set_user_quota"
+	AuditActionClearUserQuota       AuditAction = "clear_user_quota"
+	AuditActionAddUserNote          AuditAction = "add_user_note"
+)
+
+// Audit target types
+const (
+	AuditTargetUser         = "user"
+	AuditTargetChatSession  = "chat_session"
+	AuditTargetChatMessages = "chat_messages" // Search across all transcripts, target ID is the user filter if any
+)
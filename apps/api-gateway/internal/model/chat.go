@@ -10,7 +10,8 @@ import (
 type ChatSession struct {
 	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
 	UserID    primitive.ObjectID `bson:"user_id" json:"user_id"`
-	Title     string             `bson:"title" json:"title"` // Auto-generated or user-set
+	Title     string             `bson:"title" json:"title"`                           // Auto-generated or user-set
+	Language  string             `bson:"language,omitempty" json:"language,omitempty"` // "vi" | "en", empty = follow user settings
 	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
 	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
 	DeletedAt *time.Time         `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"` // Soft delete
@@ -26,6 +27,34 @@ type ChatMessage struct {
 	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
 }
 
+// ChatSessionSummary is a chat session joined with its latest message and message count.
+// Produced by the session list aggregation so the sidebar can render previews in one request.
+type ChatSessionSummary struct {
+	ChatSession  `bson:",inline"`
+	LastMessage  *ChatMessage `bson:"last_message,omitempty" json:"last_message,omitempty"`
+	MessageCount int64        `bson:"message_count" json:"message_count"`
+}
+
+// ResolveLanguage returns the session language override, falling back to the user's default language
+func (s *ChatSession) ResolveLanguage(settings *UserSettings) string {
+	if s.Language != "" {
+		return s.Language
+	}
+	if settings != nil {
+		return settings.ChatLanguage()
+	}
+	return LanguageVI
+}
+
+// Source is a normalized RAG citation stored in assistant message metadata
+type Source struct {
+	Title    string  `bson:"title" json:"title"`
+	URL      string  `bson:"url,omitempty" json:"url,omitempty"`
+	Snippet  string  `bson:"snippet,omitempty" json:"snippet,omitempty"` // Truncated content
+	Score    float32 `bson:"score" json:"score"`
+	DeadLink bool    `bson:"dead_link,omitempty" json:"dead_link,omitempty"` // URL did not respond successfully
+}
+
 // MessageRole defines the sender of a message
 type MessageRole string
 
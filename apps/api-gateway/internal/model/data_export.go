@@ -0,0 +1,32 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DataExport is a user's request for an archive of all their data (takeout).
+// The archive is built in the background and kept until ExpiresAt.
+type DataExport struct {
+	ID          primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	UserID      primitive.ObjectID  `bson:"user_id" json:"user_id"`
+	Status      DataExportStatus    `bson:"status" json:"status"`
+	FileID      *primitive.ObjectID `bson:"file_id,omitempty" json:"-"` // GridFS file of the ZIP archive
+	SizeBytes   int64               `bson:"size_bytes,omitempty" json:"size_bytes,omitempty"`
+	Error       string              `bson:"error,omitempty" json:"error,omitempty"`
+	CreatedAt   time.Time           `bson:"created_at" json:"created_at"`
+	StartedAt   *time.Time          `bson:"started_at,omitempty" json:"started_at,omitempty"`
+	CompletedAt *time.Time          `bson:"completed_at,omitempty" json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time          `bson:"expires_at,omitempty" json:"expires_at,omitempty"` // Archive is deleted after this
+}
+
+type DataExportStatus string
+
+const (
+	DataExportStatusPending    DataExportStatus = "pending"
+	DataExportStatusProcessing DataExportStatus = "processing"
+	DataExportStatusReady      DataExportStatus = "ready"
+	DataExportStatusFailed     DataExportStatus = "failed"
+	DataExportStatusExpired    DataExportStatus = "expired"
+)
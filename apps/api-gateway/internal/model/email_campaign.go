@@ -0,0 +1,83 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// EmailCampaign is an admin-authored email sent to a segment of users.
+// Body is an html/template rendered per recipient with EmailCampaignData.
+type EmailCampaign struct {
+	ID       primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Subject  string             `bson:"subject" json:"subject"`
+	Body     string             `bson:"body" json:"body"`
+	Audience EmailAudience      `bson:"audience" json:"audience"`
+	Role     Role               `bson:"role,omitempty" json:"role,omitempty"` // Only for the role audience
+
+	// Delivery
+	Status         EmailCampaignStatus `bson:"status" json:"status"`
+	RecipientCount int64               `bson:"recipient_count" json:"recipient_count"`
+	SentCount      int64               `bson:"sent_count" json:"sent_count"`
+	FailedCount    int64               `bson:"failed_count" json:"failed_count"`
+	QueuedAt       *time.Time          `bson:"queued_at,omitempty" json:"queued_at,omitempty"` // Set once every recipient is queued
+	LastQueuedID   *primitive.ObjectID `bson:"last_queued_id,omitempty" json:"-"`              // Last user queued, queueing resumes after it
+	QueueingAt     *time.Time          `bson:"queueing_at,omitempty" json:"-"`                 // Last progress of the worker queueing recipients
+	CompletedAt    *time.Time          `bson:"completed_at,omitempty" json:"completed_at,omitempty"`
+
+	CreatedBy primitive.ObjectID `bson:"created_by" json:"created_by"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// EmailCampaignData is the data available to a campaign body template
+type EmailCampaignData struct {
+	Username string
+	Email    string
+}
+
+// EmailAudience selects the users a campaign is sent to. Banned and deleted users are always excluded.
+type EmailAudience string
+
+const (
+	EmailAudienceAll    EmailAudience = "all"
+	EmailAudienceActive EmailAudience = "active" // Logged in within EmailAudienceActiveDays
+	EmailAudienceRole   EmailAudience = "role"
+)
+
+// EmailAudienceActiveDays is the login window of the active audience
+const EmailAudienceActiveDays = 30
+
+// EmailCampaignStatus tracks the delivery state of a campaign
+type EmailCampaignStatus string
+
+const (
+	EmailCampaignStatusDraft   EmailCampaignStatus = "draft"
+	EmailCampaignStatusSending EmailCampaignStatus = "sending" // Recipients being queued, emails going out
+	EmailCampaignStatusSent    EmailCampaignStatus = "sent"    // Every queued email was sent or failed
+)
+
+// EmailDelivery is one queued campaign email and its delivery status
+type EmailDelivery struct {
+	ID         primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	CampaignID primitive.ObjectID  `bson:"campaign_id" json:"campaign_id"`
+	UserID     primitive.ObjectID  `bson:"user_id" json:"user_id"`
+	Email      string              `bson:"email" json:"email"`
+	Username   string              `bson:"username" json:"username"`
+	Status     EmailDeliveryStatus `bson:"status" json:"status"`
+	Error      string              `bson:"error,omitempty" json:"error,omitempty"`
+	SentAt     *time.Time          `bson:"sent_at,omitempty" json:"sent_at,omitempty"`
+	CreatedAt  time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt  time.Time           `bson:"updated_at" json:"updated_at"`
+}
+
+// EmailDeliveryStatus is the state of a queued campaign email
+type EmailDeliveryStatus string
+
+const (
+	EmailDeliveryPending    EmailDeliveryStatus = "pending"
+	EmailDeliveryProcessing EmailDeliveryStatus = "processing" // Claimed by a sender
+	EmailDeliveryQueued     EmailDeliveryStatus = "queued"     // Rendered and handed to the email queue
+	EmailDeliverySent       EmailDeliveryStatus = "sent"
+	EmailDeliveryFailed     EmailDeliveryStatus = "failed"
+)
@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+// EmailJob is an email waiting in the send queue, or given up on after too many failed attempts
+type EmailJob struct {
+	ID             string       `json:"id"`
+	Kind           EmailJobKind `json:"kind"`
+	To             string       `json:"to"`
+	OTP            string       `json:"otp,omitempty"` // Verification emails
+	Subject        string       `json:"subject,omitempty"`
+	Message        string       `json:"message,omitempty"`
+	Link           string       `json:"link,omitempty"`
+	UnsubscribeURL string       `json:"unsubscribe_url,omitempty"`
+	UnreadCount    int64        `json:"unread_count,omitempty"` // Digest emails
+	DigestItems    []DigestItem `json:"digest_items,omitempty"`
+	HTML           string       `json:"html,omitempty"`        // Campaign emails, already rendered for the recipient
+	DeliveryID     string       `json:"delivery_id,omitempty"` // Campaign delivery the outcome is recorded on
+	Attempts       int          `json:"attempts"`
+	LastError      string       `json:"last_error,omitempty"`
+	CreatedAt      time.Time    `json:"created_at"`
+	FailedAt       *time.Time   `json:"failed_at,omitempty"` // Set when the job is moved to the dead-letter list
+}
+
+type EmailJobKind string
+
+const (
+	EmailJobVerification EmailJobKind = "verification"
+	EmailJobNotification EmailJobKind = "notification"
+	EmailJobDigest       EmailJobKind = "digest"
+	EmailJobCampaign     EmailJobKind = "campaign"
+)
+
+// DigestItem is a notification listed in a queued digest email
+type DigestItem struct {
+	Message   string    `json:"message"`
+	Link      string    `json:"link,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -8,11 +8,13 @@ import (
 
 // EmailVerification stores temporary email verification data before user registration
 type EmailVerification struct {
-	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	Email        string             `bson:"email" json:"email"`
-	OTP          string             `bson:"otp" json:"-"` // Hidden from JSON
-	OTPExpiresAt time.Time          `bson:"otp_expires_at" json:"otp_expires_at"`
-	IsVerified   bool               `bson:"is_verified" json:"is_verified"` // true after OTP verified
-	Nonce        string             `bson:"nonce" json:"-"`                 // Used in verification token to prevent replay
-	CreatedAt    time.Time          `bson:"created_at" json:"created_at"`
+	ID           primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	Email        string              `bson:"email" json:"email"`
+	OTP          string              `bson:"otp" json:"-"` // Hidden from JSON
+	OTPExpiresAt time.Time           `bson:"otp_expires_at" json:"otp_expires_at"`
+	IsVerified   bool                `bson:"is_verified" json:"is_verified"`  // true after OTP verified
+	Nonce        string              `bson:"nonce" json:"-"`                  // Used in verification token to prevent replay
+	UserID       *primitive.ObjectID `bson:"user_id,omitempty" json:"-"`      // Account the verification was consumed by
+	CompletedAt  *time.Time          `bson:"completed_at,omitempty" json:"-"` // Consumed verifications expire a while after this
+	CreatedAt    time.Time           `bson:"created_at" json:"created_at"`
 }
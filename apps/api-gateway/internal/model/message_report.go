@@ -0,0 +1,46 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MessageReport is a user's report of a bad assistant answer, reviewed in the admin moderation queue
+type MessageReport struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	MessageID  primitive.ObjectID `bson:"message_id" json:"message_id"`
+	SessionID  primitive.ObjectID `bson:"session_id" json:"session_id"`
+	ReporterID primitive.ObjectID `bson:"reporter_id" json:"reporter_id"`
+	Reason     ReportReason       `bson:"reason" json:"reason"`
+	Comment    string             `bson:"comment,omitempty" json:"comment,omitempty"`
+
+	// Snapshot of the reported answer, kept even if the user deletes the session
+	MessageContent string `bson:"message_content" json:"message_content"`
+
+	// Review
+	Status         ReportStatus        `bson:"status" json:"status"`
+	ResolutionNote string              `bson:"resolution_note,omitempty" json:"resolution_note,omitempty"`
+	ReviewedBy     *primitive.ObjectID `bson:"reviewed_by,omitempty" json:"reviewed_by,omitempty"`
+	ReviewedAt     *time.Time          `bson:"reviewed_at,omitempty" json:"reviewed_at,omitempty"`
+
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+}
+
+// ReportReason categorizes what is wrong with a reported answer
+type ReportReason string
+
+const (
+	ReportReasonWrongInfo      ReportReason = "wrong_info"
+	ReportReasonHarmful        ReportReason = "harmful"
+	ReportReasonOutdatedPolicy ReportReason = "outdated_policy" // Outdated UIT policy or regulation
+)
+
+// ReportStatus tracks a report through the moderation queue
+type ReportStatus string
+
+const (
+	ReportStatusOpen      ReportStatus = "open"
+	ReportStatusResolved  ReportStatus = "resolved"  // Knowledge base fixed
+	ReportStatusDismissed ReportStatus = "dismissed" // Answer was correct
+)
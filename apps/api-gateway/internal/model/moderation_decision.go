@@ -0,0 +1,40 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ModerationDecision records one Gemini content check, used to tune the moderation threshold.
+// Only a hash of the content is stored, never the content itself.
+type ModerationDecision struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ContentHash string             `bson:"content_hash" json:"content_hash"` // SHA-256 of title, text and media URLs
+	ImageCount  int                `bson:"image_count" json:"image_count"`
+	VideoCount  int                `bson:"video_count" json:"video_count"`
+
+	// Gemini response, empty when the check failed
+	IsViolation bool     `bson:"is_violation" json:"is_violation"`
+	Confidence  float64  `bson:"confidence" json:"confidence"`
+	Categories  []string `bson:"categories" json:"categories"`
+	Reason      string   `bson:"reason,omitempty" json:"reason,omitempty"`
+
+	// Decision
+	Action    ModerationAction `bson:"action" json:"action"`
+	Threshold float64          `bson:"threshold" json:"threshold"` // Confidence threshold in effect at check time
+	Model     string           `bson:"model" json:"model"`
+	Error     string           `bson:"error,omitempty" json:"error,omitempty"`
+	LatencyMs int64            `bson:"latency_ms" json:"latency_ms"`
+
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+}
+
+// ModerationAction is the action taken on checked content
+type ModerationAction string
+
+const (
+	ModerationActionApproved ModerationAction = "approved"
+	ModerationActionRejected ModerationAction = "rejected" // Violation at or above the confidence threshold
+	ModerationActionFailed   ModerationAction = "failed"   // Gemini call failed, no decision was made
+)
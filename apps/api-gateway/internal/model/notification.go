@@ -13,7 +13,9 @@ type Notification struct {
 	Type        NotificationType       `bson:"type,omitempty" json:"type,omitempty"`
 	Message     string                 `bson:"message,omitempty" json:"message,omitempty"`
 	Link        string                 `bson:"link,omitempty" json:"link,omitempty"`
+	Data        *NotificationData      `bson:"data,omitempty" json:"data,omitempty"` // Deep link for clients to route on
 	IsRead      bool                   `bson:"is_read,omitempty" json:"is_read,omitempty"`
+	ReadAt      *time.Time             `bson:"read_at,omitempty" json:"read_at,omitempty"` // Drives the retention TTL index
 	Metadata    map[string]interface{} `bson:"metadata,omitempty" json:"metadata,omitempty"`
 	CreatedAt   time.Time              `bson:"created_at,omitempty" json:"created_at,omitempty"`
 }
@@ -27,4 +29,90 @@ const (
 	NotificationTypeMention    NotificationType = "mention"
 	NotificationTypeNewMessage NotificationType = "new_message"
 	NotificationTypeSystem     NotificationType = "system"
+
+	NotificationTypeChatCompleted    NotificationType = "chat_completed"
+	NotificationTypeDeadlineReminder NotificationType = "deadline_reminder"
+	NotificationTypeAnnouncement     NotificationType = "announcement"
+	NotificationTypeDataExport       NotificationType = "data_export"
+	NotificationTypeUsageLimit       NotificationType = "usage_limit"
+	NotificationTypeCookieExpired    NotificationType = "cookie_expired"
+	NotificationTypeGradePosted      NotificationType = "grade_posted"
+	NotificationTypeUITAnnouncement  NotificationType = "uit_announcement"
+)
+
+// NotificationData is a structured deep link telling the SPA and extension exactly where to go,
+// e.g. open chat session X or the notifications tab of settings. Link stays as the plain URL fallback.
+type NotificationData struct {
+	EntityType NotificationEntityType `bson:"entity_type" json:"entity_type"`
+	EntityID   string                 `bson:"entity_id,omitempty" json:"entity_id,omitempty"` // Settings tab name for EntityTypeSettings
+	Action     NotificationAction     `bson:"action" json:"action"`
+}
+
+type NotificationEntityType string
+
+const (
+	EntityTypeChatSession  NotificationEntityType = "chat_session"
+	EntityTypeSettings     NotificationEntityType = "settings"
+	EntityTypeAnnouncement NotificationEntityType = "announcement"
+	EntityTypeNotification NotificationEntityType = "notification"
+	EntityTypeDataExport   NotificationEntityType = "data_export"
+	EntityTypeCookie       NotificationEntityType = "cookie" // EntityID is the cookie source
 )
+
+type NotificationAction string
+
+const (
+	NotificationActionOpen     NotificationAction = "open"
+	NotificationActionView     NotificationAction = "view"
+	NotificationActionDownload NotificationAction = "download"
+	NotificationActionSync     NotificationAction = "sync" // The extension should sync the cookie again
+)
+
+// NotificationPreference controls through which channels a notification type is delivered
+type NotificationPreference struct {
+	InApp bool `bson:"in_app" json:"in_app"`
+	Email bool `bson:"email" json:"email"`
+}
+
+// DefaultNotificationPreferences returns the delivery defaults for user-configurable notification types
+func DefaultNotificationPreferences() map[NotificationType]NotificationPreference {
+	return map[NotificationType]NotificationPreference{
+		NotificationTypeSystem:           {InApp: true, Email: false},
+		NotificationTypeChatCompleted:    {InApp: true, Email: false},
+		NotificationTypeDeadlineReminder: {InApp: true, Email: true},
+		NotificationTypeAnnouncement:     {InApp: true, Email: false},
+		NotificationTypeDataExport:       {InApp: true, Email: true},
+		NotificationTypeUsageLimit:       {InApp: true, Email: false},
+		NotificationTypeCookieExpired:    {InApp: true, Email: false},
+		NotificationTypeGradePosted:      {InApp: true, Email: true},
+		NotificationTypeUITAnnouncement:  {InApp: true, Email: false},
+	}
+}
+
+// IsTransactionalNotificationType checks if emails of the notification type answer a user's own request.
+// Transactional emails are always sent and carry no unsubscribe link.
+func IsTransactionalNotificationType(t NotificationType) bool {
+	return t == NotificationTypeDataExport
+}
+
+// EmailCategory groups non-essential emails a user can unsubscribe from
+type EmailCategory string
+
+const (
+	EmailCategoryNotification EmailCategory = "notification" // Notification emails of one type
+	EmailCategoryDigest       EmailCategory = "digest"       // Unread notification digest
+	EmailCategoryProduct      EmailCategory = "product"      // Admin campaigns about product news
+)
+
+// IsConfigurableNotificationType checks if users can set preferences for the notification type
+func IsConfigurableNotificationType(t NotificationType) bool {
+	_, ok := DefaultNotificationPreferences()[t]
+	return ok
+}
+
+// UnreadDigest groups a recipient's unread notifications for the email digest
+type UnreadDigest struct {
+	RecipientID   primitive.ObjectID `bson:"_id"`
+	UnreadCount   int64              `bson:"unread_count"`
+	Notifications []*Notification    `bson:"notifications"` // Most recent first, capped
+}
@@ -0,0 +1,19 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// OutboxEvent is a domain event stored with the change that caused it, until the relay publishes it on the event bus.
+// Payload is the JSON form the Redis event bus carries, so the relay can rebuild the event from its topic.
+type OutboxEvent struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty"`
+	Topic        string             `bson:"topic"`
+	Payload      string             `bson:"payload"`
+	CreatedAt    time.Time          `bson:"created_at"`
+	ClaimedUntil *time.Time         `bson:"claimed_until,omitempty"` // A relay is publishing the event, others skip it until then
+	Attempts     int                `bson:"attempts"`
+	DeliveredAt  *time.Time         `bson:"delivered_at,omitempty"`
+}
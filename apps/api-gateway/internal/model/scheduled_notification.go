@@ -0,0 +1,45 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ScheduledNotification is a notification queued for delivery at a future time
+type ScheduledNotification struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	RecipientID primitive.ObjectID `bson:"recipient_id" json:"recipient_id"`
+	Type        NotificationType   `bson:"type" json:"type"`
+	Message     string             `bson:"message" json:"message"`
+	Link        string             `bson:"link,omitempty" json:"link,omitempty"`
+	Data        *NotificationData  `bson:"data,omitempty" json:"data,omitempty"`
+	DeliverAt   time.Time          `bson:"deliver_at" json:"deliver_at"`
+
+	// Set when the admin scheduled a wall-clock time, resolved to DeliverAt in the recipient's timezone
+	LocalTime string `bson:"local_time,omitempty" json:"local_time,omitempty"`
+	Timezone  string `bson:"timezone,omitempty" json:"timezone,omitempty"`
+
+	// Delivery
+	Status      ScheduledNotificationStatus `bson:"status" json:"status"`
+	DeliveredAt *time.Time                  `bson:"delivered_at,omitempty" json:"delivered_at,omitempty"`
+	Error       string                      `bson:"error,omitempty" json:"error,omitempty"` // Last delivery error
+
+	CreatedBy *primitive.ObjectID `bson:"created_by,omitempty" json:"created_by,omitempty"` // nil = scheduled by a system job
+	CreatedAt time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time           `bson:"updated_at" json:"updated_at"`
+}
+
+// ScheduledLocalTimeLayout is the format of a wall-clock delivery time
+const ScheduledLocalTimeLayout = "2006-01-02T15:04"
+
+// ScheduledNotificationStatus tracks the delivery state of a scheduled notification
+type ScheduledNotificationStatus string
+
+const (
+	ScheduledStatusPending    ScheduledNotificationStatus = "pending"
+	ScheduledStatusProcessing ScheduledNotificationStatus = "processing" // Claimed by the scheduler
+	ScheduledStatusDelivered  ScheduledNotificationStatus = "delivered"
+	ScheduledStatusCancelled  ScheduledNotificationStatus = "cancelled"
+	ScheduledStatusFailed     ScheduledNotificationStatus = "failed"
+)
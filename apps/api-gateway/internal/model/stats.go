@@ -0,0 +1,7 @@
+package model
+
+// DailyCount is the number of documents created on one day, as produced by the per-day aggregations
+type DailyCount struct {
+	Date  string `bson:"_id" json:"date"` // YYYY-MM-DD in the stats timezone
+	Count int64  `bson:"count" json:"count"`
+}
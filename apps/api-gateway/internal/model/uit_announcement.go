@@ -0,0 +1,22 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// UITAnnouncement is a notice crawled from an official UIT announcement page
+type UITAnnouncement struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Source      string             `bson:"source" json:"source"` // Host of the page it was found on, e.g. "daa.uit.edu.vn"
+	URL         string             `bson:"url" json:"url"`
+	Title       string             `bson:"title" json:"title"`
+	Summary     string             `bson:"summary,omitempty" json:"summary,omitempty"`
+	PublishedAt *time.Time         `bson:"published_at,omitempty" json:"published_at,omitempty"` // nil if the listing shows no date
+	Hash        string             `bson:"hash" json:"-"`                                        // Of the source and title, catches a notice re-posted under a new URL
+	Cohorts     []int              `bson:"cohorts,omitempty" json:"cohorts,omitempty"`           // Student cohorts (khóa) named in the title, empty = everyone
+
+	NotifiedCount int64     `bson:"notified_count" json:"notified_count"`
+	CreatedAt     time.Time `bson:"created_at" json:"created_at"`
+}
@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// UITExamSchedule is a student's exam schedule parsed from the DAA portal.
+// Field names match the exam schedule returned by the MCP server's DAA scraper.
+type UITExamSchedule struct {
+	Semester  string    `json:"semester,omitempty"`
+	Exams     []UITExam `json:"exams"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// UITExam is one exam of the schedule
+type UITExam struct {
+	CourseCode string `json:"course_code,omitempty"`
+	ClassCode  string `json:"class_code,omitempty"`
+	ExamPeriod string `json:"exam_period,omitempty"` // Ca/Tiết thi, e.g. "Ca 1" or "Tiết 1-3"
+	DayOfWeek  string `json:"day_of_week,omitempty"`
+	ExamDate   string `json:"exam_date,omitempty"` // dd/mm/yyyy
+	Room       string `json:"room,omitempty"`
+	ExamForm   string `json:"exam_form,omitempty"` // Hình thức thi
+}
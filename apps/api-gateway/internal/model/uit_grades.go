@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// UITGrades is a student's grade table parsed from the DAA portal
+type UITGrades struct {
+	Courses   []UITCourseGrade `json:"courses"`
+	FetchedAt time.Time        `json:"fetched_at"`
+}
+
+// UITCourseGrade is one course of the grade table. Field names match the MCP server's DAA scraper.
+// Scores are on a 10-point scale and nil until posted.
+type UITCourseGrade struct {
+	Semester        string   `json:"semester,omitempty"` // e.g. "HK1 năm học 2024-2025"
+	CourseCode      string   `json:"course_code"`
+	CourseName      string   `json:"course_name"`
+	Credits         int      `json:"credits"`
+	AttendanceScore *float64 `json:"attendance_score"` // Điểm quá trình
+	MidtermScore    *float64 `json:"midterm_score"`
+	PracticeScore   *float64 `json:"practice_score"`
+	FinalScore      *float64 `json:"final_score"`
+	AverageScore    *float64 `json:"average_score"` // Điểm học phần
+	IsExempt        bool     `json:"is_exempt"`     // Miễn, e.g. physical education
+}
@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// UITOpenClasses is the list of classes open for course registration (đăng ký học phần)
+type UITOpenClasses struct {
+	Classes   []UITOpenClass `json:"classes"`
+	FetchedAt time.Time      `json:"fetched_at"`
+}
+
+// UITOpenClass is one class open for registration. Day and period use the same format as UITScheduleClass.
+type UITOpenClass struct {
+	ClassCode  string `json:"class_code"` // e.g. "IT003.Q12"
+	Subject    string `json:"subject"`
+	Credits    int    `json:"credits,omitempty"`
+	DayOfWeek  string `json:"day_of_week,omitempty"`
+	Period     string `json:"period,omitempty"`
+	Room       string `json:"room,omitempty"`
+	Lecturer   string `json:"lecturer,omitempty"`
+	Capacity   int    `json:"capacity"`
+	Registered int    `json:"registered"`
+	Remaining  int    `json:"remaining"` // Slots left, never negative
+}
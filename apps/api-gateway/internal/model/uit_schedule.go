@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// UITSchedule is a student's weekly timetable parsed from the DAA portal.
+// Field names match the schedule returned by the MCP server's DAA scraper so the agent reads both the same way.
+type UITSchedule struct {
+	Semester  string             `json:"semester,omitempty"` // e.g. "HK1 năm học 2025-2026"
+	Classes   []UITScheduleClass `json:"classes"`
+	FetchedAt time.Time          `json:"fetched_at"`
+}
+
+// UITScheduleClass is one class of the timetable
+type UITScheduleClass struct {
+	DayOfWeek   string `json:"day_of_week,omitempty"` // "2".."7" or "CN", empty for classes without a fixed slot
+	Period      string `json:"period,omitempty"`      // e.g. "1-4"
+	SubjectCode string `json:"subject_code"`          // Subject and class, e.g. "SE113.Q11"
+	Subject     string `json:"subject"`
+	Type        string `json:"type,omitempty"` // "lt" = lecture, "ht1" | "ht2" = practice
+	Room        string `json:"room,omitempty"`
+	DateRange   string `json:"date_range,omitempty"` // e.g. "08/09/25 -> 29/11/25"
+	ClassSize   string `json:"class_size,omitempty"`
+}
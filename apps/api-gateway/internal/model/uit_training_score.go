@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// UITTrainingScore is a student's training score (điểm rèn luyện) history parsed from the DRL portal
+type UITTrainingScore struct {
+	Terms     []UITTrainingScoreTerm `json:"terms"`   // Oldest first, as listed by DRL
+	Average   float64                `json:"average"` // Mean score of the graded terms, 0 if none is graded yet
+	FetchedAt time.Time              `json:"fetched_at"`
+}
+
+// UITTrainingScoreTerm is the training score of one term
+type UITTrainingScoreTerm struct {
+	Semester     string `json:"semester"`                // e.g. "HK1"
+	AcademicYear string `json:"academic_year,omitempty"` // e.g. "2024-2025"
+	Score        *int   `json:"score"`                   // 0-100, nil while the term is not graded yet
+	Rank         string `json:"rank,omitempty"`          // Xếp loại, e.g. "Tốt"
+}
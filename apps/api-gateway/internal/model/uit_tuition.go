@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// UITTuition is a student's tuition fee status parsed from the DAA portal
+type UITTuition struct {
+	Terms       []UITTuitionTerm `json:"terms"`
+	Outstanding int64            `json:"outstanding"` // Total still owed over all terms, VND
+	FetchedAt   time.Time        `json:"fetched_at"`
+}
+
+// UITTuitionTerm is the tuition of one term. Amounts are in VND.
+type UITTuitionTerm struct {
+	Semester  string `json:"semester"`
+	Amount    int64  `json:"amount"`             // Học phí phải đóng
+	Paid      int64  `json:"paid"`               // Đã đóng
+	Remaining int64  `json:"remaining"`          // Còn nợ
+	Deadline  string `json:"deadline,omitempty"` // Hạn đóng, dd/mm/yyyy
+}
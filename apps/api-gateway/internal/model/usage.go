@@ -0,0 +1,33 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// UserUsage is a user's agent usage for one calendar month, rolled up from assistant message metadata
+type UserUsage struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID        primitive.ObjectID `bson:"user_id" json:"user_id"`
+	Month         string             `bson:"month" json:"month"`       // YYYY-MM in the stats timezone
+	Requests      int64              `bson:"requests" json:"requests"` // Assistant answers
+	TokensUsed    int64              `bson:"tokens_used" json:"tokens_used"`
+	EstimatedCost float64            `bson:"estimated_cost" json:"estimated_cost"` // USD, at the token price of the last rollup
+	UpdatedAt     time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// UserUsageRollup is the usage of one user over a period, computed from chat messages
+type UserUsageRollup struct {
+	UserID     primitive.ObjectID `bson:"_id"`
+	Requests   int64              `bson:"requests"`
+	TokensUsed int64              `bson:"tokens_used"`
+}
+
+// UsageTotals sums the usage of all users for one month
+type UsageTotals struct {
+	Users         int64   `bson:"users" json:"users"`
+	Requests      int64   `bson:"requests" json:"requests"`
+	TokensUsed    int64   `bson:"tokens_used" json:"tokens_used"`
+	EstimatedCost float64 `bson:"estimated_cost" json:"estimated_cost"`
+}
@@ -27,19 +27,40 @@ type User struct {
 	ProviderID string       `bson:"provider_id,omitempty" json:"-"` // Google ID
 	IsVerified bool         `bson:"is_verified" json:"is_verified"`
 
+	// Sign-in methods linked after registration, e.g. Google on a local account
+	LinkedProviders []AuthProvider `bson:"linked_providers,omitempty" json:"linked_providers,omitempty"`
+
 	// Role
 	Role Role `bson:"role" json:"role"` // "user" | "admin"
 
 	// Profile
-	Avatar *Image `bson:"avatar,omitempty" json:"avatar,omitempty"` // Avatar image
+	Avatar  *Image         `bson:"avatar,omitempty" json:"avatar,omitempty"` // Avatar image
+	Student StudentProfile `bson:"student" json:"student"`                   // Empty fields if not filled in yet
 
 	// Settings
 	Settings UserSettings `bson:"settings" json:"settings"`
 
 	// Status
-	IsActive  bool       `bson:"is_active" json:"is_active"`
-	BanUntil  *time.Time `bson:"ban_until,omitempty" json:"ban_until,omitempty"`
-	BanReason *string    `bson:"ban_reason,omitempty" json:"ban_reason,omitempty"` // nil if not banned
+	IsActive      bool       `bson:"is_active" json:"is_active"`
+	BanUntil      *time.Time `bson:"ban_until,omitempty" json:"ban_until,omitempty"`
+	BanReason     *string    `bson:"ban_reason,omitempty" json:"ban_reason,omitempty"`         // nil if not banned
+	DeactivatedAt *time.Time `bson:"deactivated_at,omitempty" json:"deactivated_at,omitempty"` // Set by the user themselves, cleared on their next login
+
+	// Chat quota, nil = default limits from config
+	QuotaOverride *UserQuota `bson:"quota_override,omitempty" json:"quota_override,omitempty"`
+
+	// Support notes, append-only and only exposed through admin endpoints
+	AdminNotes []AdminNote `bson:"admin_notes,omitempty" json:"-"`
+
+	// Activity
+	LastLogin   *time.Time `bson:"last_login,omitempty" json:"last_login,omitempty"` // Updated on login and token refresh
+	LastLoginIP string     `bson:"last_login_ip,omitempty" json:"-"`                 // Client IP of that login, only exposed to admins
+
+	// Email digest
+	LastDigestSentAt *time.Time `bson:"last_digest_sent_at,omitempty" json:"-"`
+
+	// Random secret signed into the iCalendar feed link, replaced to revoke the link
+	CalendarToken string `bson:"calendar_token,omitempty" json:"-"`
 
 	// Timestamps
 	CreatedAt time.Time  `bson:"created_at" json:"created_at"`
@@ -57,9 +78,41 @@ const (
 
 // UserSettings contains user preference settings
 type UserSettings struct {
-	Language          string `bson:"language" json:"language"`                       // "vi" | "en"
-	Theme             string `bson:"theme" json:"theme"`                             // "light" | "dark"
-	NotifyNewFeatures bool   `bson:"notify_new_features" json:"notify_new_features"` // Notify about new features
+	Language string `bson:"language" json:"language"` // "vi" | "en"
+	Theme    string `bson:"theme" json:"theme"`       // "light" | "dark"
+
+	// Accessibility, shared by the SPA and the extension
+	FontScale     float64 `bson:"font_scale,omitempty" json:"font_scale"` // Multiplier of the base font size, 0 = DefaultFontScale
+	ReducedMotion bool    `bson:"reduced_motion" json:"reduced_motion"`
+	HighContrast  bool    `bson:"high_contrast" json:"high_contrast"`
+
+	NotifyNewFeatures bool `bson:"notify_new_features" json:"notify_new_features"` // Notify about new features
+
+	DigestFrequency string `bson:"digest_frequency,omitempty" json:"digest_frequency"` // "off" | "daily" | "weekly", empty = off
+
+	// Per-type delivery preferences, missing types fall back to DefaultNotificationPreferences
+	NotificationPreferences map[NotificationType]NotificationPreference `bson:"notification_preferences,omitempty" json:"notification_preferences,omitempty"`
+
+	ProductEmails *bool `bson:"product_emails,omitempty" json:"product_emails"` // Campaign emails about product news, nil = on
+
+	Timezone string `bson:"timezone,omitempty" json:"timezone"` // IANA name, empty = DefaultTimezone
+
+	ProfileVisibility string `bson:"profile_visibility,omitempty" json:"profile_visibility"` // "everyone" | "users" | "nobody", empty = everyone
+
+	// Chat behavior
+	DefaultModel     string `bson:"default_model,omitempty" json:"default_model"`         // One of config.Cfg.AgentModels, empty = agent default
+	ResponseLanguage string `bson:"response_language,omitempty" json:"response_language"` // "auto" | "vi" | "en", empty = auto (follow Language)
+	StreamingEnabled *bool  `bson:"streaming_enabled,omitempty" json:"streaming_enabled"` // Stream answer chunks over WebSocket, nil = on
+	CitationsEnabled *bool  `bson:"citations_enabled,omitempty" json:"citations_enabled"` // Show sources under answers, nil = on
+
+	// Topics the assistant must not bring up, passed to the agent and enforced on its answers
+	BlockedTopics []string `bson:"blocked_topics,omitempty" json:"blocked_topics"`
+
+	// Self-imposed daily chat limits, 0 = none. Crossing one sends a notification;
+	// chat is only refused when UsageLimitEnforced is set.
+	UsageLimitMessages int  `bson:"usage_limit_messages,omitempty" json:"usage_limit_messages"`
+	UsageLimitTokens   int  `bson:"usage_limit_tokens,omitempty" json:"usage_limit_tokens"`
+	UsageLimitEnforced bool `bson:"usage_limit_enforced,omitempty" json:"usage_limit_enforced"`
 }
 
 // Theme constants
@@ -68,24 +121,202 @@ const (
 	ThemeDark  = "dark"
 )
 
+// Font scale bounds
+const (
+	DefaultFontScale = 1.0
+	MinFontScale     = 0.75
+	MaxFontScale     = 2.0
+)
+
 // Language constants
 const (
 	LanguageVI = "vi"
 	LanguageEN = "en"
 )
 
+// Blocked topic limits
+const (
+	MaxBlockedTopics      = 20
+	MaxBlockedTopicLength = 50
+)
+
+// Response language constants, besides the Language constants
+const (
+	ResponseLanguageAuto = "auto"
+)
+
+// Profile visibility constants, who may view a user's public profile
+const (
+	ProfileVisibilityEveryone = "everyone"
+	ProfileVisibilityUsers    = "users" // Logged-in users only
+	ProfileVisibilityNobody   = "nobody"
+)
+
+// DefaultTimezone is used for users who have not set a timezone
+const DefaultTimezone = "Asia/Ho_Chi_Minh"
+
+// Digest frequency constants
+const (
+	DigestOff    = "off"
+	DigestDaily  = "daily"
+	DigestWeekly = "weekly"
+)
+
 // NewDefaultSettings returns default user settings
 func NewDefaultSettings() UserSettings {
 	return UserSettings{
-		Language:          LanguageVI,
-		Theme:             ThemeLight,
-		NotifyNewFeatures: true,
+		Language:                LanguageVI,
+		Theme:                   ThemeLight,
+		FontScale:               DefaultFontScale,
+		NotifyNewFeatures:       true,
+		DigestFrequency:         DigestWeekly,
+		NotificationPreferences: DefaultNotificationPreferences(),
+		ResponseLanguage:        ResponseLanguageAuto,
+		Timezone:                DefaultTimezone,
+		ProfileVisibility:       ProfileVisibilityEveryone,
+	}
+}
+
+// IsValidTimezone checks if name is an IANA timezone name
+func IsValidTimezone(name string) bool {
+	if name == "" || name == "Local" {
+		return false
+	}
+	_, err := time.LoadLocation(name)
+	return err == nil
+}
+
+// TimezoneLocation loads a timezone, falling back to DefaultTimezone for empty or unknown names
+func TimezoneLocation(name string) *time.Location {
+	if name != "" {
+		if loc, err := time.LoadLocation(name); err == nil {
+			return loc
+		}
+	}
+	if loc, err := time.LoadLocation(DefaultTimezone); err == nil {
+		return loc
+	}
+	return time.UTC
+}
+
+// Location returns the user's timezone used for scheduling
+func (s *UserSettings) Location() *time.Location {
+	return TimezoneLocation(s.Timezone)
+}
+
+// NotificationPreference returns the user's delivery preference for a notification type
+func (s *UserSettings) NotificationPreference(t NotificationType) NotificationPreference {
+	if pref, ok := s.NotificationPreferences[t]; ok {
+		// Transactional emails cannot be turned off
+		pref.Email = pref.Email || IsTransactionalNotificationType(t)
+		return pref
+	}
+	if pref, ok := DefaultNotificationPreferences()[t]; ok {
+		return pref
+	}
+	// Non-configurable types are always delivered in-app
+	return NotificationPreference{InApp: true}
+}
+
+// DigestInterval returns how often the user wants an unread digest, or 0 if digests are off
+func (s *UserSettings) DigestInterval() time.Duration {
+	switch s.DigestFrequency {
+	case DigestDaily:
+		return 24 * time.Hour
+	case DigestWeekly:
+		return 7 * 24 * time.Hour
+	default:
+		return 0
+	}
+}
+
+// ChatLanguage returns the language chat answers should use when the session has no override
+func (s *UserSettings) ChatLanguage() string {
+	if s.ResponseLanguage != "" && s.ResponseLanguage != ResponseLanguageAuto {
+		return s.ResponseLanguage
+	}
+	if s.Language != "" {
+		return s.Language
+	}
+	return LanguageVI
+}
+
+// IsProductEmailsEnabled checks if the user receives campaign emails
+func (s *UserSettings) IsProductEmailsEnabled() bool {
+	return s.ProductEmails == nil || *s.ProductEmails
+}
+
+// IsStreamingEnabled checks if answers should be streamed to the user's clients
+func (s *UserSettings) IsStreamingEnabled() bool {
+	return s.StreamingEnabled == nil || *s.StreamingEnabled
+}
+
+// IsCitationsEnabled checks if answers should include their sources
+func (s *UserSettings) IsCitationsEnabled() bool {
+	return s.CitationsEnabled == nil || *s.CitationsEnabled
+}
+
+// IsUsageLimitReached checks if a day's usage reached one of the user's own limits
+func (s *UserSettings) IsUsageLimitReached(messages, tokens int) bool {
+	return (s.UsageLimitMessages > 0 && messages >= s.UsageLimitMessages) ||
+		(s.UsageLimitTokens > 0 && tokens >= s.UsageLimitTokens)
+}
+
+// IsDigestDue checks if an unread digest should be sent to the user at the given time.
+// Digests go out from sendHour in the user's timezone, once per digest interval counted in local days.
+func (u *User) IsDigestDue(now time.Time, sendHour int) bool {
+	interval := u.Settings.DigestInterval()
+	if interval == 0 {
+		return false
+	}
+
+	loc := u.Settings.Location()
+	local := now.In(loc)
+	if local.Hour() < sendHour {
+		return false
+	}
+	if u.LastDigestSentAt == nil {
+		return true
+	}
+
+	last := u.LastDigestSentAt.In(loc)
+	nextDay := time.Date(last.Year(), last.Month(), last.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, int(interval/(24*time.Hour)))
+	return !local.Before(nextDay)
+}
+
+// CanViewProfile checks if the requester may view the user's public profile.
+// requesterID is empty for unauthenticated requests; owners can always see their own profile.
+func (u *User) CanViewProfile(requesterID string) bool {
+	if requesterID != "" && requesterID == u.ID.Hex() {
+		return true
+	}
+
+	switch u.Settings.ProfileVisibility {
+	case ProfileVisibilityNobody:
+		return false
+	case ProfileVisibilityUsers:
+		return requesterID != ""
+	default:
+		return true
 	}
 }
 
 // IsBanned checks if user is currently banned
+// CanSignInWith reports whether the user registered with the provider or linked it later
+func (u *User) CanSignInWith(provider AuthProvider) bool {
+	if u.Provider == provider {
+		return true
+	}
+	for _, p := range u.LinkedProviders {
+		if p == provider {
+			return true
+		}
+	}
+	return false
+}
+
 func (u *User) IsBanned() bool {
-	if !u.IsActive {
+	if !u.IsActive && !u.IsSelfDeactivated() {
 		return true
 	}
 	if u.BanUntil != nil && u.BanUntil.After(time.Now()) {
@@ -94,6 +325,46 @@ func (u *User) IsBanned() bool {
 	return false
 }
 
+// IsSelfDeactivated checks if the user deactivated their own account rather than being banned.
+// A ban placed on a deactivated account takes precedence.
+func (u *User) IsSelfDeactivated() bool {
+	return u.DeactivatedAt != nil && u.BanReason == nil
+}
+
+// StudentProfile holds a user's UIT enrollment details, forwarded to the agent for personalized answers
+type StudentProfile struct {
+	StudentID      string `bson:"student_id,omitempty" json:"student_id"` // MSSV, 8 digits
+	Faculty        string `bson:"faculty,omitempty" json:"faculty"`
+	Program        string `bson:"program,omitempty" json:"program"`                 // e.g. "Chất lượng cao", "Chính quy"
+	EnrollmentYear int    `bson:"enrollment_year,omitempty" json:"enrollment_year"` // 0 if unknown
+}
+
+// FirstEnrollmentYear is the year UIT admitted its first students
+const FirstEnrollmentYear = 2006
+
+// Cohort returns the student's cohort (khóa), counted from UIT's first intake, or 0 if the enrollment year is unknown
+func (p StudentProfile) Cohort() int {
+	if p.EnrollmentYear < FirstEnrollmentYear {
+		return 0
+	}
+	return p.EnrollmentYear - FirstEnrollmentYear + 1
+}
+
+// UserQuota is a user's daily chat limits. 0 means unlimited.
+type UserQuota struct {
+	DailyMessages int `bson:"daily_messages" json:"daily_messages"`
+	DailyTokens   int `bson:"daily_tokens" json:"daily_tokens"`
+}
+
+// AdminNote is an internal note left on a user account by support staff
+type AdminNote struct {
+	ID             primitive.ObjectID `bson:"_id" json:"id"`
+	AuthorID       primitive.ObjectID `bson:"author_id" json:"author_id"`
+	AuthorUsername string             `bson:"author_username" json:"author_username"` // At the time the note was written
+	Content        string             `bson:"content" json:"content"`
+	CreatedAt      time.Time          `bson:"created_at" json:"created_at"`
+}
+
 // IsAdmin checks if user has admin role
 func (u *User) IsAdmin() bool {
 	return u.Role == AdminRole
@@ -119,19 +390,68 @@ func CloneUser(u *User) *User {
 		clone.BanUntil = &t
 	}
 
+	// Deep copy DeactivatedAt
+	if u.DeactivatedAt != nil {
+		t := *u.DeactivatedAt
+		clone.DeactivatedAt = &t
+	}
+
+	// Deep copy LastDigestSentAt
+	if u.LastDigestSentAt != nil {
+		t := *u.LastDigestSentAt
+		clone.LastDigestSentAt = &t
+	}
+
 	// Deep copy BanReason
 	if u.BanReason != nil {
 		s := *u.BanReason
 		clone.BanReason = &s
 	}
 
+	// Deep copy QuotaOverride
+	if u.QuotaOverride != nil {
+		q := *u.QuotaOverride
+		clone.QuotaOverride = &q
+	}
+
+	// Deep copy email and chat behavior settings
+	if u.Settings.ProductEmails != nil {
+		b := *u.Settings.ProductEmails
+		clone.Settings.ProductEmails = &b
+	}
+	if u.Settings.StreamingEnabled != nil {
+		b := *u.Settings.StreamingEnabled
+		clone.Settings.StreamingEnabled = &b
+	}
+	if u.Settings.CitationsEnabled != nil {
+		b := *u.Settings.CitationsEnabled
+		clone.Settings.CitationsEnabled = &b
+	}
+
+	// Deep copy BlockedTopics
+	if u.Settings.BlockedTopics != nil {
+		clone.Settings.BlockedTopics = append([]string(nil), u.Settings.BlockedTopics...)
+	}
+
+	// Deep copy AdminNotes
+	if u.AdminNotes != nil {
+		clone.AdminNotes = append([]AdminNote(nil), u.AdminNotes...)
+	}
+
 	// Deep copy Avatar
 	if u.Avatar != nil {
 		img := *u.Avatar
 		clone.Avatar = &img
 	}
 
-	// Settings is value type, already copied
+	// Deep copy NotificationPreferences
+	if u.Settings.NotificationPreferences != nil {
+		prefs := make(map[NotificationType]NotificationPreference, len(u.Settings.NotificationPreferences))
+		for k, v := range u.Settings.NotificationPreferences {
+			prefs[k] = v
+		}
+		clone.Settings.NotificationPreferences = prefs
+	}
 
 	return &clone
 }
@@ -1,6 +1,8 @@
 package bus
 
 import (
+	"context"
+	"slices"
 	"sync"
 )
 
@@ -16,6 +18,7 @@ type EventListener chan Event
 // EventBus interface
 type EventBus interface {
 	Subscribe(topic string, ch EventListener)
+	Unsubscribe(topic string, ch EventListener)
 	Publish(event Event)
 }
 
@@ -27,6 +30,10 @@ type eventBus struct {
 
 // NewEventBus creates a new EventBus.
 func NewEventBus() EventBus {
+	return newEventBus()
+}
+
+func newEventBus() *eventBus {
 	return &eventBus{
 		listeners: make(map[string][]EventListener),
 	}
@@ -43,6 +50,20 @@ func (b *eventBus) Subscribe(topic string, ch EventListener) {
 	b.listeners[topic] = append(b.listeners[topic], ch)
 }
 
+// Unsubscribe removes a listener from a given topic.
+func (b *eventBus) Unsubscribe(topic string, ch EventListener) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	listeners := b.listeners[topic]
+	for i, l := range listeners {
+		if l == ch {
+			b.listeners[topic] = append(listeners[:i], listeners[i+1:]...)
+			return
+		}
+	}
+}
+
 // Publish sends an event to all subscribed listeners of a topic.
 // This is done asynchronously to prevent blocking the publisher.
 func (b *eventBus) Publish(event Event) {
@@ -63,3 +84,20 @@ func (b *eventBus) Publish(event Event) {
 		}
 	}
 }
+
+// deliver hands the event to every listener of its topic, waiting for room in channels that are full.
+// Returns ctx's error if a listener did not take the event in time; the listeners before it already have it.
+func (b *eventBus) deliver(ctx context.Context, event Event) error {
+	b.lock.RLock()
+	listeners := slices.Clone(b.listeners[event.Topic()])
+	b.lock.RUnlock()
+
+	for _, listener := range listeners {
+		select {
+		case listener <- event:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
@@ -1,6 +1,8 @@
 package bus
 
 import (
+	"time"
+
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
 )
 
@@ -8,6 +10,15 @@ import (
 const (
 	TopicBroadcast           = "broadcast"
 	TopicNotificationCreated = "notification.created"
+	TopicUnreadCountChanged  = "notification.unread_count_changed"
+	TopicAnnouncementSent    = "announcement.sent"
+	TopicPresenceChanged     = "presence.changed"
+	TopicChatSessionUpdated  = "chat.session_updated"
+	TopicSessionTerminated   = "user.session_terminated"
+	TopicDashboardMetrics    = "admin.dashboard_metrics"
+	TopicUserUpdated         = "user.updated"
+	TopicUserDeleted         = "user.deleted"
+	TopicSettingsChanged     = "user.settings_changed"
 )
 
 type BroadcastEventType string
@@ -19,9 +30,6 @@ const (
 	BroadcastEventTypingStart    BroadcastEventType = "typing_start"
 	BroadcastEventTypingStop     BroadcastEventType = "typing_stop"
 	BroadcastEventMessageRead    BroadcastEventType = "message_read"
-
-	// ---- Notification-related ----
-	BroadcastEventMessageNotification BroadcastEventType = "message_notification"
 )
 
 type BroadcastEvent struct {
@@ -52,3 +60,147 @@ func (e NotificationCreatedEvent) Topic() string { return TopicNotificationCreat
 func (e NotificationCreatedEvent) Payload() map[string]interface{} {
 	return map[string]interface{}{"recipient_id": e.RecipientID, "notification": e.Notification}
 }
+
+type UnreadCountChangedEvent struct {
+	RecipientID string
+	UnreadCount int64
+}
+
+func (e UnreadCountChangedEvent) Topic() string { return TopicUnreadCountChanged }
+func (e UnreadCountChangedEvent) Payload() map[string]interface{} {
+	return map[string]interface{}{"recipient_id": e.RecipientID, "unread_count": e.UnreadCount}
+}
+
+// --- Announcement Events ---
+
+// AnnouncementSentEvent carries one delivery batch of an announcement.
+// Announcement is nil unless the admin asked for a live WebSocket banner.
+type AnnouncementSentEvent struct {
+	Notifications map[string]dto.NotificationResponse // Keyed by recipient ID
+	RecipientIDs  []string
+	Announcement  *dto.AnnouncementBanner
+}
+
+func (e AnnouncementSentEvent) Topic() string { return TopicAnnouncementSent }
+func (e AnnouncementSentEvent) Payload() map[string]interface{} {
+	return map[string]interface{}{
+		"notifications": e.Notifications,
+		"recipient_ids": e.RecipientIDs,
+		"announcement":  e.Announcement,
+	}
+}
+
+// --- Presence Events ---
+
+type PresenceChangedEvent struct {
+	UserID   string
+	Online   bool
+	LastSeen time.Time
+}
+
+func (e PresenceChangedEvent) Topic() string { return TopicPresenceChanged }
+func (e PresenceChangedEvent) Payload() map[string]interface{} {
+	return map[string]interface{}{"user_id": e.UserID, "online": e.Online, "last_seen": e.LastSeen}
+}
+
+// --- Chat Session Events ---
+
+type ChatSessionEventType string
+
+const (
+	ChatSessionEventMessageCreated  ChatSessionEventType = "message_created"
+	ChatSessionEventTitleChanged    ChatSessionEventType = "title_changed"
+	ChatSessionEventLanguageChanged ChatSessionEventType = "language_changed"
+	ChatSessionEventDeleted         ChatSessionEventType = "deleted"
+	ChatSessionEventToken           ChatSessionEventType = "token" // Streamed answer chunk
+)
+
+// ChatSessionEvent is delivered to every client subscribed to the session,
+// keeping other tabs and the extension in sync with the one that made the change.
+type ChatSessionEvent struct {
+	SessionID string
+	UserID    string
+	EventType ChatSessionEventType
+	Data      interface{}
+}
+
+func (e ChatSessionEvent) Topic() string { return TopicChatSessionUpdated }
+func (e ChatSessionEvent) Payload() map[string]interface{} {
+	return map[string]interface{}{
+		"session_id": e.SessionID,
+		"user_id":    e.UserID,
+		"event_type": e.EventType,
+		"data":       e.Data,
+	}
+}
+
+// --- Session Termination Events ---
+
+type SessionTerminationReason string
+
+const (
+	SessionTerminatedBanned      SessionTerminationReason = "banned"
+	SessionTerminatedDeleted     SessionTerminationReason = "account_deleted"
+	SessionTerminatedRoleChanged SessionTerminationReason = "role_changed"
+	SessionTerminatedForceLogout SessionTerminationReason = "force_logout"
+	SessionTerminatedDeactivated SessionTerminationReason = "account_deactivated"
+)
+
+// SessionTerminatedEvent closes all of a user's real-time connections, e.g. after a ban
+type SessionTerminatedEvent struct {
+	UserID string
+	Reason SessionTerminationReason
+}
+
+func (e SessionTerminatedEvent) Topic() string { return TopicSessionTerminated }
+func (e SessionTerminatedEvent) Payload() map[string]interface{} {
+	return map[string]interface{}{"user_id": e.UserID, "reason": e.Reason}
+}
+
+// --- User Events ---
+
+// UserUpdatedEvent is published after a user document changes, including when a user is created.
+// PreviousUsername is set when the username changed, the old one becomes available.
+type UserUpdatedEvent struct {
+	UserID           string
+	Username         string
+	PreviousUsername string
+}
+
+func (e UserUpdatedEvent) Topic() string { return TopicUserUpdated }
+func (e UserUpdatedEvent) Payload() map[string]interface{} {
+	return map[string]interface{}{"user_id": e.UserID, "username": e.Username, "previous_username": e.PreviousUsername}
+}
+
+// UserDeletedEvent is published after a user is deleted
+type UserDeletedEvent struct {
+	UserID   string
+	Username string
+}
+
+func (e UserDeletedEvent) Topic() string { return TopicUserDeleted }
+func (e UserDeletedEvent) Payload() map[string]interface{} {
+	return map[string]interface{}{"user_id": e.UserID, "username": e.Username}
+}
+
+// SettingsChangedEvent is published after a user's settings or blocked topics change
+type SettingsChangedEvent struct {
+	UserID string
+}
+
+func (e SettingsChangedEvent) Topic() string { return TopicSettingsChanged }
+func (e SettingsChangedEvent) Payload() map[string]interface{} {
+	return map[string]interface{}{"user_id": e.UserID}
+}
+
+// --- Admin Dashboard Events ---
+
+// DashboardMetricsEvent carries a snapshot of the live admin dashboard
+type DashboardMetricsEvent struct {
+	Metrics dto.DashboardMetricsPayload
+}
+
+func (e DashboardMetricsEvent) Topic() string { return TopicDashboardMetrics }
+func (e DashboardMetricsEvent) Payload() map[string]interface{} {
+	return map[string]interface{}{"metrics": e.Metrics}
+}
@@ -0,0 +1,175 @@
+package bus
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+const (
+	// natsRequestTimeout bounds a JetStream API call, e.g. creating the stream or a consumer
+	natsRequestTimeout = 10 * time.Second
+	// natsJoinRetry is how long to wait before trying again to consume a topic whose consumer could not be set up
+	natsJoinRetry = 5 * time.Second
+)
+
+// durableNameReplacer replaces the characters NATS does not allow in durable consumer names
+var durableNameReplacer = strings.NewReplacer(".", "_", "*", "_", ">", "_", " ", "_", "/", "_", "\\", "_")
+
+// NATSConfig configures the NATS JetStream event bus
+type NATSConfig struct {
+	Stream        string        // JetStream stream holding the events, created if missing
+	SubjectPrefix string        // Events are published on SubjectPrefix + "." + topic
+	Group         string        // Consumer group, must stay the same across restarts; processes sharing a group split its events between them
+	MaxLen        int64         // Number of events kept per topic
+	AckWait       time.Duration // Events not acknowledged this long, by a stopped process or a listener that was full, are delivered again
+}
+
+// natsEventBus publishes events to a JetStream stream, one subject per topic, and reads them back through a
+// durable consumer per group and topic. Like the Redis Streams bus, a process that restarts resumes where its group
+// stopped, and events are acknowledged once every local listener has taken them, so delivery is at least once.
+//
+// Every group receives every event, while the processes of one group share them. Processes that each need all
+// events, like WebSocket hubs holding different clients, must use different groups.
+type natsEventBus struct {
+	js    jetstream.JetStream
+	cfg   NATSConfig
+	local *eventBus
+
+	mu     sync.Mutex
+	topics map[string]bool // Subscribed topics
+}
+
+// NewNATSEventBus creates an EventBus backed by NATS JetStream and durable consumers
+func NewNATSEventBus(conn *nats.Conn, cfg NATSConfig) (EventBus, error) {
+	js, err := jetstream.New(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	return &natsEventBus{
+		js:     js,
+		cfg:    cfg,
+		local:  newEventBus(),
+		topics: make(map[string]bool),
+	}, nil
+}
+
+// Subscribe adds a listener on this process. The first subscription to a topic starts consuming its subject.
+func (b *natsEventBus) Subscribe(topic string, ch EventListener) {
+	b.local.Subscribe(topic, ch)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.topics[topic] {
+		b.topics[topic] = true
+		go b.join(topic)
+	}
+}
+
+// Unsubscribe removes a listener on this process. The subject is still consumed so the group does not fall behind.
+func (b *natsEventBus) Unsubscribe(topic string, ch EventListener) {
+	b.local.Unsubscribe(topic, ch)
+}
+
+// Publish adds the event to the stream. If NATS is unavailable the event is only delivered locally.
+func (b *natsEventBus) Publish(event Event) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		slog.Error("EventBus: failed to encode event, delivering locally", "topic", event.Topic(), "error", err)
+		b.local.Publish(event)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+	defer cancel()
+
+	if _, err := b.js.Publish(ctx, b.subject(event.Topic()), data); err != nil {
+		slog.Error("EventBus: failed to publish event to NATS, delivering locally", "topic", event.Topic(), "error", err)
+		b.local.Publish(event)
+	}
+}
+
+// join starts consuming the topic, trying again until the stream and consumer are set up
+func (b *natsEventBus) join(topic string) {
+	for {
+		err := b.consume(topic)
+		if err == nil {
+			slog.Info("EventBus: consuming NATS subject", "subject", b.subject(topic), "group", b.cfg.Group)
+			return
+		}
+		slog.Error("EventBus: failed to consume NATS subject", "subject", b.subject(topic), "error", err)
+		time.Sleep(natsJoinRetry)
+	}
+}
+
+// consume creates the stream and the group's durable consumer of the topic if needed, then delivers its events.
+// A new consumer starts at the beginning of what the stream still holds; an existing one resumes where it stopped.
+func (b *natsEventBus) consume(topic string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), natsRequestTimeout)
+	defer cancel()
+
+	_, err := b.js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+		Name:              b.cfg.Stream,
+		Subjects:          []string{b.cfg.SubjectPrefix + ".>"},
+		MaxMsgsPerSubject: b.cfg.MaxLen,
+		Storage:           jetstream.FileStorage,
+	})
+	if err != nil {
+		return err
+	}
+
+	consumer, err := b.js.CreateOrUpdateConsumer(ctx, b.cfg.Stream, jetstream.ConsumerConfig{
+		Durable:       durableNameReplacer.Replace(b.cfg.Group + "-" + topic),
+		FilterSubject: b.subject(topic),
+		DeliverPolicy: jetstream.DeliverAllPolicy,
+		AckPolicy:     jetstream.AckExplicitPolicy,
+		AckWait:       b.cfg.AckWait,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = consumer.Consume(func(msg jetstream.Msg) {
+		b.deliver(topic, msg)
+	}, jetstream.ConsumeErrHandler(func(_ jetstream.ConsumeContext, err error) {
+		slog.Warn("EventBus: NATS consumer error", "subject", b.subject(topic), "error", err)
+	}))
+	return err
+}
+
+// deliver hands an event to local listeners and acknowledges it once every listener took it. An event a listener
+// did not take in time is left unacknowledged and delivered again after AckWait. Events that cannot be decoded are
+// terminated, they would fail the same way every time.
+func (b *natsEventBus) deliver(topic string, msg jetstream.Msg) {
+	event, err := DecodeEvent(topic, msg.Data())
+	if err != nil {
+		slog.Error("EventBus: failed to decode NATS message, dropped", "topic", topic, "error", err)
+		if err := msg.Term(); err != nil {
+			slog.Warn("EventBus: failed to terminate NATS message", "topic", topic, "error", err)
+		}
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), streamDeliverTimeout)
+	defer cancel()
+
+	if err := b.local.deliver(ctx, event); err != nil {
+		slog.Warn("EventBus: listener did not take NATS message, left unacknowledged", "topic", topic, "error", err)
+		return
+	}
+	if err := msg.Ack(); err != nil {
+		slog.Warn("EventBus: failed to acknowledge NATS message, it will be delivered again", "topic", topic, "error", err)
+	}
+}
+
+func (b *natsEventBus) subject(topic string) string {
+	return b.cfg.SubjectPrefix + "." + topic
+}
@@ -0,0 +1,124 @@
+package bus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// publishTimeout bounds a single publish to Redis or NATS so a slow broker cannot block publishers
+const publishTimeout = 2 * time.Second
+
+// eventDecoders rebuild concrete events from their JSON form, keyed by topic.
+// Listeners type-assert payload values, so events must arrive as the same Go types they were published as.
+var eventDecoders = map[string]func(data []byte) (Event, error){
+	TopicBroadcast:           decodeEvent[BroadcastEvent],
+	TopicNotificationCreated: decodeEvent[NotificationCreatedEvent],
+	TopicUnreadCountChanged:  decodeEvent[UnreadCountChangedEvent],
+	TopicAnnouncementSent:    decodeEvent[AnnouncementSentEvent],
+	TopicPresenceChanged:     decodeEvent[PresenceChangedEvent],
+	TopicChatSessionUpdated:  decodeEvent[ChatSessionEvent],
+	TopicSessionTerminated:   decodeEvent[SessionTerminatedEvent],
+	TopicDashboardMetrics:    decodeEvent[DashboardMetricsEvent],
+	TopicUserUpdated:         decodeEvent[UserUpdatedEvent],
+	TopicUserDeleted:         decodeEvent[UserDeletedEvent],
+	TopicSettingsChanged:     decodeEvent[SettingsChangedEvent],
+}
+
+// ErrUnknownTopic is returned when decoding an event of a topic without a decoder
+var ErrUnknownTopic = errors.New("no decoder for event topic")
+
+// DecodeEvent rebuilds an event of topic from its JSON form
+func DecodeEvent(topic string, data []byte) (Event, error) {
+	decode, ok := eventDecoders[topic]
+	if !ok {
+		return nil, ErrUnknownTopic
+	}
+	return decode(data)
+}
+
+func decodeEvent[T Event](data []byte) (Event, error) {
+	var event T
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// redisEventBus fans events out through Redis pub/sub so every instance's listeners receive them.
+// Events published on this instance also come back through Redis, so they are delivered exactly once locally.
+type redisEventBus struct {
+	client *redis.Client
+	prefix string
+	local  EventBus
+}
+
+// NewRedisEventBus creates an EventBus backed by Redis pub/sub. Channels are named prefix + topic.
+func NewRedisEventBus(client *redis.Client, prefix string) EventBus {
+	b := &redisEventBus{
+		client: client,
+		prefix: prefix,
+		local:  NewEventBus(),
+	}
+
+	go b.listen()
+
+	return b
+}
+
+// Subscribe adds a new listener for a given topic on this instance.
+func (b *redisEventBus) Subscribe(topic string, ch EventListener) {
+	b.local.Subscribe(topic, ch)
+}
+
+// Unsubscribe removes a listener on this instance.
+func (b *redisEventBus) Unsubscribe(topic string, ch EventListener) {
+	b.local.Unsubscribe(topic, ch)
+}
+
+// Publish sends the event to all instances. If Redis is unavailable the event is only delivered locally.
+func (b *redisEventBus) Publish(event Event) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		slog.Error("EventBus: failed to encode event, delivering locally", "topic", event.Topic(), "error", err)
+		b.local.Publish(event)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+	defer cancel()
+
+	if err := b.client.Publish(ctx, b.prefix+event.Topic(), data).Err(); err != nil {
+		slog.Error("EventBus: failed to publish event to Redis, delivering locally", "topic", event.Topic(), "error", err)
+		b.local.Publish(event)
+	}
+}
+
+// listen relays events from Redis to local listeners. go-redis reconnects the subscription on its own.
+func (b *redisEventBus) listen() {
+	pubsub := b.client.PSubscribe(context.Background(), b.prefix+"*")
+	defer pubsub.Close()
+
+	slog.Info("EventBus: subscribed to Redis channels", "pattern", b.prefix+"*")
+
+	for msg := range pubsub.Channel() {
+		topic := strings.TrimPrefix(msg.Channel, b.prefix)
+
+		event, err := DecodeEvent(topic, []byte(msg.Payload))
+		if errors.Is(err, ErrUnknownTopic) {
+			slog.Warn("EventBus: no decoder for topic, event dropped", "topic", topic)
+			continue
+		}
+		if err != nil {
+			slog.Error("EventBus: failed to decode event", "topic", topic, "error", err)
+			continue
+		}
+
+		b.local.Publish(event)
+	}
+}
@@ -0,0 +1,290 @@
+package bus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// streamReadBlock bounds a blocking read, topics subscribed meanwhile are joined after it returns
+	streamReadBlock = 2 * time.Second
+	// streamReadCount is the most entries read per stream at once
+	streamReadCount = 100
+	// streamDataField is the entry field holding the JSON encoded event
+	streamDataField = "data"
+	// streamDeliverTimeout bounds the wait for a listener with a full channel. Entries not taken in time are
+	// left pending and claimed again once they have been idle for ClaimIdle.
+	streamDeliverTimeout = 5 * time.Second
+)
+
+// StreamsConfig configures the Redis Streams event bus
+type StreamsConfig struct {
+	Prefix    string        // Streams are named Prefix + topic
+	Group     string        // Consumer group, must stay the same across restarts; processes sharing a group split its events between them
+	Consumer  string        // Name of this process in the group, must stay the same across restarts
+	MaxLen    int64         // Approximate number of entries kept per stream
+	ClaimIdle time.Duration // Entries left unacknowledged this long, by another consumer or by this one, are delivered again
+}
+
+// redisStreamsEventBus publishes events to one Redis stream per topic and reads them back through a consumer
+// group. Unlike pub/sub, entries stay in the stream: a process that restarts resumes where its group stopped,
+// and entries it read but did not acknowledge before stopping are delivered again. An entry is only acknowledged
+// once every local listener has taken it, so delivery is at least once: a listener may see an event twice.
+//
+// Every group receives every event, while the consumers of one group share them. Processes that each need all
+// events, like WebSocket hubs holding different clients, must use different groups.
+type redisStreamsEventBus struct {
+	client *redis.Client
+	cfg    StreamsConfig
+	local  *eventBus
+
+	mu      sync.Mutex
+	topics  map[string]bool // Subscribed topics, true once this consumer joined the group of their stream
+	started bool
+}
+
+// NewRedisStreamsEventBus creates an EventBus backed by Redis Streams and consumer groups
+func NewRedisStreamsEventBus(client *redis.Client, cfg StreamsConfig) EventBus {
+	return &redisStreamsEventBus{
+		client: client,
+		cfg:    cfg,
+		local:  newEventBus(),
+		topics: make(map[string]bool),
+	}
+}
+
+// Subscribe adds a listener on this process. The stream of the topic is read from the next read on.
+func (b *redisStreamsEventBus) Subscribe(topic string, ch EventListener) {
+	b.local.Subscribe(topic, ch)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.topics[topic]; !ok {
+		b.topics[topic] = false
+	}
+	if !b.started {
+		b.started = true
+		go b.consume()
+	}
+}
+
+// Unsubscribe removes a listener on this process. The stream is still read so the group does not fall behind.
+func (b *redisStreamsEventBus) Unsubscribe(topic string, ch EventListener) {
+	b.local.Unsubscribe(topic, ch)
+}
+
+// Publish appends the event to the stream of its topic. If Redis is unavailable the event is only delivered locally.
+func (b *redisStreamsEventBus) Publish(event Event) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		slog.Error("EventBus: failed to encode event, delivering locally", "topic", event.Topic(), "error", err)
+		b.local.Publish(event)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+	defer cancel()
+
+	err = b.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: b.stream(event.Topic()),
+		MaxLen: b.cfg.MaxLen,
+		Approx: true,
+		Values: map[string]interface{}{streamDataField: data},
+	}).Err()
+	if err != nil {
+		slog.Error("EventBus: failed to add event to Redis stream, delivering locally", "topic", event.Topic(), "error", err)
+		b.local.Publish(event)
+	}
+}
+
+// consume reads the streams of all subscribed topics and relays their entries to local listeners
+func (b *redisStreamsEventBus) consume() {
+	ctx := context.Background()
+	slog.Info("EventBus: consuming Redis streams", "group", b.cfg.Group, "consumer", b.cfg.Consumer)
+
+	var lastClaim time.Time
+	for {
+		for _, topic := range b.unjoinedTopics() {
+			if err := b.join(ctx, topic); err != nil {
+				slog.Error("EventBus: failed to join consumer group", "topic", topic, "error", err)
+			}
+		}
+
+		streams := b.joinedStreams()
+		if len(streams) == 0 {
+			time.Sleep(streamReadBlock)
+			continue
+		}
+
+		if b.cfg.ClaimIdle > 0 && time.Since(lastClaim) >= b.cfg.ClaimIdle {
+			lastClaim = time.Now()
+			for _, stream := range streams {
+				b.claimIdle(ctx, stream)
+			}
+		}
+
+		args := make([]string, 0, 2*len(streams))
+		args = append(args, streams...)
+		for range streams {
+			args = append(args, ">") // Entries never delivered to the group
+		}
+		results, err := b.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    b.cfg.Group,
+			Consumer: b.cfg.Consumer,
+			Streams:  args,
+			Count:    streamReadCount,
+			Block:    streamReadBlock,
+		}).Result()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			// The stream or group was deleted, e.g. by FLUSHDB: join again
+			if strings.HasPrefix(err.Error(), "NOGROUP") {
+				b.leaveAll()
+			}
+			slog.Error("EventBus: failed to read Redis streams", "error", err)
+			time.Sleep(time.Second)
+			continue
+		}
+
+		for _, result := range results {
+			b.deliver(ctx, result.Stream, result.Messages)
+		}
+	}
+}
+
+// join creates the consumer group of the topic's stream if needed, then delivers the entries this consumer
+// read before a restart but did not acknowledge
+func (b *redisStreamsEventBus) join(ctx context.Context, topic string) error {
+	stream := b.stream(topic)
+
+	// A new group starts at the beginning of what the stream still holds, so nothing published before the group's
+	// first start is skipped. An existing group keeps its last delivered ID.
+	err := b.client.XGroupCreateMkStream(ctx, stream, b.cfg.Group, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return err
+	}
+
+	for start := "0"; ; {
+		results, err := b.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    b.cfg.Group,
+			Consumer: b.cfg.Consumer,
+			Streams:  []string{stream, start},
+			Count:    streamReadCount,
+		}).Result()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return err
+		}
+		if len(results) == 0 || len(results[0].Messages) == 0 {
+			break
+		}
+		messages := results[0].Messages
+		b.deliver(ctx, stream, messages)
+		start = messages[len(messages)-1].ID
+	}
+
+	b.mu.Lock()
+	b.topics[topic] = true
+	b.mu.Unlock()
+	return nil
+}
+
+// claimIdle takes over entries that were read but never acknowledged, e.g. because another process of the group
+// stopped for good or a local listener did not take them in time, and delivers them here
+func (b *redisStreamsEventBus) claimIdle(ctx context.Context, stream string) {
+	messages, _, err := b.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+		Stream:   stream,
+		Group:    b.cfg.Group,
+		Consumer: b.cfg.Consumer,
+		MinIdle:  b.cfg.ClaimIdle,
+		Start:    "0-0",
+		Count:    streamReadCount,
+	}).Result()
+	if err != nil {
+		slog.Warn("EventBus: failed to claim idle stream entries", "stream", stream, "error", err)
+		return
+	}
+	b.deliver(ctx, stream, messages)
+}
+
+// deliver hands entries to local listeners and acknowledges those every listener took. Entries a listener did not
+// take in time stay pending until claimIdle picks them up again. Entries that cannot be decoded are acknowledged,
+// they would fail the same way every time.
+func (b *redisStreamsEventBus) deliver(ctx context.Context, stream string, messages []redis.XMessage) {
+	topic := strings.TrimPrefix(stream, b.cfg.Prefix)
+
+	ids := make([]string, 0, len(messages))
+	for _, msg := range messages {
+		data, _ := msg.Values[streamDataField].(string)
+		event, err := DecodeEvent(topic, []byte(data))
+		if err != nil {
+			slog.Error("EventBus: failed to decode stream entry, dropped", "topic", topic, "id", msg.ID, "error", err)
+			ids = append(ids, msg.ID)
+			continue
+		}
+
+		deliverCtx, cancel := context.WithTimeout(ctx, streamDeliverTimeout)
+		err = b.local.deliver(deliverCtx, event)
+		cancel()
+		if err != nil {
+			slog.Warn("EventBus: listener did not take stream entry, left pending", "topic", topic, "id", msg.ID, "error", err)
+			continue
+		}
+		ids = append(ids, msg.ID)
+	}
+	if len(ids) == 0 {
+		return
+	}
+
+	if err := b.client.XAck(ctx, stream, b.cfg.Group, ids...).Err(); err != nil {
+		slog.Warn("EventBus: failed to acknowledge stream entries, they will be delivered again", "stream", stream, "error", err)
+	}
+}
+
+func (b *redisStreamsEventBus) unjoinedTopics() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var topics []string
+	for topic, joined := range b.topics {
+		if !joined {
+			topics = append(topics, topic)
+		}
+	}
+	return topics
+}
+
+func (b *redisStreamsEventBus) joinedStreams() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var streams []string
+	for topic, joined := range b.topics {
+		if joined {
+			streams = append(streams, b.stream(topic))
+		}
+	}
+	return streams
+}
+
+func (b *redisStreamsEventBus) leaveAll() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for topic := range b.topics {
+		b.topics[topic] = false
+	}
+}
+
+func (b *redisStreamsEventBus) stream(topic string) string {
+	return b.cfg.Prefix + topic
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"mime/multipart"
 	"time"
 
@@ -13,6 +15,13 @@ import (
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
 )
 
+const (
+	// deleteMaxAttempts is how often DeleteAsync tries to destroy an asset before giving up
+	deleteMaxAttempts = 5
+	// deleteRetryDelay is the wait before the first retry, doubled after every failure
+	deleteRetryDelay = 2 * time.Second
+)
+
 func newCld() (*cloudinary.Cloudinary, error) {
 	return cloudinary.NewFromParams(config.Cfg.Cloudinary.CloudName, config.Cfg.Cloudinary.APIKey, config.Cfg.Cloudinary.APISecret)
 }
@@ -28,6 +37,32 @@ func Upload(file multipart.File) (*uploader.UploadResult, error) {
 	})
 }
 
+// UploadAvatar uploads an avatar cropped to a size x size square, centered on a face when one is found.
+// The crop is applied before the image is stored, so the original is not kept.
+func UploadAvatar(file io.Reader, size int) (*model.Image, error) {
+	cld, err := newCld()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := cld.Upload.Upload(context.Background(), file, uploader.UploadParams{
+		Folder:         config.Cfg.Cloudinary.UploadFolder,
+		Transformation: fmt.Sprintf("c_fill,g_face,w_%d,h_%d", size, size),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result.Error.Message != "" {
+		return nil, errors.New(result.Error.Message)
+	}
+
+	return &model.Image{
+		URL:        result.SecureURL,
+		PublicID:   result.PublicID,
+		UploadedAt: time.Now(),
+	}, nil
+}
+
 func UploadVideo(file multipart.File) (*uploader.UploadResult, error) {
 	cld, err := newCld()
 	if err != nil {
@@ -49,6 +84,46 @@ func Delete(publicID string) (*uploader.DestroyResult, error) {
 	return cld.Upload.Destroy(context.Background(), uploader.DestroyParams{PublicID: publicID})
 }
 
+// DeleteAsync destroys an asset in the background, retrying with backoff on failure.
+// Used for assets that are no longer referenced, e.g. a replaced avatar, so callers do not wait on Cloudinary.
+func DeleteAsync(publicID string) {
+	if publicID == "" {
+		return
+	}
+
+	go func() {
+		delay := deleteRetryDelay
+		for attempt := 1; ; attempt++ {
+			err := destroy(publicID)
+			if err == nil {
+				return
+			}
+
+			slog.Warn("Cloudinary: failed to delete asset", "public_id", publicID, "attempt", attempt, "max_attempts", deleteMaxAttempts, "error", err)
+			if attempt == deleteMaxAttempts {
+				return
+			}
+			time.Sleep(delay)
+			delay *= 2
+		}
+	}()
+}
+
+// destroy deletes an asset, treating one that is already gone as deleted
+func destroy(publicID string) error {
+	result, err := Delete(publicID)
+	if err != nil {
+		return err
+	}
+	if result.Error.Message != "" {
+		return errors.New(result.Error.Message)
+	}
+	if result.Result != "ok" && result.Result != "not found" {
+		return fmt.Errorf("unexpected result %q", result.Result)
+	}
+	return nil
+}
+
 // UploadImages uploads multiple images and returns model.Image slice.
 // This function handles both single and multiple image uploads.
 func UploadImages(files []*multipart.FileHeader) ([]*model.Image, error) {
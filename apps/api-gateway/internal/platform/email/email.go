@@ -1,126 +1,175 @@
 package email
 
 import (
-	"bytes"
-	"fmt"
+	"context"
+	"errors"
 	"html/template"
-	"log"
-	"net/smtp"
+	"log/slog"
+	"time"
 
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
 )
 
+// sendTimeout bounds a single send through an HTTP API provider
+const sendTimeout = 30 * time.Second
+
 // Sender defines the interface for an email sender.
 type Sender interface {
 	SendVerificationEmail(to, otp string) error
+	// unsubscribeURL is the one-click unsubscribe link of non-essential emails, empty for transactional ones
+	SendNotificationEmail(to, subject, message, link, unsubscribeURL string) error
+	SendDigestEmail(to string, unreadCount int64, items []DigestItem, link, unsubscribeURL string) error
+	SendCampaignEmail(to, subject string, body template.HTML, unsubscribeURL string) error
+	Ping(ctx context.Context) error
+}
+
+// ErrNotConfigured is returned by Ping when no email provider is configured and emails are only logged.
+var ErrNotConfigured = errors.New("email provider is not configured")
+
+// DigestItem is a single notification listed in a digest email.
+type DigestItem struct {
+	Message   string
+	Link      string
+	CreatedAt time.Time
+}
+
+// Message is a rendered email, ready to be handed to any provider.
+type Message struct {
+	To             string
+	Subject        string
+	HTML           string
+	UnsubscribeURL string // Sent as the List-Unsubscribe header when set
 }
 
-// SMTPSender is an implementation of Sender that uses SMTP.
-type SMTPSender struct {
-	from string
-	auth smtp.Auth
-	addr string
+// transport delivers rendered messages through one email provider.
+type transport interface {
+	Send(ctx context.Context, msg *Message) error
+	Ping(ctx context.Context) error
 }
 
-// NewSMTPSender creates a new SMTPSender.
-// If SMTP configuration is incomplete, it returns a noopSender that only logs the OTP.
-func NewSMTPSender() Sender {
-	smtpCfg := config.Cfg.SMTP
-	if smtpCfg.User == "" || smtpCfg.Pass == "" || smtpCfg.Host == "smtp.example.com" {
-		log.Println("WARNING: SMTP is not fully configured. Email sending is disabled and will be logged to console instead.")
+// NewSender creates a Sender for the configured provider (smtp, sendgrid or ses).
+// If the provider is unknown or its configuration is incomplete, it returns a noopSender that only logs.
+func NewSender() Sender {
+	cfg := config.Cfg.Email
+
+	var t transport
+	var err error
+	switch cfg.Provider {
+	case "smtp":
+		t, err = newSMTPTransport(config.Cfg.SMTP, cfg.SenderName)
+	case "sendgrid":
+		t, err = newSendGridTransport(cfg)
+	case "ses":
+		t, err = newSESTransport(cfg)
+	default:
+		slog.Warn("Unknown email provider, email sending is disabled and will be logged instead", "provider", cfg.Provider)
+		return &noopSender{}
+	}
+	if err != nil {
+		slog.Warn("Email provider is not fully configured, email sending is disabled and will be logged instead", "provider", cfg.Provider, "error", err)
 		return &noopSender{}
 	}
 
-	auth := smtp.PlainAuth("", smtpCfg.User, smtpCfg.Pass, smtpCfg.Host)
-	addr := fmt.Sprintf("%s:%d", smtpCfg.Host, smtpCfg.Port)
-	// The 'from' address must be the same as the user used for authentication for many SMTP servers.
-	from := smtpCfg.User
+	slog.Info("Email sender initialized", "provider", cfg.Provider)
+	return &providerSender{transport: t, senderName: cfg.SenderName}
+}
 
-	return &SMTPSender{
-		from: from,
-		auth: auth,
-		addr: addr,
-	}
+// providerSender renders emails and sends them through a transport.
+type providerSender struct {
+	transport  transport
+	senderName string
 }
 
 // SendVerificationEmail sends an email with the OTP code.
-func (s *SMTPSender) SendVerificationEmail(to, otp string) error {
-	// Constructing the email headers and body
-	subject := "Subject: Your Verification Code for UIT AI Assistant\r\n"
-	mime := "MIME-version: 1.0;\nContent-Type: text/html; charset=\"UTF-8\";\n\n"
-
-	data := struct {
-		OTP        string
-		SenderName string
-	}{
-		OTP:        otp,
-		SenderName: config.Cfg.SMTP.SenderName,
+func (s *providerSender) SendVerificationEmail(to, otp string) error {
+	msg, err := renderVerificationEmail(to, otp, s.senderName)
+	if err != nil {
+		return err
+	}
+	if err := s.send(msg); err != nil {
+		return err
 	}
 
-	// Using an HTML template
-	t, err := template.New("email").Parse(verificationEmailTemplate)
+	slog.Info("Verification email sent", "to", to)
+	return nil
+}
+
+// SendNotificationEmail sends a notification message with an optional link back to the app.
+func (s *providerSender) SendNotificationEmail(to, subject, message, link, unsubscribeURL string) error {
+	msg, err := renderNotificationEmail(to, subject, message, link, unsubscribeURL, s.senderName)
 	if err != nil {
-		log.Printf("Error parsing email template: %v", err)
 		return err
 	}
+	if err := s.send(msg); err != nil {
+		return err
+	}
+
+	slog.Info("Notification email sent", "to", to)
+	return nil
+}
 
-	var body bytes.Buffer
-	if err := t.Execute(&body, data); err != nil {
-		log.Printf("Error executing email template: %v", err)
+// SendDigestEmail sends a summary of the recipient's unread notifications.
+func (s *providerSender) SendDigestEmail(to string, unreadCount int64, items []DigestItem, link, unsubscribeURL string) error {
+	msg, err := renderDigestEmail(to, unreadCount, items, link, unsubscribeURL, s.senderName)
+	if err != nil {
+		return err
+	}
+	if err := s.send(msg); err != nil {
 		return err
 	}
 
-	// The message must be in the format of `To`, `Subject`, then the body.
-	// The `from` address is passed to SendMail directly.
-	headers := fmt.Sprintf("To: %s\r\n%s", to, subject)
-	msg := []byte(headers + mime + body.String())
+	slog.Info("Digest email sent", "to", to)
+	return nil
+}
 
-	err = smtp.SendMail(s.addr, s.auth, s.from, []string{to}, msg)
+// SendCampaignEmail sends an admin campaign email. body is already rendered for the recipient.
+func (s *providerSender) SendCampaignEmail(to, subject string, body template.HTML, unsubscribeURL string) error {
+	msg, err := renderCampaignEmail(to, subject, body, unsubscribeURL, s.senderName)
 	if err != nil {
-		log.Printf("Failed to send email to %s: %v", to, err)
 		return err
 	}
+	return s.send(msg)
+}
+
+// Ping checks that the provider is reachable and accepts the credentials, without sending anything.
+func (s *providerSender) Ping(ctx context.Context) error {
+	return s.transport.Ping(ctx)
+}
 
-	log.Printf("Verification email sent to %s", to)
+func (s *providerSender) send(msg *Message) error {
+	ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+	defer cancel()
+
+	if err := s.transport.Send(ctx, msg); err != nil {
+		slog.Error("Failed to send email", "to", msg.To, "error", err)
+		return err
+	}
 	return nil
 }
 
-// noopSender is a sender that does nothing but log. Used when SMTP is not configured.
+// noopSender is a sender that does nothing but log. Used when no email provider is configured.
 type noopSender struct{}
 
 func (s *noopSender) SendVerificationEmail(to, otp string) error {
-	log.Printf("Email sending is disabled. OTP for %s: %s", to, otp)
+	slog.Info("Email sending is disabled, verification email not sent", "to", to, "otp", otp)
+	return nil
+}
+
+func (s *noopSender) SendNotificationEmail(to, subject, message, link, unsubscribeURL string) error {
+	slog.Info("Email sending is disabled, notification email not sent", "to", to, "subject", subject, "message", message)
 	return nil
 }
 
-const verificationEmailTemplate = `
-<!DOCTYPE html>
-<html>
-<head>
-<style>
-  .container { font-family: Arial, sans-serif; line-height: 1.6; color: #333; max-width: 600px; margin: 20px auto; border: 1px solid #ddd; border-radius: 5px; }
-  .header { background-color: #f7f7f7; padding: 15px; text-align: center; border-bottom: 1px solid #ddd; }
-  .content { padding: 20px; }
-  .otp { font-size: 24px; font-weight: bold; color: #007bff; text-align: center; letter-spacing: 3px; margin: 20px 0; padding: 10px; background-color: #f2f2f2; border-radius: 3px; }
-  .footer { font-size: 0.9em; text-align: center; color: #777; padding: 15px; border-top: 1px solid #ddd; }
-</style>
-</head>
-<body>
-  <div class="container">
-    <div class="header">
-      <h2>{{.SenderName}} Email Verification</h2>
-    </div>
-    <div class="content">
-      <p>Hello,</p>
-      <p>Thank you for registering. Please use the following One-Time Password (OTP) to verify your email address:</p>
-      <div class="otp">{{.OTP}}</div>
-      <p>This code will expire in 15 minutes.</p>
-      <p>If you did not request this, please ignore this email.</p>
-    </div>
-    <div class="footer">
-      <p>&copy; {{.SenderName}}. All rights reserved.</p>
-    </div>
-  </div>
-</body>
-</html>
-`
+func (s *noopSender) SendDigestEmail(to string, unreadCount int64, items []DigestItem, link, unsubscribeURL string) error {
+	slog.Info("Email sending is disabled, digest email not sent", "to", to, "unread", unreadCount)
+	return nil
+}
+
+func (s *noopSender) SendCampaignEmail(to, subject string, body template.HTML, unsubscribeURL string) error {
+	slog.Info("Email sending is disabled, campaign email not sent", "to", to, "subject", subject)
+	return nil
+}
+
+func (s *noopSender) Ping(ctx context.Context) error {
+	return ErrNotConfigured
+}
@@ -0,0 +1,219 @@
+package email
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+)
+
+// Templates are shared by all providers, which only differ in how the rendered HTML is delivered
+var (
+	verificationTemplate = template.Must(template.New("verification").Parse(verificationEmailTemplate))
+	notificationTemplate = template.Must(template.New("notification").Parse(notificationEmailTemplate))
+	digestTemplate       = template.Must(template.New("digest").Parse(digestEmailTemplate))
+	campaignTemplate     = template.Must(template.New("campaign").Parse(campaignEmailTemplate))
+)
+
+func renderVerificationEmail(to, otp, senderName string) (*Message, error) {
+	data := struct {
+		OTP        string
+		SenderName string
+	}{
+		OTP:        otp,
+		SenderName: senderName,
+	}
+	return render(verificationTemplate, to, "Your Verification Code for UIT AI Assistant", "", data)
+}
+
+func renderNotificationEmail(to, subject, message, link, unsubscribeURL, senderName string) (*Message, error) {
+	data := struct {
+		Subject        string
+		Message        string
+		Link           string
+		UnsubscribeURL string
+		SenderName     string
+	}{
+		Subject:        subject,
+		Message:        message,
+		Link:           link,
+		UnsubscribeURL: unsubscribeURL,
+		SenderName:     senderName,
+	}
+	return render(notificationTemplate, to, subject, unsubscribeURL, data)
+}
+
+func renderDigestEmail(to string, unreadCount int64, items []DigestItem, link, unsubscribeURL, senderName string) (*Message, error) {
+	subject := fmt.Sprintf("Bạn có %d thông báo chưa đọc", unreadCount)
+
+	data := struct {
+		Subject        string
+		UnreadCount    int64
+		Items          []DigestItem
+		More           int64
+		Link           string
+		UnsubscribeURL string
+		SenderName     string
+	}{
+		Subject:        subject,
+		UnreadCount:    unreadCount,
+		Items:          items,
+		More:           unreadCount - int64(len(items)),
+		Link:           link,
+		UnsubscribeURL: unsubscribeURL,
+		SenderName:     senderName,
+	}
+	return render(digestTemplate, to, subject, unsubscribeURL, data)
+}
+
+func renderCampaignEmail(to, subject string, body template.HTML, unsubscribeURL, senderName string) (*Message, error) {
+	data := struct {
+		Subject        string
+		Body           template.HTML
+		UnsubscribeURL string
+		SenderName     string
+	}{
+		Subject:        subject,
+		Body:           body,
+		UnsubscribeURL: unsubscribeURL,
+		SenderName:     senderName,
+	}
+	return render(campaignTemplate, to, subject, unsubscribeURL, data)
+}
+
+func render(t *template.Template, to, subject, unsubscribeURL string, data any) (*Message, error) {
+	var body bytes.Buffer
+	if err := t.Execute(&body, data); err != nil {
+		return nil, fmt.Errorf("render %s email: %w", t.Name(), err)
+	}
+	return &Message{To: to, Subject: subject, HTML: body.String(), UnsubscribeURL: unsubscribeURL}, nil
+}
+
+const verificationEmailTemplate = `
+<!DOCTYPE html>
+<html>
+<head>
+<style>
+  .container { font-family: Arial, sans-serif; line-height: 1.6; color: #333; max-width: 600px; margin: 20px auto; border: 1px solid #ddd; border-radius: 5px; }
+  .header { background-color: #f7f7f7; padding: 15px; text-align: center; border-bottom: 1px solid #ddd; }
+  .content { padding: 20px; }
+  .otp { font-size: 24px; font-weight: bold; color: #007bff; text-align: center; letter-spacing: 3px; margin: 20px 0; padding: 10px; background-color: #f2f2f2; border-radius: 3px; }
+  .footer { font-size: 0.9em; text-align: center; color: #777; padding: 15px; border-top: 1px solid #ddd; }
+</style>
+</head>
+<body>
+  <div class="container">
+    <div class="header">
+      <h2>{{.SenderName}} Email Verification</h2>
+    </div>
+    <div class="content">
+      <p>Hello,</p>
+      <p>Thank you for registering. Please use the following One-Time Password (OTP) to verify your email address:</p>
+      <div class="otp">{{.OTP}}</div>
+      <p>This code will expire in 15 minutes.</p>
+      <p>If you did not request this, please ignore this email.</p>
+    </div>
+    <div class="footer">
+      <p>&copy; {{.SenderName}}. All rights reserved.</p>
+    </div>
+  </div>
+</body>
+</html>
+`
+
+const notificationEmailTemplate = `
+<!DOCTYPE html>
+<html>
+<head>
+<style>
+  .container { font-family: Arial, sans-serif; line-height: 1.6; color: #333; max-width: 600px; margin: 20px auto; border: 1px solid #ddd; border-radius: 5px; }
+  .header { background-color: #f7f7f7; padding: 15px; text-align: center; border-bottom: 1px solid #ddd; }
+  .content { padding: 20px; }
+  .button { display: inline-block; padding: 10px 20px; background-color: #007bff; color: #fff; text-decoration: none; border-radius: 3px; }
+  .footer { font-size: 0.9em; text-align: center; color: #777; padding: 15px; border-top: 1px solid #ddd; }
+</style>
+</head>
+<body>
+  <div class="container">
+    <div class="header">
+      <h2>{{.Subject}}</h2>
+    </div>
+    <div class="content">
+      <p>{{.Message}}</p>
+      {{if .Link}}<p style="text-align: center;"><a class="button" href="{{.Link}}">Xem chi tiết</a></p>{{end}}
+    </div>
+    <div class="footer">
+      <p>You can change which emails you receive in your notification settings.</p>
+      {{if .UnsubscribeURL}}<p><a href="{{.UnsubscribeURL}}">Unsubscribe from these emails</a></p>{{end}}
+      <p>&copy; {{.SenderName}}. All rights reserved.</p>
+    </div>
+  </div>
+</body>
+</html>
+`
+
+const digestEmailTemplate = `
+<!DOCTYPE html>
+<html>
+<head>
+<style>
+  .container { font-family: Arial, sans-serif; line-height: 1.6; color: #333; max-width: 600px; margin: 20px auto; border: 1px solid #ddd; border-radius: 5px; }
+  .header { background-color: #f7f7f7; padding: 15px; text-align: center; border-bottom: 1px solid #ddd; }
+  .content { padding: 20px; }
+  .item { padding: 10px 0; border-bottom: 1px solid #eee; }
+  .item .time { font-size: 0.85em; color: #777; }
+  .button { display: inline-block; padding: 10px 20px; background-color: #007bff; color: #fff; text-decoration: none; border-radius: 3px; }
+  .footer { font-size: 0.9em; text-align: center; color: #777; padding: 15px; border-top: 1px solid #ddd; }
+</style>
+</head>
+<body>
+  <div class="container">
+    <div class="header">
+      <h2>{{.Subject}}</h2>
+    </div>
+    <div class="content">
+      {{range .Items}}
+      <div class="item">
+        <div>{{.Message}}</div>
+        <div class="time">{{.CreatedAt.Format "02/01/2006 15:04"}}</div>
+      </div>
+      {{end}}
+      {{if gt .More 0}}<p>... và {{.More}} thông báo khác.</p>{{end}}
+      <p style="text-align: center;"><a class="button" href="{{.Link}}">Xem tất cả thông báo</a></p>
+    </div>
+    <div class="footer">
+      <p>You can change how often you receive this digest in your settings.</p>
+      {{if .UnsubscribeURL}}<p><a href="{{.UnsubscribeURL}}">Unsubscribe from the digest</a></p>{{end}}
+      <p>&copy; {{.SenderName}}. All rights reserved.</p>
+    </div>
+  </div>
+</body>
+</html>
+`
+
+const campaignEmailTemplate = `
+<!DOCTYPE html>
+<html>
+<head>
+<style>
+  .container { font-family: Arial, sans-serif; line-height: 1.6; color: #333; max-width: 600px; margin: 20px auto; border: 1px solid #ddd; border-radius: 5px; }
+  .header { background-color: #f7f7f7; padding: 15px; text-align: center; border-bottom: 1px solid #ddd; }
+  .content { padding: 20px; }
+  .footer { font-size: 0.9em; text-align: center; color: #777; padding: 15px; border-top: 1px solid #ddd; }
+</style>
+</head>
+<body>
+  <div class="container">
+    <div class="header">
+      <h2>{{.Subject}}</h2>
+    </div>
+    <div class="content">
+      {{.Body}}
+    </div>
+    <div class="footer">
+      {{if .UnsubscribeURL}}<p><a href="{{.UnsubscribeURL}}">Unsubscribe from product emails</a></p>{{end}}
+      <p>&copy; {{.SenderName}}. All rights reserved.</p>
+    </div>
+  </div>
+</body>
+</html>
+`
@@ -0,0 +1,105 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+)
+
+const sendGridBaseURL = "https://api.sendgrid.com/v3"
+
+// sendGridTransport sends emails through the SendGrid v3 Web API.
+type sendGridTransport struct {
+	apiKey     string
+	from       string
+	senderName string
+	httpClient *http.Client
+}
+
+func newSendGridTransport(cfg config.EmailConfig) (transport, error) {
+	if cfg.SendGrid.APIKey == "" || cfg.From == "" {
+		return nil, ErrNotConfigured
+	}
+	return &sendGridTransport{
+		apiKey:     cfg.SendGrid.APIKey,
+		from:       cfg.From,
+		senderName: cfg.SenderName,
+		httpClient: &http.Client{Timeout: sendTimeout},
+	}, nil
+}
+
+type sendGridAddress struct {
+	Email string `json:"email"`
+	Name  string `json:"name,omitempty"`
+}
+
+type sendGridContent struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type sendGridPersonalization struct {
+	To []sendGridAddress `json:"to"`
+}
+
+type sendGridMail struct {
+	Personalizations []sendGridPersonalization `json:"personalizations"`
+	From             sendGridAddress           `json:"from"`
+	Subject          string                    `json:"subject"`
+	Content          []sendGridContent         `json:"content"`
+	Headers          map[string]string         `json:"headers,omitempty"`
+}
+
+func (t *sendGridTransport) Send(ctx context.Context, msg *Message) error {
+	mail := sendGridMail{
+		Personalizations: []sendGridPersonalization{{To: []sendGridAddress{{Email: msg.To}}}},
+		From:             sendGridAddress{Email: t.from, Name: t.senderName},
+		Subject:          msg.Subject,
+		Content:          []sendGridContent{{Type: "text/html", Value: msg.HTML}},
+	}
+	if msg.UnsubscribeURL != "" {
+		mail.Headers = map[string]string{"List-Unsubscribe": fmt.Sprintf("<%s>", msg.UnsubscribeURL)}
+	}
+
+	body, err := json.Marshal(mail)
+	if err != nil {
+		return err
+	}
+	return t.do(ctx, http.MethodPost, "/mail/send", body)
+}
+
+// Ping lists the scopes of the API key, which fails if the key is invalid or revoked.
+func (t *sendGridTransport) Ping(ctx context.Context) error {
+	return t.do(ctx, http.MethodGet, "/scopes", nil)
+}
+
+func (t *sendGridTransport) do(ctx context.Context, method, path string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, method, sendGridBaseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+t.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return checkProviderResponse("sendgrid", resp)
+}
+
+// checkProviderResponse turns a non-2xx response of an email API into an error carrying the start of its body
+func checkProviderResponse(provider string, resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("%s: %s: %s", provider, resp.Status, bytes.TrimSpace(detail))
+}
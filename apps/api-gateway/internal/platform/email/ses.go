@@ -0,0 +1,141 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+)
+
+// sesTransport sends emails through the Amazon SES v2 API. Requests are signed with AWS Signature Version 4
+// using static credentials; instance or task roles are not supported.
+type sesTransport struct {
+	region          string
+	host            string
+	accessKeyID     string
+	secretAccessKey string
+	from            string
+	httpClient      *http.Client
+}
+
+func newSESTransport(cfg config.EmailConfig) (transport, error) {
+	if cfg.SES.Region == "" || cfg.SES.AccessKeyID == "" || cfg.SES.SecretAccessKey == "" || cfg.From == "" {
+		return nil, ErrNotConfigured
+	}
+	return &sesTransport{
+		region:          cfg.SES.Region,
+		host:            fmt.Sprintf("email.%s.amazonaws.com", cfg.SES.Region),
+		accessKeyID:     cfg.SES.AccessKeyID,
+		secretAccessKey: cfg.SES.SecretAccessKey,
+		from:            (&mail.Address{Name: cfg.SenderName, Address: cfg.From}).String(),
+		httpClient:      &http.Client{Timeout: sendTimeout},
+	}, nil
+}
+
+type sesContent struct {
+	Data    string `json:"Data"`
+	Charset string `json:"Charset"`
+}
+
+type sesHeader struct {
+	Name  string `json:"Name"`
+	Value string `json:"Value"`
+}
+
+type sesSendEmailRequest struct {
+	FromEmailAddress string `json:"FromEmailAddress"`
+	Destination      struct {
+		ToAddresses []string `json:"ToAddresses"`
+	} `json:"Destination"`
+	Content struct {
+		Simple struct {
+			Subject sesContent `json:"Subject"`
+			Body    struct {
+				HTML sesContent `json:"Html"`
+			} `json:"Body"`
+			Headers []sesHeader `json:"Headers,omitempty"`
+		} `json:"Simple"`
+	} `json:"Content"`
+}
+
+func (t *sesTransport) Send(ctx context.Context, msg *Message) error {
+	var req sesSendEmailRequest
+	req.FromEmailAddress = t.from
+	req.Destination.ToAddresses = []string{msg.To}
+	req.Content.Simple.Subject = sesContent{Data: msg.Subject, Charset: "UTF-8"}
+	req.Content.Simple.Body.HTML = sesContent{Data: msg.HTML, Charset: "UTF-8"}
+	if msg.UnsubscribeURL != "" {
+		req.Content.Simple.Headers = []sesHeader{{Name: "List-Unsubscribe", Value: fmt.Sprintf("<%s>", msg.UnsubscribeURL)}}
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	return t.do(ctx, http.MethodPost, "/v2/email/outbound-emails", body)
+}
+
+// Ping reads the account's sending status, which fails if the credentials or region are wrong.
+func (t *sesTransport) Ping(ctx context.Context) error {
+	return t.do(ctx, http.MethodGet, "/v2/email/account", nil)
+}
+
+func (t *sesTransport) do(ctx context.Context, method, path string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, method, "https://"+t.host+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	t.sign(req, body, time.Now())
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return checkProviderResponse("ses", resp)
+}
+
+// sign adds the Signature Version 4 headers. Only host and x-amz-date are signed, the body through its hash.
+func (t *sesTransport) sign(req *http.Request, body []byte, now time.Time) {
+	const service = "ses"
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	signedHeaders := "host;x-amz-date"
+	canonicalRequest := fmt.Sprintf("%s\n%s\n%s\nhost:%s\nx-amz-date:%s\n\n%s\n%s",
+		req.Method, req.URL.EscapedPath(), req.URL.RawQuery, t.host, amzDate, signedHeaders, sha256Hex(body))
+
+	scope := fmt.Sprintf("%s/%s/%s/aws4_request", date, t.region, service)
+	stringToSign := fmt.Sprintf("AWS4-HMAC-SHA256\n%s\n%s\n%s", amzDate, scope, sha256Hex([]byte(canonicalRequest)))
+
+	key := hmacSHA256([]byte("AWS4"+t.secretAccessKey), date)
+	key = hmacSHA256(key, t.region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		t.accessKeyID, scope, signedHeaders, signature))
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,71 @@
+package email
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+)
+
+// smtpTransport sends emails through an SMTP relay.
+type smtpTransport struct {
+	from       string // Envelope sender
+	fromHeader string // Envelope sender with the display name
+	auth       smtp.Auth
+	addr       string
+	host       string
+}
+
+func newSMTPTransport(cfg config.SMTPConfig, senderName string) (transport, error) {
+	if cfg.User == "" || cfg.Pass == "" || cfg.Host == "smtp.example.com" {
+		return nil, ErrNotConfigured
+	}
+
+	// The 'from' address must be the same as the user used for authentication for many SMTP servers.
+	return &smtpTransport{
+		from:       cfg.User,
+		fromHeader: (&mail.Address{Name: senderName, Address: cfg.User}).String(),
+		auth:       smtp.PlainAuth("", cfg.User, cfg.Pass, cfg.Host),
+		addr:       fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
+		host:       cfg.Host,
+	}, nil
+}
+
+func (t *smtpTransport) Send(ctx context.Context, msg *Message) error {
+	// Subjects may contain Vietnamese characters, so encode them per RFC 2047
+	headers := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\n", t.fromHeader, msg.To, mime.QEncoding.Encode("UTF-8", msg.Subject))
+	if msg.UnsubscribeURL != "" {
+		headers += fmt.Sprintf("List-Unsubscribe: <%s>\r\n", msg.UnsubscribeURL)
+	}
+	contentType := "MIME-version: 1.0;\nContent-Type: text/html; charset=\"UTF-8\";\n\n"
+
+	return smtp.SendMail(t.addr, t.auth, t.from, []string{msg.To}, []byte(headers+contentType+msg.HTML))
+}
+
+// Ping connects to the SMTP server and exchanges greetings without sending anything.
+func (t *smtpTransport) Ping(ctx context.Context) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", t.addr)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, t.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if err := client.Hello("localhost"); err != nil {
+		return err
+	}
+	return client.Quit()
+}
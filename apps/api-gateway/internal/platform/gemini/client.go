@@ -6,7 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"strings"
 	"time"
@@ -25,7 +25,7 @@ type GeminiClient struct {
 
 func NewGeminiClient(cfg *config.GeminiConfig) (*GeminiClient, error) {
 	if !cfg.Enabled {
-		log.Println("Gemini moderation is disabled")
+		slog.Info("Gemini moderation is disabled")
 		return nil, nil
 	}
 
@@ -59,7 +59,7 @@ func NewGeminiClient(cfg *config.GeminiConfig) (*GeminiClient, error) {
 		Timeout: time.Duration(cfg.Timeout) * time.Second,
 	}
 
-	log.Printf("Gemini client initialized with model: %s", cfg.Model)
+	slog.Info("Gemini client initialized", "model", cfg.Model)
 
 	return &GeminiClient{
 		client:     client,
@@ -90,13 +90,13 @@ func (c *GeminiClient) CheckContent(ctx context.Context, req *ContentCheckReques
 	for _, imageURL := range req.ImageURLs {
 		imgData, mimeType, err := c.downloadImage(imageURL)
 		if err != nil {
-			log.Printf("Failed to download image %s: %v", imageURL, err)
+			slog.Warn("Failed to download image", "url", imageURL, "error", err)
 			continue
 		}
 
 		// Resize if too large (Gemini limit: 20MB)
 		if len(imgData) > 10*1024*1024 {
-			log.Printf("Image too large (%d bytes), skipping: %s", len(imgData), imageURL)
+			slog.Warn("Image too large, skipping", "bytes", len(imgData), "url", imageURL)
 			continue
 		}
 
@@ -107,7 +107,7 @@ func (c *GeminiClient) CheckContent(ctx context.Context, req *ContentCheckReques
 	for _, videoURL := range req.VideoURLs {
 		// For videos, we just note them in the prompt
 		// Actual video analysis would require more complex processing
-		log.Printf("Video URL provided: %s (thumbnail check only)", videoURL)
+		slog.Debug("Video URL provided, thumbnail check only", "url", videoURL)
 	}
 
 	// Call Gemini with retry
@@ -120,7 +120,7 @@ func (c *GeminiClient) CheckContent(ctx context.Context, req *ContentCheckReques
 			break
 		}
 
-		log.Printf("Gemini API error (attempt %d/%d): %v", attempt+1, c.config.MaxRetries, err)
+		slog.Warn("Gemini API error", "attempt", attempt+1, "max_attempts", c.config.MaxRetries, "error", err)
 		if attempt < c.config.MaxRetries-1 {
 			time.Sleep(time.Duration(attempt+1) * time.Second) // Exponential backoff
 		}
@@ -221,6 +221,12 @@ func (c *GeminiClient) parseResponse(resp *genai.GenerateContentResponse) (*Cont
 	return &result, nil
 }
 
+// Ping fetches the model metadata, which checks the API key without generating content
+func (c *GeminiClient) Ping(ctx context.Context) error {
+	_, err := c.model.Info(ctx)
+	return err
+}
+
 func (c *GeminiClient) Close() error {
 	if c != nil && c.client != nil {
 		return c.client.Close()
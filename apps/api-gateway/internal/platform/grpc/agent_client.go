@@ -3,11 +3,15 @@ package grpc
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/grpc/pb"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/requestid"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
 )
 
 // AgentClient wraps the gRPC client for the Agent service
@@ -25,6 +29,7 @@ func NewAgentClient(addr string) (*AgentClient, error) {
 			grpc.MaxCallRecvMsgSize(50*1024*1024), // 50MB
 			grpc.MaxCallSendMsgSize(50*1024*1024), // 50MB
 		),
+		grpc.WithUnaryInterceptor(forwardRequestID),
 	}
 
 	// Dial gRPC server
@@ -42,6 +47,14 @@ func NewAgentClient(addr string) (*AgentClient, error) {
 	}, nil
 }
 
+// forwardRequestID sends the request ID of ctx as gRPC metadata, so agent logs can be matched with the gateway's
+func forwardRequestID(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if id := requestid.FromContext(ctx); id != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, requestid.MetadataKey, id)
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
 // Close closes the gRPC connection
 func (c *AgentClient) Close() error {
 	if c.conn != nil {
@@ -50,14 +63,43 @@ func (c *AgentClient) Close() error {
 	return nil
 }
 
+// Ping waits until the connection to the agent server is ready, dialing it if idle.
+// It fails if the connection is not ready before ctx expires.
+func (c *AgentClient) Ping(ctx context.Context) error {
+	c.conn.Connect()
+	for {
+		state := c.conn.GetState()
+		switch state {
+		case connectivity.Ready:
+			return nil
+		case connectivity.Shutdown:
+			return fmt.Errorf("connection is closed")
+		}
+
+		if !c.conn.WaitForStateChange(ctx, state) {
+			return fmt.Errorf("connection not ready: %s", strings.ToLower(state.String()))
+		}
+	}
+}
+
 // Chat sends a chat request to the agent and returns the response
 // Uses stateful architecture with thread_id for conversation persistence
-func (c *AgentClient) Chat(ctx context.Context, message string, userID string, threadID string) (*AgentResponse, error) {
+// language is the resolved reply language ("vi" | "en"), student the user's profile (zero value if unknown),
+// opts the user's chat behavior settings
+func (c *AgentClient) Chat(ctx context.Context, message string, userID string, threadID string, language string, student StudentProfile, opts ChatOptions) (*AgentResponse, error) {
 	// Create request (no history needed - LangGraph checkpointer manages state)
 	req := &pb.ChatRequest{
-		Message:  message,
-		UserId:   userID,
-		ThreadId: threadID,
+		Message:        message,
+		UserId:         userID,
+		ThreadId:       threadID,
+		Language:       language,
+		StudentId:      student.StudentID,
+		Faculty:        student.Faculty,
+		Program:        student.Program,
+		EnrollmentYear: int32(student.EnrollmentYear),
+		Model:          opts.Model,
+		OmitCitations:  opts.OmitCitations,
+		BlockedTopics:  opts.BlockedTopics,
 	}
 
 	// Set timeout (10 minutes for complex retrievals with MCP tools)
@@ -65,10 +107,18 @@ func (c *AgentClient) Chat(ctx context.Context, message string, userID string, t
 	defer cancel()
 
 	// Call gRPC
+	start := time.Now()
 	resp, err := c.client.Chat(callCtx, req)
+	agentRequestDuration.WithLabel("chat").Observe(time.Since(start).Seconds())
 	if err != nil {
+		agentRequests.WithLabel("chat", resultError).Inc()
+		// gRPC reports a deadline as a status error, callers check for the context error
+		if ctxErr := callCtx.Err(); ctxErr != nil {
+			return nil, fmt.Errorf("gRPC call failed: %w", ctxErr)
+		}
 		return nil, fmt.Errorf("gRPC call failed: %w", err)
 	}
+	agentRequests.WithLabel("chat", resultOK).Inc()
 
 	// Convert response
 	return c.convertResponse(resp), nil
@@ -107,6 +157,21 @@ func (c *AgentClient) convertResponse(resp *pb.ChatResponse) *AgentResponse {
 	}
 }
 
+// StudentProfile carries the user's enrollment details so the agent can personalize answers
+type StudentProfile struct {
+	StudentID      string
+	Faculty        string
+	Program        string
+	EnrollmentYear int
+}
+
+// ChatOptions carries the user's chat behavior settings to the agent
+type ChatOptions struct {
+	Model         string // Empty = agent default model
+	OmitCitations bool
+	BlockedTopics []string // Topics the user does not want the assistant to bring up
+}
+
 // AgentResponse represents the response from the agent
 type AgentResponse struct {
 	Content        string     // Clean response text
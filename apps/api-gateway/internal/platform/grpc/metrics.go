@@ -0,0 +1,24 @@
+package grpc
+
+import "github.com/giakiet05/uit-ai-assistant/backend/internal/platform/metrics"
+
+// Agent call metrics, exposed on /metrics
+var (
+	agentRequests = metrics.NewCounterVec(
+		"agent_requests_total",
+		"Calls to the agent gRPC server, by method and result.",
+		"method", "result",
+	)
+	agentRequestDuration = metrics.NewHistogramVec(
+		"agent_request_duration_seconds",
+		"Time the agent took to answer a call, by method.",
+		metrics.DefaultBuckets,
+		"method",
+	)
+)
+
+// Results for agent_requests_total
+const (
+	resultOK    = "ok"
+	resultError = "error"
+)
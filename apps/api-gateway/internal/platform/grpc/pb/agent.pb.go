@@ -153,12 +153,20 @@ func (x *Source) GetUrl() string {
 
 // Request gọi agent (stateful architecture)
 type ChatRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`                   // Câu hỏi của user
-	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`       // User ID (để lookup credentials từ Redis)
-	ThreadId      string                 `protobuf:"bytes,3,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"` // Thread ID cho LangGraph checkpointer (format: "user_id:conversation_id")
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Message        string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`                                      // Câu hỏi của user
+	UserId         string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                          // User ID (để lookup credentials từ Redis)
+	ThreadId       string                 `protobuf:"bytes,3,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"`                    // Thread ID cho LangGraph checkpointer (format: "user_id:conversation_id")
+	Language       string                 `protobuf:"bytes,4,opt,name=language,proto3" json:"language,omitempty"`                                    // Ngôn ngữ trả lời ("vi" | "en"), đã resolve từ session override hoặc user settings
+	StudentId      string                 `protobuf:"bytes,5,opt,name=student_id,json=studentId,proto3" json:"student_id,omitempty"`                 // MSSV, rỗng nếu user chưa cập nhật hồ sơ
+	Faculty        string                 `protobuf:"bytes,6,opt,name=faculty,proto3" json:"faculty,omitempty"`                                      // Khoa
+	Program        string                 `protobuf:"bytes,7,opt,name=program,proto3" json:"program,omitempty"`                                      // Chương trình đào tạo
+	EnrollmentYear int32                  `protobuf:"varint,8,opt,name=enrollment_year,json=enrollmentYear,proto3" json:"enrollment_year,omitempty"` // Năm nhập học, 0 nếu chưa cập nhật
+	Model          string                 `protobuf:"bytes,9,opt,name=model,proto3" json:"model,omitempty"`                                          // Model user chọn, rỗng = model mặc định của agent
+	OmitCitations  bool                   `protobuf:"varint,10,opt,name=omit_citations,json=omitCitations,proto3" json:"omit_citations,omitempty"`   // User tắt trích dẫn nguồn trong câu trả lời
+	BlockedTopics  []string               `protobuf:"bytes,11,rep,name=blocked_topics,json=blockedTopics,proto3" json:"blocked_topics,omitempty"`    // Chủ đề user không muốn assistant nhắc tới
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *ChatRequest) Reset() {
@@ -212,6 +220,62 @@ func (x *ChatRequest) GetThreadId() string {
 	return ""
 }
 
+func (x *ChatRequest) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *ChatRequest) GetStudentId() string {
+	if x != nil {
+		return x.StudentId
+	}
+	return ""
+}
+
+func (x *ChatRequest) GetFaculty() string {
+	if x != nil {
+		return x.Faculty
+	}
+	return ""
+}
+
+func (x *ChatRequest) GetProgram() string {
+	if x != nil {
+		return x.Program
+	}
+	return ""
+}
+
+func (x *ChatRequest) GetEnrollmentYear() int32 {
+	if x != nil {
+		return x.EnrollmentYear
+	}
+	return 0
+}
+
+func (x *ChatRequest) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *ChatRequest) GetOmitCitations() bool {
+	if x != nil {
+		return x.OmitCitations
+	}
+	return false
+}
+
+func (x *ChatRequest) GetBlockedTopics() []string {
+	if x != nil {
+		return x.BlockedTopics
+	}
+	return nil
+}
+
 // Response từ agent
 type ChatResponse struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
@@ -310,11 +374,21 @@ const file_agent_proto_rawDesc = "" +
 	"\x05title\x18\x01 \x01(\tR\x05title\x12\x18\n" +
 	"\acontent\x18\x02 \x01(\tR\acontent\x12\x14\n" +
 	"\x05score\x18\x03 \x01(\x02R\x05score\x12\x10\n" +
-	"\x03url\x18\x04 \x01(\tR\x03url\"]\n" +
+	"\x03url\x18\x04 \x01(\tR\x03url\"\xd9\x02\n" +
 	"\vChatRequest\x12\x18\n" +
 	"\amessage\x18\x01 \x01(\tR\amessage\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x1b\n" +
-	"\tthread_id\x18\x03 \x01(\tR\bthreadId\"\xea\x01\n" +
+	"\tthread_id\x18\x03 \x01(\tR\bthreadId\x12\x1a\n" +
+	"\blanguage\x18\x04 \x01(\tR\blanguage\x12\x1d\n" +
+	"\n" +
+	"student_id\x18\x05 \x01(\tR\tstudentId\x12\x18\n" +
+	"\afaculty\x18\x06 \x01(\tR\afaculty\x12\x18\n" +
+	"\aprogram\x18\a \x01(\tR\aprogram\x12'\n" +
+	"\x0fenrollment_year\x18\b \x01(\x05R\x0eenrollmentYear\x12\x14\n" +
+	"\x05model\x18\t \x01(\tR\x05model\x12%\n" +
+	"\x0eomit_citations\x18\n" +
+	" \x01(\bR\romitCitations\x12%\n" +
+	"\x0eblocked_topics\x18\v \x03(\tR\rblockedTopics\"\xea\x01\n" +
 	"\fChatResponse\x12\x18\n" +
 	"\acontent\x18\x01 \x01(\tR\acontent\x12.\n" +
 	"\n" +
@@ -0,0 +1,88 @@
+package logger
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+)
+
+// Redacted replaces the value of a sensitive field in logs
+const Redacted = "[REDACTED]"
+
+// sensitiveKeys are field names whose values are always redacted: credentials, and contact details that
+// identify a person
+var sensitiveKeys = map[string]bool{
+	"otp":           true,
+	"code":          true, // OAuth authorization codes
+	"authorization": true,
+	"api_key":       true,
+	"apikey":        true,
+	"signature":     true, // Signed links (calendar feed, unsubscribe, data export) work with the query alone
+	"sig":           true,
+	"expires":       true,
+	"email":         true,
+	"phone":         true,
+}
+
+// sensitiveKeyParts redact any field whose name contains them, e.g. new_password or refresh_token
+var sensitiveKeyParts = []string{"password", "token", "secret", "cookie"}
+
+// IsSensitiveKey reports whether values of the field name must not be logged
+func IsSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	if sensitiveKeys[key] {
+		return true
+	}
+	for _, part := range sensitiveKeyParts {
+		if strings.Contains(key, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactJSON returns the JSON document with the values of sensitive fields replaced, at any depth.
+// ok is false if the document cannot be parsed, in which case nothing of it may be logged.
+func RedactJSON(data []byte) (redacted string, ok bool) {
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return "", false
+	}
+	out, err := json.Marshal(redactValue(doc))
+	if err != nil {
+		return "", false
+	}
+	return string(out), true
+}
+
+func redactValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if IsSensitiveKey(key) {
+				v[key] = Redacted
+				continue
+			}
+			v[key] = redactValue(value)
+		}
+	case []any:
+		for i, value := range v {
+			v[i] = redactValue(value)
+		}
+	}
+	return v
+}
+
+// RedactQuery returns the encoded query string with the values of sensitive parameters replaced
+func RedactQuery(query url.Values) string {
+	redacted := make(url.Values, len(query))
+	for key, values := range query {
+		if IsSensitiveKey(key) {
+			redacted[key] = []string{Redacted}
+			continue
+		}
+		redacted[key] = values
+	}
+	// Keep the placeholder readable rather than percent-encoded
+	return strings.ReplaceAll(redacted.Encode(), url.QueryEscape(Redacted), Redacted)
+}
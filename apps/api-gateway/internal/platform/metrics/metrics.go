@@ -0,0 +1,273 @@
+// Package metrics is a minimal Prometheus instrumentation library.
+// Metrics are registered on a process-wide registry and exposed in the
+// Prometheus text exposition format by Handler.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// collector writes the samples of one metric family
+type collector interface {
+	write(w io.Writer, name string)
+}
+
+type family struct {
+	name      string
+	help      string
+	kind      string // "counter" | "gauge" | "histogram"
+	collector collector
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]*family)
+)
+
+func register(name, help, kind string, c collector) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, exists := registry[name]; exists {
+		panic("metrics: duplicate metric " + name)
+	}
+	registry[name] = &family{name: name, help: help, kind: kind, collector: c}
+}
+
+// Counter is a monotonically increasing value.
+type Counter struct {
+	v atomic.Int64
+}
+
+// NewCounter registers a counter.
+func NewCounter(name, help string) *Counter {
+	c := &Counter{}
+	register(name, help, "counter", c)
+	return c
+}
+
+func (c *Counter) Inc()         { c.v.Add(1) }
+func (c *Counter) Add(n int64)  { c.v.Add(n) }
+func (c *Counter) Value() int64 { return c.v.Load() }
+func (c *Counter) write(w io.Writer, name string) {
+	fmt.Fprintf(w, "%s %d\n", name, c.v.Load())
+}
+
+// Gauge is a value that can go up and down.
+type Gauge struct {
+	v atomic.Int64
+}
+
+// NewGauge registers a gauge.
+func NewGauge(name, help string) *Gauge {
+	g := &Gauge{}
+	register(name, help, "gauge", g)
+	return g
+}
+
+func (g *Gauge) Set(n int64)  { g.v.Store(n) }
+func (g *Gauge) Inc()         { g.v.Add(1) }
+func (g *Gauge) Dec()         { g.v.Add(-1) }
+func (g *Gauge) Value() int64 { return g.v.Load() }
+func (g *Gauge) write(w io.Writer, name string) {
+	fmt.Fprintf(w, "%s %d\n", name, g.v.Load())
+}
+
+// CounterVec is a set of counters partitioned by the values of their labels.
+type CounterVec struct {
+	labels   []string
+	mu       sync.RWMutex
+	counters map[string]*Counter
+	values   map[string][]string // Label values by key
+}
+
+// NewCounterVec registers a counter with one or more labels.
+func NewCounterVec(name, help string, labels ...string) *CounterVec {
+	v := &CounterVec{labels: labels, counters: make(map[string]*Counter), values: make(map[string][]string)}
+	register(name, help, "counter", v)
+	return v
+}
+
+// WithLabel returns the counter for the label values, in the order the labels were declared, creating it on
+// first use.
+func (v *CounterVec) WithLabel(values ...string) *Counter {
+	key := strings.Join(values, "\xff")
+
+	v.mu.RLock()
+	c, ok := v.counters[key]
+	v.mu.RUnlock()
+	if ok {
+		return c
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if c, ok = v.counters[key]; !ok {
+		c = &Counter{}
+		v.counters[key] = c
+		v.values[key] = values
+	}
+	return c
+}
+
+func (v *CounterVec) write(w io.Writer, name string) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	for _, key := range sortedKeys(v.counters) {
+		fmt.Fprintf(w, "%s{%s} %d\n", name, labelPairs(v.labels, v.values[key]), v.counters[key].Value())
+	}
+}
+
+// DefaultBuckets are latency buckets in seconds, from 5ms up to the 10 minutes an agent call may take
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300, 600}
+
+// Histogram counts observations into cumulative buckets.
+type Histogram struct {
+	buckets []float64
+	mu      sync.Mutex
+	counts  []int64 // Per bucket, not cumulative
+	sum     float64
+	count   int64
+}
+
+// Observe records one value, e.g. a duration in seconds.
+func (h *Histogram) Observe(value float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for i, bound := range h.buckets {
+		if value <= bound {
+			h.counts[i]++
+			break
+		}
+	}
+	h.sum += value
+	h.count++
+}
+
+func (h *Histogram) write(w io.Writer, name, labels string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	prefix := labels
+	if prefix != "" {
+		prefix += ","
+	}
+	var cumulative int64
+	for i, bound := range h.buckets {
+		cumulative += h.counts[i]
+		fmt.Fprintf(w, "%s_bucket{%sle=\"%s\"} %d\n", name, prefix, strconv.FormatFloat(bound, 'g', -1, 64), cumulative)
+	}
+	fmt.Fprintf(w, "%s_bucket{%sle=\"+Inf\"} %d\n", name, prefix, h.count)
+	if labels != "" {
+		labels = "{" + labels + "}"
+	}
+	fmt.Fprintf(w, "%s_sum%s %s\n", name, labels, strconv.FormatFloat(h.sum, 'g', -1, 64))
+	fmt.Fprintf(w, "%s_count%s %d\n", name, labels, h.count)
+}
+
+// HistogramVec is a set of histograms partitioned by the values of their labels.
+type HistogramVec struct {
+	labels     []string
+	buckets    []float64
+	mu         sync.RWMutex
+	histograms map[string]*Histogram
+	values     map[string][]string // Label values by key
+}
+
+// NewHistogramVec registers a histogram with one or more labels. buckets are upper bounds in increasing order.
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	v := &HistogramVec{labels: labels, buckets: buckets, histograms: make(map[string]*Histogram), values: make(map[string][]string)}
+	register(name, help, "histogram", v)
+	return v
+}
+
+// WithLabel returns the histogram for the label values, in the order the labels were declared, creating it on
+// first use.
+func (v *HistogramVec) WithLabel(values ...string) *Histogram {
+	key := strings.Join(values, "\xff")
+
+	v.mu.RLock()
+	h, ok := v.histograms[key]
+	v.mu.RUnlock()
+	if ok {
+		return h
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if h, ok = v.histograms[key]; !ok {
+		h = &Histogram{buckets: v.buckets, counts: make([]int64, len(v.buckets))}
+		v.histograms[key] = h
+		v.values[key] = values
+	}
+	return h
+}
+
+func (v *HistogramVec) write(w io.Writer, name string) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	for _, key := range sortedKeys(v.histograms) {
+		v.histograms[key].write(w, name, labelPairs(v.labels, v.values[key]))
+	}
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// labelPairs formats label names and values as name="value" pairs
+func labelPairs(labels, values []string) string {
+	pairs := make([]string, len(labels))
+	for i, label := range labels {
+		value := ""
+		if i < len(values) {
+			value = values[i]
+		}
+		pairs[i] = fmt.Sprintf("%s=\"%s\"", label, escapeLabel(value))
+	}
+	return strings.Join(pairs, ",")
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(value string) string {
+	return labelEscaper.Replace(value)
+}
+
+// Handler serves all registered metrics in the Prometheus text format.
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+		registryMu.RLock()
+		defer registryMu.RUnlock()
+
+		names := make([]string, 0, len(registry))
+		for name := range registry {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			f := registry[name]
+			fmt.Fprintf(w, "# HELP %s %s\n", f.name, f.help)
+			fmt.Fprintf(w, "# TYPE %s %s\n", f.name, f.kind)
+			f.collector.write(w, f.name)
+		}
+	})
+}
@@ -0,0 +1,24 @@
+// Package requestid carries the ID that correlates one request across the gateway's logs,
+// its error responses and the agent.
+package requestid
+
+import "context"
+
+// Header is the HTTP header carrying the request ID, in both directions
+const Header = "X-Request-ID"
+
+// MetadataKey is the gRPC metadata key the request ID is forwarded under, metadata keys are lowercase
+const MetadataKey = "x-request-id"
+
+type ctxKey struct{}
+
+// NewContext returns a copy of ctx carrying id
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// FromContext returns the request ID carried by ctx, or "" outside a request
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(ctxKey{}).(string)
+	return id
+}
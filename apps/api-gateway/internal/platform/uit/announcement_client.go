@@ -0,0 +1,38 @@
+package uit
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+)
+
+// AnnouncementClient reads the public announcement pages of UIT's sites, no cookie is needed
+type AnnouncementClient struct {
+	portal
+}
+
+func NewAnnouncementClient(cfg *config.UITConfig) *AnnouncementClient {
+	return &AnnouncementClient{portal: newPortal("announcements", "", cfg.TimeoutSeconds)}
+}
+
+// GetAnnouncements fetches and parses the notices listed on one announcement page
+func (c *AnnouncementClient) GetAnnouncements(ctx context.Context, pageURL string) ([]model.UITAnnouncement, error) {
+	parsed, err := url.Parse(pageURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid announcement page %q: %w", pageURL, err)
+	}
+
+	page, _, err := c.download(ctx, pageURL, "")
+	if err != nil {
+		return nil, err
+	}
+
+	announcements, err := parseAnnouncements(page, parsed)
+	if err != nil {
+		return nil, fmt.Errorf("parse announcements of %s: %w", pageURL, err)
+	}
+	return announcements, nil
+}
@@ -0,0 +1,129 @@
+package uit
+
+import (
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+	"golang.org/x/net/html"
+)
+
+// minAnnouncementTitle skips menu and "read more" links, notice titles are full sentences
+const minAnnouncementTitle = 15
+
+// maxAnnouncementSummary caps the teaser kept with a notice, in runes
+const maxAnnouncementSummary = 300
+
+// cohortPattern finds the cohorts a title targets, e.g. "khóa 18", "K17", "sinh viên khóa 2022"
+var cohortPattern = regexp.MustCompile(`(?i)\b(?:khóa|khoá|k)\s*(\d{2}|20\d{2})\b`)
+
+// parseAnnouncements extracts the notices listed on an announcement page. UIT's sites are Drupal, each notice is
+// a "views-row" of the listing view; headline links (h2-h4) are taken too for themes without views markup. Only
+// links to the same site are kept, and each row's date and teaser come from its text.
+func parseAnnouncements(page string, pageURL *url.URL) ([]model.UITAnnouncement, error) {
+	doc, err := html.Parse(strings.NewReader(page))
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var announcements []model.UITAnnouncement
+	for _, link := range findAll(doc, func(n *html.Node) bool { return isElement(n, "a") }) {
+		row := announcementRow(link)
+		if row == nil {
+			continue
+		}
+
+		target, ok := announcementURL(attr(link, "href"), pageURL)
+		if !ok || seen[target] {
+			continue
+		}
+
+		title := cleanText(textOf(link))
+		if utf8.RuneCountInString(title) < minAnnouncementTitle {
+			continue
+		}
+		seen[target] = true
+
+		announcement := model.UITAnnouncement{
+			Source:  pageURL.Host,
+			URL:     target,
+			Title:   title,
+			Summary: announcementSummary(cleanText(textOf(row)), title),
+			Cohorts: parseCohorts(title),
+		}
+		if m := datePattern.FindString(textOf(row)); m != "" {
+			if date, err := parseDate(m); err == nil {
+				announcement.PublishedAt = &date
+			}
+		}
+		announcements = append(announcements, announcement)
+	}
+	return announcements, nil
+}
+
+// announcementRow returns the listing row a link belongs to, or nil if it is not a notice link
+func announcementRow(link *html.Node) *html.Node {
+	for p := link.Parent; p != nil; p = p.Parent {
+		if p.Type != html.ElementNode {
+			continue
+		}
+		if hasClass(p, "views-row") || p.Data == "article" {
+			return p
+		}
+		if p.Data == "nav" || p.Data == "header" || p.Data == "footer" {
+			return nil
+		}
+	}
+	for _, tag := range []string{"h2", "h3", "h4"} {
+		if h := ancestor(link, tag); h != nil {
+			return h.Parent // The headline's wrapper usually holds the date and teaser too
+		}
+	}
+	return nil
+}
+
+// announcementURL resolves a link against the listing page, keeping only other pages of the same site
+func announcementURL(href string, pageURL *url.URL) (string, bool) {
+	ref, err := url.Parse(strings.TrimSpace(href))
+	if err != nil || href == "" {
+		return "", false
+	}
+	target := pageURL.ResolveReference(ref)
+	target.Fragment = ""
+	if target.Host != pageURL.Host || target.Path == pageURL.Path || target.Query().Has("page") {
+		return "", false
+	}
+	if target.Scheme != "http" && target.Scheme != "https" {
+		return "", false
+	}
+	return target.String(), true
+}
+
+// announcementSummary is the row's text without its title, cut at maxAnnouncementSummary runes
+func announcementSummary(rowText, title string) string {
+	summary := cleanText(strings.Replace(rowText, title, "", 1))
+	if utf8.RuneCountInString(summary) > maxAnnouncementSummary {
+		summary = string([]rune(summary)[:maxAnnouncementSummary]) + "…"
+	}
+	return summary
+}
+
+// parseCohorts returns the cohorts named in a title; an enrollment year such as "khóa 2022" is converted
+func parseCohorts(title string) []int {
+	var cohorts []int
+	for _, m := range cohortPattern.FindAllStringSubmatch(title, -1) {
+		n, _ := strconv.Atoi(m[1])
+		if n >= model.FirstEnrollmentYear {
+			n = model.StudentProfile{EnrollmentYear: n}.Cohort()
+		}
+		if n > 0 && !slices.Contains(cohorts, n) {
+			cohorts = append(cohorts, n)
+		}
+	}
+	return cohorts
+}
@@ -0,0 +1,289 @@
+package uit
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+)
+
+// calendarTimezone is the timezone of every UIT class and exam. Vietnam has no DST, so a fixed offset
+// works even where the tz database is missing.
+const calendarTimezone = "Asia/Ho_Chi_Minh"
+
+var calendarLocation = time.FixedZone(calendarTimezone, 7*60*60)
+
+// periodTimes are the start and end (hh:mm) of each UIT class period (tiết)
+var periodTimes = map[int][2]string{
+	1: {"07:30", "08:15"}, 2: {"08:15", "09:00"}, 3: {"09:00", "09:45"},
+	4: {"10:00", "10:45"}, 5: {"10:45", "11:30"},
+	6: {"13:00", "13:45"}, 7: {"13:45", "14:30"}, 8: {"14:30", "15:15"},
+	9: {"15:30", "16:15"}, 10: {"16:15", "17:00"},
+}
+
+// examShiftStarts are the start times (hh:mm) of the exam shifts (ca thi); an exam lasts examDuration
+var examShiftStarts = map[int]string{1: "07:30", 2: "09:30", 3: "13:30", 4: "15:30"}
+
+const examDuration = 90 * time.Minute
+
+// calendarRefresh hints subscribers how often to poll the feed
+const calendarRefresh = "PT6H"
+
+var (
+	dateRangePattern  = regexp.MustCompile(`(\d{1,2}/\d{1,2}/\d{2,4})\s*->\s*(\d{1,2}/\d{1,2}/\d{2,4})`)
+	examShiftPattern  = regexp.MustCompile(`(?i)Ca\s*(\d+)`)
+	examPeriodPattern = regexp.MustCompile(`\d+`)
+)
+
+// weekdays maps a DAA day of week to Go's weekday and the iCalendar BYDAY code
+var weekdays = map[string]struct {
+	day   time.Weekday
+	byDay string
+}{
+	"2": {time.Monday, "MO"}, "3": {time.Tuesday, "TU"}, "4": {time.Wednesday, "WE"},
+	"5": {time.Thursday, "TH"}, "6": {time.Friday, "FR"}, "7": {time.Saturday, "SA"},
+	"CN": {time.Sunday, "SU"},
+}
+
+// BuildCalendar renders a timetable and exam schedule as an iCalendar (RFC 5545) feed. Each class with a fixed
+// slot becomes a weekly recurring event over its date range; each exam becomes a single event, all-day when
+// its shift is unknown. Classes without a fixed slot are left out. exams may be nil.
+func BuildCalendar(userID string, schedule *model.UITSchedule, exams *model.UITExamSchedule, now time.Time) []byte {
+	var w calendarWriter
+	w.line("BEGIN:VCALENDAR")
+	w.line("VERSION:2.0")
+	w.line("PRODID:-//UIT AI Assistant//Schedule//VI")
+	w.line("CALSCALE:GREGORIAN")
+	w.line("METHOD:PUBLISH")
+	w.line("X-WR-CALNAME:" + escapeText(calendarName(schedule)))
+	w.line("X-WR-TIMEZONE:" + calendarTimezone)
+	w.line("REFRESH-INTERVAL;VALUE=DURATION:" + calendarRefresh)
+	w.line("X-PUBLISHED-TTL:" + calendarRefresh)
+	w.line("BEGIN:VTIMEZONE")
+	w.line("TZID:" + calendarTimezone)
+	w.line("BEGIN:STANDARD")
+	w.line("DTSTART:19700101T000000")
+	w.line("TZOFFSETFROM:+0700")
+	w.line("TZOFFSETTO:+0700")
+	w.line("TZNAME:ICT")
+	w.line("END:STANDARD")
+	w.line("END:VTIMEZONE")
+
+	stamp := now.UTC().Format("20060102T150405Z")
+
+	if schedule != nil {
+		for _, class := range schedule.Classes {
+			writeClassEvent(&w, userID, class, stamp)
+		}
+	}
+	if exams != nil {
+		for _, exam := range exams.Exams {
+			writeExamEvent(&w, userID, exam, stamp)
+		}
+	}
+
+	w.line("END:VCALENDAR")
+	return []byte(w.String())
+}
+
+func calendarName(schedule *model.UITSchedule) string {
+	if schedule == nil || schedule.Semester == "" {
+		return "Lịch học UIT"
+	}
+	return "Lịch học UIT - " + schedule.Semester
+}
+
+func writeClassEvent(w *calendarWriter, userID string, class model.UITScheduleClass, stamp string) {
+	weekday, ok := weekdays[class.DayOfWeek]
+	if !ok {
+		return
+	}
+	startTime, endTime, ok := periodRange(class.Period)
+	if !ok {
+		return
+	}
+	m := dateRangePattern.FindStringSubmatch(class.DateRange)
+	if m == nil {
+		return
+	}
+	from, err1 := parseDate(m[1])
+	until, err2 := parseDate(m[2])
+	if err1 != nil || err2 != nil || until.Before(from) {
+		return
+	}
+
+	// The range starts on the first day of the semester, not necessarily on the class's weekday
+	first := from.AddDate(0, 0, (int(weekday.day)-int(from.Weekday())+7)%7)
+	if first.After(until) {
+		return
+	}
+	lastInstant := until.Add(24*time.Hour - time.Second)
+
+	summary := class.Subject
+	if label := classTypeLabel(class.Type); label != "" {
+		summary += " (" + label + ")"
+	}
+
+	w.line("BEGIN:VEVENT")
+	w.line(fmt.Sprintf("UID:class-%s-%s-%s@uit-ai-assistant", userID, sanitizeUID(class.SubjectCode), sanitizeUID(class.Type)))
+	w.line("DTSTAMP:" + stamp)
+	w.line("DTSTART;TZID=" + calendarTimezone + ":" + first.Format("20060102") + "T" + compactTime(startTime))
+	w.line("DTEND;TZID=" + calendarTimezone + ":" + first.Format("20060102") + "T" + compactTime(endTime))
+	w.line("RRULE:FREQ=WEEKLY;BYDAY=" + weekday.byDay + ";UNTIL=" + lastInstant.UTC().Format("20060102T150405Z"))
+	w.line("SUMMARY:" + escapeText(summary))
+	if class.Room != "" {
+		w.line("LOCATION:" + escapeText(class.Room))
+	}
+	w.line("DESCRIPTION:" + escapeText(fmt.Sprintf("Mã lớp: %s\nTiết: %s", class.SubjectCode, class.Period)))
+	w.line("END:VEVENT")
+}
+
+func writeExamEvent(w *calendarWriter, userID string, exam model.UITExam, stamp string) {
+	date, ok := ExamDate(exam)
+	if !ok {
+		return
+	}
+
+	code := exam.ClassCode
+	if code == "" {
+		code = exam.CourseCode
+	}
+
+	w.line("BEGIN:VEVENT")
+	w.line(fmt.Sprintf("UID:exam-%s-%s-%s@uit-ai-assistant", userID, sanitizeUID(code), date.Format("20060102")))
+	w.line("DTSTAMP:" + stamp)
+	if start, end, ok := examTimes(date, exam.ExamPeriod); ok {
+		w.line("DTSTART;TZID=" + calendarTimezone + ":" + start.Format("20060102T150405"))
+		w.line("DTEND;TZID=" + calendarTimezone + ":" + end.Format("20060102T150405"))
+	} else {
+		w.line("DTSTART;VALUE=DATE:" + date.Format("20060102"))
+		w.line("DTEND;VALUE=DATE:" + date.AddDate(0, 0, 1).Format("20060102"))
+	}
+	w.line("SUMMARY:" + escapeText("Thi "+code))
+	if exam.Room != "" {
+		w.line("LOCATION:" + escapeText(exam.Room))
+	}
+	var description []string
+	if exam.ExamPeriod != "" {
+		description = append(description, "Ca/Tiết thi: "+exam.ExamPeriod)
+	}
+	if exam.ExamForm != "" {
+		description = append(description, "Hình thức thi: "+exam.ExamForm)
+	}
+	if len(description) > 0 {
+		w.line("DESCRIPTION:" + escapeText(strings.Join(description, "\n")))
+	}
+	w.line("END:VEVENT")
+}
+
+// ExamDate returns the day of an exam at midnight in UIT's timezone, ok = false if it has no valid date
+func ExamDate(exam model.UITExam) (time.Time, bool) {
+	date, err := parseDate(exam.ExamDate)
+	return date, err == nil
+}
+
+// periodRange returns the start and end time of a period span such as "1-4" or "6"
+func periodRange(period string) (start, end string, ok bool) {
+	first, last, ok := periodSpan(period)
+	if !ok {
+		return "", "", false
+	}
+	startTimes, ok1 := periodTimes[first]
+	endTimes, ok2 := periodTimes[last]
+	if !ok1 || !ok2 {
+		return "", "", false
+	}
+	return startTimes[0], endTimes[1], true
+}
+
+// examTimes resolves the start and end of an exam from its shift ("Ca 2") or periods ("Tiết 1-3", "Tiết 1,2,3")
+func examTimes(date time.Time, period string) (start, end time.Time, ok bool) {
+	at := func(hhmm string) time.Time {
+		t, _ := time.ParseInLocation("15:04", hhmm, calendarLocation)
+		return date.Add(time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute)
+	}
+
+	if m := examShiftPattern.FindStringSubmatch(period); m != nil {
+		shift, _ := strconv.Atoi(m[1])
+		if hhmm, ok := examShiftStarts[shift]; ok {
+			start = at(hhmm)
+			return start, start.Add(examDuration), true
+		}
+		return start, end, false
+	}
+
+	periods := examPeriodPattern.FindAllString(period, -1)
+	if len(periods) == 0 {
+		return start, end, false
+	}
+	from, to, ok := periodRange(periods[0] + "-" + periods[len(periods)-1])
+	if !ok {
+		return start, end, false
+	}
+	return at(from), at(to), true
+}
+
+// parseDate parses a DAA date, either dd/mm/yy or dd/mm/yyyy
+func parseDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	layout := "2/1/2006"
+	if parts := strings.Split(s, "/"); len(parts) == 3 && len(parts[2]) == 2 {
+		layout = "2/1/06"
+	}
+	return time.ParseInLocation(layout, s, calendarLocation)
+}
+
+func compactTime(hhmm string) string {
+	return strings.ReplaceAll(hhmm, ":", "") + "00"
+}
+
+func classTypeLabel(classType string) string {
+	switch classType {
+	case "lt":
+		return "Lý thuyết"
+	case "ht1", "ht2":
+		return "Thực hành"
+	}
+	return ""
+}
+
+// sanitizeUID keeps the characters that are safe in an event UID
+func sanitizeUID(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '-' {
+			return r
+		}
+		return '_'
+	}, s)
+}
+
+// escapeText escapes a TEXT property value
+func escapeText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(s)
+}
+
+// calendarWriter writes content lines, folded at 75 octets and terminated by CRLF as RFC 5545 requires
+type calendarWriter struct {
+	strings.Builder
+}
+
+func (w *calendarWriter) line(s string) {
+	limit := 75
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(s[cut]) { // Never split a UTF-8 sequence
+			cut--
+		}
+		w.WriteString(s[:cut] + "\r\n ")
+		s = s[cut:]
+		limit = 74 // Continuation lines start with a space
+	}
+	w.WriteString(s + "\r\n")
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
@@ -0,0 +1,81 @@
+package uit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxPageSize caps how much of a portal page is read
+const maxPageSize = 5 << 20
+
+// ErrSessionExpired is returned when a portal answers with its login page, i.e. the synced cookie is no longer valid
+var ErrSessionExpired = errors.New("uit portal session expired")
+
+// portal fetches pages of one UIT portal on behalf of a user, authenticated with the cookie the extension synced for them
+type portal struct {
+	name       string
+	httpClient *http.Client
+	baseURL    string
+}
+
+func newPortal(name, baseURL string, timeoutSeconds int) portal {
+	return portal{
+		name:       name,
+		httpClient: &http.Client{Timeout: time.Duration(timeoutSeconds) * time.Second},
+		baseURL:    strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// fetch returns the HTML of a portal page, or ErrSessionExpired if the portal sent its login form instead
+func (p portal) fetch(ctx context.Context, path, cookie string) (string, error) {
+	page, finalPath, err := p.download(ctx, p.baseURL+path, cookie)
+	if err != nil {
+		return "", err
+	}
+	if isLoginPage(finalPath, page) {
+		return "", ErrSessionExpired
+	}
+	return page, nil
+}
+
+// download returns the HTML at url and the path it was finally served from, after redirects. cookie may be empty
+// for public pages.
+func (p portal) download(ctx context.Context, url, cookie string) (page, finalPath string, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", "", err
+	}
+	if cookie != "" {
+		req.Header.Set("Cookie", cookie)
+	}
+	req.Header.Set("Accept", "text/html")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return "", "", fmt.Errorf("fetch %s %s: %w", p.name, url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", "", fmt.Errorf("fetch %s %s: unexpected status %d", p.name, url, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxPageSize))
+	if err != nil {
+		return "", "", fmt.Errorf("read %s %s: %w", p.name, url, err)
+	}
+	return string(body), resp.Request.URL.Path, nil
+}
+
+// isLoginPage checks if the portal redirected to or rendered its login form
+func isLoginPage(path, page string) bool {
+	lower := strings.ToLower(page)
+	return strings.Contains(strings.ToLower(path), "login") ||
+		strings.Contains(lower, "edit-name") || // DAA's Drupal login form
+		strings.Contains(lower, `type="password"`)
+}
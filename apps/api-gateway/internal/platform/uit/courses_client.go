@@ -0,0 +1,38 @@
+package uit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+)
+
+// CoursesClient reads the courses portal with the "courses" cookie synced by the extension
+type CoursesClient struct {
+	portal
+	openClassesPath string
+}
+
+func NewCoursesClient(cfg *config.UITConfig) *CoursesClient {
+	return &CoursesClient{
+		portal:          newPortal("courses", cfg.CoursesBaseURL, cfg.TimeoutSeconds),
+		openClassesPath: cfg.OpenClassesPath,
+	}
+}
+
+// GetOpenClasses fetches and parses the classes open for course registration
+func (c *CoursesClient) GetOpenClasses(ctx context.Context, cookie string) (*model.UITOpenClasses, error) {
+	page, err := c.fetch(ctx, c.openClassesPath, cookie)
+	if err != nil {
+		return nil, err
+	}
+
+	classes, err := parseOpenClasses(page)
+	if err != nil {
+		return nil, fmt.Errorf("parse open classes: %w", err)
+	}
+	classes.FetchedAt = time.Now()
+	return classes, nil
+}
@@ -0,0 +1,79 @@
+package uit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+)
+
+// DAAClient reads the DAA student portal (daa.uit.edu.vn)
+type DAAClient struct {
+	portal
+}
+
+func NewDAAClient(cfg *config.UITConfig) *DAAClient {
+	return &DAAClient{portal: newPortal("daa", cfg.DAABaseURL, cfg.TimeoutSeconds)}
+}
+
+// GetSchedule fetches and parses the student's weekly timetable
+func (c *DAAClient) GetSchedule(ctx context.Context, cookie string) (*model.UITSchedule, error) {
+	page, err := c.fetch(ctx, "/sinhvien/tkb", cookie)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule, err := parseSchedule(page)
+	if err != nil {
+		return nil, fmt.Errorf("parse daa schedule: %w", err)
+	}
+	schedule.FetchedAt = time.Now()
+	return schedule, nil
+}
+
+// GetExams fetches and parses the student's exam schedule
+func (c *DAAClient) GetExams(ctx context.Context, cookie string) (*model.UITExamSchedule, error) {
+	page, err := c.fetch(ctx, "/sinhvien/lichhoc/lichthi", cookie)
+	if err != nil {
+		return nil, err
+	}
+
+	exams, err := parseExams(page)
+	if err != nil {
+		return nil, fmt.Errorf("parse daa exams: %w", err)
+	}
+	exams.FetchedAt = time.Now()
+	return exams, nil
+}
+
+// GetGrades fetches and parses the student's grade table
+func (c *DAAClient) GetGrades(ctx context.Context, cookie string) (*model.UITGrades, error) {
+	page, err := c.fetch(ctx, "/sinhvien/kqhoctap", cookie)
+	if err != nil {
+		return nil, err
+	}
+
+	grades, err := parseGrades(page)
+	if err != nil {
+		return nil, fmt.Errorf("parse daa grades: %w", err)
+	}
+	grades.FetchedAt = time.Now()
+	return grades, nil
+}
+
+// GetTuition fetches and parses the student's tuition fee status
+func (c *DAAClient) GetTuition(ctx context.Context, cookie string) (*model.UITTuition, error) {
+	page, err := c.fetch(ctx, "/sinhvien/hocphi", cookie)
+	if err != nil {
+		return nil, err
+	}
+
+	tuition, err := parseTuition(page)
+	if err != nil {
+		return nil, fmt.Errorf("parse daa tuition: %w", err)
+	}
+	tuition.FetchedAt = time.Now()
+	return tuition, nil
+}
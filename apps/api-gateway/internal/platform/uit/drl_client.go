@@ -0,0 +1,37 @@
+package uit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+)
+
+// drlScorePath is the DRL page listing the student's score of every term
+const drlScorePath = "/sinhvien/ketqua"
+
+// DRLClient reads the training score portal (drl.uit.edu.vn)
+type DRLClient struct {
+	portal
+}
+
+func NewDRLClient(cfg *config.UITConfig) *DRLClient {
+	return &DRLClient{portal: newPortal("drl", cfg.DRLBaseURL, cfg.TimeoutSeconds)}
+}
+
+// GetTrainingScore fetches and parses the student's training score of every term
+func (c *DRLClient) GetTrainingScore(ctx context.Context, cookie string) (*model.UITTrainingScore, error) {
+	page, err := c.fetch(ctx, drlScorePath, cookie)
+	if err != nil {
+		return nil, err
+	}
+
+	score, err := parseTrainingScore(page)
+	if err != nil {
+		return nil, fmt.Errorf("parse drl score: %w", err)
+	}
+	score.FetchedAt = time.Now()
+	return score, nil
+}
@@ -0,0 +1,69 @@
+package uit
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+	"golang.org/x/net/html"
+)
+
+// parseExams extracts the exams of the DAA exam schedule page. The exam table is the one whose header
+// has "Mã MH"; its columns are | STT | Mã MH | Mã lớp | Ca/Tiết thi | Thứ thi | Ngày thi | Phòng thi | Hình thức thi |.
+// A page without that table has no exam scheduled yet.
+func parseExams(page string) (*model.UITExamSchedule, error) {
+	doc, err := html.Parse(strings.NewReader(page))
+	if err != nil {
+		return nil, err
+	}
+
+	exams := &model.UITExamSchedule{
+		Semester: extractSemester(textOf(doc)),
+		Exams:    []model.UITExam{},
+	}
+
+	for _, table := range findAll(doc, func(n *html.Node) bool { return isElement(n, "table") }) {
+		rows := findAll(table, func(n *html.Node) bool { return isElement(n, "tr") })
+		if len(rows) == 0 || !strings.Contains(textOf(rows[0]), "Mã MH") {
+			continue
+		}
+
+		for _, row := range rows[1:] {
+			if exam, ok := parseExamRow(childElements(row, "td")); ok {
+				exams.Exams = append(exams.Exams, exam)
+			}
+		}
+		break
+	}
+
+	return exams, nil
+}
+
+func parseExamRow(cells []*html.Node) (model.UITExam, bool) {
+	var exam model.UITExam
+
+	cell := func(i int) string {
+		if i >= len(cells) {
+			return ""
+		}
+		if text := cleanText(textOf(cells[i])); text != "-" {
+			return text
+		}
+		return ""
+	}
+
+	// Rows without a number are group headers or notes
+	if _, err := strconv.Atoi(cell(0)); err != nil {
+		return exam, false
+	}
+
+	exam.CourseCode = cell(1)
+	exam.ClassCode = cell(2)
+	exam.ExamPeriod = cell(3)
+	exam.DayOfWeek = cell(4)
+	exam.ExamDate = cell(5)
+	exam.Room = cell(6)
+	exam.ExamForm = cell(7)
+
+	return exam, exam.CourseCode != "" || exam.ExamDate != ""
+}
@@ -0,0 +1,96 @@
+package uit
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+	"golang.org/x/net/html"
+)
+
+// parseGrades extracts the courses of the DAA grade page. The grade table is the one whose header has
+// "Mã HP" and "Tên học phần"; its columns are
+// | STT | Mã HP | Tên học phần | Tín chỉ | Điểm QT | Điểm GK | Điểm TH | Điểm CK | Điểm HP | Ghi chú |,
+// and each semester starts with a single-cell "Học kỳ ..." row.
+func parseGrades(page string) (*model.UITGrades, error) {
+	doc, err := html.Parse(strings.NewReader(page))
+	if err != nil {
+		return nil, err
+	}
+
+	grades := &model.UITGrades{Courses: []model.UITCourseGrade{}}
+
+	for _, table := range findAll(doc, func(n *html.Node) bool { return isElement(n, "table") }) {
+		rows := findAll(table, func(n *html.Node) bool { return isElement(n, "tr") })
+		if len(rows) == 0 {
+			continue
+		}
+		header := textOf(rows[0])
+		if !strings.Contains(header, "Mã HP") || !strings.Contains(header, "Tên học phần") {
+			continue
+		}
+
+		semester := ""
+		for _, row := range rows[1:] {
+			cells := childElements(row, "td", "th")
+			if len(cells) == 1 {
+				if text := cleanText(textOf(cells[0])); strings.Contains(text, "Học kỳ") && !strings.Contains(text, "Trung bình") {
+					semester = extractSemester(text)
+				}
+				continue
+			}
+			if course, ok := parseGradeRow(cells); ok {
+				course.Semester = semester
+				grades.Courses = append(grades.Courses, course)
+			}
+		}
+		break
+	}
+
+	return grades, nil
+}
+
+func parseGradeRow(cells []*html.Node) (model.UITCourseGrade, bool) {
+	var course model.UITCourseGrade
+
+	cell := func(i int) string {
+		if i >= len(cells) {
+			return ""
+		}
+		return cleanText(textOf(cells[i]))
+	}
+
+	// Rows without a number are semester averages or notes
+	if _, err := strconv.Atoi(cell(0)); err != nil {
+		return course, false
+	}
+
+	course.CourseCode = cell(1)
+	course.CourseName = cell(2)
+	course.Credits, _ = strconv.Atoi(cell(3))
+	if course.CourseCode == "" {
+		return course, false
+	}
+
+	if strings.Contains(strings.ToLower(cell(8)), "miễn") {
+		course.IsExempt = true
+		return course, true
+	}
+
+	course.AttendanceScore = parseScore(cell(4))
+	course.MidtermScore = parseScore(cell(5))
+	course.PracticeScore = parseScore(cell(6))
+	course.FinalScore = parseScore(cell(7))
+	course.AverageScore = parseScore(cell(8))
+
+	return course, true
+}
+
+// parseScore parses a 10-point score, nil when it is not posted
+func parseScore(text string) *float64 {
+	score, err := strconv.ParseFloat(strings.Replace(text, ",", ".", 1), 64)
+	if err != nil || score < 0 || score > 10 {
+		return nil
+	}
+	return &score
+}
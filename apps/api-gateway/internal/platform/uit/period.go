@@ -0,0 +1,31 @@
+package uit
+
+import (
+	"strconv"
+	"strings"
+)
+
+// periodSpan parses a period span such as "1-4" or "6" into its first and last period
+func periodSpan(period string) (first, last int, ok bool) {
+	from, to, found := strings.Cut(period, "-")
+	if !found {
+		to = from
+	}
+	first, err1 := strconv.Atoi(strings.TrimSpace(from))
+	last, err2 := strconv.Atoi(strings.TrimSpace(to))
+	if err1 != nil || err2 != nil || last < first {
+		return 0, 0, false
+	}
+	return first, last, true
+}
+
+// SlotsOverlap checks if two weekly slots share a period on the same day. Slots without a fixed day or
+// period never overlap.
+func SlotsOverlap(dayA, periodA, dayB, periodB string) bool {
+	if dayA == "" || dayA != dayB {
+		return false
+	}
+	firstA, lastA, okA := periodSpan(periodA)
+	firstB, lastB, okB := periodSpan(periodB)
+	return okA && okB && firstA <= lastB && firstB <= lastA
+}
@@ -0,0 +1,144 @@
+package uit
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+	"golang.org/x/net/html"
+)
+
+// errClassTableNotFound is returned when the page has no table of open classes
+var errClassTableNotFound = errors.New("open class table not found")
+
+// openClassColumns are the column indexes of the open class table, -1 when the table has no such column
+type openClassColumns struct {
+	code, subject, credits, day, period, room, lecturer, capacity, registered int
+}
+
+// parseOpenClasses extracts the classes of the registration page. Columns are found by their header
+// ("Mã lớp", "Tên môn học", "Tín chỉ", "Thứ", "Tiết", "Phòng", "Giảng viên", "Sĩ số", "Đã đăng ký") so
+// their order does not matter.
+func parseOpenClasses(page string) (*model.UITOpenClasses, error) {
+	doc, err := html.Parse(strings.NewReader(page))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, table := range findAll(doc, func(n *html.Node) bool { return isElement(n, "table") }) {
+		rows := findAll(table, func(n *html.Node) bool { return isElement(n, "tr") })
+		if len(rows) == 0 {
+			continue
+		}
+
+		columns, ok := classColumns(childElements(rows[0], "th", "td"))
+		if !ok {
+			continue
+		}
+
+		classes := &model.UITOpenClasses{Classes: []model.UITOpenClass{}}
+		for _, row := range rows[1:] {
+			if class, ok := parseOpenClassRow(childElements(row, "td", "th"), columns); ok {
+				classes.Classes = append(classes.Classes, class)
+			}
+		}
+		return classes, nil
+	}
+
+	return nil, errClassTableNotFound
+}
+
+// classColumns maps the header cells of a table, ok only if it has class code and capacity columns
+func classColumns(header []*html.Node) (openClassColumns, bool) {
+	columns := openClassColumns{code: -1, subject: -1, credits: -1, day: -1, period: -1, room: -1, lecturer: -1, capacity: -1, registered: -1}
+	for i, cell := range header {
+		text := strings.ToLower(cleanText(textOf(cell)))
+		switch {
+		case strings.Contains(text, "mã lớp") && columns.code < 0:
+			columns.code = i
+		case (strings.Contains(text, "tên môn") || strings.Contains(text, "tên học phần")) && columns.subject < 0:
+			columns.subject = i
+		case strings.Contains(text, "tín chỉ") && columns.credits < 0:
+			columns.credits = i
+		case strings.Contains(text, "thứ") && columns.day < 0:
+			columns.day = i
+		case strings.Contains(text, "tiết") && columns.period < 0:
+			columns.period = i
+		case strings.Contains(text, "phòng") && columns.room < 0:
+			columns.room = i
+		case strings.Contains(text, "giảng viên") && columns.lecturer < 0:
+			columns.lecturer = i
+		case (strings.Contains(text, "đã đăng ký") || strings.Contains(text, "đã đk")) && columns.registered < 0:
+			columns.registered = i
+		case strings.Contains(text, "sĩ số") && columns.capacity < 0:
+			columns.capacity = i
+		}
+	}
+	return columns, columns.code >= 0 && columns.capacity >= 0
+}
+
+func parseOpenClassRow(cells []*html.Node, columns openClassColumns) (model.UITOpenClass, bool) {
+	var class model.UITOpenClass
+
+	cell := func(i int) string {
+		if i < 0 || i >= len(cells) {
+			return ""
+		}
+		return cleanText(textOf(cells[i]))
+	}
+	number := func(i int) int {
+		n, _ := strconv.Atoi(digitsPattern.FindString(cell(i)))
+		return n
+	}
+
+	class.ClassCode = cell(columns.code)
+	if class.ClassCode == "" {
+		return class, false
+	}
+	class.Subject = cell(columns.subject)
+	class.Credits = number(columns.credits)
+	class.Room = cell(columns.room)
+	class.Lecturer = cell(columns.lecturer)
+
+	if m := dayPattern.FindStringSubmatch("Thứ " + strings.TrimPrefix(cell(columns.day), "Thứ ")); m != nil {
+		class.DayOfWeek = strings.ToUpper(m[1])
+	}
+	class.Period = normalizePeriod(cell(columns.period))
+
+	class.Capacity = number(columns.capacity)
+	class.Registered = number(columns.registered)
+	class.Remaining = max(class.Capacity-class.Registered, 0)
+
+	return class, true
+}
+
+// normalizePeriod turns a period listing such as "123" or "1,2,3" or "1-3" into a span "1-3". Periods 10
+// and above are written with two digits only when separated.
+func normalizePeriod(text string) string {
+	var periods []int
+	if strings.ContainsAny(text, ",-") || strings.Contains(text, " ") {
+		for _, p := range digitsPattern.FindAllString(text, -1) {
+			n, _ := strconv.Atoi(p)
+			periods = append(periods, n)
+		}
+	} else {
+		for _, r := range digitsPattern.FindString(text) {
+			n := int(r - '0')
+			if n == 0 && len(periods) > 0 && periods[len(periods)-1] == 1 {
+				periods[len(periods)-1] = 10 // "…910" lists periods 9 and 10
+				continue
+			}
+			periods = append(periods, n)
+		}
+	}
+	if len(periods) == 0 {
+		return ""
+	}
+
+	first, last := periods[0], periods[len(periods)-1]
+	if first == last {
+		return strconv.Itoa(first)
+	}
+	return strconv.Itoa(first) + "-" + strconv.Itoa(last)
+}
@@ -0,0 +1,228 @@
+package uit
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+	"golang.org/x/net/html"
+)
+
+var (
+	semesterPattern      = regexp.MustCompile(`(?i)(?:HK|Học\s*kỳ)\s*(\d+)\s*-?\s*(?:Năm\s*học\s*|Năm\s*)?(\d{4})\s*-\s*(\d{4})`)
+	specificDatesPattern = regexp.MustCompile(`Tiết\s+\d+(?:,\d+)*\s+ngày\s+\d{4}-\d{2}-\d{2}`)
+	periodPattern        = regexp.MustCompile(`Tiết\s+(\d+)`)
+	dayPattern           = regexp.MustCompile(`(?i)Thứ\s+(\d+|CN)`)
+	digitsPattern        = regexp.MustCompile(`\d+`)
+)
+
+// scheduleCardTypes are the card classes DAA uses for lectures and practice groups
+var scheduleCardTypes = []string{"lt", "ht1", "ht2"}
+
+// parseSchedule extracts the classes of the DAA timetable page. Each class is a "tkb-card" inside the
+// timetable; its day comes from the column header and its periods from the row and rowspan of its cell.
+// Classes held on specific dates (e.g. projects) have no fixed day or period.
+func parseSchedule(page string) (*model.UITSchedule, error) {
+	doc, err := html.Parse(strings.NewReader(page))
+	if err != nil {
+		return nil, err
+	}
+
+	schedule := &model.UITSchedule{
+		Semester: extractSemester(textOf(doc)),
+		Classes:  []model.UITScheduleClass{},
+	}
+
+	seen := make(map[string]bool)
+	for _, card := range findAll(doc, func(n *html.Node) bool { return isElement(n, "div") && hasClass(n, "tkb-card") }) {
+		class, ok := parseScheduleCard(card)
+		if !ok {
+			continue
+		}
+
+		// Practice groups show up once per slot, keep the first
+		key := class.SubjectCode + "_" + class.Type
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		schedule.Classes = append(schedule.Classes, class)
+	}
+
+	return schedule, nil
+}
+
+func parseScheduleCard(card *html.Node) (model.UITScheduleClass, bool) {
+	var class model.UITScheduleClass
+
+	for _, t := range scheduleCardTypes {
+		if hasClass(card, t) {
+			class.Type = t
+			break
+		}
+	}
+
+	titles := findAll(card, func(n *html.Node) bool { return isElement(n, "div") && hasClass(n, "title") })
+	if len(titles) < 2 {
+		return class, false
+	}
+	class.SubjectCode = cleanText(textOf(titles[0]))
+	class.Subject = cleanText(textOf(titles[1]))
+	if class.SubjectCode == "" || class.Subject == "" {
+		return class, false
+	}
+
+	specificDates := strings.Contains(class.Subject, "Đồ án") // Projects never have a fixed slot
+	subs := findAll(card, func(n *html.Node) bool { return isElement(n, "div") && hasClass(n, "sub") })
+	if len(subs) > 0 {
+		class.DateRange = cleanText(textOf(subs[0]))
+		schedulesText := class.DateRange
+		if len(subs) > 1 {
+			schedulesText = cleanText(textOf(subs[1]))
+		}
+		if specificDatesPattern.MatchString(schedulesText) {
+			specificDates = true
+		}
+	}
+
+	for _, badge := range findAll(card, func(n *html.Node) bool { return isElement(n, "span") && hasClass(n, "badge") }) {
+		text := strings.TrimSpace(textOf(badge))
+		switch {
+		case hasClass(badge, "room"):
+			class.Room = strings.TrimSpace(strings.TrimPrefix(text, "P "))
+		case hasClass(badge, "size"):
+			class.ClassSize = digitsPattern.FindString(text)
+		}
+	}
+
+	if !specificDates {
+		class.DayOfWeek, class.Period = cardSlot(card)
+	}
+
+	return class, true
+}
+
+// cardSlot infers the day and periods of a card from the timetable cell holding it
+func cardSlot(card *html.Node) (day, period string) {
+	cell := ancestor(card, "td")
+	if cell == nil {
+		return "", ""
+	}
+	row := ancestor(cell, "tr")
+	if row == nil {
+		return "", ""
+	}
+
+	cells := childElements(row, "td", "th")
+	if len(cells) > 0 {
+		if m := periodPattern.FindStringSubmatch(textOf(cells[0])); m != nil {
+			start, _ := strconv.Atoi(m[1])
+			period = strconv.Itoa(start)
+			if rowspan, _ := strconv.Atoi(attr(cell, "rowspan")); rowspan > 1 {
+				period = fmt.Sprintf("%d-%d", start, start+rowspan-1)
+			}
+		}
+	}
+
+	table := ancestor(row, "table")
+	if table == nil {
+		return "", period
+	}
+	heads := findAll(table, func(n *html.Node) bool { return isElement(n, "thead") })
+	if len(heads) == 0 {
+		return "", period
+	}
+	headers := findAll(heads[0], func(n *html.Node) bool { return isElement(n, "th") })
+
+	index := slices.Index(cells, cell)
+	if index >= 0 && index < len(headers) {
+		if m := dayPattern.FindStringSubmatch(textOf(headers[index])); m != nil {
+			day = strings.ToUpper(m[1])
+		}
+	}
+	return day, period
+}
+
+// extractSemester normalizes the semester title of the page, e.g. "HK1 năm học 2025-2026"
+func extractSemester(text string) string {
+	m := semesterPattern.FindStringSubmatch(text)
+	if m == nil {
+		return ""
+	}
+	return fmt.Sprintf("HK%s năm học %s-%s", m[1], m[2], m[3])
+}
+
+// --- HTML helpers ---
+
+func isElement(n *html.Node, tag string) bool {
+	return n.Type == html.ElementNode && n.Data == tag
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func hasClass(n *html.Node, class string) bool {
+	return slices.Contains(strings.Fields(attr(n, "class")), class)
+}
+
+// findAll returns the descendants of n matching match, in document order
+func findAll(n *html.Node, match func(*html.Node) bool) []*html.Node {
+	var found []*html.Node
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if match(c) {
+			found = append(found, c)
+		}
+		found = append(found, findAll(c, match)...)
+	}
+	return found
+}
+
+func ancestor(n *html.Node, tag string) *html.Node {
+	for p := n.Parent; p != nil; p = p.Parent {
+		if isElement(p, tag) {
+			return p
+		}
+	}
+	return nil
+}
+
+func childElements(n *html.Node, tags ...string) []*html.Node {
+	var children []*html.Node
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && slices.Contains(tags, c.Data) {
+			children = append(children, c)
+		}
+	}
+	return children
+}
+
+func textOf(n *html.Node) string {
+	var sb strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			sb.WriteString(n.Data)
+			sb.WriteString(" ")
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return sb.String()
+}
+
+// cleanText trims text and collapses its whitespace
+func cleanText(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,125 @@
+package uit
+
+import (
+	"errors"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+	"golang.org/x/net/html"
+)
+
+var (
+	termPattern         = regexp.MustCompile(`(?i)(?:HK|Học\s*kỳ)\s*(\d+)`)
+	academicYearPattern = regexp.MustCompile(`(\d{4})\s*-\s*(\d{4})`)
+)
+
+// errScoreTableNotFound is returned when the page has no table of term scores
+var errScoreTableNotFound = errors.New("training score table not found")
+
+// trainingScoreColumns are the column indexes of the score table, -1 when the table has no such column
+type trainingScoreColumns struct {
+	semester, year, score, rank int
+}
+
+// parseTrainingScore extracts the term scores from the DRL results page. Columns are found by their
+// header ("Học kỳ", "Năm học", "Điểm", "Xếp loại") so their order does not matter; the academic year
+// may also be part of the semester cell.
+func parseTrainingScore(page string) (*model.UITTrainingScore, error) {
+	doc, err := html.Parse(strings.NewReader(page))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, table := range findAll(doc, func(n *html.Node) bool { return isElement(n, "table") }) {
+		rows := findAll(table, func(n *html.Node) bool { return isElement(n, "tr") })
+		if len(rows) == 0 {
+			continue
+		}
+
+		columns, ok := scoreColumns(childElements(rows[0], "th", "td"))
+		if !ok {
+			continue
+		}
+
+		result := &model.UITTrainingScore{Terms: []model.UITTrainingScoreTerm{}}
+		for _, row := range rows[1:] {
+			if term, ok := parseTermRow(childElements(row, "td", "th"), columns); ok {
+				result.Terms = append(result.Terms, term)
+			}
+		}
+		result.Average = averageScore(result.Terms)
+		return result, nil
+	}
+
+	return nil, errScoreTableNotFound
+}
+
+// scoreColumns maps the header cells of a table, ok only if it has semester and score columns
+func scoreColumns(header []*html.Node) (trainingScoreColumns, bool) {
+	columns := trainingScoreColumns{semester: -1, year: -1, score: -1, rank: -1}
+	for i, cell := range header {
+		text := strings.ToLower(cleanText(textOf(cell)))
+		switch {
+		case strings.Contains(text, "học kỳ") && columns.semester < 0:
+			columns.semester = i
+		case strings.Contains(text, "năm học") && columns.year < 0:
+			columns.year = i
+		case strings.Contains(text, "xếp loại") && columns.rank < 0:
+			columns.rank = i
+		case strings.Contains(text, "điểm") && columns.score < 0:
+			columns.score = i
+		}
+	}
+	return columns, columns.semester >= 0 && columns.score >= 0
+}
+
+func parseTermRow(cells []*html.Node, columns trainingScoreColumns) (model.UITTrainingScoreTerm, bool) {
+	var term model.UITTrainingScoreTerm
+
+	cell := func(i int) string {
+		if i < 0 || i >= len(cells) {
+			return ""
+		}
+		return cleanText(textOf(cells[i]))
+	}
+
+	semesterText := cell(columns.semester)
+	m := termPattern.FindStringSubmatch(semesterText)
+	if m == nil {
+		return term, false
+	}
+	term.Semester = "HK" + m[1]
+
+	yearText := cell(columns.year)
+	if yearText == "" {
+		yearText = semesterText
+	}
+	if y := academicYearPattern.FindStringSubmatch(yearText); y != nil {
+		term.AcademicYear = y[1] + "-" + y[2]
+	}
+
+	if score, err := strconv.Atoi(digitsPattern.FindString(cell(columns.score))); err == nil && score <= 100 {
+		term.Score = &score
+	}
+	term.Rank = cell(columns.rank)
+
+	return term, true
+}
+
+// averageScore is the mean of the graded terms rounded to one decimal
+func averageScore(terms []model.UITTrainingScoreTerm) float64 {
+	total, graded := 0, 0
+	for _, t := range terms {
+		if t.Score != nil {
+			total += *t.Score
+			graded++
+		}
+	}
+	if graded == 0 {
+		return 0
+	}
+	return math.Round(float64(total)/float64(graded)*10) / 10
+}
@@ -0,0 +1,119 @@
+package uit
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+	"golang.org/x/net/html"
+)
+
+var datePattern = regexp.MustCompile(`\d{1,2}/\d{1,2}/\d{2,4}`)
+
+// errTuitionTableNotFound is returned when the page has no table of tuition fees
+var errTuitionTableNotFound = errors.New("tuition table not found")
+
+// tuitionColumns are the column indexes of the tuition table, -1 when the table has no such column
+type tuitionColumns struct {
+	semester, amount, paid, remaining, deadline int
+}
+
+// parseTuition extracts the tuition of each term from the DAA fee page. Columns are found by their header
+// ("Học kỳ", "Phải đóng", "Đã đóng", "Còn nợ", "Hạn đóng") so their order does not matter; a missing
+// remaining column is computed from the amount and what was paid.
+func parseTuition(page string) (*model.UITTuition, error) {
+	doc, err := html.Parse(strings.NewReader(page))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, table := range findAll(doc, func(n *html.Node) bool { return isElement(n, "table") }) {
+		rows := findAll(table, func(n *html.Node) bool { return isElement(n, "tr") })
+		if len(rows) == 0 {
+			continue
+		}
+
+		columns, ok := feeColumns(childElements(rows[0], "th", "td"))
+		if !ok {
+			continue
+		}
+
+		tuition := &model.UITTuition{Terms: []model.UITTuitionTerm{}}
+		for _, row := range rows[1:] {
+			if term, ok := parseTuitionRow(childElements(row, "td", "th"), columns); ok {
+				tuition.Terms = append(tuition.Terms, term)
+				tuition.Outstanding += term.Remaining
+			}
+		}
+		return tuition, nil
+	}
+
+	return nil, errTuitionTableNotFound
+}
+
+// feeColumns maps the header cells of a table, ok only if it has semester and amount columns
+func feeColumns(header []*html.Node) (tuitionColumns, bool) {
+	columns := tuitionColumns{semester: -1, amount: -1, paid: -1, remaining: -1, deadline: -1}
+	for i, cell := range header {
+		text := strings.ToLower(cleanText(textOf(cell)))
+		switch {
+		case strings.Contains(text, "học kỳ") && columns.semester < 0:
+			columns.semester = i
+		case strings.Contains(text, "hạn") && columns.deadline < 0:
+			columns.deadline = i
+		case strings.Contains(text, "đã đóng") && columns.paid < 0:
+			columns.paid = i
+		case (strings.Contains(text, "còn") || strings.Contains(text, "nợ")) && columns.remaining < 0:
+			columns.remaining = i
+		case (strings.Contains(text, "phải đóng") || strings.Contains(text, "học phí")) && columns.amount < 0:
+			columns.amount = i
+		}
+	}
+	return columns, columns.semester >= 0 && columns.amount >= 0
+}
+
+func parseTuitionRow(cells []*html.Node, columns tuitionColumns) (model.UITTuitionTerm, bool) {
+	var term model.UITTuitionTerm
+
+	cell := func(i int) string {
+		if i < 0 || i >= len(cells) {
+			return ""
+		}
+		return cleanText(textOf(cells[i]))
+	}
+
+	semesterText := cell(columns.semester)
+	if !termPattern.MatchString(semesterText) {
+		return term, false
+	}
+	term.Semester = semesterText
+	if normalized := extractSemester(semesterText); normalized != "" {
+		term.Semester = normalized
+	}
+
+	term.Amount = parseMoney(cell(columns.amount))
+	term.Paid = parseMoney(cell(columns.paid))
+	if columns.remaining >= 0 {
+		term.Remaining = parseMoney(cell(columns.remaining))
+	} else {
+		term.Remaining = max(term.Amount-term.Paid, 0)
+	}
+	term.Deadline = datePattern.FindString(cell(columns.deadline))
+
+	return term, true
+}
+
+// parseMoney reads a VND amount such as "12.500.000" or "12,500,000 đ", 0 if there is none
+func parseMoney(text string) int64 {
+	amount, _ := strconv.ParseInt(strings.Join(digitsPattern.FindAllString(text, -1), ""), 10, 64)
+	return amount
+}
+
+// TuitionDeadline returns the payment deadline of a term at midnight in UIT's timezone, ok = false if it has none
+func TuitionDeadline(term model.UITTuitionTerm) (time.Time, bool) {
+	date, err := parseDate(term.Deadline)
+	return date, err == nil
+}
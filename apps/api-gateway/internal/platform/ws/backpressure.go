@@ -0,0 +1,61 @@
+package ws
+
+import (
+	"log/slog"
+
+	"github.com/gorilla/websocket"
+)
+
+// Overflow policies applied when a client's outbound queue is full
+const (
+	OverflowDropOldest = "drop_oldest" // Discard the oldest queued message to make room
+	OverflowDisconnect = "disconnect"  // Close the connection, the client reconnects and resyncs
+)
+
+const defaultSendQueueSize = 256
+
+func (h *Hub) sendQueueSize() int {
+	if h.cfg.SendQueueSize > 0 {
+		return h.cfg.SendQueueSize
+	}
+	return defaultSendQueueSize
+}
+
+// deliver queues a message for the client, applying the overflow policy when its queue is full.
+// Runs on the hub goroutine, which is the only sender on client.send.
+func (h *Hub) deliver(client *Client, message []byte) {
+	select {
+	case client.send <- message:
+		return
+	default:
+	}
+
+	if h.cfg.OverflowPolicy == OverflowDisconnect {
+		slowClientDisconnects.Inc()
+		messagesDropped.WithLabel(dropDisconnect).Inc()
+		slog.Warn("Client outbound queue is full, disconnecting slow client", "user_id", client.UserID, "queue_size", cap(client.send))
+		h.closeClient(client, websocket.CloseTryAgainLater, "too slow to receive messages, please reconnect")
+		return
+	}
+
+	// Drop the oldest queued message. The write pump may drain the queue concurrently,
+	// in which case there is nothing to drop and the send below succeeds anyway.
+	select {
+	case <-client.send:
+		client.dropped++
+		messagesDropped.WithLabel(dropQueueFull).Inc()
+		// Log the first drop and then every 100th to avoid flooding the logs
+		if client.dropped%100 == 1 {
+			slog.Warn("Client outbound queue is full, dropped oldest message", "user_id", client.UserID, "dropped", client.dropped)
+		}
+	default:
+	}
+
+	select {
+	case client.send <- message:
+	default:
+		// Unreachable in practice, only the hub sends on client.send
+		messagesDropped.WithLabel(dropQueueFull).Inc()
+		slog.Warn("Client outbound queue is still full, message dropped", "user_id", client.UserID)
+	}
+}
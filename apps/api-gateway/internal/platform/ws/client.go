@@ -1,38 +1,64 @@
 package ws
 
 import (
-	"log"
+	"log/slog"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
 )
 
 const (
-	writeWait      = 10 * time.Second
-	pongWait       = 60 * time.Second
-	pingPeriod     = (pongWait * 9) / 10
 	maxMessageSize = 512
 )
 
 // Client is a middleman between the websocket connection and the hub.
 type Client struct {
-	hub    *Hub
-	conn   *websocket.Conn
-	send   chan []byte
-	UserID string
+	hub      *Hub
+	conn     *websocket.Conn
+	send     chan []byte
+	lastSeen atomic.Int64 // Unix nanoseconds of the last frame (message or pong) from the client
+	UserID   string
+	IsAdmin  bool // Admin clients also receive presence changes
+	dropped  int  // Messages dropped on queue overflow, only accessed from the hub goroutine
+
+	// LastAckAt, when set before registering, replays notifications created after it
+	LastAckAt *time.Time
+	replaying bool // Waiting for the client to ack a replay batch before sending the next
+
+	// Close frame sent once send is closed, set by the hub before closing it
+	closeCode   int
+	closeReason string
 }
 
 // NewClient creates a new client.
-func NewClient(hub *Hub, conn *websocket.Conn, userID string) *Client {
-	return &Client{
-		hub:    hub,
-		conn:   conn,
-		send:   make(chan []byte, 256),
-		UserID: userID,
+func NewClient(hub *Hub, conn *websocket.Conn, userID string, isAdmin bool) *Client {
+	client := &Client{
+		hub:     hub,
+		conn:    conn,
+		send:    make(chan []byte, hub.sendQueueSize()),
+		UserID:  userID,
+		IsAdmin: isAdmin,
+
+		closeCode: websocket.CloseNormalClosure,
 	}
+	client.touch()
+	return client
+}
+
+// touch records that the client is alive and extends its read deadline
+func (c *Client) touch() {
+	now := time.Now()
+	c.lastSeen.Store(now.UnixNano())
+	_ = c.conn.SetReadDeadline(now.Add(c.hub.pongWait()))
 }
 
-// Serve starts the client's read and write pumps.
+// idleSince returns how long ago the client last sent a frame
+func (c *Client) idleSince(now time.Time) time.Duration {
+	return now.Sub(time.Unix(0, c.lastSeen.Load()))
+}
+
+// Serve starts the client's read and write pumps. Call it only after the hub accepted the client.
 func (c *Client) Serve() {
 	go c.writePump()
 	go c.readPump()
@@ -41,14 +67,17 @@ func (c *Client) Serve() {
 // readPump pumps messages from the websocket connection to the hub.
 func (c *Client) readPump() {
 	defer func() {
-		c.hub.unregister <- c
+		select {
+		case c.hub.unregister <- c:
+		case <-c.hub.done:
+		}
 		c.conn.Close()
 	}()
 
 	c.conn.SetReadLimit(maxMessageSize)
-	_ = c.conn.SetReadDeadline(time.Now().UTC().Add(pongWait))
+	c.touch()
 	c.conn.SetPongHandler(func(string) error {
-		_ = c.conn.SetReadDeadline(time.Now().UTC().Add(pongWait))
+		c.touch()
 		return nil
 	})
 
@@ -56,36 +85,37 @@ func (c *Client) readPump() {
 		_, raw, err := c.conn.ReadMessage()
 		if err != nil {
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
-				log.Printf("WebSocket unexpected close for user %s: %v", c.UserID, err)
+				slog.Warn("WebSocket unexpected close", "user_id", c.UserID, "error", err)
 			}
 			break
 		}
+		c.touch()
 
-		data := append([]byte(c.UserID+"|"), raw...)
 		select {
-		case c.hub.incoming <- data:
+		case c.hub.incoming <- incomingMessage{client: c, data: raw}:
 		default:
-			log.Printf("Hub broadcast channel full, dropping message from user %s", c.UserID)
+			slog.Warn("Hub broadcast channel full, dropping message", "user_id", c.UserID)
 		}
 	}
 }
 
 // writePump pumps messages from the hub to the websocket connection.
 func (c *Client) writePump() {
-	ticker := time.NewTicker(pingPeriod)
+	ticker := time.NewTicker(c.hub.pingPeriod())
 	defer func() {
 		ticker.Stop()
 		c.conn.Close()
+		c.hub.writers.Done()
 	}()
 	for {
 		select {
 		case message, ok := <-c.send:
-			err := c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			err := c.conn.SetWriteDeadline(time.Now().Add(c.hub.writeWait()))
 			if err != nil {
 				return
 			}
 			if !ok {
-				c.conn.WriteMessage(websocket.CloseMessage, []byte{})
+				c.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(c.closeCode, c.closeReason))
 				return
 			}
 
@@ -98,8 +128,9 @@ func (c *Client) writePump() {
 			if err := w.Close(); err != nil {
 				return
 			}
+			messagesSent.Inc()
 		case <-ticker.C:
-			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			c.conn.SetWriteDeadline(time.Now().Add(c.hub.writeWait()))
 			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
 				return
 			}
@@ -1,82 +1,285 @@
 package ws
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
-	"log"
+	"log/slog"
+	"math/rand/v2"
+	"sync"
+	"time"
 
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/bus"
+	"github.com/gorilla/websocket"
 )
 
 // Hub maintains the set of active clients and broadcasts messages to them.
+// A user may be connected from several tabs or the browser extension at once; each connection is its own client.
 type Hub struct {
-	userClients map[string]*Client
+	userClients map[string]map[*Client]struct{}
 	register    chan *Client
 	unregister  chan *Client
-	incoming    chan []byte
+	incoming    chan incomingMessage
+	tasks       chan func()
 	eventBus    bus.EventBus
+	handler     IncomingHandler
+	presence    PresenceTracker
+	presenceCh  chan presenceUpdate
+	topics      *topicRegistry
+	cfg         *config.WebSocketConfig
+
+	// Shutdown
+	events  bus.EventListener
+	done    chan struct{}  // Closed once the hub loop has stopped
+	writers sync.WaitGroup // Client write pumps and the presence worker
+}
+
+// hubTopics are the bus topics the hub forwards to clients
+var hubTopics = []string{
+	bus.TopicNotificationCreated,
+	bus.TopicBroadcast,
+	bus.TopicUnreadCountChanged,
+	bus.TopicAnnouncementSent,
+	bus.TopicPresenceChanged,
+	bus.TopicChatSessionUpdated,
+	bus.TopicSessionTerminated,
+	bus.TopicDashboardMetrics,
+}
+
+// PresenceTracker records users connecting and disconnecting. Calls are made in order
+// from a single goroutine, outside the hub loop.
+type PresenceTracker interface {
+	Connected(userID string)
+	Disconnected(userID string)
+}
+
+// incomingMessage is a raw message read from a client
+type incomingMessage struct {
+	client *Client
+	data   []byte
 }
 
-func NewHub(bus bus.EventBus) *Hub {
+type presenceUpdate struct {
+	userID    string
+	connected bool
+}
+
+func NewHub(eventBus bus.EventBus, handler IncomingHandler, presence PresenceTracker, cfg *config.WebSocketConfig) *Hub {
 	return &Hub{
-		incoming:    make(chan []byte),
+		incoming:    make(chan incomingMessage),
 		register:    make(chan *Client),
 		unregister:  make(chan *Client),
-		userClients: make(map[string]*Client),
-		eventBus:    bus,
+		tasks:       make(chan func()),
+		userClients: make(map[string]map[*Client]struct{}),
+		eventBus:    eventBus,
+		handler:     handler,
+		presence:    presence,
+		presenceCh:  make(chan presenceUpdate, 256),
+		topics:      newTopicRegistry(),
+		cfg:         cfg,
+		events:      make(bus.EventListener, 100),
+		done:        make(chan struct{}),
 	}
 }
 
+func (h *Hub) pingPeriod() time.Duration {
+	return time.Duration(h.cfg.PingIntervalSeconds) * time.Second
+}
+
+func (h *Hub) pongWait() time.Duration {
+	return time.Duration(h.cfg.PongTimeoutSeconds) * time.Second
+}
+
+func (h *Hub) writeWait() time.Duration {
+	return time.Duration(h.cfg.WriteTimeoutSeconds) * time.Second
+}
+
 // Start runs the hub's event loop and subscribes to the event eventBus.
 func (h *Hub) Start() {
-	eventChannel := make(bus.EventListener, 100)
-	h.eventBus.Subscribe(bus.TopicNotificationCreated, eventChannel)
-	h.eventBus.Subscribe(bus.TopicBroadcast, eventChannel)
+	for _, topic := range hubTopics {
+		h.eventBus.Subscribe(topic, h.events)
+	}
+
+	slog.Info("WebSocket Hub started and subscribed to events")
+
+	h.writers.Add(1)
+	go h.trackPresence()
+	go h.run(h.events)
+}
 
-	log.Println("WebSocket Hub started and subscribed to events.")
+// Stop closes every connection with a reconnect hint, unsubscribes from the bus and waits
+// until all pending writes are flushed or ctx expires.
+func (h *Hub) Stop(ctx context.Context) error {
+	select {
+	case h.tasks <- h.shutdown:
+	case <-h.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	drained := make(chan struct{})
+	go func() {
+		h.writers.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		slog.Info("WebSocket Hub stopped, all clients drained")
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// shutdown runs on the hub goroutine and makes run return
+func (h *Hub) shutdown() {
+	for _, topic := range hubTopics {
+		h.eventBus.Unsubscribe(topic, h.events)
+	}
+
+	for _, clients := range h.userClients {
+		for client := range clients {
+			// Spread reconnects so clients do not all hit the next instance at once
+			h.sendToClient(client, dto.Reconnect, dto.ReconnectPayload{
+				Reason:       "server_shutdown",
+				RetryAfterMs: 1000 + rand.IntN(4000),
+			})
+			if !h.isRegistered(client) {
+				continue // Already disconnected by the overflow policy
+			}
+			h.closeClient(client, websocket.CloseServiceRestart, "server restarting, please reconnect")
+		}
+	}
 
-	go h.run(eventChannel)
+	close(h.presenceCh)
+	close(h.done)
 }
 
 // RegisterClient sends a client to the register channel.
-func (h *Hub) RegisterClient(client *Client) {
-	h.register <- client
+// Returns false if the hub has stopped and the client was not registered.
+func (h *Hub) RegisterClient(client *Client) bool {
+	select {
+	case h.register <- client:
+		return true
+	case <-h.done:
+		return false
+	}
+}
+
+// enqueue runs fn on the hub goroutine. It is dropped if the hub has stopped.
+func (h *Hub) enqueue(fn func()) {
+	select {
+	case h.tasks <- fn:
+	case <-h.done:
+	}
 }
 
 func (h *Hub) run(eventChannel bus.EventListener) {
+	sweepTicker := time.NewTicker(time.Duration(h.cfg.SweepIntervalSeconds) * time.Second)
+	defer sweepTicker.Stop()
+
 	for {
 		select {
 		case client := <-h.register:
-			h.userClients[client.UserID] = client
-			log.Printf("WebSocket client registered: %s", client.UserID)
+			h.queuePresence(client.UserID, true)
+
+			// Other connections of the user stay open; half-dead ones are evicted by the heartbeat sweep
+			clients, ok := h.userClients[client.UserID]
+			if !ok {
+				clients = make(map[*Client]struct{})
+				h.userClients[client.UserID] = clients
+			}
+			clients[client] = struct{}{}
+			connectedClients.Inc()
+			h.writers.Add(1) // Released when the client's write pump exits
+			slog.Debug("WebSocket client registered", "user_id", client.UserID)
+
+			if client.LastAckAt != nil {
+				h.replayNotifications(client, *client.LastAckAt)
+			}
 		case client := <-h.unregister:
-			if _, ok := h.userClients[client.UserID]; ok {
-				delete(h.userClients, client.UserID)
-				close(client.send)
-				log.Printf("WebSocket client unregistered: %s", client.UserID)
+			// The client may already have been removed, e.g. by the overflow policy or a terminated session
+			if h.isRegistered(client) {
+				h.removeClient(client)
+				slog.Debug("WebSocket client unregistered", "user_id", client.UserID)
 			}
-		case data := <-h.incoming:
-			//Handle message receive from client
-			parts := bytes.SplitN(data, []byte("|"), 2)
-			if len(parts) != 2 {
-				log.Println("Invalid incoming message format")
-				continue
+		case now := <-sweepTicker.C:
+			h.evictIdleClients(now)
+		case msg := <-h.incoming:
+			// Messages read before the client was removed are ignored
+			if h.isRegistered(msg.client) {
+				h.handleIncoming(msg.client, msg.data)
+			}
+		case task := <-h.tasks:
+			task()
+			select {
+			case <-h.done:
+				return
+			default:
 			}
-
-			userID := string(parts[0])
-			message := parts[1]
-			h.handleIncoming(message, userID)
 		case event := <-eventChannel:
 			//Handle event
+			hubEvents.WithLabel(event.Topic()).Inc()
 			switch event.Topic() {
 			case bus.TopicNotificationCreated:
 				payload := event.Payload()
-				if recipientID, ok := payload["recipientId"].(string); ok {
+				if recipientID, ok := payload["recipient_id"].(string); ok {
 					if notification, ok := payload["notification"].(interface{}); ok {
 						h.sendToUser(recipientID, dto.NewNotification, notification)
 					}
 				}
+			case bus.TopicUnreadCountChanged:
+				payload := event.Payload()
+				if recipientID, ok := payload["recipient_id"].(string); ok {
+					count, _ := payload["unread_count"].(int64)
+					h.sendToUser(recipientID, dto.UnreadCount, dto.UnreadCountPayload{UnreadCount: count})
+				}
+			case bus.TopicAnnouncementSent:
+				payload := event.Payload()
+				notifications, _ := payload["notifications"].(map[string]dto.NotificationResponse)
+				for recipientID, notification := range notifications {
+					h.sendToUser(recipientID, dto.NewNotification, notification)
+				}
+				if banner, ok := payload["announcement"].(*dto.AnnouncementBanner); ok && banner != nil {
+					recipientIDs, _ := payload["recipient_ids"].([]string)
+					h.broadcastToUsers(recipientIDs, dto.Announcement, banner)
+				}
+			case bus.TopicPresenceChanged:
+				payload := event.Payload()
+				userID, _ := payload["user_id"].(string)
+				online, _ := payload["online"].(bool)
+				lastSeen, _ := payload["last_seen"].(time.Time)
+				h.sendToAdmins(dto.PresenceChanged, dto.PresenceResponse{
+					UserID:   userID,
+					Online:   online,
+					LastSeen: &lastSeen,
+				})
+			case bus.TopicChatSessionUpdated:
+				payload := event.Payload()
+				sessionID, _ := payload["session_id"].(string)
+				eventType, _ := payload["event_type"].(bus.ChatSessionEventType)
+				topic := sessionTopic(sessionID)
+				h.sendToTopic(topic, dto.ChatSessionUpdate, dto.ChatSessionUpdatePayload{
+					SessionID: sessionID,
+					Event:     string(eventType),
+					Data:      payload["data"],
+				}, nil)
+				if eventType == bus.ChatSessionEventDeleted {
+					for client := range h.topics.subscribersOf(topic) {
+						h.topics.unsubscribe(topic, client)
+					}
+				}
+			case bus.TopicSessionTerminated:
+				payload := event.Payload()
+				userID, _ := payload["user_id"].(string)
+				reason, _ := payload["reason"].(bus.SessionTerminationReason)
+				h.terminateUser(userID, string(reason))
+			case bus.TopicDashboardMetrics:
+				payload := event.Payload()
+				h.sendToTopic(dashboardTopic, dto.DashboardMetrics, payload["metrics"], nil)
 			case bus.TopicBroadcast:
 				payload := event.Payload()
 				recipientIDs, _ := payload["recipient_ids"].([]string)
@@ -84,29 +287,134 @@ func (h *Hub) run(eventChannel bus.EventListener) {
 
 				h.broadcastToUsers(recipientIDs, dto.NewNotification, data)
 			default:
-				log.Printf("WebSocket client received unknown event: %s", event.Topic())
+				slog.Warn("WebSocket client received unknown event", "topic", event.Topic())
 			}
 		}
 	}
 }
 
-// sendToUser is a private method to send a message to a specific user.
-func (h *Hub) sendToUser(userID string, messageType dto.WebSocketMessageType, payload interface{}) {
-	if client, ok := h.userClients[userID]; ok {
-		msg := dto.WebSocketMessage{
-			Type:    messageType,
-			Payload: payload,
+// isRegistered reports whether the client is still connected to the hub
+func (h *Hub) isRegistered(client *Client) bool {
+	_, ok := h.userClients[client.UserID][client]
+	return ok
+}
+
+// removeClient drops the client and closes its connection. The write pump exits on the closed send channel.
+func (h *Hub) removeClient(client *Client) {
+	clients := h.userClients[client.UserID]
+	delete(clients, client)
+	if len(clients) == 0 {
+		delete(h.userClients, client.UserID)
+	}
+	connectedClients.Dec()
+	h.topics.unsubscribeAll(client)
+	close(client.send)
+	h.queuePresence(client.UserID, false)
+}
+
+// closeClient removes the client, closing its connection with the given close frame
+func (h *Hub) closeClient(client *Client, code int, reason string) {
+	client.closeCode = code
+	client.closeReason = reason
+	h.removeClient(client)
+}
+
+// terminateUser tells each of the user's clients why its session ended and closes their connections
+func (h *Hub) terminateUser(userID, reason string) {
+	h.sendToUser(userID, dto.SessionTerminated, dto.SessionTerminatedPayload{Reason: reason})
+
+	// Clients whose frame overflowed their queue are already gone
+	for client := range h.userClients[userID] {
+		h.closeClient(client, websocket.ClosePolicyViolation, "session terminated")
+	}
+	slog.Info("WebSocket session terminated", "user_id", userID, "reason", reason)
+}
+
+// queuePresence hands a connect/disconnect to the presence worker without blocking the hub loop
+func (h *Hub) queuePresence(userID string, connected bool) {
+	select {
+	case h.presenceCh <- presenceUpdate{userID: userID, connected: connected}:
+	default:
+		slog.Warn("Presence queue is full, update dropped", "user_id", userID)
+	}
+}
+
+// trackPresence forwards presence updates to the tracker one at a time so they keep their order
+func (h *Hub) trackPresence() {
+	defer h.writers.Done()
+
+	for update := range h.presenceCh {
+		if update.connected {
+			h.presence.Connected(update.userID)
+		} else {
+			h.presence.Disconnected(update.userID)
 		}
-		jsonMsg, err := json.Marshal(msg)
-		if err != nil {
-			log.Printf("Error marshalling websocket message: %v", err)
-			return
+	}
+}
+
+// evictIdleClients removes clients that have not answered heartbeats within the pong timeout
+func (h *Hub) evictIdleClients(now time.Time) {
+	for userID, clients := range h.userClients {
+		for client := range clients {
+			if idle := client.idleSince(now); idle > h.pongWait() {
+				h.removeClient(client)
+				slog.Info("WebSocket client evicted without heartbeat", "user_id", userID, "idle", idle.Round(time.Second))
+			}
 		}
+	}
+}
 
-		select {
-		case client.send <- jsonMsg:
-		default:
-			log.Printf("Warning: Client %s channel is full. Message dropped.", userID)
+// sendToUser sends a message to every client of a user
+func (h *Hub) sendToUser(userID string, messageType dto.WebSocketMessageType, payload interface{}) {
+	clients, ok := h.userClients[userID]
+	if !ok {
+		return
+	}
+
+	jsonMsg, err := json.Marshal(dto.WebSocketMessage{
+		Type:    messageType,
+		Payload: payload,
+	})
+	if err != nil {
+		slog.Error("Error marshalling websocket message", "error", err)
+		return
+	}
+
+	for client := range clients {
+		h.deliver(client, jsonMsg)
+	}
+}
+
+// sendToClient sends a message to a single client, e.g. the reply to a message it sent
+func (h *Hub) sendToClient(client *Client, messageType dto.WebSocketMessageType, payload interface{}) {
+	jsonMsg, err := json.Marshal(dto.WebSocketMessage{
+		Type:    messageType,
+		Payload: payload,
+	})
+	if err != nil {
+		slog.Error("Error marshalling websocket message", "error", err)
+		return
+	}
+
+	h.deliver(client, jsonMsg)
+}
+
+// sendToAdmins sends a message to every connected admin client
+func (h *Hub) sendToAdmins(messageType dto.WebSocketMessageType, payload interface{}) {
+	jsonMsg, err := json.Marshal(dto.WebSocketMessage{
+		Type:    messageType,
+		Payload: payload,
+	})
+	if err != nil {
+		slog.Error("Error marshalling websocket admin message", "error", err)
+		return
+	}
+
+	for _, clients := range h.userClients {
+		for client := range clients {
+			if client.IsAdmin {
+				h.deliver(client, jsonMsg)
+			}
 		}
 	}
 }
@@ -119,21 +427,36 @@ func (h *Hub) broadcastToUsers(userIDs []string, messageType dto.WebSocketMessag
 
 	jsonMsg, err := json.Marshal(msg)
 	if err != nil {
-		log.Printf("Error marshalling websocket broadcast message: %v", err)
+		slog.Error("Error marshalling websocket broadcast message", "error", err)
 		return
 	}
 
 	for _, userID := range userIDs {
-		if client, ok := h.userClients[userID]; ok {
-			select {
-			case client.send <- jsonMsg:
-			default:
-				log.Printf("Warning: Client %s channel is full. Broadcast message dropped.", userID)
-			}
+		for client := range h.userClients[userID] {
+			h.deliver(client, jsonMsg)
 		}
 	}
 }
 
-func (h *Hub) handleIncoming(message []byte, id string) {
-	panic("not implementedF")
+// sendToTopic sends a message to every client subscribed to a hub topic, except the optional sender
+func (h *Hub) sendToTopic(topic string, messageType dto.WebSocketMessageType, payload interface{}, except *Client) {
+	subscribers := h.topics.subscribersOf(topic)
+	if len(subscribers) == 0 {
+		return
+	}
+
+	jsonMsg, err := json.Marshal(dto.WebSocketMessage{
+		Type:    messageType,
+		Payload: payload,
+	})
+	if err != nil {
+		slog.Error("Error marshalling websocket topic message", "error", err)
+		return
+	}
+
+	for client := range subscribers {
+		if client != except {
+			h.deliver(client, jsonMsg)
+		}
+	}
 }
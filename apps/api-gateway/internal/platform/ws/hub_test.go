@@ -0,0 +1,159 @@
+package ws
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/bus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type allowAllHandler struct{}
+
+func (allowAllHandler) MarkNotificationRead(string, string) error                { return nil }
+func (allowAllHandler) MarkAllNotificationsRead(string) (int64, error)           { return 0, nil }
+func (allowAllHandler) CanAccessSession(string, string) bool                     { return true }
+func (allowAllHandler) DashboardSnapshot() (*dto.DashboardMetricsPayload, error) { return nil, nil }
+func (allowAllHandler) NotificationsSince(string, time.Time, int) ([]dto.NotificationResponse, bool, error) {
+	return nil, false, nil
+}
+
+type receivedMessage struct {
+	Type    dto.WebSocketMessageType `json:"type"`
+	Payload json.RawMessage          `json:"payload"`
+}
+
+// newTestHub runs a hub loop without the event bus; events are fed straight into h.events
+func newTestHub(t *testing.T) *Hub {
+	t.Helper()
+	h := NewHub(nil, allowAllHandler{}, nil, &config.WebSocketConfig{
+		PongTimeoutSeconds:   60,
+		SweepIntervalSeconds: 60,
+	})
+	go h.run(h.events)
+	return h
+}
+
+// newTestClient registers a client without a connection, its outbound messages stay in client.send
+func newTestClient(t *testing.T, h *Hub, userID string) *Client {
+	t.Helper()
+	client := &Client{hub: h, send: make(chan []byte, 16), UserID: userID}
+	if !h.RegisterClient(client) {
+		t.Fatal("hub refused the client")
+	}
+	return client
+}
+
+func sendIncoming(t *testing.T, h *Hub, client *Client, msgType dto.WebSocketMessageType, payload interface{}) {
+	t.Helper()
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(dto.IncomingWebSocketMessage{Type: msgType, RequestID: "req", Payload: raw})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.incoming <- incomingMessage{client: client, data: data}
+}
+
+func receive(t *testing.T, client *Client) receivedMessage {
+	t.Helper()
+	select {
+	case data := <-client.send:
+		var msg receivedMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			t.Fatalf("invalid message %s: %v", data, err)
+		}
+		return msg
+	case <-time.After(2 * time.Second):
+		t.Fatal("no message received")
+		return receivedMessage{}
+	}
+}
+
+func expectNothing(t *testing.T, client *Client) {
+	t.Helper()
+	select {
+	case data := <-client.send:
+		t.Fatalf("unexpected message %s", data)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func subscribe(t *testing.T, h *Hub, client *Client, sessionID string) {
+	t.Helper()
+	sendIncoming(t, h, client, dto.SubscribeSession, dto.SessionPayload{SessionID: sessionID})
+	if msg := receive(t, client); msg.Type != dto.ACKMessage {
+		t.Fatalf("expected subscribe ack, got %s", msg.Type)
+	}
+}
+
+func TestHubSendsSessionEventToEveryClientOfUser(t *testing.T) {
+	h := newTestHub(t)
+	sessionID := primitive.NewObjectID().Hex()
+
+	tab := newTestClient(t, h, "user-1")
+	extension := newTestClient(t, h, "user-1")
+	subscribe(t, h, tab, sessionID)
+	subscribe(t, h, extension, sessionID)
+
+	h.events <- bus.ChatSessionEvent{
+		SessionID: sessionID,
+		UserID:    "user-1",
+		EventType: bus.ChatSessionEventTitleChanged,
+		Data:      map[string]string{"title": "Lịch thi"},
+	}
+
+	for name, client := range map[string]*Client{"tab": tab, "extension": extension} {
+		msg := receive(t, client)
+		if msg.Type != dto.ChatSessionUpdate {
+			t.Fatalf("%s: expected %s, got %s", name, dto.ChatSessionUpdate, msg.Type)
+		}
+		var payload dto.ChatSessionUpdatePayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			t.Fatal(err)
+		}
+		if payload.SessionID != sessionID || payload.Event != string(bus.ChatSessionEventTitleChanged) {
+			t.Fatalf("%s: unexpected payload %+v", name, payload)
+		}
+	}
+}
+
+func TestHubRelaysTypingToOtherClientsOfUser(t *testing.T) {
+	h := newTestHub(t)
+	sessionID := primitive.NewObjectID().Hex()
+
+	tab := newTestClient(t, h, "user-1")
+	extension := newTestClient(t, h, "user-1")
+	subscribe(t, h, tab, sessionID)
+	subscribe(t, h, extension, sessionID)
+
+	sendIncoming(t, h, tab, dto.TypingIndicator, dto.TypingPayload{SessionID: sessionID})
+
+	if msg := receive(t, extension); msg.Type != dto.TypingIndicator {
+		t.Fatalf("expected %s, got %s", dto.TypingIndicator, msg.Type)
+	}
+	expectNothing(t, tab)
+}
+
+func TestHubKeepsOtherClientsWhenOneDisconnects(t *testing.T) {
+	h := newTestHub(t)
+
+	tab := newTestClient(t, h, "user-1")
+	extension := newTestClient(t, h, "user-1")
+	h.unregister <- tab
+
+	h.events <- bus.SessionTerminatedEvent{UserID: "user-1", Reason: bus.SessionTerminatedForceLogout}
+
+	if msg := receive(t, extension); msg.Type != dto.SessionTerminated {
+		t.Fatalf("expected %s, got %s", dto.SessionTerminated, msg.Type)
+	}
+	// The unregistered client's queue was closed without further messages
+	if _, ok := <-tab.send; ok {
+		t.Fatal("unregistered client received a message")
+	}
+}
@@ -0,0 +1,195 @@
+package ws
+
+import (
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/apperror"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// IncomingHandler performs the actions requested by client messages.
+// Methods are called outside the hub goroutine and may block on I/O.
+type IncomingHandler interface {
+	MarkNotificationRead(userID, notificationID string) error
+	MarkAllNotificationsRead(userID string) (int64, error)
+	CanAccessSession(userID, sessionID string) bool
+	NotificationsSince(userID string, since time.Time, limit int) ([]dto.NotificationResponse, bool, error)
+	DashboardSnapshot() (*dto.DashboardMetricsPayload, error)
+}
+
+// handleIncoming validates a client message and dispatches it by type. Replies go to the sending client only.
+// Runs on the hub goroutine; handlers doing I/O run in their own goroutine and
+// hand results back through h.enqueue so hub state is only touched there.
+func (h *Hub) handleIncoming(client *Client, message []byte) {
+	var msg dto.IncomingWebSocketMessage
+	if err := json.Unmarshal(message, &msg); err != nil || msg.Type == "" {
+		h.sendError(client, "", dto.WSErrInvalidMessage, "Tin nhắn không đúng định dạng")
+		return
+	}
+
+	switch msg.Type {
+	case dto.Ping:
+		h.sendToClient(client, dto.Pong, dto.AckPayload{RequestID: msg.RequestID, Type: msg.Type})
+	case dto.MarkRead:
+		h.handleMarkRead(client, msg)
+	case dto.SubscribeSession:
+		h.handleSubscribeSession(client, msg)
+	case dto.UnsubscribeSession:
+		h.handleUnsubscribeSession(client, msg)
+	case dto.TypingIndicator:
+		h.handleTyping(client, msg)
+	case dto.AckNotifications:
+		h.handleAckNotifications(client, msg)
+	case dto.SubscribeDashboard:
+		h.handleSubscribeDashboard(client, msg)
+	case dto.UnsubscribeDashboard:
+		h.handleUnsubscribeDashboard(client, msg)
+	default:
+		h.sendError(client, msg.RequestID, dto.WSErrUnknownType, "Loại tin nhắn không được hỗ trợ: "+string(msg.Type))
+	}
+}
+
+func (h *Hub) handleMarkRead(client *Client, msg dto.IncomingWebSocketMessage) {
+	var payload dto.MarkReadPayload
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		h.sendError(client, msg.RequestID, dto.WSErrInvalidPayload, "Dữ liệu không hợp lệ")
+		return
+	}
+	if !payload.All && !primitive.IsValidObjectID(payload.NotificationID) {
+		h.sendError(client, msg.RequestID, dto.WSErrInvalidPayload, "notification_id không hợp lệ")
+		return
+	}
+
+	userID := client.UserID
+	go func() {
+		var data interface{}
+		var err error
+		if payload.All {
+			var marked int64
+			marked, err = h.handler.MarkAllNotificationsRead(userID)
+			data = map[string]int64{"marked_count": marked}
+		} else {
+			err = h.handler.MarkNotificationRead(userID, payload.NotificationID)
+			data = map[string]string{"notification_id": payload.NotificationID}
+		}
+
+		h.enqueue(func() {
+			// The client may have disconnected in the meantime
+			if !h.isRegistered(client) {
+				return
+			}
+			if err != nil {
+				h.sendError(client, msg.RequestID, apperror.Code(err), apperror.Message(err))
+				return
+			}
+			h.sendAck(client, msg, data)
+		})
+	}()
+}
+
+func (h *Hub) handleSubscribeSession(client *Client, msg dto.IncomingWebSocketMessage) {
+	var payload dto.SessionPayload
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil || !primitive.IsValidObjectID(payload.SessionID) {
+		h.sendError(client, msg.RequestID, dto.WSErrInvalidPayload, "session_id không hợp lệ")
+		return
+	}
+
+	userID := client.UserID
+	go func() {
+		allowed := h.handler.CanAccessSession(userID, payload.SessionID)
+
+		h.enqueue(func() {
+			// The client may have disconnected while ownership was being checked
+			if !h.isRegistered(client) {
+				return
+			}
+			if !allowed {
+				h.sendError(client, msg.RequestID, apperror.ErrForbidden.Code, apperror.ErrForbidden.Message)
+				return
+			}
+			h.topics.subscribe(sessionTopic(payload.SessionID), client)
+			h.sendAck(client, msg, payload)
+		})
+	}()
+}
+
+func (h *Hub) handleUnsubscribeSession(client *Client, msg dto.IncomingWebSocketMessage) {
+	var payload dto.SessionPayload
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil || payload.SessionID == "" {
+		h.sendError(client, msg.RequestID, dto.WSErrInvalidPayload, "session_id không hợp lệ")
+		return
+	}
+
+	h.topics.unsubscribe(sessionTopic(payload.SessionID), client)
+	h.sendAck(client, msg, payload)
+}
+
+// handleSubscribeDashboard subscribes an admin client to live dashboard metrics and sends the current snapshot
+func (h *Hub) handleSubscribeDashboard(client *Client, msg dto.IncomingWebSocketMessage) {
+	if !client.IsAdmin {
+		h.sendError(client, msg.RequestID, apperror.ErrForbidden.Code, apperror.ErrForbidden.Message)
+		return
+	}
+
+	h.topics.subscribe(dashboardTopic, client)
+	h.sendAck(client, msg, nil)
+
+	go func() {
+		metrics, err := h.handler.DashboardSnapshot()
+		if err != nil {
+			slog.Error("WebSocket: failed to load dashboard snapshot", "user_id", client.UserID, "error", err)
+			return
+		}
+
+		h.enqueue(func() {
+			// The client may have unsubscribed or disconnected in the meantime
+			if h.isRegistered(client) && h.topics.isSubscribed(dashboardTopic, client) {
+				h.sendToClient(client, dto.DashboardMetrics, metrics)
+			}
+		})
+	}()
+}
+
+func (h *Hub) handleUnsubscribeDashboard(client *Client, msg dto.IncomingWebSocketMessage) {
+	h.topics.unsubscribe(dashboardTopic, client)
+	h.sendAck(client, msg, nil)
+}
+
+// handleTyping relays a typing indicator to the other subscribers of the session,
+// including the user's other tabs
+func (h *Hub) handleTyping(client *Client, msg dto.IncomingWebSocketMessage) {
+	var payload dto.TypingPayload
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil || payload.SessionID == "" {
+		h.sendError(client, msg.RequestID, dto.WSErrInvalidPayload, "session_id không hợp lệ")
+		return
+	}
+
+	topic := sessionTopic(payload.SessionID)
+	if !h.topics.isSubscribed(topic, client) {
+		h.sendError(client, msg.RequestID, dto.WSErrNotSubscribed, "Bạn chưa đăng ký nhận sự kiện của phiên chat này")
+		return
+	}
+
+	payload.UserID = client.UserID
+	h.sendToTopic(topic, dto.TypingIndicator, payload, client)
+}
+
+func (h *Hub) sendAck(client *Client, msg dto.IncomingWebSocketMessage, data interface{}) {
+	h.sendToClient(client, dto.ACKMessage, dto.AckPayload{
+		RequestID: msg.RequestID,
+		Type:      msg.Type,
+		Data:      data,
+	})
+}
+
+func (h *Hub) sendError(client *Client, requestID, code, message string) {
+	slog.Info("WebSocket message rejected", "user_id", client.UserID, "code", code)
+	h.sendToClient(client, dto.ErrorMessage, dto.ErrorPayload{
+		RequestID: requestID,
+		ErrorCode: &code,
+		ErrorMsg:  message,
+	})
+}
@@ -0,0 +1,35 @@
+package ws
+
+import "github.com/giakiet05/uit-ai-assistant/backend/internal/platform/metrics"
+
+// Hub metrics, exposed on /metrics
+var (
+	connectedClients = metrics.NewGauge(
+		"ws_connected_clients",
+		"Number of WebSocket clients currently registered with the hub.",
+	)
+	messagesSent = metrics.NewCounter(
+		"ws_messages_sent_total",
+		"Messages written to WebSocket connections.",
+	)
+	messagesDropped = metrics.NewCounterVec(
+		"ws_messages_dropped_total",
+		"Outbound messages discarded before reaching the client.",
+		"reason",
+	)
+	slowClientDisconnects = metrics.NewCounter(
+		"ws_slow_client_disconnects_total",
+		"Clients disconnected because their outbound queue overflowed.",
+	)
+	hubEvents = metrics.NewCounterVec(
+		"ws_hub_events_total",
+		"Bus events received by the hub, by topic.",
+		"topic",
+	)
+)
+
+// Reasons for ws_messages_dropped_total
+const (
+	dropQueueFull  = "queue_full" // Oldest message dropped by the drop_oldest policy
+	dropDisconnect = "disconnect" // Message that triggered a slow client disconnect
+)
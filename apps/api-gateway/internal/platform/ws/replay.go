@@ -0,0 +1,57 @@
+package ws
+
+import (
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
+)
+
+// replayBatchSize is the number of missed notifications sent per replay message
+const replayBatchSize = 50
+
+// replayNotifications sends the client the next batch of notifications created after since.
+// Runs on the hub goroutine. Further batches are sent as the client acknowledges each one,
+// so a client that disconnects mid-replay resumes from its last ack.
+// Live notifications may overlap the replay; clients dedupe by notification ID.
+func (h *Hub) replayNotifications(client *Client, since time.Time) {
+	client.replaying = true
+
+	go func() {
+		notifications, hasMore, err := h.handler.NotificationsSince(client.UserID, since, replayBatchSize)
+
+		h.enqueue(func() {
+			// The client may have disconnected while notifications were being loaded
+			if !h.isRegistered(client) {
+				return
+			}
+			if err != nil {
+				slog.Error("WebSocket replay failed", "user_id", client.UserID, "error", err)
+				client.replaying = false
+				h.sendError(client, "", dto.WSErrReplayFailed, "Không thể tải lại thông báo bị lỡ")
+				return
+			}
+
+			client.replaying = hasMore
+			h.sendToClient(client, dto.NotificationReplay, dto.NotificationReplayPayload{
+				Notifications: notifications,
+				HasMore:       hasMore,
+			})
+		})
+	}()
+}
+
+// handleAckNotifications confirms receipt and continues an in-progress replay from the acked timestamp
+func (h *Hub) handleAckNotifications(client *Client, msg dto.IncomingWebSocketMessage) {
+	var payload dto.AckNotificationsPayload
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil || payload.LastAckAt.IsZero() {
+		h.sendError(client, msg.RequestID, dto.WSErrInvalidPayload, "last_ack_at không hợp lệ")
+		return
+	}
+
+	h.sendAck(client, msg, payload)
+	if client.replaying {
+		h.replayNotifications(client, payload.LastAckAt)
+	}
+}
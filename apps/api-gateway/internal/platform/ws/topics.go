@@ -0,0 +1,68 @@
+package ws
+
+// sessionTopic is the hub topic carrying updates of one chat session
+func sessionTopic(sessionID string) string {
+	return "session:" + sessionID
+}
+
+// dashboardTopic is the hub topic carrying live admin dashboard metrics
+const dashboardTopic = "admin:dashboard"
+
+// topicRegistry tracks which clients subscribed to which hub topics.
+// Only accessed from the hub goroutine.
+type topicRegistry struct {
+	subscribers map[string]map[*Client]struct{} // topic -> clients
+	topics      map[*Client]map[string]struct{} // client -> topics, to clean up on disconnect
+}
+
+func newTopicRegistry() *topicRegistry {
+	return &topicRegistry{
+		subscribers: make(map[string]map[*Client]struct{}),
+		topics:      make(map[*Client]map[string]struct{}),
+	}
+}
+
+func (r *topicRegistry) subscribe(topic string, client *Client) {
+	if r.subscribers[topic] == nil {
+		r.subscribers[topic] = make(map[*Client]struct{})
+	}
+	r.subscribers[topic][client] = struct{}{}
+
+	if r.topics[client] == nil {
+		r.topics[client] = make(map[string]struct{})
+	}
+	r.topics[client][topic] = struct{}{}
+}
+
+func (r *topicRegistry) unsubscribe(topic string, client *Client) {
+	if clients, ok := r.subscribers[topic]; ok {
+		delete(clients, client)
+		if len(clients) == 0 {
+			delete(r.subscribers, topic)
+		}
+	}
+
+	if topics, ok := r.topics[client]; ok {
+		delete(topics, topic)
+		if len(topics) == 0 {
+			delete(r.topics, client)
+		}
+	}
+}
+
+// unsubscribeAll removes the client from every topic it subscribed to
+func (r *topicRegistry) unsubscribeAll(client *Client) {
+	for topic := range r.topics[client] {
+		r.unsubscribe(topic, client)
+	}
+}
+
+func (r *topicRegistry) isSubscribed(topic string, client *Client) bool {
+	_, ok := r.subscribers[topic][client]
+	return ok
+}
+
+// subscribersOf returns the clients subscribed to topic. The map must not be modified.
+func (r *topicRegistry) subscribersOf(topic string) map[*Client]struct{} {
+	return r.subscribers[topic]
+}
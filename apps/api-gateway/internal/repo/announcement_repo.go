@@ -0,0 +1,97 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// AnnouncementRepo defines the interface for announcement repository
+type AnnouncementRepo interface {
+	Create(ctx context.Context, announcement *model.Announcement) (*model.Announcement, error)
+	GetByID(ctx context.Context, id string) (*model.Announcement, error)
+	Find(ctx context.Context, page, pageSize int) ([]*model.Announcement, int64, error)
+	Update(ctx context.Context, announcement *model.Announcement) (*model.Announcement, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type announcementRepo struct {
+	collection *mongo.Collection
+}
+
+// NewAnnouncementRepo creates a new announcement repository
+func NewAnnouncementRepo(db *mongo.Database) AnnouncementRepo {
+	return &announcementRepo{collection: db.Collection(config.AnnouncementColName)}
+}
+
+// Create creates a new announcement
+func (r *announcementRepo) Create(ctx context.Context, announcement *model.Announcement) (*model.Announcement, error) {
+	announcement.CreatedAt = time.Now()
+	announcement.UpdatedAt = time.Now()
+
+	result, err := r.collection.InsertOne(ctx, announcement)
+	if err != nil {
+		return nil, err
+	}
+
+	announcement.ID = result.InsertedID.(primitive.ObjectID)
+	return announcement, nil
+}
+
+// GetByID retrieves an announcement by ID
+func (r *announcementRepo) GetByID(ctx context.Context, id string) (*model.Announcement, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var announcement model.Announcement
+	if err := r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&announcement); err != nil {
+		return nil, err
+	}
+
+	return &announcement, nil
+}
+
+// Find retrieves a page of announcements, newest first
+func (r *announcementRepo) Find(ctx context.Context, page, pageSize int) ([]*model.Announcement, int64, error) {
+	return findPage[*model.Announcement](ctx, r.collection, bson.M{}, bson.D{{Key: "created_at", Value: -1}}, offsetStages(page, pageSize))
+}
+
+// Update replaces an announcement
+func (r *announcementRepo) Update(ctx context.Context, announcement *model.Announcement) (*model.Announcement, error) {
+	announcement.UpdatedAt = time.Now()
+
+	result, err := r.collection.ReplaceOne(ctx, bson.M{"_id": announcement.ID}, announcement)
+	if err != nil {
+		return nil, err
+	}
+	if result.MatchedCount == 0 {
+		return nil, mongo.ErrNoDocuments
+	}
+
+	return announcement, nil
+}
+
+// Delete permanently removes an announcement. Notifications already delivered are kept.
+func (r *announcementRepo) Delete(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
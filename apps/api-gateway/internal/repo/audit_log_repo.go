@@ -0,0 +1,46 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// AuditLogRepo defines the interface for audit log repository.
+// Audit logs are append-only: there is no update or delete.
+type AuditLogRepo interface {
+	Create(ctx context.Context, entry *model.AuditLog) error
+	Find(ctx context.Context, filter Filter, page, pageSize int) ([]*model.AuditLog, int64, error)
+}
+
+type auditLogRepo struct {
+	collection *mongo.Collection
+}
+
+// NewAuditLogRepo creates a new audit log repository
+func NewAuditLogRepo(db *mongo.Database) AuditLogRepo {
+	return &auditLogRepo{collection: db.Collection(config.AuditLogColName)}
+}
+
+// Create appends an audit log entry
+func (r *auditLogRepo) Create(ctx context.Context, entry *model.AuditLog) error {
+	entry.CreatedAt = time.Now()
+
+	result, err := r.collection.InsertOne(ctx, entry)
+	if err != nil {
+		return err
+	}
+
+	entry.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// Find retrieves audit log entries matching filter, newest first
+func (r *auditLogRepo) Find(ctx context.Context, filter Filter, page, pageSize int) ([]*model.AuditLog, int64, error) {
+	return findPage[*model.AuditLog](ctx, r.collection, bson.M(filter), bson.D{{Key: "created_at", Value: -1}}, offsetStages(page, pageSize))
+}
@@ -0,0 +1,152 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/metrics"
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var userCacheLookups = metrics.NewCounterVec(
+	"user_cache_lookups_total",
+	"User lookups by ID served through the Redis cache, by result (hit, miss, error).",
+	"result",
+)
+
+// cachedUserRepo serves GetByID from Redis and falls back to the wrapped repo on a miss.
+// Every write through the repo drops the cached users it touches. Methods not overridden here pass through.
+type cachedUserRepo struct {
+	UserRepo
+	redisClient *redis.Client
+	ttl         time.Duration
+}
+
+// NewCachedUserRepo wraps a user repo with a read-through cache of users by ID, kept for ttl
+func NewCachedUserRepo(inner UserRepo, redisClient *redis.Client, ttl time.Duration) UserRepo {
+	return &cachedUserRepo{UserRepo: inner, redisClient: redisClient, ttl: ttl}
+}
+
+func (r *cachedUserRepo) GetByID(ctx context.Context, id string) (*model.User, error) {
+	key := userCacheKey(id)
+
+	// Encoded as BSON rather than JSON: the JSON tags hide fields such as the password hash
+	data, err := r.redisClient.Get(ctx, key).Bytes()
+	if err == nil {
+		var user model.User
+		if err := bson.Unmarshal(data, &user); err == nil {
+			userCacheLookups.WithLabel("hit").Inc()
+			return &user, nil
+		}
+	}
+	if err != nil && !errors.Is(err, redis.Nil) {
+		userCacheLookups.WithLabel("error").Inc()
+		slog.Warn("User cache: read failed", "user_id", id, "error", err)
+	} else {
+		userCacheLookups.WithLabel("miss").Inc()
+	}
+
+	user, err := r.UserRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if data, err := bson.Marshal(user); err == nil {
+		if err := r.redisClient.Set(ctx, key, data, r.ttl).Err(); err != nil {
+			slog.Warn("User cache: write failed", "user_id", id, "error", err)
+		}
+	}
+	return user, nil
+}
+
+func (r *cachedUserRepo) Update(ctx context.Context, user *model.User) (*model.User, error) {
+	defer r.invalidate(ctx, user.ID.Hex())
+	return r.UserRepo.Update(ctx, user)
+}
+
+func (r *cachedUserRepo) UpdateAvatarField(ctx context.Context, userID string, avatar *model.Image) (*model.User, error) {
+	defer r.invalidate(ctx, userID)
+	return r.UserRepo.UpdateAvatarField(ctx, userID, avatar)
+}
+
+func (r *cachedUserRepo) Delete(ctx context.Context, id string) error {
+	defer r.invalidate(ctx, id)
+	return r.UserRepo.Delete(ctx, id)
+}
+
+func (r *cachedUserRepo) HardDelete(ctx context.Context, id string) error {
+	defer r.invalidate(ctx, id)
+	return r.UserRepo.HardDelete(ctx, id)
+}
+
+func (r *cachedUserRepo) UpdateReputation(ctx context.Context, userID string, points int) error {
+	defer r.invalidate(ctx, userID)
+	return r.UserRepo.UpdateReputation(ctx, userID, points)
+}
+
+func (r *cachedUserRepo) UpdateLastDigestSentAt(ctx context.Context, userID string, sentAt time.Time) error {
+	defer r.invalidate(ctx, userID)
+	return r.UserRepo.UpdateLastDigestSentAt(ctx, userID, sentAt)
+}
+
+func (r *cachedUserRepo) UpdateLastLogin(ctx context.Context, userID string, at time.Time, ip string) error {
+	defer r.invalidate(ctx, userID)
+	return r.UserRepo.UpdateLastLogin(ctx, userID, at, ip)
+}
+
+func (r *cachedUserRepo) AddAdminNote(ctx context.Context, userID string, note *model.AdminNote) error {
+	defer r.invalidate(ctx, userID)
+	return r.UserRepo.AddAdminNote(ctx, userID, note)
+}
+
+func (r *cachedUserRepo) Deactivate(ctx context.Context, userID string, at time.Time) error {
+	defer r.invalidate(ctx, userID)
+	return r.UserRepo.Deactivate(ctx, userID, at)
+}
+
+func (r *cachedUserRepo) Reactivate(ctx context.Context, userID string) error {
+	defer r.invalidate(ctx, userID)
+	return r.UserRepo.Reactivate(ctx, userID)
+}
+
+func (r *cachedUserRepo) LinkProvider(ctx context.Context, userID string, provider model.AuthProvider, providerID string) error {
+	defer r.invalidate(ctx, userID)
+	return r.UserRepo.LinkProvider(ctx, userID, provider, providerID)
+}
+
+// UpdateManyByIDs backs the bulk admin actions (ban, unban, delete, restore)
+func (r *cachedUserRepo) UpdateManyByIDs(ctx context.Context, ids []primitive.ObjectID, update bson.M) (int64, error) {
+	userIDs := make([]string, len(ids))
+	for i, id := range ids {
+		userIDs[i] = id.Hex()
+	}
+	defer r.invalidate(ctx, userIDs...)
+	return r.UserRepo.UpdateManyByIDs(ctx, ids, update)
+}
+
+// invalidate drops cached users after a write, whether or not it succeeded, since a failed write may
+// still have been applied. A reader racing the write can cache the old document again; the TTL bounds that.
+func (r *cachedUserRepo) invalidate(ctx context.Context, userIDs ...string) {
+	if len(userIDs) == 0 {
+		return
+	}
+
+	keys := make([]string, len(userIDs))
+	for i, id := range userIDs {
+		keys[i] = userCacheKey(id)
+	}
+	if err := r.redisClient.Del(ctx, keys...).Err(); err != nil {
+		slog.Warn("User cache: invalidation failed", "users", len(keys), "error", err)
+	}
+}
+
+func userCacheKey(userID string) string {
+	return fmt.Sprintf(config.RedisUserCacheKey, userID)
+}
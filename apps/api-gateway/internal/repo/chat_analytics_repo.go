@@ -0,0 +1,166 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ChatAnalyticsRepo aggregates chat message metadata for admin analytics.
+// All methods cover messages created in [from, to).
+type ChatAnalyticsRepo interface {
+	GetDailyStats(ctx context.Context, from, to time.Time) ([]*model.ChatDailyStats, error)
+	GetToolUsage(ctx context.Context, from, to time.Time, limit int) ([]*model.NamedCount, error)
+	GetTopKeywords(ctx context.Context, from, to time.Time, stopwords []string, limit int) ([]*model.NamedCount, error)
+	GetUserUsage(ctx context.Context, from, to time.Time) ([]*model.UserUsageRollup, error)
+}
+
+type chatAnalyticsRepo struct {
+	collection *mongo.Collection
+}
+
+// NewChatAnalyticsRepo creates a new chat analytics repository
+func NewChatAnalyticsRepo(db *mongo.Database) ChatAnalyticsRepo {
+	return &chatAnalyticsRepo{
+		collection: db.Collection(config.ChatMessageColName),
+	}
+}
+
+func createdBetween(from, to time.Time) bson.M {
+	return bson.M{"created_at": bson.M{"$gte": from, "$lt": to}}
+}
+
+// GetDailyStats returns message volume, average agent latency and tokens used per day, oldest first
+func (r *chatAnalyticsRepo) GetDailyStats(ctx context.Context, from, to time.Time) ([]*model.ChatDailyStats, error) {
+	isRole := func(role model.MessageRole) bson.M {
+		return bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$role", role}}, 1, 0}}
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: createdBetween(from, to)}},
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{"$dateToString": bson.M{
+				"format":   "%Y-%m-%d",
+				"date":     "$created_at",
+				"timezone": StatsTimezone,
+			}},
+			"user_messages":      bson.M{"$sum": isRole(model.RoleUser)},
+			"assistant_messages": bson.M{"$sum": isRole(model.RoleAssistant)},
+			// $avg and $sum skip messages without the field, i.e. user messages
+			"avg_latency_ms": bson.M{"$avg": "$metadata.latency_ms"},
+			"tokens_used":    bson.M{"$sum": "$metadata.tokens_used"},
+		}}},
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+	}
+
+	var stats []*model.ChatDailyStats
+	if err := r.aggregate(ctx, pipeline, &stats); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// GetToolUsage returns how often each agent tool was called, most used first
+func (r *chatAnalyticsRepo) GetToolUsage(ctx context.Context, from, to time.Time, limit int) ([]*model.NamedCount, error) {
+	match := createdBetween(from, to)
+	match["role"] = model.RoleAssistant
+	match["metadata.tool_calls"] = bson.M{"$exists": true}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$unwind", Value: "$metadata.tool_calls"}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   "$metadata.tool_calls.tool_name",
+			"count": bson.M{"$sum": 1},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$limit", Value: limit}},
+	}
+
+	var usage []*model.NamedCount
+	if err := r.aggregate(ctx, pipeline, &usage); err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
+
+// GetTopKeywords returns the most frequent words in user questions, ignoring stopwords and punctuation
+func (r *chatAnalyticsRepo) GetTopKeywords(ctx context.Context, from, to time.Time, stopwords []string, limit int) ([]*model.NamedCount, error) {
+	match := createdBetween(from, to)
+	match["role"] = model.RoleUser
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$project", Value: bson.M{
+			"word": bson.M{"$split": bson.A{bson.M{"$toLower": "$content"}, " "}},
+		}}},
+		{{Key: "$unwind", Value: "$word"}},
+		{{Key: "$project", Value: bson.M{
+			"word": bson.M{"$trim": bson.M{"input": "$word", "chars": " \t\n.,;:!?\"'()[]{}"}},
+		}}},
+		{{Key: "$match", Value: bson.M{
+			"word":  bson.M{"$nin": stopwords},
+			"$expr": bson.M{"$gte": bson.A{bson.M{"$strLenCP": "$word"}, 2}},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   "$word",
+			"count": bson.M{"$sum": 1},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$limit", Value: limit}},
+	}
+
+	var keywords []*model.NamedCount
+	if err := r.aggregate(ctx, pipeline, &keywords); err != nil {
+		return nil, err
+	}
+	return keywords, nil
+}
+
+// GetUserUsage returns the number of assistant answers and tokens used per user.
+// Messages are grouped per session first so each session is joined with its owner only once.
+func (r *chatAnalyticsRepo) GetUserUsage(ctx context.Context, from, to time.Time) ([]*model.UserUsageRollup, error) {
+	match := createdBetween(from, to)
+	match["role"] = model.RoleAssistant
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{
+			"_id":         "$session_id",
+			"requests":    bson.M{"$sum": 1},
+			"tokens_used": bson.M{"$sum": "$metadata.tokens_used"},
+		}}},
+		{{Key: "$lookup", Value: bson.M{
+			"from":         config.ChatSessionColName,
+			"localField":   "_id",
+			"foreignField": "_id",
+			"as":           "session",
+		}}},
+		{{Key: "$unwind", Value: "$session"}},
+		{{Key: "$group", Value: bson.M{
+			"_id":         "$session.user_id",
+			"requests":    bson.M{"$sum": "$requests"},
+			"tokens_used": bson.M{"$sum": "$tokens_used"},
+		}}},
+	}
+
+	var usage []*model.UserUsageRollup
+	if err := r.aggregate(ctx, pipeline, &usage); err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
+
+func (r *chatAnalyticsRepo) aggregate(ctx context.Context, pipeline mongo.Pipeline, results interface{}) error {
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	return cursor.All(ctx, results)
+}
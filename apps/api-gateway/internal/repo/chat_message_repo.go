@@ -2,6 +2,7 @@ package repo
 
 import (
 	"context"
+	"slices"
 	"time"
 
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
@@ -17,9 +18,15 @@ type ChatMessageRepo interface {
 	Create(ctx context.Context, message *model.ChatMessage) (*model.ChatMessage, error)
 	CreateBatch(ctx context.Context, messages []*model.ChatMessage) error
 	GetBySessionID(ctx context.Context, sessionID string, limit int) ([]*model.ChatMessage, error)
+	GetPageBySessionID(ctx context.Context, sessionID string, page Page) ([]*model.ChatMessage, *Cursor, error)
 	GetByID(ctx context.Context, id string) (*model.ChatMessage, error)
 	DeleteBySessionID(ctx context.Context, sessionID string) error
+	DeleteBySessionIDs(ctx context.Context, sessionIDs []primitive.ObjectID) (int64, error)
 	CountBySessionID(ctx context.Context, sessionID string) (int64, error)
+	Search(ctx context.Context, filter Filter, page, pageSize int) ([]*model.ChatMessage, int64, error)
+	GetAround(ctx context.Context, sessionID primitive.ObjectID, at time.Time, before, after int) ([]*model.ChatMessage, []*model.ChatMessage, error)
+	CountCreatedPerDay(ctx context.Context, since time.Time) ([]*model.DailyCount, error)
+	MarkDeadLinks(ctx context.Context, messageID primitive.ObjectID, urls []string) error
 }
 
 type chatMessageRepo struct {
@@ -119,6 +126,35 @@ func (r *chatMessageRepo) GetBySessionID(ctx context.Context, sessionID string,
 	return messages, nil
 }
 
+// GetPageBySessionID retrieves a page of a session's messages. Pages follow page's order (newest first
+// for history), the messages of a page are returned in chronological order.
+// The returned cursor is nil on the last page.
+func (r *chatMessageRepo) GetPageBySessionID(ctx context.Context, sessionID string, page Page) ([]*model.ChatMessage, *Cursor, error) {
+	objectID, err := primitive.ObjectIDFromHex(sessionID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cursor, err := r.collection.Find(ctx, page.Match(bson.M{"session_id": objectID}), page.FindOptions())
+	if err != nil {
+		return nil, nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var messages []*model.ChatMessage
+	if err = cursor.All(ctx, &messages); err != nil {
+		return nil, nil, err
+	}
+
+	messages, next := paginate(messages, page, func(m *model.ChatMessage) Cursor {
+		return Cursor{Value: m.CreatedAt, ID: m.ID}
+	})
+	if page.Order < 0 {
+		slices.Reverse(messages)
+	}
+	return messages, next, nil
+}
+
 // GetByID retrieves a chat message by ID
 func (r *chatMessageRepo) GetByID(ctx context.Context, id string) (*model.ChatMessage, error) {
 	objectID, err := primitive.ObjectIDFromHex(id)
@@ -149,6 +185,20 @@ func (r *chatMessageRepo) DeleteBySessionID(ctx context.Context, sessionID strin
 	return err
 }
 
+// DeleteBySessionIDs deletes all messages of the given sessions
+func (r *chatMessageRepo) DeleteBySessionIDs(ctx context.Context, sessionIDs []primitive.ObjectID) (int64, error) {
+	if len(sessionIDs) == 0 {
+		return 0, nil
+	}
+
+	result, err := r.collection.DeleteMany(ctx, bson.M{"session_id": bson.M{"$in": sessionIDs}})
+	if err != nil {
+		return 0, err
+	}
+
+	return result.DeletedCount, nil
+}
+
 // CountBySessionID counts messages in a session
 func (r *chatMessageRepo) CountBySessionID(ctx context.Context, sessionID string) (int64, error) {
 	objectID, err := primitive.ObjectIDFromHex(sessionID)
@@ -164,3 +214,69 @@ func (r *chatMessageRepo) CountBySessionID(ctx context.Context, sessionID string
 
 	return count, nil
 }
+
+// CountCreatedPerDay counts user messages sent per day since the given time
+func (r *chatMessageRepo) CountCreatedPerDay(ctx context.Context, since time.Time) ([]*model.DailyCount, error) {
+	return countPerDay(ctx, r.collection, bson.M{"role": model.RoleUser}, since)
+}
+
+// Search retrieves messages from all sessions matching filter, newest first
+func (r *chatMessageRepo) Search(ctx context.Context, filter Filter, page, pageSize int) ([]*model.ChatMessage, int64, error) {
+	sort := bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}
+	return findPage[*model.ChatMessage](ctx, r.collection, bson.M(filter), sort, offsetStages(page, pageSize))
+}
+
+// GetAround returns up to before messages sent just before at and up to after messages sent just after it
+// in the same session, both oldest first
+func (r *chatMessageRepo) GetAround(ctx context.Context, sessionID primitive.ObjectID, at time.Time, before, after int) ([]*model.ChatMessage, []*model.ChatMessage, error) {
+	find := func(op string, order, limit int) ([]*model.ChatMessage, error) {
+		if limit <= 0 {
+			return []*model.ChatMessage{}, nil
+		}
+
+		filter := bson.M{"session_id": sessionID, "created_at": bson.M{op: at}}
+		opts := options.Find().
+			SetSort(bson.D{{Key: "created_at", Value: order}}).
+			SetLimit(int64(limit))
+
+		cursor, err := r.collection.Find(ctx, filter, opts)
+		if err != nil {
+			return nil, err
+		}
+		defer cursor.Close(ctx)
+
+		var messages []*model.ChatMessage
+		if err := cursor.All(ctx, &messages); err != nil {
+			return nil, err
+		}
+		return messages, nil
+	}
+
+	prev, err := find("$lt", -1, before)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Fetched newest first to get the closest ones, reverse to chronological order
+	for i, j := 0, len(prev)-1; i < j; i, j = i+1, j-1 {
+		prev[i], prev[j] = prev[j], prev[i]
+	}
+
+	next, err := find("$gt", 1, after)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return prev, next, nil
+}
+
+// MarkDeadLinks flags the sources in a message's metadata whose URL is one of urls
+func (r *chatMessageRepo) MarkDeadLinks(ctx context.Context, messageID primitive.ObjectID, urls []string) error {
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": messageID},
+		bson.M{"$set": bson.M{"metadata.sources.$[source].dead_link": true}},
+		options.Update().SetArrayFilters(options.ArrayFilters{
+			Filters: []interface{}{bson.M{"source.url": bson.M{"$in": urls}}},
+		}),
+	)
+	return err
+}
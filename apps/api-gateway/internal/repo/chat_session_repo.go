@@ -18,10 +18,16 @@ type ChatSessionRepo interface {
 	Create(ctx context.Context, session *model.ChatSession) (*model.ChatSession, error)
 	GetByID(ctx context.Context, id string) (*model.ChatSession, error)
 	GetByUserID(ctx context.Context, userID string, opts *FindOptions) ([]*model.ChatSession, error)
+	GetSummariesByUserID(ctx context.Context, userID string, page Page) ([]*model.ChatSessionSummary, *Cursor, error)
 	Update(ctx context.Context, session *model.ChatSession) (*model.ChatSession, error)
+	UpdateLanguageField(ctx context.Context, id string, language string) (*model.ChatSession, error)
 	Delete(ctx context.Context, id string) error // Soft delete
 	HardDelete(ctx context.Context, id string) error
+	GetAllIDsByUserID(ctx context.Context, userID string) ([]primitive.ObjectID, error)
+	GetByIDsIncludingDeleted(ctx context.Context, ids []primitive.ObjectID) ([]*model.ChatSession, error)
+	DeleteByUserID(ctx context.Context, userID string) (int64, error)
 	CountByUserID(ctx context.Context, userID string) (int64, error)
+	CountCreatedPerDay(ctx context.Context, since time.Time) ([]*model.DailyCount, error)
 }
 
 type chatSessionRepo struct {
@@ -96,12 +102,7 @@ func (r *chatSessionRepo) GetByUserID(ctx context.Context, userID string, opts *
 			findOpts.SetSkip(opts.Skip)
 		}
 		if opts.Sort != nil {
-			// Convert map to bson.D for MongoDB
-			sortDoc := bson.D{}
-			for k, v := range opts.Sort {
-				sortDoc = append(sortDoc, bson.E{Key: k, Value: v})
-			}
-			findOpts.SetSort(sortDoc)
+			findOpts.SetSort(opts.Sort)
 		}
 	}
 
@@ -119,6 +120,64 @@ func (r *chatSessionRepo) GetByUserID(ctx context.Context, userID string, opts *
 	return sessions, nil
 }
 
+// GetSummariesByUserID retrieves a page of a user's sessions (excluding soft-deleted) joined with
+// their latest message and message count in a single aggregation.
+// The returned cursor is nil on the last page.
+func (r *chatSessionRepo) GetSummariesByUserID(ctx context.Context, userID string, page Page) ([]*model.ChatSessionSummary, *Cursor, error) {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Paginate before the lookups so we only join the sessions being returned
+	pipeline := mongo.Pipeline(page.Stages(bson.M{"user_id": objectID, "deleted_at": nil}))
+
+	pipeline = append(pipeline,
+		// Latest message of the session
+		bson.D{{Key: "$lookup", Value: bson.M{
+			"from": config.ChatMessageColName,
+			"let":  bson.M{"sid": "$_id"},
+			"pipeline": bson.A{
+				bson.M{"$match": bson.M{"$expr": bson.M{"$eq": bson.A{"$session_id", "$$sid"}}}},
+				bson.M{"$sort": bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+				bson.M{"$limit": 1},
+			},
+			"as": "last_messages",
+		}}},
+		// Message count of the session
+		bson.D{{Key: "$lookup", Value: bson.M{
+			"from": config.ChatMessageColName,
+			"let":  bson.M{"sid": "$_id"},
+			"pipeline": bson.A{
+				bson.M{"$match": bson.M{"$expr": bson.M{"$eq": bson.A{"$session_id", "$$sid"}}}},
+				bson.M{"$count": "count"},
+			},
+			"as": "message_counts",
+		}}},
+		bson.D{{Key: "$addFields", Value: bson.M{
+			"last_message":  bson.M{"$arrayElemAt": bson.A{"$last_messages", 0}},
+			"message_count": bson.M{"$ifNull": bson.A{bson.M{"$arrayElemAt": bson.A{"$message_counts.count", 0}}, 0}},
+		}}},
+		bson.D{{Key: "$project", Value: bson.M{"last_messages": 0, "message_counts": 0}}},
+	)
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var summaries []*model.ChatSessionSummary
+	if err = cursor.All(ctx, &summaries); err != nil {
+		return nil, nil, err
+	}
+
+	summaries, next := paginate(summaries, page, func(s *model.ChatSessionSummary) Cursor {
+		return Cursor{Value: s.UpdatedAt, ID: s.ID}
+	})
+	return summaries, next, nil
+}
+
 // Update updates a chat session
 func (r *chatSessionRepo) Update(ctx context.Context, session *model.ChatSession) (*model.ChatSession, error) {
 	session.UpdatedAt = time.Now()
@@ -145,6 +204,50 @@ func (r *chatSessionRepo) Update(ctx context.Context, session *model.ChatSession
 	return &updated, nil
 }
 
+// UpdateLanguageField sets the session language override, or removes it when language is empty
+func (r *chatSessionRepo) UpdateLanguageField(ctx context.Context, id string, language string) (*model.ChatSession, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{"_id": objectID, "deleted_at": nil}
+	var update bson.M
+
+	if language == "" {
+		// Use $unset so the session falls back to the user's default language
+		update = bson.M{
+			"$unset": bson.M{"language": ""},
+			"$set":   bson.M{"updated_at": time.Now()},
+		}
+	} else {
+		update = bson.M{
+			"$set": bson.M{
+				"language":   language,
+				"updated_at": time.Now(),
+			},
+		}
+	}
+
+	result := r.collection.FindOneAndUpdate(
+		ctx,
+		filter,
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	)
+
+	if result.Err() != nil {
+		return nil, result.Err()
+	}
+
+	var updated model.ChatSession
+	if err := result.Decode(&updated); err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
 // Delete soft deletes a chat session
 func (r *chatSessionRepo) Delete(ctx context.Context, id string) error {
 	objectID, err := primitive.ObjectIDFromHex(id)
@@ -188,6 +291,63 @@ func (r *chatSessionRepo) HardDelete(ctx context.Context, id string) error {
 	return nil
 }
 
+// GetByIDsIncludingDeleted retrieves sessions by ID, soft-deleted ones included
+func (r *chatSessionRepo) GetByIDsIncludingDeleted(ctx context.Context, ids []primitive.ObjectID) ([]*model.ChatSession, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var sessions []*model.ChatSession
+	if err := cursor.All(ctx, &sessions); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// GetAllIDsByUserID returns the IDs of all of a user's sessions, including soft-deleted ones
+func (r *chatSessionRepo) GetAllIDsByUserID(ctx context.Context, userID string) ([]primitive.ObjectID, error) {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := r.collection.Find(ctx, bson.M{"user_id": objectID}, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+
+	ids := make([]primitive.ObjectID, len(docs))
+	for i, doc := range docs {
+		ids[i] = doc.ID
+	}
+	return ids, nil
+}
+
+// DeleteByUserID permanently deletes all of a user's sessions, including soft-deleted ones
+func (r *chatSessionRepo) DeleteByUserID(ctx context.Context, userID string) (int64, error) {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := r.collection.DeleteMany(ctx, bson.M{"user_id": objectID})
+	if err != nil {
+		return 0, err
+	}
+
+	return result.DeletedCount, nil
+}
+
 // CountByUserID counts chat sessions for a user (excluding soft-deleted)
 func (r *chatSessionRepo) CountByUserID(ctx context.Context, userID string) (int64, error) {
 	objectID, err := primitive.ObjectIDFromHex(userID)
@@ -207,3 +367,8 @@ func (r *chatSessionRepo) CountByUserID(ctx context.Context, userID string) (int
 
 	return count, nil
 }
+
+// CountCreatedPerDay counts sessions created per day since the given time, including soft-deleted ones
+func (r *chatSessionRepo) CountCreatedPerDay(ctx context.Context, since time.Time) ([]*model.DailyCount, error) {
+	return countPerDay(ctx, r.collection, nil, since)
+}
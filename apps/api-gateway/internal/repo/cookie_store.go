@@ -0,0 +1,154 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+	"github.com/redis/go-redis/v9"
+)
+
+// CookieStore stores the UIT portal cookies synced by the extension, by source and user.
+// The agent reads the cookies from the same place, so an implementation change must be mirrored there.
+type CookieStore interface {
+	Save(ctx context.Context, userID, source, cookie string, ttl time.Duration) error
+	// Get returns the stored cookie, or ok = false if none is stored
+	Get(ctx context.Context, userID, source string) (cookie string, ok bool, err error)
+	// TTL returns how long the stored cookie stays valid, or ok = false if none is stored
+	TTL(ctx context.Context, userID, source string) (ttl time.Duration, ok bool, err error)
+	// Delete removes the user's cookie of a source, reporting whether one was stored
+	Delete(ctx context.Context, userID, source string) (bool, error)
+	DeleteAll(ctx context.Context, userID string) (int64, error)
+	// UserIDs returns the users who have a cookie of the source stored
+	UserIDs(ctx context.Context, source string) ([]string, error)
+	// ClaimExpired returns up to limit cookies that expired by now without being synced again. Each expired
+	// cookie is returned once, even across instances.
+	ClaimExpired(ctx context.Context, now time.Time, limit int64) ([]StoredCookie, error)
+}
+
+// StoredCookie identifies a synced cookie
+type StoredCookie struct {
+	UserID string
+	Source string
+}
+
+type redisCookieStore struct {
+	redisClient *redis.Client
+}
+
+// NewRedisCookieStore creates a cookie store keeping cookies in Redis with an expiry
+func NewRedisCookieStore(redisClient *redis.Client) CookieStore {
+	return &redisCookieStore{redisClient: redisClient}
+}
+
+func (s *redisCookieStore) Save(ctx context.Context, userID, source, cookie string, ttl time.Duration) error {
+	pipe := s.redisClient.TxPipeline()
+	pipe.Set(ctx, cookieKey(source, userID), cookie, ttl)
+	pipe.ZAdd(ctx, config.RedisCookieExpiryKey, redis.Z{Score: float64(time.Now().Add(ttl).Unix()), Member: expiryMember(source, userID)})
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (s *redisCookieStore) Get(ctx context.Context, userID, source string) (string, bool, error) {
+	cookie, err := s.redisClient.Get(ctx, cookieKey(source, userID)).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return cookie, true, nil
+}
+
+func (s *redisCookieStore) TTL(ctx context.Context, userID, source string) (time.Duration, bool, error) {
+	key := cookieKey(source, userID)
+
+	exists, err := s.redisClient.Exists(ctx, key).Result()
+	if err != nil || exists == 0 {
+		return 0, false, err
+	}
+
+	ttl, err := s.redisClient.TTL(ctx, key).Result()
+	if err != nil {
+		return 0, false, err
+	}
+	return ttl, true, nil
+}
+
+func (s *redisCookieStore) Delete(ctx context.Context, userID, source string) (bool, error) {
+	pipe := s.redisClient.TxPipeline()
+	deleted := pipe.Del(ctx, cookieKey(source, userID))
+	pipe.ZRem(ctx, config.RedisCookieExpiryKey, expiryMember(source, userID))
+	if _, err := pipe.Exec(ctx); err != nil {
+		return false, err
+	}
+	return deleted.Val() > 0, nil
+}
+
+// DeleteAll removes the user's cookies of every source and returns how many were stored
+func (s *redisCookieStore) DeleteAll(ctx context.Context, userID string) (int64, error) {
+	keys := make([]string, 0, len(config.CookieSources))
+	members := make([]interface{}, 0, len(config.CookieSources))
+	for _, source := range config.CookieSources {
+		keys = append(keys, cookieKey(source, userID))
+		members = append(members, expiryMember(source, userID))
+	}
+
+	pipe := s.redisClient.TxPipeline()
+	deleted := pipe.Del(ctx, keys...)
+	pipe.ZRem(ctx, config.RedisCookieExpiryKey, members...)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return deleted.Val(), nil
+}
+
+func (s *redisCookieStore) UserIDs(ctx context.Context, source string) ([]string, error) {
+	prefix := cookieKey(source, "")
+
+	var userIDs []string
+	iter := s.redisClient.Scan(ctx, 0, prefix+"*", 1000).Iterator()
+	for iter.Next(ctx) {
+		userIDs = append(userIDs, strings.TrimPrefix(iter.Val(), prefix))
+	}
+	return userIDs, iter.Err()
+}
+
+func (s *redisCookieStore) ClaimExpired(ctx context.Context, now time.Time, limit int64) ([]StoredCookie, error) {
+	members, err := s.redisClient.ZRangeByScore(ctx, config.RedisCookieExpiryKey, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(now.Unix(), 10),
+		Count: limit,
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	var expired []StoredCookie
+	for _, member := range members {
+		// Whichever instance removes the member owns it
+		removed, err := s.redisClient.ZRem(ctx, config.RedisCookieExpiryKey, member).Result()
+		if err != nil {
+			return expired, err
+		}
+		source, userID, ok := strings.Cut(member, ":")
+		if removed == 0 || !ok {
+			continue
+		}
+		expired = append(expired, StoredCookie{UserID: userID, Source: source})
+	}
+	return expired, nil
+}
+
+// expiryMember is the member of a cookie in the expiry sorted set
+func expiryMember(source, userID string) string {
+	return source + ":" + userID
+}
+
+func cookieKey(source, userID string) string {
+	return fmt.Sprintf(config.RedisCookieKey, source, userID)
+}
@@ -0,0 +1,178 @@
+package repo
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor marks a position in a list sorted by one field, then by _id in the same direction.
+// The _id tie-breaker keeps pages stable when several documents share the sort value.
+// Value is the sort field of the document at the position: a time.Time, a string, or nil if it has none.
+type Cursor struct {
+	Value any
+	ID    primitive.ObjectID
+}
+
+// Kinds of cursor values, the first segment of an encoded cursor
+const (
+	cursorTime   = "t"
+	cursorString = "s"
+	cursorNull   = "n"
+)
+
+// Encode returns an opaque, URL-safe representation of the cursor
+func (c Cursor) Encode() string {
+	var kind, value string
+	switch v := c.Value.(type) {
+	case time.Time:
+		kind, value = cursorTime, strconv.FormatInt(v.UnixMilli(), 10)
+	case string:
+		kind, value = cursorString, v
+	default:
+		kind = cursorNull
+	}
+
+	// The value goes last, a string value may itself contain ':'
+	raw := kind + ":" + c.ID.Hex() + ":" + value
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor parses a cursor produced by Encode
+func DecodeCursor(encoded string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	parts := strings.SplitN(string(raw), ":", 3)
+	if len(parts) != 3 {
+		return nil, ErrInvalidCursor
+	}
+	id, err := primitive.ObjectIDFromHex(parts[1])
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	cursor := &Cursor{ID: id}
+	switch parts[0] {
+	case cursorTime:
+		ms, err := strconv.ParseInt(parts[2], 10, 64)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		cursor.Value = time.UnixMilli(ms)
+	case cursorString:
+		cursor.Value = parts[2]
+	case cursorNull:
+	default:
+		return nil, ErrInvalidCursor
+	}
+	return cursor, nil
+}
+
+// Page selects one page of a list sorted by Field, then by _id, both in Order
+type Page struct {
+	Field string
+	Order int     // 1 ascending, -1 descending
+	After *Cursor // nil starts from the first document
+	Skip  int64   // Documents skipped after the cursor, for clients still paging by page number
+	Limit int
+}
+
+// Match adds the condition selecting documents after the cursor to filter
+func (p Page) Match(filter bson.M) bson.M {
+	if p.After == nil {
+		return filter
+	}
+	return bson.M{"$and": bson.A{filter, p.afterCursor()}}
+}
+
+// afterCursor matches documents that come after the cursor. MongoDB sorts a missing or null field
+// before any value, so null values come first in ascending order and last in descending order.
+func (p Page) afterCursor() bson.M {
+	op := "$gt"
+	if p.Order < 0 {
+		op = "$lt"
+	}
+	value, id := p.After.Value, p.After.ID
+
+	if value == nil {
+		sameValue := bson.M{p.Field: nil, "_id": bson.M{op: id}}
+		if p.Order < 0 {
+			return sameValue
+		}
+		return bson.M{"$or": bson.A{sameValue, bson.M{p.Field: bson.M{"$ne": nil}}}}
+	}
+
+	branches := bson.A{
+		bson.M{p.Field: bson.M{op: value}},
+		bson.M{p.Field: value, "_id": bson.M{op: id}},
+	}
+	if p.Order < 0 {
+		branches = append(branches, bson.M{p.Field: nil})
+	}
+	return bson.M{"$or": branches}
+}
+
+// Sort returns the sort order of the page
+func (p Page) Sort() bson.D {
+	return bson.D{{Key: p.Field, Value: p.Order}, {Key: "_id", Value: p.Order}}
+}
+
+// FindOptions sorts and limits a find to the page. One extra document is fetched to know whether
+// another page exists, paginate trims it.
+func (p Page) FindOptions() *options.FindOptions {
+	opts := options.Find().SetSort(p.Sort()).SetLimit(int64(p.Limit + 1))
+	if p.Skip > 0 {
+		opts.SetSkip(p.Skip)
+	}
+	return opts
+}
+
+// Stages returns the aggregation stages selecting the page from documents matching filter
+func (p Page) Stages(filter bson.M) []bson.D {
+	return append([]bson.D{{{Key: "$match", Value: p.Match(filter)}}}, p.sortAndLimit()...)
+}
+
+// facetStages returns the stages selecting the page from documents already matching the filter and sorted,
+// for findPage. Documents before the cursor are skipped here so the total still counts every match.
+func (p Page) facetStages() []bson.D {
+	stages := []bson.D{}
+	if p.After != nil {
+		stages = append(stages, bson.D{{Key: "$match", Value: p.afterCursor()}})
+	}
+	if p.Skip > 0 {
+		stages = append(stages, bson.D{{Key: "$skip", Value: p.Skip}})
+	}
+	return append(stages, bson.D{{Key: "$limit", Value: p.Limit + 1}})
+}
+
+// sortAndLimit sorts documents and limits them to the page, plus the extra document paginate trims
+func (p Page) sortAndLimit() []bson.D {
+	stages := []bson.D{{{Key: "$sort", Value: p.Sort()}}}
+	if p.Skip > 0 {
+		stages = append(stages, bson.D{{Key: "$skip", Value: p.Skip}})
+	}
+	return append(stages, bson.D{{Key: "$limit", Value: p.Limit + 1}})
+}
+
+// paginate trims the extra document fetched for the page and returns the cursor of the next page,
+// nil on the last page. key gives the cursor positioned at a document.
+func paginate[T any](items []T, p Page, key func(T) Cursor) ([]T, *Cursor) {
+	if len(items) <= p.Limit {
+		return items, nil
+	}
+
+	items = items[:p.Limit]
+	next := key(items[p.Limit-1])
+	return items, &next
+}
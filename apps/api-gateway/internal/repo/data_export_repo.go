@@ -0,0 +1,281 @@
+package repo
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// DataExportRepo defines the interface for data export repository.
+// Archives are stored in GridFS next to the export documents.
+type DataExportRepo interface {
+	Create(ctx context.Context, export *model.DataExport) (*model.DataExport, error)
+	GetByID(ctx context.Context, id string) (*model.DataExport, error)
+	GetLatestByUserID(ctx context.Context, userID string) (*model.DataExport, error)
+	ClaimNext(ctx context.Context, staleBefore time.Time) (*model.DataExport, error)
+	MarkReady(ctx context.Context, id, fileID primitive.ObjectID, sizeBytes int64, completedAt, expiresAt time.Time) error
+	MarkFailed(ctx context.Context, id primitive.ObjectID, reason string) error
+	GetExpiredReady(ctx context.Context, now time.Time, limit int) ([]*model.DataExport, error)
+	MarkExpired(ctx context.Context, id primitive.ObjectID) error
+	DeleteByUserID(ctx context.Context, userID string) (int64, error)
+
+	UploadArchive(ctx context.Context, filename string, write func(w io.Writer) error) (primitive.ObjectID, int64, error)
+	OpenArchive(ctx context.Context, fileID primitive.ObjectID) (io.ReadCloser, int64, error)
+	DeleteArchive(ctx context.Context, fileID primitive.ObjectID) error
+}
+
+type dataExportRepo struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+// NewDataExportRepo creates a new data export repository
+func NewDataExportRepo(db *mongo.Database) DataExportRepo {
+	return &dataExportRepo{
+		db:         db,
+		collection: db.Collection(config.DataExportColName),
+	}
+}
+
+// bucket returns the GridFS bucket of the archives. A bucket is not safe for concurrent use, so each call gets its own.
+func (r *dataExportRepo) bucket() (*gridfs.Bucket, error) {
+	return gridfs.NewBucket(r.db, options.GridFSBucket().SetName(config.DataExportArchiveBucket))
+}
+
+func (r *dataExportRepo) Create(ctx context.Context, export *model.DataExport) (*model.DataExport, error) {
+	export.CreatedAt = time.Now()
+
+	result, err := r.collection.InsertOne(ctx, export)
+	if err != nil {
+		return nil, err
+	}
+
+	export.ID = result.InsertedID.(primitive.ObjectID)
+	return export, nil
+}
+
+func (r *dataExportRepo) GetByID(ctx context.Context, id string) (*model.DataExport, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var export model.DataExport
+	if err := r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&export); err != nil {
+		return nil, err
+	}
+
+	return &export, nil
+}
+
+// GetLatestByUserID returns the user's most recent export, or mongo.ErrNoDocuments if they never requested one
+func (r *dataExportRepo) GetLatestByUserID(ctx context.Context, userID string) (*model.DataExport, error) {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := options.FindOne().SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	var export model.DataExport
+	if err := r.collection.FindOne(ctx, bson.M{"user_id": objectID}, opts).Decode(&export); err != nil {
+		return nil, err
+	}
+
+	return &export, nil
+}
+
+// ClaimNext atomically moves the oldest pending export to processing so only one worker builds it.
+// Exports that started processing before staleBefore are claimed again.
+// Returns mongo.ErrNoDocuments when nothing is waiting.
+func (r *dataExportRepo) ClaimNext(ctx context.Context, staleBefore time.Time) (*model.DataExport, error) {
+	filter := bson.M{
+		"$or": bson.A{
+			bson.M{"status": model.DataExportStatusPending},
+			bson.M{"status": model.DataExportStatusProcessing, "started_at": bson.M{"$lt": staleBefore}},
+		},
+	}
+	update := bson.M{"$set": bson.M{
+		"status":     model.DataExportStatusProcessing,
+		"started_at": time.Now(),
+	}}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "created_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var export model.DataExport
+	if err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&export); err != nil {
+		return nil, err
+	}
+
+	return &export, nil
+}
+
+func (r *dataExportRepo) MarkReady(ctx context.Context, id, fileID primitive.ObjectID, sizeBytes int64, completedAt, expiresAt time.Time) error {
+	update := bson.M{"$set": bson.M{
+		"status":       model.DataExportStatusReady,
+		"file_id":      fileID,
+		"size_bytes":   sizeBytes,
+		"completed_at": completedAt,
+		"expires_at":   expiresAt,
+	}}
+	_, err := r.collection.UpdateByID(ctx, id, update)
+	return err
+}
+
+func (r *dataExportRepo) MarkFailed(ctx context.Context, id primitive.ObjectID, reason string) error {
+	update := bson.M{"$set": bson.M{
+		"status":       model.DataExportStatusFailed,
+		"error":        reason,
+		"completed_at": time.Now(),
+	}}
+	_, err := r.collection.UpdateByID(ctx, id, update)
+	return err
+}
+
+// GetExpiredReady returns up to limit ready exports whose archive has expired
+func (r *dataExportRepo) GetExpiredReady(ctx context.Context, now time.Time, limit int) ([]*model.DataExport, error) {
+	filter := bson.M{
+		"status":     model.DataExportStatusReady,
+		"expires_at": bson.M{"$lte": now},
+	}
+
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetLimit(int64(limit)))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var exports []*model.DataExport
+	if err := cursor.All(ctx, &exports); err != nil {
+		return nil, err
+	}
+
+	return exports, nil
+}
+
+func (r *dataExportRepo) MarkExpired(ctx context.Context, id primitive.ObjectID) error {
+	update := bson.M{
+		"$set":   bson.M{"status": model.DataExportStatusExpired},
+		"$unset": bson.M{"file_id": ""},
+	}
+	_, err := r.collection.UpdateByID(ctx, id, update)
+	return err
+}
+
+// DeleteByUserID removes all exports of a user together with their archives
+func (r *dataExportRepo) DeleteByUserID(ctx context.Context, userID string) (int64, error) {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return 0, err
+	}
+
+	filter := bson.M{"user_id": objectID}
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	var exports []*model.DataExport
+	if err := cursor.All(ctx, &exports); err != nil {
+		return 0, err
+	}
+
+	for _, export := range exports {
+		if export.FileID == nil {
+			continue
+		}
+		if err := r.DeleteArchive(ctx, *export.FileID); err != nil {
+			return 0, err
+		}
+	}
+
+	result, err := r.collection.DeleteMany(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.DeletedCount, nil
+}
+
+// UploadArchive stores the bytes produced by write as a new GridFS file and returns its ID and size
+func (r *dataExportRepo) UploadArchive(ctx context.Context, filename string, write func(w io.Writer) error) (primitive.ObjectID, int64, error) {
+	bucket, err := r.bucket()
+	if err != nil {
+		return primitive.NilObjectID, 0, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := bucket.SetWriteDeadline(deadline); err != nil {
+			return primitive.NilObjectID, 0, err
+		}
+	}
+
+	stream, err := bucket.OpenUploadStream(filename)
+	if err != nil {
+		return primitive.NilObjectID, 0, err
+	}
+
+	counter := &countingWriter{w: stream}
+	if err := write(counter); err != nil {
+		_ = stream.Abort()
+		return primitive.NilObjectID, 0, err
+	}
+	if err := stream.Close(); err != nil {
+		return primitive.NilObjectID, 0, err
+	}
+
+	return stream.FileID.(primitive.ObjectID), counter.n, nil
+}
+
+// OpenArchive opens a stored archive for reading. Returns gridfs.ErrFileNotFound if it was deleted.
+func (r *dataExportRepo) OpenArchive(ctx context.Context, fileID primitive.ObjectID) (io.ReadCloser, int64, error) {
+	bucket, err := r.bucket()
+	if err != nil {
+		return nil, 0, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := bucket.SetReadDeadline(deadline); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	stream, err := bucket.OpenDownloadStream(fileID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return stream, stream.GetFile().Length, nil
+}
+
+// DeleteArchive removes a stored archive. A missing file is not an error.
+func (r *dataExportRepo) DeleteArchive(ctx context.Context, fileID primitive.ObjectID) error {
+	bucket, err := r.bucket()
+	if err != nil {
+		return err
+	}
+
+	if err := bucket.DeleteContext(ctx, fileID); err != nil && err != gridfs.ErrFileNotFound {
+		return err
+	}
+	return nil
+}
+
+// countingWriter counts the bytes written through it
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
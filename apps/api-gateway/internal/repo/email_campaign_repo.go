@@ -0,0 +1,175 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// EmailCampaignRepo defines the interface for email campaign repository
+type EmailCampaignRepo interface {
+	Create(ctx context.Context, campaign *model.EmailCampaign) (*model.EmailCampaign, error)
+	GetByID(ctx context.Context, id string) (*model.EmailCampaign, error)
+	Find(ctx context.Context, page, pageSize int) ([]*model.EmailCampaign, int64, error)
+	Update(ctx context.Context, campaign *model.EmailCampaign) (*model.EmailCampaign, error)
+	MarkSending(ctx context.Context, id primitive.ObjectID, now time.Time) error
+	ClaimUnqueued(ctx context.Context, now time.Time) (*model.EmailCampaign, error)
+	SaveQueueProgress(ctx context.Context, id primitive.ObjectID, lastQueuedID primitive.ObjectID, queued int64, now time.Time) error
+	SetQueued(ctx context.Context, id primitive.ObjectID, queuedAt time.Time) error
+	IncrementCounts(ctx context.Context, id primitive.ObjectID, sent, failed int64) error
+	Complete(ctx context.Context, id primitive.ObjectID, completedAt time.Time) error
+}
+
+type emailCampaignRepo struct {
+	collection *mongo.Collection
+}
+
+// NewEmailCampaignRepo creates a new email campaign repository
+func NewEmailCampaignRepo(db *mongo.Database) EmailCampaignRepo {
+	return &emailCampaignRepo{collection: db.Collection(config.EmailCampaignColName)}
+}
+
+// Create creates a new campaign
+func (r *emailCampaignRepo) Create(ctx context.Context, campaign *model.EmailCampaign) (*model.EmailCampaign, error) {
+	campaign.CreatedAt = time.Now()
+	campaign.UpdatedAt = time.Now()
+
+	result, err := r.collection.InsertOne(ctx, campaign)
+	if err != nil {
+		return nil, err
+	}
+
+	campaign.ID = result.InsertedID.(primitive.ObjectID)
+	return campaign, nil
+}
+
+// GetByID retrieves a campaign by ID
+func (r *emailCampaignRepo) GetByID(ctx context.Context, id string) (*model.EmailCampaign, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var campaign model.EmailCampaign
+	if err := r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&campaign); err != nil {
+		return nil, err
+	}
+
+	return &campaign, nil
+}
+
+// Find retrieves a page of campaigns, newest first
+func (r *emailCampaignRepo) Find(ctx context.Context, page, pageSize int) ([]*model.EmailCampaign, int64, error) {
+	return findPage[*model.EmailCampaign](ctx, r.collection, bson.M{}, bson.D{{Key: "created_at", Value: -1}}, offsetStages(page, pageSize))
+}
+
+// Update replaces a campaign
+func (r *emailCampaignRepo) Update(ctx context.Context, campaign *model.EmailCampaign) (*model.EmailCampaign, error) {
+	campaign.UpdatedAt = time.Now()
+
+	result, err := r.collection.ReplaceOne(ctx, bson.M{"_id": campaign.ID}, campaign)
+	if err != nil {
+		return nil, err
+	}
+	if result.MatchedCount == 0 {
+		return nil, mongo.ErrNoDocuments
+	}
+
+	return campaign, nil
+}
+
+// MarkSending moves a draft campaign to sending, for the sender loop to queue its recipients.
+// Returns mongo.ErrNoDocuments if the campaign is not a draft, e.g. another request already sent it.
+func (r *emailCampaignRepo) MarkSending(ctx context.Context, id primitive.ObjectID, now time.Time) error {
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "status": model.EmailCampaignStatusDraft},
+		bson.M{"$set": bson.M{
+			"status":     model.EmailCampaignStatusSending,
+			"updated_at": now,
+		}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// ClaimUnqueued claims a sending campaign whose recipients are not all queued: one no worker has started
+// queueing yet, or one whose queueing made no progress for claimTimeout because its worker stopped.
+// Returns mongo.ErrNoDocuments when there is none.
+func (r *emailCampaignRepo) ClaimUnqueued(ctx context.Context, now time.Time) (*model.EmailCampaign, error) {
+	filter := bson.M{
+		"status":    model.EmailCampaignStatusSending,
+		"queued_at": bson.M{"$exists": false},
+		"$or": bson.A{
+			bson.M{"queueing_at": bson.M{"$exists": false}},
+			bson.M{"queueing_at": bson.M{"$lte": now.Add(-claimTimeout)}},
+		},
+	}
+	update := bson.M{"$set": bson.M{"queueing_at": now, "updated_at": now}}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var campaign model.EmailCampaign
+	if err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&campaign); err != nil {
+		return nil, err
+	}
+
+	return &campaign, nil
+}
+
+// SaveQueueProgress records a queued batch: the last user it covered and how many deliveries it added
+func (r *emailCampaignRepo) SaveQueueProgress(ctx context.Context, id primitive.ObjectID, lastQueuedID primitive.ObjectID, queued int64, now time.Time) error {
+	_, err := r.collection.UpdateByID(ctx, id, bson.M{
+		"$inc": bson.M{"recipient_count": queued},
+		"$set": bson.M{
+			"last_queued_id": lastQueuedID,
+			"queueing_at":    now,
+			"updated_at":     now,
+		},
+	})
+	return err
+}
+
+// SetQueued marks queueing as finished
+func (r *emailCampaignRepo) SetQueued(ctx context.Context, id primitive.ObjectID, queuedAt time.Time) error {
+	_, err := r.collection.UpdateByID(ctx, id, bson.M{
+		"$set": bson.M{
+			"queued_at":  queuedAt,
+			"updated_at": queuedAt,
+		},
+	})
+	return err
+}
+
+// IncrementCounts adds delivery outcomes to the campaign counters
+func (r *emailCampaignRepo) IncrementCounts(ctx context.Context, id primitive.ObjectID, sent, failed int64) error {
+	_, err := r.collection.UpdateByID(ctx, id, bson.M{
+		"$inc": bson.M{"sent_count": sent, "failed_count": failed},
+		"$set": bson.M{"updated_at": time.Now()},
+	})
+	return err
+}
+
+// Complete marks a sending campaign as sent. Campaigns in any other state are left untouched.
+func (r *emailCampaignRepo) Complete(ctx context.Context, id primitive.ObjectID, completedAt time.Time) error {
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "status": model.EmailCampaignStatusSending},
+		bson.M{"$set": bson.M{
+			"status":       model.EmailCampaignStatusSent,
+			"completed_at": completedAt,
+			"updated_at":   completedAt,
+		}},
+	)
+	return err
+}
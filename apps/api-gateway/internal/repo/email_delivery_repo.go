@@ -0,0 +1,175 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// EmailDeliveryRepo is the queue of campaign emails, one document per recipient
+type EmailDeliveryRepo interface {
+	CreateMany(ctx context.Context, deliveries []*model.EmailDelivery) (int64, error)
+	Find(ctx context.Context, campaignID primitive.ObjectID, status model.EmailDeliveryStatus, page, pageSize int) ([]*model.EmailDelivery, int64, error)
+	ClaimPending(ctx context.Context, now time.Time) (*model.EmailDelivery, error)
+	MarkQueued(ctx context.Context, id primitive.ObjectID) error
+	MarkSent(ctx context.Context, id primitive.ObjectID, sentAt time.Time) (*model.EmailDelivery, error)
+	MarkFailed(ctx context.Context, id primitive.ObjectID, reason string) (*model.EmailDelivery, error)
+	CountUnfinished(ctx context.Context, campaignID primitive.ObjectID) (int64, error)
+	DeleteByUserID(ctx context.Context, userID string) (int64, error)
+}
+
+type emailDeliveryRepo struct {
+	collection *mongo.Collection
+}
+
+// NewEmailDeliveryRepo creates a new email delivery repository
+func NewEmailDeliveryRepo(db *mongo.Database) EmailDeliveryRepo {
+	return &emailDeliveryRepo{collection: db.Collection(config.EmailDeliveryColName)}
+}
+
+// CreateMany queues deliveries as pending and returns how many were added. A campaign holds one delivery
+// per user, deliveries already queued by an earlier attempt at the batch are skipped.
+func (r *emailDeliveryRepo) CreateMany(ctx context.Context, deliveries []*model.EmailDelivery) (int64, error) {
+	if len(deliveries) == 0 {
+		return 0, nil
+	}
+
+	now := time.Now()
+	docs := make([]interface{}, len(deliveries))
+	for i, d := range deliveries {
+		d.Status = model.EmailDeliveryPending
+		d.CreatedAt = now
+		d.UpdatedAt = now
+		docs[i] = d
+	}
+
+	_, err := r.collection.InsertMany(ctx, docs, options.InsertMany().SetOrdered(false))
+	var bulkErr mongo.BulkWriteException
+	if errors.As(err, &bulkErr) && bulkErr.WriteConcernError == nil {
+		for _, writeErr := range bulkErr.WriteErrors {
+			if !mongo.IsDuplicateKeyError(writeErr) {
+				return 0, err
+			}
+		}
+		return int64(len(docs) - len(bulkErr.WriteErrors)), nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(docs)), nil
+}
+
+// Find retrieves a page of a campaign's deliveries, optionally filtered by status
+func (r *emailDeliveryRepo) Find(ctx context.Context, campaignID primitive.ObjectID, status model.EmailDeliveryStatus, page, pageSize int) ([]*model.EmailDelivery, int64, error) {
+	filter := bson.M{"campaign_id": campaignID}
+	if status != "" {
+		filter["status"] = status
+	}
+
+	return findPage[*model.EmailDelivery](ctx, r.collection, filter, bson.D{{Key: "_id", Value: 1}}, offsetStages(page, pageSize))
+}
+
+// ClaimPending atomically moves the oldest pending delivery to processing so only one sender sends it.
+// Deliveries stuck in processing longer than claimTimeout are claimed again.
+// Returns mongo.ErrNoDocuments when the queue is empty.
+func (r *emailDeliveryRepo) ClaimPending(ctx context.Context, now time.Time) (*model.EmailDelivery, error) {
+	filter := bson.M{
+		"$or": bson.A{
+			bson.M{"status": model.EmailDeliveryPending},
+			bson.M{"status": model.EmailDeliveryProcessing, "updated_at": bson.M{"$lte": now.Add(-claimTimeout)}},
+		},
+	}
+	update := bson.M{"$set": bson.M{
+		"status":     model.EmailDeliveryProcessing,
+		"updated_at": now,
+	}}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var delivery model.EmailDelivery
+	if err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&delivery); err != nil {
+		return nil, err
+	}
+
+	return &delivery, nil
+}
+
+// MarkQueued moves a claimed delivery to queued once it was handed to the email queue. A delivery the email
+// queue already reported on is left untouched.
+func (r *emailDeliveryRepo) MarkQueued(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "status": model.EmailDeliveryProcessing},
+		bson.M{"$set": bson.M{
+			"status":     model.EmailDeliveryQueued,
+			"updated_at": time.Now(),
+		}},
+	)
+	return err
+}
+
+// MarkSent marks a delivery as sent and returns it as it was before. A failed delivery can still be sent when its
+// email is queued again. Returns mongo.ErrNoDocuments when the delivery was already sent.
+func (r *emailDeliveryRepo) MarkSent(ctx context.Context, id primitive.ObjectID, sentAt time.Time) (*model.EmailDelivery, error) {
+	return r.finish(ctx,
+		bson.M{"_id": id, "status": bson.M{"$ne": model.EmailDeliverySent}},
+		bson.M{
+			"status":     model.EmailDeliverySent,
+			"sent_at":    sentAt,
+			"updated_at": time.Now(),
+		},
+	)
+}
+
+// MarkFailed marks a delivery as failed and returns it as it was before.
+// Returns mongo.ErrNoDocuments when the delivery was already sent or failed.
+func (r *emailDeliveryRepo) MarkFailed(ctx context.Context, id primitive.ObjectID, reason string) (*model.EmailDelivery, error) {
+	return r.finish(ctx,
+		bson.M{"_id": id, "status": bson.M{"$nin": bson.A{model.EmailDeliverySent, model.EmailDeliveryFailed}}},
+		bson.M{
+			"status":     model.EmailDeliveryFailed,
+			"error":      reason,
+			"updated_at": time.Now(),
+		},
+	)
+}
+
+func (r *emailDeliveryRepo) finish(ctx context.Context, filter, set bson.M) (*model.EmailDelivery, error) {
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.Before)
+
+	var delivery model.EmailDelivery
+	if err := r.collection.FindOneAndUpdate(ctx, filter, bson.M{"$set": set}, opts).Decode(&delivery); err != nil {
+		return nil, err
+	}
+
+	return &delivery, nil
+}
+
+// CountUnfinished counts a campaign's deliveries that are still pending, processing or in the email queue
+func (r *emailDeliveryRepo) CountUnfinished(ctx context.Context, campaignID primitive.ObjectID) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{
+		"campaign_id": campaignID,
+		"status":      bson.M{"$in": bson.A{model.EmailDeliveryPending, model.EmailDeliveryProcessing, model.EmailDeliveryQueued}},
+	})
+}
+
+// DeleteByUserID deletes a user's deliveries across all campaigns. Their campaigns keep the delivery counters.
+func (r *emailDeliveryRepo) DeleteByUserID(ctx context.Context, userID string) (int64, error) {
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := r.collection.DeleteMany(ctx, bson.M{"user_id": userObjID})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
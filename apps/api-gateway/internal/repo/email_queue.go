@@ -0,0 +1,174 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrEmailJobNotFound is returned when requeuing a job that is not in the dead-letter list
+var ErrEmailJobNotFound = errors.New("email job not found")
+
+// EmailQueue holds emails waiting to be sent, shared by all API instances
+type EmailQueue interface {
+	// Enqueue schedules the job for its first attempt at the given time
+	Enqueue(ctx context.Context, job *model.EmailJob, at time.Time) error
+	// ClaimDue returns up to limit jobs due by now and hides them from other workers until leaseUntil.
+	// A job whose worker stops before completing or retrying it is claimed again after the lease.
+	ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int64) ([]*model.EmailJob, error)
+	Complete(ctx context.Context, jobID string) error
+	Retry(ctx context.Context, job *model.EmailJob, at time.Time) error
+	// DeadLetter moves the job to the dead-letter list, keeping the newest maxLen jobs
+	DeadLetter(ctx context.Context, job *model.EmailJob, maxLen int64) error
+	ListDeadLetters(ctx context.Context, offset, limit int64) ([]*model.EmailJob, int64, error)
+	// RequeueDeadLetter removes the job from the dead-letter list and schedules it again with no attempts counted
+	RequeueDeadLetter(ctx context.Context, jobID string, at time.Time) error
+}
+
+type redisEmailQueue struct {
+	redisClient *redis.Client
+}
+
+// NewRedisEmailQueue creates an email queue stored in Redis
+func NewRedisEmailQueue(redisClient *redis.Client) EmailQueue {
+	return &redisEmailQueue{redisClient: redisClient}
+}
+
+// claimDueScript pushes the due jobs' next attempt to the lease end in one step, so two workers never claim the same job
+var claimDueScript = redis.NewScript(`
+local due = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, ARGV[3])
+for _, id in ipairs(due) do
+	redis.call('ZADD', KEYS[1], ARGV[2], id)
+end
+return due
+`)
+
+func (q *redisEmailQueue) Enqueue(ctx context.Context, job *model.EmailJob, at time.Time) error {
+	return q.schedule(ctx, job, at)
+}
+
+func (q *redisEmailQueue) ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int64) ([]*model.EmailJob, error) {
+	ids, err := claimDueScript.Run(ctx, q.redisClient, []string{config.RedisEmailQueueKey},
+		now.UnixMilli(), leaseUntil.UnixMilli(), limit).StringSlice()
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+
+	values, err := q.redisClient.HMGet(ctx, config.RedisEmailJobsKey, ids...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]*model.EmailJob, 0, len(ids))
+	for i, value := range values {
+		data, ok := value.(string)
+		if !ok {
+			// The job data is gone, nothing can be sent
+			q.redisClient.ZRem(ctx, config.RedisEmailQueueKey, ids[i])
+			continue
+		}
+		var job model.EmailJob
+		if err := json.Unmarshal([]byte(data), &job); err != nil {
+			slog.Error("Email queue: dropping undecodable job", "id", ids[i], "error", err)
+			_ = q.Complete(ctx, ids[i])
+			continue
+		}
+		jobs = append(jobs, &job)
+	}
+	return jobs, nil
+}
+
+func (q *redisEmailQueue) Complete(ctx context.Context, jobID string) error {
+	pipe := q.redisClient.TxPipeline()
+	pipe.ZRem(ctx, config.RedisEmailQueueKey, jobID)
+	pipe.HDel(ctx, config.RedisEmailJobsKey, jobID)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (q *redisEmailQueue) Retry(ctx context.Context, job *model.EmailJob, at time.Time) error {
+	return q.schedule(ctx, job, at)
+}
+
+func (q *redisEmailQueue) DeadLetter(ctx context.Context, job *model.EmailJob, maxLen int64) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	pipe := q.redisClient.TxPipeline()
+	pipe.LPush(ctx, config.RedisEmailDeadLetterKey, data)
+	pipe.LTrim(ctx, config.RedisEmailDeadLetterKey, 0, maxLen-1)
+	pipe.ZRem(ctx, config.RedisEmailQueueKey, job.ID)
+	pipe.HDel(ctx, config.RedisEmailJobsKey, job.ID)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+func (q *redisEmailQueue) ListDeadLetters(ctx context.Context, offset, limit int64) ([]*model.EmailJob, int64, error) {
+	pipe := q.redisClient.Pipeline()
+	total := pipe.LLen(ctx, config.RedisEmailDeadLetterKey)
+	values := pipe.LRange(ctx, config.RedisEmailDeadLetterKey, offset, offset+limit-1)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, 0, err
+	}
+
+	jobs := make([]*model.EmailJob, 0, len(values.Val()))
+	for _, data := range values.Val() {
+		var job model.EmailJob
+		if err := json.Unmarshal([]byte(data), &job); err != nil {
+			continue
+		}
+		jobs = append(jobs, &job)
+	}
+	return jobs, total.Val(), nil
+}
+
+func (q *redisEmailQueue) RequeueDeadLetter(ctx context.Context, jobID string, at time.Time) error {
+	values, err := q.redisClient.LRange(ctx, config.RedisEmailDeadLetterKey, 0, -1).Result()
+	if err != nil {
+		return err
+	}
+
+	for _, data := range values {
+		var job model.EmailJob
+		if err := json.Unmarshal([]byte(data), &job); err != nil || job.ID != jobID {
+			continue
+		}
+
+		// Whoever removes the entry owns it, so a job requeued twice at once is only sent once
+		removed, err := q.redisClient.LRem(ctx, config.RedisEmailDeadLetterKey, 1, data).Result()
+		if err != nil {
+			return err
+		}
+		if removed == 0 {
+			return ErrEmailJobNotFound
+		}
+
+		job.Attempts = 0
+		job.LastError = ""
+		job.FailedAt = nil
+		return q.schedule(ctx, &job, at)
+	}
+	return ErrEmailJobNotFound
+}
+
+// schedule stores the job and sets its next attempt
+func (q *redisEmailQueue) schedule(ctx context.Context, job *model.EmailJob, at time.Time) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	pipe := q.redisClient.TxPipeline()
+	pipe.HSet(ctx, config.RedisEmailJobsKey, job.ID, data)
+	pipe.ZAdd(ctx, config.RedisEmailQueueKey, redis.Z{Score: float64(at.UnixMilli()), Member: job.ID})
+	_, err = pipe.Exec(ctx)
+	return err
+}
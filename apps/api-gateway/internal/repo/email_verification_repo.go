@@ -2,6 +2,7 @@ package repo
 
 import (
 	"context"
+	"time"
 
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
@@ -14,6 +15,7 @@ type EmailVerificationRepo interface {
 	Create(ctx context.Context, verification *model.EmailVerification) (*model.EmailVerification, error)
 	GetByEmail(ctx context.Context, email string) (*model.EmailVerification, error)
 	Update(ctx context.Context, verification *model.EmailVerification) (*model.EmailVerification, error)
+	Complete(ctx context.Context, email, nonce string, userID primitive.ObjectID) error
 	Delete(ctx context.Context, email string) error
 }
 
@@ -62,6 +64,30 @@ func (r *emailVerificationRepo) Update(ctx context.Context, verification *model.
 	return verification, nil
 }
 
+// Complete consumes a verified verification for the account created with it. Returns mongo.ErrNoDocuments when the
+// verification was replaced or already consumed.
+func (r *emailVerificationRepo) Complete(ctx context.Context, email, nonce string, userID primitive.ObjectID) error {
+	filter := bson.M{
+		"email":       email,
+		"nonce":       nonce,
+		"is_verified": true,
+		"user_id":     bson.M{"$exists": false},
+	}
+	update := bson.M{"$set": bson.M{
+		"user_id":      userID,
+		"completed_at": time.Now(),
+	}}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
 func (r *emailVerificationRepo) Delete(ctx context.Context, email string) error {
 	filter := bson.M{"email": email}
 	_, err := r.collection.DeleteOne(ctx, filter)
@@ -0,0 +1,67 @@
+package repo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// findPage fetches a page of the documents matching filter and how many documents match in total, in one
+// aggregation instead of a count and a find. The filter and sort run before the $facet so they can use an
+// index; inside it, stages select the page from the sorted documents while a $count totals them.
+// The page comes back inside a single document, so it must fit MongoDB's 16MB document limit.
+func findPage[T any](ctx context.Context, collection *mongo.Collection, filter bson.M, sort bson.D, stages []bson.D) ([]T, int64, error) {
+	if filter == nil {
+		filter = bson.M{}
+	}
+	if len(stages) == 0 {
+		// $facet rejects an empty pipeline
+		stages = []bson.D{{{Key: "$match", Value: bson.M{}}}}
+	}
+
+	pipeline := mongo.Pipeline{{{Key: "$match", Value: filter}}}
+	if len(sort) > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$sort", Value: sort}})
+	}
+	pipeline = append(pipeline, bson.D{{Key: "$facet", Value: bson.D{
+		{Key: "data", Value: stages},
+		{Key: "total", Value: bson.A{bson.D{{Key: "$count", Value: "total"}}}},
+	}}})
+
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var result []struct {
+		Data  []T `bson:"data"`
+		Total []struct {
+			Total int64 `bson:"total"`
+		} `bson:"total"`
+	}
+	if err := cursor.All(ctx, &result); err != nil {
+		return nil, 0, err
+	}
+
+	items := []T{}
+	var total int64
+	if len(result) > 0 {
+		if result[0].Data != nil {
+			items = result[0].Data
+		}
+		if len(result[0].Total) > 0 {
+			total = result[0].Total[0].Total
+		}
+	}
+	return items, total, nil
+}
+
+// offsetStages selects the page-th page of pageSize documents, pages starting at 1
+func offsetStages(page, pageSize int) []bson.D {
+	return []bson.D{
+		{{Key: "$skip", Value: int64((page - 1) * pageSize)}},
+		{{Key: "$limit", Value: int64(pageSize)}},
+	}
+}
@@ -0,0 +1,112 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MessageReportRepo defines the interface for message report repository
+type MessageReportRepo interface {
+	Create(ctx context.Context, report *model.MessageReport) (*model.MessageReport, error)
+	GetByID(ctx context.Context, id string) (*model.MessageReport, error)
+	ExistsByReporter(ctx context.Context, messageID, reporterID primitive.ObjectID) (bool, error)
+	Find(ctx context.Context, filter Filter, page, pageSize int) ([]*model.MessageReport, int64, error)
+	Review(ctx context.Context, id primitive.ObjectID, status model.ReportStatus, note string, reviewerID primitive.ObjectID) error
+	DeleteBySessionIDs(ctx context.Context, sessionIDs []primitive.ObjectID) (int64, error)
+}
+
+type messageReportRepo struct {
+	collection *mongo.Collection
+}
+
+// NewMessageReportRepo creates a new message report repository
+func NewMessageReportRepo(db *mongo.Database) MessageReportRepo {
+	return &messageReportRepo{collection: db.Collection(config.MessageReportColName)}
+}
+
+// Create creates a new report
+func (r *messageReportRepo) Create(ctx context.Context, report *model.MessageReport) (*model.MessageReport, error) {
+	report.CreatedAt = time.Now()
+
+	result, err := r.collection.InsertOne(ctx, report)
+	if err != nil {
+		return nil, err
+	}
+
+	report.ID = result.InsertedID.(primitive.ObjectID)
+	return report, nil
+}
+
+// GetByID retrieves a report by ID
+func (r *messageReportRepo) GetByID(ctx context.Context, id string) (*model.MessageReport, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var report model.MessageReport
+	if err := r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&report); err != nil {
+		return nil, err
+	}
+
+	return &report, nil
+}
+
+// ExistsByReporter checks whether the user already reported the message
+func (r *messageReportRepo) ExistsByReporter(ctx context.Context, messageID, reporterID primitive.ObjectID) (bool, error) {
+	count, err := r.collection.CountDocuments(ctx, bson.M{
+		"message_id":  messageID,
+		"reporter_id": reporterID,
+	}, options.Count().SetLimit(1))
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// Find retrieves a page of reports matching filter, oldest first so the queue is worked in order
+func (r *messageReportRepo) Find(ctx context.Context, filter Filter, page, pageSize int) ([]*model.MessageReport, int64, error) {
+	return findPage[*model.MessageReport](ctx, r.collection, bson.M(filter), bson.D{{Key: "created_at", Value: 1}}, offsetStages(page, pageSize))
+}
+
+// Review records the moderation decision on a report
+func (r *messageReportRepo) Review(ctx context.Context, id primitive.ObjectID, status model.ReportStatus, note string, reviewerID primitive.ObjectID) error {
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$set": bson.M{
+			"status":          status,
+			"resolution_note": note,
+			"reviewed_by":     reviewerID,
+			"reviewed_at":     time.Now(),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+// DeleteBySessionIDs deletes all reports on messages of the given sessions
+func (r *messageReportRepo) DeleteBySessionIDs(ctx context.Context, sessionIDs []primitive.ObjectID) (int64, error) {
+	if len(sessionIDs) == 0 {
+		return 0, nil
+	}
+
+	result, err := r.collection.DeleteMany(ctx, bson.M{"session_id": bson.M{"$in": sessionIDs}})
+	if err != nil {
+		return 0, err
+	}
+
+	return result.DeletedCount, nil
+}
@@ -0,0 +1,45 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ModerationDecisionRepo defines the interface for moderation decision repository
+type ModerationDecisionRepo interface {
+	Create(ctx context.Context, decision *model.ModerationDecision) error
+	Find(ctx context.Context, filter Filter, page, pageSize int) ([]*model.ModerationDecision, int64, error)
+}
+
+type moderationDecisionRepo struct {
+	collection *mongo.Collection
+}
+
+// NewModerationDecisionRepo creates a new moderation decision repository
+func NewModerationDecisionRepo(db *mongo.Database) ModerationDecisionRepo {
+	return &moderationDecisionRepo{collection: db.Collection(config.ModerationDecisionColName)}
+}
+
+// Create stores a moderation decision
+func (r *moderationDecisionRepo) Create(ctx context.Context, decision *model.ModerationDecision) error {
+	decision.CreatedAt = time.Now()
+
+	result, err := r.collection.InsertOne(ctx, decision)
+	if err != nil {
+		return err
+	}
+
+	decision.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// Find retrieves moderation decisions matching filter, newest first
+func (r *moderationDecisionRepo) Find(ctx context.Context, filter Filter, page, pageSize int) ([]*model.ModerationDecision, int64, error) {
+	return findPage[*model.ModerationDecision](ctx, r.collection, bson.M(filter), bson.D{{Key: "created_at", Value: -1}}, offsetStages(page, pageSize))
+}
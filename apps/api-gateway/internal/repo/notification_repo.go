@@ -2,6 +2,7 @@ package repo
 
 import (
 	"context"
+	"time"
 
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
@@ -13,10 +14,15 @@ import (
 
 type NotificationRepo interface {
 	Create(ctx context.Context, notification *model.Notification) (*model.Notification, error)
-	GetByRecipientID(ctx context.Context, recipientID string, page, pageSize int) ([]*model.Notification, int64, error)
+	CreateMany(ctx context.Context, notifications []*model.Notification) ([]*model.Notification, error)
+	GetByRecipientID(ctx context.Context, recipientID string, cursor *Cursor, limit int) ([]*model.Notification, *Cursor, error)
+	GetCreatedSince(ctx context.Context, recipientID string, since time.Time, limit int) ([]*model.Notification, bool, error)
 	MarkAsRead(ctx context.Context, notificationID, recipientID string) error
 	MarkAllAsRead(ctx context.Context, recipientID string) (int64, error)
 	CountUnread(ctx context.Context, recipientID string) (int64, error)
+	GetUnreadDigests(ctx context.Context, olderThan time.Time, maxItems int) ([]*model.UnreadDigest, error)
+	DeleteExpired(ctx context.Context, readBefore time.Time, unreadBefore *time.Time) (int64, error)
+	DeleteByRecipientID(ctx context.Context, recipientID string) (int64, error)
 }
 
 type notificationRepo struct {
@@ -40,37 +46,90 @@ func (r *notificationRepo) Create(ctx context.Context, notification *model.Notif
 	return notification, nil
 }
 
-func (r *notificationRepo) GetByRecipientID(ctx context.Context, recipientID string, page, pageSize int) ([]*model.Notification, int64, error) {
-	recipientObjID, err := primitive.ObjectIDFromHex(recipientID)
+// CreateMany inserts notifications in a single round trip and fills in their IDs
+func (r *notificationRepo) CreateMany(ctx context.Context, notifications []*model.Notification) ([]*model.Notification, error) {
+	if len(notifications) == 0 {
+		return notifications, nil
+	}
+
+	docs := make([]interface{}, len(notifications))
+	for i, n := range notifications {
+		docs[i] = n
+	}
+
+	result, err := r.notificationCollection.InsertMany(ctx, docs)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 
-	filter := bson.M{"recipient_id": recipientObjID}
-	skip := (page - 1) * pageSize
+	for i, id := range result.InsertedIDs {
+		if oid, ok := id.(primitive.ObjectID); ok {
+			notifications[i].ID = oid
+		}
+	}
 
-	findOptions := options.Find().
-		SetSort(bson.D{{Key: "created_at", Value: -1}}).
-		SetSkip(int64(skip)).
-		SetLimit(int64(pageSize))
+	return notifications, nil
+}
 
-	cursor, err := r.notificationCollection.Find(ctx, filter, findOptions)
+// GetByRecipientID returns up to limit notifications after the cursor, newest first.
+// A nil cursor starts from the newest notification. The returned cursor is nil on the last page.
+func (r *notificationRepo) GetByRecipientID(ctx context.Context, recipientID string, cursor *Cursor, limit int) ([]*model.Notification, *Cursor, error) {
+	recipientObjID, err := primitive.ObjectIDFromHex(recipientID)
 	if err != nil {
-		return nil, 0, err
+		return nil, nil, err
 	}
-	defer cursor.Close(ctx)
+
+	page := Page{Field: "created_at", Order: -1, After: cursor, Limit: limit}
+	cursorResult, err := r.notificationCollection.Find(ctx, page.Match(bson.M{"recipient_id": recipientObjID}), page.FindOptions())
+	if err != nil {
+		return nil, nil, err
+	}
+	defer cursorResult.Close(ctx)
 
 	var notifications []*model.Notification
-	if err := cursor.All(ctx, &notifications); err != nil {
-		return nil, 0, err
+	if err := cursorResult.All(ctx, &notifications); err != nil {
+		return nil, nil, err
 	}
 
-	total, err := r.notificationCollection.CountDocuments(ctx, filter)
+	notifications, next := paginate(notifications, page, func(n *model.Notification) Cursor {
+		return Cursor{Value: n.CreatedAt, ID: n.ID}
+	})
+	return notifications, next, nil
+}
+
+// GetCreatedSince returns up to limit notifications created after since, oldest first,
+// and whether more remain. Used to replay notifications missed while a client was disconnected.
+func (r *notificationRepo) GetCreatedSince(ctx context.Context, recipientID string, since time.Time, limit int) ([]*model.Notification, bool, error) {
+	recipientObjID, err := primitive.ObjectIDFromHex(recipientID)
 	if err != nil {
-		return nil, 0, err
+		return nil, false, err
+	}
+
+	filter := bson.M{
+		"recipient_id": recipientObjID,
+		"created_at":   bson.M{"$gt": since},
 	}
 
-	return notifications, total, nil
+	// Fetch one extra document to know whether more remain
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetLimit(int64(limit + 1))
+
+	cursorResult, err := r.notificationCollection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, false, err
+	}
+	defer cursorResult.Close(ctx)
+
+	var notifications []*model.Notification
+	if err := cursorResult.All(ctx, &notifications); err != nil {
+		return nil, false, err
+	}
+
+	if len(notifications) <= limit {
+		return notifications, false, nil
+	}
+	return notifications[:limit], true, nil
 }
 
 func (r *notificationRepo) MarkAsRead(ctx context.Context, notificationID, recipientID string) error {
@@ -88,7 +147,7 @@ func (r *notificationRepo) MarkAsRead(ctx context.Context, notificationID, recip
 		"recipient_id": recipientObjID,
 	}
 	update := bson.M{
-		"$set": bson.M{"is_read": true},
+		"$set": bson.M{"is_read": true, "read_at": time.Now()},
 	}
 
 	result, err := r.notificationCollection.UpdateOne(ctx, filter, update)
@@ -114,7 +173,7 @@ func (r *notificationRepo) MarkAllAsRead(ctx context.Context, recipientID string
 		"is_read":      false,
 	}
 	update := bson.M{
-		"$set": bson.M{"is_read": true},
+		"$set": bson.M{"is_read": true, "read_at": time.Now()},
 	}
 
 	result, err := r.notificationCollection.UpdateMany(ctx, filter, update)
@@ -138,3 +197,71 @@ func (r *notificationRepo) CountUnread(ctx context.Context, recipientID string)
 
 	return r.notificationCollection.CountDocuments(ctx, filter)
 }
+
+// GetUnreadDigests groups unread notifications created before olderThan by recipient,
+// keeping the unread count and the most recent maxItems notifications of each recipient
+func (r *notificationRepo) GetUnreadDigests(ctx context.Context, olderThan time.Time, maxItems int) ([]*model.UnreadDigest, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"is_read":    bson.M{"$ne": true},
+			"created_at": bson.M{"$lte": olderThan},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "created_at", Value: -1}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":           "$recipient_id",
+			"unread_count":  bson.M{"$sum": 1},
+			"notifications": bson.M{"$push": "$$ROOT"},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"unread_count":  1,
+			"notifications": bson.M{"$slice": bson.A{"$notifications", maxItems}},
+		}}},
+	}
+
+	cursor, err := r.notificationCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var digests []*model.UnreadDigest
+	if err := cursor.All(ctx, &digests); err != nil {
+		return nil, err
+	}
+
+	return digests, nil
+}
+
+// DeleteExpired removes read notifications without read_at created before readBefore (marked read before
+// read_at existed, so the TTL index never expires them) and, when unreadBefore is set, unread
+// notifications created before it.
+func (r *notificationRepo) DeleteExpired(ctx context.Context, readBefore time.Time, unreadBefore *time.Time) (int64, error) {
+	conditions := bson.A{
+		bson.M{"is_read": true, "read_at": bson.M{"$exists": false}, "created_at": bson.M{"$lt": readBefore}},
+	}
+	if unreadBefore != nil {
+		conditions = append(conditions, bson.M{"is_read": bson.M{"$ne": true}, "created_at": bson.M{"$lt": *unreadBefore}})
+	}
+
+	result, err := r.notificationCollection.DeleteMany(ctx, bson.M{"$or": conditions})
+	if err != nil {
+		return 0, err
+	}
+
+	return result.DeletedCount, nil
+}
+
+// DeleteByRecipientID deletes all of a recipient's notifications
+func (r *notificationRepo) DeleteByRecipientID(ctx context.Context, recipientID string) (int64, error) {
+	recipientObjID, err := primitive.ObjectIDFromHex(recipientID)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := r.notificationCollection.DeleteMany(ctx, bson.M{"recipient_id": recipientObjID})
+	if err != nil {
+		return 0, err
+	}
+
+	return result.DeletedCount, nil
+}
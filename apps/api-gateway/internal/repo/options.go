@@ -1,5 +1,7 @@
 package repo
 
+import "go.mongodb.org/mongo-driver/bson"
+
 // Filter is a map to define query conditions.
 // It's an alias for map[string]interface{} for better readability in function signatures.
 // Example: Filter{"community_id": id, "is_deleted": false}
@@ -12,7 +14,7 @@ type UpdateDocument map[string]interface{}
 
 // FindOptions defines generic options for find operations, independent of the database driver.
 type FindOptions struct {
-	Sort  map[string]int // Example: {"created_at": -1, "votes": 1}
+	Sort  bson.D // Keys in priority order, e.g. {{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}
 	Skip  int64
 	Limit int64
 }
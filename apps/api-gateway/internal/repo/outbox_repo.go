@@ -0,0 +1,85 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// OutboxRepo stores domain events until they are published. Insert them with the ctx of the transaction
+// that makes the change, so an event exists if and only if its change was committed.
+type OutboxRepo interface {
+	Insert(ctx context.Context, events []*model.OutboxEvent) error
+	// ClaimNext leases the oldest pending event until claimedUntil so only one relay publishes it.
+	// Events whose lease ran out are claimed again. Returns mongo.ErrNoDocuments when nothing is pending.
+	ClaimNext(ctx context.Context, now, claimedUntil time.Time) (*model.OutboxEvent, error)
+	MarkDelivered(ctx context.Context, id primitive.ObjectID, at time.Time) error
+}
+
+type outboxRepo struct {
+	collection *mongo.Collection
+}
+
+func NewOutboxRepo(db *mongo.Database) OutboxRepo {
+	return &outboxRepo{collection: db.Collection(config.OutboxColName)}
+}
+
+func (r *outboxRepo) Insert(ctx context.Context, events []*model.OutboxEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	docs := make([]interface{}, len(events))
+	for i, event := range events {
+		docs[i] = event
+	}
+	result, err := r.collection.InsertMany(ctx, docs)
+	if err != nil {
+		return err
+	}
+
+	for i, id := range result.InsertedIDs {
+		if oid, ok := id.(primitive.ObjectID); ok {
+			events[i].ID = oid
+		}
+	}
+	return nil
+}
+
+func (r *outboxRepo) ClaimNext(ctx context.Context, now, claimedUntil time.Time) (*model.OutboxEvent, error) {
+	filter := bson.M{
+		"delivered_at": nil,
+		"$or": bson.A{
+			bson.M{"claimed_until": nil},
+			bson.M{"claimed_until": bson.M{"$lt": now}},
+		},
+	}
+	update := bson.M{
+		"$set": bson.M{"claimed_until": claimedUntil},
+		"$inc": bson.M{"attempts": 1},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "created_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var event model.OutboxEvent
+	if err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&event); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+func (r *outboxRepo) MarkDelivered(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	update := bson.M{
+		"$set":   bson.M{"delivered_at": at},
+		"$unset": bson.M{"claimed_until": ""},
+	}
+	_, err := r.collection.UpdateByID(ctx, id, update)
+	return err
+}
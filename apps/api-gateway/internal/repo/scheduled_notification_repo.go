@@ -0,0 +1,154 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// claimTimeout is how long a claimed notification may stay in processing before it is claimed again
+const claimTimeout = 10 * time.Minute
+
+type ScheduledNotificationRepo interface {
+	CreateMany(ctx context.Context, notifications []*model.ScheduledNotification) ([]*model.ScheduledNotification, error)
+	Find(ctx context.Context, status model.ScheduledNotificationStatus, page, pageSize int) ([]*model.ScheduledNotification, int64, error)
+	Cancel(ctx context.Context, id string) (*model.ScheduledNotification, error)
+	ClaimDue(ctx context.Context, now time.Time) (*model.ScheduledNotification, error)
+	MarkDelivered(ctx context.Context, id primitive.ObjectID, deliveredAt time.Time) error
+	MarkFailed(ctx context.Context, id primitive.ObjectID, reason string) error
+	DeleteByRecipientID(ctx context.Context, recipientID string) (int64, error)
+}
+
+type scheduledNotificationRepo struct {
+	collection *mongo.Collection
+}
+
+func NewScheduledNotificationRepo(db *mongo.Database) ScheduledNotificationRepo {
+	return &scheduledNotificationRepo{collection: db.Collection(config.ScheduledNotificationColName)}
+}
+
+func (r *scheduledNotificationRepo) CreateMany(ctx context.Context, notifications []*model.ScheduledNotification) ([]*model.ScheduledNotification, error) {
+	if len(notifications) == 0 {
+		return notifications, nil
+	}
+
+	now := time.Now()
+	docs := make([]interface{}, len(notifications))
+	for i, n := range notifications {
+		n.CreatedAt = now
+		n.UpdatedAt = now
+		docs[i] = n
+	}
+
+	result, err := r.collection.InsertMany(ctx, docs)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, id := range result.InsertedIDs {
+		if oid, ok := id.(primitive.ObjectID); ok {
+			notifications[i].ID = oid
+		}
+	}
+
+	return notifications, nil
+}
+
+// Find lists scheduled notifications ordered by delivery time. An empty status matches all.
+func (r *scheduledNotificationRepo) Find(ctx context.Context, status model.ScheduledNotificationStatus, page, pageSize int) ([]*model.ScheduledNotification, int64, error) {
+	filter := bson.M{}
+	if status != "" {
+		filter["status"] = status
+	}
+
+	return findPage[*model.ScheduledNotification](ctx, r.collection, filter, bson.D{{Key: "deliver_at", Value: 1}}, offsetStages(page, pageSize))
+}
+
+// Cancel cancels a pending scheduled notification. Returns mongo.ErrNoDocuments if it is not pending.
+func (r *scheduledNotificationRepo) Cancel(ctx context.Context, id string) (*model.ScheduledNotification, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{"_id": objectID, "status": model.ScheduledStatusPending}
+	update := bson.M{"$set": bson.M{
+		"status":     model.ScheduledStatusCancelled,
+		"updated_at": time.Now(),
+	}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var notification model.ScheduledNotification
+	if err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&notification); err != nil {
+		return nil, err
+	}
+
+	return &notification, nil
+}
+
+// ClaimDue atomically moves the earliest due notification to processing so only one scheduler delivers it.
+// Notifications stuck in processing longer than claimTimeout are claimed again.
+// Returns mongo.ErrNoDocuments when nothing is due.
+func (r *scheduledNotificationRepo) ClaimDue(ctx context.Context, now time.Time) (*model.ScheduledNotification, error) {
+	filter := bson.M{
+		"deliver_at": bson.M{"$lte": now},
+		"$or": bson.A{
+			bson.M{"status": model.ScheduledStatusPending},
+			bson.M{"status": model.ScheduledStatusProcessing, "updated_at": bson.M{"$lte": now.Add(-claimTimeout)}},
+		},
+	}
+	update := bson.M{"$set": bson.M{
+		"status":     model.ScheduledStatusProcessing,
+		"updated_at": now,
+	}}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "deliver_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var notification model.ScheduledNotification
+	if err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&notification); err != nil {
+		return nil, err
+	}
+
+	return &notification, nil
+}
+
+func (r *scheduledNotificationRepo) MarkDelivered(ctx context.Context, id primitive.ObjectID, deliveredAt time.Time) error {
+	update := bson.M{"$set": bson.M{
+		"status":       model.ScheduledStatusDelivered,
+		"delivered_at": deliveredAt,
+		"updated_at":   time.Now(),
+	}}
+	_, err := r.collection.UpdateByID(ctx, id, update)
+	return err
+}
+
+func (r *scheduledNotificationRepo) MarkFailed(ctx context.Context, id primitive.ObjectID, reason string) error {
+	update := bson.M{"$set": bson.M{
+		"status":     model.ScheduledStatusFailed,
+		"error":      reason,
+		"updated_at": time.Now(),
+	}}
+	_, err := r.collection.UpdateByID(ctx, id, update)
+	return err
+}
+
+// DeleteByRecipientID deletes all of a recipient's scheduled notifications, delivered or not
+func (r *scheduledNotificationRepo) DeleteByRecipientID(ctx context.Context, recipientID string) (int64, error) {
+	recipientObjID, err := primitive.ObjectIDFromHex(recipientID)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := r.collection.DeleteMany(ctx, bson.M{"recipient_id": recipientObjID})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
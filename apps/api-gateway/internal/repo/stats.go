@@ -0,0 +1,47 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// StatsTimezone is the timezone days are bucketed in for admin statistics
+const StatsTimezone = "Asia/Ho_Chi_Minh"
+
+// countPerDay counts documents matching filter created since the given time, grouped by day, oldest first
+func countPerDay(ctx context.Context, collection *mongo.Collection, filter bson.M, since time.Time) ([]*model.DailyCount, error) {
+	match := bson.M{"created_at": bson.M{"$gte": since}}
+	for k, v := range filter {
+		match[k] = v
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{"$dateToString": bson.M{
+				"format":   "%Y-%m-%d",
+				"date":     "$created_at",
+				"timezone": StatsTimezone,
+			}},
+			"count": bson.M{"$sum": 1},
+		}}},
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+	}
+
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var counts []*model.DailyCount
+	if err := cursor.All(ctx, &counts); err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
@@ -0,0 +1,75 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// Transactor runs several repo writes as one unit. Repos join the transaction through the ctx passed to fn.
+type Transactor interface {
+	// Run calls fn in a transaction, retrying it on transient errors, and commits if fn returns nil
+	Run(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// ErrTransactionsUnsupported is returned by NewTransactor when the server is standalone
+var ErrTransactionsUnsupported = errors.New("MongoDB does not support transactions, it must run as a replica set or sharded cluster")
+
+type mongoTransactor struct {
+	client    *mongo.Client
+	supported bool
+}
+
+// NewTransactor creates a transactor on client. Transactions need a replica set or sharded cluster; on a standalone
+// server it fails with ErrTransactionsUnsupported, unless allowStandalone is set. fn then runs without a
+// transaction, and a crash can leave only part of its writes applied.
+func NewTransactor(ctx context.Context, client *mongo.Client, allowStandalone bool) (Transactor, error) {
+	supported, err := transactionsSupported(ctx, client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check MongoDB transaction support: %w", err)
+	}
+	if !supported {
+		if !allowStandalone {
+			return nil, ErrTransactionsUnsupported
+		}
+		slog.Warn("MongoDB is standalone and MONGO_ALLOW_STANDALONE is set, transactional writes (e.g. domain events in the outbox) are not atomic")
+	}
+	return &mongoTransactor{client: client, supported: supported}, nil
+}
+
+func (t *mongoTransactor) Run(ctx context.Context, fn func(ctx context.Context) error) error {
+	if !t.supported {
+		return fn(ctx)
+	}
+
+	session, err := t.client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	// Reads in a transaction must go to the primary, whatever MONGO_READ_PREFERENCE says
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sc)
+	}, options.Transaction().SetReadPreference(readpref.Primary()))
+	return err
+}
+
+// transactionsSupported reports whether the server is a replica set member or a mongos
+func transactionsSupported(ctx context.Context, client *mongo.Client) (bool, error) {
+	var hello struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+	err := client.Database("admin").RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello)
+	if err != nil {
+		return false, err
+	}
+	return hello.SetName != "" || hello.Msg == "isdbgrid", nil
+}
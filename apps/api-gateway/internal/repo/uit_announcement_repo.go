@@ -0,0 +1,63 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// UITAnnouncementRepo defines the interface for the repository of crawled UIT announcements
+type UITAnnouncementRepo interface {
+	Insert(ctx context.Context, announcement *model.UITAnnouncement) (bool, error)
+	Find(ctx context.Context, page, pageSize int) ([]*model.UITAnnouncement, int64, error)
+	Count(ctx context.Context) (int64, error)
+	SetNotifiedCount(ctx context.Context, id primitive.ObjectID, count int64) error
+}
+
+type uitAnnouncementRepo struct {
+	collection *mongo.Collection
+}
+
+// NewUITAnnouncementRepo creates a new UIT announcement repository
+func NewUITAnnouncementRepo(db *mongo.Database) UITAnnouncementRepo {
+	return &uitAnnouncementRepo{collection: db.Collection(config.UITAnnouncementColName)}
+}
+
+// Insert stores a crawled announcement and reports whether it is new. An announcement with the same URL or hash
+// as a stored one is a duplicate, caught by the collection's unique indexes.
+func (r *uitAnnouncementRepo) Insert(ctx context.Context, announcement *model.UITAnnouncement) (bool, error) {
+	announcement.CreatedAt = time.Now()
+
+	result, err := r.collection.InsertOne(ctx, announcement)
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	announcement.ID = result.InsertedID.(primitive.ObjectID)
+	return true, nil
+}
+
+// Find retrieves a page of announcements, most recently crawled first
+func (r *uitAnnouncementRepo) Find(ctx context.Context, page, pageSize int) ([]*model.UITAnnouncement, int64, error) {
+	sort := bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}
+	return findPage[*model.UITAnnouncement](ctx, r.collection, bson.M{}, sort, offsetStages(page, pageSize))
+}
+
+// Count returns the number of stored announcements
+func (r *uitAnnouncementRepo) Count(ctx context.Context) (int64, error) {
+	return r.collection.EstimatedDocumentCount(ctx)
+}
+
+// SetNotifiedCount records how many users were notified of an announcement
+func (r *uitAnnouncementRepo) SetNotifiedCount(ctx context.Context, id primitive.ObjectID, count int64) error {
+	_, err := r.collection.UpdateByID(ctx, id, bson.M{"$set": bson.M{"notified_count": count}})
+	return err
+}
@@ -2,6 +2,7 @@ package repo
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -19,13 +20,28 @@ type UserRepo interface {
 	Update(ctx context.Context, user *model.User) (*model.User, error)
 	UpdateAvatarField(ctx context.Context, userID string, avatar *model.Image) (*model.User, error)
 	Delete(ctx context.Context, id string) error
+	HardDelete(ctx context.Context, id string) error
 	UpdateReputation(ctx context.Context, userID string, points int) error
+	UpdateLastDigestSentAt(ctx context.Context, userID string, sentAt time.Time) error
+	EnsureCalendarToken(ctx context.Context, userID, token string) (string, error)
+	SetCalendarToken(ctx context.Context, userID, token string) error
+	UpdateLastLogin(ctx context.Context, userID string, at time.Time, ip string) error
+	AddAdminNote(ctx context.Context, userID string, note *model.AdminNote) error
+	Deactivate(ctx context.Context, userID string, at time.Time) error
+	Reactivate(ctx context.Context, userID string) error
+	LinkProvider(ctx context.Context, userID string, provider model.AuthProvider, providerID string) error
+	DemoteAdmin(ctx context.Context, userID string) error
+	UpdateManyByIDs(ctx context.Context, ids []primitive.ObjectID, update bson.M) (int64, error)
 
 	GetByID(ctx context.Context, id string) (*model.User, error)
 	GetByIDs(ctx context.Context, ids []string) ([]*model.User, error)
 	GetByUsername(ctx context.Context, username string) (*model.User, error)
 	GetByEmail(ctx context.Context, email string) (*model.User, error)
 	Find(ctx context.Context, filter Filter, opts *FindOptions) ([]*model.User, int64, error)
+	FindPage(ctx context.Context, filter Filter, page Page) ([]*model.User, *Cursor, int64, error)
+	FindPageWithoutTotal(ctx context.Context, filter Filter, page Page) ([]*model.User, *Cursor, error)
+	Iterate(ctx context.Context, filter Filter, fn func(*model.User) error) error
+	GetDeletedBefore(ctx context.Context, before time.Time, limit int) ([]*model.User, error)
 
 	// Stats methods
 	CountTotal(ctx context.Context) (int64, error)
@@ -72,7 +88,7 @@ func (r *userRepo) GetByIDs(ctx context.Context, ids []string) ([]*model.User, e
 func (r *userRepo) Create(ctx context.Context, user *model.User) (*model.User, error) {
 	result, err := r.userCollection.InsertOne(ctx, user)
 	if err != nil {
-		return nil, err
+		return nil, duplicateUserError(err)
 	}
 
 	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
@@ -88,7 +104,7 @@ func (r *userRepo) Update(ctx context.Context, user *model.User) (*model.User, e
 
 	result, err := r.userCollection.UpdateOne(ctx, filter, update)
 	if err != nil {
-		return nil, err
+		return nil, duplicateUserError(err)
 	}
 	if result.MatchedCount == 0 {
 		return nil, mongo.ErrNoDocuments
@@ -104,6 +120,18 @@ func (r *userRepo) Update(ctx context.Context, user *model.User) (*model.User, e
 	return &updatedUser, nil
 }
 
+// duplicateUserError maps a unique index violation to the error of the field taken by another user,
+// it backs the services' own checks, which two concurrent sign-ups can both pass
+func duplicateUserError(err error) error {
+	if !mongo.IsDuplicateKeyError(err) {
+		return err
+	}
+	if strings.Contains(err.Error(), config.UserUsernameUniqueIndex) {
+		return apperror.ErrUsernameExists
+	}
+	return apperror.ErrEmailExists
+}
+
 func (r *userRepo) UpdateAvatarField(ctx context.Context, userID string, avatar *model.Image) (*model.User, error) {
 	objectID, err := primitive.ObjectIDFromHex(userID)
 	if err != nil {
@@ -164,6 +192,23 @@ func (r *userRepo) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
+// HardDelete permanently removes the user document
+func (r *userRepo) HardDelete(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return apperror.ErrInvalidID
+	}
+
+	result, err := r.userCollection.DeleteOne(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
 func (r *userRepo) UpdateReputation(ctx context.Context, userID string, points int) error {
 	objectID, err := primitive.ObjectIDFromHex(userID)
 	if err != nil {
@@ -187,6 +232,201 @@ func (r *userRepo) UpdateReputation(ctx context.Context, userID string, points i
 	return nil
 }
 
+// EnsureCalendarToken stores token as the user's calendar token unless they already have one, and returns the
+// token the user ends up with
+func (r *userRepo) EnsureCalendarToken(ctx context.Context, userID, token string) (string, error) {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return "", apperror.ErrInvalidID
+	}
+
+	filter := bson.M{"_id": objectID, "deleted_at": bson.M{"$exists": false}}
+	update := bson.A{bson.M{"$set": bson.M{"calendar_token": bson.M{"$ifNull": bson.A{"$calendar_token", token}}}}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var user model.User
+	if err := r.userCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&user); err != nil {
+		return "", err
+	}
+	return user.CalendarToken, nil
+}
+
+// SetCalendarToken replaces the user's calendar token, which revokes the feed link signed with the old one
+func (r *userRepo) SetCalendarToken(ctx context.Context, userID, token string) error {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return apperror.ErrInvalidID
+	}
+
+	filter := bson.M{"_id": objectID, "deleted_at": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{"calendar_token": token}}
+
+	result, err := r.userCollection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (r *userRepo) UpdateLastDigestSentAt(ctx context.Context, userID string, sentAt time.Time) error {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return apperror.ErrInvalidID
+	}
+
+	filter := bson.M{"_id": objectID}
+	update := bson.M{"$set": bson.M{"last_digest_sent_at": sentAt}}
+
+	result, err := r.userCollection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// UpdateLastLogin records a sign-in. An empty ip keeps the previously stored one.
+func (r *userRepo) UpdateLastLogin(ctx context.Context, userID string, at time.Time, ip string) error {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return apperror.ErrInvalidID
+	}
+
+	set := bson.M{"last_login": at}
+	if ip != "" {
+		set["last_login_ip"] = ip
+	}
+
+	filter := bson.M{"_id": objectID}
+	update := bson.M{"$set": set}
+
+	result, err := r.userCollection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// Deactivate marks the account as deactivated by its owner. Leftover fields of a lifted ban are
+// cleared so the account is not mistaken for a banned one.
+func (r *userRepo) Deactivate(ctx context.Context, userID string, at time.Time) error {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return apperror.ErrInvalidID
+	}
+
+	filter := bson.M{"_id": objectID}
+	update := bson.M{
+		"$set":   bson.M{"is_active": false, "deactivated_at": at, "updated_at": at},
+		"$unset": bson.M{"ban_until": "", "ban_reason": ""},
+	}
+
+	result, err := r.userCollection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// Reactivate ends a self-deactivation
+func (r *userRepo) Reactivate(ctx context.Context, userID string) error {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return apperror.ErrInvalidID
+	}
+
+	filter := bson.M{"_id": objectID}
+	update := bson.M{
+		"$set":   bson.M{"is_active": true, "updated_at": time.Now()},
+		"$unset": bson.M{"deactivated_at": ""},
+	}
+
+	result, err := r.userCollection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// LinkProvider adds a sign-in method to the user and stores its account ID
+func (r *userRepo) LinkProvider(ctx context.Context, userID string, provider model.AuthProvider, providerID string) error {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return apperror.ErrInvalidID
+	}
+
+	filter := bson.M{"_id": objectID}
+	update := bson.M{
+		"$set":      bson.M{"provider_id": providerID, "updated_at": time.Now()},
+		"$addToSet": bson.M{"linked_providers": provider},
+	}
+
+	result, err := r.userCollection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// AddAdminNote appends a note to the user's admin notes. Notes are never edited or removed.
+func (r *userRepo) AddAdminNote(ctx context.Context, userID string, note *model.AdminNote) error {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return apperror.ErrInvalidID
+	}
+
+	filter := bson.M{"_id": objectID}
+	update := bson.M{"$push": bson.M{"admin_notes": note}}
+
+	result, err := r.userCollection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// UpdateManyByIDs applies one update document to all given users in a single operation.
+// updated_at is set automatically. Returns the number of users modified.
+func (r *userRepo) UpdateManyByIDs(ctx context.Context, ids []primitive.ObjectID, update bson.M) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	set, _ := update["$set"].(bson.M)
+	if set == nil {
+		set = bson.M{}
+		update["$set"] = set
+	}
+	set["updated_at"] = time.Now()
+
+	result, err := r.userCollection.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": ids}}, update)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.ModifiedCount, nil
+}
+
 func (r *userRepo) GetByID(ctx context.Context, id string) (*model.User, error) {
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
@@ -221,65 +461,116 @@ func (r *userRepo) GetByEmail(ctx context.Context, email string) (*model.User, e
 	return &user, nil
 }
 
-// Find fetches users with filter and pagination options
-func (r *userRepo) Find(ctx context.Context, filter Filter, opts *FindOptions) ([]*model.User, int64, error) {
-	// Get total count
-	countPipeline := mongo.Pipeline{
-		{{"$match", bson.M(filter)}},
-		{{"$count", "total"}},
-	}
-	cursor, err := r.userCollection.Aggregate(ctx, countPipeline)
+// FindPage fetches a page of users matching filter, and how many users match in total.
+// page.Field is one of created_at, last_login, username or email. The returned cursor is nil on the last page.
+func (r *userRepo) FindPage(ctx context.Context, filter Filter, page Page) ([]*model.User, *Cursor, int64, error) {
+	users, total, err := findPage[*model.User](ctx, r.userCollection, bson.M(filter), page.Sort(), page.facetStages())
 	if err != nil {
-		return nil, 0, err
+		return nil, nil, 0, err
 	}
 
-	var countResult []struct {
-		Total int64 `bson:"total"`
-	}
-	if err = cursor.All(ctx, &countResult); err != nil {
-		return nil, 0, err
+	users, next := paginate(users, page, func(u *model.User) Cursor {
+		return Cursor{Value: userSortValue(u, page.Field), ID: u.ID}
+	})
+	return users, next, total, nil
+}
+
+// FindPageWithoutTotal fetches a page of users like FindPage, for callers that already know the total
+func (r *userRepo) FindPageWithoutTotal(ctx context.Context, filter Filter, page Page) ([]*model.User, *Cursor, error) {
+	cursor, err := r.userCollection.Find(ctx, page.Match(bson.M(filter)), page.FindOptions())
+	if err != nil {
+		return nil, nil, err
 	}
+	defer cursor.Close(ctx)
 
-	var total int64
-	if len(countResult) > 0 {
-		total = countResult[0].Total
+	users := []*model.User{}
+	if err = cursor.All(ctx, &users); err != nil {
+		return nil, nil, err
 	}
 
-	// If no documents, return early
-	if total == 0 {
-		return []*model.User{}, 0, nil
+	users, next := paginate(users, page, func(u *model.User) Cursor {
+		return Cursor{Value: userSortValue(u, page.Field), ID: u.ID}
+	})
+	return users, next, nil
+}
+
+// userSortValue returns the value of a user's sort field, nil if the user has none
+func userSortValue(u *model.User, field string) any {
+	switch field {
+	case "last_login":
+		if u.LastLogin == nil {
+			return nil
+		}
+		return *u.LastLogin
+	case "username":
+		return u.Username
+	case "email":
+		return u.Email
+	default:
+		return u.CreatedAt
 	}
+}
 
-	// Get paginated data
-	findOptions := options.Find()
+// Find fetches users with filter and pagination options
+func (r *userRepo) Find(ctx context.Context, filter Filter, opts *FindOptions) ([]*model.User, int64, error) {
+	var sort bson.D
+	var stages []bson.D
 	if opts != nil {
-		if opts.Sort != nil {
-			sortDoc := bson.D{}
-			for key, value := range opts.Sort {
-				sortDoc = append(sortDoc, bson.E{Key: key, Value: value})
-			}
-			findOptions.SetSort(sortDoc)
-		}
+		sort = opts.Sort
 		if opts.Skip > 0 {
-			findOptions.SetSkip(opts.Skip)
+			stages = append(stages, bson.D{{Key: "$skip", Value: opts.Skip}})
 		}
 		if opts.Limit > 0 {
-			findOptions.SetLimit(opts.Limit)
+			stages = append(stages, bson.D{{Key: "$limit", Value: opts.Limit}})
 		}
 	}
 
-	cursor, err = r.userCollection.Find(ctx, bson.M(filter), findOptions)
+	return findPage[*model.User](ctx, r.userCollection, bson.M(filter), sort, stages)
+}
+
+// Iterate calls fn for every user matching filter, newest first, streaming from a cursor
+// so large result sets are never held in memory. Iteration stops at the first error from fn.
+func (r *userRepo) Iterate(ctx context.Context, filter Filter, fn func(*model.User) error) error {
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetBatchSize(500)
+
+	cursor, err := r.userCollection.Find(ctx, bson.M(filter), findOptions)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var user model.User
+		if err := cursor.Decode(&user); err != nil {
+			return err
+		}
+		if err := fn(&user); err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
+
+// GetDeletedBefore returns up to limit users soft-deleted before the given time, oldest deletion first
+func (r *userRepo) GetDeletedBefore(ctx context.Context, before time.Time, limit int) ([]*model.User, error) {
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "deleted_at", Value: 1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.userCollection.Find(ctx, bson.M{"deleted_at": bson.M{"$lt": before}}, findOptions)
 	if err != nil {
-		return nil, total, err
+		return nil, err
 	}
 	defer cursor.Close(ctx)
 
 	var users []*model.User
 	if err := cursor.All(ctx, &users); err != nil {
-		return nil, 0, err
+		return nil, err
 	}
-
-	return users, total, nil
+	return users, nil
 }
 
 // Stats methods implementations
@@ -301,11 +592,56 @@ func (r *userRepo) CountCreatedAfter(ctx context.Context, since time.Time) (int6
 	return r.userCollection.CountDocuments(ctx, filter)
 }
 
+// BannedUserFilter matches banned users that are not deleted. Banned users are stored as inactive,
+// like self-deactivated ones unless they were also banned.
+func BannedUserFilter() bson.M {
+	return bson.M{
+		"is_active":  false,
+		"deleted_at": bson.M{"$exists": false},
+		"$or": bson.A{
+			bson.M{"deactivated_at": bson.M{"$exists": false}},
+			bson.M{"ban_reason": bson.M{"$exists": true}},
+		},
+	}
+}
+
 func (r *userRepo) CountBanned(ctx context.Context) (int64, error) {
-	filter := bson.M{
-		"is_banned": true,
+	return r.userCollection.CountDocuments(ctx, BannedUserFilter())
+}
+
+// DemoteAdmin makes an admin a regular user unless no other admin would be left, returning apperror.ErrLastAdmin.
+// It must run in a transaction: the demotion is rolled back when it left no admin, and every remaining admin is
+// written so that two concurrent demotions conflict instead of each counting the other admin still in place.
+// Returns mongo.ErrNoDocuments when the user is not an admin.
+func (r *userRepo) DemoteAdmin(ctx context.Context, userID string) error {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return apperror.ErrInvalidID
 	}
-	return r.userCollection.CountDocuments(ctx, filter)
+
+	now := time.Now()
+	result, err := r.userCollection.UpdateOne(ctx,
+		bson.M{"_id": objectID, "role": model.AdminRole, "deleted_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"role": model.UserRole, "updated_at": now}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	remaining, err := r.userCollection.UpdateMany(ctx,
+		bson.M{"role": model.AdminRole, "deleted_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"admin_checked_at": now}},
+	)
+	if err != nil {
+		return err
+	}
+	if remaining.MatchedCount == 0 {
+		return apperror.ErrLastAdmin
+	}
+	return nil
 }
 
 func (r *userRepo) CountVerified(ctx context.Context) (int64, error) {
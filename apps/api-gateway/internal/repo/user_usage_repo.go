@@ -0,0 +1,109 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// UserUsageRepo defines the interface for monthly user usage repository
+type UserUsageRepo interface {
+	ReplaceMonth(ctx context.Context, month string, usage []*model.UserUsage) error
+	Find(ctx context.Context, month string, page, pageSize int) ([]*model.UserUsage, int64, error)
+	GetTotals(ctx context.Context, month string) (*model.UsageTotals, error)
+	DeleteByUserID(ctx context.Context, userID string) (int64, error)
+}
+
+type userUsageRepo struct {
+	collection *mongo.Collection
+}
+
+// NewUserUsageRepo creates a new user usage repository
+func NewUserUsageRepo(db *mongo.Database) UserUsageRepo {
+	return &userUsageRepo{collection: db.Collection(config.UserUsageColName)}
+}
+
+// ReplaceMonth upserts the usage of every user in the month and removes users no longer in it,
+// e.g. after their data was erased
+func (r *userUsageRepo) ReplaceMonth(ctx context.Context, month string, usage []*model.UserUsage) error {
+	now := time.Now()
+	userIDs := make([]primitive.ObjectID, 0, len(usage))
+
+	if len(usage) > 0 {
+		writes := make([]mongo.WriteModel, 0, len(usage))
+		for _, u := range usage {
+			userIDs = append(userIDs, u.UserID)
+			writes = append(writes, mongo.NewUpdateOneModel().
+				SetFilter(bson.M{"user_id": u.UserID, "month": month}).
+				SetUpdate(bson.M{"$set": bson.M{
+					"requests":       u.Requests,
+					"tokens_used":    u.TokensUsed,
+					"estimated_cost": u.EstimatedCost,
+					"updated_at":     now,
+				}}).
+				SetUpsert(true))
+		}
+
+		if _, err := r.collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false)); err != nil {
+			return err
+		}
+	}
+
+	_, err := r.collection.DeleteMany(ctx, bson.M{"month": month, "user_id": bson.M{"$nin": userIDs}})
+	return err
+}
+
+// Find retrieves the usage of a month, heaviest users first
+func (r *userUsageRepo) Find(ctx context.Context, month string, page, pageSize int) ([]*model.UserUsage, int64, error) {
+	sort := bson.D{{Key: "tokens_used", Value: -1}, {Key: "requests", Value: -1}}
+	return findPage[*model.UserUsage](ctx, r.collection, bson.M{"month": month}, sort, offsetStages(page, pageSize))
+}
+
+// GetTotals sums the usage of all users in a month
+func (r *userUsageRepo) GetTotals(ctx context.Context, month string) (*model.UsageTotals, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"month": month}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":            nil,
+			"users":          bson.M{"$sum": 1},
+			"requests":       bson.M{"$sum": "$requests"},
+			"tokens_used":    bson.M{"$sum": "$tokens_used"},
+			"estimated_cost": bson.M{"$sum": "$estimated_cost"},
+		}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var totals []*model.UsageTotals
+	if err := cursor.All(ctx, &totals); err != nil {
+		return nil, err
+	}
+	if len(totals) == 0 {
+		return &model.UsageTotals{}, nil
+	}
+	return totals[0], nil
+}
+
+// DeleteByUserID deletes all monthly usage of a user
+func (r *userUsageRepo) DeleteByUserID(ctx context.Context, userID string) (int64, error) {
+	objID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := r.collection.DeleteMany(ctx, bson.M{"user_id": objID})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
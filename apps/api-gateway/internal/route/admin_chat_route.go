@@ -0,0 +1,25 @@
+package route
+
+import (
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/controller"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterAdminChatRoutes(rg *gin.RouterGroup, c *controller.AdminChatController) {
+	admin := rg.Group("/admin/users/:user_id/chat")
+
+	// All admin routes require authentication AND admin role
+	admin.Use(middleware.RequireAuth(), middleware.RequireAdmin())
+	{
+		admin.GET("/sessions", c.GetUserSessions)
+		admin.GET("/sessions/:session_id/messages", c.GetUserSessionMessages)
+	}
+
+	// Transcript search across all users, for abuse investigations
+	search := rg.Group("/admin/chat")
+	search.Use(middleware.RequireAuth(), middleware.RequireAdmin())
+	{
+		search.POST("/search", c.SearchMessages)
+	}
+}
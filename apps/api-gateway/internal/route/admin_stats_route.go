@@ -0,0 +1,17 @@
+package route
+
+import (
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/controller"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterAdminStatsRoutes(rg *gin.RouterGroup, c *controller.AdminStatsController) {
+	stats := rg.Group("/admin/stats")
+
+	// All stats routes require authentication AND admin role
+	stats.Use(middleware.RequireAuth(), middleware.RequireAdmin())
+	{
+		stats.GET("", c.GetStats)
+	}
+}
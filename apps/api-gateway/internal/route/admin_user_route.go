@@ -14,9 +14,17 @@ func RegisterAdminUserRoutes(rg *gin.RouterGroup, c *controller.AdminUserControl
 	{
 		// User management
 		admin.GET("", c.GetUsers)
+		admin.GET("/export", c.ExportUsers)
+		admin.POST("/bulk", c.BulkUserAction)
 		admin.POST("/:user_id/ban", c.BanUser)
 		admin.POST("/:user_id/unban", c.UnbanUser)
+		admin.PATCH("/:user_id", c.UpdateUser)
 		admin.DELETE("/:user_id", c.DeleteUser)
 		admin.POST("/:user_id/restore", c.RestoreUser)
+		admin.POST("/:user_id/erase", c.EraseUser)
+		admin.PATCH("/:user_id/role", c.UpdateUserRole)
+		admin.POST("/:user_id/force-logout", c.ForceLogout)
+		admin.GET("/:user_id/notes", c.GetUserNotes)
+		admin.POST("/:user_id/notes", c.AddUserNote)
 	}
 }
@@ -0,0 +1,17 @@
+package route
+
+import (
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/controller"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterAnalyticsRoutes(rg *gin.RouterGroup, c *controller.AnalyticsController) {
+	analytics := rg.Group("/admin/analytics")
+
+	// All analytics routes require authentication AND admin role
+	analytics.Use(middleware.RequireAuth(), middleware.RequireAdmin())
+	{
+		analytics.GET("/chat", c.GetChatAnalytics)
+	}
+}
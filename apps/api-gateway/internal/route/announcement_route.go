@@ -0,0 +1,22 @@
+package route
+
+import (
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/controller"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterAnnouncementRoutes(rg *gin.RouterGroup, c *controller.AnnouncementController) {
+	announcements := rg.Group("/admin/announcements")
+
+	// All announcement routes require authentication AND admin role
+	announcements.Use(middleware.RequireAuth(), middleware.RequireAdmin())
+	{
+		announcements.POST("", c.CreateAnnouncement)
+		announcements.GET("", c.GetAnnouncements)
+		announcements.GET("/:id", c.GetAnnouncement)
+		announcements.PATCH("/:id", c.UpdateAnnouncement)
+		announcements.DELETE("/:id", c.DeleteAnnouncement)
+		announcements.POST("/:id/send", c.SendAnnouncement)
+	}
+}
@@ -1,7 +1,9 @@
 package route
 
 import (
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/controller"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/middleware"
 	"github.com/gin-gonic/gin"
 )
 
@@ -9,25 +11,45 @@ import (
 func RegisterAuthRoutes(rg *gin.RouterGroup, authCtrl *controller.AuthController, userCtrl *controller.UserController) {
 	auth := rg.Group("/auth")
 
+	// Public endpoints that send email or can be scripted are rate limited per client IP, each in its own bucket
+	limits := config.Cfg.RateLimit
+	authLimit := func(policy string) gin.HandlerFunc {
+		return middleware.RateLimit(policy, middleware.RateLimitByIP, limits.AuthPerMinute, limits.AuthBurst)
+	}
+	lookupLimit := middleware.RateLimit("lookup", middleware.RateLimitByIP, limits.LookupPerMinute, limits.LookupBurst)
+	// Password sign-in is limited per IP and per account, so guessing is slowed however it is spread out.
+	// Completing a registration or a Google link is limited the same way; linking checks the account's password,
+	// so its guesses count against the account's sign-in bucket.
+	accountLimit := func(policy, accountPolicy string, key middleware.RateLimitKey) []gin.HandlerFunc {
+		return []gin.HandlerFunc{
+			middleware.RateLimit(policy, middleware.RateLimitByIP, limits.LoginPerMinute, limits.LoginBurst),
+			middleware.RateLimit(accountPolicy, key, limits.LoginAccountPerMinute, limits.LoginAccountBurst),
+		}
+	}
+	loginLimit := accountLimit("login", "login_account", middleware.RateLimitByLoginIdentifier)
+	completeRegistrationLimit := accountLimit("complete_registration", "complete_registration_account", middleware.RateLimitByVerificationToken)
+	completeLinkLimit := accountLimit("complete_link", "login_account", middleware.RateLimitByLinkToken)
+
 	auth.POST("/refresh", authCtrl.RefreshToken)
 	auth.POST("/logout", authCtrl.Logout)
-	auth.POST("/check-username", userCtrl.CheckUsername) // Public endpoint for username availability check
+	auth.POST("/check-username", lookupLimit, userCtrl.CheckUsername) // Public endpoint for username availability check
 
 	// Local Authentication - New Flow (Verify Email First)
 	local := auth.Group("/local")
 	{
-		local.POST("/send-verification", authCtrl.SendEmailVerification)
-		local.POST("/verify-email", authCtrl.VerifyEmailCode)
-		local.POST("/complete-registration", authCtrl.CompleteRegistration)
-		local.POST("/resend-otp", authCtrl.ResendOTP)
-		local.POST("/login", authCtrl.Login)
+		local.POST("/send-verification", authLimit("send_verification"), authCtrl.SendEmailVerification)
+		local.POST("/verify-email", authLimit("verify_email"), authCtrl.VerifyEmailCode)
+		local.POST("/complete-registration", append(completeRegistrationLimit, authCtrl.CompleteRegistration)...)
+		local.POST("/resend-otp", authLimit("resend_otp"), authCtrl.ResendOTP)
+		local.POST("/login", append(loginLimit, authCtrl.Login)...)
 	}
 
 	// Google OAuth2
 	google := auth.Group("/google")
 	{
-		google.GET("/login", authCtrl.GoogleLogin)
-		google.GET("/callback", authCtrl.GoogleCallback)
+		google.GET("/login", authLimit("google_login"), authCtrl.GoogleLogin)
+		google.GET("/callback", authLimit("google_callback"), authCtrl.GoogleCallback)
 		google.POST("/complete-setup", authCtrl.CompleteGoogleSetup)
+		google.POST("/complete-link", append(completeLinkLimit, authCtrl.CompleteAccountLink)...)
 	}
 }
@@ -10,8 +10,8 @@ func RegisterChatRoutes(rg *gin.RouterGroup, c *controller.ChatController) {
 	chat := rg.Group("/chat")
 	chat.Use(middleware.RequireAuth()) // All chat routes require authentication
 	{
-		// Main chat endpoint
-		chat.POST("", c.Chat)
+		// Main chat endpoint, a retried message is answered once
+		chat.POST("", middleware.Idempotent(), c.Chat)
 
 		// Session management
 		sessions := chat.Group("/sessions")
@@ -21,6 +21,7 @@ func RegisterChatRoutes(rg *gin.RouterGroup, c *controller.ChatController) {
 			sessions.GET("/:id/messages", c.GetMessages)
 			sessions.DELETE("/:id", c.DeleteSession)
 			sessions.PATCH("/:id/title", c.UpdateSessionTitle)
+			sessions.PATCH("/:id/language", c.UpdateSessionLanguage)
 		}
 	}
 }
@@ -8,9 +8,8 @@ import (
 
 func RegisterCookieRoutes(rg *gin.RouterGroup, cookieCtrl *controller.CookieController) {
 	cookie := rg.Group("/cookie")
-	cookie.Use(middleware.RequireAuth()) // Cần auth
 	{
-		cookie.POST("/sync", cookieCtrl.SyncCookie)
-		cookie.GET("/status", cookieCtrl.GetCookieStatus)
+		cookie.POST("/sync", middleware.RequireExtensionAuth(), middleware.Idempotent(), cookieCtrl.SyncCookie) // Token extension cũng được phép
+		cookie.GET("/status", middleware.RequireAuth(), cookieCtrl.GetCookieStatus)
 	}
 }
@@ -0,0 +1,20 @@
+package route
+
+import (
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/controller"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterDataExportRoutes(rg *gin.RouterGroup, c *controller.DataExportController) {
+	// Export requests of the currently authenticated user
+	me := rg.Group("/users/me")
+	me.Use(middleware.RequireAuth())
+	{
+		me.POST("/export", c.RequestExport)
+		me.GET("/exports/:export_id", c.GetExport)
+	}
+
+	// Public route - the signed download link is the credential
+	rg.GET("/data-exports/:export_id/download", c.Download)
+}
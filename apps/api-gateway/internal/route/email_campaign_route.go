@@ -0,0 +1,21 @@
+package route
+
+import (
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/controller"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterEmailCampaignRoutes(rg *gin.RouterGroup, c *controller.EmailCampaignController) {
+	campaigns := rg.Group("/admin/email-campaigns")
+
+	// All email campaign routes require authentication AND admin role
+	campaigns.Use(middleware.RequireAuth(), middleware.RequireAdmin())
+	{
+		campaigns.POST("", c.CreateCampaign)
+		campaigns.GET("", c.GetCampaigns)
+		campaigns.GET("/:id", c.GetCampaign)
+		campaigns.POST("/:id/send", c.SendCampaign)
+		campaigns.GET("/:id/deliveries", c.GetDeliveries)
+	}
+}
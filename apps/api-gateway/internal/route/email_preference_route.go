@@ -0,0 +1,11 @@
+package route
+
+import (
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/controller"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterEmailPreferenceRoutes(rg *gin.RouterGroup, c *controller.EmailPreferenceController) {
+	// Public route - the signed unsubscribe link is the credential
+	rg.GET("/email/unsubscribe", c.Unsubscribe)
+}
@@ -0,0 +1,18 @@
+package route
+
+import (
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/controller"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterEmailQueueRoutes(rg *gin.RouterGroup, c *controller.EmailQueueController) {
+	emails := rg.Group("/admin/emails")
+
+	// All email queue routes require authentication AND admin role
+	emails.Use(middleware.RequireAuth(), middleware.RequireAdmin())
+	{
+		emails.GET("/failed", c.GetFailedEmails)
+		emails.POST("/failed/:id/requeue", c.RequeueFailedEmail)
+	}
+}
@@ -0,0 +1,16 @@
+package route
+
+import (
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/controller"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterExtensionRoutes(rg *gin.RouterGroup, extensionCtrl *controller.ExtensionController) {
+	extension := rg.Group("/extension")
+	extension.Use(middleware.RequireExtensionAuth()) // Scoped extension token or full access token
+	{
+		extension.POST("/token", extensionCtrl.IssueToken)
+		extension.POST("/heartbeat", extensionCtrl.Heartbeat)
+	}
+}
@@ -0,0 +1,18 @@
+package route
+
+import (
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/controller"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterMaintenanceRoutes(rg *gin.RouterGroup, c *controller.MaintenanceController) {
+	maintenance := rg.Group("/admin/maintenance")
+
+	// Maintenance routes require authentication AND admin role
+	maintenance.Use(middleware.RequireAuth(), middleware.RequireAdmin())
+	{
+		maintenance.GET("", c.GetMaintenance)
+		maintenance.PUT("", c.UpdateMaintenance)
+	}
+}
@@ -0,0 +1,17 @@
+package route
+
+import (
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/controller"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterModerationRoutes(rg *gin.RouterGroup, c *controller.ModerationController) {
+	moderation := rg.Group("/admin/moderation")
+
+	// All moderation routes require authentication AND admin role
+	moderation.Use(middleware.RequireAuth(), middleware.RequireAdmin())
+	{
+		moderation.GET("/decisions", c.GetDecisions)
+	}
+}
@@ -11,6 +11,16 @@ func RegisterNotificationRoutes(rg *gin.RouterGroup, c *controller.NotificationC
 	notifications.Use(middleware.RequireAuth()) // All notification routes require authentication
 	{
 		notifications.GET("", c.GetNotifications)
+		notifications.GET("/unread-count", c.GetUnreadCount)
 		notifications.PATCH("/read-all", c.MarkAllAsRead)
 	}
+
+	// Scheduled notifications are managed by admins
+	scheduled := rg.Group("/admin/notifications/scheduled")
+	scheduled.Use(middleware.RequireAuth(), middleware.RequireAdmin())
+	{
+		scheduled.POST("", c.ScheduleNotification)
+		scheduled.GET("", c.GetScheduledNotifications)
+		scheduled.DELETE("/:id", c.CancelScheduledNotification)
+	}
 }
@@ -0,0 +1,19 @@
+package route
+
+import (
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/controller"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterQuotaRoutes(rg *gin.RouterGroup, c *controller.QuotaController) {
+	quota := rg.Group("/admin/users/:user_id/quota")
+
+	// All quota routes require authentication AND admin role
+	quota.Use(middleware.RequireAuth(), middleware.RequireAdmin())
+	{
+		quota.GET("", c.GetUserQuota)
+		quota.PUT("", c.SetUserQuota)
+		quota.DELETE("", c.ClearUserQuota)
+	}
+}
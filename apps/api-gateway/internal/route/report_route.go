@@ -0,0 +1,23 @@
+package route
+
+import (
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/controller"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterReportRoutes(rg *gin.RouterGroup, c *controller.ReportController) {
+	messages := rg.Group("/chat/messages")
+	messages.Use(middleware.RequireAuth())
+	{
+		messages.POST("/:id/report", c.ReportMessage)
+	}
+
+	// Moderation queue is managed by admins
+	reports := rg.Group("/admin/reports")
+	reports.Use(middleware.RequireAuth(), middleware.RequireAdmin())
+	{
+		reports.GET("", c.GetReports)
+		reports.PATCH("/:id", c.ReviewReport)
+	}
+}
@@ -0,0 +1,17 @@
+package route
+
+import (
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/controller"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterSystemHealthRoutes(rg *gin.RouterGroup, c *controller.SystemHealthController) {
+	system := rg.Group("/admin/system")
+
+	// All system routes require authentication AND admin role
+	system.Use(middleware.RequireAuth(), middleware.RequireAdmin())
+	{
+		system.GET("/health", c.GetHealth)
+	}
+}
@@ -0,0 +1,28 @@
+package route
+
+import (
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/controller"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterUITRoutes(rg *gin.RouterGroup, c *controller.UITController) {
+	uit := rg.Group("/uit")
+	uit.Use(middleware.RequireAuth())
+	{
+		uit.GET("/schedule", c.GetSchedule) // ?refresh=true bypasses the cache
+		uit.GET("/drl", c.GetTrainingScore) // ?refresh=true bypasses the cache
+		uit.GET("/tuition", c.GetTuition)   // ?refresh=true bypasses the cache
+		uit.GET("/calendar", c.GetCalendarFeed)
+		uit.POST("/calendar/regenerate", c.RegenerateCalendarFeed) // Revokes the previous feed link
+		uit.GET("/registration/classes", c.GetOpenClasses)
+		uit.POST("/registration/conflicts", c.CheckConflicts)
+	}
+
+	// Public route - the signed feed link is the credential, calendar apps cannot log in
+	rg.GET("/uit/calendar/:user_id/schedule.ics", c.GetCalendar)
+}
+
+func RegisterUITAnnouncementRoutes(rg *gin.RouterGroup, c *controller.UITAnnouncementController) {
+	rg.GET("/uit/announcements", middleware.RequireAuth(), c.GetAnnouncements)
+}
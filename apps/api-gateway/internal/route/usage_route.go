@@ -0,0 +1,17 @@
+package route
+
+import (
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/controller"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterUsageRoutes(rg *gin.RouterGroup, c *controller.UsageController) {
+	usage := rg.Group("/admin/usage")
+
+	// All usage routes require authentication AND admin role
+	usage.Use(middleware.RequireAuth(), middleware.RequireAdmin())
+	{
+		usage.GET("", c.GetUsage)
+	}
+}
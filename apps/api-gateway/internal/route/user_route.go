@@ -1,16 +1,21 @@
 package route
 
 import (
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/controller"
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/middleware"
 	"github.com/gin-gonic/gin"
 )
 
-func RegisterUserRoutes(rg *gin.RouterGroup, c *controller.UserController) {
+func RegisterUserRoutes(rg *gin.RouterGroup, c *controller.UserController, presenceCtrl *controller.PresenceController) {
 	users := rg.Group("/users")
 
-	// Public routes - anyone can view a user's profile
-	users.GET("/", c.GetUsers)
+	// Public routes - only the public profile, subject to the owner's profile visibility
+	users.GET("/", middleware.OptionalAuth(), c.GetUsers)
+	users.GET("/by-username/:username", middleware.OptionalAuth(), c.GetUserByUsername)
+
+	// Presence - users can see their own, admins can see anyone's
+	users.GET("/:id/presence", middleware.RequireAuth(), presenceCtrl.GetPresence)
 
 	// Routes for the currently authenticated user ("me")
 	me := users.Group("/me")
@@ -19,9 +24,20 @@ func RegisterUserRoutes(rg *gin.RouterGroup, c *controller.UserController) {
 		me.GET("", c.GetMyProfile)
 		me.PATCH("", c.UpdateUser)              // Update user (username)
 		me.PATCH("/password", c.ChangePassword) // Change password
-		me.POST("/avatar", c.UploadAvatar)      // Upload avatar
-		me.DELETE("/avatar", c.DeleteAvatar)    // Delete avatar
+		// Upload avatar, the body is capped at the avatar size limit
+		me.POST("/avatar", middleware.BodyLimit(0, avatarUploadMaxBytes()), middleware.Idempotent(), c.UploadAvatar)
+		me.DELETE("/avatar", c.DeleteAvatar) // Delete avatar
+		me.GET("/completeness", c.GetProfileCompleteness)
 		me.GET("/settings", c.GetSettings)      // Get settings
 		me.PATCH("/settings", c.UpdateSettings) // Update settings
+		me.GET("/blocked-topics", c.GetBlockedTopics)
+		me.POST("/blocked-topics", c.AddBlockedTopic)
+		me.DELETE("/blocked-topics/:topic", c.RemoveBlockedTopic)
+		me.POST("/deactivate", c.DeactivateAccount) // Deactivate until next login
 	}
 }
+
+// avatarUploadMaxBytes is the largest accepted avatar plus room for the multipart envelope
+func avatarUploadMaxBytes() int64 {
+	return int64(config.Cfg.Avatar.MaxSizeMB)<<20 + 1<<20
+}
@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/apperror"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/repo"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/util"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// AdminChatService gives support staff read-only access to any user's chat history.
+// Every access is written to the audit log before any data is returned.
+type AdminChatService interface {
+	GetUserSessions(ctx context.Context, adminID, userID string, query *dto.GetSessionsQuery) (*dto.PaginatedSessionsResponse, error)
+	GetUserSessionMessages(ctx context.Context, adminID, userID, sessionID string, limit int) (*dto.AdminChatSessionResponse, error)
+	SearchMessages(ctx context.Context, adminID string, req *dto.SearchChatMessagesRequest) (*dto.ChatSearchResponse, error)
+}
+
+type adminChatService struct {
+	sessionRepo  repo.ChatSessionRepo
+	messageRepo  repo.ChatMessageRepo
+	auditService AuditService
+}
+
+func NewAdminChatService(sessionRepo repo.ChatSessionRepo, messageRepo repo.ChatMessageRepo, auditService AuditService) AdminChatService {
+	return &adminChatService{
+		sessionRepo:  sessionRepo,
+		messageRepo:  messageRepo,
+		auditService: auditService,
+	}
+}
+
+func (s *adminChatService) GetUserSessions(ctx context.Context, adminID, userID string, query *dto.GetSessionsQuery) (*dto.PaginatedSessionsResponse, error) {
+	ctx, cancel := util.NewDBContextFrom(ctx)
+	defer cancel()
+
+	if _, err := primitive.ObjectIDFromHex(userID); err != nil {
+		return nil, apperror.ErrInvalidID
+	}
+
+	if err := s.auditService.Record(ctx, adminID, model.AuditActionViewUserChatSessions, model.AuditTargetUser, userID, nil); err != nil {
+		return nil, err
+	}
+
+	page, err := query.ToPage()
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, next, err := s.sessionRepo.GetSummariesByUserID(ctx, userID, page)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.PaginatedSessionsResponse{
+		Sessions:   dto.FromChatSessionSummaries(sessions),
+		NextCursor: dto.NextCursor(next),
+		HasMore:    next != nil,
+	}, nil
+}
+
+func (s *adminChatService) GetUserSessionMessages(ctx context.Context, adminID, userID, sessionID string, limit int) (*dto.AdminChatSessionResponse, error) {
+	ctx, cancel := util.NewDBContextFrom(ctx)
+	defer cancel()
+
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, apperror.ErrInvalidID
+	}
+	if _, err := primitive.ObjectIDFromHex(sessionID); err != nil {
+		return nil, apperror.ErrInvalidID
+	}
+
+	session, err := s.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, apperror.ErrChatSessionNotFound
+		}
+		return nil, err
+	}
+	// Don't reveal sessions through the wrong user's path
+	if session.UserID != userObjID {
+		return nil, apperror.ErrChatSessionNotFound
+	}
+
+	metadata := map[string]string{"user_id": userID}
+	if err := s.auditService.Record(ctx, adminID, model.AuditActionViewChatMessages, model.AuditTargetChatSession, sessionID, metadata); err != nil {
+		return nil, err
+	}
+
+	messages, err := s.messageRepo.GetBySessionID(ctx, sessionID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.AdminChatSessionResponse{
+		UserID:   userID,
+		Session:  *dto.FromChatSession(session),
+		Messages: make([]dto.ChatMessageResponse, len(messages)),
+	}
+	for i, msg := range messages {
+		response.Messages[i] = *dto.FromChatMessage(msg)
+	}
+
+	return response, nil
+}
+
+// defaultSearchContext is the number of messages shown before and after each search match
+const defaultSearchContext = 2
+
+// SearchMessages searches the transcripts of all users for abuse or jailbreak attempts.
+// The search and its reason are audited before anything is returned.
+func (s *adminChatService) SearchMessages(ctx context.Context, adminID string, req *dto.SearchChatMessagesRequest) (*dto.ChatSearchResponse, error) {
+	ctx, cancel := util.NewDBContextFrom(ctx)
+	defer cancel()
+
+	filter := repo.Filter{
+		"content": bson.M{"$regex": primitive.Regex{Pattern: regexp.QuoteMeta(req.Query), Options: "i"}},
+	}
+	if req.Role != "" {
+		filter["role"] = req.Role
+	}
+
+	createdAt := bson.M{}
+	if req.From != "" {
+		from, err := time.ParseInLocation(analyticsDateLayout, req.From, statsLocation())
+		if err != nil {
+			return nil, apperror.ErrInvalidDateFormat
+		}
+		createdAt["$gte"] = from
+	}
+	if req.To != "" {
+		to, err := time.ParseInLocation(analyticsDateLayout, req.To, statsLocation())
+		if err != nil {
+			return nil, apperror.ErrInvalidDateFormat
+		}
+		createdAt["$lt"] = to.AddDate(0, 0, 1) // To is inclusive
+	}
+	if len(createdAt) > 0 {
+		filter["created_at"] = createdAt
+	}
+
+	if req.UserID != "" {
+		if _, err := primitive.ObjectIDFromHex(req.UserID); err != nil {
+			return nil, apperror.ErrInvalidID
+		}
+	}
+
+	metadata := map[string]string{"reason": req.Reason, "query": req.Query}
+	if req.From != "" {
+		metadata["from"] = req.From
+	}
+	if req.To != "" {
+		metadata["to"] = req.To
+	}
+	if err := s.auditService.Record(ctx, adminID, model.AuditActionSearchChatMessages, model.AuditTargetChatMessages, req.UserID, metadata); err != nil {
+		return nil, err
+	}
+
+	if req.UserID != "" {
+		sessionIDs, err := s.sessionRepo.GetAllIDsByUserID(ctx, req.UserID)
+		if err != nil {
+			return nil, err
+		}
+		filter["session_id"] = bson.M{"$in": sessionIDs}
+	}
+
+	page := req.Page
+	if page < 1 {
+		page = 1
+	}
+	pageSize := req.PageSize
+	if pageSize < 1 {
+		pageSize = 20
+	}
+	contextSize := defaultSearchContext
+	if req.Context != nil {
+		contextSize = *req.Context
+	}
+
+	messages, total, err := s.messageRepo.Search(ctx, filter, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	// Soft-deleted sessions are included, abuse may have been deleted afterwards
+	sessionIDs := make([]primitive.ObjectID, 0, len(messages))
+	for _, msg := range messages {
+		sessionIDs = append(sessionIDs, msg.SessionID)
+	}
+	sessions, err := s.sessionRepo.GetByIDsIncludingDeleted(ctx, sessionIDs)
+	if err != nil {
+		return nil, err
+	}
+	sessionsByID := make(map[primitive.ObjectID]*model.ChatSession, len(sessions))
+	for _, session := range sessions {
+		sessionsByID[session.ID] = session
+	}
+
+	matches := make([]dto.ChatSearchMatch, 0, len(messages))
+	for _, msg := range messages {
+		before, after, err := s.messageRepo.GetAround(ctx, msg.SessionID, msg.CreatedAt, contextSize, contextSize)
+		if err != nil {
+			return nil, err
+		}
+
+		match := dto.ChatSearchMatch{
+			Message: *dto.FromChatMessage(msg),
+			Before:  dto.FromChatMessages(before),
+			After:   dto.FromChatMessages(after),
+		}
+		if session, ok := sessionsByID[msg.SessionID]; ok {
+			match.UserID = session.UserID.Hex()
+			match.Session = dto.FromChatSession(session)
+		}
+		matches = append(matches, match)
+	}
+
+	return &dto.ChatSearchResponse{
+		Matches: matches,
+		Pagination: dto.Pagination{
+			Page:     page,
+			PageSize: pageSize,
+			Total:    total,
+		},
+	}, nil
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/repo"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/util"
+)
+
+const (
+	defaultStatsDays = 30
+	maxStatsDays     = 90
+)
+
+// AdminStatsService computes the statistics shown on the admin dashboard
+type AdminStatsService interface {
+	GetStats(ctx context.Context, days int) (*dto.AdminStatsResponse, error)
+}
+
+type adminStatsService struct {
+	userRepo        repo.UserRepo
+	chatSessionRepo repo.ChatSessionRepo
+	chatMessageRepo repo.ChatMessageRepo
+}
+
+func NewAdminStatsService(userRepo repo.UserRepo, chatSessionRepo repo.ChatSessionRepo, chatMessageRepo repo.ChatMessageRepo) AdminStatsService {
+	return &adminStatsService{
+		userRepo:        userRepo,
+		chatSessionRepo: chatSessionRepo,
+		chatMessageRepo: chatMessageRepo,
+	}
+}
+
+func (s *adminStatsService) GetStats(ctx context.Context, days int) (*dto.AdminStatsResponse, error) {
+	ctx, cancel := util.NewDBContextFrom(ctx)
+	defer cancel()
+
+	if days < 1 {
+		days = defaultStatsDays
+	}
+	if days > maxStatsDays {
+		days = maxStatsDays
+	}
+
+	loc, err := time.LoadLocation(repo.StatsTimezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	now := time.Now().In(loc)
+
+	var users dto.UserStats
+	if users.Total, err = s.userRepo.CountTotal(ctx); err != nil {
+		return nil, err
+	}
+	if users.NewLast7Days, err = s.userRepo.CountCreatedAfter(ctx, now.AddDate(0, 0, -7)); err != nil {
+		return nil, err
+	}
+	if users.NewLast30Days, err = s.userRepo.CountCreatedAfter(ctx, now.AddDate(0, 0, -30)); err != nil {
+		return nil, err
+	}
+	if users.DAU, err = s.userRepo.CountActiveAfter(ctx, now.Add(-24*time.Hour)); err != nil {
+		return nil, err
+	}
+	if users.WAU, err = s.userRepo.CountActiveAfter(ctx, now.AddDate(0, 0, -7)); err != nil {
+		return nil, err
+	}
+	if users.Verified, err = s.userRepo.CountVerified(ctx); err != nil {
+		return nil, err
+	}
+	if users.Banned, err = s.userRepo.CountBanned(ctx); err != nil {
+		return nil, err
+	}
+	if users.Total > 0 {
+		users.VerifiedRatio = float64(users.Verified) / float64(users.Total)
+	}
+
+	// Series start at midnight so the first day is complete
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, -(days - 1))
+
+	sessions, err := s.chatSessionRepo.CountCreatedPerDay(ctx, start)
+	if err != nil {
+		return nil, err
+	}
+	messages, err := s.chatMessageRepo.CountCreatedPerDay(ctx, start)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.AdminStatsResponse{
+		Users: users,
+		Chat: dto.ChatStats{
+			SessionsPerDay: fillDays(sessions, start, days),
+			MessagesPerDay: fillDays(messages, start, days),
+		},
+		GeneratedAt: time.Now(),
+	}, nil
+}
+
+// fillDays returns one entry per day starting at start, using zero for days missing from counts
+func fillDays(counts []*model.DailyCount, start time.Time, days int) []model.DailyCount {
+	byDate := make(map[string]int64, len(counts))
+	for _, c := range counts {
+		byDate[c.Date] = c.Count
+	}
+
+	series := make([]model.DailyCount, days)
+	for i := range series {
+		date := start.AddDate(0, 0, i).Format("2006-01-02")
+		series[i] = model.DailyCount{Date: date, Count: byDate[date]}
+	}
+	return series
+}
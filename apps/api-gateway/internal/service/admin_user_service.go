@@ -1,14 +1,27 @@
 package service
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
 	"errors"
+	"io"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/apperror"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/auth"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/bus"
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/repo"
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/util"
+	"github.com/redis/go-redis/v9"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -16,49 +29,65 @@ import (
 
 type AdminUserService interface {
 	// User management
-	GetUsersAdmin(query *dto.GetUsersAdminQuery) (*dto.PaginatedUsersResponse, error)
-	BanUser(userID string, req *dto.BanUserRequest) error
-	UnbanUser(userID string) error
-	SoftDeleteUser(userID string) error
-	RestoreUser(userID string) error
+	GetUsersAdmin(ctx context.Context, query *dto.GetUsersAdminQuery) (*dto.PaginatedUsersResponse, error)
+	ExportUsersCSV(ctx context.Context, query *dto.GetUsersAdminQuery, w io.Writer) error
+	BanUser(ctx context.Context, userID string, req *dto.BanUserRequest) error
+	UnbanUser(ctx context.Context, userID string) error
+	SoftDeleteUser(ctx context.Context, userID string) error
+	RestoreUser(ctx context.Context, userID string) error
+	BulkUserAction(ctx context.Context, req *dto.BulkUserActionRequest) (*dto.BulkUserActionResponse, error)
+	EraseUser(ctx context.Context, adminID, userID string, req *dto.EraseUserRequest) (*dto.UserPurgeReport, error)
+	UpdateUserRole(ctx context.Context, adminID, userID string, req *dto.UpdateUserRoleRequest) (*dto.UserResponse, error)
+	ForceLogout(ctx context.Context, adminID, userID string) error
+	UpdateUser(ctx context.Context, adminID, userID string, req *dto.AdminUpdateUserRequest) (*dto.UserResponse, error)
+	GetUserNotes(ctx context.Context, userID string) ([]model.AdminNote, error)
+	AddUserNote(ctx context.Context, adminID, userID string, req *dto.AddUserNoteRequest) (*model.AdminNote, error)
 }
 
 type adminUserService struct {
-	userRepo repo.UserRepo
+	userRepo         repo.UserRepo
+	eventBus         bus.EventBus
+	transactor       repo.Transactor
+	outboxService    OutboxService
+	userPurgeService UserPurgeService
+	auditService     AuditService
+	redisClient      *redis.Client
+	countTTL         time.Duration
 }
 
-func NewAdminUserService(userRepo repo.UserRepo) AdminUserService {
+func NewAdminUserService(userRepo repo.UserRepo, eventBus bus.EventBus, transactor repo.Transactor, outboxService OutboxService, userPurgeService UserPurgeService, auditService AuditService, redisClient *redis.Client, cfg *config.UserCacheConfig) AdminUserService {
 	return &adminUserService{
-		userRepo: userRepo,
+		userRepo:         userRepo,
+		eventBus:         eventBus,
+		transactor:       transactor,
+		outboxService:    outboxService,
+		userPurgeService: userPurgeService,
+		auditService:     auditService,
+		redisClient:      redisClient,
+		countTTL:         time.Duration(cfg.AdminCountTTLSeconds) * time.Second,
 	}
 }
 
-func (s *adminUserService) GetUsersAdmin(query *dto.GetUsersAdminQuery) (*dto.PaginatedUsersResponse, error) {
-	ctx, cancel := util.NewDefaultDBContext()
-	defer cancel()
-
-	// Build filter based on status
-	filter := repo.Filter{}
+// saveUser updates user and stores the events of the change in the outbox, in one transaction
+func (s *adminUserService) saveUser(ctx context.Context, user *model.User, events ...bus.Event) (*model.User, error) {
+	var updated *model.User
+	err := s.transactor.Run(ctx, func(ctx context.Context) error {
+		var err error
+		if updated, err = s.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+		return s.outboxService.Add(ctx, events...)
+	})
+	return updated, err
+}
 
-	switch query.Status {
-	case "active":
-		filter["is_banned"] = false
-		filter["deleted_at"] = bson.M{"$exists": false}
-	case "banned":
-		filter["is_banned"] = true
-	case "deleted":
-		filter["deleted_at"] = bson.M{"$exists": true}
-	case "all":
-		// No filter - get all users
-	default:
-		// Default: active users only
-		filter["is_banned"] = false
-		filter["deleted_at"] = bson.M{"$exists": false}
-	}
+func (s *adminUserService) GetUsersAdmin(ctx context.Context, query *dto.GetUsersAdminQuery) (*dto.PaginatedUsersResponse, error) {
+	ctx, cancel := util.NewDBContextFrom(ctx)
+	defer cancel()
 
-	// Add username search if provided
-	if query.Username != "" {
-		filter["username"] = bson.M{"$regex": primitive.Regex{Pattern: query.Username, Options: "i"}}
+	filter, err := usersAdminFilter(query)
+	if err != nil {
+		return nil, err
 	}
 
 	// Pagination
@@ -74,13 +103,19 @@ func (s *adminUserService) GetUsersAdmin(query *dto.GetUsersAdminQuery) (*dto.Pa
 		pageSize = 100
 	}
 
-	findOptions := &repo.FindOptions{
-		Skip:  int64((page - 1) * pageSize),
-		Limit: int64(pageSize),
-		Sort:  map[string]int{"created_at": -1},
+	field, order := usersAdminSort(query)
+	userPage := repo.Page{Field: field, Order: order, Limit: pageSize}
+	if query.Cursor != "" {
+		after, err := repo.DecodeCursor(query.Cursor)
+		if err != nil {
+			return nil, apperror.ErrInvalidCursor
+		}
+		userPage.After = after
+	} else {
+		userPage.Skip = int64((page - 1) * pageSize)
 	}
 
-	users, total, err := s.userRepo.Find(ctx, filter, findOptions)
+	users, next, total, err := s.findUsersPage(ctx, query, filter, userPage)
 	if err != nil {
 		return nil, err
 	}
@@ -94,11 +129,209 @@ func (s *adminUserService) GetUsersAdmin(query *dto.GetUsersAdminQuery) (*dto.Pa
 			PageSize: pageSize,
 			Total:    total,
 		},
+		NextCursor: dto.NextCursor(next),
+		HasMore:    next != nil,
 	}, nil
 }
 
-func (s *adminUserService) BanUser(userID string, req *dto.BanUserRequest) error {
-	ctx, cancel := util.NewDefaultDBContext()
+// findUsersPage fetches a page of the admin user list. Admins page through the same filters repeatedly, so
+// the total is reused from Redis for a short while instead of counting on every page; the cached totals are
+// dropped whenever a user is created, changed or deleted.
+func (s *adminUserService) findUsersPage(ctx context.Context, query *dto.GetUsersAdminQuery, filter repo.Filter, page repo.Page) ([]*model.User, *repo.Cursor, int64, error) {
+	if s.countTTL <= 0 || s.redisClient == nil {
+		return s.userRepo.FindPage(ctx, filter, page)
+	}
+
+	signature := usersAdminFilterSignature(query)
+	total, err := s.redisClient.HGet(ctx, config.RedisAdminUserCountsKey, signature).Int64()
+	if err == nil {
+		users, next, err := s.userRepo.FindPageWithoutTotal(ctx, filter, page)
+		return users, next, total, err
+	}
+	if !errors.Is(err, redis.Nil) {
+		slog.Warn("Admin user count cache: read failed", "error", err)
+	}
+
+	users, next, total, err := s.userRepo.FindPage(ctx, filter, page)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	// The TTL runs from the first total cached, so no total outlives it
+	pipe := s.redisClient.TxPipeline()
+	pipe.HSet(ctx, config.RedisAdminUserCountsKey, signature, total)
+	pipe.ExpireNX(ctx, config.RedisAdminUserCountsKey, s.countTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		slog.Warn("Admin user count cache: write failed", "error", err)
+	}
+	return users, next, total, nil
+}
+
+// usersAdminFilterSignature identifies the filters of an admin user list query, ignoring sorting and paging
+// which do not change the total
+func usersAdminFilterSignature(query *dto.GetUsersAdminQuery) string {
+	verified := ""
+	if query.Verified != nil {
+		verified = strconv.FormatBool(*query.Verified)
+	}
+	raw := strings.Join([]string{
+		query.Status, query.Username, query.Email, query.Provider, verified,
+		query.CreatedFrom, query.CreatedTo, strconv.Itoa(query.ActiveDays),
+	}, "\x00")
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:16])
+}
+
+// usersExportHeader is the header row of the users CSV export
+var usersExportHeader = []string{
+	"id", "email", "username", "role", "provider", "is_verified", "status",
+	"ban_until", "ban_reason", "last_login", "last_login_ip", "created_at", "deleted_at",
+}
+
+// ExportUsersCSV streams every user matching the admin list filters to w as CSV.
+// Pagination in the query is ignored. ctx bounds the whole export rather than a single query,
+// since exporting tens of thousands of accounts outlasts the default DB timeout.
+// Invalid filters are reported before anything is written to w.
+func (s *adminUserService) ExportUsersCSV(ctx context.Context, query *dto.GetUsersAdminQuery, w io.Writer) error {
+	filter, err := usersAdminFilter(query)
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(usersExportHeader); err != nil {
+		return err
+	}
+
+	err = s.userRepo.Iterate(ctx, filter, func(user *model.User) error {
+		return writer.Write(userExportRow(user))
+	})
+	if err != nil {
+		return err
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// userExportRow formats a user as a CSV row matching usersExportHeader
+func userExportRow(user *model.User) []string {
+	status := "active"
+	switch {
+	case user.DeletedAt != nil:
+		status = "deleted"
+	case user.IsSelfDeactivated():
+		status = "deactivated"
+	case !user.IsActive:
+		status = "banned"
+	}
+
+	banReason := ""
+	if user.BanReason != nil {
+		banReason = *user.BanReason
+	}
+
+	return []string{
+		user.ID.Hex(),
+		user.Email,
+		user.Username,
+		string(user.Role),
+		string(user.Provider),
+		strconv.FormatBool(user.IsVerified),
+		status,
+		formatExportTime(user.BanUntil),
+		banReason,
+		formatExportTime(user.LastLogin),
+		user.LastLoginIP,
+		user.CreatedAt.UTC().Format(time.RFC3339),
+		formatExportTime(user.DeletedAt),
+	}
+}
+
+func formatExportTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// usersAdminFilter builds the user filter shared by the admin user list and export
+func usersAdminFilter(query *dto.GetUsersAdminQuery) (repo.Filter, error) {
+	// Build filter based on status
+	filter := repo.Filter{}
+
+	switch query.Status {
+	case "banned":
+		filter = repo.Filter(repo.BannedUserFilter())
+	case "deleted":
+		filter["deleted_at"] = bson.M{"$exists": true}
+	case "all":
+		// No filter - get all users
+	default:
+		// Active, the default: not deleted and not banned. Self-deactivated users are still listed.
+		filter["deleted_at"] = bson.M{"$exists": false}
+		filter["$nor"] = bson.A{repo.BannedUserFilter()}
+	}
+
+	// Username and email match by prefix. Anchored, case-sensitive patterns are bounded by the unique indexes
+	// instead of scanning every user.
+	if query.Username != "" {
+		filter["username"] = bson.M{"$regex": primitive.Regex{Pattern: "^" + regexp.QuoteMeta(query.Username)}}
+	}
+	if query.Email != "" {
+		filter["email"] = bson.M{"$regex": primitive.Regex{Pattern: "^" + regexp.QuoteMeta(query.Email)}}
+	}
+
+	if query.Provider != "" {
+		filter["provider"] = query.Provider
+	}
+	if query.Verified != nil {
+		filter["is_verified"] = *query.Verified
+	}
+
+	createdAt := bson.M{}
+	if query.CreatedFrom != "" {
+		from, err := time.ParseInLocation(analyticsDateLayout, query.CreatedFrom, statsLocation())
+		if err != nil {
+			return nil, apperror.ErrInvalidDateFormat
+		}
+		createdAt["$gte"] = from
+	}
+	if query.CreatedTo != "" {
+		to, err := time.ParseInLocation(analyticsDateLayout, query.CreatedTo, statsLocation())
+		if err != nil {
+			return nil, apperror.ErrInvalidDateFormat
+		}
+		createdAt["$lt"] = to.AddDate(0, 0, 1) // CreatedTo is inclusive
+	}
+	if len(createdAt) > 0 {
+		filter["created_at"] = createdAt
+	}
+
+	if query.ActiveDays > 0 {
+		filter["last_login"] = bson.M{"$gte": time.Now().AddDate(0, 0, -query.ActiveDays)}
+	}
+
+	return filter, nil
+}
+
+// usersAdminSort returns the sort field and order of the admin user list, newest first by default
+func usersAdminSort(query *dto.GetUsersAdminQuery) (string, int) {
+	field := query.SortBy
+	if field == "" {
+		field = "created_at"
+	}
+
+	order := -1
+	if query.SortOrder == "asc" {
+		order = 1
+	}
+
+	return field, order
+}
+
+func (s *adminUserService) BanUser(ctx context.Context, userID string, req *dto.BanUserRequest) error {
+	ctx, cancel := util.NewDBContextFrom(ctx)
 	defer cancel()
 
 	// Get user
@@ -120,12 +353,24 @@ func (s *adminUserService) BanUser(userID string, req *dto.BanUserRequest) error
 	user.BanUntil = req.BanUntil // null = permanent
 	user.BanReason = &req.Reason
 
-	_, err = s.userRepo.Update(ctx, user)
-	return err
+	// Also closes the user's open WebSocket connections
+	_, err = s.saveUser(ctx, user,
+		bus.UserUpdatedEvent{UserID: userID, Username: user.Username},
+		bus.SessionTerminatedEvent{UserID: userID, Reason: bus.SessionTerminatedBanned},
+	)
+	if err != nil {
+		return err
+	}
+
+	// Reject the user's existing tokens
+	if auth.TokenSvc != nil {
+		return auth.TokenSvc.InvalidateAllUserTokens(ctx, userID)
+	}
+	return nil
 }
 
-func (s *adminUserService) UnbanUser(userID string) error {
-	ctx, cancel := util.NewDefaultDBContext()
+func (s *adminUserService) UnbanUser(ctx context.Context, userID string) error {
+	ctx, cancel := util.NewDBContextFrom(ctx)
 	defer cancel()
 
 	// Get user
@@ -142,12 +387,12 @@ func (s *adminUserService) UnbanUser(userID string) error {
 	user.BanUntil = nil
 	user.BanReason = nil
 
-	_, err = s.userRepo.Update(ctx, user)
+	_, err = s.saveUser(ctx, user, bus.UserUpdatedEvent{UserID: userID, Username: user.Username})
 	return err
 }
 
-func (s *adminUserService) SoftDeleteUser(userID string) error {
-	ctx, cancel := util.NewDefaultDBContext()
+func (s *adminUserService) SoftDeleteUser(ctx context.Context, userID string) error {
+	ctx, cancel := util.NewDBContextFrom(ctx)
 	defer cancel()
 
 	// Get user
@@ -168,12 +413,24 @@ func (s *adminUserService) SoftDeleteUser(userID string) error {
 	now := time.Now()
 	user.DeletedAt = &now
 
-	_, err = s.userRepo.Update(ctx, user)
-	return err
+	// Also closes the user's open WebSocket connections
+	_, err = s.saveUser(ctx, user,
+		bus.UserDeletedEvent{UserID: userID, Username: user.Username},
+		bus.SessionTerminatedEvent{UserID: userID, Reason: bus.SessionTerminatedDeleted},
+	)
+	if err != nil {
+		return err
+	}
+
+	// Reject the user's existing tokens
+	if auth.TokenSvc != nil {
+		return auth.TokenSvc.InvalidateAllUserTokens(ctx, userID)
+	}
+	return nil
 }
 
-func (s *adminUserService) RestoreUser(userID string) error {
-	ctx, cancel := util.NewDefaultDBContext()
+func (s *adminUserService) RestoreUser(ctx context.Context, userID string) error {
+	ctx, cancel := util.NewDBContextFrom(ctx)
 	defer cancel()
 
 	// Get user
@@ -193,6 +450,417 @@ func (s *adminUserService) RestoreUser(userID string) error {
 	// Restore user
 	user.DeletedAt = nil
 
-	_, err = s.userRepo.Update(ctx, user)
+	// Tokens issued before the deletion stay rejected, the user signs in again
+	_, err = s.saveUser(ctx, user, bus.UserUpdatedEvent{UserID: userID, Username: user.Username})
 	return err
 }
+
+// EraseUser permanently erases a user and all associated data, whether or not the user is soft-deleted.
+// The erasure is written to the audit log first and cannot be undone.
+func (s *adminUserService) EraseUser(ctx context.Context, adminID, userID string, req *dto.EraseUserRequest) (*dto.UserPurgeReport, error) {
+	ctx, cancel := util.NewDBContextFrom(ctx)
+	defer cancel()
+
+	if _, err := primitive.ObjectIDFromHex(userID); err != nil {
+		return nil, apperror.ErrInvalidID
+	}
+
+	// GetByIDs also returns soft-deleted users
+	users, err := s.userRepo.GetByIDs(ctx, []string{userID})
+	if err != nil {
+		return nil, err
+	}
+	if len(users) == 0 {
+		return nil, apperror.ErrUserNotFound
+	}
+	user := users[0]
+
+	if user.Role == model.AdminRole {
+		return nil, apperror.ErrCannotModifyAdmin
+	}
+
+	metadata := map[string]string{"reason": req.Reason}
+	if err := s.auditService.Record(ctx, adminID, model.AuditActionEraseUser, model.AuditTargetUser, userID, metadata); err != nil {
+		return nil, err
+	}
+
+	report, err := s.userPurgeService.PurgeUser(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	s.eventBus.Publish(bus.UserDeletedEvent{UserID: userID, Username: user.Username})
+
+	s.eventBus.Publish(bus.SessionTerminatedEvent{UserID: userID, Reason: bus.SessionTerminatedDeleted})
+	return report, nil
+}
+
+// UpdateUserRole promotes a user to admin or demotes an admin to user.
+// Admins cannot demote themselves and the last admin cannot be demoted. The change is audited
+// and the user's tokens are invalidated, since they carry the old role.
+func (s *adminUserService) UpdateUserRole(ctx context.Context, adminID, userID string, req *dto.UpdateUserRoleRequest) (*dto.UserResponse, error) {
+	ctx, cancel := util.NewDBContextFrom(ctx)
+	defer cancel()
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, apperror.ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	newRole := model.Role(req.Role)
+	if user.Role == newRole {
+		return dto.FromUser(user), nil
+	}
+
+	if newRole == model.UserRole && userID == adminID {
+		return nil, apperror.ErrCannotDemoteSelf
+	}
+
+	// The last admin check, the save and its audit entry are one transaction, so concurrent demotions cannot
+	// together remove every admin and a change is audited only once it is saved
+	previousRole := user.Role
+	user.Role = newRole
+	user.UpdatedAt = time.Now()
+	err = s.transactor.Run(ctx, func(ctx context.Context) error {
+		if newRole == model.UserRole {
+			if err := s.userRepo.DemoteAdmin(ctx, userID); err != nil {
+				if errors.Is(err, mongo.ErrNoDocuments) {
+					return apperror.ErrUserNotFound
+				}
+				return err
+			}
+		}
+		if _, err := s.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+
+		metadata := map[string]string{"from": string(previousRole), "to": string(newRole)}
+		if err := s.auditService.Record(ctx, adminID, model.AuditActionChangeUserRole, model.AuditTargetUser, userID, metadata); err != nil {
+			return err
+		}
+		return s.outboxService.Add(ctx,
+			bus.UserUpdatedEvent{UserID: userID, Username: user.Username},
+			bus.SessionTerminatedEvent{UserID: userID, Reason: bus.SessionTerminatedRoleChanged},
+		)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Tokens carry the role, so the user signs in again to pick up the new one. Tokens issued from then on are
+	// accepted. A demoted admin's old tokens must not keep working, so a failure is returned.
+	if auth.TokenSvc != nil {
+		if err := auth.TokenSvc.InvalidateAllUserTokens(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	return dto.FromUser(user), nil
+}
+
+// UpdateUser applies an admin's support edits to a user: a new username, the email verified flag,
+// or a reset of settings. The edited fields and their previous values are audited.
+func (s *adminUserService) UpdateUser(ctx context.Context, adminID, userID string, req *dto.AdminUpdateUserRequest) (*dto.UserResponse, error) {
+	ctx, cancel := util.NewDBContextFrom(ctx)
+	defer cancel()
+
+	if req.Username == "" && req.IsVerified == nil && !req.ResetSettings {
+		return nil, apperror.ErrBadRequest
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, apperror.ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	previousUsername := user.Username
+	metadata := map[string]string{}
+	if req.Username != "" && req.Username != user.Username {
+		metadata["username_from"] = user.Username
+		metadata["username_to"] = req.Username
+		if err := changeUsername(ctx, s.userRepo, user, req.Username); err != nil {
+			return nil, err
+		}
+	}
+	if req.IsVerified != nil && *req.IsVerified != user.IsVerified {
+		metadata["is_verified"] = strconv.FormatBool(*req.IsVerified)
+		user.IsVerified = *req.IsVerified
+	}
+	if req.ResetSettings {
+		metadata["reset_settings"] = "true"
+		user.Settings = model.NewDefaultSettings()
+	}
+
+	// Nothing differs from the current values
+	if len(metadata) == 0 {
+		return dto.FromUser(user), nil
+	}
+
+	if err := s.auditService.Record(ctx, adminID, model.AuditActionUpdateUser, model.AuditTargetUser, userID, metadata); err != nil {
+		return nil, err
+	}
+
+	user.UpdatedAt = time.Now()
+	updatedUser, err := s.saveUser(ctx, user, bus.UserUpdatedEvent{UserID: userID, Username: user.Username, PreviousUsername: previousUsername})
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.FromUser(updatedUser), nil
+}
+
+// ForceLogout signs a user out everywhere, e.g. when their account was stolen: all tokens are
+// invalidated and open WebSocket connections are closed. The account itself stays active.
+func (s *adminUserService) ForceLogout(ctx context.Context, adminID, userID string) error {
+	if auth.TokenSvc == nil {
+		return apperror.ErrInternal
+	}
+
+	ctx, cancel := util.NewDBContextFrom(ctx)
+	defer cancel()
+
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return apperror.ErrUserNotFound
+		}
+		return err
+	}
+
+	if err := s.auditService.Record(ctx, adminID, model.AuditActionForceLogout, model.AuditTargetUser, userID, nil); err != nil {
+		return err
+	}
+
+	if err := auth.TokenSvc.InvalidateAllUserTokens(ctx, userID); err != nil {
+		return err
+	}
+
+	s.eventBus.Publish(bus.SessionTerminatedEvent{UserID: userID, Reason: bus.SessionTerminatedForceLogout})
+	return nil
+}
+
+// GetUserNotes returns the support notes left on a user, oldest first
+func (s *adminUserService) GetUserNotes(ctx context.Context, userID string) ([]model.AdminNote, error) {
+	ctx, cancel := util.NewDBContextFrom(ctx)
+	defer cancel()
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, apperror.ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	if user.AdminNotes == nil {
+		return []model.AdminNote{}, nil
+	}
+	return user.AdminNotes, nil
+}
+
+// AddUserNote appends a support note to a user, signed with the admin's current username
+func (s *adminUserService) AddUserNote(ctx context.Context, adminID, userID string, req *dto.AddUserNoteRequest) (*model.AdminNote, error) {
+	ctx, cancel := util.NewDBContextFrom(ctx)
+	defer cancel()
+
+	content := strings.TrimSpace(req.Content)
+	if content == "" {
+		return nil, apperror.ErrBadRequest
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, apperror.ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	author, err := s.userRepo.GetByID(ctx, adminID)
+	if err != nil {
+		return nil, err
+	}
+
+	note := &model.AdminNote{
+		ID:             primitive.NewObjectID(),
+		AuthorID:       author.ID,
+		AuthorUsername: author.Username,
+		Content:        content,
+		CreatedAt:      time.Now(),
+	}
+
+	metadata := map[string]string{"note_id": note.ID.Hex()}
+	if err := s.auditService.Record(ctx, adminID, model.AuditActionAddUserNote, model.AuditTargetUser, userID, metadata); err != nil {
+		return nil, err
+	}
+
+	err = s.transactor.Run(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.AddAdminNote(ctx, userID, note); err != nil {
+			return err
+		}
+		return s.outboxService.Add(ctx, bus.UserUpdatedEvent{UserID: userID, Username: user.Username})
+	})
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, apperror.ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	return note, nil
+}
+
+// BulkUserAction applies one action to many users: users are loaded in one query, checked
+// individually, and every eligible user is updated in a single repo operation.
+// Per-user failures are reported in the response; only a failed update fails the whole call.
+func (s *adminUserService) BulkUserAction(ctx context.Context, req *dto.BulkUserActionRequest) (*dto.BulkUserActionResponse, error) {
+	ctx, cancel := util.NewDBContextFrom(ctx)
+	defer cancel()
+
+	if req.Action == dto.BulkActionBan && strings.TrimSpace(req.Reason) == "" {
+		return nil, apperror.ErrBadRequest // Ban reason is required
+	}
+
+	// Deduplicate, keeping the request order for the results
+	seen := make(map[string]bool, len(req.UserIDs))
+	userIDs := make([]string, 0, len(req.UserIDs))
+	for _, id := range req.UserIDs {
+		if !seen[id] {
+			seen[id] = true
+			userIDs = append(userIDs, id)
+		}
+	}
+
+	users, err := s.userRepo.GetByIDs(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	usersByID := make(map[string]*model.User, len(users))
+	for _, user := range users {
+		usersByID[user.ID.Hex()] = user
+	}
+
+	response := &dto.BulkUserActionResponse{
+		Action:  req.Action,
+		Results: make([]dto.BulkUserActionResult, len(userIDs)),
+	}
+	var eligible []primitive.ObjectID
+	for i, id := range userIDs {
+		response.Results[i].UserID = id
+		if err := checkBulkEligibility(req.Action, id, usersByID[id]); err != nil {
+			response.Results[i].ErrorCode = apperror.Code(err)
+			response.Results[i].Message = apperror.Message(err)
+			response.Failed++
+			continue
+		}
+		response.Results[i].Success = true
+		response.Succeeded++
+		eligible = append(eligible, usersByID[id].ID)
+	}
+
+	var events []bus.Event
+	for _, id := range eligible {
+		events = append(events, bulkUserEvents(req.Action, usersByID[id.Hex()])...)
+	}
+	err = s.transactor.Run(ctx, func(ctx context.Context) error {
+		if _, err := s.userRepo.UpdateManyByIDs(ctx, eligible, bulkUserUpdate(req)); err != nil {
+			return err
+		}
+		return s.outboxService.Add(ctx, events...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range eligible {
+		s.afterBulkUserAction(ctx, req.Action, usersByID[id.Hex()])
+	}
+
+	return response, nil
+}
+
+// checkBulkEligibility applies the same rules as the single-user endpoints
+func checkBulkEligibility(action, id string, user *model.User) error {
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return apperror.ErrInvalidID
+	}
+	if user == nil {
+		return apperror.ErrUserNotFound
+	}
+
+	if action == dto.BulkActionRestore {
+		if user.DeletedAt == nil {
+			return apperror.ErrUserNotDeleted
+		}
+		return nil
+	}
+
+	// Ban, unban and delete only apply to users that are not deleted
+	if user.DeletedAt != nil {
+		return apperror.ErrUserNotFound
+	}
+	if user.Role == model.AdminRole && (action == dto.BulkActionBan || action == dto.BulkActionDelete) {
+		return apperror.ErrCannotModifyAdmin
+	}
+	return nil
+}
+
+// bulkUserUpdate builds the update document for a bulk action
+func bulkUserUpdate(req *dto.BulkUserActionRequest) bson.M {
+	switch req.Action {
+	case dto.BulkActionBan:
+		update := bson.M{"$set": bson.M{"is_active": false, "ban_reason": req.Reason}}
+		if req.BanUntil != nil {
+			update["$set"].(bson.M)["ban_until"] = *req.BanUntil
+		} else {
+			update["$unset"] = bson.M{"ban_until": ""} // Permanent ban
+		}
+		return update
+	case dto.BulkActionUnban:
+		return bson.M{
+			"$set":   bson.M{"is_active": true},
+			"$unset": bson.M{"ban_until": "", "ban_reason": ""},
+		}
+	case dto.BulkActionDelete:
+		return bson.M{"$set": bson.M{"deleted_at": time.Now()}}
+	default: // restore
+		return bson.M{"$unset": bson.M{"deleted_at": ""}}
+	}
+}
+
+// bulkUserEvents returns the events the single-user endpoints publish for a user after the action
+func bulkUserEvents(action string, user *model.User) []bus.Event {
+	userID := user.ID.Hex()
+	switch action {
+	case dto.BulkActionBan:
+		return []bus.Event{
+			bus.UserUpdatedEvent{UserID: userID, Username: user.Username},
+			bus.SessionTerminatedEvent{UserID: userID, Reason: bus.SessionTerminatedBanned},
+		}
+	case dto.BulkActionDelete:
+		return []bus.Event{
+			bus.UserDeletedEvent{UserID: userID, Username: user.Username},
+			bus.SessionTerminatedEvent{UserID: userID, Reason: bus.SessionTerminatedDeleted},
+		}
+	default: // unban, restore
+		return []bus.Event{bus.UserUpdatedEvent{UserID: userID, Username: user.Username}}
+	}
+}
+
+// afterBulkUserAction runs the per-user side effects of the single-user endpoints besides their events.
+// The users are already updated, so failures are logged rather than reported.
+func (s *adminUserService) afterBulkUserAction(ctx context.Context, action string, user *model.User) {
+	userID := user.ID.Hex()
+	switch action {
+	case dto.BulkActionBan, dto.BulkActionDelete:
+		if auth.TokenSvc != nil {
+			if err := auth.TokenSvc.InvalidateAllUserTokens(ctx, userID); err != nil {
+				slog.Error("Bulk "+action+": failed to invalidate tokens", "user_id", userID, "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/apperror"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/repo"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/util"
+)
+
+const (
+	maxAnalyticsDays    = 180
+	analyticsTopLimit   = 20
+	analyticsDateLayout = "2006-01-02"
+)
+
+// topicStopwords are common Vietnamese and English words excluded from question topics
+var topicStopwords = []string{
+	// Vietnamese
+	"là", "của", "và", "có", "không", "cho", "em", "mình", "tôi", "được", "các", "những", "thì", "này",
+	"ở", "với", "để", "trong", "khi", "như", "nào", "gì", "ạ", "ơi", "bao", "nhiêu", "làm", "sao",
+	"thế", "vậy", "nếu", "về", "ra", "vào", "đã", "sẽ", "đang", "một", "hay", "hoặc", "cần", "muốn",
+	"bạn", "ad", "admin", "giúp", "hỏi", "mà", "thể", "bị", "theo", "từ", "đến", "tại", "còn",
+	// English
+	"the", "is", "are", "a", "an", "of", "to", "in", "for", "and", "or", "what", "how", "can", "i",
+	"do", "does", "my", "me", "it", "on", "at", "be", "with", "about", "when", "where", "which",
+}
+
+// AnalyticsService aggregates chat usage for admins
+type AnalyticsService interface {
+	GetChatAnalytics(ctx context.Context, query *dto.ChatAnalyticsQuery) (*dto.ChatAnalyticsResponse, error)
+}
+
+type analyticsService struct {
+	chatAnalyticsRepo repo.ChatAnalyticsRepo
+}
+
+func NewAnalyticsService(chatAnalyticsRepo repo.ChatAnalyticsRepo) AnalyticsService {
+	return &analyticsService{
+		chatAnalyticsRepo: chatAnalyticsRepo,
+	}
+}
+
+func (s *analyticsService) GetChatAnalytics(ctx context.Context, query *dto.ChatAnalyticsQuery) (*dto.ChatAnalyticsResponse, error) {
+	from, to, err := parseAnalyticsRange(query.From, query.To)
+	if err != nil {
+		return nil, err
+	}
+	// Exclusive upper bound for the queries
+	end := to.AddDate(0, 0, 1)
+
+	ctx, cancel := util.NewDBContextFrom(ctx)
+	defer cancel()
+
+	daily, err := s.chatAnalyticsRepo.GetDailyStats(ctx, from, end)
+	if err != nil {
+		return nil, err
+	}
+	toolUsage, err := s.chatAnalyticsRepo.GetToolUsage(ctx, from, end, analyticsTopLimit)
+	if err != nil {
+		return nil, err
+	}
+	topics, err := s.chatAnalyticsRepo.GetTopKeywords(ctx, from, end, topicStopwords, analyticsTopLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.ChatAnalyticsResponse{
+		From:      from.Format(analyticsDateLayout),
+		To:        to.Format(analyticsDateLayout),
+		Daily:     fillDailyStats(daily, from, end),
+		ToolUsage: derefAll(toolUsage),
+		TopTopics: derefAll(topics),
+	}, nil
+}
+
+// parseAnalyticsRange parses the inclusive date range in the stats timezone, defaulting to the last 30 days
+func parseAnalyticsRange(fromStr, toStr string) (time.Time, time.Time, error) {
+	loc, err := time.LoadLocation(repo.StatsTimezone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	now := time.Now().In(loc)
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	if toStr != "" {
+		if to, err = time.ParseInLocation(analyticsDateLayout, toStr, loc); err != nil {
+			return time.Time{}, time.Time{}, apperror.ErrInvalidDateFormat
+		}
+	}
+
+	from := to.AddDate(0, 0, -(defaultStatsDays - 1))
+	if fromStr != "" {
+		if from, err = time.ParseInLocation(analyticsDateLayout, fromStr, loc); err != nil {
+			return time.Time{}, time.Time{}, apperror.ErrInvalidDateFormat
+		}
+	}
+
+	if from.After(to) || to.Sub(from) >= maxAnalyticsDays*24*time.Hour {
+		return time.Time{}, time.Time{}, apperror.ErrInvalidDateRange
+	}
+
+	return from, to, nil
+}
+
+// fillDailyStats returns one entry per day in [from, end), using zero stats for days without messages
+func fillDailyStats(stats []*model.ChatDailyStats, from, end time.Time) []model.ChatDailyStats {
+	byDate := make(map[string]*model.ChatDailyStats, len(stats))
+	for _, st := range stats {
+		byDate[st.Date] = st
+	}
+
+	var series []model.ChatDailyStats
+	for day := from; day.Before(end); day = day.AddDate(0, 0, 1) {
+		date := day.Format(analyticsDateLayout)
+		if st, ok := byDate[date]; ok {
+			series = append(series, *st)
+		} else {
+			series = append(series, model.ChatDailyStats{Date: date})
+		}
+	}
+	return series
+}
+
+func derefAll[T any](items []*T) []T {
+	values := make([]T, len(items))
+	for i, item := range items {
+		values[i] = *item
+	}
+	return values
+}
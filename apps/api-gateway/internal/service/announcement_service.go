@@ -0,0 +1,314 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/apperror"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/bus"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/repo"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/util"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// announcementBatchSize is the number of recipients loaded and notified per batch
+const announcementBatchSize = 500
+
+type AnnouncementService interface {
+	CreateAnnouncement(ctx context.Context, adminID string, req *dto.CreateAnnouncementRequest) (*dto.AnnouncementResponse, error)
+	GetAnnouncements(ctx context.Context, page, pageSize int) (*dto.PaginatedAnnouncementsResponse, error)
+	GetAnnouncement(ctx context.Context, id string) (*dto.AnnouncementResponse, error)
+	UpdateAnnouncement(ctx context.Context, id string, req *dto.UpdateAnnouncementRequest) (*dto.AnnouncementResponse, error)
+	DeleteAnnouncement(ctx context.Context, id string) error
+	SendAnnouncement(ctx context.Context, id string) (*dto.AnnouncementResponse, error)
+}
+
+type announcementService struct {
+	announcementRepo    repo.AnnouncementRepo
+	userRepo            repo.UserRepo
+	notificationService NotificationService
+	eventBus            bus.EventBus
+}
+
+func NewAnnouncementService(
+	announcementRepo repo.AnnouncementRepo,
+	userRepo repo.UserRepo,
+	notificationService NotificationService,
+	eventBus bus.EventBus,
+) AnnouncementService {
+	return &announcementService{
+		announcementRepo:    announcementRepo,
+		userRepo:            userRepo,
+		notificationService: notificationService,
+		eventBus:            eventBus,
+	}
+}
+
+func (s *announcementService) CreateAnnouncement(ctx context.Context, adminID string, req *dto.CreateAnnouncementRequest) (*dto.AnnouncementResponse, error) {
+	ctx, cancel := util.NewDBContextFrom(ctx)
+	defer cancel()
+
+	adminObjID, err := primitive.ObjectIDFromHex(adminID)
+	if err != nil {
+		return nil, apperror.ErrInvalidID
+	}
+
+	announcement, err := s.announcementRepo.Create(ctx, &model.Announcement{
+		Title:       req.Title,
+		Message:     req.Message,
+		Link:        req.Link,
+		Segment:     req.Segment.ToModel(),
+		BroadcastWS: req.BroadcastWS,
+		Status:      model.AnnouncementStatusDraft,
+		CreatedBy:   adminObjID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := dto.FromAnnouncement(announcement)
+	return &response, nil
+}
+
+func (s *announcementService) GetAnnouncements(ctx context.Context, page, pageSize int) (*dto.PaginatedAnnouncementsResponse, error) {
+	ctx, cancel := util.NewDBContextFrom(ctx)
+	defer cancel()
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	announcements, total, err := s.announcementRepo.Find(ctx, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.PaginatedAnnouncementsResponse{
+		Announcements: dto.FromAnnouncements(announcements),
+		Pagination: dto.Pagination{
+			Page:     page,
+			PageSize: pageSize,
+			Total:    total,
+		},
+	}, nil
+}
+
+func (s *announcementService) GetAnnouncement(ctx context.Context, id string) (*dto.AnnouncementResponse, error) {
+	ctx, cancel := util.NewDBContextFrom(ctx)
+	defer cancel()
+
+	announcement, err := s.getAnnouncement(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := dto.FromAnnouncement(announcement)
+	return &response, nil
+}
+
+func (s *announcementService) UpdateAnnouncement(ctx context.Context, id string, req *dto.UpdateAnnouncementRequest) (*dto.AnnouncementResponse, error) {
+	ctx, cancel := util.NewDBContextFrom(ctx)
+	defer cancel()
+
+	announcement, err := s.getAnnouncement(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !announcement.IsEditable() {
+		return nil, apperror.ErrAnnouncementNotEditable
+	}
+
+	if req.Title != nil {
+		announcement.Title = *req.Title
+	}
+	if req.Message != nil {
+		announcement.Message = *req.Message
+	}
+	if req.Link != nil {
+		announcement.Link = *req.Link
+	}
+	if req.Segment != nil {
+		announcement.Segment = req.Segment.ToModel()
+	}
+	if req.BroadcastWS != nil {
+		announcement.BroadcastWS = *req.BroadcastWS
+	}
+
+	updated, err := s.announcementRepo.Update(ctx, announcement)
+	if err != nil {
+		return nil, err
+	}
+
+	response := dto.FromAnnouncement(updated)
+	return &response, nil
+}
+
+func (s *announcementService) DeleteAnnouncement(ctx context.Context, id string) error {
+	ctx, cancel := util.NewDBContextFrom(ctx)
+	defer cancel()
+
+	announcement, err := s.getAnnouncement(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// Deleting while delivery is running would leave the announcement half-sent
+	if announcement.Status == model.AnnouncementStatusSending {
+		return apperror.ErrAnnouncementNotEditable
+	}
+
+	return s.announcementRepo.Delete(ctx, id)
+}
+
+// SendAnnouncement marks the announcement as sending and delivers it in the background.
+// The returned status is "sending"; poll GetAnnouncement for the final recipient count.
+func (s *announcementService) SendAnnouncement(ctx context.Context, id string) (*dto.AnnouncementResponse, error) {
+	ctx, cancel := util.NewDBContextFrom(ctx)
+	defer cancel()
+
+	announcement, err := s.getAnnouncement(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !announcement.IsEditable() {
+		return nil, apperror.ErrAnnouncementNotEditable
+	}
+
+	announcement.Status = model.AnnouncementStatusSending
+	announcement.RecipientCount = 0
+	if _, err := s.announcementRepo.Update(ctx, announcement); err != nil {
+		return nil, err
+	}
+
+	go s.deliver(model.CloneAnnouncement(announcement))
+
+	response := dto.FromAnnouncement(announcement)
+	return &response, nil
+}
+
+// deliver fans the announcement out as notifications to every user in its segment, batch by batch
+func (s *announcementService) deliver(announcement *model.Announcement) {
+	filter := segmentFilter(announcement.Segment)
+	message := announcement.Title + ": " + announcement.Message
+	metadata := map[string]interface{}{"announcement_id": announcement.ID.Hex()}
+
+	var banner *dto.AnnouncementBanner
+	if announcement.BroadcastWS {
+		banner = dto.NewAnnouncementBanner(announcement)
+	}
+
+	var delivered int64
+	var deliverErr error
+	for skip := int64(0); ; skip += announcementBatchSize {
+		ctx, cancel := util.NewDefaultDBContext()
+		users, _, err := s.userRepo.Find(ctx, filter, &repo.FindOptions{
+			Sort:  bson.D{{Key: "_id", Value: 1}},
+			Skip:  skip,
+			Limit: announcementBatchSize,
+		})
+		cancel()
+		if err != nil {
+			deliverErr = err
+			break
+		}
+		if len(users) == 0 {
+			break
+		}
+
+		notifications, err := s.notificationService.CreateBulkNotifications(ctx, users, model.NotificationTypeAnnouncement, message, announcement.Link, &model.NotificationData{
+			EntityType: model.EntityTypeAnnouncement,
+			EntityID:   announcement.ID.Hex(),
+			Action:     model.NotificationActionView,
+		}, metadata)
+		if err != nil {
+			deliverErr = err
+			break
+		}
+		delivered += int64(len(notifications))
+
+		event := bus.AnnouncementSentEvent{
+			Notifications: make(map[string]dto.NotificationResponse, len(notifications)),
+			Announcement:  banner,
+		}
+		for _, n := range notifications {
+			event.Notifications[n.RecipientID.Hex()] = dto.FromNotification(n)
+		}
+		for _, u := range users {
+			event.RecipientIDs = append(event.RecipientIDs, u.ID.Hex())
+		}
+		s.eventBus.Publish(event)
+
+		if len(users) < announcementBatchSize {
+			break
+		}
+	}
+
+	announcement.RecipientCount = delivered
+	if deliverErr != nil {
+		slog.Error("Announcement: delivery failed", "announcement_id", announcement.ID.Hex(), "delivered", delivered, "error", deliverErr)
+		announcement.Status = model.AnnouncementStatusFailed
+	} else {
+		now := time.Now()
+		announcement.Status = model.AnnouncementStatusSent
+		announcement.SentAt = &now
+		slog.Info("Announcement: delivered", "announcement_id", announcement.ID.Hex(), "delivered", delivered)
+	}
+
+	ctx, cancel := util.NewDefaultDBContext()
+	defer cancel()
+	if _, err := s.announcementRepo.Update(ctx, announcement); err != nil {
+		slog.Error("Announcement: failed to save delivery status", "announcement_id", announcement.ID.Hex(), "error", err)
+	}
+}
+
+func (s *announcementService) getAnnouncement(ctx context.Context, id string) (*model.Announcement, error) {
+	if !primitive.IsValidObjectID(id) {
+		return nil, apperror.ErrInvalidID
+	}
+
+	announcement, err := s.announcementRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, apperror.ErrAnnouncementNotFound
+		}
+		return nil, err
+	}
+	return announcement, nil
+}
+
+// segmentFilter builds the user filter for an announcement segment. Deleted and banned users are always excluded.
+func segmentFilter(segment model.AnnouncementSegment) repo.Filter {
+	filter := repo.Filter{
+		"is_active":  true,
+		"deleted_at": bson.M{"$exists": false},
+	}
+
+	if len(segment.Roles) > 0 {
+		filter["role"] = bson.M{"$in": segment.Roles}
+	}
+	if len(segment.Languages) > 0 {
+		filter["settings.language"] = bson.M{"$in": segment.Languages}
+	}
+	if segment.VerifiedOnly {
+		filter["is_verified"] = true
+	}
+	if segment.FeatureSubscribersOnly {
+		filter["settings.notify_new_features"] = true
+	}
+
+	return filter
+}
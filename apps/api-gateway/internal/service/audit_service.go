@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/apperror"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/repo"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AuditService records privileged admin actions
+type AuditService interface {
+	Record(ctx context.Context, actorID string, action model.AuditAction, targetType, targetID string, metadata map[string]string) error
+}
+
+type auditService struct {
+	auditLogRepo repo.AuditLogRepo
+}
+
+func NewAuditService(auditLogRepo repo.AuditLogRepo) AuditService {
+	return &auditService{
+		auditLogRepo: auditLogRepo,
+	}
+}
+
+// Record writes an audit log entry. Callers must not perform the audited action
+// if recording fails, so every access leaves a trace.
+func (s *auditService) Record(ctx context.Context, actorID string, action model.AuditAction, targetType, targetID string, metadata map[string]string) error {
+	actorObjID, err := primitive.ObjectIDFromHex(actorID)
+	if err != nil {
+		return apperror.ErrInvalidID
+	}
+
+	return s.auditLogRepo.Create(ctx, &model.AuditLog{
+		ActorID:    actorObjID,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Metadata:   metadata,
+	})
+}
@@ -1,8 +1,10 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"math/rand"
 	"regexp"
 	"time"
@@ -11,7 +13,7 @@ import (
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/auth"
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
-	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/email"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/bus"
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/repo"
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/util"
 	"github.com/golang-jwt/jwt/v5"
@@ -24,6 +26,7 @@ import (
 const (
 	StatusLoginSuccess  = "LOGIN_SUCCESS"
 	StatusSetupRequired = "SETUP_REQUIRED"
+	StatusLinkRequired  = "LINK_REQUIRED" // Email belongs to a local account, confirm its password to link Google
 )
 
 // GoogleAuthResult is the result of processing a Google OAuth callback.
@@ -33,44 +36,50 @@ type GoogleAuthResult struct {
 	AccessToken  string
 	RefreshToken string
 	SetupToken   string
+	LinkToken    string
 }
 
 type AuthService interface {
 	// Local Auth - New Flow (Verify Email First)
-	SendEmailVerification(email string) error
-	VerifyEmailCode(email, otp string) (string, error) // Returns verification_token
-	CompleteRegistration(verificationToken, username, password string) (*model.User, string, string, error)
-	ResendOTP(email string) error
-	Login(identifier, password string) (*model.User, string, string, error)
-	RefreshToken(refreshToken string) (string, string, error)
-	Logout(accessToken, refreshToken string) error
+	SendEmailVerification(ctx context.Context, email string) error
+	VerifyEmailCode(ctx context.Context, email, otp string) (string, error) // Returns verification_token
+	CompleteRegistration(ctx context.Context, verificationToken, username, password, clientIP string) (*model.User, string, string, error)
+	ResendOTP(ctx context.Context, email string) error
+	Login(ctx context.Context, identifier, password, clientIP string) (*model.User, string, string, error)
+	RefreshToken(ctx context.Context, refreshToken, clientIP string) (string, string, error)
+	Logout(ctx context.Context, accessToken, refreshToken string) error
 
 	// Google OAuth
-	ProcessGoogleCallback(code string) (*GoogleAuthResult, error)
-	CompleteGoogleSetup(setupToken, username string) (*model.User, string, string, error)
+	ProcessGoogleCallback(ctx context.Context, code, clientIP string) (*GoogleAuthResult, error)
+	CompleteGoogleSetup(ctx context.Context, setupToken, username, clientIP string) (*model.User, string, string, error)
+	CompleteAccountLink(ctx context.Context, linkToken, password, clientIP string) (*model.User, string, string, error)
 }
 
 type authService struct {
 	userRepo              repo.UserRepo
 	emailVerificationRepo repo.EmailVerificationRepo
-	emailSender           email.Sender
+	emailQueue            EmailQueueService
 	redisClient           *redis.Client
+	eventBus              bus.EventBus
+	transactor            repo.Transactor
 }
 
-func NewAuthService(userRepo repo.UserRepo, emailVerificationRepo repo.EmailVerificationRepo, emailSender email.Sender, redisClient *redis.Client) AuthService {
+func NewAuthService(userRepo repo.UserRepo, emailVerificationRepo repo.EmailVerificationRepo, emailQueue EmailQueueService, redisClient *redis.Client, eventBus bus.EventBus, transactor repo.Transactor) AuthService {
 	return &authService{
 		userRepo:              userRepo,
 		emailVerificationRepo: emailVerificationRepo,
-		emailSender:           emailSender,
+		emailQueue:            emailQueue,
 		redisClient:           redisClient,
+		eventBus:              eventBus,
+		transactor:            transactor,
 	}
 }
 
 // --- Local Authentication - New Flow (Verify Email First) ---
 
 // SendEmailVerification initiates the registration process by sending OTP to email
-func (s *authService) SendEmailVerification(email string) error {
-	ctx, cancel := util.NewDefaultDBContext()
+func (s *authService) SendEmailVerification(ctx context.Context, email string) error {
+	ctx, cancel := util.NewDBContextFrom(ctx)
 	defer cancel()
 
 	// Check if email already registered
@@ -103,18 +112,19 @@ func (s *authService) SendEmailVerification(email string) error {
 	}
 
 	// Send OTP email
-	go func() {
-		if err := s.emailSender.SendVerificationEmail(email, otp); err != nil {
-			fmt.Printf("CRITICAL: Failed to send verification email to %s: %v\n", email, err)
-		}
-	}()
+	err = s.emailQueue.SendVerificationEmail(email, otp)
+	otpEmails.WithLabel("send", metricResult(err)).Inc()
+	if err != nil {
+		slog.Error("Failed to queue verification email", "to", email, "error", err)
+		return err
+	}
 
 	return nil
 }
 
 // VerifyEmailCode verifies the OTP and returns a verification_token
-func (s *authService) VerifyEmailCode(email, otp string) (string, error) {
-	ctx, cancel := util.NewDefaultDBContext()
+func (s *authService) VerifyEmailCode(ctx context.Context, email, otp string) (string, error) {
+	ctx, cancel := util.NewDBContextFrom(ctx)
 	defer cancel()
 
 	verification, err := s.emailVerificationRepo.GetByEmail(ctx, email)
@@ -153,44 +163,18 @@ func (s *authService) VerifyEmailCode(email, otp string) (string, error) {
 	return verificationToken, nil
 }
 
-// CompleteRegistration creates the user account after email verification
-func (s *authService) CompleteRegistration(verificationToken, username, password string) (*model.User, string, string, error) {
+// CompleteRegistration creates the user account after email verification. It is idempotent: a retry with the same
+// verification token, username and password signs in to the account the first call created.
+func (s *authService) CompleteRegistration(ctx context.Context, verificationToken, username, password, clientIP string) (*model.User, string, string, error) {
 	// Parse verification token
 	claims, err := auth.ParseVerificationToken(verificationToken)
 	if err != nil {
 		return nil, "", "", err
 	}
 
-	ctx, cancel := util.NewDefaultDBContext()
+	ctx, cancel := util.NewDBContextFrom(ctx)
 	defer cancel()
 
-	// Verify the nonce matches (prevent replay)
-	verification, err := s.emailVerificationRepo.GetByEmail(ctx, claims.Email)
-	if err != nil {
-		if errors.Is(err, mongo.ErrNoDocuments) {
-			return nil, "", "", apperror.ErrInvalidToken
-		}
-		return nil, "", "", err
-	}
-
-	if !verification.IsVerified {
-		return nil, "", "", apperror.ErrEmailNotVerified
-	}
-
-	if verification.Nonce != claims.Nonce {
-		return nil, "", "", apperror.ErrInvalidToken
-	}
-
-	// Check username availability
-	if _, err := s.userRepo.GetByUsername(ctx, username); !errors.Is(err, mongo.ErrNoDocuments) {
-		return nil, "", "", apperror.ErrUsernameExists
-	}
-
-	// Double-check email not taken (race condition prevention)
-	if _, err := s.userRepo.GetByEmail(ctx, claims.Email); !errors.Is(err, mongo.ErrNoDocuments) {
-		return nil, "", "", apperror.ErrEmailExists
-	}
-
 	// Hash password
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
@@ -211,29 +195,93 @@ func (s *authService) CompleteRegistration(verificationToken, username, password
 		UpdatedAt:  time.Now(),
 	}
 
-	createdUser, err := s.userRepo.Create(ctx, user)
+	// The verification is checked and consumed in the same transaction that creates the account, so it is never
+	// consumed without one. Concurrent completions with the same token conflict on consuming it; the transaction
+	// that loses is retried and finds the account the other one created.
+	var createdUser *model.User
+	var created bool
+	err = s.transactor.Run(ctx, func(ctx context.Context) error {
+		created = false
+
+		// Verify the nonce matches (prevent replay)
+		verification, err := s.emailVerificationRepo.GetByEmail(ctx, claims.Email)
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				return apperror.ErrInvalidToken
+			}
+			return err
+		}
+		if !verification.IsVerified {
+			return apperror.ErrEmailNotVerified
+		}
+		if verification.Nonce != claims.Nonce {
+			return apperror.ErrInvalidToken
+		}
+
+		if verification.UserID != nil {
+			createdUser, err = s.completedRegistration(ctx, verification.UserID.Hex(), username, password)
+			return err
+		}
+
+		if err := s.checkAvailable(ctx, username, claims.Email); err != nil {
+			return err
+		}
+
+		if createdUser, err = s.userRepo.Create(ctx, user); err != nil {
+			return err
+		}
+		created = true
+		if err := s.emailVerificationRepo.Complete(ctx, claims.Email, claims.Nonce, createdUser.ID); err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				return apperror.ErrInvalidToken
+			}
+			return err
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, "", "", err
 	}
 
-	// Delete verification record (cleanup)
-	_ = s.emailVerificationRepo.Delete(ctx, claims.Email)
-
-	// Invalidate username cache
-	s.invalidateUsernameCache(username)
+	if created {
+		// The username is taken now
+		s.eventBus.Publish(bus.UserUpdatedEvent{UserID: createdUser.ID.Hex(), Username: username})
+	}
 
 	// Generate access & refresh tokens
 	accessToken, refreshToken, err := auth.GenerateToken(createdUser.ID.Hex(), string(createdUser.Role))
 	if err != nil {
 		return nil, "", "", err
 	}
+	s.recordLogin(ctx, createdUser.ID.Hex(), clientIP)
 
 	return createdUser, accessToken, refreshToken, nil
 }
 
+// completedRegistration returns the account a registration already created, when the retry carries the same
+// username and password. The account must still be allowed to sign in.
+func (s *authService) completedRegistration(ctx context.Context, userID, username, password string) (*model.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, apperror.ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	if user.Username != username || bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)) != nil {
+		return nil, apperror.ErrInvalidToken
+	}
+
+	if err := s.checkSignInAllowed(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
 // ResendOTP resends OTP for email verification
-func (s *authService) ResendOTP(email string) error {
-	ctx, cancel := util.NewDefaultDBContext()
+func (s *authService) ResendOTP(ctx context.Context, email string) error {
+	ctx, cancel := util.NewDBContextFrom(ctx)
 	defer cancel()
 
 	verification, err := s.emailVerificationRepo.GetByEmail(ctx, email)
@@ -260,17 +308,18 @@ func (s *authService) ResendOTP(email string) error {
 	}
 
 	// Send email
-	go func() {
-		if err := s.emailSender.SendVerificationEmail(email, otp); err != nil {
-			fmt.Printf("CRITICAL: Failed to resend verification email to %s: %v\n", email, err)
-		}
-	}()
+	err = s.emailQueue.SendVerificationEmail(email, otp)
+	otpEmails.WithLabel("resend", metricResult(err)).Inc()
+	if err != nil {
+		slog.Error("Failed to queue verification email", "to", email, "error", err)
+		return err
+	}
 
 	return nil
 }
 
-func (s *authService) Login(identifier, password string) (*model.User, string, string, error) {
-	ctx, cancel := util.NewDefaultDBContext()
+func (s *authService) Login(ctx context.Context, identifier, password, clientIP string) (*model.User, string, string, error) {
+	ctx, cancel := util.NewDBContextFrom(ctx)
 	defer cancel()
 	var user *model.User
 	var err error
@@ -300,35 +349,25 @@ func (s *authService) Login(identifier, password string) (*model.User, string, s
 		return nil, "", "", apperror.ErrEmailNotVerified
 	}
 
-	// Check if user is banned
-	if !user.IsActive {
-		// Check if ban has expired
-		if user.BanUntil != nil && time.Now().After(*user.BanUntil) {
-			// Ban expired, unban user
-			user.IsActive = true
-			user.BanUntil = nil
-			user.BanReason = nil
-			s.userRepo.Update(ctx, user)
-		} else {
-			// Still banned
-			return nil, "", "", apperror.ErrUserInactive
-		}
+	if err := s.checkSignInAllowed(ctx, user); err != nil {
+		return nil, "", "", err
 	}
 
 	accessToken, refreshToken, err := auth.GenerateToken(user.ID.Hex(), string(user.Role))
 	if err != nil {
 		return nil, "", "", err
 	}
+	s.recordLogin(ctx, user.ID.Hex(), clientIP)
 	return user, accessToken, refreshToken, nil
 }
 
-func (s *authService) RefreshToken(refreshToken string) (string, string, error) {
+func (s *authService) RefreshToken(ctx context.Context, refreshToken, clientIP string) (string, string, error) {
 	userID, err := auth.ParseRefreshToken(refreshToken)
 	if err != nil {
 		return "", "", err
 	}
 
-	ctx, cancel := util.NewDefaultDBContext()
+	ctx, cancel := util.NewDBContextFrom(ctx)
 	defer cancel()
 	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
@@ -357,16 +396,17 @@ func (s *authService) RefreshToken(refreshToken string) (string, string, error)
 	if err != nil {
 		return "", "", err
 	}
+	s.recordLogin(ctx, userID, clientIP)
 
 	return accessToken, newRefreshToken, nil
 }
 
-func (s *authService) Logout(accessToken, refreshToken string) error {
+func (s *authService) Logout(ctx context.Context, accessToken, refreshToken string) error {
 	if auth.TokenSvc == nil {
 		return apperror.ErrInternal
 	}
 
-	ctx, cancel := util.NewDefaultDBContext()
+	ctx, cancel := util.NewDBContextFrom(ctx)
 	defer cancel()
 
 	// Parse access token to get JTI
@@ -398,13 +438,13 @@ func (s *authService) Logout(accessToken, refreshToken string) error {
 
 // --- Google OAuth ---
 
-func (s *authService) ProcessGoogleCallback(code string) (*GoogleAuthResult, error) {
+func (s *authService) ProcessGoogleCallback(ctx context.Context, code, clientIP string) (*GoogleAuthResult, error) {
 	userInfo, err := auth.GetGoogleUserInfo(code)
 	if err != nil {
 		return nil, err
 	}
 
-	ctx, cancel := util.NewDefaultDBContext()
+	ctx, cancel := util.NewDBContextFrom(ctx)
 	defer cancel()
 
 	user, err := s.userRepo.GetByEmail(ctx, userInfo.Email)
@@ -420,29 +460,26 @@ func (s *authService) ProcessGoogleCallback(code string) (*GoogleAuthResult, err
 		return nil, err
 	}
 
-	if user.Provider != model.ProviderGoogle {
-		return nil, apperror.ErrLoginMethodMismatch
+	if !user.CanSignInWith(model.ProviderGoogle) {
+		if user.Provider != model.ProviderLocal {
+			return nil, apperror.ErrLoginMethodMismatch
+		}
+		linkToken, err := auth.CreateLinkToken(user.ID.Hex(), userInfo)
+		if err != nil {
+			return nil, err
+		}
+		return &GoogleAuthResult{Status: StatusLinkRequired, LinkToken: linkToken}, nil
 	}
 
-	// Check if user is banned
-	if !user.IsActive {
-		// Check if ban has expired
-		if user.BanUntil != nil && time.Now().After(*user.BanUntil) {
-			// Ban expired, unban user
-			user.IsActive = true
-			user.BanUntil = nil
-			user.BanReason = nil
-			s.userRepo.Update(ctx, user)
-		} else {
-			// Still banned
-			return nil, apperror.ErrUserInactive
-		}
+	if err := s.checkSignInAllowed(ctx, user); err != nil {
+		return nil, err
 	}
 
 	accessToken, refreshToken, err := auth.GenerateToken(user.ID.Hex(), string(user.Role))
 	if err != nil {
 		return nil, err
 	}
+	s.recordLogin(ctx, user.ID.Hex(), clientIP)
 
 	return &GoogleAuthResult{
 		Status:       StatusLoginSuccess,
@@ -452,23 +489,15 @@ func (s *authService) ProcessGoogleCallback(code string) (*GoogleAuthResult, err
 	}, nil
 }
 
-func (s *authService) CompleteGoogleSetup(setupToken, username string) (*model.User, string, string, error) {
+func (s *authService) CompleteGoogleSetup(ctx context.Context, setupToken, username, clientIP string) (*model.User, string, string, error) {
 	claims, err := auth.ParseSetupToken(setupToken)
 	if err != nil {
 		return nil, "", "", err
 	}
 
-	ctx, cancel := util.NewDefaultDBContext()
+	ctx, cancel := util.NewDBContextFrom(ctx)
 	defer cancel()
 
-	if _, err := s.userRepo.GetByUsername(ctx, username); !errors.Is(err, mongo.ErrNoDocuments) {
-		return nil, "", "", apperror.ErrUsernameExists
-	}
-
-	if _, err := s.userRepo.GetByEmail(ctx, claims.Email); !errors.Is(err, mongo.ErrNoDocuments) {
-		return nil, "", "", apperror.ErrEmailExists
-	}
-
 	newUser := &model.User{
 		Username:   username,
 		Email:      claims.Email,
@@ -483,22 +512,98 @@ func (s *authService) CompleteGoogleSetup(setupToken, username string) (*model.U
 		UpdatedAt:  time.Now(),
 	}
 
-	createdUser, err := s.userRepo.Create(ctx, newUser)
+	// Availability is checked in the same transaction as the insert
+	var createdUser *model.User
+	err = s.transactor.Run(ctx, func(ctx context.Context) error {
+		if err := s.checkAvailable(ctx, username, claims.Email); err != nil {
+			return err
+		}
+
+		var err error
+		createdUser, err = s.userRepo.Create(ctx, newUser)
+		return err
+	})
 	if err != nil {
 		return nil, "", "", err
 	}
 
-	// Invalidate username cache
-	s.invalidateUsernameCache(username)
+	// The username is taken now
+	s.eventBus.Publish(bus.UserUpdatedEvent{UserID: createdUser.ID.Hex(), Username: username})
 
 	accessToken, refreshToken, err := auth.GenerateToken(createdUser.ID.Hex(), string(createdUser.Role))
 	if err != nil {
 		return nil, "", "", err
 	}
+	s.recordLogin(ctx, createdUser.ID.Hex(), clientIP)
 
 	return createdUser, accessToken, refreshToken, nil
 }
 
+// CompleteAccountLink links the Google account in the link token to the local account with the same
+// email once the user confirms its password, then signs them in. Each link token allows a single
+// password attempt; after a wrong password the user has to start over from Google sign-in.
+func (s *authService) CompleteAccountLink(ctx context.Context, linkToken, password, clientIP string) (*model.User, string, string, error) {
+	claims, err := auth.ParseLinkToken(linkToken)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	redisCtx, redisCancel := util.NewDefaultRedisContext()
+	defer redisCancel()
+
+	key := fmt.Sprintf(config.RedisAccountLinkUsedKey, claims.ID)
+	fresh, err := s.redisClient.SetNX(redisCtx, key, claims.UserID, auth.LinkTokenTTL).Result()
+	if err != nil {
+		return nil, "", "", err
+	}
+	if !fresh {
+		return nil, "", "", apperror.ErrInvalidToken
+	}
+
+	ctx, cancel := util.NewDBContextFrom(ctx)
+	defer cancel()
+
+	user, err := s.userRepo.GetByID(ctx, claims.UserID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, "", "", apperror.ErrInvalidToken
+		}
+		return nil, "", "", err
+	}
+
+	// The account must still be the local one the token was issued for
+	if user.Provider != model.ProviderLocal || user.Email != claims.Email {
+		return nil, "", "", apperror.ErrInvalidToken
+	}
+
+	if user.Password == "" || bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)) != nil {
+		return nil, "", "", apperror.ErrInvalidCredentials
+	}
+
+	if !user.IsVerified {
+		return nil, "", "", apperror.ErrEmailNotVerified
+	}
+
+	if err := s.checkSignInAllowed(ctx, user); err != nil {
+		return nil, "", "", err
+	}
+
+	if !user.CanSignInWith(model.ProviderGoogle) {
+		if err := s.userRepo.LinkProvider(ctx, claims.UserID, model.ProviderGoogle, claims.GoogleID); err != nil {
+			return nil, "", "", err
+		}
+		user.ProviderID = claims.GoogleID
+		user.LinkedProviders = append(user.LinkedProviders, model.ProviderGoogle)
+	}
+
+	accessToken, refreshToken, err := auth.GenerateToken(user.ID.Hex(), string(user.Role))
+	if err != nil {
+		return nil, "", "", err
+	}
+	s.recordLogin(ctx, user.ID.Hex(), clientIP)
+	return user, accessToken, refreshToken, nil
+}
+
 // --- Helpers ---
 
 func isEmail(s string) bool {
@@ -534,16 +639,58 @@ func extractJTI(tokenStr string) (string, error) {
 	return "", fmt.Errorf("jti not found in token")
 }
 
-// invalidateUsernameCache removes the cached username availability check
-func (s *authService) invalidateUsernameCache(username string) {
-	if s.redisClient == nil {
-		return
+// checkSignInAllowed rejects banned users on login. An expired ban is lifted, and a self-deactivated
+// account is reactivated since logging in again is how the user comes back.
+func (s *authService) checkSignInAllowed(ctx context.Context, user *model.User) error {
+	// Check if user is banned
+	if !user.IsActive && !user.IsSelfDeactivated() {
+		// Check if ban has expired
+		if user.BanUntil != nil && time.Now().After(*user.BanUntil) {
+			// Ban expired, unban user
+			user.IsActive = true
+			user.BanUntil = nil
+			user.BanReason = nil
+			s.userRepo.Update(ctx, user)
+		} else {
+			// Still banned
+			return apperror.ErrUserInactive
+		}
+	}
+
+	// Also covers an account banned and unbanned while deactivated. Tokens issued before the deactivation stay
+	// rejected, the login issues fresh ones.
+	if user.DeactivatedAt != nil {
+		if err := s.userRepo.Reactivate(ctx, user.ID.Hex()); err != nil {
+			return err
+		}
+		user.IsActive = true
+		user.DeactivatedAt = nil
+	}
+
+	return nil
+}
+
+// checkAvailable fails early with a friendly error if the username or email is taken. It cannot stop two
+// concurrent sign-ups from both passing: the unique user indexes are the real guard, and UserRepo.Create
+// maps their violation to the same errors. Lookup failures are returned as is, not reported as taken.
+func (s *authService) checkAvailable(ctx context.Context, username, email string) error {
+	if _, err := s.userRepo.GetByUsername(ctx, username); err == nil {
+		return apperror.ErrUsernameExists
+	} else if !errors.Is(err, mongo.ErrNoDocuments) {
+		return err
 	}
 
-	ctx, cancel := util.NewDefaultRedisContext()
-	defer cancel()
+	if _, err := s.userRepo.GetByEmail(ctx, email); err == nil {
+		return apperror.ErrEmailExists
+	} else if !errors.Is(err, mongo.ErrNoDocuments) {
+		return err
+	}
+	return nil
+}
 
-	cacheKey := fmt.Sprintf("username_exists:%s", username)
-	// Ignore error, cache invalidation is not critical
-	_ = s.redisClient.Del(ctx, cacheKey).Err()
+// recordLogin stores the login time and IP used for active-user statistics. Failures are logged, not returned.
+func (s *authService) recordLogin(ctx context.Context, userID, clientIP string) {
+	if err := s.userRepo.UpdateLastLogin(ctx, userID, time.Now(), clientIP); err != nil {
+		slog.Warn("Failed to record login", "user_id", userID, "error", err)
+	}
 }
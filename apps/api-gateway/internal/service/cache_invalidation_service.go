@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/bus"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/util"
+	"github.com/redis/go-redis/v9"
+)
+
+// CacheInvalidationService clears the Redis caches derived from user documents when user events are published
+type CacheInvalidationService interface {
+	Start(workers *Workers)
+}
+
+type cacheInvalidationService struct {
+	eventBus    bus.EventBus
+	redisClient *redis.Client
+	events      bus.EventListener
+}
+
+func NewCacheInvalidationService(eventBus bus.EventBus, redisClient *redis.Client) CacheInvalidationService {
+	return &cacheInvalidationService{
+		eventBus:    eventBus,
+		redisClient: redisClient,
+		events:      make(bus.EventListener, 256),
+	}
+}
+
+// cacheInvalidationTopics are the user events that make cached data stale
+var cacheInvalidationTopics = []string{bus.TopicUserUpdated, bus.TopicUserDeleted, bus.TopicSettingsChanged}
+
+func (s *cacheInvalidationService) Start(workers *Workers) {
+	for _, topic := range cacheInvalidationTopics {
+		s.eventBus.Subscribe(topic, s.events)
+	}
+
+	workers.Go(func(stop context.Context) {
+		for {
+			select {
+			case <-stop.Done():
+				return
+			case event := <-s.events:
+				s.invalidate(event)
+			}
+		}
+	})
+}
+
+// invalidate deletes the cache keys an event makes stale. With the Redis event bus every instance receives the
+// event and deletes the same shared keys, which is harmless.
+func (s *cacheInvalidationService) invalidate(event bus.Event) {
+	var keys []string
+	switch e := event.(type) {
+	case bus.UserUpdatedEvent:
+		// Creating, banning or restoring a user changes the admin list totals too
+		keys = append(keys, userCacheKey(e.UserID), usernameExistsKey(e.Username), config.RedisAdminUserCountsKey)
+		if e.PreviousUsername != "" && e.PreviousUsername != e.Username {
+			keys = append(keys, usernameExistsKey(e.PreviousUsername))
+		}
+	case bus.UserDeletedEvent:
+		keys = append(keys, userCacheKey(e.UserID), usernameExistsKey(e.Username), config.RedisAdminUserCountsKey)
+	case bus.SettingsChangedEvent:
+		keys = append(keys, userCacheKey(e.UserID))
+	default:
+		return
+	}
+
+	ctx, cancel := util.NewDefaultRedisContext()
+	defer cancel()
+
+	if err := s.redisClient.Del(ctx, keys...).Err(); err != nil {
+		slog.Warn("Cache invalidation: failed to delete keys", "topic", event.Topic(), "keys", keys, "error", err)
+	}
+}
+
+func userCacheKey(userID string) string {
+	return fmt.Sprintf(config.RedisUserCacheKey, userID)
+}
+
+func usernameExistsKey(username string) string {
+	return fmt.Sprintf(config.RedisUsernameExistsKey, username)
+}
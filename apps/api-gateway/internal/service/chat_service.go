@@ -2,29 +2,48 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"strings"
 	"time"
 
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/apperror"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/bus"
 	platformgrpc "github.com/giakiet05/uit-ai-assistant/backend/internal/platform/grpc"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/logger"
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/repo"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/util"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
+// streamChunkSize is the approximate size in bytes of a streamed answer chunk
+const streamChunkSize = 64
+
 // ChatService interface defines chat business logic operations
 type ChatService interface {
-	Chat(ctx context.Context, userID string, sessionID *string, message string) (*model.ChatMessage, error)
-	GetSessionsByUserID(ctx context.Context, userID string, opts *repo.FindOptions) ([]*model.ChatSession, error)
+	Chat(ctx context.Context, userID string, sessionID *string, message string, language *string, settings *model.UserSettings, student *model.StudentProfile) (*model.ChatMessage, error)
+	GetSessionsByUserID(ctx context.Context, userID string, page repo.Page) ([]*model.ChatSessionSummary, *repo.Cursor, error)
 	GetSessionByID(ctx context.Context, userID string, sessionID string) (*model.ChatSession, error)
-	GetMessagesBySessionID(ctx context.Context, userID string, sessionID string, limit int) ([]*model.ChatMessage, error)
+	GetMessagesBySessionID(ctx context.Context, userID string, sessionID string, page repo.Page) ([]*model.ChatMessage, *repo.Cursor, error)
 	DeleteSession(ctx context.Context, userID string, sessionID string) error
 	UpdateSessionTitle(ctx context.Context, userID string, sessionID string, title string) (*model.ChatSession, error)
+	UpdateSessionLanguage(ctx context.Context, userID string, sessionID string, language string) (*model.ChatSession, error)
 }
 
 type chatService struct {
 	sessionRepo repo.ChatSessionRepo
 	messageRepo repo.ChatMessageRepo
 	agentClient *platformgrpc.AgentClient
+	citations   *citationNormalizer
+	eventBus    bus.EventBus
+	quota       QuotaService
+	dashboard   DashboardService
+	moderation  ModerationService
 }
 
 // NewChatService creates a new chat service
@@ -32,23 +51,49 @@ func NewChatService(
 	sessionRepo repo.ChatSessionRepo,
 	messageRepo repo.ChatMessageRepo,
 	agentClient *platformgrpc.AgentClient,
+	eventBus bus.EventBus,
+	quota QuotaService,
+	dashboard DashboardService,
+	moderation ModerationService,
 ) ChatService {
 	return &chatService{
 		sessionRepo: sessionRepo,
 		messageRepo: messageRepo,
 		agentClient: agentClient,
+		citations:   newCitationNormalizer(&config.Cfg.Citation),
+		eventBus:    eventBus,
+		quota:       quota,
+		dashboard:   dashboard,
+		moderation:  moderation,
 	}
 }
 
+// publishSessionEvent notifies the user's other tabs and devices subscribed to the session
+func (s *chatService) publishSessionEvent(userID, sessionID string, eventType bus.ChatSessionEventType, data interface{}) {
+	s.eventBus.Publish(bus.ChatSessionEvent{
+		SessionID: sessionID,
+		UserID:    userID,
+		EventType: eventType,
+		Data:      data,
+	})
+}
+
 // Chat handles a chat request
 // It creates/loads session, loads history, calls agent, and saves messages
-func (s *chatService) Chat(ctx context.Context, userID string, sessionID *string, message string) (*model.ChatMessage, error) {
+// language optionally sets the session language override; settings supply the user's default language
+// student is the user's profile forwarded to the agent, nil if unknown
+func (s *chatService) Chat(ctx context.Context, userID string, sessionID *string, message string, language *string, settings *model.UserSettings, student *model.StudentProfile) (*model.ChatMessage, error) {
 	// Step 1: Convert userID string to ObjectID
 	userObjectID, err := primitive.ObjectIDFromHex(userID)
 	if err != nil {
 		return nil, fmt.Errorf("invalid user ID: %w", err)
 	}
 
+	// Refuse before touching the session once the daily quota is used up
+	if err := s.quota.CheckChatQuota(ctx, userID); err != nil {
+		return nil, err
+	}
+
 	// Step 2: Get or create session
 	var session *model.ChatSession
 
@@ -75,6 +120,9 @@ func (s *chatService) Chat(ctx context.Context, userID string, sessionID *string
 			UserID: userObjectID,
 			Title:  title,
 		}
+		if language != nil {
+			session.Language = *language
+		}
 
 		session, err = s.sessionRepo.Create(ctx, session)
 		if err != nil {
@@ -82,18 +130,40 @@ func (s *chatService) Chat(ctx context.Context, userID string, sessionID *string
 		}
 	}
 
+	// Override language on an existing session if requested
+	if language != nil && *language != session.Language {
+		session, err = s.sessionRepo.UpdateLanguageField(ctx, session.ID.Hex(), *language)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update session language: %w", err)
+		}
+	}
+
 	// Step 2: Construct thread_id for LangGraph checkpointer
 	// Format: "user_id:session_id" (e.g., "507f1f77bcf86cd799439011:507f191e810c19729de860ea")
 	threadID := fmt.Sprintf("%s:%s", userID, session.ID.Hex())
 
 	// Step 3: Call agent via gRPC (no history needed - checkpointer manages state)
 	startTime := time.Now()
-	agentResp, err := s.agentClient.Chat(ctx, message, userID, threadID)
+	chatDone := s.dashboard.ChatStarted()
+	agentResp, err := s.agentClient.Chat(ctx, message, userID, threadID, session.ResolveLanguage(settings), agentStudentProfile(student), agentChatOptions(settings))
+	chatDone(err)
+	chatMessages.WithLabel(metricResult(err)).Inc()
 	if err != nil {
 		return nil, fmt.Errorf("agent call failed: %w", err)
 	}
 	latency := time.Since(startTime)
 
+	content := agentResp.Content
+	metadata := s.buildMetadata(ctx, agentResp, latency, settings == nil || settings.IsCitationsEnabled())
+	if settings != nil {
+		// The agent is told about blocked topics, this catches answers that mention them anyway
+		if topic, blocked := s.moderation.MatchBlockedTopic(content, settings.BlockedTopics); blocked {
+			content = blockedTopicAnswer(session.ResolveLanguage(settings))
+			metadata["blocked_topic"] = topic
+			delete(metadata, "sources")
+		}
+	}
+
 	// Step 4: Save user message
 	userMsg := &model.ChatMessage{
 		SessionID: session.ID,
@@ -111,28 +181,117 @@ func (s *chatService) Chat(ctx context.Context, userID string, sessionID *string
 	assistantMsg := &model.ChatMessage{
 		SessionID: session.ID,
 		Role:      model.RoleAssistant,
-		Content:   agentResp.Content,
-		Metadata:  s.buildMetadata(agentResp, latency),
+		Content:   content,
+		Metadata:  metadata,
 	}
 
 	assistantMsg, err = s.messageRepo.Create(ctx, assistantMsg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to save assistant message: %w", err)
 	}
+	s.quota.RecordChatUsage(ctx, userID, agentResp.TokensUsed, settings)
+	if sources, ok := metadata["sources"].([]model.Source); ok && config.Cfg.Citation.LinkCheckEnabled {
+		go s.markDeadLinks(assistantMsg.ID, sources)
+	}
 
 	// Step 6: Update session timestamp
 	session.UpdatedAt = time.Now()
 	_, err = s.sessionRepo.Update(ctx, session)
 	if err != nil {
 		// Log error but don't fail the request
-		fmt.Printf("failed to update session timestamp: %v\n", err)
+		logger.FromContext(ctx).Warn("Failed to update session timestamp", "session_id", session.ID.Hex(), "error", err)
+	}
+
+	if settings == nil || settings.IsStreamingEnabled() {
+		s.streamAnswer(userID, session.ID.Hex(), assistantMsg)
 	}
+	s.publishSessionEvent(userID, session.ID.Hex(), bus.ChatSessionEventMessageCreated, map[string]interface{}{
+		"messages": dto.FromChatMessages([]*model.ChatMessage{userMsg, assistantMsg}),
+	})
 
 	return assistantMsg, nil
 }
 
-// buildMetadata converts agent response to MongoDB metadata
-func (s *chatService) buildMetadata(resp *platformgrpc.AgentResponse, latency time.Duration) map[string]any {
+// agentStudentProfile converts the user's profile for the agent request
+func agentStudentProfile(student *model.StudentProfile) platformgrpc.StudentProfile {
+	if student == nil {
+		return platformgrpc.StudentProfile{}
+	}
+	return platformgrpc.StudentProfile{
+		StudentID:      student.StudentID,
+		Faculty:        student.Faculty,
+		Program:        student.Program,
+		EnrollmentYear: student.EnrollmentYear,
+	}
+}
+
+// agentChatOptions converts the user's chat behavior settings for the agent request
+func agentChatOptions(settings *model.UserSettings) platformgrpc.ChatOptions {
+	if settings == nil {
+		return platformgrpc.ChatOptions{}
+	}
+	return platformgrpc.ChatOptions{
+		Model:         settings.DefaultModel,
+		OmitCitations: !settings.IsCitationsEnabled(),
+		BlockedTopics: settings.BlockedTopics,
+	}
+}
+
+// blockedTopicAnswer replaces an answer that mentions one of the user's blocked topics
+func blockedTopicAnswer(language string) string {
+	if language == model.LanguageEN {
+		return "Sorry, this answer was hidden because it mentions a topic you have blocked."
+	}
+	return "Xin lỗi, câu trả lời đã được ẩn vì có nhắc tới chủ đề bạn đã chặn."
+}
+
+// streamAnswer publishes the saved answer as token events ahead of message_created,
+// so clients that stream render it progressively instead of all at once
+func (s *chatService) streamAnswer(userID, sessionID string, msg *model.ChatMessage) {
+	var chunk strings.Builder
+	index := 0
+	flush := func() {
+		if chunk.Len() == 0 {
+			return
+		}
+		s.publishSessionEvent(userID, sessionID, bus.ChatSessionEventToken, map[string]interface{}{
+			"message_id": msg.ID.Hex(),
+			"index":      index,
+			"delta":      chunk.String(),
+		})
+		chunk.Reset()
+		index++
+	}
+
+	for _, word := range strings.SplitAfter(msg.Content, " ") {
+		chunk.WriteString(word)
+		if chunk.Len() >= streamChunkSize {
+			flush()
+		}
+	}
+	flush()
+}
+
+// markDeadLinks flags the message's sources whose links are dead. It runs after the reply is sent, so slow or
+// unreachable sites do not hold it up; clients see the flags once they reload the message.
+func (s *chatService) markDeadLinks(messageID primitive.ObjectID, sources []model.Source) {
+	// A link is probed with HEAD, then GET if the server does not support HEAD
+	checkCtx, cancelCheck := context.WithTimeout(context.Background(), 2*time.Duration(config.Cfg.Citation.LinkCheckTimeout)*time.Second)
+	dead := s.citations.DeadLinks(checkCtx, sources)
+	cancelCheck()
+	if len(dead) == 0 {
+		return
+	}
+
+	ctx, cancel := util.NewDefaultDBContext()
+	defer cancel()
+	if err := s.messageRepo.MarkDeadLinks(ctx, messageID, dead); err != nil {
+		slog.Warn("Failed to mark dead citation links", "message_id", messageID.Hex(), "error", err)
+	}
+}
+
+// buildMetadata converts agent response to MongoDB metadata, dropping sources if the user turned citations off
+func (s *chatService) buildMetadata(ctx context.Context, resp *platformgrpc.AgentResponse, latency time.Duration, includeSources bool) map[string]any {
 	metadata := make(map[string]any)
 
 	// Tool calls
@@ -148,18 +307,11 @@ func (s *chatService) buildMetadata(resp *platformgrpc.AgentResponse, latency ti
 		metadata["tool_calls"] = toolCalls
 	}
 
-	// Sources
-	if len(resp.Sources) > 0 {
-		sources := make([]map[string]any, len(resp.Sources))
-		for i, src := range resp.Sources {
-			sources[i] = map[string]any{
-				"title":   src.Title,
-				"content": src.Content,
-				"score":   src.Score,
-				"url":     src.URL,
-			}
+	// Sources (de-duplicated, truncated and link-checked)
+	if includeSources && len(resp.Sources) > 0 {
+		if sources := s.citations.Normalize(resp.Sources); len(sources) > 0 {
+			metadata["sources"] = sources
 		}
-		metadata["sources"] = sources
 	}
 
 	// Reasoning steps
@@ -183,13 +335,13 @@ func (s *chatService) buildMetadata(resp *platformgrpc.AgentResponse, latency ti
 	return metadata
 }
 
-// GetSessionsByUserID retrieves all sessions for a user
-func (s *chatService) GetSessionsByUserID(ctx context.Context, userID string, opts *repo.FindOptions) ([]*model.ChatSession, error) {
-	sessions, err := s.sessionRepo.GetByUserID(ctx, userID, opts)
+// GetSessionsByUserID retrieves a page of a user's sessions with their last message preview and message count
+func (s *chatService) GetSessionsByUserID(ctx context.Context, userID string, page repo.Page) ([]*model.ChatSessionSummary, *repo.Cursor, error) {
+	sessions, next, err := s.sessionRepo.GetSummariesByUserID(ctx, userID, page)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get sessions: %w", err)
+		return nil, nil, fmt.Errorf("failed to get sessions: %w", err)
 	}
-	return sessions, nil
+	return sessions, next, nil
 }
 
 // GetSessionByID retrieves a session by ID
@@ -212,30 +364,30 @@ func (s *chatService) GetSessionByID(ctx context.Context, userID string, session
 	return session, nil
 }
 
-// GetMessagesBySessionID retrieves messages for a session
-func (s *chatService) GetMessagesBySessionID(ctx context.Context, userID string, sessionID string, limit int) ([]*model.ChatMessage, error) {
+// GetMessagesBySessionID retrieves a page of a session's messages, in chronological order
+func (s *chatService) GetMessagesBySessionID(ctx context.Context, userID string, sessionID string, page repo.Page) ([]*model.ChatMessage, *repo.Cursor, error) {
 	userObjectID, err := primitive.ObjectIDFromHex(userID)
 	if err != nil {
-		return nil, fmt.Errorf("invalid user ID: %w", err)
+		return nil, nil, fmt.Errorf("invalid user ID: %w", err)
 	}
 
 	// Verify session ownership
 	session, err := s.sessionRepo.GetByID(ctx, sessionID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get session: %w", err)
+		return nil, nil, fmt.Errorf("failed to get session: %w", err)
 	}
 
 	if session.UserID != userObjectID {
-		return nil, fmt.Errorf("session does not belong to user")
+		return nil, nil, fmt.Errorf("session does not belong to user")
 	}
 
 	// Get messages
-	messages, err := s.messageRepo.GetBySessionID(ctx, sessionID, limit)
+	messages, next, err := s.messageRepo.GetPageBySessionID(ctx, sessionID, page)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get messages: %w", err)
+		return nil, nil, fmt.Errorf("failed to get messages: %w", err)
 	}
 
-	return messages, nil
+	return messages, next, nil
 }
 
 // DeleteSession soft deletes a session
@@ -261,6 +413,8 @@ func (s *chatService) DeleteSession(ctx context.Context, userID string, sessionI
 		return fmt.Errorf("failed to delete session: %w", err)
 	}
 
+	s.publishSessionEvent(userID, sessionID, bus.ChatSessionEventDeleted, nil)
+
 	return nil
 }
 
@@ -288,5 +442,37 @@ func (s *chatService) UpdateSessionTitle(ctx context.Context, userID string, ses
 		return nil, fmt.Errorf("failed to update session: %w", err)
 	}
 
+	s.publishSessionEvent(userID, sessionID, bus.ChatSessionEventTitleChanged, dto.FromChatSession(session))
+
+	return session, nil
+}
+
+// UpdateSessionLanguage sets or clears the session language override
+func (s *chatService) UpdateSessionLanguage(ctx context.Context, userID string, sessionID string, language string) (*model.ChatSession, error) {
+	userObjectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	// Verify ownership
+	session, err := s.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, apperror.ErrChatSessionNotFound
+		}
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	if session.UserID != userObjectID {
+		return nil, apperror.ErrForbidden
+	}
+
+	session, err = s.sessionRepo.UpdateLanguageField(ctx, sessionID, language)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update session language: %w", err)
+	}
+
+	s.publishSessionEvent(userID, sessionID, bus.ChatSessionEventLanguageChanged, dto.FromChatSession(session))
+
 	return session, nil
 }
@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+	platformgrpc "github.com/giakiet05/uit-ai-assistant/backend/internal/platform/grpc"
+)
+
+// trackingParams are query parameters stripped from citation URLs
+var trackingParams = []string{"utm_source", "utm_medium", "utm_campaign", "utm_term", "utm_content", "fbclid", "gclid"}
+
+// citationNormalizer cleans up raw agent sources before they are stored
+type citationNormalizer struct {
+	cfg        *config.CitationConfig
+	httpClient *http.Client
+}
+
+// linkCheckMaxRedirects is how many redirects a link check follows before the link counts as dead
+const linkCheckMaxRedirects = 3
+
+var errLinkCheckBlockedAddress = errors.New("link check: address is not public")
+
+func newCitationNormalizer(cfg *config.CitationConfig) *citationNormalizer {
+	// Source URLs come from the agent's output, so the checks must not reach internal hosts: every address is
+	// checked after DNS resolution, including those of redirects
+	dialer := &net.Dialer{
+		Timeout: time.Duration(cfg.LinkCheckTimeout) * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+				return errLinkCheckBlockedAddress
+			}
+			return nil
+		},
+	}
+
+	return &citationNormalizer{
+		cfg: cfg,
+		httpClient: &http.Client{
+			Timeout: time.Duration(cfg.LinkCheckTimeout) * time.Second,
+			Transport: &http.Transport{
+				Proxy:             nil,
+				DialContext:       dialer.DialContext,
+				DisableKeepAlives: true,
+			},
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= linkCheckMaxRedirects {
+					return http.ErrUseLastResponse
+				}
+				return nil
+			},
+		},
+	}
+}
+
+// isPublicIP reports whether ip is routable on the internet: not loopback, private, link-local (which
+// includes cloud metadata endpoints), multicast or unspecified
+func isPublicIP(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() && !ip.IsMulticast() && !ip.IsUnspecified()
+}
+
+// Normalize de-duplicates sources, normalizes URLs and truncates content. Sources are returned ordered by
+// score (highest first). Links are checked later with DeadLinks, off the request path.
+func (n *citationNormalizer) Normalize(raw []platformgrpc.Source) []model.Source {
+	byKey := make(map[string]int, len(raw))
+	sources := make([]model.Source, 0, len(raw))
+
+	for _, src := range raw {
+		source := model.Source{
+			Title:   strings.TrimSpace(src.Title),
+			URL:     normalizeURL(src.URL),
+			Snippet: truncateSnippet(src.Content, n.cfg.SnippetMaxLength),
+			Score:   src.Score,
+		}
+		if source.Title == "" && source.URL == "" && source.Snippet == "" {
+			continue
+		}
+
+		// Keep the highest scoring copy of each source
+		key := citationKey(source)
+		if i, ok := byKey[key]; ok {
+			if source.Score > sources[i].Score {
+				sources[i] = source
+			}
+			continue
+		}
+		byKey[key] = len(sources)
+		sources = append(sources, source)
+	}
+
+	sort.SliceStable(sources, func(i, j int) bool {
+		return sources[i].Score > sources[j].Score
+	})
+
+	return sources
+}
+
+// DeadLinks checks every source URL concurrently and returns the ones that do not respond successfully.
+// Links to non-public addresses count as dead.
+func (n *citationNormalizer) DeadLinks(ctx context.Context, sources []model.Source) []string {
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		dead []string
+	)
+	for _, source := range sources {
+		if source.URL == "" {
+			continue
+		}
+		wg.Add(1)
+		go func(link string) {
+			defer wg.Done()
+			if !n.isAlive(ctx, link) {
+				mu.Lock()
+				dead = append(dead, link)
+				mu.Unlock()
+			}
+		}(source.URL)
+	}
+	wg.Wait()
+	return dead
+}
+
+func (n *citationNormalizer) isAlive(ctx context.Context, link string) bool {
+	status, err := n.probe(ctx, http.MethodHead, link)
+	// Some servers do not support HEAD, retry with GET
+	if err == nil && (status == http.StatusMethodNotAllowed || status == http.StatusNotImplemented) {
+		status, err = n.probe(ctx, http.MethodGet, link)
+	}
+	if err != nil {
+		return false
+	}
+	// A redirect still pending after the limit is not followed further
+	return status < http.StatusMultipleChoices
+}
+
+func (n *citationNormalizer) probe(ctx context.Context, method, link string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, link, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+// normalizeURL returns a canonical http(s) URL, or an empty string if the URL is invalid
+func normalizeURL(raw string) string {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return ""
+	}
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return ""
+	}
+
+	// Lowercase host and drop default ports
+	host := strings.ToLower(u.Hostname())
+	if port := u.Port(); port != "" && !(u.Scheme == "http" && port == "80") && !(u.Scheme == "https" && port == "443") {
+		host = host + ":" + port
+	}
+	u.Host = host
+	u.Fragment = ""
+	u.User = nil
+
+	// Strip tracking parameters
+	query := u.Query()
+	for _, p := range trackingParams {
+		query.Del(p)
+	}
+	u.RawQuery = query.Encode()
+
+	if u.Path != "/" {
+		u.Path = strings.TrimSuffix(u.Path, "/")
+	}
+
+	return u.String()
+}
+
+// truncateSnippet collapses whitespace and cuts content at a word boundary
+func truncateSnippet(content string, maxLen int) string {
+	snippet := strings.Join(strings.Fields(content), " ")
+
+	runes := []rune(snippet)
+	if maxLen <= 0 || len(runes) <= maxLen {
+		return snippet
+	}
+
+	cut := string(runes[:maxLen])
+	if lastSpace := strings.LastIndex(cut, " "); lastSpace > 0 {
+		cut = cut[:lastSpace]
+	}
+	return cut + "..."
+}
+
+// citationKey identifies duplicate sources: by URL when present, otherwise by title and snippet
+func citationKey(s model.Source) string {
+	if s.URL != "" {
+		return "url:" + s.URL
+	}
+	return "text:" + strings.ToLower(s.Title) + "|" + strings.ToLower(s.Snippet)
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/apperror"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/repo"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/util"
+)
+
+// CookieService manages the UIT portal cookies the extension syncs for the agent's tools
+type CookieService interface {
+	Start(workers *Workers)
+	SyncCookie(userID string, req *dto.SyncCookieRequest) (*dto.SyncCookieResponse, error)
+	GetCookieStatus(userID string) (map[string]dto.CookieStatusResponse, error)
+	MissingSources(ctx context.Context, userID string) ([]string, error)
+	GetCookie(ctx context.Context, userID, source string) (string, error)
+	SyncedUserIDs(ctx context.Context, source string) ([]string, error)
+	ReportExpired(userID, source string)
+}
+
+// cookieExpiryBatchSize is the number of expired cookies handled per check
+const cookieExpiryBatchSize = 100
+
+// cookieSourceNames are the portal names shown to users, by cookie source
+var cookieSourceNames = map[string]string{
+	"daa":     "DAA (daa.uit.edu.vn)",
+	"courses": "Courses (courses.uit.edu.vn)",
+	"drl":     "DRL (drl.uit.edu.vn)",
+}
+
+type cookieService struct {
+	cookieStore         repo.CookieStore
+	notificationService NotificationService
+	cfg                 *config.CookieConfig
+}
+
+func NewCookieService(cookieStore repo.CookieStore, notificationService NotificationService, cfg *config.CookieConfig) CookieService {
+	return &cookieService{
+		cookieStore:         cookieStore,
+		notificationService: notificationService,
+		cfg:                 cfg,
+	}
+}
+
+// Start launches the loop telling users when a synced cookie expired without being synced again
+func (s *cookieService) Start(workers *Workers) {
+	workers.TickEvery(time.Duration(s.cfg.ExpiryCheckSeconds)*time.Second, func(context.Context) {
+		s.notifyExpiredCookies()
+	})
+
+	slog.Info("CookieService started with cookie expiry check")
+}
+
+// SyncCookie stores the cookie for its source's configured TTL; the extension syncs again before it expires
+func (s *cookieService) SyncCookie(userID string, req *dto.SyncCookieRequest) (*dto.SyncCookieResponse, error) {
+	if !slices.Contains(config.CookieSources, req.Source) {
+		return nil, apperror.ErrInvalidCookieSource
+	}
+
+	ctx, cancel := util.NewDefaultRedisContext()
+	defer cancel()
+
+	ttl := time.Duration(s.cfg.TTLMinutes[req.Source]) * time.Minute
+	if err := s.cookieStore.Save(ctx, userID, req.Source, req.Cookie, ttl); err != nil {
+		return nil, err
+	}
+
+	return &dto.SyncCookieResponse{Source: req.Source, ExpiresAt: time.Now().Add(ttl)}, nil
+}
+
+// GetCookieStatus reports every portal; a source that cannot be read is reported as not synced with its error
+func (s *cookieService) GetCookieStatus(userID string) (map[string]dto.CookieStatusResponse, error) {
+	ctx, cancel := util.NewDefaultRedisContext()
+	defer cancel()
+
+	now := time.Now()
+	refreshBefore := time.Duration(s.cfg.RefreshBeforeMinutes) * time.Minute
+
+	status := make(map[string]dto.CookieStatusResponse, len(config.CookieSources))
+	for _, source := range config.CookieSources {
+		ttl, ok, err := s.cookieStore.TTL(ctx, userID, source)
+		switch {
+		case err != nil:
+			status[source] = dto.CookieStatusResponse{Error: err.Error()}
+		case ok:
+			expiresAt := now.Add(ttl)
+			status[source] = dto.CookieStatusResponse{
+				Synced:             true,
+				ExpiresIn:          int(ttl.Seconds()),
+				ExpiresAt:          &expiresAt,
+				RefreshRecommended: ttl <= refreshBefore,
+			}
+		default:
+			status[source] = dto.CookieStatusResponse{}
+		}
+	}
+
+	return status, nil
+}
+
+// MissingSources returns the portals the user has no synced cookie for
+func (s *cookieService) MissingSources(ctx context.Context, userID string) ([]string, error) {
+	var missing []string
+	for _, source := range config.CookieSources {
+		_, ok, err := s.cookieStore.TTL(ctx, userID, source)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			missing = append(missing, source)
+		}
+	}
+	return missing, nil
+}
+
+// GetCookie returns the user's cookie of a portal, or ErrPortalCookieMissing if they have not synced it
+func (s *cookieService) GetCookie(ctx context.Context, userID, source string) (string, error) {
+	cookie, ok, err := s.cookieStore.Get(ctx, userID, source)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", apperror.ErrPortalCookieMissing
+	}
+	return cookie, nil
+}
+
+// SyncedUserIDs returns the users who currently have a synced cookie of the portal
+func (s *cookieService) SyncedUserIDs(ctx context.Context, source string) ([]string, error) {
+	return s.cookieStore.UserIDs(ctx, source)
+}
+
+// ReportExpired drops a cookie a portal rejected and tells the user to sync it again.
+// Only the first report of a cookie notifies, later ones find it already dropped.
+func (s *cookieService) ReportExpired(userID, source string) {
+	ctx, cancel := util.NewDefaultRedisContext()
+	defer cancel()
+
+	deleted, err := s.cookieStore.Delete(ctx, userID, source)
+	if err != nil {
+		slog.Warn("Cookie: failed to drop rejected cookie", "source", source, "user_id", userID, "error", err)
+		return
+	}
+	if deleted {
+		s.notifyExpired(userID, source)
+	}
+}
+
+// notifyExpiredCookies notifies the users whose cookies reached their TTL since the last check
+func (s *cookieService) notifyExpiredCookies() {
+	ctx, cancel := util.NewDefaultRedisContext()
+	defer cancel()
+
+	expired, err := s.cookieStore.ClaimExpired(ctx, time.Now(), cookieExpiryBatchSize)
+	if err != nil {
+		slog.Error("Cookie: failed to claim expired cookies", "error", err)
+	}
+
+	for _, cookie := range expired {
+		s.notifyExpired(cookie.UserID, cookie.Source)
+	}
+}
+
+// notifyExpired prompts the user to sync a portal cookie again. The notification reaches the extension over
+// WebSocket too, where its deep link asks for a re-sync of the source.
+func (s *cookieService) notifyExpired(userID, source string) {
+	message := fmt.Sprintf("Phiên đăng nhập %s đã hết hạn. Hãy đồng bộ lại bằng tiện ích mở rộng để trợ lý tiếp tục đọc được dữ liệu của bạn.", cookieSourceNames[source])
+
+	_, err := s.notificationService.CreateNotification(context.Background(), userID, model.NotificationTypeCookieExpired, message, "", &model.NotificationData{
+		EntityType: model.EntityTypeCookie,
+		EntityID:   source,
+		Action:     model.NotificationActionSync,
+	})
+	if err != nil {
+		slog.Error("Cookie: failed to notify user about their expired cookie", "user_id", userID, "source", source, "error", err)
+	}
+}
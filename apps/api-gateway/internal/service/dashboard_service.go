@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/bus"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/util"
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// inFlightMaxAge drops chat requests from the in-progress count if their instance died before finishing them
+const inFlightMaxAge = 10 * time.Minute
+
+// DashboardService collects real-time metrics for the admin dashboard and pushes them over the event bus.
+// Counters live in Redis so the numbers cover every API instance.
+type DashboardService interface {
+	Start(workers *Workers)
+	// ChatStarted marks a chat request as waiting on the agent. Call the returned func with the agent's error when it answers.
+	ChatStarted() func(err error)
+	Snapshot() (*dto.DashboardMetricsPayload, error)
+}
+
+type dashboardService struct {
+	redisClient *redis.Client
+	eventBus    bus.EventBus
+	cfg         *config.DashboardConfig
+}
+
+func NewDashboardService(redisClient *redis.Client, eventBus bus.EventBus, cfg *config.DashboardConfig) DashboardService {
+	return &dashboardService{
+		redisClient: redisClient,
+		eventBus:    eventBus,
+		cfg:         cfg,
+	}
+}
+
+// Start pushes a snapshot every interval. With several instances, only the one holding the publisher key pushes.
+func (s *dashboardService) Start(workers *Workers) {
+	interval := time.Duration(s.cfg.PushIntervalSeconds) * time.Second
+	if interval <= 0 {
+		slog.Info("Dashboard: live metrics disabled")
+		return
+	}
+
+	workers.TickEvery(interval, func(context.Context) {
+		s.push(interval)
+	})
+}
+
+func (s *dashboardService) push(interval time.Duration) {
+	ctx, cancel := util.NewDefaultRedisContext()
+	defer cancel()
+
+	// Expire slightly early so the holder can take it again on its next tick
+	acquired, err := s.redisClient.SetNX(ctx, config.RedisDashboardPublisherKey, 1, interval-interval/10).Result()
+	if err != nil {
+		slog.Warn("Dashboard: failed to acquire publisher key", "error", err)
+		return
+	}
+	if !acquired {
+		return
+	}
+
+	metrics, err := s.Snapshot()
+	if err != nil {
+		slog.Warn("Dashboard: failed to collect metrics", "error", err)
+		return
+	}
+
+	s.eventBus.Publish(bus.DashboardMetricsEvent{Metrics: *metrics})
+}
+
+func (s *dashboardService) ChatStarted() func(err error) {
+	startedAt := time.Now()
+	id := primitive.NewObjectID().Hex()
+
+	ctx, cancel := util.NewDefaultRedisContext()
+	defer cancel()
+
+	if err := s.redisClient.ZAdd(ctx, config.RedisDashboardInFlightKey, redis.Z{Score: float64(startedAt.UnixMilli()), Member: id}).Err(); err != nil {
+		slog.Warn("Dashboard: failed to record chat start", "error", err)
+	}
+
+	return func(chatErr error) {
+		ctx, cancel := util.NewDefaultRedisContext()
+		defer cancel()
+
+		now := time.Now()
+		key := dashboardChatKey(now)
+
+		pipe := s.redisClient.TxPipeline()
+		pipe.ZRem(ctx, config.RedisDashboardInFlightKey, id)
+		pipe.HIncrBy(ctx, key, "requests", 1)
+		if chatErr != nil {
+			pipe.HIncrBy(ctx, key, "errors", 1)
+		} else {
+			pipe.HIncrBy(ctx, key, "latency_ms", now.Sub(startedAt).Milliseconds())
+		}
+		pipe.Expire(ctx, key, time.Duration(s.cfg.WindowMinutes+1)*time.Minute)
+		if _, err := pipe.Exec(ctx); err != nil {
+			slog.Warn("Dashboard: failed to record chat result", "error", err)
+		}
+	}
+}
+
+func (s *dashboardService) Snapshot() (*dto.DashboardMetricsPayload, error) {
+	ctx, cancel := util.NewDefaultRedisContext()
+	defer cancel()
+
+	now := time.Now()
+	staleBefore := strconv.FormatInt(now.Add(-inFlightMaxAge).UnixMilli(), 10)
+
+	pipe := s.redisClient.Pipeline()
+	online := pipe.SCard(ctx, config.RedisOnlineUsersKey)
+	pipe.ZRemRangeByScore(ctx, config.RedisDashboardInFlightKey, "-inf", "("+staleBefore)
+	inFlight := pipe.ZCard(ctx, config.RedisDashboardInFlightKey)
+	buckets := make([]*redis.MapStringStringCmd, 0, s.cfg.WindowMinutes)
+	for i := 0; i < s.cfg.WindowMinutes; i++ {
+		buckets = append(buckets, pipe.HGetAll(ctx, dashboardChatKey(now.Add(-time.Duration(i)*time.Minute))))
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, err
+	}
+
+	metrics := &dto.DashboardMetricsPayload{
+		OnlineUsers:     online.Val(),
+		ChatsInProgress: inFlight.Val(),
+		WindowMinutes:   s.cfg.WindowMinutes,
+		GeneratedAt:     now,
+	}
+
+	var latencyMs int64
+	for _, bucket := range buckets {
+		values := bucket.Val()
+		requests, _ := strconv.ParseInt(values["requests"], 10, 64)
+		failed, _ := strconv.ParseInt(values["errors"], 10, 64)
+		latency, _ := strconv.ParseInt(values["latency_ms"], 10, 64)
+		metrics.ChatRequests += requests
+		metrics.ChatErrors += failed
+		latencyMs += latency
+	}
+
+	if metrics.ChatRequests > 0 {
+		metrics.ErrorRate = float64(metrics.ChatErrors) / float64(metrics.ChatRequests)
+	}
+	if succeeded := metrics.ChatRequests - metrics.ChatErrors; succeeded > 0 {
+		metrics.AvgAgentLatencyMs = latencyMs / succeeded
+	}
+
+	return metrics, nil
+}
+
+// dashboardChatKey is the Redis key of the minute containing t
+func dashboardChatKey(t time.Time) string {
+	return fmt.Sprintf(config.RedisDashboardChatKey, t.Unix()/60)
+}
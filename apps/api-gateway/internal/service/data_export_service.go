@@ -0,0 +1,399 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/apperror"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/repo"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/util"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+)
+
+const (
+	// dataExportBuildTimeout bounds building and uploading one archive
+	dataExportBuildTimeout = 10 * time.Minute
+	// dataExportDownloadTimeout bounds streaming one archive to the client
+	dataExportDownloadTimeout = 30 * time.Minute
+	// dataExportNotificationPage is how many notifications are read per query while exporting
+	dataExportNotificationPage = 200
+	// dataExportCleanupBatch is how many expired archives are deleted per worker tick
+	dataExportCleanupBatch = 50
+)
+
+// DataExportService builds a ZIP archive of everything stored about a user (takeout).
+// Archives are built in the background, announced by notification and downloaded through a signed, expiring link.
+type DataExportService interface {
+	Start(workers *Workers)
+	RequestExport(ctx context.Context, userID string) (*dto.DataExportResponse, error)
+	GetExport(ctx context.Context, userID, exportID string) (*dto.DataExportResponse, error)
+	// OpenDownload verifies a signed link and opens the archive. The caller must close the reader.
+	OpenDownload(exportID, expires, signature string) (io.ReadCloser, int64, string, error)
+}
+
+type dataExportService struct {
+	dataExportRepo      repo.DataExportRepo
+	userRepo            repo.UserRepo
+	sessionRepo         repo.ChatSessionRepo
+	messageRepo         repo.ChatMessageRepo
+	notificationRepo    repo.NotificationRepo
+	notificationService NotificationService
+	cfg                 *config.DataExportConfig
+	workers             *Workers // Set by Start, runs the builds requested between worker runs
+}
+
+func NewDataExportService(
+	dataExportRepo repo.DataExportRepo,
+	userRepo repo.UserRepo,
+	sessionRepo repo.ChatSessionRepo,
+	messageRepo repo.ChatMessageRepo,
+	notificationRepo repo.NotificationRepo,
+	notificationService NotificationService,
+	cfg *config.DataExportConfig,
+) DataExportService {
+	return &dataExportService{
+		dataExportRepo:      dataExportRepo,
+		userRepo:            userRepo,
+		sessionRepo:         sessionRepo,
+		messageRepo:         messageRepo,
+		notificationRepo:    notificationRepo,
+		notificationService: notificationService,
+		cfg:                 cfg,
+	}
+}
+
+// Start launches the worker that builds pending exports and deletes expired archives.
+// The worker also picks up exports left behind by an instance that stopped mid-build.
+func (s *dataExportService) Start(workers *Workers) {
+	s.workers = workers
+
+	interval := time.Duration(s.cfg.WorkerIntervalSeconds) * time.Second
+	if interval <= 0 {
+		slog.Info("Data export worker is disabled")
+		return
+	}
+
+	workers.TickEvery(interval, func(stop context.Context) {
+		s.processPending(stop)
+		s.deleteExpired()
+	})
+
+	slog.Info("DataExportService started with export worker")
+}
+
+func (s *dataExportService) RequestExport(ctx context.Context, userID string) (*dto.DataExportResponse, error) {
+	ctx, cancel := util.NewDBContextFrom(ctx)
+	defer cancel()
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, apperror.ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	latest, err := s.dataExportRepo.GetLatestByUserID(ctx, userID)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, err
+	}
+	if latest != nil {
+		if latest.Status == model.DataExportStatusPending || latest.Status == model.DataExportStatusProcessing {
+			return nil, apperror.ErrDataExportInProgress
+		}
+		// A failed export does not count towards the cooldown so the user can retry right away
+		cooldown := time.Duration(s.cfg.CooldownHours) * time.Hour
+		if latest.Status != model.DataExportStatusFailed && time.Since(latest.CreatedAt) < cooldown {
+			return nil, apperror.ErrDataExportTooSoon
+		}
+	}
+
+	export, err := s.dataExportRepo.Create(ctx, &model.DataExport{
+		UserID: user.ID,
+		Status: model.DataExportStatusPending,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Build right away instead of waiting for the next worker tick. Without workers (command-line tools)
+	// the export waits for a running API to build it.
+	if s.workers != nil {
+		s.workers.Go(s.processPending)
+	}
+
+	return dto.FromDataExport(export), nil
+}
+
+func (s *dataExportService) GetExport(ctx context.Context, userID, exportID string) (*dto.DataExportResponse, error) {
+	if _, err := primitive.ObjectIDFromHex(exportID); err != nil {
+		return nil, apperror.ErrInvalidID
+	}
+
+	ctx, cancel := util.NewDBContextFrom(ctx)
+	defer cancel()
+
+	export, err := s.dataExportRepo.GetByID(ctx, exportID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, apperror.ErrDataExportNotFound
+		}
+		return nil, err
+	}
+	// Other users' exports are reported as missing so their IDs cannot be probed
+	if export.UserID.Hex() != userID {
+		return nil, apperror.ErrDataExportNotFound
+	}
+
+	resp := dto.FromDataExport(export)
+	if export.Status == model.DataExportStatusReady && export.ExpiresAt != nil && time.Now().Before(*export.ExpiresAt) {
+		resp.DownloadURL = s.downloadURL(export)
+	}
+
+	return resp, nil
+}
+
+func (s *dataExportService) OpenDownload(exportID, expires, signature string) (io.ReadCloser, int64, string, error) {
+	expiresUnix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || !hmac.Equal([]byte(signature), []byte(signDownload(exportID, expiresUnix))) {
+		return nil, 0, "", apperror.ErrDownloadLinkInvalid
+	}
+	if time.Now().Unix() > expiresUnix {
+		return nil, 0, "", apperror.ErrDownloadLinkInvalid
+	}
+
+	ctx, cancel := util.NewDBContextWith(dataExportDownloadTimeout)
+
+	export, err := s.dataExportRepo.GetByID(ctx, exportID)
+	if err != nil {
+		cancel()
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, 0, "", apperror.ErrDataExportNotFound
+		}
+		return nil, 0, "", err
+	}
+	if export.Status != model.DataExportStatusReady || export.FileID == nil {
+		cancel()
+		return nil, 0, "", apperror.ErrDownloadLinkInvalid
+	}
+
+	reader, size, err := s.dataExportRepo.OpenArchive(ctx, *export.FileID)
+	if err != nil {
+		cancel()
+		if errors.Is(err, gridfs.ErrFileNotFound) {
+			return nil, 0, "", apperror.ErrDownloadLinkInvalid
+		}
+		return nil, 0, "", err
+	}
+
+	return &cancelOnClose{ReadCloser: reader, cancel: cancel}, size, archiveFilename(export), nil
+}
+
+// processPending builds claimed exports until none are left
+func (s *dataExportService) processPending(stop context.Context) {
+	staleBefore := time.Now().Add(-time.Duration(s.cfg.StaleAfterMinutes) * time.Minute)
+
+	// An export being built is finished before stopping, the next one waits for the next run
+	for stop.Err() == nil {
+		ctx, cancel := util.NewDefaultDBContext()
+		export, err := s.dataExportRepo.ClaimNext(ctx, staleBefore)
+		cancel()
+		if err != nil {
+			if !errors.Is(err, mongo.ErrNoDocuments) {
+				slog.Error("DataExport: failed to claim export", "error", err)
+			}
+			return
+		}
+
+		s.build(export)
+	}
+}
+
+func (s *dataExportService) build(export *model.DataExport) {
+	ctx, cancel := util.NewDBContextWith(dataExportBuildTimeout)
+	defer cancel()
+
+	userID := export.UserID.Hex()
+
+	fileID, size, err := s.dataExportRepo.UploadArchive(ctx, archiveFilename(export), func(w io.Writer) error {
+		return s.writeArchive(ctx, userID, w)
+	})
+	if err != nil {
+		slog.Error("DataExport: failed to build export", "export_id", export.ID.Hex(), "user_id", userID, "error", err)
+		// The build context may have timed out, so record the failure with a fresh one.
+		// The user sees the reason, so it stays generic; details are in the log.
+		failCtx, failCancel := util.NewDefaultDBContext()
+		defer failCancel()
+		if err := s.dataExportRepo.MarkFailed(failCtx, export.ID, "Không thể tạo bản sao dữ liệu"); err != nil {
+			slog.Error("DataExport: failed to mark export as failed", "export_id", export.ID.Hex(), "error", err)
+		}
+		return
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(time.Duration(s.cfg.RetentionHours) * time.Hour)
+	if err := s.dataExportRepo.MarkReady(ctx, export.ID, fileID, size, now, expiresAt); err != nil {
+		slog.Error("DataExport: failed to mark export as ready", "export_id", export.ID.Hex(), "error", err)
+		if err := s.dataExportRepo.DeleteArchive(ctx, fileID); err != nil {
+			slog.Error("DataExport: failed to delete orphaned archive", "file_id", fileID.Hex(), "error", err)
+		}
+		return
+	}
+
+	export.Status = model.DataExportStatusReady
+	export.ExpiresAt = &expiresAt
+
+	// Data export emails are transactional, the notification always queues one with the download link
+	message := fmt.Sprintf("Bản sao dữ liệu của bạn đã sẵn sàng. Liên kết tải xuống có hiệu lực đến %s.",
+		expiresAt.In(statsLocation()).Format("15:04 02/01/2006"))
+	_, err = s.notificationService.CreateNotification(ctx, userID, model.NotificationTypeDataExport, message, s.downloadURL(export), &model.NotificationData{
+		EntityType: model.EntityTypeDataExport,
+		EntityID:   export.ID.Hex(),
+		Action:     model.NotificationActionDownload,
+	})
+	if err != nil {
+		slog.Error("DataExport: failed to notify user", "user_id", userID, "error", err)
+	}
+}
+
+// writeArchive writes the ZIP archive of a user's profile, chat history and notifications
+func (s *dataExportService) writeArchive(ctx context.Context, userID string, w io.Writer) error {
+	zw := zip.NewWriter(w)
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("get user: %w", err)
+	}
+	if err := writeJSONFile(zw, "profile.json", dto.FromUser(user)); err != nil {
+		return err
+	}
+
+	sessions, err := s.sessionRepo.GetByUserID(ctx, userID, nil)
+	if err != nil {
+		return fmt.Errorf("get chat sessions: %w", err)
+	}
+	for _, session := range sessions {
+		messages, err := s.messageRepo.GetBySessionID(ctx, session.ID.Hex(), 0)
+		if err != nil {
+			return fmt.Errorf("get messages of session %s: %w", session.ID.Hex(), err)
+		}
+
+		exported := dto.ExportedChatSession{
+			ChatSessionResponse: *dto.FromChatSession(session),
+			Messages:            dto.FromChatMessages(messages),
+		}
+		if err := writeJSONFile(zw, "chat_sessions/"+session.ID.Hex()+".json", exported); err != nil {
+			return err
+		}
+	}
+
+	var notifications []dto.NotificationResponse
+	var cursor *repo.Cursor
+	for {
+		page, next, err := s.notificationRepo.GetByRecipientID(ctx, userID, cursor, dataExportNotificationPage)
+		if err != nil {
+			return fmt.Errorf("get notifications: %w", err)
+		}
+		notifications = append(notifications, dto.FromNotifications(page)...)
+		if next == nil {
+			break
+		}
+		cursor = next
+	}
+	if notifications == nil {
+		notifications = []dto.NotificationResponse{}
+	}
+	if err := writeJSONFile(zw, "notifications.json", notifications); err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+// deleteExpired removes archives whose download links have expired
+func (s *dataExportService) deleteExpired() {
+	ctx, cancel := util.NewDBContextWith(time.Minute)
+	defer cancel()
+
+	exports, err := s.dataExportRepo.GetExpiredReady(ctx, time.Now(), dataExportCleanupBatch)
+	if err != nil {
+		slog.Error("DataExport: failed to list expired exports", "error", err)
+		return
+	}
+
+	for _, export := range exports {
+		if export.FileID != nil {
+			if err := s.dataExportRepo.DeleteArchive(ctx, *export.FileID); err != nil {
+				slog.Error("DataExport: failed to delete archive", "export_id", export.ID.Hex(), "error", err)
+				continue
+			}
+		}
+		if err := s.dataExportRepo.MarkExpired(ctx, export.ID); err != nil {
+			slog.Error("DataExport: failed to mark export as expired", "export_id", export.ID.Hex(), "error", err)
+		}
+	}
+}
+
+// downloadURL returns the signed download link of a ready export, valid until the archive expires
+func (s *dataExportService) downloadURL(export *model.DataExport) string {
+	expires := export.ExpiresAt.Unix()
+
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	query.Set("signature", signDownload(export.ID.Hex(), expires))
+
+	return fmt.Sprintf("%s/data-exports/%s/download?%s", s.cfg.DownloadBaseURL, export.ID.Hex(), query.Encode())
+}
+
+// signDownload signs an export ID and expiry time with the server secret. The prefix keeps the signature from
+// being valid for any other signed link.
+func signDownload(exportID string, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(config.Cfg.JWTSecret))
+	mac.Write([]byte("export." + exportID + "." + strconv.FormatInt(expires, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func archiveFilename(export *model.DataExport) string {
+	return fmt.Sprintf("uit-ai-assistant-export-%s.zip", export.CreatedAt.In(statsLocation()).Format("20060102"))
+}
+
+func writeJSONFile(zw *zip.Writer, name string, v interface{}) error {
+	f, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(f)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	return nil
+}
+
+// cancelOnClose releases the download context once the archive has been streamed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	defer c.cancel()
+	return c.ReadCloser.Close()
+}
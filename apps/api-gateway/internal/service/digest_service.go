@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/repo"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/util"
+)
+
+// digestMaxItems is the number of notifications listed in a digest email
+const digestMaxItems = 5
+
+// DigestService periodically emails users a summary of their unread notifications.
+type DigestService interface {
+	Start(workers *Workers)
+}
+
+type digestService struct {
+	notificationRepo repo.NotificationRepo
+	userRepo         repo.UserRepo
+	emailQueue       EmailQueueService
+	cfg              *config.DigestConfig
+}
+
+func NewDigestService(
+	notificationRepo repo.NotificationRepo,
+	userRepo repo.UserRepo,
+	emailQueue EmailQueueService,
+	cfg *config.DigestConfig,
+) DigestService {
+	return &digestService{
+		notificationRepo: notificationRepo,
+		userRepo:         userRepo,
+		emailQueue:       emailQueue,
+		cfg:              cfg,
+	}
+}
+
+func (s *digestService) Start(workers *Workers) {
+	if !s.cfg.Enabled {
+		slog.Info("Notification digest is disabled")
+		return
+	}
+
+	slog.Info("DigestService started")
+
+	workers.TickEvery(time.Duration(s.cfg.IntervalMinutes)*time.Minute, s.sendDueDigests)
+}
+
+// sendDueDigests finds recipients with old unread notifications and emails those whose digest is due.
+// It stops between batches when stop is done, the rest are sent on the next run.
+func (s *digestService) sendDueDigests(stop context.Context) {
+	ctx, cancel := util.NewDefaultDBContext()
+	defer cancel()
+
+	now := time.Now()
+	olderThan := now.Add(-time.Duration(s.cfg.MinAgeHours) * time.Hour)
+
+	digests, err := s.notificationRepo.GetUnreadDigests(ctx, olderThan, digestMaxItems)
+	if err != nil {
+		slog.Error("Digest: failed to load unread notifications", "error", err)
+		return
+	}
+
+	sent := 0
+	for start := 0; start < len(digests) && stop.Err() == nil; start += s.cfg.BatchSize {
+		end := min(start+s.cfg.BatchSize, len(digests))
+		batch := digests[start:end]
+
+		ids := make([]string, len(batch))
+		for i, d := range batch {
+			ids[i] = d.RecipientID.Hex()
+		}
+
+		users, err := s.userRepo.GetByIDs(ctx, ids)
+		if err != nil {
+			slog.Error("Digest: failed to load recipients", "error", err)
+			continue
+		}
+
+		usersByID := make(map[string]*model.User, len(users))
+		for _, u := range users {
+			usersByID[u.ID.Hex()] = u
+		}
+
+		for _, d := range batch {
+			user, ok := usersByID[d.RecipientID.Hex()]
+			if !ok || user.DeletedAt != nil || user.Email == "" || !user.IsDigestDue(now, s.cfg.SendHour) {
+				continue
+			}
+
+			// The queue retries failed sends, a queued digest counts as sent
+			if err := s.emailQueue.SendDigestEmail(user.Email, d.UnreadCount, toDigestItems(d.Notifications), notificationsURL(), unsubscribeURL(user.ID.Hex(), model.EmailCategoryDigest, "")); err != nil {
+				slog.Error("Digest: failed to queue", "user_id", user.ID.Hex(), "error", err)
+				continue
+			}
+
+			if err := s.userRepo.UpdateLastDigestSentAt(ctx, user.ID.Hex(), now); err != nil {
+				slog.Error("Digest: failed to record send time", "user_id", user.ID.Hex(), "error", err)
+			}
+			sent++
+		}
+	}
+
+	if sent > 0 {
+		slog.Info("Digest: queued digest emails", "count", sent)
+	}
+}
+
+func toDigestItems(notifications []*model.Notification) []model.DigestItem {
+	items := make([]model.DigestItem, len(notifications))
+	for i, n := range notifications {
+		items[i] = model.DigestItem{
+			Message:   n.Message,
+			Link:      n.Link,
+			CreatedAt: n.CreatedAt,
+		}
+	}
+	return items
+}
+
+// notificationsURL is the frontend page listing the user's notifications
+func notificationsURL() string {
+	return config.Cfg.FrontendURL + "/#/notifications"
+}
@@ -0,0 +1,483 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"html/template"
+	"log/slog"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/apperror"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/repo"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/util"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// emailQueueBatchSize is the number of recipients loaded and queued per batch
+const emailQueueBatchSize = 500
+
+// EmailCampaignService lets admins email a segment of users.
+// Sending a campaign queues one delivery per recipient; a background sender renders the deliveries and hands
+// them to the email queue, which sends them with retries and reports the outcome of every delivery.
+type EmailCampaignService interface {
+	Start(workers *Workers)
+	CreateCampaign(ctx context.Context, adminID string, req *dto.CreateEmailCampaignRequest) (*dto.EmailCampaignResponse, error)
+	GetCampaigns(ctx context.Context, page, pageSize int) (*dto.PaginatedEmailCampaignsResponse, error)
+	GetCampaign(ctx context.Context, id string) (*dto.EmailCampaignResponse, error)
+	SendCampaign(ctx context.Context, id string) (*dto.EmailCampaignResponse, error)
+	GetDeliveries(ctx context.Context, id string, query *dto.GetEmailDeliveriesQuery) (*dto.PaginatedEmailDeliveriesResponse, error)
+}
+
+type emailCampaignService struct {
+	campaignRepo repo.EmailCampaignRepo
+	deliveryRepo repo.EmailDeliveryRepo
+	userRepo     repo.UserRepo
+	emailQueue   EmailQueueService
+	cfg          *config.EmailCampaignConfig
+}
+
+func NewEmailCampaignService(
+	campaignRepo repo.EmailCampaignRepo,
+	deliveryRepo repo.EmailDeliveryRepo,
+	userRepo repo.UserRepo,
+	emailQueue EmailQueueService,
+	cfg *config.EmailCampaignConfig,
+) EmailCampaignService {
+	s := &emailCampaignService{
+		campaignRepo: campaignRepo,
+		deliveryRepo: deliveryRepo,
+		userRepo:     userRepo,
+		emailQueue:   emailQueue,
+		cfg:          cfg,
+	}
+	emailQueue.OnCampaignEmailDone(s.campaignEmailDone)
+	return s
+}
+
+// Start launches the sender loop that queues the recipients of campaigns being sent and hands their deliveries to the email queue
+func (s *emailCampaignService) Start(workers *Workers) {
+	workers.TickEvery(time.Duration(s.cfg.IntervalSeconds)*time.Second, func(ctx context.Context) {
+		s.queueCampaigns(ctx)
+		s.sendQueuedEmails(ctx)
+	})
+
+	slog.Info("EmailCampaignService started")
+}
+
+func (s *emailCampaignService) CreateCampaign(ctx context.Context, adminID string, req *dto.CreateEmailCampaignRequest) (*dto.EmailCampaignResponse, error) {
+	ctx, cancel := util.NewDBContextFrom(ctx)
+	defer cancel()
+
+	adminObjID, err := primitive.ObjectIDFromHex(adminID)
+	if err != nil {
+		return nil, apperror.ErrInvalidID
+	}
+
+	if req.Audience == model.EmailAudienceRole && req.Role == "" {
+		return nil, apperror.ErrBadRequest
+	}
+
+	// Reject templates that would fail for every recipient
+	tmpl, err := parseCampaignBody(req.Body)
+	if err != nil {
+		return nil, apperror.ErrInvalidEmailTemplate
+	}
+	if _, err := renderCampaignBody(tmpl, model.EmailCampaignData{Username: "preview", Email: "preview@example.com"}); err != nil {
+		return nil, apperror.ErrInvalidEmailTemplate
+	}
+
+	campaign := &model.EmailCampaign{
+		Subject:   req.Subject,
+		Body:      req.Body,
+		Audience:  req.Audience,
+		Status:    model.EmailCampaignStatusDraft,
+		CreatedBy: adminObjID,
+	}
+	if req.Audience == model.EmailAudienceRole {
+		campaign.Role = req.Role
+	}
+
+	campaign, err = s.campaignRepo.Create(ctx, campaign)
+	if err != nil {
+		return nil, err
+	}
+
+	response := dto.FromEmailCampaign(campaign)
+	return &response, nil
+}
+
+func (s *emailCampaignService) GetCampaigns(ctx context.Context, page, pageSize int) (*dto.PaginatedEmailCampaignsResponse, error) {
+	ctx, cancel := util.NewDBContextFrom(ctx)
+	defer cancel()
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	campaigns, total, err := s.campaignRepo.Find(ctx, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.PaginatedEmailCampaignsResponse{
+		Campaigns: dto.FromEmailCampaigns(campaigns),
+		Pagination: dto.Pagination{
+			Page:     page,
+			PageSize: pageSize,
+			Total:    total,
+		},
+	}, nil
+}
+
+func (s *emailCampaignService) GetCampaign(ctx context.Context, id string) (*dto.EmailCampaignResponse, error) {
+	ctx, cancel := util.NewDBContextFrom(ctx)
+	defer cancel()
+
+	campaign, err := s.getCampaign(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := dto.FromEmailCampaign(campaign)
+	return &response, nil
+}
+
+// SendCampaign marks a draft campaign as sending; the sender loop queues its recipients on its next tick.
+// Poll GetCampaign for the recipient count and delivery progress.
+func (s *emailCampaignService) SendCampaign(ctx context.Context, id string) (*dto.EmailCampaignResponse, error) {
+	ctx, cancel := util.NewDBContextFrom(ctx)
+	defer cancel()
+
+	campaign, err := s.getCampaign(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if campaign.Status != model.EmailCampaignStatusDraft {
+		return nil, apperror.ErrEmailCampaignAlreadySent
+	}
+
+	// Only the request that moves the draft to sending sends it
+	now := time.Now()
+	if err := s.campaignRepo.MarkSending(ctx, campaign.ID, now); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, apperror.ErrEmailCampaignAlreadySent
+		}
+		return nil, err
+	}
+	campaign.Status = model.EmailCampaignStatusSending
+	campaign.UpdatedAt = now
+
+	response := dto.FromEmailCampaign(campaign)
+	return &response, nil
+}
+
+func (s *emailCampaignService) GetDeliveries(ctx context.Context, id string, query *dto.GetEmailDeliveriesQuery) (*dto.PaginatedEmailDeliveriesResponse, error) {
+	ctx, cancel := util.NewDBContextFrom(ctx)
+	defer cancel()
+
+	campaign, err := s.getCampaign(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	page := query.Page
+	if page < 1 {
+		page = 1
+	}
+	pageSize := query.PageSize
+	if pageSize < 1 {
+		pageSize = 20
+	}
+
+	deliveries, total, err := s.deliveryRepo.Find(ctx, campaign.ID, query.Status, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.PaginatedEmailDeliveriesResponse{
+		Deliveries: dto.FromEmailDeliveries(deliveries),
+		Pagination: dto.Pagination{
+			Page:     page,
+			PageSize: pageSize,
+			Total:    total,
+		},
+	}, nil
+}
+
+// enqueue queues one delivery per recipient in the campaign's audience, batch by batch in _id order.
+// Progress is saved after every batch; if queueing stops early, queueCampaigns continues after the last queued user.
+func (s *emailCampaignService) enqueue(stop context.Context, campaign model.EmailCampaign) {
+	filter := audienceFilter(&campaign)
+
+	var lastID primitive.ObjectID
+	if campaign.LastQueuedID != nil {
+		lastID = *campaign.LastQueuedID
+	}
+
+	queued := campaign.RecipientCount
+	for {
+		// Stopping between batches leaves the rest for the next claim
+		if stop.Err() != nil {
+			return
+		}
+
+		ctx, cancel := util.NewDefaultDBContext()
+		batchFilter := repo.Filter{"_id": bson.M{"$gt": lastID}}
+		for k, v := range filter {
+			batchFilter[k] = v
+		}
+		users, _, err := s.userRepo.Find(ctx, batchFilter, &repo.FindOptions{
+			Sort:  bson.D{{Key: "_id", Value: 1}},
+			Limit: emailQueueBatchSize,
+		})
+		if err == nil && len(users) > 0 {
+			deliveries := make([]*model.EmailDelivery, 0, len(users))
+			for _, u := range users {
+				// Users who unsubscribed from product emails are skipped
+				if u.Email == "" || !u.Settings.IsProductEmailsEnabled() {
+					continue
+				}
+				deliveries = append(deliveries, &model.EmailDelivery{
+					CampaignID: campaign.ID,
+					UserID:     u.ID,
+					Email:      u.Email,
+					Username:   u.Username,
+				})
+			}
+
+			var added int64
+			added, err = s.deliveryRepo.CreateMany(ctx, deliveries)
+			if err == nil {
+				lastID = users[len(users)-1].ID
+				err = s.campaignRepo.SaveQueueProgress(ctx, campaign.ID, lastID, added, time.Now())
+				queued += added
+			}
+		}
+		cancel()
+
+		if err != nil {
+			// Deliveries queued so far are still sent, the rest is queued when the campaign is resumed
+			slog.Error("Email campaign: queueing failed", "campaign_id", campaign.ID.Hex(), "queued", queued, "error", err)
+			return
+		}
+		if len(users) < emailQueueBatchSize {
+			break
+		}
+	}
+
+	slog.Info("Email campaign: queued recipients", "campaign_id", campaign.ID.Hex(), "queued", queued)
+
+	ctx, cancel := util.NewDefaultDBContext()
+	defer cancel()
+
+	if err := s.campaignRepo.SetQueued(ctx, campaign.ID, time.Now()); err != nil {
+		slog.Error("Email campaign: failed to mark as queued", "campaign_id", campaign.ID.Hex(), "error", err)
+		return
+	}
+
+	s.completeIfDone(ctx, campaign.ID)
+}
+
+// queueCampaigns queues the recipients of campaigns just sent, and of campaigns whose queueing stopped midway
+func (s *emailCampaignService) queueCampaigns(stop context.Context) {
+	for stop.Err() == nil {
+		ctx, cancel := util.NewDefaultDBContext()
+		campaign, err := s.campaignRepo.ClaimUnqueued(ctx, time.Now())
+		cancel()
+		if err != nil {
+			if !errors.Is(err, mongo.ErrNoDocuments) {
+				slog.Error("Email campaign: failed to claim campaign to queue", "error", err)
+			}
+			return
+		}
+
+		if campaign.LastQueuedID != nil {
+			slog.Info("Email campaign: resuming queueing", "campaign_id", campaign.ID.Hex(), "queued", campaign.RecipientCount)
+		}
+		s.enqueue(stop, *campaign)
+	}
+}
+
+// sendQueuedEmails hands up to BatchSize queued deliveries to the email queue. It stops early when stop is done.
+func (s *emailCampaignService) sendQueuedEmails(stop context.Context) {
+	ctx, cancel := util.NewDefaultDBContext()
+	defer cancel()
+
+	templates := make(map[primitive.ObjectID]*model.EmailCampaign)
+	parsed := make(map[primitive.ObjectID]*template.Template)
+	touched := make(map[primitive.ObjectID]bool)
+
+	for i := 0; i < s.cfg.BatchSize && stop.Err() == nil; i++ {
+		delivery, err := s.deliveryRepo.ClaimPending(ctx, time.Now())
+		if err != nil {
+			if !errors.Is(err, mongo.ErrNoDocuments) {
+				slog.Error("Email campaign: failed to claim delivery", "error", err)
+			}
+			break
+		}
+
+		campaign, ok := templates[delivery.CampaignID]
+		if !ok {
+			campaign, err = s.campaignRepo.GetByID(ctx, delivery.CampaignID.Hex())
+			if err != nil {
+				s.recordDelivery(ctx, delivery.ID, err)
+				touched[delivery.CampaignID] = true
+				continue
+			}
+			templates[delivery.CampaignID] = campaign
+			if tmpl, err := parseCampaignBody(campaign.Body); err == nil {
+				parsed[delivery.CampaignID] = tmpl
+			}
+		}
+
+		tmpl, ok := parsed[delivery.CampaignID]
+		if !ok {
+			s.recordDelivery(ctx, delivery.ID, apperror.ErrInvalidEmailTemplate)
+			touched[delivery.CampaignID] = true
+			continue
+		}
+
+		body, err := renderCampaignBody(tmpl, model.EmailCampaignData{Username: delivery.Username, Email: delivery.Email})
+		if err != nil {
+			s.recordDelivery(ctx, delivery.ID, err)
+			touched[delivery.CampaignID] = true
+			continue
+		}
+
+		// The delivery stays claimed and is queued again after the claim times out
+		if err := s.emailQueue.SendCampaignEmail(delivery.ID.Hex(), delivery.Email, campaign.Subject, body, unsubscribeURL(delivery.UserID.Hex(), model.EmailCategoryProduct, "")); err != nil {
+			slog.Error("Email campaign: failed to queue email", "delivery_id", delivery.ID.Hex(), "error", err)
+			break
+		}
+		if err := s.deliveryRepo.MarkQueued(ctx, delivery.ID); err != nil {
+			slog.Error("Email campaign: failed to mark delivery as queued", "delivery_id", delivery.ID.Hex(), "error", err)
+		}
+	}
+
+	for campaignID := range touched {
+		s.completeIfDone(ctx, campaignID)
+	}
+}
+
+// campaignEmailDone records the outcome of a campaign email reported by the email queue
+func (s *emailCampaignService) campaignEmailDone(deliveryID string, sendErr error) {
+	id, err := primitive.ObjectIDFromHex(deliveryID)
+	if err != nil {
+		slog.Error("Email campaign: email queue reported an invalid delivery", "delivery_id", deliveryID)
+		return
+	}
+
+	ctx, cancel := util.NewDefaultDBContext()
+	defer cancel()
+
+	if delivery := s.recordDelivery(ctx, id, sendErr); delivery != nil {
+		s.completeIfDone(ctx, delivery.CampaignID)
+	}
+}
+
+// recordDelivery stores the outcome of one delivery and updates the campaign counters. It returns the delivery
+// as it was before, or nil when the outcome was already recorded or could not be stored.
+func (s *emailCampaignService) recordDelivery(ctx context.Context, id primitive.ObjectID, sendErr error) *model.EmailDelivery {
+	var delivery *model.EmailDelivery
+	var sent, failed int64
+	var err error
+	if sendErr != nil {
+		failed = 1
+		delivery, err = s.deliveryRepo.MarkFailed(ctx, id, sendErr.Error())
+	} else {
+		sent = 1
+		delivery, err = s.deliveryRepo.MarkSent(ctx, id, time.Now())
+		// A dead-lettered email an admin queued again was counted as failed
+		if err == nil && delivery.Status == model.EmailDeliveryFailed {
+			failed = -1
+		}
+	}
+	if err != nil {
+		// The email queue delivers at least once, a repeated outcome is already counted
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			slog.Error("Email campaign: failed to record delivery", "delivery_id", id.Hex(), "error", err)
+		}
+		return nil
+	}
+
+	if err := s.campaignRepo.IncrementCounts(ctx, delivery.CampaignID, sent, failed); err != nil {
+		slog.Error("Email campaign: failed to update campaign counters", "campaign_id", delivery.CampaignID.Hex(), "error", err)
+	}
+	return delivery
+}
+
+// completeIfDone marks the campaign as sent once no delivery is left in the queue
+func (s *emailCampaignService) completeIfDone(ctx context.Context, campaignID primitive.ObjectID) {
+	remaining, err := s.deliveryRepo.CountUnfinished(ctx, campaignID)
+	if err != nil || remaining > 0 {
+		return
+	}
+
+	// Queueing may still be running, more deliveries can follow
+	campaign, err := s.campaignRepo.GetByID(ctx, campaignID.Hex())
+	if err != nil || campaign.QueuedAt == nil {
+		return
+	}
+
+	if err := s.campaignRepo.Complete(ctx, campaignID, time.Now()); err != nil {
+		slog.Error("Email campaign: failed to mark as sent", "campaign_id", campaignID.Hex(), "error", err)
+	}
+}
+
+func (s *emailCampaignService) getCampaign(ctx context.Context, id string) (*model.EmailCampaign, error) {
+	if !primitive.IsValidObjectID(id) {
+		return nil, apperror.ErrInvalidID
+	}
+
+	campaign, err := s.campaignRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, apperror.ErrEmailCampaignNotFound
+		}
+		return nil, err
+	}
+
+	return campaign, nil
+}
+
+// audienceFilter builds the user filter of a campaign audience. Banned and deleted users never receive campaigns.
+func audienceFilter(campaign *model.EmailCampaign) repo.Filter {
+	filter := repo.Filter{
+		"is_active":  true,
+		"deleted_at": bson.M{"$exists": false},
+	}
+
+	switch campaign.Audience {
+	case model.EmailAudienceActive:
+		filter["last_login"] = bson.M{"$gte": time.Now().AddDate(0, 0, -model.EmailAudienceActiveDays)}
+	case model.EmailAudienceRole:
+		filter["role"] = campaign.Role
+	}
+
+	return filter
+}
+
+func parseCampaignBody(body string) (*template.Template, error) {
+	return template.New("campaign").Option("missingkey=error").Parse(body)
+}
+
+func renderCampaignBody(tmpl *template.Template, data model.EmailCampaignData) (template.HTML, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return template.HTML(buf.String()), nil
+}
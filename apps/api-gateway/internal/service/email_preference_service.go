@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/apperror"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/repo"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/util"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// EmailPreferenceService applies the one-click unsubscribe links included in non-essential emails.
+// Links are signed with the server secret, so they work without logging in.
+type EmailPreferenceService interface {
+	Unsubscribe(ctx context.Context, userID string, category model.EmailCategory, notifType model.NotificationType, signature string) error
+}
+
+type emailPreferenceService struct {
+	userRepo repo.UserRepo
+}
+
+func NewEmailPreferenceService(userRepo repo.UserRepo) EmailPreferenceService {
+	return &emailPreferenceService{
+		userRepo: userRepo,
+	}
+}
+
+func (s *emailPreferenceService) Unsubscribe(ctx context.Context, userID string, category model.EmailCategory, notifType model.NotificationType, signature string) error {
+	if !hmac.Equal([]byte(signature), []byte(signUnsubscribe(userID, category, notifType))) {
+		return apperror.ErrUnsubscribeLinkInvalid
+	}
+
+	ctx, cancel := util.NewDBContextFrom(ctx)
+	defer cancel()
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return apperror.ErrUserNotFound
+		}
+		return err
+	}
+
+	switch category {
+	case model.EmailCategoryProduct:
+		disabled := false
+		user.Settings.ProductEmails = &disabled
+	case model.EmailCategoryDigest:
+		user.Settings.DigestFrequency = model.DigestOff
+	case model.EmailCategoryNotification:
+		if !model.IsConfigurableNotificationType(notifType) || model.IsTransactionalNotificationType(notifType) {
+			return apperror.ErrUnsubscribeLinkInvalid
+		}
+		pref := user.Settings.NotificationPreference(notifType)
+		pref.Email = false
+		if user.Settings.NotificationPreferences == nil {
+			user.Settings.NotificationPreferences = make(map[model.NotificationType]model.NotificationPreference)
+		}
+		user.Settings.NotificationPreferences[notifType] = pref
+	default:
+		return apperror.ErrUnsubscribeLinkInvalid
+	}
+
+	user.UpdatedAt = time.Now()
+	_, err = s.userRepo.Update(ctx, user)
+	return err
+}
+
+// unsubscribeURL returns the signed one-click unsubscribe link of a non-essential email.
+// notifType is only set for the notification category.
+func unsubscribeURL(userID string, category model.EmailCategory, notifType model.NotificationType) string {
+	query := url.Values{}
+	query.Set("user", userID)
+	query.Set("category", string(category))
+	if notifType != "" {
+		query.Set("type", string(notifType))
+	}
+	query.Set("signature", signUnsubscribe(userID, category, notifType))
+
+	return fmt.Sprintf("%s/email/unsubscribe?%s", config.Cfg.APIBaseURL, query.Encode())
+}
+
+// notificationUnsubscribeURL returns the unsubscribe link of a notification email, or empty if the type is transactional
+func notificationUnsubscribeURL(userID string, notifType model.NotificationType) string {
+	if model.IsTransactionalNotificationType(notifType) {
+		return ""
+	}
+	return unsubscribeURL(userID, model.EmailCategoryNotification, notifType)
+}
+
+// signUnsubscribe signs an unsubscribe request with the server secret.
+// The links do not expire so that unsubscribing from an old email still works.
+func signUnsubscribe(userID string, category model.EmailCategory, notifType model.NotificationType) string {
+	mac := hmac.New(sha256.New, []byte(config.Cfg.JWTSecret))
+	mac.Write([]byte("unsubscribe." + userID + "." + string(category) + "." + string(notifType)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
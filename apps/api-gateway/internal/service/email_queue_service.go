@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"html/template"
+	"log/slog"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/apperror"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/email"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/repo"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/util"
+	"github.com/google/uuid"
+)
+
+// EmailQueueService sends transactional emails in the background. Sends that fail are retried with
+// exponential backoff; after the last attempt the email is kept in a dead-letter list where admins
+// can inspect it and queue it again.
+type EmailQueueService interface {
+	Start(workers *Workers)
+	SendVerificationEmail(to, otp string) error
+	SendNotificationEmail(to, subject, message, link, unsubscribeURL string) error
+	SendDigestEmail(to string, unreadCount int64, items []model.DigestItem, link, unsubscribeURL string) error
+	SendCampaignEmail(deliveryID, to, subject string, body template.HTML, unsubscribeURL string) error
+	OnCampaignEmailDone(fn CampaignEmailDoneFunc)
+	GetFailedEmails(page, pageSize int) (*dto.PaginatedFailedEmailsResponse, error)
+	RequeueFailedEmail(id string) error
+}
+
+// CampaignEmailDoneFunc is told the outcome of a queued campaign email: nil once it was sent, the last error
+// once it was dead-lettered. A dead-lettered email that is queued again and sent is reported a second time.
+type CampaignEmailDoneFunc func(deliveryID string, sendErr error)
+
+type emailQueueService struct {
+	queue        repo.EmailQueue
+	emailSender  email.Sender
+	cfg          *config.EmailQueueConfig
+	campaignDone CampaignEmailDoneFunc
+}
+
+func NewEmailQueueService(queue repo.EmailQueue, emailSender email.Sender, cfg *config.EmailQueueConfig) EmailQueueService {
+	return &emailQueueService{
+		queue:       queue,
+		emailSender: emailSender,
+		cfg:         cfg,
+	}
+}
+
+func (s *emailQueueService) Start(workers *Workers) {
+	interval := time.Duration(s.cfg.WorkerIntervalSeconds) * time.Second
+	if interval <= 0 {
+		slog.Warn("Email queue: worker disabled, queued emails are not sent")
+		return
+	}
+
+	// A claimed batch is always sent to the end, stopping midway would leave its leases to expire
+	workers.TickEvery(interval, func(context.Context) { s.sendDue() })
+
+	slog.Info("Email queue worker started", "interval", interval)
+}
+
+// SendVerificationEmail queues an email with the OTP
+func (s *emailQueueService) SendVerificationEmail(to, otp string) error {
+	return s.enqueue(&model.EmailJob{Kind: model.EmailJobVerification, To: to, OTP: otp})
+}
+
+// SendNotificationEmail queues a notification email
+func (s *emailQueueService) SendNotificationEmail(to, subject, message, link, unsubscribeURL string) error {
+	return s.enqueue(&model.EmailJob{
+		Kind:           model.EmailJobNotification,
+		To:             to,
+		Subject:        subject,
+		Message:        message,
+		Link:           link,
+		UnsubscribeURL: unsubscribeURL,
+	})
+}
+
+// SendDigestEmail queues a digest of unread notifications
+func (s *emailQueueService) SendDigestEmail(to string, unreadCount int64, items []model.DigestItem, link, unsubscribeURL string) error {
+	return s.enqueue(&model.EmailJob{
+		Kind:           model.EmailJobDigest,
+		To:             to,
+		Link:           link,
+		UnsubscribeURL: unsubscribeURL,
+		UnreadCount:    unreadCount,
+		DigestItems:    items,
+	})
+}
+
+// SendCampaignEmail queues a campaign email, body is already rendered for the recipient
+func (s *emailQueueService) SendCampaignEmail(deliveryID, to, subject string, body template.HTML, unsubscribeURL string) error {
+	return s.enqueue(&model.EmailJob{
+		Kind:           model.EmailJobCampaign,
+		To:             to,
+		Subject:        subject,
+		HTML:           string(body),
+		UnsubscribeURL: unsubscribeURL,
+		DeliveryID:     deliveryID,
+	})
+}
+
+// OnCampaignEmailDone sets the function told the outcome of campaign emails. It must be set before Start.
+func (s *emailQueueService) OnCampaignEmailDone(fn CampaignEmailDoneFunc) {
+	s.campaignDone = fn
+}
+
+func (s *emailQueueService) GetFailedEmails(page, pageSize int) (*dto.PaginatedFailedEmailsResponse, error) {
+	ctx, cancel := util.NewDefaultRedisContext()
+	defer cancel()
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	jobs, total, err := s.queue.ListDeadLetters(ctx, int64((page-1)*pageSize), int64(pageSize))
+	if err != nil {
+		return nil, err
+	}
+
+	emails := make([]dto.FailedEmailResponse, len(jobs))
+	for i, job := range jobs {
+		emails[i] = dto.FromEmailJob(job)
+	}
+
+	return &dto.PaginatedFailedEmailsResponse{
+		Emails: emails,
+		Pagination: dto.Pagination{
+			Page:     page,
+			PageSize: pageSize,
+			Total:    total,
+		},
+	}, nil
+}
+
+func (s *emailQueueService) RequeueFailedEmail(id string) error {
+	ctx, cancel := util.NewDefaultRedisContext()
+	defer cancel()
+
+	if err := s.queue.RequeueDeadLetter(ctx, id, time.Now()); err != nil {
+		if errors.Is(err, repo.ErrEmailJobNotFound) {
+			return apperror.ErrFailedEmailNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *emailQueueService) enqueue(job *model.EmailJob) error {
+	ctx, cancel := util.NewDefaultRedisContext()
+	defer cancel()
+
+	job.ID = uuid.New().String()
+	job.CreatedAt = time.Now()
+	return s.queue.Enqueue(ctx, job, job.CreatedAt)
+}
+
+// sendDue sends up to a batch of due emails, rescheduling or dead-lettering those that fail
+func (s *emailQueueService) sendDue() {
+	ctx, cancel := util.NewDefaultRedisContext()
+	defer cancel()
+
+	now := time.Now()
+	lease := time.Duration(s.cfg.LeaseSeconds) * time.Second
+	jobs, err := s.queue.ClaimDue(ctx, now, now.Add(lease), int64(s.cfg.BatchSize))
+	if err != nil {
+		slog.Error("Email queue: failed to claim due emails", "error", err)
+		return
+	}
+
+	for _, job := range jobs {
+		s.send(job)
+	}
+}
+
+func (s *emailQueueService) send(job *model.EmailJob) {
+	// Sending can take a while, each job gets its own Redis context for the bookkeeping after it
+	sendErr := s.deliver(job)
+
+	ctx, cancel := util.NewDefaultRedisContext()
+	defer cancel()
+
+	if sendErr == nil {
+		emailSends.WithLabel(string(job.Kind), "ok").Inc()
+		if err := s.queue.Complete(ctx, job.ID); err != nil {
+			slog.Error("Email queue: failed to remove sent email, it may be sent again", "id", job.ID, "error", err)
+		}
+		s.done(job, nil)
+		return
+	}
+
+	job.Attempts++
+	job.LastError = sendErr.Error()
+
+	if job.Attempts >= s.cfg.MaxAttempts {
+		emailSends.WithLabel(string(job.Kind), "dead").Inc()
+		failedAt := time.Now()
+		job.FailedAt = &failedAt
+		slog.Error("Email queue: giving up on email", "id", job.ID, "kind", job.Kind, "to", job.To, "attempts", job.Attempts, "error", sendErr)
+		if err := s.queue.DeadLetter(ctx, job, int64(s.cfg.DeadLetterMax)); err != nil {
+			slog.Error("Email queue: failed to dead-letter email", "id", job.ID, "error", err)
+		}
+		s.done(job, sendErr)
+		return
+	}
+
+	emailSends.WithLabel(string(job.Kind), "retry").Inc()
+	backoff := s.backoff(job.Attempts)
+	slog.Warn("Email queue: send failed, retrying", "id", job.ID, "kind", job.Kind, "to", job.To, "attempt", job.Attempts, "retry_in", backoff, "error", sendErr)
+	if err := s.queue.Retry(ctx, job, time.Now().Add(backoff)); err != nil {
+		slog.Error("Email queue: failed to reschedule email, it is retried after its lease", "id", job.ID, "error", err)
+	}
+}
+
+func (s *emailQueueService) deliver(job *model.EmailJob) error {
+	switch job.Kind {
+	case model.EmailJobVerification:
+		return s.emailSender.SendVerificationEmail(job.To, job.OTP)
+	case model.EmailJobNotification:
+		return s.emailSender.SendNotificationEmail(job.To, job.Subject, job.Message, job.Link, job.UnsubscribeURL)
+	case model.EmailJobDigest:
+		items := make([]email.DigestItem, len(job.DigestItems))
+		for i, item := range job.DigestItems {
+			items[i] = email.DigestItem{Message: item.Message, Link: item.Link, CreatedAt: item.CreatedAt}
+		}
+		return s.emailSender.SendDigestEmail(job.To, job.UnreadCount, items, job.Link, job.UnsubscribeURL)
+	case model.EmailJobCampaign:
+		return s.emailSender.SendCampaignEmail(job.To, job.Subject, template.HTML(job.HTML), job.UnsubscribeURL)
+	default:
+		return errors.New("unknown email kind " + string(job.Kind))
+	}
+}
+
+// done reports the final outcome of a campaign email to the campaign sender
+func (s *emailQueueService) done(job *model.EmailJob, sendErr error) {
+	if job.Kind == model.EmailJobCampaign && s.campaignDone != nil {
+		s.campaignDone(job.DeliveryID, sendErr)
+	}
+}
+
+// backoff is the wait after the given number of failed attempts: the base doubled after each one, capped
+func (s *emailQueueService) backoff(attempts int) time.Duration {
+	maxBackoff := time.Duration(s.cfg.MaxBackoffMinutes) * time.Minute
+	backoff := time.Duration(s.cfg.BaseBackoffSeconds) * time.Second
+	for i := 1; i < attempts && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, maxBackoff)
+}
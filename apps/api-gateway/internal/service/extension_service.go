@@ -0,0 +1,127 @@
+package service
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/apperror"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/auth"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/util"
+	"github.com/redis/go-redis/v9"
+)
+
+// ExtensionService negotiates with the Chrome extension which versions are supported and what it should sync
+type ExtensionService interface {
+	IssueToken(user auth.AuthUser) (*dto.ExtensionTokenResponse, error)
+	Heartbeat(userID string, req *dto.ExtensionHeartbeatRequest) (*dto.ExtensionHeartbeatResponse, error)
+}
+
+type extensionService struct {
+	cookieService CookieService
+	redisClient   *redis.Client
+	cfg           *config.ExtensionConfig
+}
+
+func NewExtensionService(cookieService CookieService, redisClient *redis.Client, cfg *config.ExtensionConfig) ExtensionService {
+	return &extensionService{
+		cookieService: cookieService,
+		redisClient:   redisClient,
+		cfg:           cfg,
+	}
+}
+
+// IssueToken exchanges a full access token for a scoped extension token, or renews an extension token. A renewed
+// token keeps the session start of the one it replaces and never outlives the session's maximum age.
+func (s *extensionService) IssueToken(user auth.AuthUser) (*dto.ExtensionTokenResponse, error) {
+	now := time.Now()
+	sessionStart := now
+	if user.Scope == auth.ScopeExtension {
+		sessionStart = user.SessionStart
+	}
+
+	ttl := time.Duration(s.cfg.TokenTTLMinutes) * time.Minute
+	left := sessionStart.Add(time.Duration(s.cfg.SessionMaxAgeHours) * time.Hour).Sub(now)
+	if left <= 0 {
+		return nil, apperror.ErrExtensionSessionExpired
+	}
+	ttl = min(ttl, left)
+
+	token, expiresAt, err := auth.CreateExtensionToken(user.ID, sessionStart, ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.ExtensionTokenResponse{Token: token, Scope: auth.ScopeExtension, ExpiresAt: expiresAt}, nil
+}
+
+// Heartbeat records the extension's version and capabilities, then tells it whether it must update, whether
+// every cookie must be synced again and which sources need a re-sync now. An extension that reports no
+// capabilities predates them and is assumed to sync every source.
+func (s *extensionService) Heartbeat(userID string, req *dto.ExtensionHeartbeatRequest) (*dto.ExtensionHeartbeatResponse, error) {
+	version, ok := util.ParseVersion(req.Version)
+	if !ok {
+		return nil, apperror.ErrInvalidExtensionVersion
+	}
+
+	capabilities := config.CookieSources
+	if len(req.Capabilities) > 0 {
+		capabilities = slices.DeleteFunc(slices.Clone(req.Capabilities), func(source string) bool {
+			return !slices.Contains(config.CookieSources, source)
+		})
+	}
+
+	resp := &dto.ExtensionHeartbeatResponse{
+		MinSupportedVersion: s.cfg.MinVersion,
+		LatestVersion:       s.cfg.LatestVersion,
+		UpdateRequired:      olderThan(version, s.cfg.MinVersion),
+		UpdateRecommended:   olderThan(version, s.cfg.LatestVersion),
+		ReauthRequired:      olderThan(version, s.cfg.ReauthBeforeVersion),
+		ResyncSources:       []string{},
+	}
+
+	if resp.ReauthRequired {
+		resp.ResyncSources = append(resp.ResyncSources, capabilities...)
+	} else {
+		status, err := s.cookieService.GetCookieStatus(userID)
+		if err != nil {
+			return nil, err
+		}
+		for _, source := range capabilities {
+			if st := status[source]; !st.Synced || st.RefreshRecommended {
+				resp.ResyncSources = append(resp.ResyncSources, source)
+			}
+		}
+	}
+
+	s.recordHeartbeat(userID, req.Version, capabilities)
+	return resp, nil
+}
+
+// recordHeartbeat keeps the user's last heartbeat so support can see which extension they run; it is best effort
+func (s *extensionService) recordHeartbeat(userID, version string, capabilities []string) {
+	ctx, cancel := util.NewDefaultRedisContext()
+	defer cancel()
+
+	key := fmt.Sprintf(config.RedisExtensionHeartbeatKey, userID)
+	pipe := s.redisClient.TxPipeline()
+	pipe.HSet(ctx, key, map[string]interface{}{
+		"version":      version,
+		"capabilities": strings.Join(capabilities, ","),
+		"seen_at":      time.Now().Unix(),
+	})
+	pipe.Expire(ctx, key, time.Duration(s.cfg.HeartbeatTTLHours)*time.Hour)
+	if _, err := pipe.Exec(ctx); err != nil {
+		slog.Warn("Extension: failed to record heartbeat", "user_id", userID, "error", err)
+	}
+}
+
+// olderThan reports whether version is older than the configured one; an empty or invalid setting never matches
+func olderThan(version []int, configured string) bool {
+	threshold, ok := util.ParseVersion(configured)
+	return ok && util.CompareVersions(version, threshold) < 0
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/util"
+	"github.com/redis/go-redis/v9"
+)
+
+// maintenanceCacheTTL bounds how long an instance keeps serving a cached maintenance state,
+// so toggling reaches every API instance within a few seconds without a Redis call per request
+const maintenanceCacheTTL = 5 * time.Second
+
+// MaintenanceService stores the maintenance mode flag in Redis, shared by all API instances
+type MaintenanceService interface {
+	// Current returns the cached state for per-request checks
+	Current() dto.MaintenanceStatus
+	GetStatus() (*dto.MaintenanceStatus, error)
+	SetStatus(adminID string, req *dto.UpdateMaintenanceRequest) (*dto.MaintenanceStatus, error)
+}
+
+type maintenanceService struct {
+	redisClient *redis.Client
+
+	mu        sync.Mutex
+	cached    dto.MaintenanceStatus
+	fetchedAt time.Time
+}
+
+func NewMaintenanceService(redisClient *redis.Client) MaintenanceService {
+	return &maintenanceService{
+		redisClient: redisClient,
+	}
+}
+
+func (s *maintenanceService) Current() dto.MaintenanceStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if time.Since(s.fetchedAt) < maintenanceCacheTTL {
+		return s.cached
+	}
+
+	status, err := s.load()
+	if err != nil {
+		// Keep serving the last known state rather than locking everyone out on a Redis hiccup
+		slog.Error("Maintenance: failed to load state", "error", err)
+	} else {
+		s.cached = *status
+	}
+	s.fetchedAt = time.Now()
+	return s.cached
+}
+
+func (s *maintenanceService) GetStatus() (*dto.MaintenanceStatus, error) {
+	return s.load()
+}
+
+func (s *maintenanceService) SetStatus(adminID string, req *dto.UpdateMaintenanceRequest) (*dto.MaintenanceStatus, error) {
+	ctx, cancel := util.NewDefaultRedisContext()
+	defer cancel()
+
+	now := time.Now()
+	status := dto.MaintenanceStatus{
+		Enabled:   *req.Enabled,
+		Message:   req.Message,
+		UpdatedBy: adminID,
+		UpdatedAt: &now,
+	}
+
+	err := s.redisClient.HSet(ctx, config.RedisMaintenanceKey,
+		"enabled", strconv.FormatBool(status.Enabled),
+		"message", status.Message,
+		"updated_by", status.UpdatedBy,
+		"updated_at", now.UnixMilli(),
+	).Err()
+	if err != nil {
+		return nil, err
+	}
+
+	// Apply to this instance immediately, others pick it up when their cache expires
+	s.mu.Lock()
+	s.cached = status
+	s.fetchedAt = now
+	s.mu.Unlock()
+
+	slog.Info("Maintenance mode set", "enabled", status.Enabled, "admin_id", adminID)
+	return &status, nil
+}
+
+// load reads the state from Redis. A missing key means maintenance mode was never enabled.
+func (s *maintenanceService) load() (*dto.MaintenanceStatus, error) {
+	ctx, cancel := util.NewDefaultRedisContext()
+	defer cancel()
+
+	fields, err := s.redisClient.HGetAll(ctx, config.RedisMaintenanceKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	status := &dto.MaintenanceStatus{
+		Enabled:   fields["enabled"] == "true",
+		Message:   fields["message"],
+		UpdatedBy: fields["updated_by"],
+	}
+	if ms, err := strconv.ParseInt(fields["updated_at"], 10, 64); err == nil {
+		updatedAt := time.UnixMilli(ms)
+		status.UpdatedAt = &updatedAt
+	}
+
+	return status, nil
+}
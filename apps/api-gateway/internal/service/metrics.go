@@ -0,0 +1,36 @@
+package service
+
+import "github.com/giakiet05/uit-ai-assistant/backend/internal/platform/metrics"
+
+// Business metrics, exposed on /metrics. Per-minute rates come from rate() over the counters.
+var (
+	chatMessages = metrics.NewCounterVec(
+		"chat_messages_total",
+		"Chat messages sent to the agent, by result.",
+		"result",
+	)
+	otpEmails = metrics.NewCounterVec(
+		"otp_emails_total",
+		"Verification OTP emails queued, by trigger (send, resend) and result.",
+		"trigger", "result",
+	)
+	emailSends = metrics.NewCounterVec(
+		"email_sends_total",
+		"Queued email send attempts, by kind and result (ok, retry, dead).",
+		"kind", "result",
+	)
+)
+
+// Results for business metrics
+const (
+	metricResultOK    = "ok"
+	metricResultError = "error"
+)
+
+// metricResult labels an outcome by its error
+func metricResult(err error) string {
+	if err != nil {
+		return metricResultError
+	}
+	return metricResultOK
+}
@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/apperror"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/gemini"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/repo"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/util"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// ModerationService checks content with Gemini and keeps a log of every decision,
+// so admins can review false positives and negatives and tune the confidence threshold.
+type ModerationService interface {
+	CheckContent(ctx context.Context, req *gemini.ContentCheckRequest) (*model.ModerationDecision, error)
+	GetDecisions(ctx context.Context, query *dto.GetModerationDecisionsQuery) (*dto.PaginatedModerationDecisionsResponse, error)
+	// MatchBlockedTopic returns the first of a user's blocked topics mentioned in content
+	MatchBlockedTopic(content string, topics []string) (string, bool)
+}
+
+type moderationService struct {
+	decisionRepo repo.ModerationDecisionRepo
+	geminiClient *gemini.GeminiClient
+	cfg          *config.GeminiConfig
+}
+
+func NewModerationService(decisionRepo repo.ModerationDecisionRepo, geminiClient *gemini.GeminiClient, cfg *config.GeminiConfig) ModerationService {
+	return &moderationService{
+		decisionRepo: decisionRepo,
+		geminiClient: geminiClient,
+		cfg:          cfg,
+	}
+}
+
+// CheckContent checks content and records the decision. Content is rejected when Gemini reports
+// a violation at or above the confidence threshold. On a Gemini error the failed check is
+// recorded and the error returned, leaving the caller to decide whether to fail open.
+func (s *moderationService) CheckContent(ctx context.Context, req *gemini.ContentCheckRequest) (*model.ModerationDecision, error) {
+	decision := &model.ModerationDecision{
+		ContentHash: contentHash(req),
+		ImageCount:  len(req.ImageURLs),
+		VideoCount:  len(req.VideoURLs),
+		Threshold:   s.cfg.ConfidenceThreshold,
+		Model:       s.cfg.Model,
+	}
+
+	// Nothing is checked when moderation is disabled, so there is no decision to keep
+	if s.geminiClient == nil {
+		decision.Action = model.ModerationActionApproved
+		return decision, nil
+	}
+
+	start := time.Now()
+	resp, err := s.geminiClient.CheckContent(ctx, req)
+	decision.LatencyMs = time.Since(start).Milliseconds()
+
+	if err != nil {
+		decision.Action = model.ModerationActionFailed
+		decision.Error = err.Error()
+	} else {
+		decision.IsViolation = resp.IsViolation
+		decision.Confidence = resp.Confidence
+		decision.Categories = resp.Categories
+		decision.Reason = resp.Reason
+
+		decision.Action = model.ModerationActionApproved
+		if resp.IsViolation && resp.Confidence >= s.cfg.ConfidenceThreshold {
+			decision.Action = model.ModerationActionRejected
+		}
+	}
+
+	// The log is for tuning only, a failed write must not block moderation
+	dbCtx, cancel := util.NewDefaultDBContext()
+	defer cancel()
+	if logErr := s.decisionRepo.Create(dbCtx, decision); logErr != nil {
+		slog.Warn("Failed to record moderation decision", "error", logErr)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	return decision, nil
+}
+
+func (s *moderationService) GetDecisions(ctx context.Context, query *dto.GetModerationDecisionsQuery) (*dto.PaginatedModerationDecisionsResponse, error) {
+	ctx, cancel := util.NewDBContextFrom(ctx)
+	defer cancel()
+
+	filter, err := moderationDecisionsFilter(query)
+	if err != nil {
+		return nil, err
+	}
+
+	page := query.Page
+	if page < 1 {
+		page = 1
+	}
+	pageSize := query.PageSize
+	if pageSize < 1 {
+		pageSize = 20
+	}
+
+	decisions, total, err := s.decisionRepo.Find(ctx, filter, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.PaginatedModerationDecisionsResponse{
+		Decisions: dto.FromModerationDecisions(decisions),
+		Pagination: dto.Pagination{
+			Page:     page,
+			PageSize: pageSize,
+			Total:    total,
+		},
+	}, nil
+}
+
+// MatchBlockedTopic is the post-filter applied to agent answers. Matching ignores case and only
+// counts whole words, so a blocked "art" does not hide an answer about "start".
+func (s *moderationService) MatchBlockedTopic(content string, topics []string) (string, bool) {
+	if len(topics) == 0 {
+		return "", false
+	}
+
+	text := strings.ToLower(content)
+	for _, topic := range topics {
+		if containsWord(text, strings.ToLower(topic)) {
+			return topic, true
+		}
+	}
+	return "", false
+}
+
+// containsWord checks if word occurs in text without a letter or digit directly before or after it
+func containsWord(text, word string) bool {
+	if word == "" {
+		return false
+	}
+
+	for offset := 0; offset < len(text); {
+		i := strings.Index(text[offset:], word)
+		if i < 0 {
+			return false
+		}
+		start := offset + i
+		end := start + len(word)
+
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		if !isWordRune(before) && !isWordRune(after) {
+			return true
+		}
+		offset = end
+	}
+	return false
+}
+
+func isWordRune(r rune) bool {
+	return r != utf8.RuneError && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+func moderationDecisionsFilter(query *dto.GetModerationDecisionsQuery) (repo.Filter, error) {
+	filter := repo.Filter{}
+	if query.Action != "" {
+		filter["action"] = query.Action
+	}
+	if query.Category != "" {
+		filter["categories"] = query.Category
+	}
+	if query.IsViolation != nil {
+		filter["is_violation"] = *query.IsViolation
+	}
+
+	if query.MinConfidence != nil && query.MaxConfidence != nil && *query.MinConfidence > *query.MaxConfidence {
+		return nil, apperror.ErrBadRequest
+	}
+	confidence := bson.M{}
+	if query.MinConfidence != nil {
+		confidence["$gte"] = *query.MinConfidence
+	}
+	if query.MaxConfidence != nil {
+		confidence["$lte"] = *query.MaxConfidence
+	}
+	if len(confidence) > 0 {
+		filter["confidence"] = confidence
+	}
+
+	loc, err := time.LoadLocation(repo.StatsTimezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	createdAt := bson.M{}
+	if query.From != "" {
+		from, err := time.ParseInLocation(analyticsDateLayout, query.From, loc)
+		if err != nil {
+			return nil, apperror.ErrInvalidDateFormat
+		}
+		createdAt["$gte"] = from
+	}
+	if query.To != "" {
+		to, err := time.ParseInLocation(analyticsDateLayout, query.To, loc)
+		if err != nil {
+			return nil, apperror.ErrInvalidDateFormat
+		}
+		// To is inclusive
+		createdAt["$lt"] = to.AddDate(0, 0, 1)
+	}
+	if from, ok := createdAt["$gte"].(time.Time); ok {
+		if end, ok := createdAt["$lt"].(time.Time); ok && !from.Before(end) {
+			return nil, apperror.ErrBadRequest
+		}
+	}
+	if len(createdAt) > 0 {
+		filter["created_at"] = createdAt
+	}
+
+	return filter, nil
+}
+
+// contentHash identifies checked content without storing it
+func contentHash(req *gemini.ContentCheckRequest) string {
+	h := sha256.New()
+	h.Write([]byte(req.Title))
+	h.Write([]byte{0})
+	h.Write([]byte(req.Text))
+	for _, url := range req.ImageURLs {
+		h.Write([]byte{0})
+		h.Write([]byte(url))
+	}
+	for _, url := range req.VideoURLs {
+		h.Write([]byte{0})
+		h.Write([]byte(url))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -1,86 +1,484 @@
 package service
 
 import (
-	"log"
+	"context"
+	"errors"
+	"log/slog"
+	"time"
 
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/apperror"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/bus"
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/repo"
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/util"
 	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 type NotificationService interface {
-	Start()
-	GetNotifications(recipientID string, page, pageSize int) (*dto.PaginatedNotificationsResponse, error)
-	MarkAllAsRead(recipientID string) (int64, error)
+	Start(workers *Workers)
+	CreateNotification(ctx context.Context, recipientID string, notifType model.NotificationType, message, link string, data *model.NotificationData) (*dto.NotificationResponse, error)
+	CreateBulkNotifications(ctx context.Context, recipients []*model.User, notifType model.NotificationType, message, link string, data *model.NotificationData, metadata map[string]interface{}) ([]*model.Notification, error)
+	GetNotifications(ctx context.Context, recipientID string, cursor string, limit int) (*dto.PaginatedNotificationsResponse, error)
+	GetNotificationsSince(ctx context.Context, recipientID string, since time.Time, limit int) ([]dto.NotificationResponse, bool, error)
+	MarkAsRead(ctx context.Context, recipientID, notificationID string) error
+	MarkAllAsRead(ctx context.Context, recipientID string) (int64, error)
+	GetUnreadCount(ctx context.Context, recipientID string) (int64, error)
+
+	// Scheduled notifications
+	ScheduleNotification(ctx context.Context, recipientIDs []string, notifType model.NotificationType, message, link string, data *model.NotificationData, deliverAt time.Time, localTime string, createdBy string) ([]dto.ScheduledNotificationResponse, error)
+	GetScheduledNotifications(ctx context.Context, status model.ScheduledNotificationStatus, page, pageSize int) (*dto.PaginatedScheduledNotificationsResponse, error)
+	CancelScheduledNotification(ctx context.Context, id string) (*dto.ScheduledNotificationResponse, error)
 }
 
 type notificationService struct {
-	notificationRepo repo.NotificationRepo
-	userRepo         repo.UserRepo
-	eventBus         bus.EventBus
-	redisClient      *redis.Client
+	notificationRepo          repo.NotificationRepo
+	scheduledNotificationRepo repo.ScheduledNotificationRepo
+	userRepo                  repo.UserRepo
+	eventBus                  bus.EventBus
+	redisClient               *redis.Client
+	emailQueue                EmailQueueService
+	schedulerCfg              *config.SchedulerConfig
+	retentionCfg              *config.RetentionConfig
 }
 
 func NewNotificationService(
 	notificationRepo repo.NotificationRepo,
+	scheduledNotificationRepo repo.ScheduledNotificationRepo,
 	userRepo repo.UserRepo,
 	bus bus.EventBus,
 	redis *redis.Client,
+	emailQueue EmailQueueService,
+	schedulerCfg *config.SchedulerConfig,
+	retentionCfg *config.RetentionConfig,
 ) NotificationService {
 	return &notificationService{
-		notificationRepo: notificationRepo,
-		userRepo:         userRepo,
-		eventBus:         bus,
-		redisClient:      redis,
+		notificationRepo:          notificationRepo,
+		scheduledNotificationRepo: scheduledNotificationRepo,
+		userRepo:                  userRepo,
+		eventBus:                  bus,
+		redisClient:               redis,
+		emailQueue:                emailQueue,
+		schedulerCfg:              schedulerCfg,
+		retentionCfg:              retentionCfg,
 	}
 }
 
-func (s *notificationService) Start() {
-	eventChannel := make(bus.EventListener, 100)
+// Start launches the dispatcher loop that delivers due scheduled notifications
+// and the cleanup loop that enforces the retention policy
+func (s *notificationService) Start(workers *Workers) {
+	workers.TickEvery(time.Duration(s.schedulerCfg.IntervalSeconds)*time.Second, s.dispatchDueNotifications)
+	workers.RunEvery(time.Duration(s.retentionCfg.CleanupIntervalHours)*time.Hour, func(context.Context) {
+		s.deleteExpiredNotifications()
+	})
+
+	slog.Info("NotificationService started with scheduled notification dispatcher and retention cleanup")
+}
+
+// CreateNotification stores and pushes a notification, honoring the recipient's per-type preferences.
+// Returns nil without error when the recipient has disabled in-app delivery for the type.
+func (s *notificationService) CreateNotification(ctx context.Context, recipientID string, notifType model.NotificationType, message, link string, data *model.NotificationData) (*dto.NotificationResponse, error) {
+	ctx, cancel := util.NewDBContextFrom(ctx)
+	defer cancel()
+
+	recipient, err := s.userRepo.GetByID(ctx, recipientID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, apperror.ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	pref := recipient.Settings.NotificationPreference(notifType)
+
+	if pref.Email && recipient.Email != "" {
+		if err := s.emailQueue.SendNotificationEmail(recipient.Email, emailSubject(notifType), message, link, notificationUnsubscribeURL(recipientID, notifType)); err != nil {
+			slog.Error("Failed to queue notification email", "user_id", recipientID, "error", err)
+		}
+	}
 
-	s.eventBus.Subscribe(bus.TopicBroadcast, eventChannel)
+	if !pref.InApp {
+		return nil, nil
+	}
+
+	notification, err := s.notificationRepo.Create(ctx, &model.Notification{
+		RecipientID: recipient.ID,
+		Type:        notifType,
+		Message:     message,
+		Link:        link,
+		Data:        data,
+		IsRead:      false,
+		CreatedAt:   time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	log.Println("NotificationService started and subscribed to events.")
+	response := dto.FromNotification(notification)
+	s.eventBus.Publish(bus.NotificationCreatedEvent{
+		RecipientID:  recipientID,
+		Notification: response,
+	})
+	s.publishUnreadCount(ctx, recipientID)
 
-	go s.processEvents(eventChannel)
+	return &response, nil
 }
 
-func (s *notificationService) processEvents(ch bus.EventListener) {
-	for event := range ch {
-		switch event.Topic() {
-		case bus.TopicBroadcast:
-			s.handleBroadcast(event)
+// CreateBulkNotifications stores the same notification for many recipients in one insert, honoring
+// each recipient's per-type preferences. Publishing to WebSocket clients is left to the caller.
+func (s *notificationService) CreateBulkNotifications(ctx context.Context, recipients []*model.User, notifType model.NotificationType, message, link string, data *model.NotificationData, metadata map[string]interface{}) ([]*model.Notification, error) {
+	ctx, cancel := util.NewDBContextFrom(ctx)
+	defer cancel()
+
+	now := time.Now()
+	notifications := make([]*model.Notification, 0, len(recipients))
+	var emailTo []*model.User
+
+	for _, recipient := range recipients {
+		pref := recipient.Settings.NotificationPreference(notifType)
+		if pref.Email && recipient.Email != "" {
+			emailTo = append(emailTo, recipient)
+		}
+		if !pref.InApp {
+			continue
 		}
+
+		notifications = append(notifications, &model.Notification{
+			RecipientID: recipient.ID,
+			Type:        notifType,
+			Message:     message,
+			Link:        link,
+			Data:        data,
+			IsRead:      false,
+			Metadata:    metadata,
+			CreatedAt:   now,
+		})
+	}
+
+	if len(emailTo) > 0 {
+		// Queuing is one Redis round trip per recipient, large audiences should not hold up the caller
+		go func() {
+			for _, to := range emailTo {
+				if err := s.emailQueue.SendNotificationEmail(to.Email, emailSubject(notifType), message, link, notificationUnsubscribeURL(to.ID.Hex(), notifType)); err != nil {
+					slog.Error("Failed to queue notification email", "to", to.Email, "error", err)
+				}
+			}
+		}()
 	}
+
+	return s.notificationRepo.CreateMany(ctx, notifications)
 }
 
-func (s *notificationService) GetNotifications(recipientID string, page, pageSize int) (*dto.PaginatedNotificationsResponse, error) {
-	ctx, cancel := util.NewDefaultDBContext()
+// GetNotificationsSince returns notifications created after since, oldest first, and whether more remain
+func (s *notificationService) GetNotificationsSince(ctx context.Context, recipientID string, since time.Time, limit int) ([]dto.NotificationResponse, bool, error) {
+	ctx, cancel := util.NewDBContextFrom(ctx)
 	defer cancel()
 
-	notifications, total, err := s.notificationRepo.GetByRecipientID(ctx, recipientID, page, pageSize)
+	notifications, hasMore, err := s.notificationRepo.GetCreatedSince(ctx, recipientID, since, limit)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return dto.FromNotifications(notifications), hasMore, nil
+}
+
+func (s *notificationService) GetNotifications(ctx context.Context, recipientID string, cursor string, limit int) (*dto.PaginatedNotificationsResponse, error) {
+	ctx, cancel := util.NewDBContextFrom(ctx)
+	defer cancel()
+
+	if limit < 1 || limit > 50 {
+		limit = 15
+	}
+
+	var after *repo.Cursor
+	if cursor != "" {
+		decoded, err := repo.DecodeCursor(cursor)
+		if err != nil {
+			return nil, apperror.ErrInvalidCursor
+		}
+		after = decoded
+	}
+
+	notifications, next, err := s.notificationRepo.GetByRecipientID(ctx, recipientID, after, limit)
 	if err != nil {
 		return nil, err
 	}
 
-	return &dto.PaginatedNotificationsResponse{
+	response := &dto.PaginatedNotificationsResponse{
 		Notifications: dto.FromNotifications(notifications),
+	}
+	if next != nil {
+		response.NextCursor = next.Encode()
+		response.HasMore = true
+	}
+
+	return response, nil
+}
+
+func (s *notificationService) MarkAsRead(ctx context.Context, recipientID, notificationID string) error {
+	ctx, cancel := util.NewDBContextFrom(ctx)
+	defer cancel()
+
+	if !primitive.IsValidObjectID(notificationID) {
+		return apperror.ErrInvalidID
+	}
+
+	if err := s.notificationRepo.MarkAsRead(ctx, notificationID, recipientID); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return apperror.ErrNotificationNotFound
+		}
+		return err
+	}
+
+	s.publishUnreadCount(ctx, recipientID)
+	return nil
+}
+
+func (s *notificationService) MarkAllAsRead(ctx context.Context, recipientID string) (int64, error) {
+	ctx, cancel := util.NewDBContextFrom(ctx)
+	defer cancel()
+
+	modified, err := s.notificationRepo.MarkAllAsRead(ctx, recipientID)
+	if err != nil {
+		return 0, err
+	}
+
+	if modified > 0 {
+		s.publishUnreadCount(ctx, recipientID)
+	}
+
+	return modified, nil
+}
+
+func (s *notificationService) GetUnreadCount(ctx context.Context, recipientID string) (int64, error) {
+	ctx, cancel := util.NewDBContextFrom(ctx)
+	defer cancel()
+
+	return s.notificationRepo.CountUnread(ctx, recipientID)
+}
+
+// ScheduleNotification queues a notification for each recipient to be delivered at deliverAt.
+// If localTime is set, it is resolved in each recipient's timezone instead, so a reminder at 08:00
+// reaches every student in their own morning.
+// createdBy is the scheduling admin's ID, or empty for system jobs.
+func (s *notificationService) ScheduleNotification(ctx context.Context, recipientIDs []string, notifType model.NotificationType, message, link string, data *model.NotificationData, deliverAt time.Time, localTime string, createdBy string) ([]dto.ScheduledNotificationResponse, error) {
+	ctx, cancel := util.NewDBContextFrom(ctx)
+	defer cancel()
+
+	var locations map[string]*time.Location
+	if localTime != "" {
+		var err error
+		if locations, err = s.recipientLocations(ctx, recipientIDs); err != nil {
+			return nil, err
+		}
+	} else if !deliverAt.After(time.Now()) {
+		return nil, apperror.ErrInvalidDeliverAt
+	}
+
+	var createdByObjID *primitive.ObjectID
+	if createdBy != "" {
+		objID, err := primitive.ObjectIDFromHex(createdBy)
+		if err != nil {
+			return nil, apperror.ErrInvalidID
+		}
+		createdByObjID = &objID
+	}
+
+	scheduled := make([]*model.ScheduledNotification, 0, len(recipientIDs))
+	for _, recipientID := range recipientIDs {
+		recipientObjID, err := primitive.ObjectIDFromHex(recipientID)
+		if err != nil {
+			return nil, apperror.ErrInvalidID
+		}
+
+		n := &model.ScheduledNotification{
+			RecipientID: recipientObjID,
+			Type:        notifType,
+			Message:     message,
+			Link:        link,
+			Data:        data,
+			DeliverAt:   deliverAt,
+			Status:      model.ScheduledStatusPending,
+			CreatedBy:   createdByObjID,
+		}
+		if localTime != "" {
+			loc := locations[recipientID]
+			n.DeliverAt, err = time.ParseInLocation(model.ScheduledLocalTimeLayout, localTime, loc)
+			if err != nil || !n.DeliverAt.After(time.Now()) {
+				return nil, apperror.ErrInvalidDeliverAt
+			}
+			n.LocalTime = localTime
+			n.Timezone = loc.String()
+		}
+		scheduled = append(scheduled, n)
+	}
+
+	created, err := s.scheduledNotificationRepo.CreateMany(ctx, scheduled)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.FromScheduledNotifications(created), nil
+}
+
+// recipientLocations returns the timezone of each recipient, recipients not found get the default timezone
+func (s *notificationService) recipientLocations(ctx context.Context, recipientIDs []string) (map[string]*time.Location, error) {
+	users, err := s.userRepo.GetByIDs(ctx, recipientIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	locations := make(map[string]*time.Location, len(recipientIDs))
+	for _, u := range users {
+		locations[u.ID.Hex()] = u.Settings.Location()
+	}
+	for _, id := range recipientIDs {
+		if _, ok := locations[id]; !ok {
+			locations[id] = model.TimezoneLocation("")
+		}
+	}
+
+	return locations, nil
+}
+
+func (s *notificationService) GetScheduledNotifications(ctx context.Context, status model.ScheduledNotificationStatus, page, pageSize int) (*dto.PaginatedScheduledNotificationsResponse, error) {
+	ctx, cancel := util.NewDBContextFrom(ctx)
+	defer cancel()
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	scheduled, total, err := s.scheduledNotificationRepo.Find(ctx, status, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.PaginatedScheduledNotificationsResponse{
+		ScheduledNotifications: dto.FromScheduledNotifications(scheduled),
 		Pagination: dto.Pagination{
-			Total: total,
-			Page:  page,
+			Page:     page,
+			PageSize: pageSize,
+			Total:    total,
 		},
 	}, nil
 }
 
-func (s *notificationService) MarkAllAsRead(recipientID string) (int64, error) {
+// CancelScheduledNotification cancels a notification that has not been delivered yet
+func (s *notificationService) CancelScheduledNotification(ctx context.Context, id string) (*dto.ScheduledNotificationResponse, error) {
+	ctx, cancel := util.NewDBContextFrom(ctx)
+	defer cancel()
+
+	if !primitive.IsValidObjectID(id) {
+		return nil, apperror.ErrInvalidID
+	}
+
+	cancelled, err := s.scheduledNotificationRepo.Cancel(ctx, id)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, apperror.ErrScheduledNotificationNotFound
+		}
+		return nil, err
+	}
+
+	response := dto.FromScheduledNotification(cancelled)
+	return &response, nil
+}
+
+// dispatchDueNotifications claims and delivers due scheduled notifications, up to the configured batch size
+func (s *notificationService) dispatchDueNotifications(stop context.Context) {
 	ctx, cancel := util.NewDefaultDBContext()
 	defer cancel()
 
-	return s.notificationRepo.MarkAllAsRead(ctx, recipientID)
+	for i := 0; i < s.schedulerCfg.BatchSize && stop.Err() == nil; i++ {
+		scheduled, err := s.scheduledNotificationRepo.ClaimDue(ctx, time.Now())
+		if err != nil {
+			if !errors.Is(err, mongo.ErrNoDocuments) {
+				slog.Error("Scheduler: failed to claim due notification", "error", err)
+			}
+			return
+		}
+
+		_, err = s.CreateNotification(ctx, scheduled.RecipientID.Hex(), scheduled.Type, scheduled.Message, scheduled.Link, scheduled.Data)
+		if err != nil {
+			slog.Error("Scheduler: failed to deliver scheduled notification", "notification_id", scheduled.ID.Hex(), "error", err)
+			if err := s.scheduledNotificationRepo.MarkFailed(ctx, scheduled.ID, err.Error()); err != nil {
+				slog.Error("Scheduler: failed to mark notification as failed", "notification_id", scheduled.ID.Hex(), "error", err)
+			}
+			continue
+		}
+
+		if err := s.scheduledNotificationRepo.MarkDelivered(ctx, scheduled.ID, time.Now()); err != nil {
+			slog.Error("Scheduler: failed to mark notification as delivered", "notification_id", scheduled.ID.Hex(), "error", err)
+		}
+	}
 }
 
-func (s *notificationService) handleBroadcast(event bus.Event) {
-	panic("not implemented")
+// deleteExpiredNotifications removes notifications past the retention policy
+func (s *notificationService) deleteExpiredNotifications() {
+	ctx, cancel := util.NewDefaultDBContext()
+	defer cancel()
+
+	now := time.Now()
+	readBefore := now.AddDate(0, 0, -s.retentionCfg.ReadNotificationDays)
+
+	var unreadBefore *time.Time
+	if s.retentionCfg.UnreadNotificationDays > 0 {
+		t := now.AddDate(0, 0, -s.retentionCfg.UnreadNotificationDays)
+		unreadBefore = &t
+	}
+
+	deleted, err := s.notificationRepo.DeleteExpired(ctx, readBefore, unreadBefore)
+	if err != nil {
+		slog.Error("Retention: failed to delete expired notifications", "error", err)
+		return
+	}
+
+	if deleted > 0 {
+		slog.Info("Retention: deleted expired notifications", "count", deleted)
+	}
+}
+
+// publishUnreadCount pushes the recipient's current unread count to their WebSocket clients
+func (s *notificationService) publishUnreadCount(ctx context.Context, recipientID string) {
+	count, err := s.notificationRepo.CountUnread(ctx, recipientID)
+	if err != nil {
+		slog.Error("Failed to count unread notifications", "user_id", recipientID, "error", err)
+		return
+	}
+
+	s.eventBus.Publish(bus.UnreadCountChangedEvent{
+		RecipientID: recipientID,
+		UnreadCount: count,
+	})
+}
+
+// emailSubject returns the email subject line for a notification type
+func emailSubject(notifType model.NotificationType) string {
+	switch notifType {
+	case model.NotificationTypeDeadlineReminder:
+		return "Nhắc nhở hạn chót từ UIT"
+	case model.NotificationTypeChatCompleted:
+		return "Trợ lý AI đã trả lời câu hỏi của bạn"
+	case model.NotificationTypeAnnouncement:
+		return "Thông báo mới từ UIT AI Assistant"
+	case model.NotificationTypeDataExport:
+		return "Dữ liệu của bạn đã sẵn sàng để tải xuống"
+	case model.NotificationTypeCookieExpired:
+		return "Phiên đăng nhập cổng UIT đã hết hạn"
+	case model.NotificationTypeGradePosted:
+		return "Bạn có điểm mới trên cổng DAA"
+	case model.NotificationTypeUITAnnouncement:
+		return "Thông báo mới từ nhà trường"
+	case model.NotificationTypeSystem:
+		return "Thông báo từ hệ thống"
+	default:
+		return "Bạn có thông báo mới"
+	}
 }
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/bus"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/repo"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/util"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// OutboxService stores domain events together with the writes that cause them and relays them to the event bus,
+// so an event is not lost when the process stops between the write and the publish.
+// Delivery is at least once: an event may be published again if the relay stops before marking it delivered.
+type OutboxService interface {
+	Start(workers *Workers)
+	// Add stores events to publish once the write is committed. Call it inside Transactor.Run with its ctx.
+	Add(ctx context.Context, events ...bus.Event) error
+}
+
+type outboxService struct {
+	outboxRepo repo.OutboxRepo
+	eventBus   bus.EventBus
+	cfg        *config.OutboxConfig
+}
+
+func NewOutboxService(outboxRepo repo.OutboxRepo, eventBus bus.EventBus, cfg *config.OutboxConfig) OutboxService {
+	return &outboxService{
+		outboxRepo: outboxRepo,
+		eventBus:   eventBus,
+		cfg:        cfg,
+	}
+}
+
+func (s *outboxService) Start(workers *Workers) {
+	interval := time.Duration(s.cfg.RelayIntervalSeconds) * time.Second
+	if interval <= 0 {
+		slog.Warn("Outbox: relay disabled, stored events are not published")
+		return
+	}
+
+	workers.TickEvery(interval, s.relay)
+
+	slog.Info("Outbox relay started", "interval", interval)
+}
+
+func (s *outboxService) Add(ctx context.Context, events ...bus.Event) error {
+	now := time.Now()
+	entries := make([]*model.OutboxEvent, len(events))
+	for i, event := range events {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		entries[i] = &model.OutboxEvent{Topic: event.Topic(), Payload: string(payload), CreatedAt: now}
+	}
+	return s.outboxRepo.Insert(ctx, entries)
+}
+
+// relay publishes up to a batch of pending events, oldest first
+func (s *outboxService) relay(stop context.Context) {
+	ctx, cancel := util.NewDefaultDBContext()
+	defer cancel()
+
+	lease := time.Duration(s.cfg.LeaseSeconds) * time.Second
+	for range s.cfg.BatchSize {
+		if stop.Err() != nil {
+			return
+		}
+
+		now := time.Now()
+		entry, err := s.outboxRepo.ClaimNext(ctx, now, now.Add(lease))
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return
+		}
+		if err != nil {
+			slog.Error("Outbox: failed to claim event", "error", err)
+			return
+		}
+
+		// An event that cannot be decoded never will be, it is dropped rather than retried forever
+		event, err := bus.DecodeEvent(entry.Topic, []byte(entry.Payload))
+		if err != nil {
+			slog.Error("Outbox: failed to decode event, dropped", "id", entry.ID.Hex(), "topic", entry.Topic, "error", err)
+		} else {
+			s.eventBus.Publish(event)
+		}
+
+		if err := s.outboxRepo.MarkDelivered(ctx, entry.ID, time.Now()); err != nil {
+			slog.Error("Outbox: failed to mark event delivered, it will be published again", "id", entry.ID.Hex(), "error", err)
+		}
+	}
+}
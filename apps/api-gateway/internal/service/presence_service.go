@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/apperror"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/bus"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/repo"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/util"
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// presenceTTL expires presence records of users who have not connected for a long time
+const presenceTTL = 30 * 24 * time.Hour
+
+// decrementConnections lowers the connection count without going below zero,
+// so a disconnect replayed after a Redis flush cannot make a user look online forever
+var decrementConnections = redis.NewScript(`
+local n = redis.call("HINCRBY", KEYS[1], "connections", -1)
+if n < 0 then
+	redis.call("HSET", KEYS[1], "connections", 0)
+	n = 0
+end
+return n
+`)
+
+// PresenceService tracks which users have an open WebSocket connection.
+// Connections are counted per user so a user connected to several API instances stays online
+// until the last connection closes.
+type PresenceService interface {
+	Connected(userID string)
+	Disconnected(userID string)
+	GetPresence(ctx context.Context, requester, userID string, isAdmin bool) (*dto.PresenceResponse, error)
+}
+
+type presenceService struct {
+	userRepo    repo.UserRepo
+	redisClient *redis.Client
+	eventBus    bus.EventBus
+}
+
+func NewPresenceService(userRepo repo.UserRepo, redisClient *redis.Client, eventBus bus.EventBus) PresenceService {
+	return &presenceService{
+		userRepo:    userRepo,
+		redisClient: redisClient,
+		eventBus:    eventBus,
+	}
+}
+
+func (s *presenceService) Connected(userID string) {
+	ctx, cancel := util.NewDefaultRedisContext()
+	defer cancel()
+
+	now := time.Now()
+	key := fmt.Sprintf(config.RedisPresenceKey, userID)
+
+	pipe := s.redisClient.TxPipeline()
+	connections := pipe.HIncrBy(ctx, key, "connections", 1)
+	pipe.HSet(ctx, key, "last_seen", now.UnixMilli())
+	pipe.Expire(ctx, key, presenceTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		slog.Warn("Presence: failed to record connect", "user_id", userID, "error", err)
+		return
+	}
+
+	// Only the first connection changes the user's state
+	if connections.Val() == 1 {
+		if err := s.redisClient.SAdd(ctx, config.RedisOnlineUsersKey, userID).Err(); err != nil {
+			slog.Warn("Presence: failed to add user to online users", "user_id", userID, "error", err)
+		}
+		s.publish(userID, true, now)
+	}
+}
+
+func (s *presenceService) Disconnected(userID string) {
+	ctx, cancel := util.NewDefaultRedisContext()
+	defer cancel()
+
+	now := time.Now()
+	key := fmt.Sprintf(config.RedisPresenceKey, userID)
+
+	remaining, err := decrementConnections.Run(ctx, s.redisClient, []string{key}).Int64()
+	if err != nil {
+		slog.Warn("Presence: failed to record disconnect", "user_id", userID, "error", err)
+		return
+	}
+	if err := s.redisClient.HSet(ctx, key, "last_seen", now.UnixMilli()).Err(); err != nil {
+		slog.Warn("Presence: failed to update last seen", "user_id", userID, "error", err)
+	}
+
+	if remaining == 0 {
+		if err := s.redisClient.SRem(ctx, config.RedisOnlineUsersKey, userID).Err(); err != nil {
+			slog.Warn("Presence: failed to remove user from online users", "user_id", userID, "error", err)
+		}
+		s.publish(userID, false, now)
+	}
+}
+
+// GetPresence returns a user's online state. Users can see their own presence, admins can see anyone's.
+func (s *presenceService) GetPresence(ctx context.Context, requester, userID string, isAdmin bool) (*dto.PresenceResponse, error) {
+	if requester != userID && !isAdmin {
+		return nil, apperror.ErrForbidden
+	}
+
+	dbCtx, dbCancel := util.NewDBContextFrom(ctx)
+	defer dbCancel()
+
+	if _, err := s.userRepo.GetByID(dbCtx, userID); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, apperror.ErrUserNotFound
+		}
+		return nil, apperror.ErrInvalidID
+	}
+
+	ctx, cancel := util.NewDefaultRedisContext()
+	defer cancel()
+
+	fields, err := s.redisClient.HGetAll(ctx, fmt.Sprintf(config.RedisPresenceKey, userID)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.PresenceResponse{UserID: userID}
+	if n, err := strconv.ParseInt(fields["connections"], 10, 64); err == nil {
+		response.Online = n > 0
+	}
+	if ms, err := strconv.ParseInt(fields["last_seen"], 10, 64); err == nil {
+		lastSeen := time.UnixMilli(ms)
+		response.LastSeen = &lastSeen
+	}
+
+	return response, nil
+}
+
+func (s *presenceService) publish(userID string, online bool, at time.Time) {
+	s.eventBus.Publish(bus.PresenceChangedEvent{
+		UserID:   userID,
+		Online:   online,
+		LastSeen: at,
+	})
+}
@@ -0,0 +1,242 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/apperror"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/repo"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/util"
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// quotaKeyTTL keeps a day's counters a little longer than the day itself
+const quotaKeyTTL = 48 * time.Hour
+
+// QuotaService enforces daily chat limits per user. Limits come from config unless an admin
+// set an override on the user; usage is counted per day in Redis.
+// Users can also set limits on themselves, which notify when crossed and only block chat if they opted in.
+type QuotaService interface {
+	CheckChatQuota(ctx context.Context, userID string) error
+	RecordChatUsage(ctx context.Context, userID string, tokens int, settings *model.UserSettings)
+
+	GetUserQuota(ctx context.Context, userID string) (*dto.UserQuotaResponse, error)
+	SetUserQuota(ctx context.Context, adminID, userID string, req *dto.SetUserQuotaRequest) (*dto.UserQuotaResponse, error)
+	ClearUserQuota(ctx context.Context, adminID, userID string) (*dto.UserQuotaResponse, error)
+}
+
+type quotaService struct {
+	userRepo            repo.UserRepo
+	redisClient         *redis.Client
+	auditService        AuditService
+	notificationService NotificationService
+	cfg                 *config.QuotaConfig
+}
+
+func NewQuotaService(userRepo repo.UserRepo, redisClient *redis.Client, auditService AuditService, notificationService NotificationService, cfg *config.QuotaConfig) QuotaService {
+	return &quotaService{
+		userRepo:            userRepo,
+		redisClient:         redisClient,
+		auditService:        auditService,
+		notificationService: notificationService,
+		cfg:                 cfg,
+	}
+}
+
+// CheckChatQuota fails with ErrQuotaExceeded once the user reached a daily limit, or with
+// ErrUsageLimitReached once they reached a self-imposed limit they chose to enforce.
+// If usage cannot be read the check lets the message through.
+func (s *quotaService) CheckChatQuota(ctx context.Context, userID string) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	limits := s.limitsFor(user)
+	enforceOwn := user.Settings.UsageLimitEnforced
+	if limits.DailyMessages == 0 && limits.DailyTokens == 0 && !enforceOwn {
+		return nil
+	}
+
+	used, err := s.usedToday(ctx, userID)
+	if err != nil {
+		slog.Error("Quota: failed to read usage", "user_id", userID, "error", err)
+		return nil
+	}
+
+	if (limits.DailyMessages > 0 && used.DailyMessages >= limits.DailyMessages) ||
+		(limits.DailyTokens > 0 && used.DailyTokens >= limits.DailyTokens) {
+		return apperror.ErrQuotaExceeded
+	}
+	if enforceOwn && user.Settings.IsUsageLimitReached(used.DailyMessages, used.DailyTokens) {
+		return apperror.ErrUsageLimitReached
+	}
+	return nil
+}
+
+// RecordChatUsage counts one answered message and its tokens towards today's usage.
+// settings are the user's settings, used to notify them when this message crosses one of their own limits.
+func (s *quotaService) RecordChatUsage(ctx context.Context, userID string, tokens int, settings *model.UserSettings) {
+	key := quotaKey(userID, time.Now())
+
+	pipe := s.redisClient.TxPipeline()
+	messagesCmd := pipe.HIncrBy(ctx, key, "messages", 1)
+	tokensCmd := pipe.HIncrBy(ctx, key, "tokens", int64(tokens))
+	pipe.Expire(ctx, key, quotaKeyTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		slog.Error("Quota: failed to record usage", "user_id", userID, "error", err)
+		return
+	}
+
+	if settings == nil {
+		return
+	}
+	messages, totalTokens := int(messagesCmd.Val()), int(tokensCmd.Val())
+	// Counters only grow, so exactly one message per day moves usage from below the limit to reaching it
+	if !settings.IsUsageLimitReached(messages-1, totalTokens-tokens) && settings.IsUsageLimitReached(messages, totalTokens) {
+		s.notifyUsageLimit(userID, settings)
+	}
+}
+
+// notifyUsageLimit tells the user they reached one of their own daily limits
+func (s *quotaService) notifyUsageLimit(userID string, settings *model.UserSettings) {
+	message := "Bạn đã đạt giới hạn sử dụng hôm nay do bạn tự đặt. Bạn vẫn có thể tiếp tục trò chuyện."
+	if settings.UsageLimitEnforced {
+		message = "Bạn đã đạt giới hạn sử dụng hôm nay do bạn tự đặt. Trò chuyện sẽ tạm dừng đến ngày mai."
+	}
+
+	_, err := s.notificationService.CreateNotification(context.Background(), userID, model.NotificationTypeUsageLimit, message, "", &model.NotificationData{
+		EntityType: model.EntityTypeSettings,
+		EntityID:   "usage",
+		Action:     model.NotificationActionOpen,
+	})
+	if err != nil {
+		slog.Error("Quota: failed to notify user about their usage limit", "user_id", userID, "error", err)
+	}
+}
+
+func (s *quotaService) GetUserQuota(ctx context.Context, userID string) (*dto.UserQuotaResponse, error) {
+	ctx, cancel := util.NewDBContextFrom(ctx)
+	defer cancel()
+
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.quotaResponse(ctx, user)
+}
+
+// SetUserQuota overrides the user's default limits, e.g. for club or demo accounts
+func (s *quotaService) SetUserQuota(ctx context.Context, adminID, userID string, req *dto.SetUserQuotaRequest) (*dto.UserQuotaResponse, error) {
+	ctx, cancel := util.NewDBContextFrom(ctx)
+	defer cancel()
+
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := map[string]string{
+		"daily_messages": strconv.Itoa(*req.DailyMessages),
+		"daily_tokens":   strconv.Itoa(*req.DailyTokens),
+	}
+	if err := s.auditService.Record(ctx, adminID, model.AuditActionSetUserQuota, model.AuditTargetUser, userID, metadata); err != nil {
+		return nil, err
+	}
+
+	user.QuotaOverride = &model.UserQuota{
+		DailyMessages: *req.DailyMessages,
+		DailyTokens:   *req.DailyTokens,
+	}
+	user.UpdatedAt = time.Now()
+	if user, err = s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return s.quotaResponse(ctx, user)
+}
+
+// ClearUserQuota removes the override so the default limits apply again
+func (s *quotaService) ClearUserQuota(ctx context.Context, adminID, userID string) (*dto.UserQuotaResponse, error) {
+	ctx, cancel := util.NewDBContextFrom(ctx)
+	defer cancel()
+
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.QuotaOverride != nil {
+		if err := s.auditService.Record(ctx, adminID, model.AuditActionClearUserQuota, model.AuditTargetUser, userID, nil); err != nil {
+			return nil, err
+		}
+
+		user.QuotaOverride = nil
+		user.UpdatedAt = time.Now()
+		if user, err = s.userRepo.Update(ctx, user); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.quotaResponse(ctx, user)
+}
+
+func (s *quotaService) getUser(ctx context.Context, userID string) (*model.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, apperror.ErrUserNotFound
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *quotaService) quotaResponse(ctx context.Context, user *model.User) (*dto.UserQuotaResponse, error) {
+	used, err := s.usedToday(ctx, user.ID.Hex())
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().In(statsLocation())
+	return &dto.UserQuotaResponse{
+		UserID:    user.ID.Hex(),
+		Override:  user.QuotaOverride,
+		Limits:    s.limitsFor(user),
+		UsedToday: *used,
+		ResetsAt:  time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location()),
+	}, nil
+}
+
+// limitsFor returns the user's override if set, the configured defaults otherwise
+func (s *quotaService) limitsFor(user *model.User) model.UserQuota {
+	if user.QuotaOverride != nil {
+		return *user.QuotaOverride
+	}
+	return model.UserQuota{DailyMessages: s.cfg.DailyMessages, DailyTokens: s.cfg.DailyTokens}
+}
+
+func (s *quotaService) usedToday(ctx context.Context, userID string) (*model.UserQuota, error) {
+	values, err := s.redisClient.HGetAll(ctx, quotaKey(userID, time.Now())).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	messages, _ := strconv.Atoi(values["messages"])
+	tokens, _ := strconv.Atoi(values["tokens"])
+	return &model.UserQuota{DailyMessages: messages, DailyTokens: tokens}, nil
+}
+
+// quotaKey is the Redis key of a user's usage on the day of t, in the stats timezone
+func quotaKey(userID string, t time.Time) string {
+	return fmt.Sprintf(config.RedisChatQuotaKey, userID, t.In(statsLocation()).Format(analyticsDateLayout))
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/apperror"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/repo"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/util"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type ReportService interface {
+	// User-facing
+	ReportMessage(ctx context.Context, userID, messageID string, req *dto.ReportMessageRequest) (*dto.MessageReportResponse, error)
+
+	// Moderation queue (admin)
+	GetReports(ctx context.Context, query *dto.GetReportsQuery) (*dto.PaginatedReportsResponse, error)
+	ReviewReport(ctx context.Context, adminID, reportID string, req *dto.ReviewReportRequest) (*dto.MessageReportResponse, error)
+}
+
+type reportService struct {
+	reportRepo  repo.MessageReportRepo
+	sessionRepo repo.ChatSessionRepo
+	messageRepo repo.ChatMessageRepo
+}
+
+func NewReportService(reportRepo repo.MessageReportRepo, sessionRepo repo.ChatSessionRepo, messageRepo repo.ChatMessageRepo) ReportService {
+	return &reportService{
+		reportRepo:  reportRepo,
+		sessionRepo: sessionRepo,
+		messageRepo: messageRepo,
+	}
+}
+
+// ReportMessage files a report on an assistant answer in one of the user's own sessions
+func (s *reportService) ReportMessage(ctx context.Context, userID, messageID string, req *dto.ReportMessageRequest) (*dto.MessageReportResponse, error) {
+	ctx, cancel := util.NewDBContextFrom(ctx)
+	defer cancel()
+
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, apperror.ErrInvalidID
+	}
+	if _, err := primitive.ObjectIDFromHex(messageID); err != nil {
+		return nil, apperror.ErrInvalidID
+	}
+
+	message, err := s.messageRepo.GetByID(ctx, messageID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, apperror.ErrChatMessageNotFound
+		}
+		return nil, err
+	}
+
+	// Only the session owner may report, and the session must not be deleted
+	session, err := s.sessionRepo.GetByID(ctx, message.SessionID.Hex())
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, apperror.ErrChatMessageNotFound
+		}
+		return nil, err
+	}
+	if session.UserID != userObjID {
+		return nil, apperror.ErrChatMessageNotFound
+	}
+
+	if message.Role != model.RoleAssistant {
+		return nil, apperror.ErrMessageNotReportable
+	}
+
+	exists, err := s.reportRepo.ExistsByReporter(ctx, message.ID, userObjID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, apperror.ErrAlreadyReported
+	}
+
+	report, err := s.reportRepo.Create(ctx, &model.MessageReport{
+		MessageID:      message.ID,
+		SessionID:      message.SessionID,
+		ReporterID:     userObjID,
+		Reason:         req.Reason,
+		Comment:        req.Comment,
+		MessageContent: message.Content,
+		Status:         model.ReportStatusOpen,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := dto.FromMessageReport(report)
+	return &response, nil
+}
+
+// GetReports returns a page of the moderation queue, oldest first. Defaults to open reports.
+func (s *reportService) GetReports(ctx context.Context, query *dto.GetReportsQuery) (*dto.PaginatedReportsResponse, error) {
+	ctx, cancel := util.NewDBContextFrom(ctx)
+	defer cancel()
+
+	status := query.Status
+	if status == "" {
+		status = model.ReportStatusOpen
+	}
+	filter := repo.Filter{"status": status}
+	if query.Reason != "" {
+		filter["reason"] = query.Reason
+	}
+
+	page := query.Page
+	if page < 1 {
+		page = 1
+	}
+	pageSize := query.PageSize
+	if pageSize < 1 {
+		pageSize = 20
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	reports, total, err := s.reportRepo.Find(ctx, filter, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.PaginatedReportsResponse{
+		Reports: dto.FromMessageReports(reports),
+		Pagination: dto.Pagination{
+			Page:     page,
+			PageSize: pageSize,
+			Total:    total,
+		},
+	}, nil
+}
+
+// ReviewReport resolves or dismisses a report. A reviewed report can be reviewed again to correct a decision.
+func (s *reportService) ReviewReport(ctx context.Context, adminID, reportID string, req *dto.ReviewReportRequest) (*dto.MessageReportResponse, error) {
+	ctx, cancel := util.NewDBContextFrom(ctx)
+	defer cancel()
+
+	adminObjID, err := primitive.ObjectIDFromHex(adminID)
+	if err != nil {
+		return nil, apperror.ErrInvalidID
+	}
+	reportObjID, err := primitive.ObjectIDFromHex(reportID)
+	if err != nil {
+		return nil, apperror.ErrInvalidID
+	}
+
+	if err := s.reportRepo.Review(ctx, reportObjID, req.Status, req.ResolutionNote, adminObjID); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, apperror.ErrReportNotFound
+		}
+		return nil, err
+	}
+
+	report, err := s.reportRepo.GetByID(ctx, reportID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := dto.FromMessageReport(report)
+	return &response, nil
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/email"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/gemini"
+	platformgrpc "github.com/giakiet05/uit-ai-assistant/backend/internal/platform/grpc"
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// healthProbeTimeout bounds each probe so one hanging dependency cannot stall the report
+const healthProbeTimeout = 3 * time.Second
+
+// errProbeDisabled marks a dependency that is not configured
+var errProbeDisabled = errors.New("disabled")
+
+// SystemHealthService probes the external dependencies of the gateway
+type SystemHealthService interface {
+	GetHealth() *dto.SystemHealthResponse
+	GetReadiness() *dto.SystemHealthResponse
+}
+
+// healthProbe checks one dependency
+type healthProbe struct {
+	name  string
+	probe func(ctx context.Context) error
+}
+
+type systemHealthService struct {
+	mongoClient  *mongo.Client
+	redisClient  *redis.Client
+	agentClient  *platformgrpc.AgentClient
+	emailSender  email.Sender
+	geminiClient *gemini.GeminiClient
+}
+
+func NewSystemHealthService(
+	mongoClient *mongo.Client,
+	redisClient *redis.Client,
+	agentClient *platformgrpc.AgentClient,
+	emailSender email.Sender,
+	geminiClient *gemini.GeminiClient,
+) SystemHealthService {
+	return &systemHealthService{
+		mongoClient:  mongoClient,
+		redisClient:  redisClient,
+		agentClient:  agentClient,
+		emailSender:  emailSender,
+		geminiClient: geminiClient,
+	}
+}
+
+// GetHealth runs all probes concurrently and reports each result in a fixed order
+func (s *systemHealthService) GetHealth() *dto.SystemHealthResponse {
+	probes := append(s.requiredProbes(),
+		healthProbe{"email", func(ctx context.Context) error {
+			if err := s.emailSender.Ping(ctx); err != nil {
+				if errors.Is(err, email.ErrNotConfigured) {
+					return errProbeDisabled
+				}
+				return err
+			}
+			return nil
+		}},
+		healthProbe{"gemini", func(ctx context.Context) error {
+			// A nil client means moderation is disabled or failed to initialize
+			if s.geminiClient == nil {
+				return errProbeDisabled
+			}
+			return s.geminiClient.Ping(ctx)
+		}},
+	)
+	return checkHealth(probes)
+}
+
+// GetReadiness probes only the dependencies no request can be served without; email and Gemini are optional
+func (s *systemHealthService) GetReadiness() *dto.SystemHealthResponse {
+	return checkHealth(s.requiredProbes())
+}
+
+func (s *systemHealthService) requiredProbes() []healthProbe {
+	return []healthProbe{
+		{"mongo", func(ctx context.Context) error {
+			return s.mongoClient.Ping(ctx, readpref.Primary())
+		}},
+		{"redis", func(ctx context.Context) error {
+			return s.redisClient.Ping(ctx).Err()
+		}},
+		{"agent", s.agentClient.Ping},
+	}
+}
+
+// checkHealth runs the probes concurrently; the system is degraded if any dependency is down
+func checkHealth(probes []healthProbe) *dto.SystemHealthResponse {
+	dependencies := make([]dto.DependencyHealth, len(probes))
+	var wg sync.WaitGroup
+	for i, p := range probes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			dependencies[i] = runProbe(p.name, p.probe)
+		}()
+	}
+	wg.Wait()
+
+	status := dto.SystemStatusOK
+	for _, d := range dependencies {
+		if d.Status == dto.DependencyStatusDown {
+			status = dto.SystemStatusDegraded
+			break
+		}
+	}
+
+	return &dto.SystemHealthResponse{
+		Status:       status,
+		Dependencies: dependencies,
+		CheckedAt:    time.Now(),
+	}
+}
+
+func runProbe(name string, probe func(ctx context.Context) error) dto.DependencyHealth {
+	ctx, cancel := context.WithTimeout(context.Background(), healthProbeTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := probe(ctx)
+	latency := time.Since(start).Milliseconds()
+
+	switch {
+	case errors.Is(err, errProbeDisabled):
+		return dto.DependencyHealth{Name: name, Status: dto.DependencyStatusDisabled}
+	case err != nil:
+		return dto.DependencyHealth{Name: name, Status: dto.DependencyStatusDown, LatencyMs: latency, Error: err.Error()}
+	default:
+		return dto.DependencyHealth{Name: name, Status: dto.DependencyStatusUp, LatencyMs: latency}
+	}
+}
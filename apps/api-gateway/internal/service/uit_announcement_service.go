@@ -0,0 +1,249 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/bus"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/uit"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/repo"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/util"
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// UITAnnouncementService crawls official UIT announcement pages, notifies the students each new notice concerns
+// and keeps the latest notices in Redis as fresh context for the agent
+type UITAnnouncementService interface {
+	Start(workers *Workers)
+	GetAnnouncements(ctx context.Context, page, pageSize int) (*dto.PaginatedUITAnnouncementsResponse, error)
+}
+
+type uitAnnouncementService struct {
+	announcementRepo    repo.UITAnnouncementRepo
+	userRepo            repo.UserRepo
+	notificationService NotificationService
+	eventBus            bus.EventBus
+	client              *uit.AnnouncementClient
+	redisClient         *redis.Client
+	cfg                 *config.UITConfig
+}
+
+func NewUITAnnouncementService(announcementRepo repo.UITAnnouncementRepo, userRepo repo.UserRepo, notificationService NotificationService, eventBus bus.EventBus, client *uit.AnnouncementClient, redisClient *redis.Client, cfg *config.UITConfig) UITAnnouncementService {
+	return &uitAnnouncementService{
+		announcementRepo:    announcementRepo,
+		userRepo:            userRepo,
+		notificationService: notificationService,
+		eventBus:            eventBus,
+		client:              client,
+		redisClient:         redisClient,
+		cfg:                 cfg,
+	}
+}
+
+// Start launches the crawl loop, crawling once right away so the agent has context after a restart
+func (s *uitAnnouncementService) Start(workers *Workers) {
+	if s.cfg.AnnouncementPollMinutes <= 0 || len(s.cfg.AnnouncementPages) == 0 {
+		slog.Info("UITAnnouncementService disabled")
+		return
+	}
+
+	workers.RunEvery(time.Duration(s.cfg.AnnouncementPollMinutes)*time.Minute, func(context.Context) {
+		s.crawl()
+	})
+
+	slog.Info("UITAnnouncementService started", "pages", len(s.cfg.AnnouncementPages), "poll_minutes", s.cfg.AnnouncementPollMinutes)
+}
+
+func (s *uitAnnouncementService) GetAnnouncements(ctx context.Context, page, pageSize int) (*dto.PaginatedUITAnnouncementsResponse, error) {
+	ctx, cancel := util.NewDBContextFrom(ctx)
+	defer cancel()
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	announcements, total, err := s.announcementRepo.Find(ctx, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.PaginatedUITAnnouncementsResponse{
+		Announcements: announcements,
+		Pagination: dto.Pagination{
+			Page:     page,
+			PageSize: pageSize,
+			Total:    total,
+		},
+	}, nil
+}
+
+// crawl stores the notices not seen before and notifies their students. The very first crawl only fills the
+// collection, so the notices already listed are not announced as new.
+func (s *uitAnnouncementService) crawl() {
+	ctx, cancel := util.NewDefaultDBContext()
+	stored, err := s.announcementRepo.Count(ctx)
+	cancel()
+	if err != nil {
+		slog.Error("UIT announcements: failed to count stored announcements", "error", err)
+		return
+	}
+	seeding := stored == 0
+
+	var fresh []*model.UITAnnouncement
+	for _, pageURL := range s.cfg.AnnouncementPages {
+		fresh = append(fresh, s.crawlPage(pageURL)...)
+	}
+
+	notifyAfter := time.Now().AddDate(0, 0, -s.cfg.AnnouncementNotifyMaxAgeDays)
+	for _, announcement := range fresh {
+		if seeding || (announcement.PublishedAt != nil && announcement.PublishedAt.Before(notifyAfter)) {
+			continue
+		}
+		s.deliver(announcement)
+	}
+
+	if len(fresh) > 0 {
+		slog.Info("UIT announcements: stored new announcements", "count", len(fresh))
+	}
+	s.refreshAgentContext()
+}
+
+// crawlPage stores the new notices of one page; a page that fails is retried on the next crawl
+func (s *uitAnnouncementService) crawlPage(pageURL string) []*model.UITAnnouncement {
+	fetchCtx, cancelFetch := context.WithTimeout(context.Background(), time.Duration(s.cfg.TimeoutSeconds)*time.Second)
+	announcements, err := s.client.GetAnnouncements(fetchCtx, pageURL)
+	cancelFetch()
+	if err != nil {
+		slog.Error("UIT announcements: failed to crawl", "url", pageURL, "error", err)
+		return nil
+	}
+
+	ctx, cancel := util.NewDefaultDBContext()
+	defer cancel()
+
+	var fresh []*model.UITAnnouncement
+	for i := range announcements {
+		announcement := &announcements[i]
+		announcement.Hash = announcementHash(announcement)
+
+		inserted, err := s.announcementRepo.Insert(ctx, announcement)
+		if err != nil {
+			slog.Error("UIT announcements: failed to store", "url", announcement.URL, "error", err)
+			continue
+		}
+		if inserted {
+			fresh = append(fresh, announcement)
+		}
+	}
+	return fresh
+}
+
+// deliver notifies the active users an announcement concerns, batch by batch: the students of the cohorts its
+// title names, or everyone when it names none
+func (s *uitAnnouncementService) deliver(announcement *model.UITAnnouncement) {
+	filter := repo.Filter{
+		"is_active":  true,
+		"deleted_at": bson.M{"$exists": false},
+	}
+	if len(announcement.Cohorts) > 0 {
+		years := make([]int, len(announcement.Cohorts))
+		for i, cohort := range announcement.Cohorts {
+			years[i] = model.FirstEnrollmentYear + cohort - 1
+		}
+		filter["student.enrollment_year"] = bson.M{"$in": years}
+	}
+
+	message := "Thông báo mới từ " + announcement.Source + ": " + announcement.Title
+	metadata := map[string]interface{}{"uit_announcement_id": announcement.ID.Hex()}
+
+	var delivered int64
+	for skip := int64(0); ; skip += announcementBatchSize {
+		ctx, cancel := util.NewDefaultDBContext()
+		users, _, err := s.userRepo.Find(ctx, filter, &repo.FindOptions{
+			Sort:  bson.D{{Key: "_id", Value: 1}},
+			Skip:  skip,
+			Limit: announcementBatchSize,
+		})
+		cancel()
+		if err != nil {
+			slog.Error("UIT announcements: failed to load recipients", "url", announcement.URL, "error", err)
+			break
+		}
+		if len(users) == 0 {
+			break
+		}
+
+		notifications, err := s.notificationService.CreateBulkNotifications(ctx, users, model.NotificationTypeUITAnnouncement, message, announcement.URL, nil, metadata)
+		if err != nil {
+			slog.Error("UIT announcements: failed to notify", "url", announcement.URL, "error", err)
+			break
+		}
+		delivered += int64(len(notifications))
+
+		// Reuses the announcement fan-out so connected clients receive the notifications live, without a banner
+		event := bus.AnnouncementSentEvent{
+			Notifications: make(map[string]dto.NotificationResponse, len(notifications)),
+		}
+		for _, n := range notifications {
+			event.Notifications[n.RecipientID.Hex()] = dto.FromNotification(n)
+		}
+		for _, u := range users {
+			event.RecipientIDs = append(event.RecipientIDs, u.ID.Hex())
+		}
+		s.eventBus.Publish(event)
+
+		if len(users) < announcementBatchSize {
+			break
+		}
+	}
+
+	ctx, cancel := util.NewDefaultDBContext()
+	defer cancel()
+	if err := s.announcementRepo.SetNotifiedCount(ctx, announcement.ID, delivered); err != nil {
+		slog.Error("UIT announcements: failed to save notified count", "url", announcement.URL, "error", err)
+	}
+}
+
+// refreshAgentContext caches the latest notices for the agent's announcement tool. The key has no TTL: stale
+// notices beat none when a crawl fails.
+func (s *uitAnnouncementService) refreshAgentContext() {
+	ctx, cancel := util.NewDefaultDBContext()
+	announcements, _, err := s.announcementRepo.Find(ctx, 1, s.cfg.AnnouncementAgentContextLimit)
+	cancel()
+	if err != nil {
+		slog.Error("UIT announcements: failed to load the latest announcements", "error", err)
+		return
+	}
+
+	data, err := json.Marshal(map[string]interface{}{
+		"announcements": announcements,
+		"updated_at":    time.Now(),
+	})
+	if err != nil {
+		return
+	}
+
+	redisCtx, cancelRedis := util.NewDefaultRedisContext()
+	defer cancelRedis()
+	if err := s.redisClient.Set(redisCtx, config.RedisUITAnnouncementsKey, data, 0).Err(); err != nil {
+		slog.Error("UIT announcements: failed to cache the latest announcements", "error", err)
+	}
+}
+
+// announcementHash identifies a notice by its site and title, so one re-posted under a new URL is not stored twice
+func announcementHash(announcement *model.UITAnnouncement) string {
+	sum := sha256.Sum256([]byte(announcement.Source + "|" + strings.ToLower(announcement.Title)))
+	return hex.EncodeToString(sum[:])
+}
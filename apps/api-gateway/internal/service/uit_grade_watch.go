@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/util"
+)
+
+// gradeExempt is the snapshot value of an exempt course
+const gradeExempt = "miễn"
+
+// detectGradeChanges fetches the grade table of every student with a synced DAA cookie, compares it to the
+// snapshot of the previous run and notifies each course whose grade was posted or changed since
+func (s *uitService) detectGradeChanges() {
+	users, err := s.syncedUsers(model.NotificationTypeGradePosted)
+	if err != nil {
+		slog.Error("UIT grades: failed to load synced users", "error", err)
+		return
+	}
+
+	notified := 0
+	for _, user := range users {
+		n, err := s.detectUserGradeChanges(user.ID.Hex())
+		if err != nil {
+			slog.Error("UIT grades: failed to check grades", "user_id", user.ID.Hex(), "error", err)
+			continue
+		}
+		notified += n
+	}
+
+	if notified > 0 {
+		slog.Info("UIT grades: notified grade changes", "count", notified)
+	}
+}
+
+// detectUserGradeChanges diffs the user's grades against their snapshot and returns the number of notifications
+// sent. The first run only records the snapshot, so existing grades are not announced as new.
+func (s *uitService) detectUserGradeChanges(userID string) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(s.cfg.TimeoutSeconds)*time.Second)
+	defer cancel()
+
+	cookie, err := s.cookieService.GetCookie(ctx, userID, daaCookieSource)
+	if err != nil {
+		return 0, err
+	}
+
+	grades, err := s.daaClient.GetGrades(ctx, cookie)
+	if err != nil {
+		return 0, s.portalError(err, daaCookieSource, "grades", userID)
+	}
+
+	current := gradeSnapshot(grades)
+	if len(current) == 0 {
+		return 0, nil // Never replace a snapshot with an empty table, DAA sometimes renders none
+	}
+
+	redisCtx, cancelRedis := util.NewDefaultRedisContext()
+	defer cancelRedis()
+
+	key := fmt.Sprintf(config.RedisUITGradeSnapshotKey, userID)
+	previous, err := s.redisClient.HGetAll(redisCtx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	pipe := s.redisClient.TxPipeline()
+	pipe.Del(redisCtx, key)
+	pipe.HSet(redisCtx, key, current)
+	if _, err := pipe.Exec(redisCtx); err != nil {
+		return 0, err
+	}
+
+	if len(previous) == 0 {
+		return 0, nil
+	}
+
+	notified := 0
+	for _, course := range grades.Courses {
+		field := gradeSnapshotField(course)
+		grade := current[field].(string)
+		if grade == "" || grade == previous[field] {
+			continue
+		}
+
+		message := fmt.Sprintf("Đã có điểm học phần %s - %s: %s", course.CourseCode, course.CourseName, grade)
+		if old := previous[field]; old != "" {
+			message = fmt.Sprintf("Điểm học phần %s - %s đã được cập nhật: %s → %s", course.CourseCode, course.CourseName, old, grade)
+		}
+
+		if _, err := s.notificationService.CreateNotification(ctx, userID, model.NotificationTypeGradePosted, message, "", nil); err != nil {
+			slog.Error("UIT grades: failed to notify user", "user_id", userID, "course", course.CourseCode, "error", err)
+			continue
+		}
+		notified++
+	}
+	return notified, nil
+}
+
+// gradeSnapshot maps each course to its displayed grade, empty while the grade is not posted
+func gradeSnapshot(grades *model.UITGrades) map[string]interface{} {
+	snapshot := make(map[string]interface{}, len(grades.Courses))
+	for _, course := range grades.Courses {
+		grade := ""
+		switch {
+		case course.IsExempt:
+			grade = gradeExempt
+		case course.AverageScore != nil:
+			grade = strconv.FormatFloat(*course.AverageScore, 'f', -1, 64)
+		}
+		snapshot[gradeSnapshotField(course)] = grade
+	}
+	return snapshot
+}
+
+// gradeSnapshotField identifies a course in the snapshot; a retaken course is a new field in a later semester
+func gradeSnapshotField(course model.UITCourseGrade) string {
+	return course.Semester + "|" + course.CourseCode
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/uit"
+)
+
+// GetOpenClasses returns the classes open for registration matching the query, from a short-lived cache or
+// fetched from the courses portal
+func (s *uitService) GetOpenClasses(ctx context.Context, userID string, query *dto.OpenClassesQuery) (*dto.UITOpenClassesResponse, error) {
+	classes, cached, err := s.loadOpenClasses(ctx, userID, query.Refresh)
+	if err != nil {
+		return nil, err
+	}
+
+	subject := strings.ToLower(strings.TrimSpace(query.Subject))
+	matching := make([]model.UITOpenClass, 0, len(classes.Classes))
+	for _, class := range classes.Classes {
+		if query.Available && class.Remaining == 0 {
+			continue
+		}
+		if subject != "" && !strings.HasPrefix(strings.ToLower(class.ClassCode), subject) && !strings.Contains(strings.ToLower(class.Subject), subject) {
+			continue
+		}
+		matching = append(matching, class)
+	}
+
+	return &dto.UITOpenClassesResponse{
+		Classes:   matching,
+		FetchedAt: classes.FetchedAt,
+		Cached:    cached,
+	}, nil
+}
+
+// CheckConflicts checks each planned class against the user's current timetable and the planned classes before
+// it, and reports the planned classes that are full or not open
+func (s *uitService) CheckConflicts(ctx context.Context, userID string, req *dto.CheckConflictsRequest) (*dto.UITConflictCheckResponse, error) {
+	classes, _, err := s.loadOpenClasses(ctx, userID, false)
+	if err != nil {
+		return nil, err
+	}
+	schedule, _, err := s.loadSchedule(ctx, userID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	open := make(map[string]model.UITOpenClass, len(classes.Classes))
+	for _, class := range classes.Classes {
+		open[strings.ToUpper(class.ClassCode)] = class
+	}
+
+	result := &dto.UITConflictCheckResponse{
+		Conflicts: []dto.UITClassConflict{},
+		Full:      []string{},
+		Unknown:   []string{},
+	}
+
+	var planned []model.UITOpenClass
+	for _, code := range req.ClassCodes {
+		class, ok := open[strings.ToUpper(strings.TrimSpace(code))]
+		if !ok {
+			result.Unknown = append(result.Unknown, code)
+			continue
+		}
+		if class.Remaining == 0 {
+			result.Full = append(result.Full, class.ClassCode)
+		}
+
+		for _, current := range schedule.Classes {
+			if uit.SlotsOverlap(class.DayOfWeek, class.Period, current.DayOfWeek, current.Period) {
+				result.Conflicts = append(result.Conflicts, classConflict(class, current.SubjectCode, true))
+			}
+		}
+		for _, other := range planned {
+			if uit.SlotsOverlap(class.DayOfWeek, class.Period, other.DayOfWeek, other.Period) {
+				result.Conflicts = append(result.Conflicts, classConflict(class, other.ClassCode, false))
+			}
+		}
+		planned = append(planned, class)
+	}
+
+	return result, nil
+}
+
+func classConflict(class model.UITOpenClass, with string, inTimetable bool) dto.UITClassConflict {
+	return dto.UITClassConflict{
+		ClassCode:     class.ClassCode,
+		ConflictsWith: with,
+		InTimetable:   inTimetable,
+		DayOfWeek:     class.DayOfWeek,
+		Period:        class.Period,
+	}
+}
+
+// loadOpenClasses returns the open classes from cache unless refresh is set, otherwise fetches and caches them.
+// cached reports whether they came from cache.
+func (s *uitService) loadOpenClasses(ctx context.Context, userID string, refresh bool) (classes *model.UITOpenClasses, cached bool, err error) {
+	key := fmt.Sprintf(config.RedisUITOpenClassesKey, userID)
+
+	if !refresh {
+		var cachedClasses model.UITOpenClasses
+		if s.readCache(key, &cachedClasses) {
+			return &cachedClasses, true, nil
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(s.cfg.TimeoutSeconds)*time.Second)
+	defer cancel()
+
+	cookie, err := s.cookieService.GetCookie(ctx, userID, coursesCookieSource)
+	if err != nil {
+		return nil, false, err
+	}
+
+	classes, err = s.coursesClient.GetOpenClasses(ctx, cookie)
+	if err != nil {
+		return nil, false, s.portalError(err, coursesCookieSource, "open classes", userID)
+	}
+
+	s.writeCache(key, classes, s.cfg.OpenClassesCacheMinutes)
+	return classes, false, nil
+}
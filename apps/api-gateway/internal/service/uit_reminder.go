@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/apperror"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/uit"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/util"
+)
+
+// scheduleReminders scans the exams and tuition of every student with a synced DAA cookie and schedules a
+// deadline reminder for each upcoming exam and unpaid tuition deadline not reminded yet. Only synced students
+// are scanned since their data cannot be fetched without their cookie.
+func (s *uitService) scheduleReminders() {
+	users, err := s.syncedUsers(model.NotificationTypeDeadlineReminder)
+	if err != nil {
+		slog.Error("UIT reminders: failed to load synced users", "error", err)
+		return
+	}
+
+	now := time.Now()
+	scheduled := 0
+	for _, user := range users {
+		userID := user.ID.Hex()
+
+		if exams, err := s.loadExams(context.Background(), userID); err != nil {
+			slog.Error("UIT reminders: failed to load exams", "user_id", userID, "error", err)
+		} else {
+			for _, exam := range exams.Exams {
+				if s.scheduleExamReminder(userID, exam, now) {
+					scheduled++
+				}
+			}
+		}
+
+		if tuition, _, err := s.loadTuition(context.Background(), userID, false); err != nil {
+			slog.Error("UIT reminders: failed to load tuition", "user_id", userID, "error", err)
+		} else {
+			for _, term := range tuition.Terms {
+				if s.scheduleTuitionReminder(userID, term, now) {
+					scheduled++
+				}
+			}
+		}
+	}
+
+	if scheduled > 0 {
+		slog.Info("UIT reminders: scheduled reminders", "count", scheduled)
+	}
+}
+
+func (s *uitService) scheduleExamReminder(userID string, exam model.UITExam, now time.Time) bool {
+	date, ok := uit.ExamDate(exam)
+	if !ok {
+		return false
+	}
+
+	code := exam.ClassCode
+	if code == "" {
+		code = exam.CourseCode
+	}
+
+	event := "exam-" + code + "-" + date.Format("20060102")
+	return s.scheduleReminder(userID, event, date, s.cfg.ReminderDaysBefore, examReminderMessage(code, exam), now)
+}
+
+// scheduleTuitionReminder reminds of a term's payment deadline while some of its tuition is unpaid
+func (s *uitService) scheduleTuitionReminder(userID string, term model.UITTuitionTerm, now time.Time) bool {
+	date, ok := uit.TuitionDeadline(term)
+	if !ok || term.Remaining <= 0 {
+		return false
+	}
+
+	event := "tuition-" + date.Format("20060102")
+	message := fmt.Sprintf("Nhắc đóng học phí %s: còn nợ %s đồng, hạn đóng %s", term.Semester, formatVND(term.Remaining), term.Deadline)
+	return s.scheduleReminder(userID, event, date, s.cfg.TuitionReminderDaysBefore, message, now)
+}
+
+// scheduleReminder queues the reminder of an event on date at the configured hour, daysBefore days ahead, in
+// the user's timezone. When that time has already passed but the event has not, it is delivered right away.
+// Each event is reminded once; it reports whether a reminder was scheduled or delivered.
+func (s *uitService) scheduleReminder(userID, event string, date time.Time, daysBefore int, message string, now time.Time) bool {
+	over := date.AddDate(0, 0, 1)
+	if !over.After(now) {
+		return false
+	}
+
+	// Claim the reminder first so a slow run never overlaps the next one
+	key := fmt.Sprintf(config.RedisUITReminderKey, userID, event)
+	ctx, cancel := util.NewDefaultRedisContext()
+	claimed, err := s.redisClient.SetNX(ctx, key, 1, over.Sub(now)).Result()
+	cancel()
+	if err != nil || !claimed {
+		if err != nil {
+			slog.Error("UIT reminders: failed to claim reminder", "key", key, "error", err)
+		}
+		return false
+	}
+
+	localTime := date.AddDate(0, 0, -daysBefore).Format("2006-01-02") + fmt.Sprintf("T%02d:00", s.cfg.ReminderHour)
+
+	_, err = s.notificationService.ScheduleNotification(ctx, []string{userID}, model.NotificationTypeDeadlineReminder, message, "", nil, time.Time{}, localTime, "")
+	if errors.Is(err, apperror.ErrInvalidDeliverAt) {
+		// Found too late to remind ahead of time, e.g. the event was just published
+		_, err = s.notificationService.CreateNotification(ctx, userID, model.NotificationTypeDeadlineReminder, message, "", nil)
+	}
+	if err != nil {
+		slog.Error("UIT reminders: failed to schedule reminder", "key", key, "error", err)
+
+		ctx, cancel := util.NewDefaultRedisContext()
+		defer cancel()
+		s.redisClient.Del(ctx, key) // Retry on the next run
+		return false
+	}
+	return true
+}
+
+func examReminderMessage(code string, exam model.UITExam) string {
+	message := fmt.Sprintf("Nhắc lịch thi: môn %s ngày %s", code, exam.ExamDate)
+	if exam.ExamPeriod != "" {
+		message += ", " + exam.ExamPeriod
+	}
+	if exam.Room != "" {
+		message += ", phòng " + exam.Room
+	}
+	if exam.ExamForm != "" {
+		message += " (" + exam.ExamForm + ")"
+	}
+	return message
+}
+
+// formatVND groups the digits of an amount by thousands, e.g. 12.500.000
+func formatVND(amount int64) string {
+	digits := strconv.FormatInt(amount, 10)
+	var sb strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			sb.WriteByte('.')
+		}
+		sb.WriteRune(d)
+	}
+	return sb.String()
+}
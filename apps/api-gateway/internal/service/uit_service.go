@@ -0,0 +1,389 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/apperror"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/uit"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/repo"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/util"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Cookie sources of the UIT portals read by UITService
+const (
+	daaCookieSource     = "daa"
+	drlCookieSource     = "drl"
+	coursesCookieSource = "courses"
+)
+
+// UITService reads a student's data from UIT portals server-side, using the cookies synced by the extension,
+// and notifies students of their upcoming exams and newly posted grades
+type UITService interface {
+	Start(workers *Workers)
+	GetSchedule(ctx context.Context, userID string, refresh bool) (*dto.UITScheduleResponse, error)
+	GetTrainingScore(ctx context.Context, userID string, refresh bool) (*dto.UITTrainingScoreResponse, error)
+	GetTuition(ctx context.Context, userID string, refresh bool) (*dto.UITTuitionResponse, error)
+	GetOpenClasses(ctx context.Context, userID string, query *dto.OpenClassesQuery) (*dto.UITOpenClassesResponse, error)
+	CheckConflicts(ctx context.Context, userID string, req *dto.CheckConflictsRequest) (*dto.UITConflictCheckResponse, error)
+	GetCalendarFeed(ctx context.Context, userID string) (*dto.UITCalendarFeedResponse, error)
+	RegenerateCalendarFeed(ctx context.Context, userID string) (*dto.UITCalendarFeedResponse, error)
+	GetCalendar(ctx context.Context, userID, signature string) ([]byte, error)
+}
+
+type uitService struct {
+	cookieService       CookieService
+	notificationService NotificationService
+	userRepo            repo.UserRepo
+	daaClient           *uit.DAAClient
+	drlClient           *uit.DRLClient
+	coursesClient       *uit.CoursesClient
+	redisClient         *redis.Client
+	cfg                 *config.UITConfig
+}
+
+func NewUITService(cookieService CookieService, notificationService NotificationService, userRepo repo.UserRepo, daaClient *uit.DAAClient, drlClient *uit.DRLClient, coursesClient *uit.CoursesClient, redisClient *redis.Client, cfg *config.UITConfig) UITService {
+	return &uitService{
+		cookieService:       cookieService,
+		notificationService: notificationService,
+		userRepo:            userRepo,
+		daaClient:           daaClient,
+		drlClient:           drlClient,
+		coursesClient:       coursesClient,
+		redisClient:         redisClient,
+		cfg:                 cfg,
+	}
+}
+
+// Start launches the background jobs reading synced students' DAA data: exam and tuition reminders, and grade
+// change detection
+func (s *uitService) Start(workers *Workers) {
+	if s.cfg.ReminderEnabled {
+		workers.RunEvery(time.Duration(s.cfg.ReminderIntervalMinutes)*time.Minute, func(context.Context) {
+			s.scheduleReminders()
+		})
+	} else {
+		slog.Info("UIT reminders are disabled")
+	}
+
+	if s.cfg.GradeCheckIntervalMinutes > 0 {
+		workers.RunEvery(time.Duration(s.cfg.GradeCheckIntervalMinutes)*time.Minute, func(context.Context) {
+			s.detectGradeChanges()
+		})
+	} else {
+		slog.Info("UIT grade change detection is disabled")
+	}
+
+	slog.Info("UITService started")
+}
+
+// syncedUsers returns the active users with a synced DAA cookie who receive notifications of notifType through
+// at least one channel. Only they can be served by the jobs, which need the cookie to read DAA.
+func (s *uitService) syncedUsers(notifType model.NotificationType) ([]*model.User, error) {
+	redisCtx, cancelRedis := util.NewRedisContextWith(time.Minute)
+	userIDs, err := s.cookieService.SyncedUserIDs(redisCtx, daaCookieSource)
+	cancelRedis()
+	if err != nil || len(userIDs) == 0 {
+		return nil, err
+	}
+
+	ctx, cancel := util.NewDefaultDBContext()
+	defer cancel()
+
+	users, err := s.userRepo.GetByIDs(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	active := users[:0]
+	for _, user := range users {
+		pref := user.Settings.NotificationPreference(notifType)
+		if user.DeletedAt == nil && (pref.InApp || pref.Email) {
+			active = append(active, user)
+		}
+	}
+	return active, nil
+}
+
+// GetSchedule returns the user's timetable from cache, or fetches it from DAA when it is not cached or refresh is set
+func (s *uitService) GetSchedule(ctx context.Context, userID string, refresh bool) (*dto.UITScheduleResponse, error) {
+	schedule, cached, err := s.loadSchedule(ctx, userID, refresh)
+	if err != nil {
+		return nil, err
+	}
+	return dto.FromUITSchedule(schedule, cached), nil
+}
+
+// GetTrainingScore returns the user's training score per term from cache, or fetches it from DRL when it is not
+// cached or refresh is set
+func (s *uitService) GetTrainingScore(ctx context.Context, userID string, refresh bool) (*dto.UITTrainingScoreResponse, error) {
+	key := fmt.Sprintf(config.RedisUITTrainingScoreKey, userID)
+
+	if !refresh {
+		var score model.UITTrainingScore
+		if s.readCache(key, &score) {
+			return dto.FromUITTrainingScore(&score, true), nil
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(s.cfg.TimeoutSeconds)*time.Second)
+	defer cancel()
+
+	cookie, err := s.cookieService.GetCookie(ctx, userID, drlCookieSource)
+	if err != nil {
+		return nil, err
+	}
+
+	score, err := s.drlClient.GetTrainingScore(ctx, cookie)
+	if err != nil {
+		return nil, s.portalError(err, drlCookieSource, "training score", userID)
+	}
+
+	s.writeCache(key, score, s.cfg.DRLCacheMinutes)
+	return dto.FromUITTrainingScore(score, false), nil
+}
+
+// GetTuition returns the user's tuition status from cache, or fetches it from DAA when it is not cached or refresh is set
+func (s *uitService) GetTuition(ctx context.Context, userID string, refresh bool) (*dto.UITTuitionResponse, error) {
+	tuition, cached, err := s.loadTuition(ctx, userID, refresh)
+	if err != nil {
+		return nil, err
+	}
+	return dto.FromUITTuition(tuition, cached), nil
+}
+
+// GetCalendarFeed returns the user's signed iCalendar feed URL to subscribe to from a calendar app.
+// The user's calendar token is created the first time.
+func (s *uitService) GetCalendarFeed(ctx context.Context, userID string) (*dto.UITCalendarFeedResponse, error) {
+	ctx, cancel := util.NewDBContextFrom(ctx)
+	defer cancel()
+
+	token, err := s.userRepo.EnsureCalendarToken(ctx, userID, uuid.NewString())
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, apperror.ErrUserNotFound
+		}
+		return nil, err
+	}
+	return &dto.UITCalendarFeedResponse{URL: calendarURL(userID, token)}, nil
+}
+
+// RegenerateCalendarFeed replaces the user's calendar token and returns the new feed URL.
+// Calendar apps subscribed to the old URL stop receiving updates.
+func (s *uitService) RegenerateCalendarFeed(ctx context.Context, userID string) (*dto.UITCalendarFeedResponse, error) {
+	ctx, cancel := util.NewDBContextFrom(ctx)
+	defer cancel()
+
+	token := uuid.NewString()
+	if err := s.userRepo.SetCalendarToken(ctx, userID, token); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, apperror.ErrUserNotFound
+		}
+		return nil, err
+	}
+	return &dto.UITCalendarFeedResponse{URL: calendarURL(userID, token)}, nil
+}
+
+// GetCalendar renders the user's timetable and exams as an iCalendar feed, authorized by the signature of the
+// feed URL. Exams are best effort: the feed still has the classes when they cannot be fetched.
+func (s *uitService) GetCalendar(ctx context.Context, userID, signature string) ([]byte, error) {
+	if err := s.checkCalendarSignature(ctx, userID, signature); err != nil {
+		return nil, err
+	}
+
+	schedule, _, err := s.loadSchedule(ctx, userID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	exams, err := s.loadExams(ctx, userID)
+	if err != nil {
+		slog.Warn("UIT: calendar built without exams", "user_id", userID, "error", err)
+	}
+
+	return uit.BuildCalendar(userID, schedule, exams, time.Now()), nil
+}
+
+// loadSchedule returns the user's timetable from cache unless refresh is set, otherwise fetches and caches it.
+// cached reports whether it came from cache.
+func (s *uitService) loadSchedule(ctx context.Context, userID string, refresh bool) (schedule *model.UITSchedule, cached bool, err error) {
+	key := fmt.Sprintf(config.RedisUITScheduleKey, userID)
+
+	if !refresh {
+		var cachedSchedule model.UITSchedule
+		if s.readCache(key, &cachedSchedule) {
+			return &cachedSchedule, true, nil
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(s.cfg.TimeoutSeconds)*time.Second)
+	defer cancel()
+
+	cookie, err := s.cookieService.GetCookie(ctx, userID, daaCookieSource)
+	if err != nil {
+		return nil, false, err
+	}
+
+	schedule, err = s.daaClient.GetSchedule(ctx, cookie)
+	if err != nil {
+		return nil, false, s.portalError(err, daaCookieSource, "schedule", userID)
+	}
+
+	s.writeCache(key, schedule, s.cfg.ScheduleCacheMinutes)
+	return schedule, false, nil
+}
+
+// loadExams returns the user's exam schedule from cache, or fetches and caches it
+func (s *uitService) loadExams(ctx context.Context, userID string) (*model.UITExamSchedule, error) {
+	key := fmt.Sprintf(config.RedisUITExamsKey, userID)
+
+	var exams model.UITExamSchedule
+	if s.readCache(key, &exams) {
+		return &exams, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(s.cfg.TimeoutSeconds)*time.Second)
+	defer cancel()
+
+	cookie, err := s.cookieService.GetCookie(ctx, userID, daaCookieSource)
+	if err != nil {
+		return nil, err
+	}
+
+	fetched, err := s.daaClient.GetExams(ctx, cookie)
+	if err != nil {
+		return nil, s.portalError(err, daaCookieSource, "exams", userID)
+	}
+
+	s.writeCache(key, fetched, s.cfg.ScheduleCacheMinutes)
+	return fetched, nil
+}
+
+// loadTuition returns the user's tuition status from cache unless refresh is set, otherwise fetches and caches it.
+// cached reports whether it came from cache.
+func (s *uitService) loadTuition(ctx context.Context, userID string, refresh bool) (tuition *model.UITTuition, cached bool, err error) {
+	key := fmt.Sprintf(config.RedisUITTuitionKey, userID)
+
+	if !refresh {
+		var cachedTuition model.UITTuition
+		if s.readCache(key, &cachedTuition) {
+			return &cachedTuition, true, nil
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(s.cfg.TimeoutSeconds)*time.Second)
+	defer cancel()
+
+	cookie, err := s.cookieService.GetCookie(ctx, userID, daaCookieSource)
+	if err != nil {
+		return nil, false, err
+	}
+
+	tuition, err = s.daaClient.GetTuition(ctx, cookie)
+	if err != nil {
+		return nil, false, s.portalError(err, daaCookieSource, "tuition", userID)
+	}
+
+	s.writeCache(key, tuition, s.cfg.TuitionCacheMinutes)
+	return tuition, false, nil
+}
+
+// checkCalendarSignature checks a feed URL signature against the user's current calendar token
+func (s *uitService) checkCalendarSignature(ctx context.Context, userID, signature string) error {
+	ctx, cancel := util.NewDBContextFrom(ctx)
+	defer cancel()
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) || errors.Is(err, apperror.ErrInvalidID) {
+			return apperror.ErrCalendarLinkInvalid
+		}
+		return err
+	}
+	if user.CalendarToken == "" || !hmac.Equal([]byte(signature), []byte(signCalendar(userID, user.CalendarToken))) {
+		return apperror.ErrCalendarLinkInvalid
+	}
+	return nil
+}
+
+// calendarURL returns the signed iCalendar feed link of a user. Calendar apps cannot authenticate, so the
+// signature is the credential; it does not expire so the subscription keeps working, and is revoked by replacing
+// the user's calendar token.
+func calendarURL(userID, token string) string {
+	query := url.Values{}
+	query.Set("signature", signCalendar(userID, token))
+
+	return fmt.Sprintf("%s/uit/calendar/%s/schedule.ics?%s", config.Cfg.APIBaseURL, userID, query.Encode())
+}
+
+// signCalendar signs a user's calendar feed and calendar token with the server secret
+func signCalendar(userID, token string) string {
+	mac := hmac.New(sha256.New, []byte(config.Cfg.JWTSecret))
+	mac.Write([]byte("calendar." + userID + "." + token))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// portalError maps a portal client error to the error returned to the user. A rejected cookie is reported
+// so the user is told to sync it again.
+func (s *uitService) portalError(err error, source, what, userID string) error {
+	if errors.Is(err, uit.ErrSessionExpired) {
+		s.cookieService.ReportExpired(userID, source)
+		return apperror.ErrPortalSessionExpired
+	}
+	slog.Error("UIT: failed to fetch", "what", what, "user_id", userID, "error", err)
+	return apperror.ErrPortalUnavailable
+}
+
+// readCache decodes the cached JSON at key into v, reporting false if there is none or it cannot be read
+func (s *uitService) readCache(key string, v any) bool {
+	ctx, cancel := util.NewDefaultRedisContext()
+	defer cancel()
+
+	data, err := s.redisClient.Get(ctx, key).Bytes()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			slog.Warn("UIT: failed to read cache", "key", key, "error", err)
+		}
+		return false
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		slog.Warn("UIT: failed to decode cache", "key", key, "error", err)
+		return false
+	}
+	return true
+}
+
+// writeCache stores v as JSON at key for the given minutes; caching is disabled when minutes is not positive
+func (s *uitService) writeCache(key string, v any, minutes int) {
+	if minutes <= 0 {
+		return
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		slog.Warn("UIT: failed to encode cache", "key", key, "error", err)
+		return
+	}
+
+	ctx, cancel := util.NewDefaultRedisContext()
+	defer cancel()
+
+	if err := s.redisClient.Set(ctx, key, data, time.Duration(minutes)*time.Minute).Err(); err != nil {
+		slog.Warn("UIT: failed to write cache", "key", key, "error", err)
+	}
+}
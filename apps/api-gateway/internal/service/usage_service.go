@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/apperror"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/repo"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/util"
+)
+
+const (
+	usageMonthLayout   = "2006-01"
+	usageRollupTimeout = 2 * time.Minute
+)
+
+// UsageService tracks agent usage and estimated cost per user per month.
+// A background job rolls usage up from assistant message metadata into the usage collection.
+type UsageService interface {
+	Start(workers *Workers)
+	GetUsage(ctx context.Context, query *dto.GetUsageQuery) (*dto.UsageReportResponse, error)
+}
+
+type usageService struct {
+	usageRepo     repo.UserUsageRepo
+	analyticsRepo repo.ChatAnalyticsRepo
+	userRepo      repo.UserRepo
+	cfg           *config.UsageConfig
+}
+
+func NewUsageService(usageRepo repo.UserUsageRepo, analyticsRepo repo.ChatAnalyticsRepo, userRepo repo.UserRepo, cfg *config.UsageConfig) UsageService {
+	return &usageService{
+		usageRepo:     usageRepo,
+		analyticsRepo: analyticsRepo,
+		userRepo:      userRepo,
+		cfg:           cfg,
+	}
+}
+
+// Start launches the usage rollup job
+func (s *usageService) Start(workers *Workers) {
+	workers.RunEvery(time.Duration(s.cfg.RollupIntervalMinutes)*time.Minute, func(context.Context) {
+		s.rollup()
+	})
+
+	slog.Info("UsageService started")
+}
+
+// rollup recomputes the current month, and the previous one to pick up answers
+// stored after the last run of that month
+func (s *usageService) rollup() {
+	now := time.Now().In(statsLocation())
+	current := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+
+	for _, month := range []time.Time{current.AddDate(0, -1, 0), current} {
+		if err := s.rollupMonth(month); err != nil {
+			slog.Error("Usage: failed to roll up", "month", month.Format(usageMonthLayout), "error", err)
+		}
+	}
+}
+
+func (s *usageService) rollupMonth(start time.Time) error {
+	ctx, cancel := util.NewDBContextWith(usageRollupTimeout)
+	defer cancel()
+
+	rollups, err := s.analyticsRepo.GetUserUsage(ctx, start, start.AddDate(0, 1, 0))
+	if err != nil {
+		return err
+	}
+
+	usage := make([]*model.UserUsage, 0, len(rollups))
+	for _, r := range rollups {
+		usage = append(usage, &model.UserUsage{
+			UserID:        r.UserID,
+			Requests:      r.Requests,
+			TokensUsed:    r.TokensUsed,
+			EstimatedCost: float64(r.TokensUsed) / 1000 * s.cfg.CostPer1KTokens,
+		})
+	}
+
+	return s.usageRepo.ReplaceMonth(ctx, start.Format(usageMonthLayout), usage)
+}
+
+func (s *usageService) GetUsage(ctx context.Context, query *dto.GetUsageQuery) (*dto.UsageReportResponse, error) {
+	ctx, cancel := util.NewDBContextFrom(ctx)
+	defer cancel()
+
+	month := query.Month
+	if month == "" {
+		month = time.Now().In(statsLocation()).Format(usageMonthLayout)
+	} else if _, err := time.Parse(usageMonthLayout, month); err != nil {
+		return nil, apperror.ErrInvalidMonth
+	}
+
+	page := query.Page
+	if page < 1 {
+		page = 1
+	}
+	pageSize := query.PageSize
+	if pageSize < 1 {
+		pageSize = 20
+	}
+
+	usage, total, err := s.usageRepo.Find(ctx, month, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	totals, err := s.usageRepo.GetTotals(ctx, month)
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]string, 0, len(usage))
+	for _, u := range usage {
+		userIDs = append(userIDs, u.UserID.Hex())
+	}
+	users, err := s.userRepo.GetByIDs(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	usersByID := make(map[string]*model.User, len(users))
+	for _, u := range users {
+		usersByID[u.ID.Hex()] = u
+	}
+
+	responses := make([]dto.UserUsageResponse, 0, len(usage))
+	for _, u := range usage {
+		response := dto.UserUsageResponse{
+			UserID:        u.UserID.Hex(),
+			Requests:      u.Requests,
+			TokensUsed:    u.TokensUsed,
+			EstimatedCost: u.EstimatedCost,
+		}
+		if user, ok := usersByID[response.UserID]; ok {
+			response.Username = user.Username
+			response.Email = user.Email
+		}
+		responses = append(responses, response)
+	}
+
+	return &dto.UsageReportResponse{
+		Month:  month,
+		Totals: *totals,
+		Users:  responses,
+		Pagination: dto.Pagination{
+			Page:     page,
+			PageSize: pageSize,
+			Total:    total,
+		},
+	}, nil
+}
+
+func statsLocation() *time.Location {
+	loc, err := time.LoadLocation(repo.StatsTimezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
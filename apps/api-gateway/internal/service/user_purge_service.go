@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/auth"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/cloudinary"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/repo"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/util"
+	"github.com/redis/go-redis/v9"
+)
+
+// purgeBatchSize is the number of deleted users loaded and purged per batch
+const purgeBatchSize = 100
+
+// UserPurgeService permanently erases users and everything linked to them.
+// A background job purges users that have been soft-deleted for longer than the retention period.
+type UserPurgeService interface {
+	Start(workers *Workers)
+	PurgeUser(ctx context.Context, user *model.User) (*dto.UserPurgeReport, error)
+	// PurgeDeletedBefore erases the users soft-deleted before the cutoff and returns how many were erased
+	// It stops between users once stop is done.
+	PurgeDeletedBefore(stop context.Context, before time.Time) (int, error)
+}
+
+type userPurgeService struct {
+	userRepo              repo.UserRepo
+	sessionRepo           repo.ChatSessionRepo
+	messageRepo           repo.ChatMessageRepo
+	reportRepo            repo.MessageReportRepo
+	notificationRepo      repo.NotificationRepo
+	scheduledRepo         repo.ScheduledNotificationRepo
+	emailVerificationRepo repo.EmailVerificationRepo
+	usageRepo             repo.UserUsageRepo
+	dataExportRepo        repo.DataExportRepo
+	deliveryRepo          repo.EmailDeliveryRepo
+	cookieStore           repo.CookieStore
+	redisClient           *redis.Client
+	retentionCfg          *config.RetentionConfig
+}
+
+func NewUserPurgeService(
+	userRepo repo.UserRepo,
+	sessionRepo repo.ChatSessionRepo,
+	messageRepo repo.ChatMessageRepo,
+	reportRepo repo.MessageReportRepo,
+	notificationRepo repo.NotificationRepo,
+	scheduledRepo repo.ScheduledNotificationRepo,
+	emailVerificationRepo repo.EmailVerificationRepo,
+	usageRepo repo.UserUsageRepo,
+	dataExportRepo repo.DataExportRepo,
+	deliveryRepo repo.EmailDeliveryRepo,
+	cookieStore repo.CookieStore,
+	redisClient *redis.Client,
+	retentionCfg *config.RetentionConfig,
+) UserPurgeService {
+	return &userPurgeService{
+		userRepo:              userRepo,
+		sessionRepo:           sessionRepo,
+		messageRepo:           messageRepo,
+		reportRepo:            reportRepo,
+		notificationRepo:      notificationRepo,
+		scheduledRepo:         scheduledRepo,
+		emailVerificationRepo: emailVerificationRepo,
+		usageRepo:             usageRepo,
+		dataExportRepo:        dataExportRepo,
+		deliveryRepo:          deliveryRepo,
+		cookieStore:           cookieStore,
+		redisClient:           redisClient,
+		retentionCfg:          retentionCfg,
+	}
+}
+
+func (s *userPurgeService) Start(workers *Workers) {
+	if s.retentionCfg.DeletedUserDays <= 0 {
+		slog.Info("Deleted user purge is disabled")
+		return
+	}
+
+	workers.RunEvery(time.Duration(s.retentionCfg.CleanupIntervalHours)*time.Hour, s.purgeDeletedUsers)
+
+	slog.Info("UserPurgeService started", "purge_after_days", s.retentionCfg.DeletedUserDays)
+}
+
+// purgeDeletedUsers erases users soft-deleted before the retention cutoff
+func (s *userPurgeService) purgeDeletedUsers(stop context.Context) {
+	purged, err := s.PurgeDeletedBefore(stop, time.Now().AddDate(0, 0, -s.retentionCfg.DeletedUserDays))
+	if err != nil {
+		slog.Error("Retention: failed to load deleted users", "error", err)
+	}
+	if purged > 0 {
+		slog.Info("Retention: purged deleted users", "count", purged)
+	}
+}
+
+// PurgeDeletedBefore erases users batch by batch. A user that fails to purge keeps its document
+// and is retried on the next run.
+func (s *userPurgeService) PurgeDeletedBefore(stop context.Context, before time.Time) (int, error) {
+	purged := 0
+
+	for stop.Err() == nil {
+		ctx, cancel := util.NewDefaultDBContext()
+		users, err := s.userRepo.GetDeletedBefore(ctx, before, purgeBatchSize)
+		cancel()
+		if err != nil {
+			return purged, err
+		}
+
+		failed := 0
+		for _, user := range users {
+			if stop.Err() != nil {
+				return purged, nil
+			}
+
+			ctx, cancel := util.NewDefaultDBContext()
+			if _, err := s.PurgeUser(ctx, user); err != nil {
+				slog.Error("Retention: failed to purge user", "user_id", user.ID.Hex(), "error", err)
+				failed++
+			} else {
+				purged++
+			}
+			cancel()
+		}
+
+		// Stop when the batch was the last one, or when nothing in it could be purged
+		// so failing users are not reloaded forever
+		if len(users) < purgeBatchSize || failed == len(users) {
+			return purged, nil
+		}
+	}
+	return purged, nil
+}
+
+// PurgeUser permanently erases the user, their chat data, reports on their chats, notifications and
+// scheduled notifications, pending email verifications, usage records, data exports, campaign email
+// deliveries, synced portal cookies, presence and avatar. The user document is deleted last, so a purge that fails
+// midway can be retried.
+func (s *userPurgeService) PurgeUser(ctx context.Context, user *model.User) (*dto.UserPurgeReport, error) {
+	userID := user.ID.Hex()
+	report := &dto.UserPurgeReport{UserID: userID}
+
+	// Tokens outlive the soft delete, make sure none can be used once the account is gone
+	if auth.TokenSvc != nil {
+		if err := auth.TokenSvc.InvalidateAllUserTokens(ctx, userID); err != nil {
+			return nil, fmt.Errorf("invalidate tokens: %w", err)
+		}
+	}
+
+	sessionIDs, err := s.sessionRepo.GetAllIDsByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("load sessions: %w", err)
+	}
+	if report.MessagesDeleted, err = s.messageRepo.DeleteBySessionIDs(ctx, sessionIDs); err != nil {
+		return nil, fmt.Errorf("delete messages: %w", err)
+	}
+	if report.ReportsDeleted, err = s.reportRepo.DeleteBySessionIDs(ctx, sessionIDs); err != nil {
+		return nil, fmt.Errorf("delete reports: %w", err)
+	}
+	if report.SessionsDeleted, err = s.sessionRepo.DeleteByUserID(ctx, userID); err != nil {
+		return nil, fmt.Errorf("delete sessions: %w", err)
+	}
+
+	if report.NotificationsDeleted, err = s.notificationRepo.DeleteByRecipientID(ctx, userID); err != nil {
+		return nil, fmt.Errorf("delete notifications: %w", err)
+	}
+	// Pending ones would otherwise still fire after the account is gone
+	if report.ScheduledNotificationsDeleted, err = s.scheduledRepo.DeleteByRecipientID(ctx, userID); err != nil {
+		return nil, fmt.Errorf("delete scheduled notifications: %w", err)
+	}
+
+	if err := s.emailVerificationRepo.Delete(ctx, user.Email); err != nil {
+		return nil, fmt.Errorf("delete email verifications: %w", err)
+	}
+	report.EmailVerificationsDeleted = true
+
+	if report.UsageRecordsDeleted, err = s.usageRepo.DeleteByUserID(ctx, userID); err != nil {
+		return nil, fmt.Errorf("delete usage: %w", err)
+	}
+
+	if report.DataExportsDeleted, err = s.dataExportRepo.DeleteByUserID(ctx, userID); err != nil {
+		return nil, fmt.Errorf("delete data exports: %w", err)
+	}
+
+	// Deliveries keep a copy of the email address and username
+	if report.EmailDeliveriesDeleted, err = s.deliveryRepo.DeleteByUserID(ctx, userID); err != nil {
+		return nil, fmt.Errorf("delete email deliveries: %w", err)
+	}
+
+	if report.RedisKeysDeleted, err = s.redisClient.Del(ctx, fmt.Sprintf(config.RedisPresenceKey, userID), fmt.Sprintf(config.RedisUITScheduleKey, userID), fmt.Sprintf(config.RedisUITExamsKey, userID), fmt.Sprintf(config.RedisUITTuitionKey, userID), fmt.Sprintf(config.RedisUITOpenClassesKey, userID), fmt.Sprintf(config.RedisUITGradeSnapshotKey, userID), fmt.Sprintf(config.RedisUITTrainingScoreKey, userID), fmt.Sprintf(config.RedisExtensionHeartbeatKey, userID)).Result(); err != nil {
+		return nil, fmt.Errorf("delete redis keys: %w", err)
+	}
+	cookiesDeleted, err := s.cookieStore.DeleteAll(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("delete portal cookies: %w", err)
+	}
+	report.RedisKeysDeleted += cookiesDeleted
+
+	if user.Avatar != nil && user.Avatar.PublicID != "" {
+		if _, err := cloudinary.Delete(user.Avatar.PublicID); err != nil {
+			return nil, fmt.Errorf("delete avatar: %w", err)
+		}
+		report.AvatarDeleted = true
+	}
+
+	if err := s.userRepo.HardDelete(ctx, userID); err != nil {
+		return nil, fmt.Errorf("delete user: %w", err)
+	}
+
+	return report, nil
+}
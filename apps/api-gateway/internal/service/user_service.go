@@ -1,57 +1,83 @@
 package service
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
+	"image"
+	_ "image/gif" // Register decoders for avatar validation
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/apperror"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/auth"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/dto"
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/model"
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/bus"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/cloudinary"
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/repo"
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/util"
 	"github.com/redis/go-redis/v9"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"golang.org/x/crypto/bcrypt"
 )
 
 // UserService handles business logic related to user management.
 type UserService interface {
-	UpdateUser(userID string, req *dto.UpdateUserRequest) (*dto.UserResponse, error)
-	UpdateAvatar(userID string, imageURL string, publicID string) (*dto.UserResponse, error)
-	DeleteAvatar(userID string) (*dto.UserResponse, error)
-	DeleteUser(id string) error
-	ChangePassword(userID, oldPassword, newPassword string) error
+	UpdateUser(ctx context.Context, userID string, req *dto.UpdateUserRequest) (*dto.UserResponse, error)
+	UploadAvatar(ctx context.Context, userID string, file *multipart.FileHeader) (*dto.UserResponse, error)
+	UpdateAvatar(ctx context.Context, userID string, imageURL string, publicID string) (*dto.UserResponse, error)
+	DeleteAvatar(ctx context.Context, userID string) (*dto.UserResponse, error)
+	DeleteUser(ctx context.Context, id string) error
+	DeactivateAccount(ctx context.Context, userID string) error
+	ChangePassword(ctx context.Context, userID, oldPassword, newPassword string) error
 
-	GetUserByID(id string) (*dto.UserResponse, error)
-	GetUserByUsername(username string, requesterID string) (*dto.UserResponse, error)
-	GetUserByEmail(email string) (*dto.UserResponse, error)
-	GetUsers(query *dto.GetUsersQuery) (*dto.PaginatedUsersResponse, error)
+	GetUserByID(ctx context.Context, id string) (*dto.UserResponse, error)
+	GetUserByUsername(ctx context.Context, username string, requesterID string) (*dto.PublicUserResponse, error)
+	GetUserByEmail(ctx context.Context, email string) (*dto.UserResponse, error)
+	GetUsers(ctx context.Context, query *dto.GetUsersQuery, requesterID string) (*dto.PaginatedPublicUsersResponse, error)
 
-	GetSettings(userID string) (*dto.UserSettingsResponse, error)
-	UpdateSettings(userID string, req *dto.UpdateSettingsRequest) (*dto.UserSettingsResponse, error)
+	GetSettings(ctx context.Context, userID string) (*dto.UserSettingsResponse, error)
+	UpdateSettings(ctx context.Context, userID string, req *dto.UpdateSettingsRequest) (*dto.UserSettingsResponse, error)
 
-	CheckUsernameAvailability(username string) (bool, error)
+	GetBlockedTopics(ctx context.Context, userID string) (*dto.BlockedTopicsResponse, error)
+	AddBlockedTopic(ctx context.Context, userID string, topic string) (*dto.BlockedTopicsResponse, error)
+	RemoveBlockedTopic(ctx context.Context, userID string, topic string) (*dto.BlockedTopicsResponse, error)
+
+	GetProfileCompleteness(ctx context.Context, userID string) (*dto.ProfileCompletenessResponse, error)
+
+	CheckUsernameAvailability(ctx context.Context, username string) (bool, error)
 }
 
 type userService struct {
-	userRepo    repo.UserRepo
-	eventBus    bus.EventBus
-	redisClient *redis.Client
+	userRepo      repo.UserRepo
+	eventBus      bus.EventBus
+	redisClient   *redis.Client
+	cookieService CookieService
 }
 
-func NewUserService(userRepo repo.UserRepo, bus bus.EventBus, redisClient *redis.Client) UserService {
+func NewUserService(userRepo repo.UserRepo, bus bus.EventBus, redisClient *redis.Client, cookieService CookieService) UserService {
 	return &userService{
-		userRepo:    userRepo,
-		eventBus:    bus,
-		redisClient: redisClient,
+		userRepo:      userRepo,
+		eventBus:      bus,
+		redisClient:   redisClient,
+		cookieService: cookieService,
 	}
 }
 
-func (s *userService) UpdateUser(userID string, req *dto.UpdateUserRequest) (*dto.UserResponse, error) {
-	ctx, cancel := util.NewDefaultDBContext()
+func (s *userService) UpdateUser(ctx context.Context, userID string, req *dto.UpdateUserRequest) (*dto.UserResponse, error) {
+	ctx, cancel := util.NewDBContextFrom(ctx)
 	defer cancel()
 
 	user, err := s.userRepo.GetByID(ctx, userID)
@@ -60,13 +86,15 @@ func (s *userService) UpdateUser(userID string, req *dto.UpdateUserRequest) (*dt
 	}
 
 	// Update username if provided
+	previousUsername := user.Username
 	if req.Username != "" {
-		// Check username availability
-		existing, _ := s.userRepo.GetByUsername(ctx, req.Username)
-		if existing != nil && existing.ID != user.ID {
-			return nil, apperror.ErrUsernameExists
+		if err := changeUsername(ctx, s.userRepo, user, req.Username); err != nil {
+			return nil, err
 		}
-		user.Username = req.Username
+	}
+
+	if err := applyStudentProfile(&user.Student, req); err != nil {
+		return nil, err
 	}
 
 	// Update timestamp
@@ -77,12 +105,119 @@ func (s *userService) UpdateUser(userID string, req *dto.UpdateUserRequest) (*dt
 	if err != nil {
 		return nil, err
 	}
+	s.eventBus.Publish(bus.UserUpdatedEvent{UserID: userID, Username: updatedUser.Username, PreviousUsername: previousUsername})
 
 	return dto.FromUser(updatedUser), nil
 }
 
-func (s *userService) UpdateAvatar(userID string, imageURL string, publicID string) (*dto.UserResponse, error) {
-	ctx, cancel := util.NewDefaultDBContext()
+// changeUsername sets a new username on user, failing if another user already has it.
+// Shared by profile updates and admin edits.
+func changeUsername(ctx context.Context, userRepo repo.UserRepo, user *model.User, username string) error {
+	existing, _ := userRepo.GetByUsername(ctx, username)
+	if existing != nil && existing.ID != user.ID {
+		return apperror.ErrUsernameExists
+	}
+	user.Username = username
+	return nil
+}
+
+// studentIDPattern matches a UIT student ID (MSSV), e.g. 22520123
+var studentIDPattern = regexp.MustCompile(`^\d{8}$`)
+
+// applyStudentProfile copies the provided student profile fields of req onto profile after validating them
+func applyStudentProfile(profile *model.StudentProfile, req *dto.UpdateUserRequest) error {
+	if req.StudentID != nil {
+		studentID := strings.TrimSpace(*req.StudentID)
+		if studentID != "" && !studentIDPattern.MatchString(studentID) {
+			return apperror.ErrInvalidStudentID
+		}
+		profile.StudentID = studentID
+	}
+	if req.Faculty != nil {
+		profile.Faculty = strings.TrimSpace(*req.Faculty)
+	}
+	if req.Program != nil {
+		profile.Program = strings.TrimSpace(*req.Program)
+	}
+	if req.EnrollmentYear != nil {
+		year := *req.EnrollmentYear
+		if year != 0 && (year < model.FirstEnrollmentYear || year > time.Now().Year()) {
+			return apperror.ErrInvalidEnrollmentYear
+		}
+		profile.EnrollmentYear = year
+	}
+	return nil
+}
+
+// allowedAvatarTypes are the sniffed content types accepted as avatars
+var allowedAvatarTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+}
+
+// UploadAvatar validates an uploaded avatar, stores a square crop of it and sets it on the user
+func (s *userService) UploadAvatar(ctx context.Context, userID string, file *multipart.FileHeader) (*dto.UserResponse, error) {
+	data, err := readAvatar(file, &config.Cfg.Avatar)
+	if err != nil {
+		return nil, err
+	}
+
+	avatar, err := cloudinary.UploadAvatar(bytes.NewReader(data), config.Cfg.Avatar.OutputSize)
+	if err != nil {
+		return nil, fmt.Errorf("upload avatar: %w", err)
+	}
+
+	user, err := s.UpdateAvatar(ctx, userID, avatar.URL, avatar.PublicID)
+	if err != nil {
+		// The new image is not referenced by anyone
+		cloudinary.DeleteAsync(avatar.PublicID)
+		return nil, err
+	}
+	return user, nil
+}
+
+// readAvatar reads an uploaded avatar after checking its size, type and dimensions.
+// The type is sniffed from the content since the client's Content-Type header cannot be trusted.
+func readAvatar(file *multipart.FileHeader, cfg *config.AvatarConfig) ([]byte, error) {
+	maxBytes := int64(cfg.MaxSizeMB) << 20
+	if file.Size > maxBytes {
+		return nil, apperror.ErrAvatarTooLarge
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	// Read one byte past the limit in case the declared size was wrong
+	data, err := io.ReadAll(io.LimitReader(f, maxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxBytes {
+		return nil, apperror.ErrAvatarTooLarge
+	}
+
+	if !allowedAvatarTypes[http.DetectContentType(data)] {
+		return nil, apperror.ErrInvalidAvatarType
+	}
+
+	imgCfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, apperror.ErrInvalidAvatarType
+	}
+	if imgCfg.Width < cfg.MinDimension || imgCfg.Height < cfg.MinDimension ||
+		imgCfg.Width > cfg.MaxDimension || imgCfg.Height > cfg.MaxDimension {
+		return nil, apperror.ErrInvalidAvatarDimensions
+	}
+
+	return data, nil
+}
+
+func (s *userService) UpdateAvatar(ctx context.Context, userID string, imageURL string, publicID string) (*dto.UserResponse, error) {
+	ctx, cancel := util.NewDBContextFrom(ctx)
 	defer cancel()
 
 	user, err := s.userRepo.GetByID(ctx, userID)
@@ -90,6 +225,8 @@ func (s *userService) UpdateAvatar(userID string, imageURL string, publicID stri
 		return nil, err
 	}
 
+	oldAvatar := user.Avatar
+
 	// Update avatar
 	user.Avatar = &model.Image{
 		URL:      imageURL,
@@ -101,32 +238,91 @@ func (s *userService) UpdateAvatar(userID string, imageURL string, publicID stri
 	if err != nil {
 		return nil, err
 	}
+	s.eventBus.Publish(bus.UserUpdatedEvent{UserID: userID, Username: updatedUser.Username})
+
+	// Free the replaced image only once the new one is saved
+	if oldAvatar != nil && oldAvatar.PublicID != publicID {
+		cloudinary.DeleteAsync(oldAvatar.PublicID)
+	}
 
 	return dto.FromUser(updatedUser), nil
 }
 
-func (s *userService) DeleteAvatar(userID string) (*dto.UserResponse, error) {
-	ctx, cancel := util.NewDefaultDBContext()
+func (s *userService) DeleteAvatar(ctx context.Context, userID string) (*dto.UserResponse, error) {
+	ctx, cancel := util.NewDBContextFrom(ctx)
 	defer cancel()
 
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
 	// Use UpdateAvatarField to properly unset the avatar field
 	updatedUser, err := s.userRepo.UpdateAvatarField(ctx, userID, nil)
 	if err != nil {
 		return nil, err
 	}
+	s.eventBus.Publish(bus.UserUpdatedEvent{UserID: userID, Username: updatedUser.Username})
+
+	if user.Avatar != nil {
+		cloudinary.DeleteAsync(user.Avatar.PublicID)
+	}
 
 	return dto.FromUser(updatedUser), nil
 }
 
-func (s *userService) DeleteUser(id string) error {
-	ctx, cancel := util.NewDefaultDBContext()
+func (s *userService) DeleteUser(ctx context.Context, id string) error {
+	ctx, cancel := util.NewDBContextFrom(ctx)
 	defer cancel()
 
-	return s.userRepo.Delete(ctx, id)
+	user, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.userRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.eventBus.Publish(bus.UserDeletedEvent{UserID: id, Username: user.Username})
+	return nil
 }
 
-func (s *userService) ChangePassword(userID, oldPassword, newPassword string) error {
-	ctx, cancel := util.NewDefaultDBContext()
+// DeactivateAccount hides the user's account and signs them out everywhere until they log in again.
+// Unlike deletion nothing is scheduled for removal.
+func (s *userService) DeactivateAccount(ctx context.Context, userID string) error {
+	ctx, cancel := util.NewDBContextFrom(ctx)
+	defer cancel()
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return apperror.ErrUserNotFound
+		}
+		return err
+	}
+
+	// Tokens survive a ban, so a banned user could otherwise turn the ban into a deactivation
+	if user.IsBanned() {
+		return apperror.ErrUserInactive
+	}
+
+	if err := s.userRepo.Deactivate(ctx, userID, time.Now()); err != nil {
+		return err
+	}
+	s.eventBus.Publish(bus.UserUpdatedEvent{UserID: userID, Username: user.Username})
+
+	// Reject the user's existing tokens and close their WebSocket connections
+	if auth.TokenSvc != nil {
+		if err := auth.TokenSvc.InvalidateAllUserTokens(ctx, userID); err != nil {
+			return err
+		}
+	}
+	s.eventBus.Publish(bus.SessionTerminatedEvent{UserID: userID, Reason: bus.SessionTerminatedDeactivated})
+	return nil
+}
+
+func (s *userService) ChangePassword(ctx context.Context, userID, oldPassword, newPassword string) error {
+	ctx, cancel := util.NewDBContextFrom(ctx)
 	defer cancel()
 
 	user, err := s.userRepo.GetByID(ctx, userID)
@@ -147,12 +343,15 @@ func (s *userService) ChangePassword(userID, oldPassword, newPassword string) er
 
 	user.Password = string(hashedPassword)
 	user.UpdatedAt = time.Now()
-	_, err = s.userRepo.Update(ctx, user)
-	return err
+	if _, err = s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+	s.eventBus.Publish(bus.UserUpdatedEvent{UserID: userID, Username: user.Username})
+	return nil
 }
 
-func (s *userService) GetUserByID(id string) (*dto.UserResponse, error) {
-	ctx, cancel := util.NewDefaultDBContext()
+func (s *userService) GetUserByID(ctx context.Context, id string) (*dto.UserResponse, error) {
+	ctx, cancel := util.NewDBContextFrom(ctx)
 	defer cancel()
 
 	user, err := s.userRepo.GetByID(ctx, id)
@@ -163,20 +362,27 @@ func (s *userService) GetUserByID(id string) (*dto.UserResponse, error) {
 	return dto.FromUser(user), nil
 }
 
-func (s *userService) GetUserByUsername(username string, requesterID string) (*dto.UserResponse, error) {
-	ctx, cancel := util.NewDefaultDBContext()
+func (s *userService) GetUserByUsername(ctx context.Context, username string, requesterID string) (*dto.PublicUserResponse, error) {
+	ctx, cancel := util.NewDBContextFrom(ctx)
 	defer cancel()
 
 	user, err := s.userRepo.GetByUsername(ctx, username)
 	if err != nil {
 		return nil, err
 	}
+	// Deactivated accounts are hidden from other users
+	if user.DeactivatedAt != nil {
+		return nil, apperror.ErrUserNotFound
+	}
+	if !user.CanViewProfile(requesterID) {
+		return nil, apperror.ErrProfilePrivate
+	}
 
-	return dto.FromUser(user), nil
+	return dto.FromPublicUser(user), nil
 }
 
-func (s *userService) GetUserByEmail(email string) (*dto.UserResponse, error) {
-	ctx, cancel := util.NewDefaultDBContext()
+func (s *userService) GetUserByEmail(ctx context.Context, email string) (*dto.UserResponse, error) {
+	ctx, cancel := util.NewDBContextFrom(ctx)
 	defer cancel()
 
 	user, err := s.userRepo.GetByEmail(ctx, email)
@@ -187,13 +393,27 @@ func (s *userService) GetUserByEmail(email string) (*dto.UserResponse, error) {
 	return dto.FromUser(user), nil
 }
 
-func (s *userService) GetUsers(query *dto.GetUsersQuery) (*dto.PaginatedUsersResponse, error) {
-	ctx, cancel := util.NewDefaultDBContext()
+// GetUsers lists the profiles the requester may view, see User.CanViewProfile. requesterID is empty for
+// unauthenticated requests.
+func (s *userService) GetUsers(ctx context.Context, query *dto.GetUsersQuery, requesterID string) (*dto.PaginatedPublicUsersResponse, error) {
+	ctx, cancel := util.NewDBContextFrom(ctx)
 	defer cancel()
 
-	filter := repo.Filter{"deleted_at": nil}
+	filter := repo.Filter{"deleted_at": nil, "deactivated_at": nil}
 	if query.Username != "" {
-		filter["username"] = bson.M{"$regex": query.Username, "$options": "i"}
+		filter["username"] = bson.M{"$regex": regexp.QuoteMeta(query.Username), "$options": "i"}
+	}
+
+	// A missing visibility means everyone; $nin also matches it
+	hidden := bson.A{model.ProfileVisibilityUsers, model.ProfileVisibilityNobody}
+	if requesterID != "" {
+		hidden = bson.A{model.ProfileVisibilityNobody}
+	}
+	visible := bson.M{"settings.profile_visibility": bson.M{"$nin": hidden}}
+	if ownID, err := primitive.ObjectIDFromHex(requesterID); err == nil {
+		filter["$or"] = bson.A{visible, bson.M{"_id": ownID}}
+	} else {
+		filter["settings.profile_visibility"] = visible["settings.profile_visibility"]
 	}
 
 	page := query.Page
@@ -212,7 +432,7 @@ func (s *userService) GetUsers(query *dto.GetUsersQuery) (*dto.PaginatedUsersRes
 	findOptions := &repo.FindOptions{
 		Skip:  int64((page - 1) * pageSize),
 		Limit: int64(pageSize),
-		Sort:  map[string]int{"created_at": -1},
+		Sort:  bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
 	}
 
 	users, total, err := s.userRepo.Find(ctx, filter, findOptions)
@@ -220,10 +440,8 @@ func (s *userService) GetUsers(query *dto.GetUsersQuery) (*dto.PaginatedUsersRes
 		return nil, err
 	}
 
-	userResponses := dto.FromUsers(users)
-
-	return &dto.PaginatedUsersResponse{
-		Users: userResponses,
+	return &dto.PaginatedPublicUsersResponse{
+		Users: dto.FromPublicUsers(users),
 		Pagination: dto.Pagination{
 			Page:     page,
 			PageSize: pageSize,
@@ -232,8 +450,8 @@ func (s *userService) GetUsers(query *dto.GetUsersQuery) (*dto.PaginatedUsersRes
 	}, nil
 }
 
-func (s *userService) GetSettings(userID string) (*dto.UserSettingsResponse, error) {
-	ctx, cancel := util.NewDefaultDBContext()
+func (s *userService) GetSettings(ctx context.Context, userID string) (*dto.UserSettingsResponse, error) {
+	ctx, cancel := util.NewDBContextFrom(ctx)
 	defer cancel()
 
 	user, err := s.userRepo.GetByID(ctx, userID)
@@ -245,15 +463,11 @@ func (s *userService) GetSettings(userID string) (*dto.UserSettingsResponse, err
 	}
 
 	// Return user settings
-	return &dto.UserSettingsResponse{
-		Language:          user.Settings.Language,
-		Theme:             user.Settings.Theme,
-		NotifyNewFeatures: user.Settings.NotifyNewFeatures,
-	}, nil
+	return dto.FromUserSettings(&user.Settings), nil
 }
 
-func (s *userService) UpdateSettings(userID string, req *dto.UpdateSettingsRequest) (*dto.UserSettingsResponse, error) {
-	ctx, cancel := util.NewDefaultDBContext()
+func (s *userService) UpdateSettings(ctx context.Context, userID string, req *dto.UpdateSettingsRequest) (*dto.UserSettingsResponse, error) {
+	ctx, cancel := util.NewDBContextFrom(ctx)
 	defer cancel()
 
 	user, err := s.userRepo.GetByID(ctx, userID)
@@ -271,9 +485,76 @@ func (s *userService) UpdateSettings(userID string, req *dto.UpdateSettingsReque
 	if req.Theme != nil {
 		user.Settings.Theme = *req.Theme
 	}
+	if req.FontScale != nil {
+		user.Settings.FontScale = *req.FontScale
+	}
+	if req.ReducedMotion != nil {
+		user.Settings.ReducedMotion = *req.ReducedMotion
+	}
+	if req.HighContrast != nil {
+		user.Settings.HighContrast = *req.HighContrast
+	}
 	if req.NotifyNewFeatures != nil {
 		user.Settings.NotifyNewFeatures = *req.NotifyNewFeatures
 	}
+	if req.DigestFrequency != nil {
+		user.Settings.DigestFrequency = *req.DigestFrequency
+	}
+	if req.ProductEmails != nil {
+		user.Settings.ProductEmails = req.ProductEmails
+	}
+	if req.ProfileVisibility != nil {
+		user.Settings.ProfileVisibility = *req.ProfileVisibility
+	}
+	if req.Timezone != nil {
+		if !model.IsValidTimezone(*req.Timezone) {
+			return nil, apperror.ErrInvalidTimezone
+		}
+		user.Settings.Timezone = *req.Timezone
+	}
+	if len(req.NotificationPreferences) > 0 {
+		if user.Settings.NotificationPreferences == nil {
+			user.Settings.NotificationPreferences = make(map[model.NotificationType]model.NotificationPreference)
+		}
+		for notifType, prefReq := range req.NotificationPreferences {
+			if !model.IsConfigurableNotificationType(notifType) {
+				return nil, apperror.ErrInvalidNotificationType
+			}
+			pref := user.Settings.NotificationPreference(notifType)
+			if prefReq.InApp != nil {
+				pref.InApp = *prefReq.InApp
+			}
+			if prefReq.Email != nil {
+				pref.Email = *prefReq.Email
+			}
+			user.Settings.NotificationPreferences[notifType] = pref
+		}
+	}
+
+	if req.DefaultModel != nil {
+		if *req.DefaultModel != "" && !slices.Contains(config.Cfg.AgentModels, *req.DefaultModel) {
+			return nil, apperror.ErrInvalidModel
+		}
+		user.Settings.DefaultModel = *req.DefaultModel
+	}
+	if req.ResponseLanguage != nil {
+		user.Settings.ResponseLanguage = *req.ResponseLanguage
+	}
+	if req.StreamingEnabled != nil {
+		user.Settings.StreamingEnabled = req.StreamingEnabled
+	}
+	if req.CitationsEnabled != nil {
+		user.Settings.CitationsEnabled = req.CitationsEnabled
+	}
+	if req.UsageLimitMessages != nil {
+		user.Settings.UsageLimitMessages = *req.UsageLimitMessages
+	}
+	if req.UsageLimitTokens != nil {
+		user.Settings.UsageLimitTokens = *req.UsageLimitTokens
+	}
+	if req.UsageLimitEnforced != nil {
+		user.Settings.UsageLimitEnforced = *req.UsageLimitEnforced
+	}
 
 	// Save updated user
 	user.UpdatedAt = time.Now()
@@ -281,22 +562,148 @@ func (s *userService) UpdateSettings(userID string, req *dto.UpdateSettingsReque
 	if err != nil {
 		return nil, err
 	}
+	s.eventBus.Publish(bus.SettingsChangedEvent{UserID: userID})
 
-	return &dto.UserSettingsResponse{
-		Language:          updatedUser.Settings.Language,
-		Theme:             updatedUser.Settings.Theme,
-		NotifyNewFeatures: updatedUser.Settings.NotifyNewFeatures,
-	}, nil
+	return dto.FromUserSettings(&updatedUser.Settings), nil
+}
+
+func (s *userService) GetBlockedTopics(ctx context.Context, userID string) (*dto.BlockedTopicsResponse, error) {
+	ctx, cancel := util.NewDBContextFrom(ctx)
+	defer cancel()
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, apperror.ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	return dto.FromBlockedTopics(&user.Settings), nil
+}
+
+// AddBlockedTopic adds a topic the assistant must not bring up. Topics are compared ignoring case.
+func (s *userService) AddBlockedTopic(ctx context.Context, userID string, topic string) (*dto.BlockedTopicsResponse, error) {
+	topic = strings.Join(strings.Fields(topic), " ")
+	if len([]rune(topic)) > model.MaxBlockedTopicLength {
+		return nil, apperror.ErrBadRequest
+	}
+
+	ctx, cancel := util.NewDBContextFrom(ctx)
+	defer cancel()
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, apperror.ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	if blockedTopicIndex(user.Settings.BlockedTopics, topic) >= 0 {
+		return nil, apperror.ErrBlockedTopicExists
+	}
+	if len(user.Settings.BlockedTopics) >= model.MaxBlockedTopics {
+		return nil, apperror.ErrTooManyBlockedTopics
+	}
+
+	user.Settings.BlockedTopics = append(user.Settings.BlockedTopics, topic)
+	user.UpdatedAt = time.Now()
+	updatedUser, err := s.userRepo.Update(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	s.eventBus.Publish(bus.SettingsChangedEvent{UserID: userID})
+
+	return dto.FromBlockedTopics(&updatedUser.Settings), nil
+}
+
+func (s *userService) RemoveBlockedTopic(ctx context.Context, userID string, topic string) (*dto.BlockedTopicsResponse, error) {
+	ctx, cancel := util.NewDBContextFrom(ctx)
+	defer cancel()
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, apperror.ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	i := blockedTopicIndex(user.Settings.BlockedTopics, strings.Join(strings.Fields(topic), " "))
+	if i < 0 {
+		return nil, apperror.ErrBlockedTopicNotFound
+	}
+
+	user.Settings.BlockedTopics = slices.Delete(user.Settings.BlockedTopics, i, i+1)
+	user.UpdatedAt = time.Now()
+	updatedUser, err := s.userRepo.Update(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	s.eventBus.Publish(bus.SettingsChangedEvent{UserID: userID})
+
+	return dto.FromBlockedTopics(&updatedUser.Settings), nil
+}
+
+// blockedTopicIndex returns the index of topic in topics ignoring case, or -1
+func blockedTopicIndex(topics []string, topic string) int {
+	return slices.IndexFunc(topics, func(t string) bool {
+		return strings.EqualFold(t, topic)
+	})
+}
+
+// GetProfileCompleteness scores the user's profile and tells them what is still missing.
+// UIT cookies count as connected only while every portal has a synced cookie.
+func (s *userService) GetProfileCompleteness(ctx context.Context, userID string) (*dto.ProfileCompletenessResponse, error) {
+	ctx, cancel := util.NewDBContextFrom(ctx)
+	defer cancel()
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, apperror.ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	redisCtx, redisCancel := util.NewDefaultRedisContext()
+	defer redisCancel()
+
+	missingSources, err := s.cookieService.MissingSources(redisCtx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	student := user.Student
+	items := []dto.ProfileCompletenessItem{
+		{Key: "avatar", Done: user.Avatar != nil && user.Avatar.URL != "", Weight: 15, Hint: "Thêm ảnh đại diện"},
+		{Key: "student_id", Done: student.StudentID != "", Weight: 20, Hint: "Nhập mã số sinh viên"},
+		{Key: "faculty", Done: student.Faculty != "", Weight: 15, Hint: "Chọn khoa của bạn"},
+		{Key: "program", Done: student.Program != "", Weight: 15, Hint: "Chọn chương trình đào tạo"},
+		{Key: "enrollment_year", Done: student.EnrollmentYear != 0, Weight: 10, Hint: "Nhập năm nhập học"},
+		{Key: "uit_cookies", Done: len(missingSources) == 0, Weight: 25,
+			Hint: "Đồng bộ phiên đăng nhập UIT bằng tiện ích mở rộng: " + strings.Join(missingSources, ", ")},
+	}
+
+	resp := &dto.ProfileCompletenessResponse{Items: items}
+	for i := range resp.Items {
+		if resp.Items[i].Done {
+			resp.Score += resp.Items[i].Weight
+			resp.Items[i].Hint = ""
+		}
+	}
+
+	return resp, nil
 }
 
-func (s *userService) CheckUsernameAvailability(username string) (bool, error) {
+func (s *userService) CheckUsernameAvailability(ctx context.Context, username string) (bool, error) {
 	// Try cache first
 	if s.redisClient != nil {
 		ctx, cancel := util.NewDefaultRedisContext()
 		defer cancel()
 
-		cacheKey := fmt.Sprintf("username_exists:%s", username)
-		cached, err := s.redisClient.Get(ctx, cacheKey).Result()
+		cached, err := s.redisClient.Get(ctx, usernameExistsKey(username)).Result()
 		if err == nil {
 			// Cache hit - "false" means available, "true" means taken
 			return cached == "false", nil
@@ -304,7 +711,7 @@ func (s *userService) CheckUsernameAvailability(username string) (bool, error) {
 	}
 
 	// Cache miss - query database
-	dbCtx, cancel := util.NewDefaultDBContext()
+	dbCtx, cancel := util.NewDBContextFrom(ctx)
 	defer cancel()
 
 	_, err := s.userRepo.GetByUsername(dbCtx, username)
@@ -315,13 +722,12 @@ func (s *userService) CheckUsernameAvailability(username string) (bool, error) {
 		ctx, cancel := util.NewDefaultRedisContext()
 		defer cancel()
 
-		cacheKey := fmt.Sprintf("username_exists:%s", username)
 		value := "false"
 		if exists {
 			value = "true"
 		}
 		// Ignore cache write errors, not critical
-		_ = s.redisClient.Set(ctx, cacheKey, value, 5*time.Minute).Err()
+		_ = s.redisClient.Set(ctx, usernameExistsKey(username), value, 5*time.Minute).Err()
 	}
 
 	return !exists, nil
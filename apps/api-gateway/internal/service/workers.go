@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Workers runs the background loops of the services so shutdown can stop them before the Mongo and Redis
+// clients are closed. The ctx passed to a job is cancelled on Stop; jobs that work through many items
+// check it between items and return, so the item in progress is finished rather than cut off.
+type Workers struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu      sync.Mutex
+	stopped bool
+}
+
+func NewWorkers() *Workers {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Workers{ctx: ctx, cancel: cancel}
+}
+
+// Go runs fn in a goroutine that Stop waits for. fn must return once ctx is done.
+// After Stop, fn is not run.
+func (w *Workers) Go(fn func(ctx context.Context)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.stopped {
+		return
+	}
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		fn(w.ctx)
+	}()
+}
+
+// RunEvery runs job right away, then every interval until Stop
+func (w *Workers) RunEvery(interval time.Duration, job func(ctx context.Context)) {
+	w.Go(func(ctx context.Context) {
+		job(ctx)
+		w.tick(ctx, interval, job)
+	})
+}
+
+// TickEvery runs job every interval until Stop, the first time after one interval
+func (w *Workers) TickEvery(interval time.Duration, job func(ctx context.Context)) {
+	w.Go(func(ctx context.Context) {
+		w.tick(ctx, interval, job)
+	})
+}
+
+func (w *Workers) tick(ctx context.Context, interval time.Duration, job func(ctx context.Context)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			job(ctx)
+		}
+	}
+}
+
+// Stop tells the workers to stop and waits until the jobs in progress have returned, or until ctx is done
+func (w *Workers) Stop(ctx context.Context) error {
+	w.mu.Lock()
+	w.stopped = true
+	w.cancel()
+	w.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -27,3 +27,9 @@ func NewDefaultRedisContext() (context.Context, context.CancelFunc) {
 func NewRedisContextWith(timeout time.Duration) (context.Context, context.CancelFunc) {
 	return context.WithTimeout(context.Background(), timeout)
 }
+
+// NewDBContextFrom derives a context with the default database timeout from parent, so the calls made on
+// it also end with parent, e.g. when the request's route timeout runs out
+func NewDBContextFrom(parent context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(parent, DefaultDBTimeout)
+}
@@ -0,0 +1,53 @@
+package util
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ParseVersion parses a semantic version such as "1.2.3" or "v1.2" into its numeric parts; a pre-release or
+// build suffix ("1.2.3-beta") is ignored
+func ParseVersion(version string) ([]int, bool) {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		version = version[:i]
+	}
+	if version == "" {
+		return nil, false
+	}
+
+	parts := strings.Split(version, ".")
+	if len(parts) > 4 { // Chrome allows up to four parts
+		return nil, false
+	}
+	numbers := make([]int, len(parts))
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, false
+		}
+		numbers[i] = n
+	}
+	return numbers, true
+}
+
+// CompareVersions returns -1, 0 or 1 as version a is older than, the same as or newer than b; missing parts
+// count as 0, so "1.2" equals "1.2.0"
+func CompareVersions(a, b []int) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+	}
+	return 0
+}
@@ -1,28 +1,112 @@
 package main
 
 import (
-	"log"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+	_ "time/tzdata" // Embed the timezone database, the production image has none
 
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/bootstrap"
 	"github.com/giakiet05/uit-ai-assistant/backend/internal/config"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/logger"
+	"github.com/giakiet05/uit-ai-assistant/backend/internal/platform/metrics"
 )
 
+// webSocketDrainTimeout bounds how long WebSocket clients get to receive their reconnect message
+const webSocketDrainTimeout = 10 * time.Second
+
+// closeTimeout bounds how long the Mongo, Redis and agent clients get to close once requests are drained
+const closeTimeout = 10 * time.Second
+
 func main() {
-	// Initialize Gin router
-	r, err := bootstrap.Init()
+	// Initialize application
+	app, err := bootstrap.Init()
 	if err != nil {
-		log.Fatalf("failed to initialize application: %v", err)
+		logger.Fatal("Failed to initialize application", "error", err)
 	}
 
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	// Start the server
 	port := config.Cfg.Port
-	log.Printf("Server is running at http://localhost:%s\n", port)
-	if err := r.Run(":" + port); err != nil {
-		log.Fatalf("failed to run server: %v", err)
+	srv := &http.Server{
+		Addr:    ":" + port,
+		Handler: app.Router,
+	}
+
+	go func() {
+		slog.Info("Server is running", "addr", "http://localhost:"+port)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Fatal("Failed to run server", "error", err)
+		}
+	}()
+
+	// Metrics are served on their own port, reachable by the scraper inside the deployment but not published
+	var metricsSrv *http.Server
+	if metricsPort := config.Cfg.MetricsPort; metricsPort != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", metrics.Handler())
+		metricsSrv = &http.Server{Addr: ":" + metricsPort, Handler: mux}
+
+		go func() {
+			slog.Info("Metrics server is running", "addr", "http://localhost:"+metricsPort+"/metrics")
+			if err := metricsSrv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logger.Fatal("Failed to run metrics server", "error", err)
+			}
+		}()
+	}
+
+	<-ctx.Done()
+	stop()
+	slog.Info("Shutting down server")
+
+	// Shutdown closes the listeners, then its hooks move WebSocket clients to other instances while in-flight
+	// requests, agent calls included, keep running until they finish or the drain timeout expires
+	wsStopped := make(chan struct{})
+	srv.RegisterOnShutdown(func() {
+		defer close(wsStopped)
+		wsCtx, cancel := context.WithTimeout(context.Background(), webSocketDrainTimeout)
+		defer cancel()
+		if err := app.StopWebSockets(wsCtx); err != nil {
+			slog.Error("WebSocket hub shutdown error", "error", err)
+		}
+	})
+
+	drainCtx, cancelDrain := context.WithTimeout(context.Background(), time.Duration(config.Cfg.ShutdownTimeout)*time.Second)
+	defer cancelDrain()
+
+	// Background jobs finish the item they are on while requests drain
+	workersStopped := make(chan struct{})
+	go func() {
+		defer close(workersStopped)
+		if err := app.StopWorkers(drainCtx); err != nil {
+			slog.Error("Background workers shutdown error, jobs still running were dropped", "error", err)
+		}
+	}()
+
+	if err := srv.Shutdown(drainCtx); err != nil {
+		slog.Error("HTTP server shutdown error, requests still in flight were dropped", "error", err)
+	}
+	// Scrapes keep working while requests drain
+	if metricsSrv != nil {
+		if err := metricsSrv.Close(); err != nil {
+			slog.Error("Metrics server shutdown error", "error", err)
+		}
 	}
+	<-wsStopped
+	<-workersStopped
 
-	for _, ri := range r.Routes() {
-		println(ri.Method, ri.Path)
+	closeCtx, cancelClose := context.WithTimeout(context.Background(), closeTimeout)
+	defer cancelClose()
+	if err := app.Close(closeCtx); err != nil {
+		slog.Error("Client shutdown error", "error", err)
 	}
 
+	slog.Info("Server stopped")
 }
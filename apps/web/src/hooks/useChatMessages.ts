@@ -19,7 +19,7 @@ export function useChatMessages(sessionId: string | null, limit?: number) {
       setError(null)
       const response = await apiClient.getChatMessages(sessionId, limit)
       if (response.success && response.data) {
-        setMessages(response.data)
+        setMessages(response.data.messages)
       }
     } catch (err) {
       const errorMessage = err instanceof Error ? err.message : "Failed to fetch messages"
@@ -14,7 +14,7 @@ export function useChatSessions(query?: GetSessionsQuery) {
       setError(null)
       const response = await apiClient.getChatSessions(query)
       if (response.success && response.data) {
-        setSessions(response.data)
+        setSessions(response.data.sessions)
       }
     } catch (err) {
       const errorMessage = err instanceof Error ? err.message : "Failed to fetch sessions"
@@ -27,7 +27,7 @@ export function useChatSessions(query?: GetSessionsQuery) {
 
   useEffect(() => {
     fetchSessions()
-  }, [query?.page, query?.page_size])
+  }, [query?.cursor, query?.page_size])
 
   return {
     sessions,
@@ -132,10 +132,22 @@ export interface UpdateSessionTitleRequest {
 }
 
 export interface GetSessionsQuery {
-  page?: number
+  cursor?: string // next_cursor of the previous page
   page_size?: number
 }
 
+export interface PaginatedSessionsResponse {
+  sessions: ChatSession[]
+  next_cursor?: string
+  has_more: boolean
+}
+
+export interface PaginatedMessagesResponse {
+  messages: ChatMessageResponse[]
+  next_cursor?: string
+  has_more: boolean
+}
+
 class ApiClient {
   private baseURL: string
   private isRefreshing = false
@@ -423,15 +435,15 @@ class ApiClient {
     })
   }
 
-  async getChatSessions(query?: GetSessionsQuery): Promise<ApiResponse<ChatSession[]>> {
+  async getChatSessions(query?: GetSessionsQuery): Promise<ApiResponse<PaginatedSessionsResponse>> {
     const params = new URLSearchParams()
-    if (query?.page) params.append("page", query.page.toString())
+    if (query?.cursor) params.append("cursor", query.cursor)
     if (query?.page_size) params.append("page_size", query.page_size.toString())
 
     const queryString = params.toString()
     const endpoint = queryString ? `/api/v1/chat/sessions?${queryString}` : "/api/v1/chat/sessions"
 
-    return this.request<ChatSession[]>(endpoint, {
+    return this.request<PaginatedSessionsResponse>(endpoint, {
       method: "GET",
     })
   }
@@ -442,16 +454,21 @@ class ApiClient {
     })
   }
 
-  async getChatMessages(sessionId: string, limit?: number): Promise<ApiResponse<ChatMessageResponse[]>> {
+  async getChatMessages(
+    sessionId: string,
+    limit?: number,
+    cursor?: string,
+  ): Promise<ApiResponse<PaginatedMessagesResponse>> {
     const params = new URLSearchParams()
     if (limit) params.append("limit", limit.toString())
+    if (cursor) params.append("cursor", cursor)
 
     const queryString = params.toString()
     const endpoint = queryString
       ? `/api/v1/chat/sessions/${sessionId}/messages?${queryString}`
       : `/api/v1/chat/sessions/${sessionId}/messages`
 
-    return this.request<ChatMessageResponse[]>(endpoint, {
+    return this.request<PaginatedMessagesResponse>(endpoint, {
       method: "GET",
     })
   }